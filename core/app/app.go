@@ -1,12 +1,40 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/dig"
+
 	"github.com/iotaledger/hive.go/core/app"
 	"github.com/iotaledger/hive.go/core/app/core/shutdown"
 	"github.com/iotaledger/hive.go/core/app/plugins/profiling"
 	"github.com/iotaledger/inx-app/core/inx"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
 	"github.com/iotaledger/inx-coordinator/core/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/envreport"
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+	"github.com/iotaledger/inx-coordinator/pkg/fileperm"
+	"github.com/iotaledger/inx-coordinator/pkg/handoff"
+	"github.com/iotaledger/inx-coordinator/pkg/nodecaps"
+	"github.com/iotaledger/inx-coordinator/pkg/shutdownreason"
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+	"github.com/iotaledger/inx-coordinator/pkg/supervisor"
+	"github.com/iotaledger/inx-coordinator/pkg/toolset"
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
+	"github.com/iotaledger/inx-coordinator/plugins/grpcapi"
 	"github.com/iotaledger/inx-coordinator/plugins/migrator"
+	"github.com/iotaledger/inx-coordinator/plugins/mirror"
+	"github.com/iotaledger/inx-coordinator/plugins/prometheus"
+	"github.com/iotaledger/inx-coordinator/plugins/restapi"
+	"github.com/iotaledger/inx-coordinator/plugins/telemetry"
+	"github.com/iotaledger/inx-coordinator/plugins/webhooks"
 )
 
 var (
@@ -18,6 +46,10 @@ var (
 )
 
 func App() *app.App {
+	if mirror.ShouldRun() {
+		return mirrorApp()
+	}
+
 	return app.New(Name, Version,
 		app.WithInitComponent(InitComponent),
 		app.WithCoreComponents([]*app.CoreComponent{
@@ -27,21 +59,57 @@ func App() *app.App {
 		}...),
 		app.WithPlugins([]*app.Plugin{
 			migrator.Plugin,
+			mirror.Plugin,
+			restapi.Plugin,
+			grpcapi.Plugin,
 			profiling.Plugin,
-			//nolint:gocritic // false positive
-			//prometheus.Plugin,
+			prometheus.Plugin,
+			telemetry.Plugin,
+			webhooks.Plugin,
 		}...),
 	)
 }
 
+// mirrorApp only runs the migrator and compares its receipts with the receipts on the network.
+// The coordinator is not started, so no milestones are issued and no signing keys are needed.
+func mirrorApp() *app.App {
+	return app.New(Name, Version,
+		app.WithInitComponent(InitComponent),
+		app.WithCoreComponents([]*app.CoreComponent{
+			inx.CoreComponent,
+			shutdown.CoreComponent,
+		}...),
+		app.WithPlugins([]*app.Plugin{
+			migrator.Plugin,
+			mirror.Plugin,
+			profiling.Plugin,
+		}...),
+	)
+}
+
+const (
+	// CfgAppPrintConfig defines whether the effective configuration should be printed.
+	CfgAppPrintConfig = "printConfig"
+)
+
 var (
 	InitComponent *app.InitComponent
+
+	printConfig = flag.Bool(CfgAppPrintConfig, false, "prints the effective configuration (defaults, config file, environment variables and flags merged) and exits")
+
+	application       *app.App
+	environmentReport *envreport.Report
+	// the policy the permissions of the state and key files are verified with (nil = disabled).
+	filePermissionsPolicy *fileperm.Policy
+	// the store that is shared by the components (nil = every component keeps its own files).
+	sharedStore storage.Store
 )
 
 func init() {
 	InitComponent = &app.InitComponent{
 		Component: &app.Component{
-			Name: "App",
+			Name:   "App",
+			Params: params,
 		},
 		NonHiddenFlags: []string{
 			"config",
@@ -51,6 +119,249 @@ func init() {
 			"migratorStartIndex",
 			"cooBootstrap",
 			"cooStartIndex",
+			CfgAppPrintConfig,
 		},
+		Init: initialize,
+	}
+	InitComponent.InitConfigPars = initConfigPars
+	InitComponent.Provide = provide
+	InitComponent.Run = run
+}
+
+func initialize(a *app.App) error {
+	application = a
+
+	if toolset.ShouldHandleTools() {
+		toolset.HandleTools()
+		// HandleTools will call os.Exit
+	}
+
+	if supervisor.ShouldRun() {
+		supervisor.Run()
+		// Run will call os.Exit
+	}
+
+	return nil
+}
+
+func initConfigPars(_ *dig.Container) error {
+	if !*printConfig {
+		if err := validation.Validate("nodeCapabilities", ParamsNodeCapabilities); err != nil {
+			return err
+		}
+
+		if err := validation.Validate("handoff", ParamsHandoff); err != nil {
+			return err
+		}
+
+		if err := validation.Validate("storage", ParamsStorage); err != nil {
+			return err
+		}
+
+		return initFilePermissions()
+	}
+
+	// the configuration is already merged at this point, the components are not initialized yet
+	output, err := json.MarshalIndent(formatDurations(application.Config().Koanf().Raw()), "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to print configuration: %w", err)
+	}
+
+	fmt.Println(string(output))
+	os.Exit(0)
+
+	return nil
+}
+
+// initFilePermissions sets the umask before any file is created and parses the policy the components verify their files with.
+func initFilePermissions() error {
+	if err := validation.Validate("filePermissions", ParamsFilePermissions); err != nil {
+		return err
+	}
+
+	if ParamsFilePermissions.Umask != "" {
+		umask, err := fileperm.ParseMode(ParamsFilePermissions.Umask)
+		if err != nil {
+			return fmt.Errorf("invalid umask: %w", err)
+		}
+		fileperm.SetUmask(umask)
+	}
+
+	if !ParamsFilePermissions.Enabled {
+		return nil
+	}
+
+	stateMode, err := fileperm.ParseMode(ParamsFilePermissions.StateMode)
+	if err != nil {
+		return fmt.Errorf("invalid state file mode: %w", err)
+	}
+
+	keyMode, err := fileperm.ParseMode(ParamsFilePermissions.KeyMode)
+	if err != nil {
+		return fmt.Errorf("invalid key file mode: %w", err)
 	}
+
+	filePermissionsPolicy = &fileperm.Policy{
+		StateMode:  stateMode,
+		KeyMode:    keyMode,
+		Fix:        ParamsFilePermissions.Fix,
+		CheckOwner: ParamsFilePermissions.CheckOwner,
+	}
+
+	return nil
+}
+
+func provide(c *dig.Container) error {
+	// the report is completed by the components while they are initialized
+	environmentReport = envreport.New(Name, Version, formatDurations(application.Config().Koanf().Raw()))
+
+	if err := c.Provide(func() *envreport.Report {
+		return environmentReport
+	}); err != nil {
+		return err
+	}
+
+	// the running coordinator must have exited before the state is loaded and the ports are bound by the components
+	predecessor, err := takeOver()
+	if err != nil {
+		return err
+	}
+
+	if err := c.Provide(func() *handoff.Server {
+		if !ParamsHandoff.Enabled || mirror.ShouldRun() {
+			return nil
+		}
+
+		return handoff.NewServer(InitComponent.Logger(), ParamsHandoff.SocketPath, ParamsHandoff.Timeout, predecessor)
+	}); err != nil {
+		return err
+	}
+
+	// the dropped events of all event listeners are counted in the same metrics
+	if err := c.Provide(func() *eventqueue.Metrics {
+		return eventqueue.NewMetrics()
+	}); err != nil {
+		return err
+	}
+
+	// the reason of the previous shutdown is reported once, so that the operator sees why the coordinator was restarted
+	shutdownReasons := shutdownreason.NewReporter(InitComponent.Logger(), ParamsShutdownReason.FilePath)
+	previousReason, err := shutdownReasons.TakePrevious()
+	if err != nil {
+		InitComponent.LogWarnf("failed to read the reason of the previous shutdown: %s", err)
+	} else if previousReason != nil {
+		InitComponent.LogWarnf("the previous run was shut down by the %s at %s: %s", previousReason.Component, time.Unix(previousReason.Timestamp, 0).Format(time.RFC3339), previousReason)
+	}
+
+	if err := c.Provide(func() *shutdownreason.Reporter {
+		return shutdownReasons
+	}); err != nil {
+		return err
+	}
+
+	if ParamsStorage.Backend != "" {
+		if sharedStore, err = storage.Open(ParamsStorage.Backend, ParamsStorage.Path); err != nil {
+			return fmt.Errorf("unable to open the %s storage: %w", ParamsStorage.Backend, err)
+		}
+		InitComponent.LogInfof("the included tail transaction hashes and the event journal are kept in the %s storage", ParamsStorage.Backend)
+	}
+
+	if err := c.Provide(func() storage.Store {
+		return sharedStore
+	}); err != nil {
+		return err
+	}
+
+	if err := c.Provide(func() *fileperm.Enforcer {
+		return fileperm.NewEnforcer(InitComponent.Logger(), filePermissionsPolicy)
+	}); err != nil {
+		return err
+	}
+
+	// the node bridge is provided by the INX component, the capabilities are only read once a component checks them
+	if err := c.Provide(func(nodeBridge *nodebridge.NodeBridge) *nodecaps.Capabilities {
+		if !ParamsNodeCapabilities.Enabled {
+			return nil
+		}
+
+		capabilities := nodecaps.New(
+			InitComponent.Logger(),
+			nodeBridge.Client(),
+			nodeBridge.NodeConfig,
+			nodeBridge.NodeStatus(),
+			nodeBridge.ProtocolParameters(),
+			ParamsNodeCapabilities.ProbeTimeout,
+		)
+		InitComponent.LogInfof("node of network %s runs protocol version %d (supported versions %v), milestones pruned up to %d",
+			capabilities.NetworkName, capabilities.ProtocolVersion, capabilities.SupportedProtocolVersions, capabilities.MilestonesPruningIndex)
+
+		return capabilities
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// takeOver asks the coordinator that runs on the same host to hand off, and waits until it exited.
+// It returns nil if the handoff is disabled or no coordinator is running.
+func takeOver() (*handoff.Token, error) {
+	// the mirror doesn't issue milestones, so it never takes over
+	if !ParamsHandoff.Enabled || mirror.ShouldRun() {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ParamsHandoff.Timeout)
+	defer cancel()
+
+	InitComponent.LogInfof("taking over from the running coordinator at %s ...", ParamsHandoff.SocketPath)
+	token, err := handoff.TakeOver(ctx, ParamsHandoff.SocketPath, ParamsHandoff.Timeout)
+	if err != nil {
+		if errors.Is(err, handoff.ErrNoPredecessor) {
+			InitComponent.LogInfo("no running coordinator found, starting normally")
+
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to take over from the running coordinator: %w", err)
+	}
+
+	InitComponent.LogInfof("took over from coordinator %d after milestone %d (handoff %s)", token.PID, token.LatestMilestoneIndex, token.ID)
+
+	return token, nil
+}
+
+func run() error {
+	// all components are initialized at this point, so the report is complete
+	InitComponent.Logger().Infow("Environment report", "report", environmentReport.Snapshot())
+
+	if sharedStore != nil {
+		// the store is closed after all components that use it were stopped
+		if err := application.Daemon().BackgroundWorker("Storage", func(ctx context.Context) {
+			<-ctx.Done()
+
+			if err := sharedStore.Close(); err != nil {
+				InitComponent.LogErrorf("failed to close the storage: %s", err)
+			}
+		}, daemon.PriorityCloseStorage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatDurations replaces the durations in the configuration with their string representation,
+// so that the printed configuration can be used as config file.
+func formatDurations(settings map[string]any) map[string]any {
+	for key, value := range settings {
+		switch v := value.(type) {
+		case time.Duration:
+			settings[key] = v.String()
+		case map[string]any:
+			settings[key] = formatDurations(v)
+		}
+	}
+
+	return settings
 }
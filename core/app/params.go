@@ -0,0 +1,62 @@
+package app
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/core/app"
+)
+
+// ParametersFilePermissions contains the parameters of the verification of the state and key file permissions.
+type ParametersFilePermissions struct {
+	Enabled    bool   `default:"true" usage:"whether the permissions and the ownership of the state and key files are verified at startup, the coordinator refuses to run if they are accessible by other users"`
+	Umask      string `default:"0007" usage:"the umask of the process that restricts the permissions of newly created files (octal, empty = unchanged)"`
+	StateMode  string `default:"0660" usage:"the maximum permissions of the state files (octal)" validate:"required"`
+	KeyMode    string `default:"0600" usage:"the maximum permissions of the private key files (octal)" validate:"required"`
+	Fix        bool   `default:"true" usage:"whether additional permissions are removed automatically if it is safe (regular files owned by the coordinator user, key files that were accessible by other users are never fixed)"`
+	CheckOwner bool   `default:"true" usage:"whether the state and key files must be owned by the user the coordinator runs as"`
+}
+
+var ParamsFilePermissions = &ParametersFilePermissions{}
+
+// ParametersNodeCapabilities contains the parameters of the check of the capabilities of the node.
+type ParametersNodeCapabilities struct {
+	Enabled      bool          `default:"true" usage:"whether the protocol versions, the pruning state and the INX APIs of the node are checked at startup, the coordinator refuses to start if the node lacks a capability it depends on"`
+	ProbeTimeout time.Duration `default:"2s" usage:"the timeout of the probes of the INX APIs of the node" validate:"min=100ms"`
+}
+
+var ParamsNodeCapabilities = &ParametersNodeCapabilities{}
+
+// ParametersHandoff contains the parameters of the handoff to a newly started coordinator process.
+type ParametersHandoff struct {
+	Enabled    bool          `default:"false" usage:"whether a newly started coordinator takes over from the coordinator running on the same host, which stops after its current milestone and exits, e.g. to upgrade the binary without downtime"`
+	SocketPath string        `default:"coordinator.sock" usage:"the path to the unix socket the running coordinator listens at for successors" validate:"required"`
+	Timeout    time.Duration `default:"2m" usage:"the maximum time to wait for the running coordinator to finish its current milestone and exit" validate:"min=1s"`
+}
+
+var ParamsHandoff = &ParametersHandoff{}
+
+// ParametersShutdownReason contains the parameters of the report of the reasons of the shutdowns the coordinator decided on.
+type ParametersShutdownReason struct {
+	FilePath string `default:"shutdown_reason.json" usage:"the path to the file the structured reason of a shutdown the coordinator decided on is written to, so that supervising tools know whether it is safe to restart (empty = the reason is only logged)"`
+}
+
+var ParamsShutdownReason = &ParametersShutdownReason{}
+
+// ParametersStorage contains the parameters of the store that is shared by the included tail transaction hashes and the event journal.
+type ParametersStorage struct {
+	Backend string `default:"" usage:"the backend of the store that is shared by the included tail transaction hashes and the event journal (empty = every component keeps its own files, file = a single append-only file, mapdb = an in-memory key-value store that is lost on shutdown, for tests only)" validate:"omitempty,oneof=file mapdb"`
+	Path    string `default:"storage.db" usage:"the path to the file of the file backend" validate:"required_if=Backend file"`
+}
+
+var ParamsStorage = &ParametersStorage{}
+
+var params = &app.ComponentParams{
+	Params: map[string]any{
+		"filePermissions":  ParamsFilePermissions,
+		"nodeCapabilities": ParamsNodeCapabilities,
+		"handoff":          ParamsHandoff,
+		"shutdownReason":   ParamsShutdownReason,
+		"storage":          ParamsStorage,
+	},
+	Masked: nil,
+}
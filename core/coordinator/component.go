@@ -16,15 +16,25 @@ import (
 	"github.com/iotaledger/hive.go/core/app/pkg/shutdown"
 	"github.com/iotaledger/hive.go/core/crypto"
 	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
 	"github.com/iotaledger/hive.go/core/syncutils"
 	"github.com/iotaledger/hive.go/core/timeutil"
 	"github.com/iotaledger/hornet/v2/pkg/common"
 	"github.com/iotaledger/inx-app/pkg/nodebridge"
 	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
 	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/envreport"
+	"github.com/iotaledger/inx-coordinator/pkg/fileperm"
+	"github.com/iotaledger/inx-coordinator/pkg/handoff"
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
 	"github.com/iotaledger/inx-coordinator/pkg/migrator"
 	"github.com/iotaledger/inx-coordinator/pkg/mselection"
+	"github.com/iotaledger/inx-coordinator/pkg/nodecaps"
+	"github.com/iotaledger/inx-coordinator/pkg/scheduler"
+	"github.com/iotaledger/inx-coordinator/pkg/shutdownreason"
 	"github.com/iotaledger/inx-coordinator/pkg/todo"
+	"github.com/iotaledger/inx-coordinator/pkg/trace"
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
 	"github.com/iotaledger/iota.go/v3/keymanager"
@@ -35,8 +45,8 @@ const (
 	CfgCoordinatorBootstrap = "cooBootstrap"
 	// CfgCoordinatorStartIndex defines the index of the first milestone at bootstrap.
 	CfgCoordinatorStartIndex = "cooStartIndex"
-	// MilestoneMaxAdditionalTipsLimit defines the maximum limit of additional tips that fit into a milestone (besides the last milestone and checkpoint hash).
-	MilestoneMaxAdditionalTipsLimit = 6
+	// CfgCoordinatorNetworkReset defines whether the network is bootstrapped on a network reset.
+	CfgCoordinatorNetworkReset = "cooNetworkReset"
 )
 
 var (
@@ -46,12 +56,13 @@ var (
 func init() {
 	CoreComponent = &app.CoreComponent{
 		Component: &app.Component{
-			Name:      "Coordinator",
-			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
-			Params:    params,
-			Provide:   provide,
-			Configure: configure,
-			Run:       run,
+			Name:           "Coordinator",
+			DepsFunc:       func(cDeps dependencies) { deps = cDeps },
+			Params:         params,
+			InitConfigPars: initConfigPars,
+			Provide:        provide,
+			Configure:      configure,
+			Run:            run,
 		},
 	}
 }
@@ -60,8 +71,9 @@ var (
 	CoreComponent *app.CoreComponent
 	deps          dependencies
 
-	bootstrap  = flag.Bool(CfgCoordinatorBootstrap, false, "bootstrap the network")
-	startIndex = flag.Uint32(CfgCoordinatorStartIndex, 0, "index of the first milestone at bootstrap")
+	bootstrap    = flag.Bool(CfgCoordinatorBootstrap, false, "bootstrap the network")
+	startIndex   = flag.Uint32(CfgCoordinatorStartIndex, 0, "index of the first milestone at bootstrap")
+	networkReset = flag.Bool(CfgCoordinatorNetworkReset, false, "bootstrap the network on a network reset, the milestone index continues from the start index")
 
 	nextCheckpointSignal chan struct{}
 	nextMilestoneSignal  chan struct{}
@@ -72,6 +84,9 @@ var (
 	lastCheckpointBlockID iotago.BlockID
 	lastMilestoneBlockID  iotago.BlockID
 
+	// the optional recorder of the interactions with the node.
+	traceRecorder *trace.Recorder
+
 	// closures.
 	onBlockSolid                *events.Closure
 	onLatestMilestoneChanged    *events.Closure
@@ -79,20 +94,55 @@ var (
 	onMilestoneTimeout          *events.Closure
 	onIssuedCheckpoint          *events.Closure
 	onIssuedMilestone           *events.Closure
+	onReceiptSigned             *events.Closure
+	onReceiptIssued             *events.Closure
+	onMigrationCompleted        *events.Closure
+	onSigningSelfTestCompleted  *events.Closure
+
+	onMilestoneConfirmationFailed *events.Closure
+	onMilestoneSkipped            *events.Closure
+	onMilestoneGapDetected        *events.Closure
+	onQuorumNodeDemoted           *events.Closure
+	onClockDriftDetected          *events.Closure
+	onReceiptsPaused              *events.Closure
+	onReceiptsResumed             *events.Closure
+	onPoWAttempt                  *events.Closure
+	onMilestoneTimings            *events.Closure
+	onSoftError                   *events.Closure
 )
 
 type dependencies struct {
 	dig.In
 	Coordinator      *coordinator.Coordinator
+	SoftErrorHistory *coordinator.SoftErrorHistory
+	RemoteSigners    *coordinator.RemoteSignerConnections
+	PinnedParents    *coordinator.PinnedParents
 	Selector         *mselection.HeaviestSelector
 	NodeBridge       *nodebridge.NodeBridge
 	ShutdownHandler  *shutdown.ShutdownHandler
 	TangleListener   *nodebridge.TangleListener
 	TreasuryListener *TreasuryListener `optional:"true"`
+	Handoff          *handoff.Server
+	Scheduler        *scheduler.Scheduler
+	ShutdownReasons  *shutdownreason.Reporter
+}
+
+func initConfigPars(_ *dig.Container) error {
+	// fail early if the merged configuration contains invalid values
+	return validation.Validate("coordinator", ParamsCoordinator)
 }
 
 func provide(c *dig.Container) error {
 
+	if err := c.Provide(func() *scheduler.Scheduler {
+		return scheduler.New(
+			scheduler.WithWorkers(ParamsCoordinator.Scheduler.Workers),
+			scheduler.WithStarvationTimeout(ParamsCoordinator.Scheduler.StarvationTimeout),
+		)
+	}); err != nil {
+		return err
+	}
+
 	if err := c.Provide(func() *mselection.HeaviestSelector {
 		// use the heaviest branch tip selection for the milestones
 		return mselection.New(
@@ -100,22 +150,55 @@ func provide(c *dig.Container) error {
 			ParamsCoordinator.TipSel.MaxHeaviestBranchTipsPerCheckpoint,
 			ParamsCoordinator.TipSel.RandomTipsPerCheckpoint,
 			ParamsCoordinator.TipSel.HeaviestBranchSelectionTimeout,
+			mselection.WithConeAgeWeight(ParamsCoordinator.TipSel.ConeScoring.AgeWeight, ParamsCoordinator.TipSel.ConeScoring.MaxConeAge),
+			mselection.WithLazyTipPenalty(ParamsCoordinator.TipSel.ConeScoring.LazyTipPenalty),
+			mselection.WithTargetConeWidth(ParamsCoordinator.TipSel.TargetConeWidth),
 		)
 	}); err != nil {
 		return err
 	}
 
+	if err := c.Provide(func() (*coordinator.SoftErrorHistory, error) {
+		return coordinator.NewSoftErrorHistory(
+			ParamsCoordinator.SoftErrorHistory.Size,
+			ParamsCoordinator.SoftErrorHistory.FilePath,
+		)
+	}); err != nil {
+		return err
+	}
+
+	type identityDeps struct {
+		dig.In
+		NodeBridge        *nodebridge.NodeBridge
+		EnvironmentReport *envreport.Report
+	}
+
+	if err := c.Provide(func(deps identityDeps) (*identity.Identity, error) {
+		return initIdentity(deps.NodeBridge, deps.EnvironmentReport)
+	}); err != nil {
+		return err
+	}
+
 	type coordinatorDeps struct {
 		dig.In
-		MigratorService *migrator.Service `optional:"true"`
-		NodeBridge      *nodebridge.NodeBridge
+		MigratorService   *migrator.Service `optional:"true"`
+		NodeBridge        *nodebridge.NodeBridge
+		EnvironmentReport *envreport.Report
+		FilePermissions   *fileperm.Enforcer
+		NodeCapabilities  *nodecaps.Capabilities
+		Handoff           *handoff.Server
+		ShutdownReasons   *shutdownreason.Reporter
 	}
 
 	type coordinatorDepsOut struct {
 		dig.Out
-		Coordinator      *coordinator.Coordinator
-		TangleListener   *nodebridge.TangleListener
-		TreasuryListener *TreasuryListener `optional:"true"`
+		Coordinator       *coordinator.Coordinator
+		SignerCommittee   *coordinator.SignerCommittee `optional:"true"`
+		RemoteSigners     *coordinator.RemoteSignerConnections
+		ReceiptProofStore *coordinator.ReceiptProofStore `optional:"true"`
+		PinnedParents     *coordinator.PinnedParents
+		TangleListener    *nodebridge.TangleListener
+		TreasuryListener  *TreasuryListener `optional:"true"`
 	}
 
 	if err := c.Provide(func(deps coordinatorDeps) coordinatorDepsOut {
@@ -125,16 +208,68 @@ func provide(c *dig.Container) error {
 			treasuryListener = NewTreasuryListener(CoreComponent.Logger(), deps.NodeBridge)
 		}
 
+		var signerCommittee *coordinator.SignerCommittee
+		var remoteSigners *coordinator.RemoteSignerConnections
+		var receiptProofStore *coordinator.ReceiptProofStore
+		pinnedParents := coordinator.NewPinnedParents(blockState, coordinator.WithMaxPinnedParents(maxMilestoneAdditionalTips()))
+
 		initCoordinator := func() (*coordinator.Coordinator, error) {
 
+			// refuse to run with state or key files that other users can access
+			if err := deps.FilePermissions.Enforce(fileperm.KindState,
+				ParamsCoordinator.StateFilePath,
+				ParamsCoordinator.Signing.Committee.FilePath,
+				ParamsCoordinator.SoftErrorHistory.FilePath,
+			); err != nil {
+				return nil, err
+			}
+
+			if ParamsCoordinator.Signing.TLS.Enabled {
+				if err := deps.FilePermissions.Enforce(fileperm.KindKey, ParamsCoordinator.Signing.TLS.PrivateKeyPath); err != nil {
+					return nil, err
+				}
+			}
+
+			// refuse to run with a node that can't serve the coordinator, instead of failing while issuing milestones
+			requiredAPIs := []string{nodecaps.APIComputeWhiteFlag}
+			if deps.MigratorService != nil {
+				requiredAPIs = append(requiredAPIs, nodecaps.APIListenToTreasuryUpdates)
+			}
+			if err := deps.NodeCapabilities.RequireAPIs(CoreComponent.Daemon().ContextStopped(), "the coordinator", requiredAPIs...); err != nil {
+				return nil, err
+			}
+
 			keyManager := keymanager.New()
 			for _, keyRange := range deps.NodeBridge.NodeConfig.GetMilestoneKeyRanges() {
 				keyManager.AddKeyRange(keyRange.GetPublicKey(), keyRange.GetStartIndex(), keyRange.GetEndIndex())
 			}
+			for _, keyRange := range keyManager.KeyRanges() {
+				deps.EnvironmentReport.AddKey(envreport.KeyPurposeMilestone, keyRange.PublicKey[:], keyRange.StartIndex, keyRange.EndIndex)
+			}
+
+			remoteSignerCredentials, err := initRemoteSignerCredentials()
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize remote signer TLS: %w", err)
+			}
+
+			var remoteSignerKeepalive *coordinator.RemoteSignerKeepalive
+			if ParamsCoordinator.Signing.Keepalive.Enabled {
+				remoteSignerKeepalive = &coordinator.RemoteSignerKeepalive{
+					Interval: ParamsCoordinator.Signing.Keepalive.Interval,
+					Timeout:  ParamsCoordinator.Signing.Keepalive.Timeout,
+				}
+			}
+			remoteSigners = coordinator.NewRemoteSignerConnections(remoteSignerCredentials, remoteSignerKeepalive)
+			if remoteSigners.KeepaliveEnabled() {
+				CoreComponent.LogInfof("keeping the connections to the remote signers warm, probing them every %v", remoteSignerKeepalive.Interval)
+			}
 
 			signingProvider, err := initSigningProvider(
 				ParamsCoordinator.Signing.Provider,
 				ParamsCoordinator.Signing.RemoteAddress,
+				remoteSigners,
+				ParamsCoordinator.Signing.Committee.FilePath,
+				ParamsCoordinator.Signing.Committee.Members,
 				keyManager,
 				int(deps.NodeBridge.NodeConfig.GetMilestonePublicKeyCount()),
 			)
@@ -142,31 +277,237 @@ func provide(c *dig.Container) error {
 				return nil, fmt.Errorf("failed to initialize signing provider: %w", err)
 			}
 
+			if committee, ok := signingProvider.(*coordinator.SignerCommittee); ok {
+				signerCommittee = committee
+				CoreComponent.LogInfof("running coordinator with a signer committee of %d members", len(committee.Members()))
+			}
+
+			var treasurySigner coordinator.TreasurySigner
+			if ParamsCoordinator.Signing.Treasury.Enabled {
+				if deps.MigratorService == nil {
+					return nil, errors.New("treasury signing enabled, but migration is disabled")
+				}
+
+				treasurySigner, err = initTreasurySigner(
+					ParamsCoordinator.Signing.Treasury.Provider,
+					ParamsCoordinator.Signing.Treasury.RemoteAddress,
+					remoteSigners,
+					ParamsCoordinator.Signing.Treasury.PublicKey,
+				)
+				if err != nil {
+					return nil, fmt.Errorf("failed to initialize treasury signer: %w", err)
+				}
+
+				treasuryPublicKey := treasurySigner.PublicKey()
+				CoreComponent.LogInfof("receipts are additionally signed by treasury key %s", iotago.EncodeHex(treasuryPublicKey[:]))
+				deps.EnvironmentReport.AddKey(envreport.KeyPurposeTreasury, treasuryPublicKey[:], 0, 0)
+			}
+
+			milestoneMetadata, err := coordinator.ParseMilestoneMetadata(ParamsCoordinator.MilestoneMetadata)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(milestoneMetadata) > 0 {
+				CoreComponent.LogInfof("embedding metadata into milestones (%d bytes)", len(milestoneMetadata))
+			}
+
+			var confirmationMilestones int
+			if ParamsCoordinator.ConfirmationCheck.Enabled {
+				if ParamsCoordinator.ConfirmationCheck.MaxMilestones <= 0 {
+					return nil, errors.New("the amount of milestones for the confirmation check must be greater than 0")
+				}
+				confirmationMilestones = ParamsCoordinator.ConfirmationCheck.MaxMilestones
+				CoreComponent.LogInfof("issued milestones need to be confirmed within %d milestone intervals", confirmationMilestones)
+			}
+
+			var quorumScoring *coordinator.QuorumScoring
 			if ParamsCoordinator.Quorum.Enabled {
 				CoreComponent.LogInfo("running coordinator with quorum enabled")
+
+				if ParamsCoordinator.Quorum.Scoring.Enabled {
+					quorumScoring, err = coordinator.NewQuorumScoring(ParamsCoordinator.Quorum.Groups, &coordinator.QuorumScoringOptions{
+						Window:                 ParamsCoordinator.Quorum.Scoring.Window,
+						MinActiveNodes:         ParamsCoordinator.Quorum.Scoring.MinActiveNodes,
+						MaxDisagreementRate:    ParamsCoordinator.Quorum.Scoring.MaxDisagreementRate,
+						MaxFailureRate:         ParamsCoordinator.Quorum.Scoring.MaxFailureRate,
+						MaxAverageResponseTime: ParamsCoordinator.Quorum.Scoring.MaxAverageResponseTime,
+					})
+					if err != nil {
+						return nil, err
+					}
+					CoreComponent.LogInfof("consistently disagreeing or slow quorum nodes are demoted, keeping at least %d active nodes per group", ParamsCoordinator.Quorum.Scoring.MinActiveNodes)
+				}
 			}
 
 			if deps.MigratorService == nil {
 				CoreComponent.LogInfo("running coordinator without migration enabled")
+			} else if ParamsCoordinator.ReceiptProofs.Enabled {
+				var receiptProofStoreOpts []options.Option[coordinator.ReceiptProofStore]
+				if treasurySigner != nil {
+					// the stored treasury signatures are verified when the proofs are loaded
+					protoParamsAtFunc, err := coordinator.NewProtocolParametersAtFunc(ParamsCoordinator.Protocol.ParametersUpdates, deps.NodeBridge.ProtocolParameters)
+					if err != nil {
+						return nil, err
+					}
+					receiptProofStoreOpts = append(receiptProofStoreOpts, coordinator.WithReceiptProofTreasuryKey(treasurySigner.PublicKey(), protoParamsAtFunc))
+				}
+
+				if receiptProofStore, err = coordinator.NewReceiptProofStore(ParamsCoordinator.ReceiptProofs.FolderPath, receiptProofStoreOpts...); err != nil {
+					return nil, err
+				}
+			}
+
+			treasuryValidation, err := coordinator.NewTreasuryValidation(ParamsCoordinator.TreasuryValidation.Mode, ParamsCoordinator.TreasuryValidation.MaxAmount)
+			if err != nil {
+				return nil, err
+			}
+
+			if deps.MigratorService != nil && treasuryValidation.Mode != coordinator.TreasuryValidationStrict {
+				CoreComponent.LogWarnf("treasury output is validated in %s mode, use for test networks only!", treasuryValidation.Mode)
+			}
+
+			powProvider, err := coordinator.NewPoWProvider(ParamsCoordinator.PoW.Provider, ParamsCoordinator.PoW.RemoteWorkers, ParamsCoordinator.PoW.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize PoW provider: %w", err)
+			}
+
+			if ParamsCoordinator.PoW.Provider == coordinator.PoWProviderRemote {
+				CoreComponent.LogInfof("offloading the PoW of checkpoints to %d remote PoW workers", len(ParamsCoordinator.PoW.RemoteWorkers))
+			}
+
+			// the latency budget and the milestone deadline are split across the stages of the issuance in the same way
+			stageShares := map[string]float64{
+				coordinator.StageTipSelection: ParamsCoordinator.LatencyBudget.TipSelection,
+				coordinator.StageWhiteFlag:    ParamsCoordinator.LatencyBudget.WhiteFlag,
+				coordinator.StageQuorum:       ParamsCoordinator.LatencyBudget.Quorum,
+				coordinator.StageReceipt:      ParamsCoordinator.LatencyBudget.Receipt,
+				coordinator.StageSigning:      ParamsCoordinator.LatencyBudget.Signing,
+			}
+
+			var latencyBudget *coordinator.LatencyBudget
+			if ParamsCoordinator.LatencyBudget.Enabled {
+				latencyBudget, err = coordinator.NewLatencyBudget(ParamsCoordinator.Interval, ParamsCoordinator.LatencyBudget.IntervalFraction, stageShares)
+				if err != nil {
+					return nil, err
+				}
+				CoreComponent.LogInfof("milestones are issued within a latency budget of %v", latencyBudget.Total)
+			}
+
+			var milestoneDeadline *coordinator.MilestoneDeadline
+			if ParamsCoordinator.MilestoneDeadline > 0 {
+				milestoneDeadline, err = coordinator.NewMilestoneDeadline(ParamsCoordinator.Interval, ParamsCoordinator.MilestoneDeadline, stageShares)
+				if err != nil {
+					return nil, err
+				}
+				CoreComponent.LogInfof("milestones are issued or aborted within a deadline of %v", milestoneDeadline.Total)
+			}
+
+			var nodeHealthMonitor *coordinator.NodeHealthMonitor
+			if deps.MigratorService != nil && ParamsCoordinator.NodeHealth.PauseReceipts {
+				nodeHealthMonitor = coordinator.NewNodeHealthMonitor(func() *coordinator.NodeHealth {
+					nodeStatus := deps.NodeBridge.NodeStatus()
+
+					return &coordinator.NodeHealth{
+						Healthy:      nodeStatus.GetIsHealthy(),
+						PruningIndex: nodeStatus.GetMilestonesPruningIndex(),
+					}
+				}, ParamsCoordinator.NodeHealth.MaxPruningStep)
+			}
+
+			protocolAdapters, err := coordinator.NewProtocolAdapters(ParamsCoordinator.Protocol.Activations)
+			if err != nil {
+				return nil, err
+			}
+
+			if activations := protocolAdapters.Activations(); len(activations) > 1 {
+				for _, activation := range activations {
+					CoreComponent.LogInfof("milestones are issued with protocol version %d from index %d on", activation.Version, activation.StartIndex)
+				}
+			}
+
+			milestoneOptionsFunc, err := coordinator.NewProtocolParametersUpdatesFunc(ParamsCoordinator.Protocol.ParametersUpdates)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, update := range ParamsCoordinator.Protocol.ParametersUpdates {
+				CoreComponent.LogInfof("milestone %d announces the protocol parameters of version %d that are active from index %d on", update.AnnouncementIndex, update.ProtocolVersion, update.TargetIndex)
+			}
+
+			merkleRootsFunc := ComputeMerkleTreeHash
+			nodeSyncedFunc := deps.NodeBridge.IsNodeSynced
+			confirmedMilestoneIndexFunc := deps.NodeBridge.ConfirmedMilestoneIndex
+			protoParamsFunc := deps.NodeBridge.ProtocolParameters
+			sendBlockFunc := sendBlock
+			clock := time.Now
+
+			if ParamsCoordinator.Trace.Enabled {
+				traceRecorder, err = trace.NewRecorder(CoreComponent.Logger(), ParamsCoordinator.Trace.FolderPath)
+				if err != nil {
+					return nil, err
+				}
+				CoreComponent.LogInfof("recording the interactions with the node to %s", traceRecorder.FilePath())
+
+				// all interactions of the coordinator logic with the outside are recorded, so that it can be replayed offline
+				merkleRootsFunc = traceRecorder.MerkleRootsFunc(merkleRootsFunc)
+				nodeSyncedFunc = traceRecorder.NodeSyncedFunc(nodeSyncedFunc)
+				confirmedMilestoneIndexFunc = traceRecorder.ConfirmedMilestoneIndexFunc(confirmedMilestoneIndexFunc)
+				protoParamsFunc = traceRecorder.ProtocolParametersFunc(protoParamsFunc)
+				sendBlockFunc = traceRecorder.SendBlockFunc(sendBlockFunc)
+				clock = traceRecorder.Clock(clock)
+				signingProvider = traceRecorder.SignerProvider(signingProvider)
+			}
+
+			var currentTraceFilePath string
+			if traceRecorder != nil {
+				currentTraceFilePath = traceRecorder.FilePath()
+			}
+
+			// the backups and archives that exceed the retention policies are removed before new ones are written
+			if err := cleanupFiles(currentTraceFilePath); err != nil {
+				return nil, err
 			}
 
 			coo, err := coordinator.New(
-				ComputeMerkleTreeHash,
-				deps.NodeBridge.IsNodeSynced,
-				deps.NodeBridge.ProtocolParameters,
+				merkleRootsFunc,
+				nodeSyncedFunc,
+				protoParamsFunc,
 				signingProvider,
 				deps.MigratorService,
 				treasuryListener.LatestTreasuryOutput,
-				sendBlock,
+				sendBlockFunc,
 				coordinator.WithLogger(CoreComponent.Logger()),
 				coordinator.WithStateFilePath(ParamsCoordinator.StateFilePath),
+				coordinator.WithStateBackups(ParamsCoordinator.StateBackups),
 				coordinator.WithMilestoneInterval(ParamsCoordinator.Interval),
 				coordinator.WithMilestoneTimeout(ParamsCoordinator.MilestoneTimeout),
 				coordinator.WithQuorum(ParamsCoordinator.Quorum.Enabled, ParamsCoordinator.Quorum.Groups, ParamsCoordinator.Quorum.Timeout),
+				coordinator.WithQuorumScoring(quorumScoring),
 				coordinator.WithSigningRetryAmount(ParamsCoordinator.Signing.RetryAmount),
 				coordinator.WithSigningRetryTimeout(ParamsCoordinator.Signing.RetryTimeout),
+				coordinator.WithTreasurySigner(treasurySigner),
+				coordinator.WithTreasuryValidation(treasuryValidation),
+				coordinator.WithProtocolAdapters(protocolAdapters),
+				coordinator.WithMilestoneOptions(milestoneOptionsFunc),
 				coordinator.WithBlockBackups(ParamsCoordinator.BlockBackups.Enabled, ParamsCoordinator.BlockBackups.FolderPath),
+				coordinator.WithMilestoneMetadata(milestoneMetadata),
+				coordinator.WithMilestoneParentsCount(ParamsCoordinator.TipSel.MilestoneParents),
+				coordinator.WithConfirmationCheck(confirmationMilestones, confirmedMilestoneIndexFunc),
+				coordinator.WithMilestoneResend(ParamsCoordinator.ConfirmationCheck.MaxSendAttempts, ParamsCoordinator.ConfirmationCheck.ResendBackoff),
+				coordinator.WithMaxBlockLag(ParamsCoordinator.MaxBlockLag),
+				coordinator.WithMaxClockDrift(ParamsCoordinator.MaxClockDrift),
+				coordinator.WithPoWProvider(powProvider),
+				coordinator.WithLatencyBudget(latencyBudget),
+				coordinator.WithMilestoneDeadline(milestoneDeadline),
+				coordinator.WithNodeHealthMonitor(nodeHealthMonitor, !ParamsCoordinator.NodeHealth.ContinueMilestones),
+				coordinator.WithRecovery(ParamsCoordinator.Recovery.CatchUpPolicy, ParamsCoordinator.Recovery.MaxCatchUpMilestones, ParamsCoordinator.Recovery.CatchUpInterval),
+				coordinator.WithReceiptProofStore(receiptProofStore),
+				coordinator.WithPinnedParents(pinnedParents),
+				coordinator.WithMigrationSummary(ParamsCoordinator.MigrationSummary.LastLegacyMilestoneIndex, ParamsCoordinator.MigrationSummary.FilePath),
 				coordinator.WithDebugFakeMilestoneTimestamps(ParamsCoordinator.DebugFakeMilestoneTimestamps),
+				coordinator.WithClock(clock),
+				coordinator.WithNetworkReset(*networkReset),
 			)
 			if err != nil {
 				return nil, err
@@ -189,10 +530,35 @@ func provide(c *dig.Container) error {
 					MilestoneID: ms.MilestoneID,
 				}
 			}
+			// the stored version has to be reported before the state file is upgraded
+			deps.EnvironmentReport.AddStateFile("coordinator", ParamsCoordinator.StateFilePath, coordinator.StateVersion)
+
+			if traceRecorder != nil {
+				traceStart, err := newTraceStart(latestMilestone, milestoneMetadata, int(deps.NodeBridge.NodeConfig.GetMilestonePublicKeyCount()), deps.MigratorService != nil)
+				if err != nil {
+					return nil, err
+				}
+				traceRecorder.RecordStart(traceStart)
+				coo.Events.Decision.Hook(events.NewClosure(traceRecorder.RecordDecision))
+			}
+
 			if err := coo.InitState(*bootstrap, *startIndex, latestMilestone); err != nil {
 				return nil, err
 			}
 
+			// never continue with a different state than the coordinator that handed off
+			if err := verifyHandoff(coo, deps.Handoff); err != nil {
+				return nil, err
+			}
+
+			if err := deps.NodeCapabilities.CheckPruning(coo.State().LatestMilestoneIndex); err != nil {
+				return nil, err
+			}
+
+			if err := deps.NodeCapabilities.CheckProtocolVersions(protocolAdapters.Activations(), coo.State().LatestMilestoneIndex+1); err != nil {
+				return nil, err
+			}
+
 			// don't issue milestones or checkpoints in case the node is running hot
 			coo.AddBackPressureFunc(todo.IsNodeTooLoaded)
 
@@ -201,13 +567,18 @@ func provide(c *dig.Container) error {
 
 		coo, err := initCoordinator()
 		if err != nil {
+			deps.ShutdownReasons.Report(shutdownreason.ComponentCoordinator, err)
 			CoreComponent.LogErrorAndExit(err)
 		}
 
 		return coordinatorDepsOut{
-			Coordinator:      coo,
-			TangleListener:   nodebridge.NewTangleListener(deps.NodeBridge),
-			TreasuryListener: treasuryListener,
+			Coordinator:       coo,
+			SignerCommittee:   signerCommittee,
+			RemoteSigners:     remoteSigners,
+			ReceiptProofStore: receiptProofStore,
+			PinnedParents:     pinnedParents,
+			TangleListener:    nodebridge.NewTangleListener(deps.NodeBridge),
+			TreasuryListener:  treasuryListener,
 		}
 	}); err != nil {
 		return err
@@ -238,6 +609,11 @@ func configure() error {
 	return nil
 }
 
+// maxMilestoneAdditionalTips returns the amount of tips that fit into a milestone (besides the last milestone and checkpoint).
+func maxMilestoneAdditionalTips() int {
+	return ParamsCoordinator.TipSel.MilestoneParents - 2
+}
+
 // handleError checks for critical errors and returns true if the node should shutdown.
 func handleError(err error) bool {
 	if err == nil {
@@ -245,7 +621,8 @@ func handleError(err error) bool {
 	}
 
 	if err := common.IsCriticalError(err); err != nil {
-		deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("coordinator plugin hit a critical error: %s", err), true)
+		reason := deps.ShutdownReasons.Report(shutdownreason.ComponentCoordinator, err)
+		deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("coordinator plugin hit a critical error: %s", reason), true)
 
 		return true
 	}
@@ -281,6 +658,42 @@ func run() error {
 		CoreComponent.LogPanicf("failed to start worker: %s", err)
 	}
 
+	if err := registerBackgroundTasks(); err != nil {
+		CoreComponent.LogPanicf("failed to register background tasks: %s", err)
+	}
+
+	// create a background worker that runs the background tasks, e.g. the signing self-test
+	if err := CoreComponent.Daemon().BackgroundWorker("Coordinator[Scheduler]", func(ctx context.Context) {
+		CoreComponent.LogInfo("Start Scheduler")
+		deps.Scheduler.Run(ctx)
+		CoreComponent.LogInfo("Stopped Scheduler")
+	}, daemon.PriorityStopScheduler); err != nil {
+		CoreComponent.LogPanicf("failed to start worker: %s", err)
+	}
+
+	if deps.RemoteSigners.KeepaliveEnabled() {
+		// create a background worker that keeps the connections to the remote signers warm
+		if err := CoreComponent.Daemon().BackgroundWorker("Coordinator[RemoteSigners]", func(ctx context.Context) {
+			CoreComponent.LogInfo("Start RemoteSigners")
+			probe := func() {
+				if err := deps.RemoteSigners.Probe(ctx); err != nil && ctx.Err() == nil {
+					CoreComponent.LogWarnf("failed to probe the remote signers: %s", err)
+				}
+			}
+			probe()
+
+			ticker := timeutil.NewTicker(probe, ParamsCoordinator.Signing.Keepalive.Interval, ctx)
+			ticker.WaitForGracefulShutdown()
+
+			if err := deps.RemoteSigners.Close(); err != nil {
+				CoreComponent.LogWarnf("failed to close the connections to the remote signers: %s", err)
+			}
+			CoreComponent.LogInfo("Stopped RemoteSigners")
+		}, daemon.PriorityCloseRemoteSigners); err != nil {
+			CoreComponent.LogPanicf("failed to start worker: %s", err)
+		}
+	}
+
 	if err := CoreComponent.Daemon().BackgroundWorker("Coordinator[TangleListener]", func(ctx context.Context) {
 		CoreComponent.LogInfo("Start TangleListener")
 		deps.TangleListener.Run(ctx)
@@ -299,6 +712,21 @@ func run() error {
 		}
 	}
 
+	if deps.Handoff != nil {
+		if err := deps.Handoff.Listen(); err != nil {
+			CoreComponent.LogErrorfAndExit("failed to start handoff: %s", err)
+		}
+
+		// create a background worker that hands off to a newly started coordinator process
+		if err := CoreComponent.Daemon().BackgroundWorker("Coordinator[Handoff]", func(ctx context.Context) {
+			CoreComponent.LogInfo("Start Handoff")
+			deps.Handoff.Run(ctx, &handoffYielder{coo: deps.Coordinator}, onHandedOff)
+			CoreComponent.LogInfo("Stopped Handoff")
+		}, daemon.PriorityStopHandoff); err != nil {
+			CoreComponent.LogPanicf("failed to start worker: %s", err)
+		}
+	}
+
 	// create a background worker that issues milestones
 	if err := CoreComponent.Daemon().BackgroundWorker("Coordinator", func(ctx context.Context) {
 		attachEvents()
@@ -320,6 +748,14 @@ func run() error {
 		lastCheckpointBlockID = milestoneBlockID
 		lastCheckpointIndex = 0
 
+		// catch up if milestone intervals were missed, e.g. because the coordinator was down
+		recoveryPlan, err := deps.Coordinator.PlanRecovery(time.Now())
+		if err != nil {
+			CoreComponent.LogWarnf("failed to plan the recovery of missed milestones: %s", err)
+		} else if recoveryPlan != nil {
+			go catchUp(ctx, recoveryPlan)
+		}
+
 	coordinatorLoop:
 		for {
 			select {
@@ -368,17 +804,42 @@ func run() error {
 					continue
 				}
 
+				// no background tasks are started until the milestone was issued
+				releaseScheduler := deps.Scheduler.Hold()
+
+				tipSelectionStart := time.Now()
+
+				// the tip selection exceeded its latency budget in the previous milestone,
+				// so only the latest milestone and checkpoint are referenced this time.
+				cachedTips := deps.Coordinator.UseCachedTips()
+
+				// blocks pinned via the API are referenced directly by the milestone,
+				// so less tips of the checkpoint fit into the milestone.
+				pinnedParents := deps.PinnedParents.BlockIDs()
+				maxAdditionalTips := maxMilestoneAdditionalTips() - len(pinnedParents)
+
 				// issue a new checkpoint right in front of the milestone
-				checkpointTips, err := deps.Selector.SelectTips(1)
+				var checkpointTips iotago.BlockIDs
+				err := mselection.ErrNoTipsAvailable
+				if !cachedTips {
+					checkpointTips, err = deps.Selector.SelectTips(1)
+				}
+				if timeout := deps.Coordinator.TipSelectionTimeout(); err == nil && timeout > 0 && time.Since(tipSelectionStart) > timeout {
+					// the tips are dropped, so that the milestone is still issued within its deadline
+					CoreComponent.LogWarnf("tip selection exceeded the timeout of the milestone deadline (%v), only the latest milestone and checkpoint are referenced", timeout)
+					cachedTips = true
+					checkpointTips = nil
+					err = mselection.ErrNoTipsAvailable
+				}
 				if err != nil {
 					// issuing checkpoint failed => not critical
 					if !errors.Is(err, mselection.ErrNoTipsAvailable) {
 						CoreComponent.LogWarn(err)
 					}
 				} else {
-					if len(checkpointTips) > MilestoneMaxAdditionalTipsLimit {
-						// issue a checkpoint with all the tips that wouldn't fit into the milestone (more than MilestoneMaxAdditionalTipsLimit)
-						checkpointBlockID, err := deps.Coordinator.IssueCheckpoint(lastCheckpointIndex, lastCheckpointBlockID, checkpointTips[MilestoneMaxAdditionalTipsLimit:])
+					if len(checkpointTips) > maxAdditionalTips {
+						// issue a checkpoint with all the tips that wouldn't fit into the milestone (more than maxAdditionalTips)
+						checkpointBlockID, err := deps.Coordinator.IssueCheckpoint(lastCheckpointIndex, lastCheckpointBlockID, checkpointTips[maxAdditionalTips:])
 						if err != nil {
 							// issuing checkpoint failed => not critical
 							CoreComponent.LogWarn(err)
@@ -388,17 +849,19 @@ func run() error {
 						}
 
 						// use the other tips for the milestone
-						milestoneTips = checkpointTips[:MilestoneMaxAdditionalTipsLimit]
+						milestoneTips = checkpointTips[:maxAdditionalTips]
 					} else {
 						// do not issue a checkpoint and use the tips for the milestone instead since they fit into the milestone directly
 						milestoneTips = checkpointTips
 					}
 				}
 
+				milestoneTips = append(milestoneTips, pinnedParents...)
 				milestoneTips = append(milestoneTips, iotago.BlockIDs{lastMilestoneBlockID, lastCheckpointBlockID}...)
+				deps.Coordinator.RecordTipSelection(time.Since(tipSelectionStart), cachedTips)
 
-				//nolint:contextcheck // false positive
-				milestoneBlockID, err := deps.Coordinator.IssueMilestone(milestoneTips)
+				milestoneBlockID, err := deps.Coordinator.IssueMilestone(ctx, milestoneTips)
+				releaseScheduler()
 				if handleError(err) {
 					// critical error => quit loop
 					break coordinatorLoop
@@ -431,6 +894,12 @@ func run() error {
 
 		deps.Coordinator.StopMilestoneTimeoutTicker()
 		detachEvents()
+
+		if traceRecorder != nil {
+			if err := traceRecorder.Close(); err != nil {
+				CoreComponent.LogWarnf("failed to close trace file: %s", err)
+			}
+		}
 	}, daemon.PriorityStopCoordinator); err != nil {
 		CoreComponent.LogPanicf("failed to start worker: %s", err)
 	}
@@ -464,7 +933,48 @@ func loadEd25519PrivateKeysFromEnvironment(name string) ([]ed25519.PrivateKey, e
 	return privateKeys, nil
 }
 
-func initSigningProvider(signingProviderType string, remoteEndpoint string, keyManager *keymanager.KeyManager, milestonePublicKeyCount int) (coordinator.MilestoneSignerProvider, error) {
+// initRemoteSignerCredentials loads the mutual TLS credentials for the remote signers, if TLS is enabled.
+func initRemoteSignerCredentials() (*coordinator.RemoteSignerCredentials, error) {
+	if !ParamsCoordinator.Signing.TLS.Enabled {
+		if ParamsCoordinator.Signing.TLS.Attestation.Enabled {
+			return nil, errors.New("remote signer attestation requires TLS to be enabled")
+		}
+
+		return nil, nil
+	}
+
+	var attestationVerifier *coordinator.AttestationVerifier
+	if ParamsCoordinator.Signing.TLS.Attestation.Enabled {
+		policy, err := coordinator.LoadAttestationPolicy(ParamsCoordinator.Signing.TLS.Attestation.PolicyFilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if attestationVerifier, err = coordinator.NewAttestationVerifier(policy); err != nil {
+			return nil, err
+		}
+	}
+
+	remoteSignerCredentials, err := coordinator.NewRemoteSignerCredentials(&coordinator.RemoteSignerTLSConfig{
+		CertificatePath:     ParamsCoordinator.Signing.TLS.CertificatePath,
+		PrivateKeyPath:      ParamsCoordinator.Signing.TLS.PrivateKeyPath,
+		CACertificatePath:   ParamsCoordinator.Signing.TLS.CACertificatePath,
+		ExpectedSANs:        ParamsCoordinator.Signing.TLS.ExpectedSANs,
+		AttestationVerifier: attestationVerifier,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	CoreComponent.LogInfo("using mutual TLS to connect to the remote signers")
+	if attestationVerifier != nil {
+		CoreComponent.LogInfo("verifying the attestations of the remote signers")
+	}
+
+	return remoteSignerCredentials, nil
+}
+
+func initSigningProvider(signingProviderType string, remoteEndpoint string, remoteSigners *coordinator.RemoteSignerConnections, committeeFilePath string, committeeMembers []*coordinator.SignerCommitteeMember, keyManager *keymanager.KeyManager, milestonePublicKeyCount int) (coordinator.MilestoneSignerProvider, error) {
 
 	switch signingProviderType {
 	case "local":
@@ -490,13 +1000,110 @@ func initSigningProvider(signingProviderType string, remoteEndpoint string, keyM
 			return nil, errors.New("no address given for remote signing provider")
 		}
 
-		return coordinator.NewInsecureRemoteEd25519MilestoneSignerProvider(remoteEndpoint, keyManager, milestonePublicKeyCount), nil
+		return coordinator.NewRemoteEd25519MilestoneSignerProvider(remoteEndpoint, remoteSigners, keyManager, milestonePublicKeyCount), nil
+
+	case "committee":
+		committee, err := coordinator.NewSignerCommittee(committeeFilePath, committeeMembers, remoteSigners, keyManager, milestonePublicKeyCount)
+		if err != nil {
+			return nil, err
+		}
+
+		return committee, nil
 
 	default:
 		return nil, fmt.Errorf("unknown milestone signing provider: %s", signingProviderType)
 	}
 }
 
+func initTreasurySigner(signingProviderType string, remoteEndpoint string, remoteSigners *coordinator.RemoteSignerConnections, publicKeyHex string) (coordinator.TreasurySigner, error) {
+
+	switch signingProviderType {
+	case "local":
+		privateKeys, err := loadEd25519PrivateKeysFromEnvironment("COO_TREASURY_PRV_KEY")
+		if err != nil {
+			return nil, err
+		}
+
+		if len(privateKeys) != 1 {
+			return nil, errors.New("exactly one treasury private key must be given")
+		}
+
+		if len(privateKeys[0]) != ed25519.PrivateKeySize {
+			return nil, errors.New("wrong treasury private key length")
+		}
+
+		return coordinator.NewInMemoryEd25519TreasurySigner(privateKeys[0]), nil
+
+	case "remote":
+		if remoteEndpoint == "" {
+			return nil, errors.New("no address given for remote treasury signing provider")
+		}
+
+		if publicKeyHex == "" {
+			return nil, errors.New("no public key given for remote treasury signing provider")
+		}
+
+		publicKey, err := crypto.ParseEd25519PublicKeyFromString(publicKeyHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid treasury public key: %w", err)
+		}
+
+		return coordinator.NewRemoteEd25519TreasurySigner(remoteEndpoint, remoteSigners, publicKey), nil
+
+	default:
+		return nil, fmt.Errorf("unknown treasury signing provider: %s", signingProviderType)
+	}
+}
+
+// newTraceStart returns the state and the options of the coordinator that are needed to replay the trace.
+// It must be created before the state is initialized, because the state file is read as it is stored.
+func newTraceStart(latestMilestone *coordinator.LatestMilestoneInfo, milestoneMetadata []byte, publicKeysCount int, migration bool) (*trace.Start, error) {
+	traceStart := &trace.Start{
+		Bootstrap:                    *bootstrap,
+		StartIndex:                   *startIndex,
+		NetworkReset:                 *networkReset,
+		LatestMilestoneIndex:         latestMilestone.Index,
+		LatestMilestoneTimestamp:     latestMilestone.Timestamp,
+		LatestMilestoneID:            iotago.EncodeHex(latestMilestone.MilestoneID[:]),
+		PublicKeysCount:              publicKeysCount,
+		ProtocolActivations:          ParamsCoordinator.Protocol.Activations,
+		ProtocolParametersUpdates:    ParamsCoordinator.Protocol.ParametersUpdates,
+		SigningRetryAmount:           ParamsCoordinator.Signing.RetryAmount,
+		MaxClockDrift:                int64(ParamsCoordinator.MaxClockDrift),
+		DebugFakeMilestoneTimestamps: ParamsCoordinator.DebugFakeMilestoneTimestamps,
+		Migration:                    migration,
+	}
+	if len(milestoneMetadata) > 0 {
+		traceStart.MilestoneMetadata = iotago.EncodeHex(milestoneMetadata)
+	}
+
+	if !*bootstrap {
+		// a missing state file is reported when the state is initialized
+		//nolint:gosec // the path is defined by the operator
+		state, err := os.ReadFile(ParamsCoordinator.StateFilePath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("unable to read coordinator state for the trace: %w", err)
+		}
+		traceStart.State = state
+	}
+
+	return traceStart, nil
+}
+
+// blockState returns the state of a block in the node, which is checked before the block is pinned as a parent.
+func blockState(ctx context.Context, blockID iotago.BlockID) (*coordinator.BlockState, error) {
+	metadata, err := deps.NodeBridge.BlockMetadata(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &coordinator.BlockState{
+		Solid:                      metadata.GetSolid(),
+		ReferencedByMilestoneIndex: metadata.GetReferencedByMilestoneIndex(),
+		BelowMaxDepth:              metadata.GetShouldReattach(),
+	}, nil
+}
+
 func sendBlock(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
 
 	var err error
@@ -532,8 +1139,28 @@ func sendBlock(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.Bl
 	}
 
 	if len(msIndex) > 0 {
+		// the milestone could already be confirmed if it is sent again, the registered event would never fire then
+		if deps.NodeBridge.ConfirmedMilestoneIndex() >= msIndex[0] {
+			deps.TangleListener.DeregisterMilestoneConfirmedEvent(msIndex[0])
+
+			return blockID, nil
+		}
+
 		// if it was a milestone, also wait until the milestone was confirmed
-		if err = events.WaitForChannelClosed(context.Background(), milestoneConfirmedEventChan); err != nil {
+		ctxConfirmed := context.Background()
+		if confirmationTimeout := deps.Coordinator.ConfirmationTimeout(); confirmationTimeout > 0 {
+			var cancelConfirmed context.CancelFunc
+			ctxConfirmed, cancelConfirmed = context.WithTimeout(ctxConfirmed, confirmationTimeout)
+			defer cancelConfirmed()
+		}
+
+		if err = events.WaitForChannelClosed(ctxConfirmed, milestoneConfirmedEventChan); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = fmt.Errorf("%w: milestone %d", coordinator.ErrMilestoneNotConfirmed, msIndex[0])
+
+				return blockID, err
+			}
+
 			return iotago.EmptyBlockID(), err
 		}
 	}
@@ -541,10 +1168,35 @@ func sendBlock(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.Bl
 	return blockID, nil
 }
 
+// catchUp signals the milestones of the recovery plan in the catch-up interval.
+// The milestones are issued in addition to the milestones of the regular ticker.
+func catchUp(ctx context.Context, plan *coordinator.RecoveryPlan) {
+	for i := 0; i < plan.CatchUpMilestones; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(plan.CatchUpInterval):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case nextMilestoneSignal <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	CoreComponent.LogInfof("catch-up finished, signaled %d milestone(s) for %d missed interval(s)", plan.CatchUpMilestones, plan.MissedIntervals)
+}
+
 func configureEvents() {
 	// pass all new solid blocks to the selector
 	onBlockSolid = events.NewClosure(func(metadata *inx.BlockMetadata) {
 
+		// used to detect whether the node is lagging behind
+		deps.Coordinator.OnBlockSolid()
+
 		if !deps.NodeBridge.IsNodeSynced() {
 			// ignore tips if the node is not synced,
 			// otherwise we may add blocks that seem to be fine,
@@ -578,7 +1230,12 @@ func configureEvents() {
 		deps.Selector.Continue()
 	})
 
-	onConfirmedMilestoneChanged = events.NewClosure(func(_ *nodebridge.Milestone) {
+	onConfirmedMilestoneChanged = events.NewClosure(func(ms *nodebridge.Milestone) {
+		// check that the node confirmed the milestone that was issued by the coordinator
+		if handleError(deps.Coordinator.OnConfirmedMilestone(ms.Milestone.Index, ms.MilestoneID)) {
+			return
+		}
+
 		heaviestSelectorLock.Lock()
 		defer heaviestSelectorLock.Unlock()
 
@@ -604,6 +1261,82 @@ func configureEvents() {
 	onIssuedMilestone = events.NewClosure(func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, blockID iotago.BlockID) {
 		CoreComponent.LogInfof("milestone issued (%d) MilestoneID: %s, BlockID: %v", index, iotago.EncodeHex(milestoneID[:]), blockID.ToHex())
 	})
+
+	onReceiptIssued = events.NewClosure(func(index iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt, size int) {
+		CoreComponent.LogInfof("receipt issued in milestone (%d), migrated at: %d, entries: %d, final: %t, size: %d bytes", index, receipt.MigratedAt, len(receipt.Funds), receipt.Final, size)
+	})
+
+	onMigrationCompleted = events.NewClosure(func(summary *coordinator.MigrationSummary) {
+		CoreComponent.LogInfof("migration completed in milestone (%d), legacy milestones: %d-%d, entries: %d, value: %d, receipts: %d, summary written to %s", summary.MilestoneIndex, summary.FirstMigratedAtIndex, summary.LastMigratedAtIndex, summary.MigratedEntriesCount, summary.MigratedValue, summary.ReceiptsCount, ParamsCoordinator.MigrationSummary.FilePath)
+	})
+
+	onMilestoneConfirmationFailed = events.NewClosure(func(failure *coordinator.MilestoneConfirmationFailure) {
+		if failure.ConfirmedMilestoneID != nil {
+			CoreComponent.LogErrorf("milestone (%d) MilestoneID: %s was replaced by MilestoneID: %s, receipt: %t", failure.Index, iotago.EncodeHex(failure.MilestoneID[:]), iotago.EncodeHex(failure.ConfirmedMilestoneID[:]), failure.HasReceipt)
+
+			return
+		}
+
+		CoreComponent.LogErrorf("milestone (%d) MilestoneID: %s was not confirmed within %v, receipt: %t", failure.Index, iotago.EncodeHex(failure.MilestoneID[:]), deps.Coordinator.ConfirmationTimeout(), failure.HasReceipt)
+	})
+
+	onReceiptSigned = events.NewClosure(func(index iotago.MilestoneIndex, signature *iotago.Ed25519Signature) {
+		CoreComponent.LogInfof("receipt for milestone (%d) signed by treasury key, %s", index, signature)
+	})
+
+	onSigningSelfTestCompleted = events.NewClosure(func(result *coordinator.SigningSelfTestResult) {
+		if result.Err != nil {
+			CoreComponent.LogErrorf("signing self-test for milestone (%d) failed, a real milestone could not be signed: %s", result.Index, result.Err)
+
+			return
+		}
+		CoreComponent.LogDebugf("signing self-test for milestone (%d) succeeded, took %v", result.Index, result.Duration.Truncate(time.Millisecond))
+	})
+
+	onMilestoneSkipped = events.NewClosure(func(index iotago.MilestoneIndex, err error) {
+		CoreComponent.LogWarnf("milestone (%d) skipped: %s", index, err)
+	})
+
+	onMilestoneGapDetected = events.NewClosure(func(plan *coordinator.RecoveryPlan) {
+		CoreComponent.LogWarnf("no milestones were issued since %s, catching up (%s)", plan.LatestMilestoneTime.Truncate(time.Second), plan)
+	})
+
+	onQuorumNodeDemoted = events.NewClosure(func(demotion *coordinator.QuorumNodeDemotion) {
+		CoreComponent.LogWarnf("quorum node demoted, group: %s, baseURL: %s, reason: %s", demotion.Group, demotion.BaseURL, demotion.Score.DemotionReason)
+	})
+
+	onClockDriftDetected = events.NewClosure(func(drift *coordinator.ClockDrift) {
+		CoreComponent.LogErrorf("system clock jumped backwards: %s", drift)
+	})
+
+	onReceiptsPaused = events.NewClosure(func(pause *coordinator.ReceiptPause) {
+		CoreComponent.LogWarnf("receipts paused with milestone (%d): %s", pause.Index, pause.Reason)
+	})
+
+	onReceiptsResumed = events.NewClosure(func(pause *coordinator.ReceiptPause) {
+		CoreComponent.LogInfof("receipts resumed with milestone (%d) after %v", pause.ResumedIndex, pause.Duration.Truncate(time.Second))
+	})
+
+	onPoWAttempt = events.NewClosure(func(attempt *coordinator.PoWAttempt) {
+		if attempt.Err != nil {
+			CoreComponent.LogWarnf("PoW attempt of remote worker %s failed after %v: %s", attempt.Worker, attempt.Duration.Truncate(time.Millisecond), attempt.Err)
+		}
+	})
+
+	onMilestoneTimings = events.NewClosure(func(timings *coordinator.MilestoneTimings) {
+		if exceeded := timings.ExceededStages(); len(exceeded) > 0 {
+			CoreComponent.LogWarnf("stages %s exceeded the latency budget: %s", strings.Join(exceeded, ", "), timings)
+
+			return
+		}
+		CoreComponent.LogDebug(timings)
+	})
+
+	onSoftError = events.NewClosure(func(err error) {
+		if err := deps.SoftErrorHistory.Add(err); err != nil {
+			CoreComponent.LogWarn(err)
+		}
+	})
 }
 
 func attachEvents() {
@@ -613,6 +1346,20 @@ func attachEvents() {
 	deps.Coordinator.Events.IssuedCheckpointBlock.Hook(onIssuedCheckpoint)
 	deps.Coordinator.Events.IssuedMilestone.Hook(onIssuedMilestone)
 	deps.Coordinator.Events.MilestoneTimeout.Hook(onMilestoneTimeout)
+	deps.Coordinator.Events.ReceiptSigned.Hook(onReceiptSigned)
+	deps.Coordinator.Events.ReceiptIssued.Hook(onReceiptIssued)
+	deps.Coordinator.Events.MigrationCompleted.Hook(onMigrationCompleted)
+	deps.Coordinator.Events.SigningSelfTestCompleted.Hook(onSigningSelfTestCompleted)
+	deps.Coordinator.Events.MilestoneConfirmationFailed.Hook(onMilestoneConfirmationFailed)
+	deps.Coordinator.Events.MilestoneSkipped.Hook(onMilestoneSkipped)
+	deps.Coordinator.Events.MilestoneGapDetected.Hook(onMilestoneGapDetected)
+	deps.Coordinator.Events.QuorumNodeDemoted.Hook(onQuorumNodeDemoted)
+	deps.Coordinator.Events.ClockDriftDetected.Hook(onClockDriftDetected)
+	deps.Coordinator.Events.ReceiptsPaused.Hook(onReceiptsPaused)
+	deps.Coordinator.Events.ReceiptsResumed.Hook(onReceiptsResumed)
+	deps.Coordinator.Events.PoWAttempt.Hook(onPoWAttempt)
+	deps.Coordinator.Events.MilestoneTimings.Hook(onMilestoneTimings)
+	deps.Coordinator.Events.SoftError.Hook(onSoftError)
 }
 
 func detachEvents() {
@@ -622,4 +1369,18 @@ func detachEvents() {
 	deps.Coordinator.Events.IssuedCheckpointBlock.Detach(onIssuedCheckpoint)
 	deps.Coordinator.Events.IssuedMilestone.Detach(onIssuedMilestone)
 	deps.Coordinator.Events.MilestoneTimeout.Detach(onMilestoneTimeout)
+	deps.Coordinator.Events.ReceiptSigned.Detach(onReceiptSigned)
+	deps.Coordinator.Events.ReceiptIssued.Detach(onReceiptIssued)
+	deps.Coordinator.Events.MigrationCompleted.Detach(onMigrationCompleted)
+	deps.Coordinator.Events.SigningSelfTestCompleted.Detach(onSigningSelfTestCompleted)
+	deps.Coordinator.Events.MilestoneConfirmationFailed.Detach(onMilestoneConfirmationFailed)
+	deps.Coordinator.Events.MilestoneSkipped.Detach(onMilestoneSkipped)
+	deps.Coordinator.Events.MilestoneGapDetected.Detach(onMilestoneGapDetected)
+	deps.Coordinator.Events.QuorumNodeDemoted.Detach(onQuorumNodeDemoted)
+	deps.Coordinator.Events.ClockDriftDetected.Detach(onClockDriftDetected)
+	deps.Coordinator.Events.ReceiptsPaused.Detach(onReceiptsPaused)
+	deps.Coordinator.Events.ReceiptsResumed.Detach(onReceiptsResumed)
+	deps.Coordinator.Events.PoWAttempt.Detach(onPoWAttempt)
+	deps.Coordinator.Events.MilestoneTimings.Detach(onMilestoneTimings)
+	deps.Coordinator.Events.SoftError.Detach(onSoftError)
 }
@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/handoff"
+)
+
+// handoffYielder suspends the coordinator when it hands off to a successor.
+type handoffYielder struct {
+	coo *coordinator.Coordinator
+}
+
+func (y *handoffYielder) Yield() (*handoff.Token, error) {
+	// waits until the current milestone was issued and the state file was written
+	state, err := y.coo.Suspend()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := handoff.NewToken(state.LatestMilestoneIndex, state.LatestMilestoneID)
+	if err != nil {
+		y.coo.Resume()
+
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (y *handoffYielder) Resume() {
+	CoreComponent.LogInfo("successor didn't take over, resuming the issuance of milestones")
+	y.coo.Resume()
+}
+
+// verifyHandoff checks that the loaded state continues where the coordinator that handed off stopped.
+func verifyHandoff(coo *coordinator.Coordinator, server *handoff.Server) error {
+	predecessor := server.Predecessor()
+	if predecessor == nil {
+		return nil
+	}
+
+	state := coo.State()
+	if err := predecessor.Verify(state.LatestMilestoneIndex, state.LatestMilestoneID); err != nil {
+		return fmt.Errorf("refusing to take over: %w", err)
+	}
+
+	return nil
+}
+
+// onHandedOff shuts down the coordinator after it handed off to a successor.
+// The successor continues once this process exited.
+func onHandedOff(token *handoff.Token) {
+	deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("coordinator handed off to a successor after milestone %d (handoff %s)", token.LatestMilestoneIndex, token.ID), false)
+}
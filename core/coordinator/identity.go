@@ -0,0 +1,48 @@
+package coordinator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-coordinator/pkg/envreport"
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// initIdentity loads the identity key of the coordinator instance, it returns nil if the identity is disabled.
+// The identity key must not be a milestone key, so that nothing it signs can be mistaken for a part of the protocol.
+func initIdentity(nodeBridge *nodebridge.NodeBridge, report *envreport.Report) (*identity.Identity, error) {
+	if !ParamsCoordinator.Identity.Enabled {
+		return nil, nil
+	}
+
+	privateKeys, err := loadEd25519PrivateKeysFromEnvironment("COO_IDENTITY_PRV_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(privateKeys) != 1 {
+		return nil, errors.New("exactly one identity private key must be given")
+	}
+
+	if len(privateKeys[0]) != ed25519.PrivateKeySize {
+		return nil, errors.New("wrong identity private key length")
+	}
+
+	id := identity.New(privateKeys[0])
+	publicKey := id.PublicKey()
+
+	for _, keyRange := range nodeBridge.NodeConfig.GetMilestoneKeyRanges() {
+		if bytes.Equal(keyRange.GetPublicKey(), publicKey[:]) {
+			return nil, errors.New("the identity key must be distinct from the milestone keys")
+		}
+	}
+
+	CoreComponent.LogInfof("operational artifacts are signed by identity key %s", iotago.EncodeHex(publicKey[:]))
+	report.AddKey(envreport.KeyPurposeIdentity, publicKey[:], 0, 0)
+
+	return id, nil
+}
@@ -7,41 +7,256 @@ import (
 	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
 )
 
+// ParametersSigningAttestation contains the parameters used to verify the attestations of the remote signers.
+type ParametersSigningAttestation struct {
+	Enabled        bool   `default:"false" usage:"whether the remote signer certificates must contain an attestation of the signer environment (e.g. TEE) that satisfies the attestation policy"`
+	PolicyFilePath string `default:"signer_attestation_policy.json" usage:"the path to the attestation policy file" validate:"required"`
+}
+
+// ParametersSigningTLS contains the mutual TLS parameters used to connect to the remote signers.
+type ParametersSigningTLS struct {
+	Enabled           bool     `default:"false" usage:"whether mutual TLS is used to connect to the remote signers"`
+	CertificatePath   string   `default:"" usage:"the path to the client certificate presented to the remote signers"`
+	PrivateKeyPath    string   `default:"" usage:"the path to the private key of the client certificate"`
+	CACertificatePath string   `default:"" usage:"the path to the CA certificates used to verify the remote signer certificates"`
+	ExpectedSANs      []string `default:"" usage:"the subject alternative names of which at least one must be contained in the remote signer certificates (if empty, the certificates must be valid for the host name of the remote address)"`
+
+	Attestation ParametersSigningAttestation
+}
+
+// ParametersSigningCommittee contains the parameters of the signer committee.
+type ParametersSigningCommittee struct {
+	FilePath string                               `default:"signer_committee.json" usage:"the path to the file the signer committee is persisted to" validate:"required"`
+	Members  []*coordinator.SignerCommitteeMember `noflag:"true" usage:"the initial members of the signer committee, used if no signer committee file exists"`
+}
+
+// ParametersSigningTreasury contains the parameters of the separate treasury key.
+type ParametersSigningTreasury struct {
+	Enabled       bool   `default:"false" usage:"whether receipts must additionally be signed by a separate treasury key"`
+	Provider      string `default:"local" usage:"the signing provider the coordinator uses to sign receipts with the treasury key (local/remote)" validate:"oneof=local remote"`
+	RemoteAddress string `default:"localhost:12346" usage:"the address of the remote treasury signing provider (insecure connection, unless TLS is enabled!)"`
+	PublicKey     string `default:"" usage:"the public key of the treasury key (required for the remote signing provider)"`
+}
+
+// ParametersSigningSelfTest contains the parameters of the signing self-test.
+type ParametersSigningSelfTest struct {
+	Interval time.Duration `default:"0s" usage:"the interval in which a test essence, that can't be submitted, is signed with all signers of the next milestone and the treasury signer to detect failing signers early (0 = disabled)" validate:"min=0s"`
+}
+
+// ParametersSigningKeepalive contains the parameters of the persistent connections to the remote signers.
+type ParametersSigningKeepalive struct {
+	Enabled  bool          `default:"false" usage:"whether persistent connections to all remote signers are established up front and kept warm, so that the first milestone after an idle period doesn't pay for the connection setup (the remote signers need to permit keepalive pings in the interval)"`
+	Interval time.Duration `default:"5m" usage:"the interval in which the connections are probed and keepalive pings are sent" validate:"min=10s"`
+	Timeout  time.Duration `default:"10s" usage:"the time after which a probe or a keepalive ping without response is considered failed" validate:"min=1ms"`
+}
+
+// ParametersIdentity contains the parameters of the identity key of the coordinator instance.
+type ParametersIdentity struct {
+	Enabled bool `default:"false" usage:"whether status reports and the archives of the event journal are signed with a separate identity key (COO_IDENTITY_PRV_KEY), so that downstream consumers can verify that they came from this coordinator instance"`
+}
+
+// ParametersSigning contains the parameters used to sign milestones.
+type ParametersSigning struct {
+	Provider      string        `default:"local" usage:"the signing provider the coordinator uses to sign a milestone (local/remote/committee)" validate:"oneof=local remote committee"`
+	RemoteAddress string        `default:"localhost:12345" usage:"the address of the remote signing provider (insecure connection, unless TLS is enabled!)"`
+	RetryTimeout  time.Duration `default:"2s" usage:"defines the timeout between signing retries" validate:"min=0s"`
+	RetryAmount   int           `default:"10" usage:"defines the number of signing retries to perform before shutting down the node" validate:"min=0"`
+
+	TLS       ParametersSigningTLS `name:"tls"`
+	Committee ParametersSigningCommittee
+	Treasury  ParametersSigningTreasury
+	SelfTest  ParametersSigningSelfTest
+	Keepalive ParametersSigningKeepalive
+}
+
 type Quorum struct {
 	Enabled bool                                         `default:"false" usage:"whether the coordinator quorum is enabled"`
 	Groups  map[string][]*coordinator.QuorumClientConfig `noflag:"true" usage:"defines the quorum groups used to ask other nodes for correct ledger state of the coordinator."`
-	Timeout time.Duration                                `default:"2s" usage:"the timeout until a node in the quorum must have answered"`
+	Timeout time.Duration                                `default:"2s" usage:"the timeout until a node in the quorum must have answered" validate:"min=1ms"`
+	Scoring ParametersQuorumScoring
+}
+
+// ParametersQuorumScoring contains the parameters of the scoring of the quorum nodes.
+type ParametersQuorumScoring struct {
+	// Enabled defines whether the quorum nodes are scored and consistently disagreeing or slow nodes are demoted.
+	Enabled bool `default:"false" usage:"whether the quorum nodes are scored and consistently disagreeing or slow nodes are demoted"`
+	// Window defines the amount of the latest quorum calls of a node its score is based on.
+	Window int `default:"20" usage:"the amount of the latest quorum calls of a node its score is based on" validate:"min=1"`
+	// MinActiveNodes defines the minimum amount of active nodes per quorum group.
+	MinActiveNodes int `default:"1" usage:"the minimum amount of active nodes per quorum group, nodes are not demoted below that amount" validate:"min=1"`
+	// MaxDisagreementRate defines the share of calls a node may answer with different merkle roots before it is demoted.
+	MaxDisagreementRate float64 `default:"0.2" usage:"the share of calls a node may answer with different merkle roots before it is demoted" validate:"min=0,max=1"`
+	// MaxFailureRate defines the share of calls a node may fail or not answer in time before it is demoted.
+	MaxFailureRate float64 `default:"0.5" usage:"the share of calls a node may fail or not answer in time before it is demoted" validate:"min=0,max=1"`
+	// MaxAverageResponseTime defines the average response time above which a node is demoted.
+	MaxAverageResponseTime time.Duration `default:"1s" usage:"the average response time above which a node is demoted (0 = disabled)"`
+}
+
+// ParametersCheckpoints contains the parameters of the checkpoints.
+type ParametersCheckpoints struct {
+	MaxTrackedBlocks int `default:"10000" usage:"maximum amount of known blocks for milestone tipselection. If this limit is exceeded, a new checkpoint is issued." validate:"min=1"`
 }
 
+// ParametersConeScoring contains the parameters used to score the past cones of tips.
+type ParametersConeScoring struct {
+	AgeWeight      float64       `default:"0.5" usage:"how much tips with older past cones are preferred (0 = disabled, 1 = the score of tips with a past cone of at least 'maxConeAge' is doubled)" validate:"min=0"`
+	MaxConeAge     time.Duration `default:"1m" usage:"the past cone age at which the maximum age bonus is reached" validate:"min=0s"`
+	LazyTipPenalty float64       `default:"0.5" usage:"the factor the score of tips is multiplied with, if the node marked them to be promoted or reattached (1 = disabled)" validate:"min=0,max=1"`
+}
+
+// ParametersTipSel contains the parameters of the milestone tipselection.
+type ParametersTipSel struct {
+	MinHeaviestBranchUnreferencedBlocksThreshold int           `default:"20" usage:"minimum threshold of unreferenced blocks in the heaviest branch" validate:"min=0"`
+	MaxHeaviestBranchTipsPerCheckpoint           int           `default:"10" usage:"maximum amount of checkpoint blocks with heaviest branch tips that are picked if the heaviest branch is not below 'MinHeaviestBranchUnreferencedBlocksThreshold' before" validate:"min=1"`
+	RandomTipsPerCheckpoint                      int           `default:"3" usage:"amount of checkpoint blocks with random tips that are picked if a checkpoint is issued and at least one heaviest branch tip was found, otherwise no random tips will be picked" validate:"min=0"`
+	HeaviestBranchSelectionTimeout               time.Duration `default:"100ms" usage:"the maximum duration to select the heaviest branch tips" validate:"min=1ms"`
+	MilestoneParents                             int           `default:"8" usage:"the amount of parents of a milestone, including the latest milestone and checkpoint, tips that don't fit into the milestone are referenced by a checkpoint (max 8)" validate:"min=2,max=8"`
+	TargetConeWidth                              int           `default:"0" usage:"the amount of heaviest branch tips the tipselection aims for, tips are picked until the target is reached even if their branches are below 'MinHeaviestBranchUnreferencedBlocksThreshold' (0 = disabled, max 'MaxHeaviestBranchTipsPerCheckpoint')" validate:"min=0"`
+
+	ConeScoring ParametersConeScoring
+}
+
+// ParametersBlockBackups contains the parameters of the block backups.
+type ParametersBlockBackups struct {
+	Enabled    bool          `default:"true" usage:"whether all blocks that are issued by the coordinator should be stored to disk before being submitted to the network"`
+	FolderPath string        `default:"block_backups" usage:"the path to the folder where block backups are stored" validate:"required"`
+	MaxFiles   int           `default:"0" usage:"the maximum amount of block backups that are kept, older ones are removed at startup (0 = unlimited)" validate:"min=0"`
+	MaxAge     time.Duration `default:"0s" usage:"the maximum age of the block backups, older ones are removed at startup (0 = unlimited)"`
+}
+
+// ParametersReceiptProofs contains the parameters of the inclusion proofs of confirmed receipts.
+type ParametersReceiptProofs struct {
+	Enabled    bool   `default:"true" usage:"whether inclusion proofs of confirmed receipts are stored to disk, so they can be queried via the API"`
+	FolderPath string `default:"receipt_proofs" usage:"the path to the folder where the receipt inclusion proofs are stored" validate:"required"`
+}
+
+// ParametersMigrationSummary contains the parameters of the summary of the completed migration.
+type ParametersMigrationSummary struct {
+	LastLegacyMilestoneIndex uint32 `default:"0" usage:"the last legacy milestone index of the migration, a signed summary is written once its final receipt was issued (0 = disabled)"`
+	FilePath                 string `default:"migration_summary.json" usage:"the path to the file the signed summary of the completed migration is written to" validate:"required"`
+}
+
+// ParametersTreasuryValidation contains the parameters of the validation of the treasury output that is spent by a receipt.
+type ParametersTreasuryValidation struct {
+	Mode      string `default:"strict" usage:"how inconsistencies of the treasury output are handled (strict = the coordinator stops, relaxed = inconsistencies that don't invalidate the receipt are logged, e.g. for private test networks with a synthetic treasury)" validate:"oneof=strict relaxed"`
+	MaxAmount uint64 `default:"0" usage:"the maximum amount of the treasury output (0 = the token supply of the protocol)"`
+}
+
+// ParametersProtocol contains the parameters of the protocol versions the milestones are issued with.
+type ParametersProtocol struct {
+	Activations       []*coordinator.ProtocolActivation       `noflag:"true" usage:"the protocol versions and the milestone indices they are activated at, the first one needs to start at index 0 (empty = stardust for all milestones)"`
+	ParametersUpdates []*coordinator.ProtocolParametersUpdate `noflag:"true" usage:"the protocol parameters that are announced by a milestone before they become active, a receipt that doesn't fit into the announcing milestone is deferred to the next milestone"`
+}
+
+// ParametersConfirmationCheck contains the parameters of the confirmation check of issued milestones.
+type ParametersConfirmationCheck struct {
+	Enabled       bool `default:"false" usage:"whether issued milestones need to be confirmed by the node within a certain amount of milestone intervals"`
+	MaxMilestones int  `default:"3" usage:"the amount of milestone intervals an issued milestone needs to be confirmed within, the milestone is sent again afterwards" validate:"min=1"`
+	// MaxSendAttempts defines how often an issued milestone is sent at most until it was confirmed.
+	MaxSendAttempts int `default:"10" usage:"how often an issued milestone is sent at most until it was confirmed, the coordinator stops afterwards" validate:"min=1"`
+	// ResendBackoff defines the initial delay before an unconfirmed milestone is sent again.
+	ResendBackoff time.Duration `default:"5s" usage:"the initial delay before an unconfirmed milestone is sent again, it doubles with every attempt up to the confirmation timeout" validate:"min=1ms"`
+}
+
+// ParametersSoftErrorHistory contains the parameters of the soft error history.
+type ParametersSoftErrorHistory struct {
+	Size     int    `default:"100" usage:"the amount of soft errors that are kept in the history" validate:"min=1"`
+	FilePath string `default:"" usage:"the path to the file the soft error history is persisted to (optional, in-memory only if empty)"`
+}
+
+// ParametersRecovery contains the parameters used to catch up after milestone intervals were missed.
+type ParametersRecovery struct {
+	CatchUpPolicy        string        `default:"immediate" usage:"the policy used to catch up after milestone intervals were missed, e.g. because the coordinator was down (immediate = a single milestone right away, gradual = several milestones in the catch-up interval)" validate:"oneof=immediate gradual"`
+	MaxCatchUpMilestones int           `default:"10" usage:"the maximum amount of milestones that are issued to catch up with the gradual policy" validate:"min=1"`
+	CatchUpInterval      time.Duration `default:"1s" usage:"the interval the catch-up milestones are issued in with the gradual policy" validate:"min=1s"`
+}
+
+// ParametersNodeHealth contains the parameters of the pause of the receipts while the node is unhealthy.
+type ParametersNodeHealth struct {
+	PauseReceipts      bool   `default:"true" usage:"whether receipts are paused while the node reports that it is unhealthy or busy pruning, they are resumed automatically once the node is healthy again"`
+	ContinueMilestones bool   `default:"true" usage:"whether milestones without receipts are issued while the receipts are paused, otherwise the milestones are skipped as well"`
+	MaxPruningStep     uint32 `default:"10" usage:"the maximum amount of milestones the pruning index of the node may advance per milestone before the node is considered to be busy pruning (0 = pruning is ignored)"`
+}
+
+// ParametersPoW contains the parameters of the PoW of the checkpoint blocks.
+// Milestone blocks never need PoW, the protocol requires their nonce to be zero.
+type ParametersPoW struct {
+	Provider      string        `default:"node" usage:"the provider that computes the nonces of the checkpoint blocks if the network requires PoW (node = the connected node via INX, remote = a pool of remote PoW workers)" validate:"oneof=node remote"`
+	RemoteWorkers []string      `default:"" usage:"the base URLs of the remote PoW workers, they are asked in a round-robin fashion"`
+	Timeout       time.Duration `default:"30s" usage:"the timeout of a single request to a remote PoW worker" validate:"min=1s"`
+}
+
+// ParametersLatencyBudget contains the parameters of the latency budget of the milestones.
+// The budget is split across the stages of the milestone issuance, the shares of the stages are fractions of the budget.
+// The shares also split the milestone deadline, even if the latency budget is disabled.
+type ParametersLatencyBudget struct {
+	Enabled          bool    `default:"false" usage:"whether the issuance of a milestone degrades gracefully if its stages exceed their latency budget (cached tips are used if the tip selection was slow, receipts are postponed if the milestone is late)"`
+	IntervalFraction float64 `default:"0.8" usage:"the fraction of the milestone interval that is available to issue a milestone" validate:"gt=0,max=1"`
+	TipSelection     float64 `default:"0.2" usage:"the share of the budget for the tip selection and the checkpoint in front of the milestone" validate:"min=0,max=1"`
+	WhiteFlag        float64 `default:"0.3" usage:"the share of the budget for the white-flag computation of the node" validate:"min=0,max=1"`
+	Quorum           float64 `default:"0.2" usage:"the share of the budget for the quorum" validate:"min=0,max=1"`
+	Receipt          float64 `default:"0.1" usage:"the share of the budget for the creation and the treasury signature of the receipt" validate:"min=0,max=1"`
+	Signing          float64 `default:"0.2" usage:"the share of the budget for the signing of the milestone" validate:"min=0,max=1"`
+}
+
+// ParametersTrace contains the parameters of the recorder of the interactions with the node.
+type ParametersTrace struct {
+	Enabled    bool          `default:"false" usage:"whether all interactions with the node and the decisions of the coordinator are recorded to a trace file, which can be replayed offline with the replay tool"`
+	FolderPath string        `default:"traces" usage:"the path to the folder where the trace files are stored, every run gets its own file" validate:"required"`
+	MaxFiles   int           `default:"0" usage:"the maximum amount of trace files of previous runs that are kept, older ones are removed at startup (0 = unlimited)" validate:"min=0"`
+	MaxAge     time.Duration `default:"0s" usage:"the maximum age of the trace files of previous runs, older ones are removed at startup (0 = unlimited)"`
+	Compress   bool          `default:"true" usage:"whether the trace files of previous runs are compressed with gzip at startup, the replay tool reads them transparently"`
+}
+
+// ParametersScheduler contains the parameters of the scheduler of the background tasks.
+type ParametersScheduler struct {
+	Workers           int           `default:"1" usage:"the amount of background tasks that are run at the same time, no background task is started while a milestone is issued" validate:"min=1"`
+	StarvationTimeout time.Duration `default:"1m" usage:"the duration after which a queued background task is run before tasks with a higher priority (0 = never)" validate:"min=0s"`
+	RetentionInterval time.Duration `default:"0s" usage:"the interval in which the retention policies of the backups and the trace files are applied while the coordinator is running (0 = only at startup)" validate:"min=0s"`
+}
+
+// ParametersCoordinator contains the definition of the parameters used by the coordinator.
+// All parameters can be overwritten by environment variables, e.g. COORDINATOR_SIGNING_PROVIDER for "coordinator.signing.provider".
+// The rules in the validate tags are checked after the configuration was loaded.
 type ParametersCoordinator struct {
-	StateFilePath    string        `default:"coordinator.state" usage:"the path to the state file of the coordinator"`
-	Interval         time.Duration `default:"5s" usage:"the interval milestones are issued"`
-	MilestoneTimeout time.Duration `default:"30s" usage:"the duration after which an event is triggered if no new milestones are received"`
-
-	Signing struct {
-		Provider      string        `default:"local" usage:"the signing provider the coordinator uses to sign a milestone (local/remote)"`
-		RemoteAddress string        `default:"localhost:12345" usage:"the address of the remote signing provider (insecure connection!)"`
-		RetryTimeout  time.Duration `default:"2s" usage:"defines the timeout between signing retries"`
-		RetryAmount   int           `default:"10" usage:"defines the number of signing retries to perform before shutting down the node"`
-	}
-
-	Quorum Quorum
-
-	Checkpoints struct {
-		MaxTrackedBlocks int `default:"10000" usage:"maximum amount of known blocks for milestone tipselection. If this limit is exceeded, a new checkpoint is issued."`
-	}
-
-	TipSel struct {
-		MinHeaviestBranchUnreferencedBlocksThreshold int           `default:"20" usage:"minimum threshold of unreferenced blocks in the heaviest branch"`
-		MaxHeaviestBranchTipsPerCheckpoint           int           `default:"10" usage:"maximum amount of checkpoint blocks with heaviest branch tips that are picked if the heaviest branch is not below 'MinHeaviestBranchUnreferencedBlocksThreshold' before"`
-		RandomTipsPerCheckpoint                      int           `default:"3" usage:"amount of checkpoint blocks with random tips that are picked if a checkpoint is issued and at least one heaviest branch tip was found, otherwise no random tips will be picked"`
-		HeaviestBranchSelectionTimeout               time.Duration `default:"100ms" usage:"the maximum duration to select the heaviest branch tips"`
-	} `name:"tipsel"`
-
-	BlockBackups struct {
-		Enabled    bool   `default:"true" usage:"whether all blocks that are issued by the coordinator should be stored to disk before being submitted to the network"`
-		FolderPath string `default:"block_backups" usage:"the path to the folder where block backups are stored"`
-	}
+	StateFilePath    string        `default:"coordinator.state" usage:"the path to the state file of the coordinator" validate:"required"`
+	StateBackups     int           `default:"1" usage:"the amount of backups of the state file that are kept, the latest one is '<stateFilePath>_old', older ones are numbered ('<stateFilePath>_old.1', ...)" validate:"min=1"`
+	Interval         time.Duration `default:"5s" usage:"the interval milestones are issued" validate:"min=1ms"`
+	MilestoneTimeout time.Duration `default:"30s" usage:"the duration after which an event is triggered if no new milestones are received" validate:"min=1ms"`
+
+	Signing            ParametersSigning
+	Identity           ParametersIdentity
+	Quorum             Quorum
+	Checkpoints        ParametersCheckpoints
+	TipSel             ParametersTipSel `name:"tipsel"`
+	BlockBackups       ParametersBlockBackups
+	ReceiptProofs      ParametersReceiptProofs
+	MigrationSummary   ParametersMigrationSummary
+	TreasuryValidation ParametersTreasuryValidation
+	Protocol           ParametersProtocol
+	ConfirmationCheck  ParametersConfirmationCheck
+
+	MaxBlockLag time.Duration `default:"0s" usage:"the maximum age of the latest solid block of the node, milestones are skipped if the node is not synced or lagging behind (0 = disabled)" validate:"min=0s"`
+
+	NodeHealth ParametersNodeHealth
+
+	Recovery ParametersRecovery
+
+	PoW ParametersPoW `name:"pow"`
+
+	MaxClockDrift time.Duration `default:"5s" usage:"the maximum duration the issuance of a milestone is delayed until its timestamp is newer than the latest milestone, milestones are skipped and an alert is raised if the system clock jumped further backwards (0 = never delay)" validate:"min=0s"`
+
+	LatencyBudget ParametersLatencyBudget
+
+	MilestoneDeadline time.Duration `default:"0s" usage:"the duration a milestone issuance must be finished or aborted within, the timeouts of the tip selection, the white-flag computation, the quorum, the receipt and the signing are derived from it with the stage shares of the latency budget, a signed milestone is not resent once it is exceeded (0 = disabled, max 'interval')" validate:"min=0s"`
+
+	SoftErrorHistory ParametersSoftErrorHistory
+
+	Trace ParametersTrace
+
+	Scheduler ParametersScheduler
+
+	MilestoneMetadata string `default:"" usage:"optional metadata that is embedded into every milestone, e.g. a network tag or the coordinator version (hex encoded if prefixed with '0x')"`
 
 	DebugFakeMilestoneTimestamps bool `default:"false" usage:"whether the coordinator will fake timestamps of milestones if the interval is below 1s (use for tests only!)"`
 }
@@ -52,6 +267,12 @@ var ParamsCoordinator = &ParametersCoordinator{
 	},
 }
 
+func init() {
+	ParamsCoordinator.Signing.Committee.Members = make([]*coordinator.SignerCommitteeMember, 0)
+	ParamsCoordinator.Protocol.Activations = make([]*coordinator.ProtocolActivation, 0)
+	ParamsCoordinator.Protocol.ParametersUpdates = make([]*coordinator.ProtocolParametersUpdate, 0)
+}
+
 var params = &app.ComponentParams{
 	Params: map[string]any{
 		"coordinator": ParamsCoordinator,
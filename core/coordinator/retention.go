@@ -0,0 +1,48 @@
+package coordinator
+
+import (
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+	"github.com/iotaledger/inx-coordinator/pkg/trace"
+)
+
+// blockBackupsFilePattern matches the names of the block backups in the block backups folder (see filepath.Match).
+const blockBackupsFilePattern = "*_*.bin"
+
+// cleanupFiles applies the retention policies to the backups of the state file, the block backups and the trace files of previous runs.
+// The trace file of the current run is never touched.
+func cleanupFiles(currentTraceFilePath string) error {
+	removed, err := retention.CleanupBackups(ParamsCoordinator.StateFilePath, ParamsCoordinator.StateBackups)
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		CoreComponent.LogInfof("removed %d backups of the state file that exceed the configured amount", removed)
+	}
+
+	if ParamsCoordinator.BlockBackups.MaxFiles > 0 || ParamsCoordinator.BlockBackups.MaxAge > 0 {
+		result, err := retention.Cleanup(ParamsCoordinator.BlockBackups.FolderPath, blockBackupsFilePattern, retention.Policy{
+			MaxFiles: ParamsCoordinator.BlockBackups.MaxFiles,
+			MaxAge:   ParamsCoordinator.BlockBackups.MaxAge,
+		})
+		if err != nil {
+			return err
+		}
+		if result.Removed > 0 {
+			CoreComponent.LogInfof("removed %d block backups", result.Removed)
+		}
+	}
+
+	result, err := retention.Cleanup(ParamsCoordinator.Trace.FolderPath, trace.FilePattern, retention.Policy{
+		MaxFiles: ParamsCoordinator.Trace.MaxFiles,
+		MaxAge:   ParamsCoordinator.Trace.MaxAge,
+		Compress: ParamsCoordinator.Trace.Compress,
+	}, currentTraceFilePath)
+	if err != nil {
+		return err
+	}
+	if result.Removed > 0 || result.Compressed > 0 {
+		CoreComponent.LogInfof("removed %d and compressed %d trace files of previous runs", result.Removed, result.Compressed)
+	}
+
+	return nil
+}
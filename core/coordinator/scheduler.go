@@ -0,0 +1,53 @@
+package coordinator
+
+import (
+	"context"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/scheduler"
+)
+
+const (
+	// taskSigningSelfTest tests the signers of the next milestone.
+	taskSigningSelfTest = "signingSelfTest"
+	// taskRetention applies the retention policies to the backups and the trace files.
+	taskRetention = "retention"
+)
+
+// registerBackgroundTasks registers the periodic background tasks of the coordinator at the scheduler.
+func registerBackgroundTasks() error {
+	if ParamsCoordinator.Signing.SelfTest.Interval > 0 {
+		if err := deps.Scheduler.Register(taskSigningSelfTest, scheduler.PriorityNormal, ParamsCoordinator.Signing.SelfTest.Interval, func(_ context.Context) error {
+			// failed self-tests are reported by the SigningSelfTestCompleted event
+			return deps.Coordinator.SelfTestSigning().Err
+		}); err != nil {
+			return err
+		}
+	}
+
+	if ParamsCoordinator.Scheduler.RetentionInterval > 0 {
+		var currentTraceFilePath string
+		if traceRecorder != nil {
+			currentTraceFilePath = traceRecorder.FilePath()
+		}
+
+		if err := deps.Scheduler.Register(taskRetention, scheduler.PriorityLow, ParamsCoordinator.Scheduler.RetentionInterval, func(_ context.Context) error {
+			if err := cleanupFiles(currentTraceFilePath); err != nil {
+				CoreComponent.LogWarnf("failed to apply the retention policies: %s", err)
+
+				return err
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	deps.Scheduler.Events.TaskFinished.Hook(events.NewClosure(func(run *scheduler.TaskRun) {
+		CoreComponent.LogDebugf("background task %s finished after %v (queued for %v)", run.Task, run.Runtime.Truncate(time.Millisecond), run.Wait.Truncate(time.Millisecond))
+	}))
+
+	return nil
+}
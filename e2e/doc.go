@@ -0,0 +1,5 @@
+// Package e2e contains the end-to-end tests of the coordinator.
+// The tests run against a private tangle with a Hornet node, the coordinator and a mock remote signer,
+// which is started with docker compose. They are excluded from the regular test run by the "e2e" build tag,
+// see README.md for how to run them.
+package e2e
@@ -0,0 +1,249 @@
+//go:build e2e
+
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/nodeclient"
+)
+
+const (
+	// the time the private tangle gets to start up and issue the first milestones.
+	startupTimeout = 3 * time.Minute
+	// the time the coordinator gets to include the requested migrations in a confirmed receipt.
+	receiptTimeout = 2 * time.Minute
+	// the interval the state of the private tangle is polled in.
+	pollInterval = time.Second
+)
+
+// env returns the value of the environment variable with the given name or the default value if it is not set.
+func env(name string, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+// privateTangle is the private tangle the tests run against.
+type privateTangle struct {
+	node           *nodeclient.Client
+	coordinatorAPI string
+	protoParams    *iotago.ProtocolParameters
+}
+
+// connectPrivateTangle waits until the node is healthy and the coordinator issues milestones.
+func connectPrivateTangle(t *testing.T) *privateTangle {
+	t.Helper()
+
+	tangle := &privateTangle{
+		node:           nodeclient.New(env("E2E_NODE_API", "http://localhost:14265")),
+		coordinatorAPI: env("E2E_COORDINATOR_API", "http://localhost:9091"),
+	}
+
+	eventually(t, startupTimeout, func(ctx context.Context) error {
+		info, err := tangle.node.Info(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !info.Status.IsHealthy || info.Status.ConfirmedMilestone.Index == 0 {
+			return fmt.Errorf("node is not healthy yet, confirmed milestone %d", info.Status.ConfirmedMilestone.Index)
+		}
+
+		protoParams := info.Protocol
+		tangle.protoParams = &protoParams
+
+		return nil
+	})
+
+	return tangle
+}
+
+// eventually calls the given function until it succeeds or fails the test after the given timeout.
+func eventually(t *testing.T, timeout time.Duration, f func(ctx context.Context) error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := f(ctx)
+		if err == nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			require.FailNow(t, "condition not met in time", "%s", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// coordinatorRequest sends a request to the REST API of the coordinator and decodes the JSON response.
+func (p *privateTangle) coordinatorRequest(ctx context.Context, method string, route string, reqObj interface{}, resObj interface{}) error {
+	var body bytes.Buffer
+	if reqObj != nil {
+		if err := json.NewEncoder(&body).Encode(reqObj); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.coordinatorAPI+route, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%s %s failed: %s", method, route, res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(resObj)
+}
+
+// treasuryAmount returns the amount of the current treasury output.
+func (p *privateTangle) treasuryAmount(ctx context.Context) (uint64, error) {
+	treasury, err := p.node.Treasury(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(treasury.Amount, 10, 64)
+}
+
+// targetAddress returns a test address that is derived from the name of the test, the given index and the current time,
+// so that the tests can run repeatedly against the same private tangle.
+func targetAddress(t *testing.T, index uint64) *iotago.Ed25519Address {
+	seed := make([]byte, ed25519.SeedSize)
+	copy(seed, t.Name())
+	binary.LittleEndian.PutUint64(seed[ed25519.SeedSize-8:], index+uint64(time.Now().UnixNano()))
+
+	addr := iotago.Ed25519AddressFromPubKey(ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey))
+
+	return &addr
+}
+
+func TestMigrationReceipt(t *testing.T) {
+	tangle := connectPrivateTangle(t)
+	ctx := context.Background()
+
+	treasuryBefore, err := tangle.treasuryAmount(ctx)
+	require.NoError(t, err)
+
+	// request migrations via the faucet of the coordinator, which stands in for the legacy network
+	const deposit = 1_000_000
+	migrations := make(map[string]*iotago.Ed25519Address)
+	for i := uint64(0); i < 3; i++ {
+		address := targetAddress(t, i)
+
+		resp := &api.MigratorFaucetResponse{}
+		require.NoError(t, tangle.coordinatorRequest(ctx, http.MethodPost, api.RouteMigratorFaucet, &api.MigratorFaucetRequest{
+			Address: address.Bech32(tangle.protoParams.Bech32HRP),
+			Deposit: deposit,
+		}, resp))
+		migrations[resp.TailTransactionHash] = address
+	}
+
+	// wait until all migrations were included in confirmed receipts
+	receipts := make(map[string]*nodeclient.ReceiptTuple)
+	eventually(t, receiptTimeout, func(ctx context.Context) error {
+		allReceipts, err := tangle.node.Receipts(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, receipt := range allReceipts {
+			for _, entry := range receipt.Receipt.Funds {
+				tailTransactionHash := iotago.EncodeHex(entry.TailTransactionHash[:])
+				if _, exists := migrations[tailTransactionHash]; exists {
+					receipts[tailTransactionHash] = receipt
+				}
+			}
+		}
+
+		if len(receipts) < len(migrations) {
+			return fmt.Errorf("%d of %d migrations were included in receipts", len(receipts), len(migrations))
+		}
+
+		return nil
+	})
+
+	// the migrated funds were paid from the treasury
+	treasuryAfter, err := tangle.treasuryAmount(ctx)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, treasuryBefore-treasuryAfter, uint64(len(migrations)*deposit))
+
+	// the milestones of the receipts created outputs for the migrations
+	createdOutputs := make(map[iotago.MilestoneIndex][]string)
+	for tailTransactionHash, receipt := range receipts {
+		if _, exists := createdOutputs[receipt.MilestoneIndex]; !exists {
+			utxoChanges, err := tangle.node.MilestoneUTXOChangesByIndex(ctx, receipt.MilestoneIndex)
+			require.NoError(t, err)
+			createdOutputs[receipt.MilestoneIndex] = utxoChanges.CreatedOutputs
+		}
+
+		require.Truef(t, outputExists(t, tangle, createdOutputs[receipt.MilestoneIndex], migrations[tailTransactionHash]),
+			"no output for migration %s in milestone %d", tailTransactionHash, receipt.MilestoneIndex)
+	}
+
+	// the coordinator accounted for the migrations and signed the milestones via the remote signer
+	status := &api.StatusResponse{}
+	require.NoError(t, tangle.coordinatorRequest(ctx, http.MethodGet, api.RouteStatus, nil, status))
+	require.NotNil(t, status.Migrator)
+	require.GreaterOrEqual(t, status.Migrator.MigratedEntriesCount, uint64(len(migrations)))
+	require.NotZero(t, status.Signer.LastSuccessTimestamp)
+	require.Zero(t, status.Signer.ConsecutiveFailures)
+}
+
+// outputExists returns whether one of the given outputs is a basic output of the migrated deposit to the given address.
+func outputExists(t *testing.T, tangle *privateTangle, outputIDs []string, address *iotago.Ed25519Address) bool {
+	t.Helper()
+
+	for _, outputIDHex := range outputIDs {
+		outputID, err := iotago.OutputIDFromHex(outputIDHex)
+		require.NoError(t, err)
+
+		resp, err := tangle.node.OutputByID(context.Background(), outputID)
+		require.NoError(t, err)
+
+		output, err := resp.Output()
+		require.NoError(t, err)
+
+		basicOutput, ok := output.(*iotago.BasicOutput)
+		if !ok {
+			continue
+		}
+
+		if addressUnlock := basicOutput.UnlockConditionSet().Address(); addressUnlock != nil && addressUnlock.Address.Equal(address) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+// Command remotesigner is a mock remote signer for the end-to-end tests.
+// It signs milestone essences with the private keys given in the REMOTE_SIGNER_PRV_KEYS environment variable
+// and must never be used with real keys.
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/core/crypto"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/remotesigner"
+)
+
+const (
+	defaultBindAddress = "0.0.0.0:12345"
+)
+
+// signer signs milestone essences with the keys it holds.
+type signer struct {
+	remotesigner.UnimplementedSignatureDispatcherServer
+
+	privateKeys map[iotago.MilestonePublicKey]ed25519.PrivateKey
+}
+
+func (s *signer) SignMilestone(_ context.Context, req *remotesigner.SignMilestoneRequest) (*remotesigner.SignMilestoneResponse, error) {
+	signatures := make([][]byte, len(req.GetPubKeys()))
+	for i, pubKeyBytes := range req.GetPubKeys() {
+		var pubKey iotago.MilestonePublicKey
+		if len(pubKeyBytes) != len(pubKey) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid public key length: %d", len(pubKeyBytes))
+		}
+		copy(pubKey[:], pubKeyBytes)
+
+		privateKey, exists := s.privateKeys[pubKey]
+		if !exists {
+			return nil, status.Errorf(codes.NotFound, "unknown public key: %s", iotago.EncodeHex(pubKeyBytes))
+		}
+
+		signatures[i] = ed25519.Sign(privateKey, req.GetMsEssence())
+	}
+
+	log.Printf("signed milestone essence with %d keys", len(signatures))
+
+	return &remotesigner.SignMilestoneResponse{Signatures: signatures}, nil
+}
+
+// loadPrivateKeys loads the comma separated hex encoded private keys from the given environment variable.
+func loadPrivateKeys(name string) (map[iotago.MilestonePublicKey]ed25519.PrivateKey, error) {
+	keys := os.Getenv(name)
+	if keys == "" {
+		return nil, fmt.Errorf("environment variable '%s' not set", name)
+	}
+
+	privateKeys := make(map[iotago.MilestonePublicKey]ed25519.PrivateKey)
+	for _, key := range strings.Split(keys, ",") {
+		privateKey, err := crypto.ParseEd25519PrivateKeyFromString(key)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable '%s' contains an invalid private key: %w", name, err)
+		}
+
+		var pubKey iotago.MilestonePublicKey
+		copy(pubKey[:], privateKey.Public().(ed25519.PublicKey))
+		privateKeys[pubKey] = privateKey
+	}
+
+	return privateKeys, nil
+}
+
+func main() {
+	privateKeys, err := loadPrivateKeys("REMOTE_SIGNER_PRV_KEYS")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bindAddress := os.Getenv("REMOTE_SIGNER_BIND_ADDRESS")
+	if bindAddress == "" {
+		bindAddress = defaultBindAddress
+	}
+
+	listener, err := net.Listen("tcp", bindAddress)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %s", bindAddress, err)
+	}
+
+	server := grpc.NewServer()
+	remotesigner.RegisterSignatureDispatcherServer(server, &signer{privateKeys: privateKeys})
+
+	log.Printf("mock remote signer with %d keys listening on %s", len(privateKeys), bindAddress)
+	if err := server.Serve(listener); err != nil {
+		log.Fatal(err)
+	}
+}
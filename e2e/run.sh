@@ -0,0 +1,20 @@
+#!/bin/bash
+#
+# Starts the private tangle, runs the end-to-end tests against it and tears it down again.
+# Additional arguments are passed to "go test", e.g.: ./run.sh -run TestMigrationReceipt
+
+set -e
+
+DIR="$( cd -- "$(dirname "$0")" >/dev/null 2>&1 ; pwd -P )"
+
+cleanup() {
+    if [ -z "$E2E_KEEP_RUNNING" ]; then
+        docker compose -f "$DIR/docker-compose.yml" down --volumes
+    fi
+}
+trap cleanup EXIT
+
+docker compose -f "$DIR/docker-compose.yml" up --build --detach
+
+cd "$DIR/.."
+go test -tags e2e -count=1 -timeout 10m "$@" ./e2e/...
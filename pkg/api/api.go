@@ -0,0 +1,875 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+const (
+	// APIRoute is the route of the coordinator API that is registered at the node.
+	APIRoute = "coordinator/v1"
+)
+
+const (
+	// ParameterChangeID is used to identify a signer committee change.
+	ParameterChangeID = "changeID"
+
+	// ParameterMilestoneIndex is used to identify a milestone by its index.
+	ParameterMilestoneIndex = "milestoneIndex"
+
+	// ParameterBlockID is used to identify a block by its ID.
+	ParameterBlockID = "blockID"
+
+	// ParameterTailTransactionHash is used to identify a migration by its tail transaction hash.
+	ParameterTailTransactionHash = "tailTransactionHash"
+
+	// QueryParameterFrom is used to define the sequence number of the first event that is returned.
+	QueryParameterFrom = "from"
+
+	// QueryParameterLimit is used to define the maximum amount of events or receipt entries that are returned.
+	QueryParameterLimit = "limit"
+
+	// QueryParameterOffset is used to define the index of the first receipt entry that is returned.
+	QueryParameterOffset = "offset"
+
+	// QueryParameterFields is used to select the fields of the returned receipt entries (comma separated).
+	QueryParameterFields = "fields"
+)
+
+const (
+	// ReceiptEntryFieldTailTransactionHash selects the tail transaction hash of the receipt entries.
+	ReceiptEntryFieldTailTransactionHash = "tailTransactionHash"
+	// ReceiptEntryFieldAddress selects the address of the receipt entries.
+	ReceiptEntryFieldAddress = "address"
+	// ReceiptEntryFieldDeposit selects the deposit of the receipt entries.
+	ReceiptEntryFieldDeposit = "deposit"
+)
+
+const (
+	// RouteStatus is the route to get the status of the coordinator.
+	// GET returns the status of the coordinator, the migrator and the quorum.
+	RouteStatus = "/status"
+
+	// RouteStatusSigned is the route to get the status of the coordinator signed by its identity key.
+	// GET returns the status of the coordinator as a signed document that can be verified with the public key of the identity.
+	RouteStatusSigned = "/status/signed"
+
+	// RouteSignerCommittee is the route to get the signer committee.
+	// GET returns the members and the prepared changes of the signer committee.
+	RouteSignerCommittee = "/signers"
+
+	// RouteSignerCommitteeChanges is the route to prepare a signer committee change.
+	// POST prepares a change of the signer committee membership.
+	RouteSignerCommitteeChanges = "/signers/changes"
+
+	// RouteSignerCommitteeChange is the route to abort a prepared signer committee change.
+	// DELETE aborts the prepared change.
+	RouteSignerCommitteeChange = "/signers/changes/:" + ParameterChangeID
+
+	// RouteSignerCommitteeChangeCommit is the route to commit a prepared signer committee change.
+	// POST commits the prepared change, which activates at its activation index.
+	RouteSignerCommitteeChangeCommit = "/signers/changes/:" + ParameterChangeID + "/commit"
+
+	// RouteReceiptProof is the route to get the inclusion proof of a receipt.
+	// GET returns the inclusion proof of the receipt contained in the confirmed milestone with the given index.
+	RouteReceiptProof = "/receipts/:" + ParameterMilestoneIndex + "/proof"
+
+	// RouteReceiptEntries is the route to get the entries of a receipt.
+	// GET returns a page of the entries of the receipt contained in the confirmed milestone with the given index,
+	// together with the totals of the receipt. The fields of the entries can be selected, all fields are returned by default.
+	RouteReceiptEntries = "/receipts/:" + ParameterMilestoneIndex + "/entries"
+
+	// RoutePinnedParents is the route to pin blocks as parents of the next milestone.
+	// GET returns the pinned blocks.
+	// POST pins the given blocks, they are unpinned after a milestone referencing them was issued.
+	RoutePinnedParents = "/parents/pinned"
+
+	// RoutePinnedParent is the route to unpin a block.
+	// DELETE unpins the block.
+	RoutePinnedParent = "/parents/pinned/:" + ParameterBlockID
+
+	// RouteMigrationSummary is the route to get the signed summary of the completed migration.
+	// GET returns the summary.
+	RouteMigrationSummary = "/migration/summary"
+
+	// RouteMigrationAttestation is the route to get the attestation that all migrations up to the last legacy milestone were included in receipts.
+	// GET returns the attestation once the migration is completed.
+	RouteMigrationAttestation = "/migration/attestation"
+
+	// RouteMigrationAttestationSigned is the route to get the migration attestation signed by the identity key of the coordinator.
+	// GET returns the signed attestation once the migration is completed.
+	RouteMigrationAttestationSigned = "/migration/attestation/signed"
+
+	// RouteMigratorStateExport is the route to export the persisted migrator state.
+	// GET returns the state archive, which can be imported on another host.
+	RouteMigratorStateExport = "/migrator/state/export"
+
+	// RouteMigratorStateImport is the route to import a migrator state archive.
+	// POST writes the state of the archive to the migrator state files, it is refused if the migrator is running or a state exists.
+	RouteMigratorStateImport = "/migrator/state/import"
+
+	// RouteMigratorIndexJump is the route to get the jump of the migrated at index that awaits confirmation.
+	// GET returns the pending jump.
+	RouteMigratorIndexJump = "/migrator/jump"
+
+	// RouteMigratorIndexJumpConfirm is the route to confirm the jump of the migrated at index.
+	// POST confirms the pending jump to the given legacy milestone index, the held back migrations are released.
+	RouteMigratorIndexJumpConfirm = "/migrator/jump/confirm"
+
+	// RouteMigratorHeldMigrations is the route to get the migrations that are held for review.
+	// GET returns the migrations to flagged addresses that are held.
+	RouteMigratorHeldMigrations = "/migrator/held"
+
+	// RouteMigratorHeldMigrationRelease is the route to release a migration that is held for review.
+	// POST releases the held migration, the migrations of its legacy milestone are passed on once all of them were released.
+	RouteMigratorHeldMigrationRelease = "/migrator/held/:" + ParameterTailTransactionHash + "/release"
+
+	// RouteMigratorFaucet is the route to request synthetic migrations on private tangles.
+	// POST queues a migration, which is confirmed by the next simulated legacy milestone and included in a receipt.
+	RouteMigratorFaucet = "/migrator/faucet"
+
+	// RouteEvents is the route to subscribe to the events of the coordinator.
+	// GET upgrades the connection to a WebSocket, the events are sent as JSON encoded text messages.
+	RouteEvents = "/events"
+
+	// RouteEventJournal is the route to replay the recorded events of the coordinator.
+	// GET returns the events of the journal starting at the sequence number given by the query parameter "from".
+	RouteEventJournal = "/events/journal"
+
+	// RouteDashboard is the route of the embedded dashboard.
+	// GET returns a web page showing the live status of the coordinator.
+	RouteDashboard = "/dashboard"
+)
+
+const (
+	// EventTypeMilestoneIssued is the type of the event that is sent when a milestone was issued.
+	EventTypeMilestoneIssued = "milestoneIssued"
+	// EventTypeCheckpointIssued is the type of the event that is sent when a checkpoint block was issued.
+	EventTypeCheckpointIssued = "checkpointIssued"
+	// EventTypeMilestoneSkipped is the type of the event that is sent when a milestone was skipped.
+	EventTypeMilestoneSkipped = "milestoneSkipped"
+	// EventTypeSoftError is the type of the event that is sent when the coordinator encountered a soft error.
+	EventTypeSoftError = "softError"
+	// EventTypeQuorumFinished is the type of the event that is sent when a quorum call was finished.
+	EventTypeQuorumFinished = "quorumFinished"
+	// EventTypeMilestoneTimeout is the type of the event that is sent when no new milestones were received for some time.
+	EventTypeMilestoneTimeout = "milestoneTimeout"
+	// EventTypeReceiptSigned is the type of the event that is sent when a receipt was signed by the treasury key.
+	EventTypeReceiptSigned = "receiptSigned"
+	// EventTypeReceiptIssued is the type of the event that is sent when a milestone containing a receipt was issued.
+	EventTypeReceiptIssued = "receiptIssued"
+	// EventTypeCanonicalReceipt is the type of the event that is sent with the issued receipt in the canonical JSON schema for external systems.
+	EventTypeCanonicalReceipt = "canonicalReceipt"
+	// EventTypeMilestoneConfirmationFailed is the type of the event that is sent when an issued milestone was not confirmed.
+	EventTypeMilestoneConfirmationFailed = "milestoneConfirmationFailed"
+	// EventTypeMilestoneGapDetected is the type of the event that is sent when missed milestone intervals were detected.
+	EventTypeMilestoneGapDetected = "milestoneGapDetected"
+	// EventTypeClockDriftDetected is the type of the event that is sent when the system clock is behind the latest milestone.
+	EventTypeClockDriftDetected = "clockDriftDetected"
+	// EventTypeReceiptsPaused is the type of the event that is sent when receipts were paused because the node is unhealthy.
+	EventTypeReceiptsPaused = "receiptsPaused"
+	// EventTypeReceiptsResumed is the type of the event that is sent when receipts were resumed because the node is healthy again.
+	EventTypeReceiptsResumed = "receiptsResumed"
+	// EventTypeSigningSelfTestFailed is the type of the event that is sent when a signer failed the signing self-test.
+	EventTypeSigningSelfTestFailed = "signingSelfTestFailed"
+	// EventTypeQuorumNodeDemoted is the type of the event that is sent when a quorum node was demoted from the active quorum set.
+	EventTypeQuorumNodeDemoted = "quorumNodeDemoted"
+	// EventTypeMilestoneTimings is the type of the event that is sent with the durations of the stages of a milestone issuance.
+	EventTypeMilestoneTimings = "milestoneTimings"
+	// EventTypeMigratorSoftError is the type of the event that is sent when the migrator encountered a soft error.
+	EventTypeMigratorSoftError = "migratorSoftError"
+	// EventTypeMigratedFundsFetched is the type of the event that is sent when the migrator fetched new migrations.
+	EventTypeMigratedFundsFetched = "migratedFundsFetched"
+	// EventTypeMigratorErrorAlert is the type of the event that is sent when the migrator raised an error alert.
+	EventTypeMigratorErrorAlert = "migratorErrorAlert"
+	// EventTypeMigratorIndexRangeSkipped is the type of the event that is sent when the migrator skipped legacy milestones without migrations.
+	EventTypeMigratorIndexRangeSkipped = "migratorIndexRangeSkipped"
+	// EventTypeMigratorIndexJumpDetected is the type of the event that is sent when the migrated at index would jump forward by more than allowed.
+	EventTypeMigratorIndexJumpDetected = "migratorIndexJumpDetected"
+	// EventTypeMigratorMigrationHeld is the type of the event that is sent when a migration to a flagged address is held for review.
+	EventTypeMigratorMigrationHeld = "migratorMigrationHeld"
+	// EventTypeMigratorHalted is the type of the event that is sent when the migrator halted the receipt issuance.
+	EventTypeMigratorHalted = "migratorHalted"
+)
+
+// CoordinatorStatus is the status of the coordinator.
+type CoordinatorStatus struct {
+	// The index of the latest issued milestone.
+	LatestMilestoneIndex uint32 `json:"latestMilestoneIndex"`
+	// The ID of the latest issued milestone (hex encoded).
+	LatestMilestoneID string `json:"latestMilestoneId"`
+	// The ID of the block that contains the latest issued milestone (hex encoded).
+	LatestMilestoneBlockID string `json:"latestMilestoneBlockId"`
+	// The unix timestamp of the latest issued milestone.
+	LatestMilestoneTimestamp int64 `json:"latestMilestoneTimestamp"`
+	// The interval milestones are issued in milliseconds.
+	IntervalMilliseconds int64 `json:"intervalMilliseconds"`
+	// The amount of issued milestones containing a receipt that were not confirmed yet.
+	PendingReceipts int `json:"pendingReceipts"`
+	// The index of the first milestone after the latest network reset, if the network was reset.
+	NetworkResetIndex uint32 `json:"networkResetIndex,omitempty"`
+	// The current pause of the receipts because the node is unhealthy.
+	ReceiptPause *ReceiptPauseEvent `json:"receiptPause,omitempty"`
+}
+
+// SignerStatus is the status of the milestone and treasury signers.
+type SignerStatus struct {
+	// The amount of public keys in a milestone.
+	PublicKeysCount int `json:"publicKeysCount"`
+	// The unix timestamp of the latest successful signing attempt (0 = none yet).
+	LastSuccessTimestamp int64 `json:"lastSuccessTimestamp"`
+	// The unix timestamp of the latest failed signing attempt (0 = none yet).
+	LastFailureTimestamp int64 `json:"lastFailureTimestamp,omitempty"`
+	// The error of the latest failed signing attempt.
+	LastError string `json:"lastError,omitempty"`
+	// The amount of consecutive failed signing attempts.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// The unix timestamp of the latest signing self-test (0 = none yet).
+	LastSelfTestTimestamp int64 `json:"lastSelfTestTimestamp,omitempty"`
+	// The error of the latest signing self-test, empty if it succeeded.
+	LastSelfTestError string `json:"lastSelfTestError,omitempty"`
+	// The state of the persistent connections to the remote signers, if they are kept warm.
+	Connections []*RemoteSignerConnectionStatus `json:"connections,omitempty"`
+}
+
+// RemoteSignerConnectionStatus is the state of the persistent connection to a remote signer.
+type RemoteSignerConnectionStatus struct {
+	// The address of the remote signer.
+	Endpoint string `json:"endpoint"`
+	// The connectivity state of the connection, e.g. READY or TRANSIENT_FAILURE.
+	State string `json:"state"`
+	// The unix timestamp since the connection is ready (0 = not ready).
+	ReadySinceTimestamp int64 `json:"readySinceTimestamp,omitempty"`
+	// The unix timestamp of the latest probe of the connection (0 = none yet).
+	LastProbeTimestamp int64 `json:"lastProbeTimestamp,omitempty"`
+	// The error of the latest probe, empty if it succeeded.
+	LastProbeError string `json:"lastProbeError,omitempty"`
+	// The amount of times the connection was re-established after it was lost or the certificates were rotated.
+	Reconnects int `json:"reconnects"`
+}
+
+// MigratorStatus is the status of the migrator.
+type MigratorStatus struct {
+	// The index of the legacy milestone that confirmed the latest included migrations.
+	LatestMigratedAtIndex uint32 `json:"latestMigratedAtIndex"`
+	// The amount of migrations of the latest migrated at index that are already included in receipts.
+	LatestIncludedIndex uint32 `json:"latestIncludedIndex"`
+	// Whether the coordinator is currently sending a receipt.
+	SendingReceipt bool `json:"sendingReceipt"`
+	// The 'sending receipt' window the coordinator is currently inside, if any.
+	SendingReceiptWindow *SendingReceiptWindow `json:"sendingReceiptWindow,omitempty"`
+	// The cumulative amount of migrations included in receipts.
+	MigratedEntriesCount uint64 `json:"migratedEntriesCount"`
+	// The cumulative value of the migrations included in receipts.
+	MigratedValue uint64 `json:"migratedValue"`
+	// The status of the circuit breaker of the legacy node queries.
+	CircuitBreaker *CircuitBreakerStatus `json:"circuitBreaker,omitempty"`
+	// The jump of the migrated at index that awaits confirmation.
+	PendingIndexJump *MigratorIndexJump `json:"pendingIndexJump,omitempty"`
+	// The amount of migrations that are held for review.
+	HeldMigrationsCount int `json:"heldMigrationsCount"`
+	// The halt of the receipt issuance, if a migration result could not be applied to the state.
+	Halt *MigratorHalt `json:"halt,omitempty"`
+	// The limits of the receipts.
+	ReceiptLimits *ReceiptLimits `json:"receiptLimits"`
+}
+
+// SendingReceiptWindow is the window between persisting the state of a receipt that is sent to the network
+// and the confirmation of the milestone containing it. A crash inside the window requires a manual recovery of the migrator state.
+type SendingReceiptWindow struct {
+	// The unix timestamp the window was entered.
+	SinceTimestamp int64 `json:"sinceTimestamp"`
+	// The time the coordinator has been inside the window in seconds.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// The duration after which the coordinator is considered stuck inside the window in seconds (0 = disabled).
+	StuckThresholdSeconds float64 `json:"stuckThresholdSeconds"`
+	// Whether the coordinator has been inside the window for longer than the stuck threshold.
+	Stuck bool `json:"stuck"`
+}
+
+// ReceiptLimits are the limits of the receipts issued by the migrator.
+type ReceiptLimits struct {
+	// The maximum serialized size of a receipt, so that the milestone containing it doesn't exceed the protocol limits (0 = not known).
+	MaxReceiptSize int `json:"maxReceiptSize"`
+	// The maximum serialized size of a receipt, so that the milestone containing it stays below the next PoW requirement step (0 = not known).
+	PoWStepReceiptSize int `json:"powStepReceiptSize"`
+	// The configured maximum amount of entries of a receipt (0 = derived from the receipt size below the PoW requirement step).
+	ConfiguredMaxEntries int `json:"configuredMaxEntries"`
+	// The amount of entries that fit into a receipt below the PoW requirement step with the configured parents and signatures (0 = not known).
+	DerivedMaxEntries int `json:"derivedMaxEntries"`
+	// The maximum amount of entries of a receipt that is applied.
+	MaxEntries int `json:"maxEntries"`
+}
+
+// CircuitBreakerStatus is the status of the circuit breaker of the legacy node queries.
+type CircuitBreakerStatus struct {
+	// The state of the circuit breaker (closed/open/halfOpen).
+	State string `json:"state"`
+	// The amount of consecutive failed queries.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// The unix timestamp the circuit breaker was opened the last time.
+	LastOpenedTimestamp int64 `json:"lastOpenedTimestamp,omitempty"`
+	// The amount of times the circuit breaker was opened.
+	OpenedCount uint64 `json:"openedCount"`
+}
+
+// QuorumClientStatus is the status of a client in the coordinator quorum.
+type QuorumClientStatus struct {
+	// The name of the quorum group the client is member of.
+	Group string `json:"group"`
+	// The optional alias of the quorum client.
+	Alias string `json:"alias,omitempty"`
+	// The base URL of the quorum client.
+	BaseURL string `json:"baseUrl"`
+	// The last response time of the whiteflag API call in seconds.
+	ResponseTimeSeconds float64 `json:"responseTimeSeconds"`
+	// The error of the last whiteflag API call.
+	Error string `json:"error,omitempty"`
+	// The score of the client, if the quorum nodes are scored.
+	Score *QuorumClientScore `json:"score,omitempty"`
+}
+
+// QuorumClientScore is the score of a client in the coordinator quorum over the latest quorum calls.
+type QuorumClientScore struct {
+	// The amount of calls the score is based on.
+	Calls int `json:"calls"`
+	// The amount of calls the client answered with the same merkle roots as the coordinator.
+	Agreements int `json:"agreements"`
+	// The amount of calls the client answered with different merkle roots than the coordinator.
+	Disagreements int `json:"disagreements"`
+	// The amount of calls the client failed or did not answer in time.
+	Failures int `json:"failures"`
+	// The average response time of the answered calls in seconds.
+	AverageResponseTimeSeconds float64 `json:"averageResponseTimeSeconds"`
+	// Whether the client was demoted from the active quorum set.
+	Demoted bool `json:"demoted"`
+	// The reason the client was demoted.
+	DemotionReason string `json:"demotionReason,omitempty"`
+}
+
+// SoftErrorStatus is a soft error that was encountered by the coordinator.
+type SoftErrorStatus struct {
+	// The unix timestamp the error was encountered.
+	Timestamp int64 `json:"timestamp"`
+	// The class of the error.
+	Class string `json:"class"`
+	// The error message.
+	Message string `json:"message"`
+}
+
+// StatusResponse defines the response of a GET status REST API call.
+type StatusResponse struct {
+	// The status of the coordinator.
+	Coordinator *CoordinatorStatus `json:"coordinator"`
+	// The status of the signers.
+	Signer *SignerStatus `json:"signer"`
+	// The status of the migrator, if the migrator is enabled.
+	Migrator *MigratorStatus `json:"migrator,omitempty"`
+	// The status of the quorum clients, if the quorum is enabled.
+	Quorum []*QuorumClientStatus `json:"quorum,omitempty"`
+	// The latest soft errors ordered from the oldest to the newest.
+	SoftErrors []*SoftErrorStatus `json:"softErrors"`
+	// The environment the coordinator was started in.
+	Environment *EnvironmentReport `json:"environment,omitempty"`
+}
+
+// EnvironmentReport describes the environment the application was started in, sensitive values are redacted.
+type EnvironmentReport struct {
+	// The name of the application.
+	Name string `json:"name"`
+	// The version of the application.
+	Version string `json:"version"`
+	// The Go version the application was built with.
+	GoVersion string `json:"goVersion"`
+	// The operating system and architecture the application runs on.
+	Platform string `json:"platform"`
+	// The unix timestamp the application was started.
+	StartTimestamp int64 `json:"startTimestamp"`
+	// The effective configuration with redacted sensitive values.
+	Config map[string]any `json:"config"`
+	// The public keys used by the application.
+	Keys []*EnvironmentKey `json:"keys"`
+	// The state files of the application.
+	StateFiles []*EnvironmentStateFile `json:"stateFiles"`
+	// The modules the application was built with.
+	Dependencies []*EnvironmentDependency `json:"dependencies"`
+}
+
+// EnvironmentKey is a public key used by the application.
+type EnvironmentKey struct {
+	// The purpose of the key.
+	Purpose string `json:"purpose"`
+	// The public key (hex encoded).
+	PublicKey string `json:"publicKey"`
+	// The fingerprint of the public key.
+	Fingerprint string `json:"fingerprint"`
+	// The milestone index from which on the key is valid.
+	StartIndex uint32 `json:"startIndex,omitempty"`
+	// The milestone index until which the key is valid (0 = no end).
+	EndIndex uint32 `json:"endIndex,omitempty"`
+}
+
+// EnvironmentStateFile is a state file of the application.
+type EnvironmentStateFile struct {
+	// The name of the state.
+	Name string `json:"name"`
+	// The path to the state file.
+	Path string `json:"path"`
+	// Whether the state file existed at startup.
+	Exists bool `json:"exists"`
+	// The schema version the state file was stored with at startup.
+	StoredVersion uint32 `json:"storedVersion"`
+	// The schema version that is written by the application.
+	SupportedVersion uint32 `json:"supportedVersion"`
+	// The error that occurred while reading the state file.
+	Error string `json:"error,omitempty"`
+}
+
+// EnvironmentDependency is a module the application was built with.
+type EnvironmentDependency struct {
+	// The path of the module.
+	Path string `json:"path"`
+	// The version of the module.
+	Version string `json:"version"`
+	// The module the dependency was replaced with.
+	Replace string `json:"replace,omitempty"`
+}
+
+// SignerCommitteeMember is a remote signer that is member of the signer committee.
+type SignerCommitteeMember struct {
+	// The address of the remote signer.
+	RemoteAddress string `json:"remoteAddress"`
+	// The public key of the remote signer (hex encoded).
+	PublicKey string `json:"publicKey"`
+	// The milestone index from which on the member signs milestones.
+	StartIndex uint32 `json:"startIndex,omitempty"`
+	// The milestone index from which on the member no longer signs milestones (0 = no end).
+	EndIndex uint32 `json:"endIndex,omitempty"`
+}
+
+// SignerCommitteeChange is a change of the signer committee membership.
+type SignerCommitteeChange struct {
+	// The ID of the change.
+	ID string `json:"id,omitempty"`
+	// The milestone index at which the change activates.
+	ActivationIndex uint32 `json:"activationIndex"`
+	// The members that are added to the committee.
+	Add []*SignerCommitteeMember `json:"add,omitempty"`
+	// The public keys (hex encoded) of the members that are removed from the committee.
+	Remove []string `json:"remove,omitempty"`
+}
+
+// SignerCommitteeResponse defines the response of a GET signer committee REST API call.
+type SignerCommitteeResponse struct {
+	// The members of the signer committee.
+	Members []*SignerCommitteeMember `json:"members"`
+	// The prepared, but not yet committed changes.
+	PreparedChanges []*SignerCommitteeChange `json:"preparedChanges"`
+}
+
+// ReceiptProofResponse defines the response of a GET receipt inclusion proof REST API call.
+// The proof can be verified without a node by checking the milestone signatures against the milestone public keys.
+type ReceiptProofResponse struct {
+	// The index of the milestone that contains the receipt.
+	MilestoneIndex uint32 `json:"milestoneIndex"`
+	// The ID of the milestone that contains the receipt (hex encoded).
+	MilestoneID string `json:"milestoneId"`
+	// The ID of the block that contains the milestone (hex encoded).
+	BlockID string `json:"blockId"`
+	// The index of the receipt within the milestone options.
+	ReceiptOptionIndex int `json:"receiptOptionIndex"`
+	// The signed milestone payload.
+	Milestone json.RawMessage `json:"milestone"`
+	// The signature of the treasury key over the receipt, if the receipt was co-signed by the treasury key.
+	TreasurySignature *TreasurySignature `json:"treasurySignature,omitempty"`
+}
+
+// TreasurySignature is the signature of the treasury key over the essence of a receipt.
+type TreasurySignature struct {
+	// The public key of the treasury key (hex encoded).
+	PublicKey string `json:"publicKey"`
+	// The signature over the receipt essence (hex encoded).
+	Signature string `json:"signature"`
+}
+
+// ReceiptEntry is an entry of a receipt, only the selected fields are set.
+type ReceiptEntry struct {
+	// The index of the entry within the receipt.
+	Index int `json:"index"`
+	// The tail transaction hash of the migration bundle (hex encoded).
+	TailTransactionHash string `json:"tailTransactionHash,omitempty"`
+	// The target address of the migrated funds (bech32 encoded).
+	Address string `json:"address,omitempty"`
+	// The amount of migrated funds.
+	Deposit uint64 `json:"deposit,omitempty"`
+}
+
+// ReceiptEntriesResponse defines the response of a GET receipt entries REST API call.
+// The totals always cover the whole receipt, independent of the returned page.
+type ReceiptEntriesResponse struct {
+	// The index of the milestone that contains the receipt.
+	MilestoneIndex uint32 `json:"milestoneIndex"`
+	// The legacy milestone index at which the funds were migrated.
+	MigratedAt uint32 `json:"migratedAt"`
+	// Whether the receipt is the final one of the migration.
+	Final bool `json:"final"`
+	// The amount of entries of the receipt.
+	EntriesCount int `json:"entriesCount"`
+	// The value of all entries of the receipt.
+	Value uint64 `json:"value"`
+	// The index of the first returned entry.
+	Offset int `json:"offset"`
+	// The returned entries, ordered as in the receipt.
+	Entries []*ReceiptEntry `json:"entries"`
+}
+
+// PinnedParentsRequest defines the request of a POST pinned parents REST API call.
+type PinnedParentsRequest struct {
+	// The IDs of the blocks to pin (hex encoded).
+	BlockIDs []string `json:"blockIds"`
+}
+
+// PinnedParentsResponse defines the response of a GET or POST pinned parents REST API call.
+type PinnedParentsResponse struct {
+	// The IDs of the pinned blocks (hex encoded).
+	BlockIDs []string `json:"blockIds"`
+}
+
+// MigrationSummarySignature is a signature of a milestone key over the essence of the migration summary.
+type MigrationSummarySignature struct {
+	// The public key of the milestone key (hex encoded).
+	PublicKey string `json:"publicKey"`
+	// The signature (hex encoded).
+	Signature string `json:"signature"`
+}
+
+// MigrationSummaryResponse defines the response of a GET migration summary REST API call.
+// The summary can be verified without a node by checking the signatures against the milestone public keys.
+type MigrationSummaryResponse struct {
+	// The first legacy milestone index whose migrations were included in a receipt.
+	FirstMigratedAtIndex uint32 `json:"firstMigratedAtIndex"`
+	// The last legacy milestone index of the migration.
+	LastMigratedAtIndex uint32 `json:"lastMigratedAtIndex"`
+	// The amount of migrations that were included in receipts.
+	MigratedEntriesCount uint64 `json:"migratedEntriesCount"`
+	// The value of the migrations that were included in receipts.
+	MigratedValue uint64 `json:"migratedValue"`
+	// The amount of issued receipts.
+	ReceiptsCount uint64 `json:"receiptsCount"`
+	// The index of the milestone that contains the final receipt.
+	MilestoneIndex uint32 `json:"milestoneIndex"`
+	// The ID of the milestone that contains the final receipt (hex encoded).
+	MilestoneID string `json:"milestoneId"`
+	// The signatures of the milestone keys over the essence of the summary.
+	Signatures []*MigrationSummarySignature `json:"signatures"`
+}
+
+// MigrationExclusion is a migration up to the last legacy milestone that is known to not be included in a receipt.
+type MigrationExclusion struct {
+	// The legacy milestone index the migration was confirmed at.
+	MigratedAt uint32 `json:"migratedAt"`
+	// The hex encoded tail transaction hash of the migration.
+	TailTransactionHash string `json:"tailTransactionHash"`
+	// The bech32 encoded target address of the migration.
+	Address string `json:"address"`
+	// The deposit of the migration.
+	Deposit uint64 `json:"deposit"`
+	// The reason why the migration is not included.
+	Reason string `json:"reason"`
+}
+
+// MigrationAttestationResponse defines the response of a GET migration attestation REST API call.
+// The summary can be verified against the milestone public keys, the digest of the included tail transaction hashes
+// can be compared with the digest of the own records of the legacy migrations.
+type MigrationAttestationResponse struct {
+	// The signed summary of the completed migration.
+	Summary *MigrationSummaryResponse `json:"summary"`
+	// The XOR of the BLAKE2b-256 hashes of the records (tail transaction hash + little-endian uint32 migrated at index)
+	// of all migrations that were included in receipts (hex encoded), empty if it is not known.
+	IncludedHashesDigest string `json:"includedHashesDigest,omitempty"`
+	// Whether all migrations up to the last legacy milestone were included in receipts without exclusions.
+	Complete bool `json:"complete"`
+	// The migrations up to the last legacy milestone that are known to not be included in a receipt.
+	Exclusions []*MigrationExclusion `json:"exclusions"`
+}
+
+// MigratorStateImportResponse defines the response of a POST migrator state import REST API call.
+type MigratorStateImportResponse struct {
+	// The latest legacy milestone index whose migrations were included in a receipt.
+	LatestMigratedAtIndex uint32 `json:"latestMigratedAtIndex"`
+	// The amount of imported tail transaction hashes of migrations that were included in receipts.
+	IncludedHashesCount int `json:"includedHashesCount"`
+	// The highest legacy milestone index of the imported fetch checkpoint (0 = none).
+	FetchCheckpointVerifiedIndex uint32 `json:"fetchCheckpointVerifiedIndex"`
+}
+
+// Event is an event of the coordinator that is sent to the subscribers of the event stream.
+type Event struct {
+	// The sequence number of the event in the event journal, 0 if the journal is disabled or the event was dropped by its queue.
+	Sequence uint64 `json:"sequence,omitempty"`
+	// The type of the event.
+	Type string `json:"type"`
+	// The unix timestamp of the event.
+	Timestamp int64 `json:"timestamp"`
+	// The payload of the event, depending on the type.
+	Data any `json:"data,omitempty"`
+}
+
+// MilestoneIssuedEvent is the payload of the milestone issued event.
+type MilestoneIssuedEvent struct {
+	// The index of the issued milestone.
+	Index uint32 `json:"index"`
+	// The ID of the issued milestone (hex encoded).
+	MilestoneID string `json:"milestoneId"`
+	// The ID of the block that contains the milestone (hex encoded).
+	BlockID string `json:"blockId"`
+}
+
+// CheckpointIssuedEvent is the payload of the checkpoint issued event.
+type CheckpointIssuedEvent struct {
+	// The index of the checkpoint.
+	Index int `json:"index"`
+	// The ID of the checkpoint block (hex encoded).
+	BlockID string `json:"blockId"`
+}
+
+// MilestoneSkippedEvent is the payload of the milestone skipped event.
+type MilestoneSkippedEvent struct {
+	// The index of the skipped milestone.
+	Index uint32 `json:"index"`
+	// The reason the milestone was skipped.
+	Reason string `json:"reason"`
+}
+
+// QuorumFinishedEvent is the payload of the quorum finished event.
+type QuorumFinishedEvent struct {
+	// The duration of the quorum call in milliseconds.
+	DurationMilliseconds int64 `json:"durationMilliseconds"`
+	// The error of the quorum call, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// ReceiptSignedEvent is the payload of the receipt signed event.
+type ReceiptSignedEvent struct {
+	// The index of the milestone that contains the receipt.
+	Index uint32 `json:"index"`
+	// The public key of the treasury key (hex encoded).
+	PublicKey string `json:"publicKey"`
+}
+
+// ReceiptIssuedEvent is the payload of the receipt issued event.
+type ReceiptIssuedEvent struct {
+	// The index of the milestone that contains the receipt.
+	Index uint32 `json:"index"`
+	// The index of the legacy milestone the funds were migrated at.
+	MigratedAt uint32 `json:"migratedAt"`
+	// Whether the receipt is the final one for the legacy milestone.
+	Final bool `json:"final"`
+	// The amount of migrated funds entries in the receipt.
+	EntriesCount int `json:"entriesCount"`
+	// The serialized size of the receipt.
+	Size int `json:"size"`
+}
+
+// MilestoneConfirmationFailedEvent is the payload of the milestone confirmation failed event.
+type MilestoneConfirmationFailedEvent struct {
+	// The index of the issued milestone.
+	Index uint32 `json:"index"`
+	// The ID of the issued milestone (hex encoded).
+	MilestoneID string `json:"milestoneId"`
+	// The ID of the milestone the node confirmed instead (hex encoded), empty if the milestone was not confirmed in time.
+	ConfirmedMilestoneID string `json:"confirmedMilestoneId,omitempty"`
+	// Whether the issued milestone contains a receipt.
+	HasReceipt bool `json:"hasReceipt"`
+}
+
+// MilestoneGapDetectedEvent is the payload of the milestone gap detected event.
+type MilestoneGapDetectedEvent struct {
+	// The duration since the latest milestone in milliseconds.
+	GapMilliseconds int64 `json:"gapMilliseconds"`
+	// The amount of missed milestone intervals.
+	MissedIntervals int `json:"missedIntervals"`
+	// The catch-up policy that is applied.
+	Policy string `json:"policy"`
+	// The amount of milestones that are issued to catch up.
+	CatchUpMilestones int `json:"catchUpMilestones"`
+}
+
+// ClockDriftDetectedEvent is the payload of the event that is sent when the system clock is behind the latest milestone.
+type ClockDriftDetectedEvent struct {
+	// The index of the milestone that is about to be issued.
+	Index uint32 `json:"index"`
+	// The duration the system clock is behind the latest milestone in milliseconds.
+	DriftMilliseconds int64 `json:"driftMilliseconds"`
+	// The duration the issuance of the milestone is delayed in milliseconds, zero if the milestone is skipped.
+	DelayMilliseconds int64 `json:"delayMilliseconds"`
+}
+
+// ReceiptPauseEvent is the payload of the events that are sent when receipts were paused or resumed because of the health of the node.
+type ReceiptPauseEvent struct {
+	// The index of the milestone the receipts were paused with.
+	Index uint32 `json:"index"`
+	// The reason the receipts were paused.
+	Reason string `json:"reason"`
+	// The unix timestamp the receipts were paused.
+	SinceTimestamp int64 `json:"sinceTimestamp"`
+	// The index of the milestone the receipts were resumed with, only set once the receipts were resumed.
+	ResumedIndex uint32 `json:"resumedIndex,omitempty"`
+	// The duration of the pause in milliseconds, only set once the receipts were resumed.
+	DurationMilliseconds int64 `json:"durationMilliseconds,omitempty"`
+}
+
+// SigningSelfTestFailedEvent is sent when a signer failed the signing self-test.
+type SigningSelfTestFailedEvent struct {
+	// The index of the next milestone whose signers were tested.
+	Index uint32 `json:"index"`
+	// The error of the signing self-test.
+	Message string `json:"message"`
+}
+
+// QuorumNodeDemotedEvent is sent when a quorum node was demoted from the active quorum set.
+type QuorumNodeDemotedEvent struct {
+	// The name of the quorum group the node is member of.
+	Group string `json:"group"`
+	// The optional alias of the quorum node.
+	Alias string `json:"alias,omitempty"`
+	// The base URL of the quorum node.
+	BaseURL string `json:"baseUrl"`
+	// The reason the node was demoted.
+	Reason string `json:"reason"`
+}
+
+// MilestoneStageTiming is the duration of a single stage of a milestone issuance.
+type MilestoneStageTiming struct {
+	// The name of the stage.
+	Stage string `json:"stage"`
+	// The duration of the stage in milliseconds.
+	DurationMilliseconds int64 `json:"durationMilliseconds"`
+	// The budget of the stage in milliseconds, zero if the stage is not budgeted.
+	BudgetMilliseconds int64 `json:"budgetMilliseconds"`
+	// Whether the stage exceeded its budget.
+	Exceeded bool `json:"exceeded"`
+}
+
+// MilestoneTimingsEvent is the payload of the event that is sent with the durations of the stages of a milestone issuance.
+type MilestoneTimingsEvent struct {
+	// The index of the milestone.
+	Index uint32 `json:"index"`
+	// Whether the milestone was issued.
+	Issued bool `json:"issued"`
+	// The duration of the whole issuance in milliseconds.
+	TotalMilliseconds int64 `json:"totalMilliseconds"`
+	// The latency budget of the milestone in milliseconds, zero if the budget is disabled.
+	BudgetMilliseconds int64 `json:"budgetMilliseconds"`
+	// The durations of the executed stages.
+	Stages []*MilestoneStageTiming `json:"stages"`
+	// The degradations that were applied to stay within the budget.
+	Degradations []string `json:"degradations"`
+}
+
+// MigratedFundsFetchedEvent is the payload of the migrated funds fetched event.
+type MigratedFundsFetchedEvent struct {
+	// The amount of fetched migrated funds entries.
+	EntriesCount int `json:"entriesCount"`
+	// The sum of the deposits of the fetched entries.
+	Value uint64 `json:"value"`
+}
+
+// MigratorErrorAlertEvent is the payload of the migrator error alert event.
+type MigratorErrorAlertEvent struct {
+	// The class of the error.
+	Class string `json:"class"`
+	// The error message.
+	Message string `json:"message"`
+}
+
+// MigratorIndexRangeSkippedEvent is the payload of the event that is sent when the migrator skipped legacy milestones without migrations.
+type MigratorIndexRangeSkippedEvent struct {
+	// The first skipped legacy milestone index.
+	StartIndex uint32 `json:"startIndex"`
+	// The last skipped legacy milestone index.
+	EndIndex uint32 `json:"endIndex"`
+}
+
+// MigratorHalt is the halt of the receipt issuance after a migration result could not be applied to the state.
+type MigratorHalt struct {
+	// The unix timestamp the receipt issuance was halted.
+	Timestamp int64 `json:"timestamp"`
+	// The reason of the halt.
+	Message string `json:"message"`
+	// The path to the diagnostic dump of the state and the offending result.
+	DumpFilePath string `json:"dumpFilePath,omitempty"`
+	// The error that occurred while writing the diagnostic dump.
+	DumpError string `json:"dumpError,omitempty"`
+}
+
+// MigratorIndexJump is a jump of the migrated at index by more legacy milestones than allowed at once.
+type MigratorIndexJump struct {
+	// The legacy milestone index of the latest migrations.
+	FromIndex uint32 `json:"fromIndex"`
+	// The legacy milestone index of the held back migrations.
+	ToIndex uint32 `json:"toIndex"`
+	// The amount of held back migrations.
+	EntriesCount int `json:"entriesCount"`
+}
+
+// MigratorIndexJumpConfirmRequest defines the request of a POST migrator index jump confirm REST API call.
+type MigratorIndexJumpConfirmRequest struct {
+	// The legacy milestone index of the jump that is confirmed.
+	ToIndex uint32 `json:"toIndex"`
+}
+
+// MigratorHeldMigration is a migration to a flagged address that is held for review.
+type MigratorHeldMigration struct {
+	// The legacy milestone index the migration was confirmed at.
+	MigratedAt uint32 `json:"migratedAt"`
+	// The hex encoded tail transaction hash of the migration.
+	TailTransactionHash string `json:"tailTransactionHash"`
+	// The bech32 encoded target address of the migration.
+	Address string `json:"address"`
+	// The deposit of the migration.
+	Deposit uint64 `json:"deposit"`
+	// The reason why the migration is held.
+	Reason string `json:"reason"`
+}
+
+// MigratorHeldMigrationsResponse defines the response of a GET migrator held migrations REST API call.
+type MigratorHeldMigrationsResponse struct {
+	// The migrations that are held for review.
+	Migrations []*MigratorHeldMigration `json:"migrations"`
+}
+
+// MigratorFaucetRequest defines the request of a POST migrator faucet REST API call.
+type MigratorFaucetRequest struct {
+	// The bech32 encoded Ed25519 target address of the migration.
+	Address string `json:"address"`
+	// The deposit of the migration.
+	Deposit uint64 `json:"deposit"`
+}
+
+// MigratorFaucetResponse defines the response of a POST migrator faucet REST API call.
+type MigratorFaucetResponse struct {
+	// The hex encoded random tail transaction hash that identifies the migration.
+	TailTransactionHash string `json:"tailTransactionHash"`
+	// The bech32 encoded target address of the migration.
+	Address string `json:"address"`
+	// The deposit of the migration.
+	Deposit uint64 `json:"deposit"`
+	// The amount of requested migrations that wait to be confirmed, including this one.
+	PendingCount int `json:"pendingCount"`
+}
+
+// EventJournalResponse defines the response of a GET event journal REST API call.
+type EventJournalResponse struct {
+	// The events ordered by their sequence number.
+	Events []*Event `json:"events"`
+	// The sequence number of the latest recorded event.
+	LatestSequence uint64 `json:"latestSequence"`
+}
+
+// ErrorResponse defines the error response of the REST API.
+type ErrorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
@@ -0,0 +1,116 @@
+package apiauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultHeader is the header the token is expected in by default, with the 'Bearer' scheme.
+	DefaultHeader = echo.HeaderAuthorization
+
+	// bearerScheme is the scheme of the token in the default header.
+	bearerScheme = "Bearer "
+)
+
+var (
+	// ErrInvalidToken is returned when the token could not be loaded or is invalid.
+	ErrInvalidToken = errors.New("invalid API token")
+	// ErrUnauthorized is returned to requests that don't contain the token.
+	ErrUnauthorized = echo.NewHTTPError(http.StatusUnauthorized, "missing or invalid API token")
+)
+
+// LoadToken loads the token from the given file, or from the given environment variable if no file is given.
+// Surrounding whitespace is ignored.
+func LoadToken(filePath string, envVar string) (string, error) {
+	var token string
+	if filePath != "" {
+		//nolint:gosec // the path is defined by the operator
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return "", fmt.Errorf("%w: unable to read token file: %v", ErrInvalidToken, err)
+		}
+		token = string(data)
+	} else {
+		token = os.Getenv(envVar)
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		if filePath != "" {
+			return "", fmt.Errorf("%w: token file '%s' is empty", ErrInvalidToken, filePath)
+		}
+
+		return "", fmt.Errorf("%w: environment variable '%s' not set", ErrInvalidToken, envVar)
+	}
+
+	return token, nil
+}
+
+// TokenAuth authenticates the requests with a static token.
+type TokenAuth struct {
+	// the header the token is expected in.
+	header string
+	// the hash of the token, so that the comparison doesn't depend on the length of the given token.
+	tokenHash [sha256.Size]byte
+}
+
+// NewTokenAuth creates a new TokenAuth that expects the given token in the given header.
+// If the header is empty, the token is expected in the 'Authorization' header with the 'Bearer' scheme.
+func NewTokenAuth(token string, header string) (*TokenAuth, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: empty token", ErrInvalidToken)
+	}
+
+	if header == "" {
+		header = DefaultHeader
+	}
+
+	return &TokenAuth{
+		header:    header,
+		tokenHash: sha256.Sum256([]byte(token)),
+	}, nil
+}
+
+// Header returns the header the token is expected in.
+func (a *TokenAuth) Header() string {
+	return a.header
+}
+
+// Authorized returns whether the request contains the token.
+func (a *TokenAuth) Authorized(req *http.Request) bool {
+	token := req.Header.Get(a.header)
+	if a.header == DefaultHeader {
+		if !strings.HasPrefix(token, bearerScheme) {
+			return false
+		}
+		token = strings.TrimPrefix(token, bearerScheme)
+	}
+
+	if token == "" {
+		return false
+	}
+	tokenHash := sha256.Sum256([]byte(token))
+
+	return subtle.ConstantTimeCompare(tokenHash[:], a.tokenHash[:]) == 1
+}
+
+// Middleware returns a middleware that rejects requests without the token.
+func (a *TokenAuth) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !a.Authorized(c.Request()) {
+				return ErrUnauthorized
+			}
+
+			return next(c)
+		}
+	}
+}
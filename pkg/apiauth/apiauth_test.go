@@ -0,0 +1,178 @@
+package apiauth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/apiauth"
+	"github.com/iotaledger/inx-coordinator/pkg/client"
+)
+
+func TestLoadToken(t *testing.T) {
+	t.Setenv("TEST_API_TOKEN", " secret\n")
+	token, err := apiauth.LoadToken("", "TEST_API_TOKEN")
+	require.NoError(t, err)
+	require.Equal(t, "secret", token)
+
+	_, err = apiauth.LoadToken("", "TEST_API_TOKEN_MISSING")
+	require.ErrorIs(t, err, apiauth.ErrInvalidToken)
+
+	// the file takes priority over the environment variable
+	filePath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(filePath, []byte("file-secret\n"), 0600))
+	token, err = apiauth.LoadToken(filePath, "TEST_API_TOKEN")
+	require.NoError(t, err)
+	require.Equal(t, "file-secret", token)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("\n"), 0600))
+	_, err = apiauth.LoadToken(filePath, "TEST_API_TOKEN")
+	require.ErrorIs(t, err, apiauth.ErrInvalidToken)
+
+	_, err = apiauth.LoadToken(filepath.Join(t.TempDir(), "missing"), "TEST_API_TOKEN")
+	require.ErrorIs(t, err, apiauth.ErrInvalidToken)
+}
+
+func TestTokenAuthAuthorized(t *testing.T) {
+	_, err := apiauth.NewTokenAuth("", "")
+	require.ErrorIs(t, err, apiauth.ErrInvalidToken)
+
+	tokenAuth, err := apiauth.NewTokenAuth("secret", "")
+	require.NoError(t, err)
+	require.Equal(t, apiauth.DefaultHeader, tokenAuth.Header())
+
+	tests := []struct {
+		name          string
+		authorization string
+		authorized    bool
+	}{
+		{name: "bearer token", authorization: "Bearer secret", authorized: true},
+		{name: "no token", authorization: "", authorized: false},
+		{name: "wrong token", authorization: "Bearer other", authorized: false},
+		{name: "token prefix", authorization: "Bearer secre", authorized: false},
+		{name: "missing scheme", authorization: "secret", authorized: false},
+		{name: "empty bearer token", authorization: "Bearer ", authorized: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if test.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, test.authorization)
+			}
+			require.Equal(t, test.authorized, tokenAuth.Authorized(req))
+		})
+	}
+
+	// a custom header contains the plain token
+	tokenAuth, err = apiauth.NewTokenAuth("secret", "X-Coordinator-Token")
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("X-Coordinator-Token", "secret")
+	require.True(t, tokenAuth.Authorized(req))
+	req.Header.Set(echo.HeaderAuthorization, "Bearer secret")
+	req.Header.Del("X-Coordinator-Token")
+	require.False(t, tokenAuth.Authorized(req))
+}
+
+func TestTokenAuthMiddleware(t *testing.T) {
+	tokenAuth, err := apiauth.NewTokenAuth("secret", "")
+	require.NoError(t, err)
+
+	var pinned int
+	e := echo.New()
+	e.GET(api.RoutePinnedParents, func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]any{"blockIds": []string{}})
+	})
+	e.POST(api.RoutePinnedParents, func(c echo.Context) error {
+		pinned++
+
+		return c.JSON(http.StatusOK, map[string]any{"blockIds": []string{"0x01"}})
+	}, tokenAuth.Middleware())
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	// unprotected routes don't need the token
+	_, err = client.New(server.URL).PinnedParents(context.Background())
+	require.NoError(t, err)
+
+	_, err = client.New(server.URL).PinParents(context.Background(), []string{"0x01"})
+	var httpErr *client.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+
+	_, err = client.New(server.URL, client.WithAPIToken("other", "")).PinParents(context.Background(), []string{"0x01"})
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+	require.Zero(t, pinned)
+
+	resp, err := client.New(server.URL, client.WithAPIToken("secret", "")).PinParents(context.Background(), []string{"0x01"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"0x01"}, resp.BlockIDs)
+	require.Equal(t, 1, pinned)
+}
+
+func TestTokenAuthMiddlewareFaucet(t *testing.T) {
+	tokenAuth, err := apiauth.NewTokenAuth("secret", "")
+	require.NoError(t, err)
+
+	var requested int
+	e := echo.New()
+	e.POST(api.RouteMigratorFaucet, func(c echo.Context) error {
+		requested++
+
+		return c.JSON(http.StatusAccepted, map[string]any{})
+	}, tokenAuth.Middleware())
+
+	// faucet requests mint migrated funds, so they need the token as well
+	body := `{"address":"rms1qp5hp4jm5tshfvj0yt2n6w5fpwzx55ydzsdnz4n9xwzz9u07fqunk9dcqx8","deposit":1000000}`
+	req := httptest.NewRequest(http.MethodPost, api.RouteMigratorFaucet, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Zero(t, requested)
+
+	req = httptest.NewRequest(http.MethodPost, api.RouteMigratorFaucet, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer secret")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.Equal(t, 1, requested)
+}
+
+func TestTokenAuthMiddlewareStateExport(t *testing.T) {
+	tokenAuth, err := apiauth.NewTokenAuth("secret", "")
+	require.NoError(t, err)
+
+	var exported int
+	e := echo.New()
+	e.GET(api.RouteMigratorStateExport, func(c echo.Context) error {
+		exported++
+
+		return c.JSON(http.StatusOK, map[string]any{})
+	}, tokenAuth.Middleware())
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	// the export contains the whole migrator state, so it needs the token although it doesn't change the state
+	_, err = client.New(server.URL).ExportMigratorState(context.Background())
+	var httpErr *client.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+	require.Zero(t, exported)
+
+	_, err = client.New(server.URL, client.WithAPIToken("secret", "")).ExportMigratorState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, exported)
+}
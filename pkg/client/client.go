@@ -0,0 +1,409 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/timeutil"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+)
+
+const (
+	defaultRetryAmount  = 3
+	defaultRetryTimeout = 500 * time.Millisecond
+	defaultHTTPTimeout  = 10 * time.Second
+)
+
+var (
+	// ErrHTTPRequestFailed is returned when the API returned an error status code.
+	ErrHTTPRequestFailed = errors.New("http request failed")
+)
+
+// HTTPError is returned when the API returned an error status code.
+type HTTPError struct {
+	// The status code of the response.
+	StatusCode int
+	// The error message returned by the API.
+	Message string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s: status code %d, %s", ErrHTTPRequestFailed, e.StatusCode, e.Message)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return ErrHTTPRequestFailed
+}
+
+// Client is a client for the coordinator API.
+type Client struct {
+	// the base URL of the coordinator API.
+	baseURL string
+	// the HTTP client used for the requests.
+	httpClient *http.Client
+	// the optional bearer token used to authenticate at the node.
+	bearerToken string
+	// the optional token used to authenticate at the routes that change the state of the coordinator.
+	apiToken string
+	// the header the API token is sent in.
+	apiTokenHeader string
+	// the amount of times a failed idempotent request is retried.
+	retryAmount int
+	// the timeout between retries.
+	retryTimeout time.Duration
+}
+
+// WithHTTPClient defines the HTTP client used for the requests.
+func WithHTTPClient(httpClient *http.Client) options.Option[Client] {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBearerToken defines the bearer token used to authenticate at the node,
+// if the coordinator API is accessed via the node API.
+func WithBearerToken(bearerToken string) options.Option[Client] {
+	return func(c *Client) {
+		c.bearerToken = bearerToken
+	}
+}
+
+// WithAPIToken defines the token used to authenticate at the routes that change the state of the coordinator.
+// If the header is empty, the token is sent in the 'Authorization' header with the 'Bearer' scheme,
+// which is not possible if the coordinator API is accessed via the node API with a bearer token.
+func WithAPIToken(token string, header string) options.Option[Client] {
+	return func(c *Client) {
+		c.apiToken = token
+		c.apiTokenHeader = header
+	}
+}
+
+// WithRetryAmount defines the amount of times a failed request is retried.
+// Only idempotent requests are retried, requests that change the state of the coordinator are sent once.
+func WithRetryAmount(retryAmount int) options.Option[Client] {
+	return func(c *Client) {
+		c.retryAmount = retryAmount
+	}
+}
+
+// WithRetryTimeout defines the timeout between retries.
+func WithRetryTimeout(retryTimeout time.Duration) options.Option[Client] {
+	return func(c *Client) {
+		c.retryTimeout = retryTimeout
+	}
+}
+
+// New creates a new client for the coordinator API.
+// baseURL is either the address of the coordinator API itself,
+// or the address of the node API including the coordinator route (e.g. "http://localhost:14265/api/coordinator/v1").
+func New(baseURL string, opts ...options.Option[Client]) *Client {
+	return options.Apply(&Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		httpClient:   &http.Client{Timeout: defaultHTTPTimeout},
+		retryAmount:  defaultRetryAmount,
+		retryTimeout: defaultRetryTimeout,
+	}, opts)
+}
+
+// Status returns the status of the coordinator.
+func (c *Client) Status(ctx context.Context) (*api.StatusResponse, error) {
+	res := &api.StatusResponse{}
+	if err := c.do(ctx, http.MethodGet, api.RouteStatus, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SignerCommittee returns the members and the prepared changes of the signer committee.
+func (c *Client) SignerCommittee(ctx context.Context) (*api.SignerCommitteeResponse, error) {
+	res := &api.SignerCommitteeResponse{}
+	if err := c.do(ctx, http.MethodGet, api.RouteSignerCommittee, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// PrepareSignerCommitteeChange prepares a change of the signer committee membership.
+// The returned change needs to be committed to become effective.
+func (c *Client) PrepareSignerCommitteeChange(ctx context.Context, change *api.SignerCommitteeChange) (*api.SignerCommitteeChange, error) {
+	res := &api.SignerCommitteeChange{}
+	if err := c.do(ctx, http.MethodPost, api.RouteSignerCommitteeChanges, change, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CommitSignerCommitteeChange commits a prepared change of the signer committee membership.
+func (c *Client) CommitSignerCommitteeChange(ctx context.Context, changeID string) (*api.SignerCommitteeChange, error) {
+	res := &api.SignerCommitteeChange{}
+	if err := c.do(ctx, http.MethodPost, routeWithParameter(api.RouteSignerCommitteeChangeCommit, api.ParameterChangeID, changeID), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AbortSignerCommitteeChange aborts a prepared change of the signer committee membership.
+func (c *Client) AbortSignerCommitteeChange(ctx context.Context, changeID string) error {
+	return c.do(ctx, http.MethodDelete, routeWithParameter(api.RouteSignerCommitteeChange, api.ParameterChangeID, changeID), nil, nil)
+}
+
+// ReceiptProof returns the inclusion proof of the receipt contained in the confirmed milestone with the given index.
+func (c *Client) ReceiptProof(ctx context.Context, milestoneIndex uint32) (*api.ReceiptProofResponse, error) {
+	res := &api.ReceiptProofResponse{}
+	if err := c.do(ctx, http.MethodGet, routeWithParameter(api.RouteReceiptProof, api.ParameterMilestoneIndex, strconv.FormatUint(uint64(milestoneIndex), 10)), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ReceiptEntries returns at most limit entries of the receipt contained in the confirmed milestone with the given index,
+// starting at the given offset, together with the totals of the receipt.
+// Only the given fields of the entries are returned, all fields if none are given.
+func (c *Client) ReceiptEntries(ctx context.Context, milestoneIndex uint32, offset int, limit int, fields ...string) (*api.ReceiptEntriesResponse, error) {
+	query := url.Values{}
+	query.Set(api.QueryParameterOffset, strconv.Itoa(offset))
+	if limit > 0 {
+		query.Set(api.QueryParameterLimit, strconv.Itoa(limit))
+	}
+	if len(fields) > 0 {
+		query.Set(api.QueryParameterFields, strings.Join(fields, ","))
+	}
+
+	res := &api.ReceiptEntriesResponse{}
+	if err := c.do(ctx, http.MethodGet, routeWithParameter(api.RouteReceiptEntries, api.ParameterMilestoneIndex, strconv.FormatUint(uint64(milestoneIndex), 10))+"?"+query.Encode(), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// PinnedParents returns the blocks that are pinned as parents of the next milestone.
+func (c *Client) PinnedParents(ctx context.Context) (*api.PinnedParentsResponse, error) {
+	res := &api.PinnedParentsResponse{}
+	if err := c.do(ctx, http.MethodGet, api.RoutePinnedParents, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// PinParents pins the given blocks as parents of the next milestone and returns all pinned blocks.
+// The blocks need to be solid and are unpinned after a milestone referencing them was issued.
+func (c *Client) PinParents(ctx context.Context, blockIDs []string) (*api.PinnedParentsResponse, error) {
+	res := &api.PinnedParentsResponse{}
+	if err := c.do(ctx, http.MethodPost, api.RoutePinnedParents, &api.PinnedParentsRequest{BlockIDs: blockIDs}, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UnpinParent unpins a block that was pinned as a parent of the next milestone.
+func (c *Client) UnpinParent(ctx context.Context, blockID string) error {
+	return c.do(ctx, http.MethodDelete, routeWithParameter(api.RoutePinnedParent, api.ParameterBlockID, blockID), nil, nil)
+}
+
+// MigrationSummary returns the signed summary of the completed migration.
+func (c *Client) MigrationSummary(ctx context.Context) (*api.MigrationSummaryResponse, error) {
+	res := &api.MigrationSummaryResponse{}
+	if err := c.do(ctx, http.MethodGet, api.RouteMigrationSummary, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ExportMigratorState returns the archive of the persisted migrator state.
+func (c *Client) ExportMigratorState(ctx context.Context) (json.RawMessage, error) {
+	var res json.RawMessage
+	if err := c.do(ctx, http.MethodGet, api.RouteMigratorStateExport, nil, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ImportMigratorState imports an archive returned by ExportMigratorState.
+// The coordinator must run with the migrator plugin disabled and without an existing migrator state.
+func (c *Client) ImportMigratorState(ctx context.Context, archive json.RawMessage) (*api.MigratorStateImportResponse, error) {
+	res := &api.MigratorStateImportResponse{}
+	if err := c.do(ctx, http.MethodPost, api.RouteMigratorStateImport, archive, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// MigratorIndexJump returns the jump of the migrated at index that awaits confirmation.
+func (c *Client) MigratorIndexJump(ctx context.Context) (*api.MigratorIndexJump, error) {
+	res := &api.MigratorIndexJump{}
+	if err := c.do(ctx, http.MethodGet, api.RouteMigratorIndexJump, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ConfirmMigratorIndexJump confirms the jump of the migrated at index to the given legacy milestone index.
+func (c *Client) ConfirmMigratorIndexJump(ctx context.Context, toIndex uint32) error {
+	return c.do(ctx, http.MethodPost, api.RouteMigratorIndexJumpConfirm, &api.MigratorIndexJumpConfirmRequest{ToIndex: toIndex}, nil)
+}
+
+// MigratorHeldMigrations returns the migrations to flagged addresses that are held for review.
+func (c *Client) MigratorHeldMigrations(ctx context.Context) (*api.MigratorHeldMigrationsResponse, error) {
+	res := &api.MigratorHeldMigrationsResponse{}
+	if err := c.do(ctx, http.MethodGet, api.RouteMigratorHeldMigrations, nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ReleaseMigratorHeldMigration releases the held migration with the given hex encoded tail transaction hash after it was reviewed.
+func (c *Client) ReleaseMigratorHeldMigration(ctx context.Context, tailTransactionHash string) error {
+	return c.do(ctx, http.MethodPost, routeWithParameter(api.RouteMigratorHeldMigrationRelease, api.ParameterTailTransactionHash, tailTransactionHash), nil, nil)
+}
+
+// EventJournal returns at most limit recorded events, starting at the given sequence number.
+// Listeners that were offline can use it to catch up on the events they missed.
+func (c *Client) EventJournal(ctx context.Context, fromSequence uint64, limit int) (*api.EventJournalResponse, error) {
+	query := url.Values{}
+	query.Set(api.QueryParameterFrom, strconv.FormatUint(fromSequence, 10))
+	if limit > 0 {
+		query.Set(api.QueryParameterLimit, strconv.Itoa(limit))
+	}
+
+	res := &api.EventJournalResponse{}
+	if err := c.do(ctx, http.MethodGet, api.RouteEventJournal+"?"+query.Encode(), nil, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// routeWithParameter replaces the parameter placeholder in the route with the escaped value.
+func routeWithParameter(route string, parameter string, value string) string {
+	return strings.Replace(route, ":"+parameter, url.PathEscape(value), 1)
+}
+
+// idempotent returns whether sending a request with the given method several times has the same effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// do executes the request and retries it on network errors and server errors if it is idempotent.
+// A request that changes the state, e.g. a POST, is not retried, since the failed attempt could have been applied.
+func (c *Client) do(ctx context.Context, method string, route string, reqObj interface{}, resObj interface{}) error {
+
+	var reqBody []byte
+	if reqObj != nil {
+		var err error
+		if reqBody, err = json.Marshal(reqObj); err != nil {
+			return fmt.Errorf("unable to encode request: %w", err)
+		}
+	}
+
+	retryAmount := c.retryAmount
+	if !idempotent(method) {
+		retryAmount = 0
+	}
+
+	var err error
+	for i := 0; i <= retryAmount; i++ {
+		if i > 0 {
+			if !timeutil.Sleep(ctx, c.retryTimeout) {
+				return ctx.Err()
+			}
+		}
+
+		var retry bool
+		if retry, err = c.doOnce(ctx, method, route, reqBody, resObj); err == nil || !retry {
+			return err
+		}
+	}
+
+	return err
+}
+
+// doOnce executes the request once and returns whether it makes sense to retry the request on failure.
+func (c *Client) doOnce(ctx context.Context, method string, route string, reqBody []byte, resObj interface{}) (bool, error) {
+
+	var body io.Reader
+	if reqBody != nil {
+		body = bytes.NewReader(reqBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+route, body)
+	if err != nil {
+		return false, err
+	}
+
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	if c.apiToken != "" {
+		if c.apiTokenHeader == "" {
+			req.Header.Set("Authorization", "Bearer "+c.apiToken)
+		} else {
+			req.Header.Set(c.apiTokenHeader, c.apiToken)
+		}
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		// network errors are worth a retry, unless the context is done
+		return ctx.Err() == nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return true, err
+	}
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		httpErr := &HTTPError{StatusCode: res.StatusCode, Message: string(resBody)}
+
+		errRes := &api.ErrorResponse{}
+		if err := json.Unmarshal(resBody, errRes); err == nil && errRes.Error.Message != "" {
+			httpErr.Message = errRes.Error.Message
+		}
+
+		// only server side errors are worth a retry
+		return res.StatusCode >= http.StatusInternalServerError, httpErr
+	}
+
+	if resObj == nil || len(resBody) == 0 {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(resBody, resObj); err != nil {
+		return false, fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,109 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/client"
+)
+
+func TestStatusRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, api.RouteStatus, r.URL.Path)
+
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(&api.StatusResponse{
+			Coordinator: &api.CoordinatorStatus{LatestMilestoneIndex: 42},
+		})
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL, client.WithRetryAmount(3), client.WithRetryTimeout(time.Millisecond))
+
+	status, err := c.Status(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 42, status.Coordinator.LatestMilestoneIndex)
+	require.EqualValues(t, 3, calls.Load())
+}
+
+func TestStatusDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"code":"401","message":"unauthorized"}}`))
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL, client.WithRetryAmount(3), client.WithRetryTimeout(time.Millisecond))
+
+	_, err := c.Status(context.Background())
+	require.ErrorIs(t, err, client.ErrHTTPRequestFailed)
+
+	var httpErr *client.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+	require.Equal(t, "unauthorized", httpErr.Message)
+	require.EqualValues(t, 1, calls.Load())
+}
+
+func TestStateChangesAreNotRetried(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL, client.WithRetryAmount(3), client.WithRetryTimeout(time.Millisecond))
+
+	// the failed attempt could have been applied, so it must not be sent again
+	_, err := c.PinParents(context.Background(), []string{"0x01"})
+	require.ErrorIs(t, err, client.ErrHTTPRequestFailed)
+	require.EqualValues(t, 1, calls.Load())
+
+	require.ErrorIs(t, c.ConfirmMigratorIndexJump(context.Background(), 42), client.ErrHTTPRequestFailed)
+	require.EqualValues(t, 2, calls.Load())
+}
+
+func TestReceiptEntriesQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/receipts/7/entries", r.URL.Path)
+		require.Equal(t, "20", r.URL.Query().Get(api.QueryParameterOffset))
+		require.Equal(t, "10", r.URL.Query().Get(api.QueryParameterLimit))
+		require.Equal(t, "address,deposit", r.URL.Query().Get(api.QueryParameterFields))
+
+		_ = json.NewEncoder(w).Encode(&api.ReceiptEntriesResponse{
+			MilestoneIndex: 7,
+			EntriesCount:   128,
+			Value:          1_000_000,
+			Offset:         20,
+		})
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL)
+
+	entries, err := c.ReceiptEntries(context.Background(), 7, 20, 10, api.ReceiptEntryFieldAddress, api.ReceiptEntryFieldDeposit)
+	require.NoError(t, err)
+	require.Equal(t, 128, entries.EntriesCount)
+	require.EqualValues(t, 1_000_000, entries.Value)
+}
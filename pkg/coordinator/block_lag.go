@@ -0,0 +1,57 @@
+package coordinator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrNodeLagging is returned when the latest solid block of the node is older than the allowed block lag.
+	ErrNodeLagging = errors.New("node is lagging behind")
+)
+
+// OnBlockSolid needs to be called for every block that became solid in the node.
+// It is used to check whether the node is lagging behind before a milestone is issued.
+func (coo *Coordinator) OnBlockSolid() {
+	coo.latestSolidBlockTime.Store(time.Now().UnixNano())
+}
+
+// checkBlockLag checks that the node is synced and that it received new solid blocks recently,
+// otherwise the milestone would confirm a stale cone.
+// Returns non-critical errors.
+func (coo *Coordinator) checkBlockLag(index iotago.MilestoneIndex) error {
+	if coo.maxBlockLag == 0 {
+		return nil
+	}
+
+	var err error
+	switch {
+	case !coo.isNodeSynced():
+		err = common.ErrNodeNotSynced
+
+	default:
+		latestSolidBlockTime := coo.latestSolidBlockTime.Load()
+		if latestSolidBlockTime == 0 {
+			err = fmt.Errorf("%w: no solid blocks received yet", ErrNodeLagging)
+
+			break
+		}
+
+		if blockLag := time.Since(time.Unix(0, latestSolidBlockTime)); blockLag > coo.maxBlockLag {
+			err = fmt.Errorf("%w: latest solid block was received %v ago (max %v)", ErrNodeLagging, blockLag.Truncate(time.Millisecond), coo.maxBlockLag)
+		}
+	}
+
+	if err != nil {
+		coo.Events.MilestoneSkipped.Trigger(index, err)
+
+		return common.SoftError(fmt.Errorf("skipped milestone %d: %w", index, err))
+	}
+
+	return nil
+}
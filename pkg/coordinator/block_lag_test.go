@@ -0,0 +1,78 @@
+package coordinator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestBlockLagSkipsMilestones(t *testing.T) {
+	nodeSynced := true
+	coo, milestoneBlockID := (&testCoordinatorDeps{nodeSynced: func() bool { return nodeSynced }}).newCoordinator(t, nil, coordinator.WithMaxBlockLag(500*time.Millisecond))
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	var skipped []error
+	coo.Events.MilestoneSkipped.Hook(events.NewClosure(func(index iotago.MilestoneIndex, err error) {
+		require.Equal(t, latestMilestoneIndex+1, index)
+		skipped = append(skipped, err)
+	}))
+
+	// no solid blocks were received yet
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.ErrorIs(t, err, coordinator.ErrNodeLagging)
+	require.NotNil(t, common.IsSoftError(err))
+	require.Nil(t, common.IsCriticalError(err))
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+
+	// issuance resumes once the node received new solid blocks
+	coo.OnBlockSolid()
+	milestoneBlockID, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	require.Equal(t, latestMilestoneIndex+1, coo.State().LatestMilestoneIndex)
+	latestMilestoneIndex++
+
+	// the latest solid block is older than the allowed block lag
+	time.Sleep(600 * time.Millisecond)
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.ErrorIs(t, err, coordinator.ErrNodeLagging)
+	require.NotNil(t, common.IsSoftError(err))
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+
+	// the node is not synced, even though it received new solid blocks
+	coo.OnBlockSolid()
+	nodeSynced = false
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.ErrorIs(t, err, common.ErrNodeNotSynced)
+	require.NotNil(t, common.IsSoftError(err))
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+
+	// issuance resumes with the skipped milestone index once the node caught up
+	nodeSynced = true
+	coo.OnBlockSolid()
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	require.Equal(t, latestMilestoneIndex+1, coo.State().LatestMilestoneIndex)
+
+	require.Len(t, skipped, 3)
+	require.ErrorIs(t, skipped[0], coordinator.ErrNodeLagging)
+	require.ErrorIs(t, skipped[1], coordinator.ErrNodeLagging)
+	require.ErrorIs(t, skipped[2], common.ErrNodeNotSynced)
+}
+
+func TestBlockLagDisabled(t *testing.T) {
+	nodeSynced := false
+	coo, milestoneBlockID := (&testCoordinatorDeps{nodeSynced: func() bool { return nodeSynced }}).newCoordinator(t, nil, coordinator.WithMaxBlockLag(0))
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	// without a max block lag, neither the solid blocks nor the sync state of the node are checked
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	require.Equal(t, latestMilestoneIndex+1, coo.State().LatestMilestoneIndex)
+}
@@ -0,0 +1,138 @@
+package coordinator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	defaultMaxClockDrift = 5 * time.Second
+)
+
+var (
+	// ErrClockJumpedBackwards is returned when the system clock is behind the timestamp of the latest milestone by more than the allowed drift.
+	ErrClockJumpedBackwards = errors.New("system clock jumped backwards")
+)
+
+// ClockDrift describes a system clock that is behind the timestamp of the latest milestone,
+// e.g. because it was adjusted backwards by NTP or an operator.
+type ClockDrift struct {
+	// the index of the milestone that is about to be issued.
+	Index iotago.MilestoneIndex
+	// the time of the latest milestone.
+	LatestMilestoneTime time.Time
+	// the current time of the system clock.
+	Now time.Time
+	// the duration the system clock is behind the latest milestone.
+	Drift time.Duration
+	// the duration the issuance of the milestone is delayed, zero if the milestone is skipped.
+	Delay time.Duration
+}
+
+func (d *ClockDrift) String() string {
+	if d.Delay == 0 {
+		return fmt.Sprintf("clock is %v behind the latest milestone, milestone %d skipped", d.Drift.Truncate(time.Millisecond), d.Index)
+	}
+
+	return fmt.Sprintf("clock is %v behind the latest milestone, milestone %d delayed by %v", d.Drift.Truncate(time.Millisecond), d.Index, d.Delay.Truncate(time.Millisecond))
+}
+
+// ClockDriftCaller is used to signal a system clock that is behind the latest milestone.
+func ClockDriftCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(drift *ClockDrift))(params[0].(*ClockDrift))
+}
+
+// WithMaxClockDrift defines the maximum duration the issuance of a milestone is delayed until its timestamp
+// is newer than the timestamp of the latest milestone. If the system clock is further behind,
+// the milestone is skipped until the clock caught up (0 = milestones are never delayed).
+func WithMaxClockDrift(maxClockDrift time.Duration) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.maxClockDrift = maxClockDrift
+	}
+}
+
+// WithClock defines the clock that is used to get the timestamps of the milestones.
+// A replayed coordinator uses the recorded timestamps instead of the system clock.
+func WithClock(clock func() time.Time) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.clock = clock
+	}
+}
+
+// MilestoneTimestampDelay returns the duration until the system clock reaches a milestone timestamp
+// that is strictly greater than the timestamp of the latest milestone.
+// Milestone timestamps have a resolution of one second.
+func MilestoneTimestampDelay(latestMilestoneTime time.Time, now time.Time) time.Duration {
+	if latestMilestoneTime.IsZero() {
+		return 0
+	}
+
+	next := time.Unix(latestMilestoneTime.Unix()+1, 0)
+	if !now.Before(next) {
+		return 0
+	}
+
+	return next.Sub(now)
+}
+
+// newMilestoneTimestamp returns the timestamp for the new milestone, which is strictly greater than the timestamp of the latest milestone.
+// If the system clock did not reach such a timestamp yet, the issuance is delayed by at most the maximum clock drift.
+// If the clock jumped further backwards, an alert is raised and the milestone is skipped instead of producing an invalid timestamp.
+// Returns non-critical errors.
+func (coo *Coordinator) newMilestoneTimestamp(index iotago.MilestoneIndex) (time.Time, error) {
+	now := coo.clock()
+
+	delay := MilestoneTimestampDelay(coo.state.LatestMilestoneTime, now)
+	if delay == 0 {
+		return now, nil
+	}
+
+	if coo.debugFakeMilestoneTimestamps {
+		// if the debug mode is enabled, we fake the timestamps so that the L1 protocol rules checks still pass,
+		// but we are able to issue milestones faster than 1s interval.
+		return coo.state.LatestMilestoneTime.Add(time.Second), nil
+	}
+
+	if delay > coo.maxClockDrift {
+		if now.Before(coo.state.LatestMilestoneTime) {
+			coo.Events.ClockDriftDetected.Trigger(&ClockDrift{
+				Index:               index,
+				LatestMilestoneTime: coo.state.LatestMilestoneTime,
+				Now:                 now,
+				Drift:               coo.state.LatestMilestoneTime.Sub(now),
+			})
+
+			return time.Time{}, common.SoftError(fmt.Errorf("%w: %v behind the latest milestone", ErrClockJumpedBackwards, coo.state.LatestMilestoneTime.Sub(now).Truncate(time.Millisecond)))
+		}
+
+		return time.Time{}, common.SoftError(ErrMilestoneTimestampDidNotIncrease)
+	}
+
+	// the clock is only slightly behind, so the milestone is issued as soon as its timestamp increased.
+	// a clock that is behind the latest milestone still needs to be investigated.
+	if now.Before(coo.state.LatestMilestoneTime) {
+		coo.Events.ClockDriftDetected.Trigger(&ClockDrift{
+			Index:               index,
+			LatestMilestoneTime: coo.state.LatestMilestoneTime,
+			Now:                 now,
+			Drift:               coo.state.LatestMilestoneTime.Sub(now),
+			Delay:               delay,
+		})
+	}
+	time.Sleep(delay)
+
+	now = time.Now()
+	if MilestoneTimestampDelay(coo.state.LatestMilestoneTime, now) > 0 {
+		// the clock jumped backwards again while waiting
+		return time.Time{}, common.SoftError(ErrMilestoneTimestampDidNotIncrease)
+	}
+
+	return now, nil
+}
@@ -0,0 +1,27 @@
+package coordinator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func TestMilestoneTimestampDelay(t *testing.T) {
+	latestMilestoneTime := time.Unix(1_000, 400_000_000)
+
+	// no milestone was issued yet
+	require.Zero(t, coordinator.MilestoneTimestampDelay(time.Time{}, latestMilestoneTime))
+
+	// the next full second is a valid timestamp
+	require.Zero(t, coordinator.MilestoneTimestampDelay(latestMilestoneTime, time.Unix(1_001, 0)))
+	require.Zero(t, coordinator.MilestoneTimestampDelay(latestMilestoneTime, time.Unix(1_005, 0)))
+
+	// the timestamp would be the same as the latest one
+	require.Equal(t, 100*time.Millisecond, coordinator.MilestoneTimestampDelay(latestMilestoneTime, time.Unix(1_000, 900_000_000)))
+
+	// the clock jumped backwards
+	require.Equal(t, 11*time.Second, coordinator.MilestoneTimestampDelay(latestMilestoneTime, time.Unix(990, 0)))
+}
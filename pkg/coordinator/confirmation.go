@@ -0,0 +1,147 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/timeutil"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// DefaultMilestoneSendAttempts is the default amount of times an issued milestone is sent until it was confirmed.
+	DefaultMilestoneSendAttempts = 10
+	// DefaultMilestoneResendBackoff is the default initial delay before an unconfirmed milestone is sent again.
+	DefaultMilestoneResendBackoff = 5 * time.Second
+)
+
+var (
+	// ErrMilestoneNotConfirmed is returned when an issued milestone was not confirmed in time.
+	ErrMilestoneNotConfirmed = errors.New("milestone was not confirmed in time")
+	// ErrConfirmedMilestoneMismatch is returned when the node confirmed another milestone than the one issued by the coordinator.
+	ErrConfirmedMilestoneMismatch = errors.New("confirmed milestone does not match the issued milestone")
+)
+
+// MilestoneConfirmationFailure contains the information about an issued milestone
+// that was not confirmed in time or that was replaced by another milestone.
+type MilestoneConfirmationFailure struct {
+	// the index of the issued milestone.
+	Index iotago.MilestoneIndex
+	// the ID of the issued milestone.
+	MilestoneID iotago.MilestoneID
+	// the ID of the milestone the node confirmed instead, nil if the milestone was not confirmed in time.
+	ConfirmedMilestoneID *iotago.MilestoneID
+	// whether the issued milestone contains a receipt.
+	HasReceipt bool
+}
+
+// trackIssuedMilestone remembers the ID of the milestone that is about to be sent,
+// so that it can be compared to the milestone the node confirms at that index.
+func (coo *Coordinator) trackIssuedMilestone(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, hasReceipt bool) {
+	coo.issuedMilestonesLock.Lock()
+	defer coo.issuedMilestonesLock.Unlock()
+
+	coo.issuedMilestones[index] = &MilestoneConfirmationFailure{
+		Index:       index,
+		MilestoneID: milestoneID,
+		HasReceipt:  hasReceipt,
+	}
+}
+
+// OnConfirmedMilestone needs to be called for every milestone the node confirmed.
+// It checks whether the confirmed milestone matches the milestone the coordinator issued at that index.
+// Returns critical errors.
+func (coo *Coordinator) OnConfirmedMilestone(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID) error {
+	coo.issuedMilestonesLock.Lock()
+	defer coo.issuedMilestonesLock.Unlock()
+
+	issued, exists := coo.issuedMilestones[index]
+	if !exists {
+		// the milestone was not issued by this coordinator instance (e.g. before a restart)
+		return nil
+	}
+
+	// all older entries can be dropped, the node confirms milestones in order
+	for trackedIndex := range coo.issuedMilestones {
+		if trackedIndex <= index {
+			delete(coo.issuedMilestones, trackedIndex)
+		}
+	}
+
+	if issued.MilestoneID == milestoneID {
+		return nil
+	}
+
+	issued.ConfirmedMilestoneID = &milestoneID
+	coo.Events.MilestoneConfirmationFailed.Trigger(issued)
+
+	return common.CriticalError(fmt.Errorf("%w: index %d, issued: %s, confirmed: %s", ErrConfirmedMilestoneMismatch, index, iotago.EncodeHex(issued.MilestoneID[:]), iotago.EncodeHex(milestoneID[:])))
+}
+
+// sendMilestone sends the milestone block to the network and waits until it was confirmed.
+// If the milestone is not confirmed in time, an alert is raised and the same milestone is sent again after a backoff,
+// that doubles with every attempt up to the confirmation timeout. Once the milestone was sent the maximum amount of times
+// or the given context is done, sendMilestone gives up with an error, which the caller turns into a critical error that stops the coordinator.
+//
+// The signed milestone is never reissued with other parents. The node could still confirm the old milestone,
+// so reissuing it would create two conflicting milestones with the same index, which the network can't resolve.
+func (coo *Coordinator) sendMilestone(ctx context.Context, block *iotago.Block, index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, hasReceipt bool) (iotago.BlockID, error) {
+
+	coo.trackIssuedMilestone(index, milestoneID, hasReceipt)
+
+	backoff := coo.milestoneResendBackoff
+	for attempt := 1; ; attempt++ {
+		blockID, err := coo.sendBlockFunc(block, index)
+		if err == nil || !errors.Is(err, ErrMilestoneNotConfirmed) {
+			return blockID, err
+		}
+
+		// the confirmation could have been missed, e.g. if it happened before the node was asked for it
+		if coo.confirmedMilestoneIndexFunc != nil && coo.confirmedMilestoneIndexFunc() >= index {
+			return blockID, nil
+		}
+
+		coo.Events.MilestoneConfirmationFailed.Trigger(&MilestoneConfirmationFailure{
+			Index:       index,
+			MilestoneID: milestoneID,
+			HasReceipt:  hasReceipt,
+		})
+
+		if attempt >= coo.milestoneSendAttempts {
+			return blockID, fmt.Errorf("%w: milestone %d was sent %d times", err, index, attempt)
+		}
+
+		// sending the same block again is harmless and helps if the node dropped it
+		coo.LogWarnf("milestone %d was not confirmed in time, sending it again in %v", index, backoff.Truncate(time.Millisecond))
+		if !timeutil.Sleep(ctx, backoff) {
+			return blockID, fmt.Errorf("%w: milestone %d was not sent again: %v", ErrMilestoneNotConfirmed, index, ctx.Err())
+		}
+
+		if backoff *= 2; backoff > coo.ConfirmationTimeout() {
+			backoff = coo.ConfirmationTimeout()
+		}
+		// the backoff never drops below its initial value, e.g. if the confirmation timeout is shorter
+		if backoff < coo.milestoneResendBackoff {
+			backoff = coo.milestoneResendBackoff
+		}
+	}
+}
+
+// PendingReceipts returns the amount of issued milestones containing a receipt that were not confirmed yet.
+func (coo *Coordinator) PendingReceipts() int {
+	coo.issuedMilestonesLock.Lock()
+	defer coo.issuedMilestonesLock.Unlock()
+
+	var pending int
+	for _, issued := range coo.issuedMilestones {
+		if issued.HasReceipt {
+			pending++
+		}
+	}
+
+	return pending
+}
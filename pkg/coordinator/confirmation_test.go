@@ -0,0 +1,195 @@
+package coordinator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// confirmationNode is a node whose confirmations of milestones are controlled by the test.
+type confirmationNode struct {
+	// the amount of times a sent milestone is not confirmed in time.
+	notConfirmed int
+	// the index of the latest milestone the node confirmed.
+	confirmedIndex iotago.MilestoneIndex
+	// whether the node confirms a milestone right after the confirmation timed out.
+	confirmLate bool
+	// the milestones that were sent to the node.
+	sentMilestones []*iotago.Milestone
+}
+
+func (n *confirmationNode) sendBlock(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+	blockID := iotago.BlockID{byte(len(n.sentMilestones) + 1)}
+	if len(msIndex) == 0 {
+		return blockID, nil
+	}
+	n.sentMilestones = append(n.sentMilestones, block.Payload.(*iotago.Milestone))
+
+	if n.notConfirmed > 0 {
+		n.notConfirmed--
+		if n.confirmLate {
+			n.confirmedIndex = msIndex[0]
+		}
+
+		return blockID, coordinator.ErrMilestoneNotConfirmed
+	}
+	n.confirmedIndex = msIndex[0]
+
+	return blockID, nil
+}
+
+func TestSendMilestoneResendsUnconfirmed(t *testing.T) {
+	node := &confirmationNode{}
+	coo, milestoneBlockID := newTestCoordinator(t, node.sendBlock,
+		coordinator.WithConfirmationCheck(1, func() iotago.MilestoneIndex { return node.confirmedIndex }),
+		coordinator.WithMilestoneResend(coordinator.DefaultMilestoneSendAttempts, time.Millisecond),
+	)
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	var failures []*coordinator.MilestoneConfirmationFailure
+	coo.Events.MilestoneConfirmationFailed.Hook(events.NewClosure(func(failure *coordinator.MilestoneConfirmationFailure) {
+		failures = append(failures, failure)
+	}))
+
+	node.notConfirmed = 2
+	node.sentMilestones = nil
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	require.Equal(t, latestMilestoneIndex+1, coo.State().LatestMilestoneIndex)
+
+	// every unconfirmed send raises an alert and the same milestone is sent again
+	require.Len(t, failures, 2)
+	require.Len(t, node.sentMilestones, 3)
+	for _, failure := range failures {
+		require.Equal(t, latestMilestoneIndex+1, failure.Index)
+		require.Equal(t, coo.State().LatestMilestoneID, failure.MilestoneID)
+		require.Nil(t, failure.ConfirmedMilestoneID)
+	}
+	for _, milestone := range node.sentMilestones {
+		milestoneID, err := milestone.ID()
+		require.NoError(t, err)
+		require.Equal(t, coo.State().LatestMilestoneID, milestoneID)
+	}
+}
+
+func TestSendMilestoneGivesUp(t *testing.T) {
+	node := &confirmationNode{}
+	coo, milestoneBlockID := newTestCoordinator(t, node.sendBlock,
+		coordinator.WithConfirmationCheck(1, func() iotago.MilestoneIndex { return node.confirmedIndex }),
+		coordinator.WithMilestoneResend(3, time.Millisecond),
+	)
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	// the milestone is never confirmed, so it is only sent the maximum amount of times and the coordinator stops
+	node.notConfirmed = 5
+	node.sentMilestones = nil
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.ErrorIs(t, err, coordinator.ErrMilestoneNotConfirmed)
+	require.NotNil(t, common.IsCriticalError(err))
+	require.Nil(t, common.IsSoftError(err))
+	var submissionErr *coordinator.SubmissionError
+	require.ErrorAs(t, err, &submissionErr)
+	require.Equal(t, coordinator.StageSending, submissionErr.Stage)
+	require.Equal(t, latestMilestoneIndex+1, submissionErr.Index)
+	require.Len(t, node.sentMilestones, 3)
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+}
+
+func TestSendMilestoneStopsOnDoneContext(t *testing.T) {
+	node := &confirmationNode{}
+	coo, milestoneBlockID := newTestCoordinator(t, node.sendBlock,
+		coordinator.WithConfirmationCheck(1, func() iotago.MilestoneIndex { return node.confirmedIndex }),
+		coordinator.WithMilestoneResend(coordinator.DefaultMilestoneSendAttempts, time.Hour),
+	)
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	var failures int
+	coo.Events.MilestoneConfirmationFailed.Hook(events.NewClosure(func(_ *coordinator.MilestoneConfirmationFailure) {
+		failures++
+	}))
+
+	// the coordinator is shutting down, so the milestone is not sent again after the backoff
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	node.notConfirmed = 5
+	node.sentMilestones = nil
+	_, err := coo.IssueMilestone(ctx, iotago.BlockIDs{milestoneBlockID})
+	require.ErrorIs(t, err, coordinator.ErrMilestoneNotConfirmed)
+	require.NotNil(t, common.IsCriticalError(err))
+	require.Len(t, node.sentMilestones, 1)
+	require.Equal(t, 1, failures)
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+}
+
+func TestSendMilestoneNeverReissues(t *testing.T) {
+	node := &confirmationNode{}
+	coo, milestoneBlockID := newTestCoordinator(t, node.sendBlock, coordinator.WithConfirmationCheck(1, func() iotago.MilestoneIndex { return node.confirmedIndex }))
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	var failures int
+	coo.Events.MilestoneConfirmationFailed.Hook(events.NewClosure(func(_ *coordinator.MilestoneConfirmationFailure) {
+		failures++
+	}))
+
+	// the confirmation was missed, but the node confirmed the milestone, so it is neither sent again nor reissued
+	node.notConfirmed = 1
+	node.confirmLate = true
+	node.sentMilestones = nil
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	require.Equal(t, latestMilestoneIndex+1, coo.State().LatestMilestoneIndex)
+	require.Zero(t, failures)
+	require.Len(t, node.sentMilestones, 1)
+
+	// the next milestone references the confirmed one
+	latestMilestoneBlockID := coo.State().LatestMilestoneBlockID
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{latestMilestoneBlockID})
+	require.NoError(t, err)
+	require.Len(t, node.sentMilestones, 2)
+	require.Equal(t, latestMilestoneIndex+2, node.sentMilestones[1].Index)
+}
+
+func TestOnConfirmedMilestone(t *testing.T) {
+	node := &confirmationNode{}
+	coo, milestoneBlockID := newTestCoordinator(t, node.sendBlock, coordinator.WithConfirmationCheck(1, func() iotago.MilestoneIndex { return node.confirmedIndex }))
+
+	var failures []*coordinator.MilestoneConfirmationFailure
+	coo.Events.MilestoneConfirmationFailed.Hook(events.NewClosure(func(failure *coordinator.MilestoneConfirmationFailure) {
+		failures = append(failures, failure)
+	}))
+
+	// milestones that were not issued by this instance are ignored
+	require.NoError(t, coo.OnConfirmedMilestone(100, iotago.MilestoneID{1}))
+
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	index := coo.State().LatestMilestoneIndex
+	require.NoError(t, coo.OnConfirmedMilestone(index, coo.State().LatestMilestoneID))
+	require.Empty(t, failures)
+
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{coo.State().LatestMilestoneBlockID})
+	require.NoError(t, err)
+	index = coo.State().LatestMilestoneIndex
+	issuedMilestoneID := coo.State().LatestMilestoneID
+
+	// the node confirmed another milestone at that index
+	confirmedMilestoneID := iotago.MilestoneID{42}
+	err = coo.OnConfirmedMilestone(index, confirmedMilestoneID)
+	require.ErrorIs(t, err, coordinator.ErrConfirmedMilestoneMismatch)
+	require.NotNil(t, common.IsCriticalError(err))
+	require.Len(t, failures, 1)
+	require.Equal(t, index, failures[0].Index)
+	require.Equal(t, issuedMilestoneID, failures[0].MilestoneID)
+	require.Equal(t, &confirmedMilestoneID, failures[0].ConfirmedMilestoneID)
+
+	// the issued milestone is only checked once
+	require.NoError(t, coo.OnConfirmedMilestone(index, confirmedMilestoneID))
+	require.Len(t, failures, 1)
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 	"os"
 	"path"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -19,6 +20,7 @@ import (
 	"github.com/iotaledger/hive.go/serializer/v2"
 	"github.com/iotaledger/hornet/v2/pkg/common"
 	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
 	iotago "github.com/iotaledger/iota.go/v3"
 
 	// import implementation.
@@ -71,11 +73,49 @@ type Events struct {
 	QuorumFinished *events.Event
 	// MilestoneTimeout is triggered if no new milestones are received for some time.
 	MilestoneTimeout *events.Event
+	// ReceiptSigned is triggered when a receipt was signed by the treasury key.
+	ReceiptSigned *events.Event
+	// ReceiptPending is triggered when a new receipt was created, before the milestone containing it is issued.
+	// A receipt that is issued with a later milestone, e.g. because it was deferred, is not signaled again.
+	ReceiptPending *events.Event
+	// ReceiptIssued is triggered when a milestone containing a receipt was issued.
+	ReceiptIssued *events.Event
+	// MilestoneConfirmationFailed is triggered when an issued milestone was not confirmed in time
+	// or the node confirmed another milestone at that index.
+	MilestoneConfirmationFailed *events.Event
+	// MilestoneSkipped is triggered when a milestone was not issued because the node is not synced or lagging behind.
+	MilestoneSkipped *events.Event
+	// MilestoneGapDetected is triggered when milestone intervals were missed, e.g. because the coordinator was down.
+	MilestoneGapDetected *events.Event
+	// ClockDriftDetected is triggered when the system clock is behind the timestamp of the latest milestone.
+	ClockDriftDetected *events.Event
+	// PoWAttempt is triggered after an attempt to compute the nonce of a checkpoint block.
+	PoWAttempt *events.Event
+	// MilestoneTimings is triggered after a milestone issuance with the durations of its stages.
+	MilestoneTimings *events.Event
+	// MigrationCompleted is triggered with the signed summary after the final receipt of the last legacy milestone was issued.
+	MigrationCompleted *events.Event
+	// SigningSelfTestCompleted is triggered with the result of a signing self-test.
+	SigningSelfTestCompleted *events.Event
+	// QuorumNodeDemoted is triggered when a consistently disagreeing or slow node was demoted from the active quorum set.
+	QuorumNodeDemoted *events.Event
+	// ReceiptDeferred is triggered when a receipt is deferred to the next milestone,
+	// because it doesn't fit into the milestone alongside the additional milestone options.
+	ReceiptDeferred *events.Event
+	// ReceiptsPaused is triggered when receipts are paused because the node reports that it is unhealthy or busy pruning.
+	ReceiptsPaused *events.Event
+	// ReceiptsResumed is triggered with the finished pause when receipts are issued again.
+	ReceiptsResumed *events.Event
+	// Decision is triggered after every bootstrap, checkpoint or milestone issuance with its inputs and its outcome.
+	Decision *events.Event
 }
 
 // IsNodeSyncedFunc should only return true if the node connected to the coordinator is synced.
 type IsNodeSyncedFunc = func() bool
 
+// ConfirmedMilestoneIndexFunc should return the index of the latest milestone the node connected to the coordinator confirmed.
+type ConfirmedMilestoneIndexFunc = func() iotago.MilestoneIndex
+
 // ProtocolParameteresFunc should return the current valid protocol parameters.
 type ProtocolParameteresFunc = func() *iotago.ProtocolParameters
 
@@ -107,6 +147,10 @@ type Coordinator struct {
 	treasuryOutputFunc UnspentTreasuryOutputFunc
 	// used to sign the milestones.
 	signerProvider MilestoneSignerProvider
+	// the optional signer used to additionally sign receipts with a separate treasury key.
+	treasurySigner TreasurySigner
+	// used to check the treasury output that is spent by a receipt.
+	treasuryValidation *TreasuryValidation
 	// the function used to send a block.
 	sendBlockFunc SendBlockFunc
 	// used to trigger an event if no new milestones are received for some time.
@@ -115,6 +159,8 @@ type Coordinator struct {
 	// options
 	// the path to the state file of the coordinator.
 	stateFilePath string
+	// the amount of backups of the state file that are kept.
+	stateBackups int
 	// the interval milestones are issued.
 	milestoneInterval time.Duration
 	// the duration after which an event is triggered if no new milestones are received.
@@ -125,10 +171,80 @@ type Coordinator struct {
 	signingRetryAmount int
 	// the optional quorum used by the coordinator to check for correct ledger state calculation.
 	quorum *quorum
+	// the optional scoring of the quorum nodes.
+	quorumScoring *QuorumScoring
 	// whether all blocks that are issued by the coordinator should be stored to disk before being submitted to the network.
 	blockBackupsEnabled bool
 	// the path to the folder where block backups are stored.
 	blockBackupsFolderPath string
+	// the optional metadata that is embedded into every milestone.
+	milestoneMetadata []byte
+	// the maximum amount of parents of a milestone, used to compute the space that is left for receipts.
+	milestoneParentsCount int
+	// the parameters the current receipt limits of the migrator were derived from (nil = not derived yet).
+	receiptLimitsKey *receiptLimitsKey
+	// the amount of milestone intervals an issued milestone needs to be confirmed within (0 = disabled).
+	confirmationMilestones int
+	// returns the index of the latest milestone the node confirmed, checked before an unconfirmed milestone is sent again (nil = not checked).
+	confirmedMilestoneIndexFunc ConfirmedMilestoneIndexFunc
+	// the maximum amount of times an issued milestone is sent until it was confirmed.
+	milestoneSendAttempts int
+	// the initial backoff before an unconfirmed milestone is sent again.
+	milestoneResendBackoff time.Duration
+	// the receipt that was taken from the migrator, but was not sent yet and needs to be issued with the next milestone.
+	pendingReceipt *iotago.ReceiptMilestoneOpt
+	// the optional store for the inclusion proofs of confirmed receipts.
+	receiptProofStore *ReceiptProofStore
+	// returns the additional options of the milestones (nil = no additional options).
+	milestoneOptionsFunc MilestoneOptionsFunc
+	// the last legacy milestone index of the migration (0 = disabled).
+	lastLegacyMilestoneIndex iotago.MilestoneIndex
+	// the path to the file the summary of the completed migration is written to.
+	migrationSummaryFilePath string
+	// the optional blocks that are pinned as parents of the next milestone.
+	pinnedParents *PinnedParents
+	// used to construct the milestones of the different protocol versions.
+	protocolAdapters *ProtocolAdapters
+	// used to protect the issued milestones.
+	issuedMilestonesLock syncutils.Mutex
+	// the milestones issued by the coordinator that were not confirmed yet.
+	issuedMilestones map[iotago.MilestoneIndex]*MilestoneConfirmationFailure
+	// used to protect the signer health.
+	signerHealthLock syncutils.RWMutex
+	// information about the latest signing attempts.
+	signerHealth SignerHealth
+	// the maximum age of the latest solid block of the node before milestones are skipped (0 = disabled).
+	maxBlockLag time.Duration
+	// the time the latest solid block was received (unix nanoseconds).
+	latestSolidBlockTime atomic.Int64
+	// the policy used to catch up after a gap in the issued milestones.
+	catchUpPolicy string
+	// the maximum amount of milestones issued to catch up with the gradual policy.
+	maxCatchUpMilestones int
+	// the interval the catch-up milestones are issued in with the gradual policy.
+	catchUpInterval time.Duration
+	// whether the coordinator is bootstrapped on a network reset.
+	networkReset bool
+	// used to compute the nonces of the checkpoint blocks.
+	powProvider PoWProvider
+	// the maximum duration the issuance of a milestone is delayed until its timestamp increased.
+	maxClockDrift time.Duration
+	// used to get the current time for the milestone timestamps.
+	clock func() time.Time
+	// the optional latency budget of the milestones.
+	latencyBudget *LatencyBudget
+	// the deadline of the milestone issuance (nil = disabled).
+	milestoneDeadline *MilestoneDeadline
+	// the duration of the tip selection for the next milestone.
+	tipSelectionDuration time.Duration
+	// whether the next milestone only references cached tips.
+	tipSelectionCached bool
+	// whether the tip selection exceeded its budget in the previous milestone.
+	tipSelectionExceeded bool
+	// the optional monitor that pauses the receipts while the node is unhealthy.
+	nodeHealthMonitor *NodeHealthMonitor
+	// whether milestones are skipped as well while the receipts are paused.
+	nodeHealthSkipMilestones bool
 	// whether the coordinator will fake timestamps of milestones if the interval is below 1s (use for tests only!)
 	debugFakeMilestoneTimestamps bool
 
@@ -138,6 +254,8 @@ type Coordinator struct {
 	state *State
 	// whether the coordinator was bootstrapped.
 	bootstrapped bool
+	// whether the issuance of milestones is suspended for a handoff to another coordinator process.
+	suspended bool
 	// events of the coordinator.
 	Events *Events
 }
@@ -166,6 +284,13 @@ func WithStateFilePath(stateFilePath string) options.Option[Coordinator] {
 	}
 }
 
+// WithStateBackups defines the amount of backups of the state file that are kept.
+func WithStateBackups(count int) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.stateBackups = count
+	}
+}
+
 // WithMilestoneInterval defines interval milestones are issued.
 func WithMilestoneInterval(milestoneInterval time.Duration) options.Option[Coordinator] {
 	return func(c *Coordinator) {
@@ -207,6 +332,14 @@ func WithQuorum(quorumEnabled bool, quorumGroups map[string][]*QuorumClientConfi
 	}
 }
 
+// WithTreasurySigner defines a signer that additionally signs every receipt with a separate treasury key.
+// If no signer is given, receipts are only authorized by the milestone signatures.
+func WithTreasurySigner(treasurySigner TreasurySigner) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.treasurySigner = treasurySigner
+	}
+}
+
 // WithBlockBackups defines whether all blocks that are issued by the coordinator
 // should be stored to disk before being submitted to the network.
 func WithBlockBackups(blockBackupsEnabled bool, blockBackupsFolderPath string) options.Option[Coordinator] {
@@ -216,6 +349,50 @@ func WithBlockBackups(blockBackupsEnabled bool, blockBackupsFolderPath string) o
 	}
 }
 
+// WithMilestoneMetadata defines optional metadata that is embedded into every milestone (e.g. a network tag or coordinator version).
+func WithMilestoneMetadata(milestoneMetadata []byte) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.milestoneMetadata = milestoneMetadata
+	}
+}
+
+// WithMilestoneParentsCount defines the maximum amount of parents of a milestone.
+// Milestones with less parents leave more space for the receipts.
+func WithMilestoneParentsCount(milestoneParentsCount int) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.milestoneParentsCount = milestoneParentsCount
+	}
+}
+
+// WithConfirmationCheck defines that issued milestones need to be confirmed within the given amount of milestone intervals.
+// If a milestone is not confirmed in time, an alert is raised and the same milestone is sent again,
+// unless the given function reports that the node already confirmed it in the meantime.
+// A signed milestone index is never reissued with other parents, so there are never two milestones with the same index.
+func WithConfirmationCheck(confirmationMilestones int, confirmedMilestoneIndexFunc ConfirmedMilestoneIndexFunc) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.confirmationMilestones = confirmationMilestones
+		c.confirmedMilestoneIndexFunc = confirmedMilestoneIndexFunc
+	}
+}
+
+// WithMilestoneResend defines how often a milestone that was not confirmed in time is sent at most,
+// and the initial backoff before it is sent again. The backoff doubles with every attempt up to the confirmation timeout.
+func WithMilestoneResend(maxAttempts int, backoff time.Duration) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.milestoneSendAttempts = maxAttempts
+		c.milestoneResendBackoff = backoff
+	}
+}
+
+// WithMaxBlockLag defines the maximum age of the latest solid block of the node.
+// If the node is not synced or did not receive new blocks within that duration, milestones are skipped,
+// instead of confirming a stale cone.
+func WithMaxBlockLag(maxBlockLag time.Duration) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.maxBlockLag = maxBlockLag
+	}
+}
+
 // WithDebugFakeMilestoneTimestamps defines whether the coordinator will fake timestamps of milestones
 // if the interval is below 1s (use for tests only!)
 func WithDebugFakeMilestoneTimestamps(debugFakeMilestoneTimestamps bool) options.Option[Coordinator] {
@@ -246,10 +423,12 @@ func New(
 		signerProvider:         signerProvider,
 		migratorService:        migratorService,
 		treasuryOutputFunc:     treasuryOutputFunc,
+		treasuryValidation:     &TreasuryValidation{Mode: TreasuryValidationStrict},
 		sendBlockFunc:          sendBlockFunc,
 		milestoneTimeoutTicker: nil,
 
 		stateFilePath:                defaultStateFilePath,
+		stateBackups:                 1,
 		milestoneInterval:            defaultMilestoneInterval,
 		milestoneTimeout:             defaultMilestoneTimeout,
 		signingRetryTimeout:          2 * time.Second,
@@ -257,6 +436,21 @@ func New(
 		quorum:                       nil,
 		blockBackupsEnabled:          true,
 		blockBackupsFolderPath:       "block_backups",
+		milestoneMetadata:            nil,
+		milestoneParentsCount:        iotago.BlockMaxParents,
+		confirmationMilestones:       0,
+		confirmedMilestoneIndexFunc:  nil,
+		milestoneSendAttempts:        DefaultMilestoneSendAttempts,
+		milestoneResendBackoff:       DefaultMilestoneResendBackoff,
+		issuedMilestones:             make(map[iotago.MilestoneIndex]*MilestoneConfirmationFailure),
+		catchUpPolicy:                CatchUpPolicyImmediate,
+		maxCatchUpMilestones:         defaultMaxCatchUpMilestones,
+		catchUpInterval:              defaultCatchUpInterval,
+		maxClockDrift:                defaultMaxClockDrift,
+		clock:                        time.Now,
+		latencyBudget:                nil,
+		milestoneDeadline:            nil,
+		powProvider:                  &NodePoWProvider{},
 		debugFakeMilestoneTimestamps: false,
 
 		Events: &Events{
@@ -265,17 +459,67 @@ func New(
 			SoftError:             events.NewEvent(events.ErrorCaller),
 			QuorumFinished:        events.NewEvent(QuorumFinishedCaller),
 			MilestoneTimeout:      events.NewEvent(events.VoidCaller),
+			ReceiptSigned:         events.NewEvent(ReceiptSignedCaller),
+			ReceiptPending:        events.NewEvent(ReceiptPendingCaller),
+			ReceiptIssued:         events.NewEvent(ReceiptIssuedCaller),
+
+			MilestoneConfirmationFailed: events.NewEvent(MilestoneConfirmationFailedCaller),
+			MilestoneSkipped:            events.NewEvent(MilestoneSkippedCaller),
+			MilestoneGapDetected:        events.NewEvent(RecoveryPlanCaller),
+			ClockDriftDetected:          events.NewEvent(ClockDriftCaller),
+			PoWAttempt:                  events.NewEvent(PoWAttemptCaller),
+			MilestoneTimings:            events.NewEvent(MilestoneTimingsCaller),
+			MigrationCompleted:          events.NewEvent(MigrationSummaryCaller),
+			SigningSelfTestCompleted:    events.NewEvent(SigningSelfTestCaller),
+			QuorumNodeDemoted:           events.NewEvent(QuorumNodeDemotedCaller),
+			ReceiptDeferred:             events.NewEvent(ReceiptDeferredCaller),
+			ReceiptsPaused:              events.NewEvent(ReceiptPauseCaller),
+			ReceiptsResumed:             events.NewEvent(ReceiptPauseCaller),
+			Decision:                    events.NewEvent(DecisionCaller),
 		},
 	}, opts)
 
+	if result.quorum != nil {
+		result.quorum.scoring = result.quorumScoring
+	}
+
+	if result.protocolAdapters == nil {
+		// without activations all milestones are issued with the stardust protocol
+		protocolAdapters, err := NewProtocolAdapters(nil)
+		if err != nil {
+			return nil, common.CriticalError(err)
+		}
+		result.protocolAdapters = protocolAdapters
+	}
+
 	if !result.debugFakeMilestoneTimestamps && result.milestoneInterval < time.Second {
 		return nil, common.CriticalError(errors.New("the milestone interval must be at least 1s"))
 	}
 
+	if len(result.milestoneMetadata) > iotago.MaxMetadataLength {
+		return nil, common.CriticalError(fmt.Errorf("the milestone metadata exceeds the maximum length (%d > %d)", len(result.milestoneMetadata), iotago.MaxMetadataLength))
+	}
+
 	if err := result.checkBlockBackupsFolder(); err != nil {
 		return nil, common.CriticalError(err)
 	}
 
+	if result.milestoneSendAttempts < 1 {
+		return nil, common.CriticalError(fmt.Errorf("invalid amount of milestone send attempts: %d (must be at least 1)", result.milestoneSendAttempts))
+	}
+
+	if result.milestoneResendBackoff <= 0 {
+		return nil, common.CriticalError(fmt.Errorf("invalid milestone resend backoff: %v (must be positive)", result.milestoneResendBackoff))
+	}
+
+	if result.milestoneParentsCount < 1 || result.milestoneParentsCount > iotago.BlockMaxParents {
+		return nil, common.CriticalError(fmt.Errorf("invalid amount of milestone parents: %d (must be between 1 and %d)", result.milestoneParentsCount, iotago.BlockMaxParents))
+	}
+
+	if err := result.updateReceiptLimits(); err != nil {
+		return nil, common.CriticalError(err)
+	}
+
 	return result, nil
 }
 
@@ -344,6 +588,10 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex iotago.MilestoneInd
 	_, err := os.Stat(coo.stateFilePath)
 	stateFileExists := !os.IsNotExist(err)
 
+	if coo.networkReset && !bootstrap {
+		return fmt.Errorf("%w: a network reset can only be done when bootstrapping the network", ErrInvalidNetworkReset)
+	}
+
 	if bootstrap {
 		if stateFileExists {
 			return ErrNetworkBootstrapped
@@ -359,8 +607,21 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex iotago.MilestoneInd
 		}
 
 		latestMilestoneID := iotago.MilestoneID{}
-		if startIndex != 1 {
+		var networkResetIndex iotago.MilestoneIndex
+		switch {
+		case coo.networkReset:
+			if startIndex == 1 {
+				return fmt.Errorf("%w: the start index must be greater than 1, otherwise a new network is bootstrapped", ErrInvalidNetworkReset)
+			}
+
+			// the node of a reset network only knows the index of the previous milestone, but not the milestone itself
+			if latestMilestone.MilestoneID != emptyMilestoneID {
+				return fmt.Errorf("%w: the node knows the previous milestone %d, the network was not reset", ErrInvalidNetworkReset, latestMilestone.Index)
+			}
+
+			networkResetIndex = startIndex
 
+		case startIndex != 1:
 			if latestMilestone.MilestoneID == emptyMilestoneID {
 				return fmt.Errorf("previous milestone milestoneID should not be genesis")
 			}
@@ -375,11 +636,16 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex iotago.MilestoneInd
 		state.LatestMilestoneID = latestMilestoneID
 		state.LatestMilestoneIndex = startIndex - 1
 		state.LatestMilestoneTime = time.Time{}
+		state.NetworkResetIndex = networkResetIndex
 
 		coo.state = state
 		coo.bootstrapped = false
 
-		coo.LogInfof("bootstrapping coordinator at %d", startIndex)
+		if coo.networkReset {
+			coo.LogInfof("bootstrapping coordinator at %d after a network reset", startIndex)
+		} else {
+			coo.LogInfof("bootstrapping coordinator at %d", startIndex)
+		}
 
 		return nil
 	}
@@ -389,10 +655,15 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex iotago.MilestoneInd
 	}
 
 	coo.state = &State{}
-	if err := ioutils.ReadJSONFromFile(coo.stateFilePath, coo.state); err != nil {
+	storedVersion, err := stateSchema.ReadJSONFromFile(coo.stateFilePath, coo.state)
+	if err != nil {
 		return err
 	}
 
+	if storedVersion != StateVersion {
+		coo.LogInfof("upgraded coordinator state file from version %d to %d", storedVersion, StateVersion)
+	}
+
 	if latestMilestone.Index != coo.state.LatestMilestoneIndex {
 		return fmt.Errorf("previous milestone does not match latest milestone in node. previous: %d, INX: %d", coo.state.LatestMilestoneIndex, latestMilestone.Index)
 	}
@@ -406,46 +677,76 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex iotago.MilestoneInd
 
 // createAndSendMilestone creates a milestone, sends it to the network and stores a new coordinator state file.
 // Returns non-critical and critical errors.
-func (coo *Coordinator) createAndSendMilestone(parents iotago.BlockIDs, newMilestoneIndex iotago.MilestoneIndex, previousMilestoneID iotago.MilestoneID) error {
+func (coo *Coordinator) createAndSendMilestone(ctx context.Context, parents iotago.BlockIDs, newMilestoneIndex iotago.MilestoneIndex, previousMilestoneID iotago.MilestoneID) error {
 
-	// We have to set a timestamp for when we run the white-flag mutations due to the semantic validation.
-	// This should be exactly the same one used when issuing the milestone later on.
-	newMilestoneTimestamp := time.Now()
+	timer := newMilestoneTimer(coo.latencyBudget, newMilestoneIndex, coo.tipSelectionDuration)
+	if coo.tipSelectionDuration > 0 {
+		// slow tips are only skipped for a single milestone, so that the cone does not grow too large
+		coo.tipSelectionExceeded = timer.record(StageTipSelection, coo.tipSelectionDuration)
+	}
+	if coo.tipSelectionCached {
+		timer.degrade(DegradationCachedTips)
+	}
+	coo.tipSelectionDuration = 0
+	coo.tipSelectionCached = false
 
-	// we need to take care that the new milestone timestamp increased to satisfy the L1 protocol rules.
-	if newMilestoneTimestamp.Unix() < coo.state.LatestMilestoneTime.Add(time.Second).Unix() {
-		if !coo.debugFakeMilestoneTimestamps {
-			return common.SoftError(ErrMilestoneTimestampDidNotIncrease)
-		}
+	issued := false
+	defer func() {
+		coo.Events.MilestoneTimings.Trigger(timer.finish(issued))
+	}()
 
-		// in bootstrap case, we take the current time for the last milestone timestamp
-		if coo.state.LatestMilestoneTime.IsZero() {
-			coo.state.LatestMilestoneTime = time.Now()
-		}
+	// the deadline includes the tip selection, so it starts together with the timer
+	deadline := newIssuanceDeadline(coo.milestoneDeadline, newMilestoneIndex, timer.start)
 
-		// if the debug mode is enabled, we fake the timestamps so that the L1 protocol rules checks still pass,
-		// but we are able to issue milestones faster than 1s interval.
-		newMilestoneTimestamp = coo.state.LatestMilestoneTime.Add(time.Second)
+	// We have to set a timestamp for when we run the white-flag mutations due to the semantic validation.
+	// This should be exactly the same one used when issuing the milestone later on.
+	// we need to take care that the new milestone timestamp increased to satisfy the L1 protocol rules.
+	newMilestoneTimestamp, err := coo.newMilestoneTimestamp(newMilestoneIndex)
+	if err != nil {
+		return err
 	}
 
 	parents = parents.RemoveDupsAndSort()
 
 	// compute merkle tree root
-	// we pass a background context here to not cancel the white-flag computation!
-	// otherwise the coordinator could panic at shutdown.
-	merkleProof, err := coo.merkleRootFunc(context.Background(), newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, previousMilestoneID)
+	// the context is not derived from the given one to not cancel the white-flag computation!
+	// otherwise the coordinator could panic at shutdown. it is only bounded by the deadline.
+	timer.startStage()
+	whiteFlagCtx, whiteFlagCancel := deadline.stageContext(StageWhiteFlag)
+	merkleProof, err := coo.merkleRootFunc(whiteFlagCtx, newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, previousMilestoneID)
+	if err != nil && whiteFlagCtx.Err() != nil {
+		// the node didn't compute the merkle roots before the deadline, the milestone is issued with the next interval
+		err = common.SoftError(deadline.exceeded(whiteFlagCtx, StageWhiteFlag))
+	}
+	whiteFlagCancel()
+	timer.finishStage(StageWhiteFlag)
 	if err != nil {
+		if common.IsSoftError(err) != nil {
+			return err
+		}
+
 		return common.CriticalError(fmt.Errorf("failed to compute white flag mutations: %w", err))
 	}
 
 	// ask the quorum for correct ledger state if enabled
 	if coo.quorum != nil {
+		timer.startStage()
 		ts := time.Now()
-		err := coo.quorum.checkMerkleTreeHash(merkleProof, newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, previousMilestoneID, func(groupName string, entry *quorumGroupEntry, err error) {
+		quorumCtx, quorumCancel := deadline.stageContext(StageQuorum)
+		err := coo.quorum.checkMerkleTreeHash(quorumCtx, merkleProof, newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, previousMilestoneID, func(groupName string, entry *quorumGroupEntry, err error) {
 			coo.LogInfof("coordinator quorum group encountered an error, group: %s, baseURL: %s, err: %s", groupName, entry.stats.BaseURL, err)
+		}, func(demotion *QuorumNodeDemotion) {
+			coo.Events.QuorumNodeDemoted.Trigger(demotion)
 		})
 
+		if err != nil && quorumCtx.Err() != nil && common.IsCriticalError(err) == nil {
+			// the quorum nodes didn't answer before the deadline, the milestone is issued with the next interval
+			err = common.SoftError(deadline.exceeded(quorumCtx, StageQuorum))
+		}
+		quorumCancel()
+
 		duration := time.Since(ts)
+		timer.finishStage(StageQuorum)
 		coo.Events.QuorumFinished.Trigger(&QuorumFinishedResult{Duration: duration, Err: err})
 
 		if err != nil {
@@ -458,31 +759,146 @@ func (coo *Coordinator) createAndSendMilestone(parents iotago.BlockIDs, newMiles
 		coo.LogInfof("coordinator quorum took %v", duration.Truncate(time.Millisecond))
 	}
 
+	// the additional options are bound to the milestone index, so they are always included
+	milestoneOpts, err := coo.milestoneOptions(newMilestoneIndex)
+	if err != nil {
+		return common.CriticalError(err)
+	}
+
+	// the protocol parameters might have been updated since the last milestone
+	if err := coo.updateReceiptLimits(); err != nil {
+		return common.CriticalError(err)
+	}
+
 	// get receipt data in case migrator is enabled
 	var receipt *iotago.ReceiptMilestoneOpt
-	if coo.migratorService != nil {
-		receipt = coo.migratorService.Receipt()
-		if receipt != nil {
-			if err := coo.migratorService.PersistState(true); err != nil {
-				return common.CriticalError(fmt.Errorf("unable to persist migrator state before send: %w", err))
-			}
+	// the signature of the treasury key over the receipt, stored with the inclusion proof of the receipt
+	var treasurySignature *iotago.Ed25519Signature
+	// released once the migrator state marking the receipt as being sent is durable
+	var migratorStatePersisted *migrator.PersistBarrier
+	switch {
+	case coo.migratorService == nil:
+	case timer.late(StageReceipt):
+		// the milestone must not be delayed further by the treasury, the receipt is issued with the next milestone.
+		// the migrator keeps the receipt until it is fetched, and a pending receipt stays pending.
+		timer.degrade(DegradationReceiptSkipped)
+		coo.LogWarnf("milestone %d exceeded its latency budget, available receipts are postponed to the next milestone", newMilestoneIndex)
+
+	case coo.ReceiptPause() != nil:
+		// the node is unhealthy, the migrator keeps the receipt until the receipts are resumed, and a pending receipt stays pending.
 
+	default:
+		timer.startStage()
+		receiptCtx, receiptCancel := deadline.stageContext(StageReceipt)
+		defer receiptCancel()
+
+		// a receipt that was taken from the migrator, but not sent yet, needs to be issued first
+		receipt = coo.pendingReceipt
+		newReceipt := receipt == nil
+		if newReceipt {
+			// the migrator keeps the receipt if the deadline is exceeded before it was fetched
+			receipt = coo.migratorService.Receipt(receiptCtx)
+			if receipt == nil && receiptCtx.Err() != nil {
+				timer.degrade(DegradationReceiptSkipped)
+				coo.LogWarnf("milestone %d exceeded the receipt timeout of its deadline, available receipts are postponed to the next milestone", newMilestoneIndex)
+			}
+		}
+		if receipt != nil {
 			currentTreasuryOutput, err := coo.treasuryOutputFunc()
 			if err != nil {
 				return common.CriticalError(fmt.Errorf("unable to fetch unspent treasury output: %w", err))
 			}
 
+			tolerated, err := coo.treasuryValidation.Validate(currentTreasuryOutput, receipt.Sum(), coo.protoParamsFunc().TokenSupply)
+			if err != nil {
+				return common.CriticalError(fmt.Errorf("invalid treasury output for receipt of legacy milestone %d: %w", receipt.MigratedAt, err))
+			}
+			if tolerated != nil {
+				coo.LogWarnf("tolerating treasury output for receipt of legacy milestone %d (%s mode): %s", receipt.MigratedAt, coo.treasuryValidation.Mode, tolerated)
+			}
+
 			// embed treasury within the receipt
-			input := &iotago.TreasuryInput{}
-			copy(input[:], currentTreasuryOutput.MilestoneID[:])
-			output := &iotago.TreasuryOutput{Amount: currentTreasuryOutput.Amount - receipt.Sum()}
-			treasuryTx := &iotago.TreasuryTransaction{Input: input, Output: output}
-			receipt.Transaction = treasuryTx
+			receipt.Transaction = coo.protocolAdapters.AdapterAt(newMilestoneIndex).NewTreasuryTransaction(currentTreasuryOutput, receipt)
 			receipt.SortFunds()
+
+			deferred, err := coo.deferReceipt(&MilestoneParameters{
+				Index:       newMilestoneIndex,
+				Parents:     parents,
+				MerkleRoots: merkleProof,
+				Metadata:    coo.milestoneMetadata,
+				Receipt:     receipt,
+				Opts:        milestoneOpts,
+			})
+			if err != nil {
+				return common.CriticalError(fmt.Errorf("failed to check the options of milestone %d: %w", newMilestoneIndex, err))
+			}
+
+			// the receipt is signaled once it was taken from the migrator, a pending receipt that is reissued was already signaled
+			if newReceipt {
+				coo.Events.ReceiptPending.Trigger(newMilestoneIndex, newMilestoneTimestamp, receipt)
+			}
+
+			switch {
+			case deferred:
+				// the receipt was already taken from the migrator, so it is issued with the next milestone
+				coo.LogWarnf("receipt of legacy milestone %d doesn't fit into milestone %d alongside its options, the receipt is deferred to the next milestone", receipt.MigratedAt, newMilestoneIndex)
+				coo.pendingReceipt = receipt
+				coo.Events.ReceiptDeferred.Trigger(newMilestoneIndex, receipt)
+
+			case coo.treasurySigner != nil:
+				// the receipt needs to be authorized by the treasury key as well
+				signature, err := coo.signReceipt(receiptCtx, newMilestoneIndex, receipt)
+				if errors.Is(err, ErrMilestoneDeadlineExceeded) {
+					// the receipt was already taken from the migrator, so it is reissued with the next milestone
+					coo.pendingReceipt = receipt
+
+					return common.SoftError(&SigningError{Index: newMilestoneIndex, Stage: StageReceipt, Err: err})
+				}
+				if err != nil {
+					return common.CriticalError(&SigningError{Index: newMilestoneIndex, Stage: StageReceipt, Err: fmt.Errorf("failed to sign receipt with treasury key: %w", err)})
+				}
+
+				treasurySignature = signature
+				coo.Events.ReceiptSigned.Trigger(newMilestoneIndex, signature)
+			}
+
+			if deferred {
+				// the state is only marked as sending once the receipt is embedded, otherwise a restart is refused
+				receipt = nil
+			} else {
+				// the state is written while the milestone is signed
+				migratorStatePersisted = coo.migratorService.PersistStateAsync(true)
+			}
 		}
+
+		timer.finishStage(StageReceipt)
 	}
 
-	milestoneBlock, err := coo.createMilestone(newMilestoneIndex, uint32(newMilestoneTimestamp.Unix()), parents, receipt, previousMilestoneID, merkleProof)
+	timer.startStage()
+	signingCtx, signingCancel := deadline.stageContext(StageSigning)
+	milestoneBlock, err := coo.createMilestone(signingCtx, &MilestoneParameters{
+		Index:               newMilestoneIndex,
+		Timestamp:           uint32(newMilestoneTimestamp.Unix()),
+		PreviousMilestoneID: previousMilestoneID,
+		Parents:             parents,
+		MerkleRoots:         merkleProof,
+		Metadata:            coo.milestoneMetadata,
+		Receipt:             receipt,
+		Opts:                milestoneOpts,
+	})
+	signingCancel()
+	timer.finishStage(StageSigning)
+	if errors.Is(err, ErrMilestoneDeadlineExceeded) {
+		// the milestone was not sent, a receipt is reissued with the next milestone
+		if migratorStatePersisted != nil {
+			if err := coo.migratorService.AbortSendingReceipt(context.Background()); err != nil {
+				return common.CriticalError(fmt.Errorf("unable to restore migrator state of unsent receipt: %w", err))
+			}
+		}
+		coo.keepPendingReceipt(receipt)
+
+		return common.SoftError(err)
+	}
 	if err != nil {
 		return common.CriticalError(fmt.Errorf("failed to create milestone: %w", err))
 	}
@@ -496,18 +912,35 @@ func (coo *Coordinator) createAndSendMilestone(parents iotago.BlockIDs, newMiles
 		return common.CriticalError(fmt.Errorf("failed to compute milestone ID: %w", err))
 	}
 
-	// rename the coordinator state file to mark the state as invalid
-	if err := os.Rename(coo.stateFilePath, fmt.Sprintf("%s_old", coo.stateFilePath)); err != nil && !os.IsNotExist(err) {
+	// the receipt must never be sent before the migrator state marks it as being sent
+	if migratorStatePersisted != nil {
+		if err := migratorStatePersisted.Wait(); err != nil {
+			return common.CriticalError(fmt.Errorf("unable to persist migrator state before send: %w", err))
+		}
+	}
+
+	// move the coordinator state file to its backups to mark the state as invalid
+	if err := retention.BackupFile(coo.stateFilePath, coo.stateBackups); err != nil {
 		return common.CriticalError(fmt.Errorf("unable to rename old coordinator state file: %w", err))
 	}
 
-	latestMilestoneBlockID, err := coo.sendBlockFunc(milestoneBlock, newMilestoneIndex)
+	timer.startStage()
+	// the milestone is signed, so it is never reissued with another content.
+	// it is not sent again once the deadline is exceeded, the coordinator is stopped instead.
+	sendingCtx, sendingCancel := deadline.remainderContext(ctx)
+	latestMilestoneBlockID, err := coo.sendMilestone(sendingCtx, milestoneBlock, newMilestoneIndex, milestoneID, receipt != nil)
+	sendingCancel()
+	timer.finishStage(StageSending)
 	if err != nil {
-		return common.CriticalError(fmt.Errorf("failed to send milestone: %w", err))
+		return common.CriticalError(&SubmissionError{Index: newMilestoneIndex, Stage: StageSending, Err: err})
+	}
+	if receipt != nil {
+		coo.pendingReceipt = nil
 	}
 
 	if coo.migratorService != nil && receipt != nil {
-		if err := coo.migratorService.PersistState(false); err != nil {
+		// the receipt was sent, so the state must be persisted even if the coordinator is shutting down
+		if err := coo.migratorService.PersistState(context.Background(), false); err != nil {
 			return common.CriticalError(fmt.Errorf("unable to persist migrator state after send: %w", err))
 		}
 	}
@@ -522,8 +955,28 @@ func (coo *Coordinator) createAndSendMilestone(parents iotago.BlockIDs, newMiles
 		return common.CriticalError(fmt.Errorf("failed to update coordinator state file: %w", err))
 	}
 
+	issued = true
 	coo.Events.IssuedMilestone.Trigger(coo.state.LatestMilestoneIndex, coo.state.LatestMilestoneID, coo.state.LatestMilestoneBlockID)
 
+	if receipt != nil {
+		coo.Events.ReceiptIssued.Trigger(coo.state.LatestMilestoneIndex, receipt, receipt.Size())
+
+		// the milestone was confirmed by the node, so the receipt can be proven now
+		if err := coo.storeReceiptProof(milestoneBlock, latestMilestoneBlockID, treasurySignature); err != nil {
+			coo.LogWarnf("failed to store inclusion proof of receipt in milestone %d: %s", newMilestoneIndex, err)
+		}
+
+		if coo.completesMigration(receipt) {
+			// the migration is already completed on the network, a failed summary must not stop the coordinator
+			summary, err := coo.createMigrationSummary(newMilestoneIndex, milestoneID)
+			if err != nil {
+				coo.LogWarnf("failed to create summary of the completed migration in milestone %d: %s", newMilestoneIndex, err)
+			} else {
+				coo.Events.MigrationCompleted.Trigger(summary)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -534,18 +987,27 @@ func (coo *Coordinator) Bootstrap() (iotago.BlockID, error) {
 	coo.milestoneLock.Lock()
 	defer coo.milestoneLock.Unlock()
 
+	decision := &Decision{Kind: DecisionBootstrap}
+	defer coo.Events.Decision.Trigger(decision)
+
 	if !coo.bootstrapped {
 		// create first milestone to bootstrap the network
 		// only one parent references the last known milestone or NullBlockID if startIndex = 1 (see InitState)
-		err := coo.createAndSendMilestone(iotago.BlockIDs{coo.state.LatestMilestoneBlockID}, coo.state.LatestMilestoneIndex+1, coo.state.LatestMilestoneID)
+		decision.Parents = iotago.BlockIDs{coo.state.LatestMilestoneBlockID}
+
+		err := coo.createAndSendMilestone(context.Background(), decision.Parents, coo.state.LatestMilestoneIndex+1, coo.state.LatestMilestoneID)
 		if err != nil {
 			// creating milestone failed => always a critical error at bootstrap
-			return iotago.EmptyBlockID(), common.CriticalError(err)
+			decision.Err = common.CriticalError(err)
+
+			return iotago.EmptyBlockID(), decision.Err
 		}
 
 		coo.bootstrapped = true
 	}
 
+	decision.BlockID = coo.state.LatestMilestoneBlockID
+
 	return coo.state.LatestMilestoneBlockID, nil
 }
 
@@ -562,14 +1024,35 @@ func (coo *Coordinator) IssueCheckpoint(checkpointIndex int, lastCheckpointBlock
 	coo.milestoneLock.Lock()
 	defer coo.milestoneLock.Unlock()
 
+	decision := &Decision{
+		Kind:                  DecisionCheckpoint,
+		CheckpointIndex:       checkpointIndex,
+		LastCheckpointBlockID: lastCheckpointBlockID,
+		Parents:               tips,
+	}
+	defer coo.Events.Decision.Trigger(decision)
+
+	decision.BlockID, decision.Skipped, decision.Err = coo.issueCheckpoints(checkpointIndex, lastCheckpointBlockID, tips)
+
+	return decision.BlockID, decision.Err
+}
+
+// issueCheckpoints creates and sends the checkpoints that reference the given tips.
+// Returns whether the checkpoints were skipped because of a handoff or the load of the node.
+func (coo *Coordinator) issueCheckpoints(checkpointIndex int, lastCheckpointBlockID iotago.BlockID, tips iotago.BlockIDs) (iotago.BlockID, bool, error) {
+
+	if coo.suspended {
+		return iotago.EmptyBlockID(), true, common.SoftError(ErrSuspended)
+	}
+
 	if !coo.isNodeSynced() {
-		return iotago.EmptyBlockID(), common.SoftError(common.ErrNodeNotSynced)
+		return iotago.EmptyBlockID(), false, common.SoftError(common.ErrNodeNotSynced)
 	}
 
 	// check whether we should hold issuing checkpoints
 	// if the node is currently under a lot of load
 	if coo.checkBackPressureFunctions() {
-		return iotago.EmptyBlockID(), common.SoftError(ErrNodeLoadTooHigh)
+		return iotago.EmptyBlockID(), true, common.SoftError(ErrNodeLoadTooHigh)
 	}
 
 	// maximum 8 parents per block (7 tips + last checkpoint blockID)
@@ -588,18 +1071,22 @@ func (coo *Coordinator) IssueCheckpoint(checkpointIndex int, lastCheckpointBlock
 		parents = append(parents, tips[tipStart:tipEnd]...)
 		parents = parents.RemoveDupsAndSort()
 
-		block, err := coo.createCheckpoint(parents)
+		block, err := coo.createCheckpoint(coo.state.LatestMilestoneIndex+1, parents)
 		if err != nil {
-			return iotago.EmptyBlockID(), common.SoftError(fmt.Errorf("failed to create checkPoint: %w", err))
+			return iotago.EmptyBlockID(), false, common.SoftError(fmt.Errorf("failed to create checkPoint: %w", err))
+		}
+
+		if err := coo.doPoW(block); err != nil {
+			return iotago.EmptyBlockID(), false, common.SoftError(fmt.Errorf("failed to do PoW for checkPoint: %w", err))
 		}
 
 		if err := coo.backupBlock(block); err != nil {
-			return iotago.EmptyBlockID(), common.SoftError(fmt.Errorf("failed to create checkPoint block backup: %w", err))
+			return iotago.EmptyBlockID(), false, common.SoftError(fmt.Errorf("failed to create checkPoint block backup: %w", err))
 		}
 
 		blockID, err := coo.sendBlockFunc(block)
 		if err != nil {
-			return iotago.EmptyBlockID(), common.SoftError(fmt.Errorf("failed to send checkPoint: %w", err))
+			return iotago.EmptyBlockID(), false, common.SoftError(&SubmissionError{Index: coo.state.LatestMilestoneIndex, Stage: StageCheckpoint, Err: err})
 		}
 
 		lastCheckpointBlockID = blockID
@@ -607,32 +1094,65 @@ func (coo *Coordinator) IssueCheckpoint(checkpointIndex int, lastCheckpointBlock
 		coo.Events.IssuedCheckpointBlock.Trigger(checkpointIndex, i, checkpointsNumber, lastCheckpointBlockID)
 	}
 
-	return lastCheckpointBlockID, nil
+	return lastCheckpointBlockID, false, nil
 }
 
 // IssueMilestone creates the next milestone.
+// An issued milestone that is not confirmed in time is sent again until the given context is done.
 // Returns non-critical and critical errors.
-func (coo *Coordinator) IssueMilestone(parents iotago.BlockIDs) (iotago.BlockID, error) {
+func (coo *Coordinator) IssueMilestone(ctx context.Context, parents iotago.BlockIDs) (iotago.BlockID, error) {
 
 	coo.milestoneLock.Lock()
 	defer coo.milestoneLock.Unlock()
 
+	decision := &Decision{
+		Kind:    DecisionMilestone,
+		Parents: parents,
+	}
+	defer coo.Events.Decision.Trigger(decision)
+
+	decision.BlockID, decision.Skipped, decision.Err = coo.issueMilestone(ctx, parents)
+
+	return decision.BlockID, decision.Err
+}
+
+// issueMilestone creates and sends the next milestone.
+// Returns whether the milestone was skipped because of a handoff, the load, the block lag or the health of the node.
+func (coo *Coordinator) issueMilestone(ctx context.Context, parents iotago.BlockIDs) (iotago.BlockID, bool, error) {
+
 	// we don't need to check if the node is synced,
 	// because the node takes care if the milestone index is the next one
-	// during whiteflag.
+	// during whiteflag (it is only checked if the max block lag is enforced).
+
+	// the coordinator handed off to another process
+	if coo.suspended {
+		return iotago.EmptyBlockID(), true, common.SoftError(ErrSuspended)
+	}
 
 	// check whether we should hold issuing miletones
 	// if the node is currently under a lot of load
 	if coo.checkBackPressureFunctions() {
-		return iotago.EmptyBlockID(), common.SoftError(ErrNodeLoadTooHigh)
+		return iotago.EmptyBlockID(), true, common.SoftError(ErrNodeLoadTooHigh)
+	}
+
+	// don't confirm a stale cone if the node is lagging behind
+	if err := coo.checkBlockLag(coo.state.LatestMilestoneIndex + 1); err != nil {
+		return iotago.EmptyBlockID(), true, err
+	}
+
+	// receipts are paused while the node is unhealthy
+	if err := coo.checkNodeHealth(coo.state.LatestMilestoneIndex + 1); err != nil {
+		return iotago.EmptyBlockID(), true, err
 	}
 
-	if err := coo.createAndSendMilestone(parents, coo.state.LatestMilestoneIndex+1, coo.state.LatestMilestoneID); err != nil {
+	if err := coo.createAndSendMilestone(ctx, parents, coo.state.LatestMilestoneIndex+1, coo.state.LatestMilestoneID); err != nil {
 		// creating milestone failed => non-critical or critical error
-		return iotago.EmptyBlockID(), err
+		return iotago.EmptyBlockID(), false, err
 	}
 
-	return coo.state.LatestMilestoneBlockID, nil
+	coo.unpinUsedParents(parents)
+
+	return coo.state.LatestMilestoneBlockID, false, nil
 }
 
 // Interval returns the interval milestones should be issued.
@@ -640,6 +1160,16 @@ func (coo *Coordinator) Interval() time.Duration {
 	return coo.milestoneInterval
 }
 
+// ConfirmationTimeout returns the duration an issued milestone needs to be confirmed within (0 = no timeout).
+func (coo *Coordinator) ConfirmationTimeout() time.Duration {
+	return time.Duration(coo.confirmationMilestones) * coo.milestoneInterval
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (coo *Coordinator) PublicKeysCount() int {
+	return coo.signerProvider.PublicKeysCount()
+}
+
 // State returns the current state of the coordinator.
 func (coo *Coordinator) State() *State {
 	return coo.state
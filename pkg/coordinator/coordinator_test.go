@@ -0,0 +1,161 @@
+package coordinator_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+var testProtoParams = &iotago.ProtocolParameters{
+	Version:       2,
+	NetworkName:   "testnet",
+	Bech32HRP:     iotago.PrefixTestnet,
+	BelowMaxDepth: 15,
+	RentStructure: iotago.RentStructure{
+		VByteCost:    500,
+		VBFactorData: 1,
+		VBFactorKey:  10,
+	},
+	TokenSupply: 2_779_530_283_277_761,
+}
+
+// newTestSignerProvider creates a signer provider with the given amount of random milestone keys, which all need to sign.
+func newTestSignerProvider(t *testing.T, keysCount int) coordinator.MilestoneSignerProvider {
+	t.Helper()
+
+	keyManager := keymanager.New()
+	privateKeys := make([]ed25519.PrivateKey, keysCount)
+	for i := range privateKeys {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keyManager.AddKeyRange(publicKey, 0, 0)
+		privateKeys[i] = privateKey
+	}
+
+	return coordinator.NewInMemoryEd25519MilestoneSignerProvider(privateKeys, keyManager, keysCount)
+}
+
+// testCoordinatorDeps are the dependencies of a test coordinator that only some tests need to replace.
+type testCoordinatorDeps struct {
+	// whether the node is synced (nil = always synced).
+	nodeSynced func() bool
+	// the provider of the milestone signers (nil = a single random key).
+	signerProvider coordinator.MilestoneSignerProvider
+	// the migrator that provides the receipts (nil = no receipts), the treasury always holds enough funds.
+	migratorService *migrator.Service
+	// the path to the state file (empty = a new file in a temporary folder).
+	stateFilePath string
+	// returns the current protocol parameters (nil = testProtoParams).
+	protoParamsFunc func() *iotago.ProtocolParameters
+	// computes the merkle roots of the milestones (nil = roots derived from the milestone index).
+	merkleRootsFunc coordinator.ComputeMilestoneMerkleRoots
+}
+
+// createCoordinator creates a coordinator that sends its blocks with the given function
+// (nil = consecutive block IDs are returned) and applies the given options after the defaults of the tests.
+// The clock of the coordinator advances by 10 seconds every time it is read.
+func (d *testCoordinatorDeps) createCoordinator(t *testing.T, sendBlock coordinator.SendBlockFunc, opts ...options.Option[coordinator.Coordinator]) *coordinator.Coordinator {
+	t.Helper()
+
+	nodeSynced := d.nodeSynced
+	if nodeSynced == nil {
+		nodeSynced = func() bool { return true }
+	}
+
+	signerProvider := d.signerProvider
+	if signerProvider == nil {
+		signerProvider = newTestSignerProvider(t, 1)
+	}
+
+	var treasuryOutputFunc coordinator.UnspentTreasuryOutputFunc
+	if d.migratorService != nil {
+		treasuryOutputFunc = func() (*coordinator.LatestTreasuryOutput, error) {
+			return &coordinator.LatestTreasuryOutput{Amount: 1_000_000_000_000}, nil
+		}
+	}
+
+	protoParamsFunc := d.protoParamsFunc
+	if protoParamsFunc == nil {
+		protoParamsFunc = func() *iotago.ProtocolParameters { return testProtoParams }
+	}
+
+	stateFilePath := d.stateFilePath
+	if stateFilePath == "" {
+		stateFilePath = filepath.Join(t.TempDir(), "coordinator.state")
+	}
+
+	if sendBlock == nil {
+		var sentBlocks atomic.Uint32
+		sendBlock = func(_ *iotago.Block, _ ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+			return iotago.BlockID{byte(sentBlocks.Add(1))}, nil
+		}
+	}
+
+	merkleRoots := d.merkleRootsFunc
+	if merkleRoots == nil {
+		merkleRoots = func(_ context.Context, index iotago.MilestoneIndex, _ uint32, _ iotago.BlockIDs, _ iotago.MilestoneID) (*coordinator.MilestoneMerkleRoots, error) {
+			return &coordinator.MilestoneMerkleRoots{
+				InclusionMerkleRoot: iotago.MilestoneMerkleProof{byte(index)},
+				AppliedMerkleRoot:   iotago.MilestoneMerkleProof{byte(index), 1},
+			}, nil
+		}
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	coo, err := coordinator.New(
+		merkleRoots,
+		nodeSynced,
+		protoParamsFunc,
+		signerProvider,
+		d.migratorService,
+		treasuryOutputFunc,
+		sendBlock,
+		append([]options.Option[coordinator.Coordinator]{
+			coordinator.WithLogger(logger.NewNopLogger()),
+			coordinator.WithStateFilePath(stateFilePath),
+			coordinator.WithBlockBackups(false, ""),
+			coordinator.WithClock(func() time.Time {
+				now = now.Add(10 * time.Second)
+
+				return now
+			}),
+		}, opts...)...,
+	)
+	require.NoError(t, err)
+
+	return coo
+}
+
+// newCoordinator creates a coordinator like createCoordinator and bootstraps it at index 1.
+// It returns the coordinator and the block ID of the bootstrap milestone.
+func (d *testCoordinatorDeps) newCoordinator(t *testing.T, sendBlock coordinator.SendBlockFunc, opts ...options.Option[coordinator.Coordinator]) (*coordinator.Coordinator, iotago.BlockID) {
+	t.Helper()
+
+	coo := d.createCoordinator(t, sendBlock, opts...)
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+
+	milestoneBlockID, err := coo.Bootstrap()
+	require.NoError(t, err)
+
+	return coo, milestoneBlockID
+}
+
+// newTestCoordinator creates a bootstrapped coordinator with the default dependencies, see testCoordinatorDeps.
+func newTestCoordinator(t *testing.T, sendBlock coordinator.SendBlockFunc, opts ...options.Option[coordinator.Coordinator]) (*coordinator.Coordinator, iotago.BlockID) {
+	t.Helper()
+
+	return (&testCoordinatorDeps{}).newCoordinator(t, sendBlock, opts...)
+}
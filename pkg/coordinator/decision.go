@@ -0,0 +1,40 @@
+package coordinator
+
+import (
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// DecisionBootstrap is the issuance of the first milestone of the network.
+	DecisionBootstrap = "bootstrap"
+	// DecisionCheckpoint is the issuance of checkpoints.
+	DecisionCheckpoint = "checkpoint"
+	// DecisionMilestone is the issuance of a milestone.
+	DecisionMilestone = "milestone"
+)
+
+// Decision is a call of the coordinator logic with its inputs and its outcome.
+// Together with the interactions with the node, the decisions are used to replay the coordinator offline.
+type Decision struct {
+	// the kind of the decision.
+	Kind string
+	// the index of the checkpoint (checkpoints only).
+	CheckpointIndex int
+	// the block referenced by the first new checkpoint (checkpoints only).
+	LastCheckpointBlockID iotago.BlockID
+	// the tips that were given to the coordinator.
+	Parents iotago.BlockIDs
+	// whether the issuance was skipped because of the load or the block lag of the node.
+	// both are not part of the interactions with the node, so skipped decisions can't be replayed.
+	Skipped bool
+	// the ID of the latest issued block.
+	BlockID iotago.BlockID
+	// the error that occurred while issuing the blocks.
+	Err error
+}
+
+// DecisionCaller is used to signal a decision of the coordinator.
+func DecisionCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(decision *Decision))(params[0].(*Decision))
+}
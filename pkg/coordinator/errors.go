@@ -0,0 +1,88 @@
+package coordinator
+
+import (
+	"fmt"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// StageCheckpoint is the submission of the checkpoint blocks.
+	StageCheckpoint = "checkpoint"
+	// StageMigrationSummary is the signing of the summary of the completed migration.
+	StageMigrationSummary = "migrationSummary"
+	// StageSelfTest is the signing of the test essence of the signing self-test.
+	StageSelfTest = "selfTest"
+)
+
+// SigningError is returned when a milestone or a receipt could not be signed.
+// errors.Is matches a SigningError with an empty stage and index zero against all SigningErrors,
+// otherwise the stage and the index need to be equal.
+type SigningError struct {
+	// the index of the milestone that was signed.
+	Index iotago.MilestoneIndex
+	// the stage of the milestone issuance the error occurred in, e.g. StageSigning or StageReceipt.
+	Stage string
+	// the error returned by the signer.
+	Err error
+}
+
+func (e *SigningError) Error() string {
+	return fmt.Sprintf("failed to sign milestone %d (%s): %s", e.Index, e.Stage, e.Err)
+}
+
+func (e *SigningError) Unwrap() error {
+	return e.Err
+}
+
+func (e *SigningError) Is(target error) bool {
+	t, ok := target.(*SigningError)
+	if !ok {
+		return false
+	}
+
+	return matchesIndexAndStage(t.Index, t.Stage, e.Index, e.Stage)
+}
+
+// SubmissionError is returned when a block issued by the coordinator could not be submitted to the network.
+// errors.Is matches a SubmissionError with an empty stage and index zero against all SubmissionErrors,
+// otherwise the stage and the index need to be equal.
+type SubmissionError struct {
+	// the index of the milestone, or the index of the latest milestone for checkpoints.
+	Index iotago.MilestoneIndex
+	// the stage of the issuance the error occurred in, e.g. StageSending or StageCheckpoint.
+	Stage string
+	// the error returned by the node.
+	Err error
+}
+
+func (e *SubmissionError) Error() string {
+	if e.Stage == StageCheckpoint {
+		return fmt.Sprintf("failed to submit checkpoint after milestone %d: %s", e.Index, e.Err)
+	}
+
+	return fmt.Sprintf("failed to submit milestone %d (%s): %s", e.Index, e.Stage, e.Err)
+}
+
+func (e *SubmissionError) Unwrap() error {
+	return e.Err
+}
+
+func (e *SubmissionError) Is(target error) bool {
+	t, ok := target.(*SubmissionError)
+	if !ok {
+		return false
+	}
+
+	return matchesIndexAndStage(t.Index, t.Stage, e.Index, e.Stage)
+}
+
+// matchesIndexAndStage checks whether the index and the stage of an error match the ones of the target.
+// Empty fields of the target match all values.
+func matchesIndexAndStage(targetIndex iotago.MilestoneIndex, targetStage string, index iotago.MilestoneIndex, stage string) bool {
+	if targetIndex != 0 && targetIndex != index {
+		return false
+	}
+
+	return targetStage == "" || targetStage == stage
+}
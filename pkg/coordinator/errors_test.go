@@ -0,0 +1,43 @@
+package coordinator_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func TestTypedErrors(t *testing.T) {
+	cause := errors.New("signer unreachable")
+	err := common.CriticalError(fmt.Errorf("failed to create milestone: %w", &coordinator.SigningError{Index: 42, Stage: coordinator.StageSigning, Err: cause}))
+
+	// the typed errors can be found through the wrappers
+	var signingErr *coordinator.SigningError
+	require.ErrorAs(t, err, &signingErr)
+	require.EqualValues(t, 42, signingErr.Index)
+	require.Equal(t, coordinator.StageSigning, signingErr.Stage)
+	require.ErrorIs(t, err, cause)
+	require.NotNil(t, common.IsCriticalError(err))
+
+	// empty fields of the target match all errors of that type
+	require.ErrorIs(t, err, &coordinator.SigningError{})
+	require.ErrorIs(t, err, &coordinator.SigningError{Index: 42})
+	require.ErrorIs(t, err, &coordinator.SigningError{Stage: coordinator.StageSigning})
+	require.NotErrorIs(t, err, &coordinator.SigningError{Index: 43})
+	require.NotErrorIs(t, err, &coordinator.SigningError{Stage: coordinator.StageReceipt})
+	require.NotErrorIs(t, err, &coordinator.SubmissionError{})
+}
+
+func TestSubmissionErrorClass(t *testing.T) {
+	err := common.SoftError(&coordinator.SubmissionError{Index: 7, Stage: coordinator.StageCheckpoint, Err: errors.New("connection reset")})
+	require.Equal(t, coordinator.SoftErrorClassSubmission, coordinator.SoftErrorClass(err))
+	require.Equal(t, "failed to submit checkpoint after milestone 7: connection reset", err.Error())
+
+	// the cause of the submission error is more specific
+	err = common.SoftError(&coordinator.SubmissionError{Index: 8, Stage: coordinator.StageSending, Err: coordinator.ErrMilestoneNotConfirmed})
+	require.Equal(t, coordinator.SoftErrorClassMilestoneNotConfirmed, coordinator.SoftErrorClass(err))
+}
@@ -1,6 +1,8 @@
 package coordinator
 
 import (
+	"time"
+
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
@@ -21,3 +23,51 @@ func QuorumFinishedCaller(handler interface{}, params ...interface{}) {
 	//nolint:forcetypeassert // we will replace that with generic events anyway
 	handler.(func(result *QuorumFinishedResult))(params[0].(*QuorumFinishedResult))
 }
+
+// ReceiptSignedCaller is used to signal a receipt that was signed by the treasury key.
+func ReceiptSignedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(index iotago.MilestoneIndex, signature *iotago.Ed25519Signature))(params[0].(iotago.MilestoneIndex), params[1].(*iotago.Ed25519Signature))
+}
+
+// ReceiptPendingCaller is used to signal a new receipt that will be included in the next milestone.
+func ReceiptPendingCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(index iotago.MilestoneIndex, timestamp time.Time, receipt *iotago.ReceiptMilestoneOpt))(params[0].(iotago.MilestoneIndex), params[1].(time.Time), params[2].(*iotago.ReceiptMilestoneOpt))
+}
+
+// ReceiptIssuedCaller is used to signal an issued receipt and its serialized size.
+func ReceiptIssuedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(index iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt, size int))(params[0].(iotago.MilestoneIndex), params[1].(*iotago.ReceiptMilestoneOpt), params[2].(int))
+}
+
+// ReceiptDeferredCaller is used to signal a receipt that was deferred to the next milestone.
+func ReceiptDeferredCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(index iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt))(params[0].(iotago.MilestoneIndex), params[1].(*iotago.ReceiptMilestoneOpt))
+}
+
+// QuorumNodeDemotedCaller is used to signal a quorum node that was demoted from the active quorum set.
+func QuorumNodeDemotedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(demotion *QuorumNodeDemotion))(params[0].(*QuorumNodeDemotion))
+}
+
+// MilestoneConfirmationFailedCaller is used to signal an issued milestone that was not confirmed.
+func MilestoneConfirmationFailedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(failure *MilestoneConfirmationFailure))(params[0].(*MilestoneConfirmationFailure))
+}
+
+// MilestoneSkippedCaller is used to signal a milestone that was skipped because the node is lagging behind.
+func MilestoneSkippedCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(index iotago.MilestoneIndex, err error))(params[0].(iotago.MilestoneIndex), params[1].(error))
+}
+
+// RecoveryPlanCaller is used to signal a detected gap in the issued milestones and the plan to catch up.
+func RecoveryPlanCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(plan *RecoveryPlan))(params[0].(*RecoveryPlan))
+}
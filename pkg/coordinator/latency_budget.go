@@ -0,0 +1,295 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// StageTipSelection is the selection of the milestone tips, including the checkpoint in front of the milestone.
+	StageTipSelection = "tipSelection"
+	// StageWhiteFlag is the computation of the white-flag merkle roots by the node.
+	StageWhiteFlag = "whiteFlag"
+	// StageQuorum is the check of the merkle roots by the quorum.
+	StageQuorum = "quorum"
+	// StageReceipt is the creation and the treasury signature of the receipt.
+	StageReceipt = "receipt"
+	// StageSigning is the signing of the milestone.
+	StageSigning = "signing"
+	// StageSending is the submission of the milestone until it was confirmed by the node.
+	// This stage is not part of the latency budget, because the milestone can't be degraded anymore.
+	StageSending = "sending"
+
+	// DegradationCachedTips means that the tip selection was skipped and only the latest milestone and checkpoint were referenced.
+	DegradationCachedTips = "cachedTips"
+	// DegradationReceiptSkipped means that the receipt stage was skipped, an available receipt is issued with the next milestone.
+	DegradationReceiptSkipped = "receiptSkipped"
+
+	// the tolerance of the sum of the stage shares to compensate rounding errors of the configuration.
+	stageSharesTolerance = 1e-9
+)
+
+var (
+	// ErrInvalidLatencyBudget is returned when the latency budget is configured with invalid values.
+	ErrInvalidLatencyBudget = errors.New("invalid latency budget")
+
+	// the stages that are part of the latency budget, in the order they are executed.
+	budgetedStages = []string{StageTipSelection, StageWhiteFlag, StageQuorum, StageReceipt, StageSigning}
+)
+
+// LatencyBudget is the duration that is available to issue a milestone, split across the stages of the issuance.
+type LatencyBudget struct {
+	// the duration that is available to issue a milestone.
+	Total time.Duration
+	// the durations that are available for the single stages.
+	Stages map[string]time.Duration
+}
+
+// NewLatencyBudget creates a latency budget that is the given fraction of the milestone interval.
+// The budget is split across the stages according to their shares, the shares must not sum up to more than 1.
+func NewLatencyBudget(milestoneInterval time.Duration, intervalFraction float64, stageShares map[string]float64) (*LatencyBudget, error) {
+	if intervalFraction <= 0 || intervalFraction > 1 {
+		return nil, fmt.Errorf("%w: the fraction of the milestone interval must be in (0, 1], got %v", ErrInvalidLatencyBudget, intervalFraction)
+	}
+
+	budget := &LatencyBudget{
+		Total:  time.Duration(float64(milestoneInterval) * intervalFraction),
+		Stages: make(map[string]time.Duration, len(budgetedStages)),
+	}
+
+	if err := validateStageShares(stageShares); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLatencyBudget, err)
+	}
+
+	for stage, share := range stageShares {
+		budget.Stages[stage] = time.Duration(float64(budget.Total) * share)
+	}
+
+	return budget, nil
+}
+
+// validateStageShares checks that the shares only contain known stages and don't sum up to more than 1.
+func validateStageShares(stageShares map[string]float64) error {
+	var sum float64
+	for stage, share := range stageShares {
+		if !isBudgetedStage(stage) {
+			return fmt.Errorf("unknown stage %s", stage)
+		}
+		if share < 0 {
+			return fmt.Errorf("the share of stage %s must not be negative", stage)
+		}
+
+		sum += share
+	}
+
+	if sum > 1+stageSharesTolerance {
+		return fmt.Errorf("the shares of the stages sum up to %v", sum)
+	}
+
+	return nil
+}
+
+func isBudgetedStage(stage string) bool {
+	for _, budgetedStage := range budgetedStages {
+		if stage == budgetedStage {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CumulativeBefore returns the sum of the budgets of all stages that are executed before the given stage.
+func (b *LatencyBudget) CumulativeBefore(stage string) time.Duration {
+	var cumulative time.Duration
+	for _, budgetedStage := range budgetedStages {
+		if budgetedStage == stage {
+			break
+		}
+		cumulative += b.Stages[budgetedStage]
+	}
+
+	return cumulative
+}
+
+// WithLatencyBudget defines the latency budget of the milestones.
+// If stages exceed their budget, the issuance degrades gracefully instead of delaying the milestone (nil = disabled).
+func WithLatencyBudget(latencyBudget *LatencyBudget) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.latencyBudget = latencyBudget
+	}
+}
+
+// StageTiming is the duration of a single stage of the milestone issuance.
+type StageTiming struct {
+	// the name of the stage.
+	Stage string
+	// the duration of the stage.
+	Duration time.Duration
+	// the budget of the stage (0 = not budgeted).
+	Budget time.Duration
+	// whether the stage exceeded its budget.
+	Exceeded bool
+}
+
+// MilestoneTimings contains the durations of the stages of a milestone issuance.
+type MilestoneTimings struct {
+	// the index of the milestone.
+	Index iotago.MilestoneIndex
+	// whether the milestone was issued.
+	Issued bool
+	// the duration of the whole issuance.
+	Total time.Duration
+	// the latency budget of the milestone (0 = disabled).
+	Budget time.Duration
+	// the durations of the executed stages.
+	Stages []*StageTiming
+	// the degradations that were applied to stay within the budget.
+	Degradations []string
+}
+
+// ExceededStages returns the names of the stages that exceeded their budget.
+func (t *MilestoneTimings) ExceededStages() []string {
+	exceeded := make([]string, 0)
+	for _, stage := range t.Stages {
+		if stage.Exceeded {
+			exceeded = append(exceeded, stage.Stage)
+		}
+	}
+
+	return exceeded
+}
+
+func (t *MilestoneTimings) String() string {
+	stages := make([]string, 0, len(t.Stages))
+	for _, stage := range t.Stages {
+		if stage.Budget == 0 {
+			stages = append(stages, fmt.Sprintf("%s: %v", stage.Stage, stage.Duration.Truncate(time.Millisecond)))
+
+			continue
+		}
+		stages = append(stages, fmt.Sprintf("%s: %v/%v", stage.Stage, stage.Duration.Truncate(time.Millisecond), stage.Budget.Truncate(time.Millisecond)))
+	}
+
+	result := fmt.Sprintf("milestone %d took %v (%s)", t.Index, t.Total.Truncate(time.Millisecond), strings.Join(stages, ", "))
+	if len(t.Degradations) > 0 {
+		result += fmt.Sprintf(", degraded: %s", strings.Join(t.Degradations, ", "))
+	}
+
+	return result
+}
+
+// MilestoneTimingsCaller is used to signal the timings of a milestone issuance.
+func MilestoneTimingsCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(timings *MilestoneTimings))(params[0].(*MilestoneTimings))
+}
+
+// milestoneTimer measures the stages of a milestone issuance and checks them against the latency budget.
+type milestoneTimer struct {
+	// the latency budget of the milestone (nil = disabled).
+	budget *LatencyBudget
+	// the start of the issuance, including the tip selection.
+	start time.Time
+	// the start of the current stage.
+	stageStart time.Time
+	// the measured timings.
+	timings *MilestoneTimings
+}
+
+// newMilestoneTimer creates a timer for the milestone with the given index.
+// The tip selection happens before the coordinator is asked to issue the milestone, so its duration is passed in
+// and needs to be recorded separately.
+func newMilestoneTimer(budget *LatencyBudget, index iotago.MilestoneIndex, tipSelectionDuration time.Duration) *milestoneTimer {
+	now := time.Now()
+
+	t := &milestoneTimer{
+		budget:     budget,
+		start:      now.Add(-tipSelectionDuration),
+		stageStart: now,
+		timings: &MilestoneTimings{
+			Index:        index,
+			Stages:       make([]*StageTiming, 0, len(budgetedStages)+1),
+			Degradations: make([]string, 0),
+		},
+	}
+	if budget != nil {
+		t.timings.Budget = budget.Total
+	}
+
+	return t
+}
+
+// startStage starts the measurement of the next stage.
+func (t *milestoneTimer) startStage() {
+	t.stageStart = time.Now()
+}
+
+// finishStage records the duration of the current stage and returns whether it exceeded its budget.
+func (t *milestoneTimer) finishStage(stage string) bool {
+	return t.record(stage, time.Since(t.stageStart))
+}
+
+// record records the duration of a stage and returns whether it exceeded its budget.
+func (t *milestoneTimer) record(stage string, duration time.Duration) bool {
+	timing := &StageTiming{
+		Stage:    stage,
+		Duration: duration,
+	}
+	if t.budget != nil {
+		if budget, budgeted := t.budget.Stages[stage]; budgeted {
+			timing.Budget = budget
+			timing.Exceeded = duration > budget
+		}
+	}
+	t.timings.Stages = append(t.timings.Stages, timing)
+
+	return timing.Exceeded
+}
+
+// late returns whether the stages before the given stage already used up their cumulative budget.
+func (t *milestoneTimer) late(stage string) bool {
+	if t.budget == nil {
+		return false
+	}
+
+	return time.Since(t.start) > t.budget.CumulativeBefore(stage)
+}
+
+// degrade records a degradation that was applied to stay within the budget.
+func (t *milestoneTimer) degrade(degradation string) {
+	t.timings.Degradations = append(t.timings.Degradations, degradation)
+}
+
+// finish returns the timings of the issuance.
+func (t *milestoneTimer) finish(issued bool) *MilestoneTimings {
+	t.timings.Issued = issued
+	t.timings.Total = time.Since(t.start)
+
+	return t.timings
+}
+
+// RecordTipSelection records the duration of the tip selection for the next milestone,
+// including the issuance of the checkpoint in front of the milestone.
+func (coo *Coordinator) RecordTipSelection(duration time.Duration, cachedTips bool) {
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	coo.tipSelectionDuration = duration
+	coo.tipSelectionCached = cachedTips
+}
+
+// UseCachedTips returns whether the tip selection exceeded its budget in the previous milestone.
+// In that case the next milestone only references the latest milestone and checkpoint to catch up with the budget.
+func (coo *Coordinator) UseCachedTips() bool {
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	return coo.tipSelectionExceeded
+}
@@ -0,0 +1,65 @@
+package coordinator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func TestNewLatencyBudget(t *testing.T) {
+	budget, err := coordinator.NewLatencyBudget(10*time.Second, 0.8, map[string]float64{
+		coordinator.StageTipSelection: 0.25,
+		coordinator.StageWhiteFlag:    0.25,
+		coordinator.StageReceipt:      0.5,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 8*time.Second, budget.Total)
+	require.Equal(t, 2*time.Second, budget.Stages[coordinator.StageTipSelection])
+	require.Equal(t, 4*time.Second, budget.Stages[coordinator.StageReceipt])
+
+	// stages without a share have no budget
+	require.Zero(t, budget.Stages[coordinator.StageQuorum])
+	require.Zero(t, budget.CumulativeBefore(coordinator.StageTipSelection))
+	require.Equal(t, 4*time.Second, budget.CumulativeBefore(coordinator.StageQuorum))
+	require.Equal(t, 4*time.Second, budget.CumulativeBefore(coordinator.StageReceipt))
+	require.Equal(t, 8*time.Second, budget.CumulativeBefore(coordinator.StageSigning))
+
+	// the sending stage is not budgeted
+	_, err = coordinator.NewLatencyBudget(10*time.Second, 0.8, map[string]float64{coordinator.StageSending: 0.1})
+	require.ErrorIs(t, err, coordinator.ErrInvalidLatencyBudget)
+
+	_, err = coordinator.NewLatencyBudget(10*time.Second, 0.8, map[string]float64{
+		coordinator.StageWhiteFlag: 0.6,
+		coordinator.StageSigning:   0.6,
+	})
+	require.ErrorIs(t, err, coordinator.ErrInvalidLatencyBudget)
+
+	_, err = coordinator.NewLatencyBudget(10*time.Second, 1.5, nil)
+	require.ErrorIs(t, err, coordinator.ErrInvalidLatencyBudget)
+
+	_, err = coordinator.NewLatencyBudget(10*time.Second, 0.8, map[string]float64{coordinator.StageQuorum: -0.1})
+	require.ErrorIs(t, err, coordinator.ErrInvalidLatencyBudget)
+}
+
+func TestMilestoneTimings(t *testing.T) {
+	timings := &coordinator.MilestoneTimings{
+		Index:  42,
+		Issued: true,
+		Total:  3 * time.Second,
+		Budget: 4 * time.Second,
+		Stages: []*coordinator.StageTiming{
+			{Stage: coordinator.StageTipSelection, Duration: 100 * time.Millisecond, Budget: time.Second},
+			{Stage: coordinator.StageWhiteFlag, Duration: 2 * time.Second, Budget: time.Second, Exceeded: true},
+			{Stage: coordinator.StageSigning, Duration: 500 * time.Millisecond, Budget: time.Second},
+			{Stage: coordinator.StageSending, Duration: 400 * time.Millisecond},
+		},
+		Degradations: []string{coordinator.DegradationReceiptSkipped},
+	}
+
+	require.Equal(t, []string{coordinator.StageWhiteFlag}, timings.ExceededStages())
+	require.Equal(t, "milestone 42 took 3s (tipSelection: 100ms/1s, whiteFlag: 2s/1s, signing: 500ms/1s, sending: 400ms), degraded: receiptSkipped", timings.String())
+}
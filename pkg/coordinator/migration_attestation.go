@@ -0,0 +1,138 @@
+package coordinator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrMigrationAttestationInconsistent is returned when the state of the migrator diverged from the summary of the completed migration.
+	ErrMigrationAttestationInconsistent = errors.New("migrator state is inconsistent with the migration summary")
+)
+
+// MigrationExclusion is a migration up to the last legacy milestone that is known to not be included in a receipt.
+type MigrationExclusion struct {
+	// the legacy milestone index the migration was confirmed at.
+	MigratedAt iotago.MilestoneIndex
+	// the excluded migration.
+	Entry *iotago.MigratedFundsEntry
+	// the reason why the migration is not included.
+	Reason string
+}
+
+// MigrationAttestation attests that all migrations up to the last legacy milestone were included in receipts.
+// The summary is signed by the milestone keys, the digest of the included tail transaction hashes allows consumers,
+// e.g. exchanges finalizing the migration, to check their own records of the legacy migrations against the receipts.
+type MigrationAttestation struct {
+	// the signed summary of the completed migration.
+	Summary *MigrationSummary
+	// the digest of the tail transaction hashes of all migrations that were included in receipts and the indices they were migrated at,
+	// nil if it is not known.
+	IncludedHashesDigest *migrator.IncludedHashesDigest
+	// the migrations up to the last legacy milestone that are known to not be included in a receipt,
+	// ordered by the legacy milestone index and the tail transaction hash.
+	Exclusions []*MigrationExclusion
+}
+
+// Complete returns whether all migrations up to the last legacy milestone were included in receipts without exclusions.
+func (a *MigrationAttestation) Complete() bool {
+	return len(a.Exclusions) == 0
+}
+
+// Verify checks that the summary was signed by at least minSigThreshold of the given milestone public keys
+// and that the digest matches the given records of the migrations, if any are given.
+func (a *MigrationAttestation) Verify(minSigThreshold int, publicKeys iotago.MilestonePublicKeySet, records map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex) error {
+	if err := a.Summary.Verify(minSigThreshold, publicKeys); err != nil {
+		return err
+	}
+
+	if records == nil {
+		return nil
+	}
+
+	if a.IncludedHashesDigest == nil {
+		return fmt.Errorf("%w: the attestation doesn't contain a digest of the included tail transaction hashes", ErrMigrationSummaryInvalid)
+	}
+
+	var digest migrator.IncludedHashesDigest
+	for hash, migratedAt := range records {
+		digest.AddRecord(hash, migratedAt)
+	}
+
+	if digest != *a.IncludedHashesDigest {
+		return fmt.Errorf("%w: digest %s of the %d records, attested digest %s", ErrMigrationSummaryInvalid, digest, len(records), a.IncludedHashesDigest)
+	}
+
+	return nil
+}
+
+// NewMigrationAttestation creates the attestation of the completed migration from its summary, the current state of the migrator
+// and the migrations that are held for review.
+// The state must not have advanced beyond the summary, otherwise the digest would not belong to the summary.
+func NewMigrationAttestation(summary *MigrationSummary, state migrator.State, heldMigrations []*migrator.HeldMigration) (*MigrationAttestation, error) {
+	if state.SendingReceipt {
+		return nil, fmt.Errorf("%w: a receipt is being sent", ErrMigrationAttestationInconsistent)
+	}
+
+	if state.LatestMigratedAtIndex != summary.LastMigratedAtIndex ||
+		state.MigratedEntriesCount != summary.MigratedEntriesCount ||
+		state.MigratedValue != summary.MigratedValue ||
+		state.ReceiptsCount != summary.ReceiptsCount {
+		return nil, fmt.Errorf("%w: legacy milestone %d with %d entries (value %d) in %d receipts, summary: legacy milestone %d with %d entries (value %d) in %d receipts",
+			ErrMigrationAttestationInconsistent,
+			state.LatestMigratedAtIndex, state.MigratedEntriesCount, state.MigratedValue, state.ReceiptsCount,
+			summary.LastMigratedAtIndex, summary.MigratedEntriesCount, summary.MigratedValue, summary.ReceiptsCount)
+	}
+
+	attestation := &MigrationAttestation{
+		Summary:    summary,
+		Exclusions: make([]*MigrationExclusion, 0),
+	}
+	if state.IncludedHashesDigest != nil {
+		digest := *state.IncludedHashesDigest
+		attestation.IncludedHashesDigest = &digest
+	}
+
+	for _, held := range heldMigrations {
+		if held.MigratedAt > summary.LastMigratedAtIndex {
+			continue
+		}
+
+		attestation.Exclusions = append(attestation.Exclusions, &MigrationExclusion{
+			MigratedAt: held.MigratedAt,
+			Entry:      held.Entry,
+			Reason:     fmt.Sprintf("held for review: %s", held.Reason),
+		})
+	}
+
+	sort.Slice(attestation.Exclusions, func(i, j int) bool {
+		if attestation.Exclusions[i].MigratedAt != attestation.Exclusions[j].MigratedAt {
+			return attestation.Exclusions[i].MigratedAt < attestation.Exclusions[j].MigratedAt
+		}
+
+		return bytes.Compare(attestation.Exclusions[i].Entry.TailTransactionHash[:], attestation.Exclusions[j].Entry.TailTransactionHash[:]) < 0
+	})
+
+	return attestation, nil
+}
+
+// MigrationAttestation returns the attestation that all migrations up to the last legacy milestone were included in receipts.
+// It returns ErrMigrationSummaryNotFound until the migration is completed.
+func (coo *Coordinator) MigrationAttestation() (*MigrationAttestation, error) {
+	if coo.migratorService == nil {
+		return nil, ErrMigrationSummaryNotFound
+	}
+
+	summary, err := coo.MigrationSummary()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMigrationAttestation(summary, coo.migratorService.State(), coo.migratorService.HeldMigrations())
+}
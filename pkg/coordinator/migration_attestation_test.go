@@ -0,0 +1,70 @@
+package coordinator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestMigrationAttestation(t *testing.T) {
+	summary, publicKeySet := newTestMigrationSummary(t, 2)
+
+	records := map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex{
+		{1}: 100,
+		{2}: 150,
+		{3}: 200,
+	}
+	var digest migrator.IncludedHashesDigest
+	for hash, migratedAt := range records {
+		digest.AddRecord(hash, migratedAt)
+	}
+
+	state := migrator.State{
+		LatestMigratedAtIndex: summary.LastMigratedAtIndex,
+		MigratedEntriesCount:  summary.MigratedEntriesCount,
+		MigratedValue:         summary.MigratedValue,
+		ReceiptsCount:         summary.ReceiptsCount,
+		FirstMigratedAtIndex:  summary.FirstMigratedAtIndex,
+		IncludedHashesDigest:  &digest,
+	}
+
+	attestation, err := coordinator.NewMigrationAttestation(summary, state, nil)
+	require.NoError(t, err)
+	require.True(t, attestation.Complete())
+	require.NoError(t, attestation.Verify(2, publicKeySet, nil))
+	require.NoError(t, attestation.Verify(2, publicKeySet, records))
+
+	// the records of the consumer must match the included migrations exactly
+	records[iotago.LegacyTailTransactionHash{4}] = 200
+	require.ErrorIs(t, attestation.Verify(2, publicKeySet, records), coordinator.ErrMigrationSummaryInvalid)
+	delete(records, iotago.LegacyTailTransactionHash{4})
+	records[iotago.LegacyTailTransactionHash{3}] = 199
+	require.ErrorIs(t, attestation.Verify(2, publicKeySet, records), coordinator.ErrMigrationSummaryInvalid)
+
+	// held migrations up to the last legacy milestone are listed as exclusions
+	heldMigrations := []*migrator.HeldMigration{
+		{MigratedAt: 200, Entry: &iotago.MigratedFundsEntry{TailTransactionHash: iotago.LegacyTailTransactionHash{6}}, Reason: "flagged"},
+		{MigratedAt: 150, Entry: &iotago.MigratedFundsEntry{TailTransactionHash: iotago.LegacyTailTransactionHash{5}}, Reason: "flagged"},
+		{MigratedAt: 201, Entry: &iotago.MigratedFundsEntry{TailTransactionHash: iotago.LegacyTailTransactionHash{7}}, Reason: "flagged"},
+	}
+	attestation, err = coordinator.NewMigrationAttestation(summary, state, heldMigrations)
+	require.NoError(t, err)
+	require.False(t, attestation.Complete())
+	require.Len(t, attestation.Exclusions, 2)
+	require.EqualValues(t, 150, attestation.Exclusions[0].MigratedAt)
+	require.EqualValues(t, 200, attestation.Exclusions[1].MigratedAt)
+
+	// the state must not diverge from the summary
+	state.MigratedEntriesCount++
+	_, err = coordinator.NewMigrationAttestation(summary, state, nil)
+	require.ErrorIs(t, err, coordinator.ErrMigrationAttestationInconsistent)
+
+	state.MigratedEntriesCount--
+	state.SendingReceipt = true
+	_, err = coordinator.NewMigrationAttestation(summary, state, nil)
+	require.ErrorIs(t, err, coordinator.ErrMigrationAttestationInconsistent)
+}
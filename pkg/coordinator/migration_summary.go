@@ -0,0 +1,171 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/ioutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+	iotagoEd25519 "github.com/iotaledger/iota.go/v3/ed25519"
+)
+
+var (
+	// ErrMigrationSummaryNotFound is returned when the migration is not completed yet.
+	ErrMigrationSummaryNotFound = errors.New("migration summary not found")
+	// ErrMigrationSummaryInvalid is returned when the signatures of a migration summary can't be verified.
+	ErrMigrationSummaryInvalid = errors.New("invalid migration summary")
+)
+
+// MigrationSummary is the summary of the completed legacy network migration.
+// It is signed by the milestone keys of the milestone that contains the final receipt of the last legacy milestone,
+// so it can be verified with the milestone public keys only.
+type MigrationSummary struct {
+	// the first legacy milestone index whose migrations were included in a receipt.
+	FirstMigratedAtIndex iotago.MilestoneIndex `json:"firstMigratedAtIndex"`
+	// the last legacy milestone index of the migration.
+	LastMigratedAtIndex iotago.MilestoneIndex `json:"lastMigratedAtIndex"`
+	// the amount of migrations that were included in receipts.
+	MigratedEntriesCount uint64 `json:"migratedEntriesCount"`
+	// the value of the migrations that were included in receipts.
+	MigratedValue uint64 `json:"migratedValue"`
+	// the amount of issued receipts.
+	ReceiptsCount uint64 `json:"receiptsCount"`
+	// the index of the milestone that contains the final receipt.
+	MilestoneIndex iotago.MilestoneIndex `json:"milestoneIndex"`
+	// the ID of the milestone that contains the final receipt.
+	MilestoneID iotago.MilestoneID `json:"milestoneId"`
+	// the signatures of the milestone keys over the essence of the summary.
+	Signatures []*iotago.Ed25519Signature `json:"signatures"`
+}
+
+// Essence returns the hash of the summary without the signatures, which gets signed by the milestone keys.
+func (s *MigrationSummary) Essence() []byte {
+	data := make([]byte, 0, 4+4+8+8+8+4+iotago.MilestoneIDLength)
+	data = binary.LittleEndian.AppendUint32(data, s.FirstMigratedAtIndex)
+	data = binary.LittleEndian.AppendUint32(data, s.LastMigratedAtIndex)
+	data = binary.LittleEndian.AppendUint64(data, s.MigratedEntriesCount)
+	data = binary.LittleEndian.AppendUint64(data, s.MigratedValue)
+	data = binary.LittleEndian.AppendUint64(data, s.ReceiptsCount)
+	data = binary.LittleEndian.AppendUint32(data, s.MilestoneIndex)
+	data = append(data, s.MilestoneID[:]...)
+
+	essence := blake2b.Sum256(data)
+
+	return essence[:]
+}
+
+// Verify checks that the summary was signed by at least minSigThreshold of the given milestone public keys.
+func (s *MigrationSummary) Verify(minSigThreshold int, publicKeys iotago.MilestonePublicKeySet) error {
+	essence := s.Essence()
+
+	seenPublicKeys := make(map[iotago.MilestonePublicKey]struct{}, len(s.Signatures))
+	for _, signature := range s.Signatures {
+		if _, known := publicKeys[signature.PublicKey]; !known {
+			return fmt.Errorf("%w: unknown public key %s", ErrMigrationSummaryInvalid, iotago.EncodeHex(signature.PublicKey[:]))
+		}
+		if _, seen := seenPublicKeys[signature.PublicKey]; seen {
+			return fmt.Errorf("%w: duplicated signature of public key %s", ErrMigrationSummaryInvalid, iotago.EncodeHex(signature.PublicKey[:]))
+		}
+		if !iotagoEd25519.Verify(signature.PublicKey[:], essence, signature.Signature[:]) {
+			return fmt.Errorf("%w: signature of public key %s is invalid", ErrMigrationSummaryInvalid, iotago.EncodeHex(signature.PublicKey[:]))
+		}
+		seenPublicKeys[signature.PublicKey] = struct{}{}
+	}
+
+	if len(seenPublicKeys) < minSigThreshold {
+		return fmt.Errorf("%w: only %d of %d required signatures", ErrMigrationSummaryInvalid, len(seenPublicKeys), minSigThreshold)
+	}
+
+	return nil
+}
+
+// MigrationSummaryCaller is used to signal the summary of the completed migration.
+func MigrationSummaryCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(summary *MigrationSummary))(params[0].(*MigrationSummary))
+}
+
+// WithMigrationSummary defines the last legacy milestone index of the migration (0 = disabled).
+// Once the final receipt of that legacy milestone was issued, a signed summary of the migration is written to the given file.
+func WithMigrationSummary(lastLegacyMilestoneIndex iotago.MilestoneIndex, filePath string) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.lastLegacyMilestoneIndex = lastLegacyMilestoneIndex
+		c.migrationSummaryFilePath = filePath
+	}
+}
+
+// MigrationSummary returns the signed summary of the completed migration.
+func (coo *Coordinator) MigrationSummary() (*MigrationSummary, error) {
+	if coo.migrationSummaryFilePath == "" {
+		return nil, ErrMigrationSummaryNotFound
+	}
+
+	if _, err := os.Stat(coo.migrationSummaryFilePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrMigrationSummaryNotFound
+		}
+
+		return nil, err
+	}
+
+	summary := &MigrationSummary{}
+	if err := ioutils.ReadJSONFromFile(coo.migrationSummaryFilePath, summary); err != nil {
+		return nil, fmt.Errorf("unable to load migration summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// completesMigration returns whether the receipt is the final receipt of the last legacy milestone.
+func (coo *Coordinator) completesMigration(receipt *iotago.ReceiptMilestoneOpt) bool {
+	return coo.migratorService != nil && coo.lastLegacyMilestoneIndex != 0 && receipt.Final && receipt.MigratedAt == coo.lastLegacyMilestoneIndex
+}
+
+// createMigrationSummary signs the summary of the completed migration with the keys of the milestone
+// that contains the final receipt and writes it to disk.
+func (coo *Coordinator) createMigrationSummary(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID) (*MigrationSummary, error) {
+	state := coo.migratorService.State()
+
+	summary := &MigrationSummary{
+		FirstMigratedAtIndex: state.FirstMigratedAtIndex,
+		LastMigratedAtIndex:  state.LatestMigratedAtIndex,
+		MigratedEntriesCount: state.MigratedEntriesCount,
+		MigratedValue:        state.MigratedValue,
+		ReceiptsCount:        state.ReceiptsCount,
+		MilestoneIndex:       index,
+		MilestoneID:          milestoneID,
+	}
+
+	milestoneIndexSigner := coo.signerProvider.MilestoneIndexSigner(index)
+	pubKeys := milestoneIndexSigner.PublicKeys()
+
+	sigs, err := coo.createSigningFuncWithRetries(context.Background(), milestoneIndexSigner.SigningFunc())(pubKeys, summary.Essence())
+	if err != nil {
+		return nil, &SigningError{Index: index, Stage: StageMigrationSummary, Err: err}
+	}
+
+	if len(sigs) != len(pubKeys) {
+		return nil, &SigningError{Index: index, Stage: StageMigrationSummary, Err: iotago.ErrMilestoneProducedSignaturesCountMismatch}
+	}
+
+	summary.Signatures = make([]*iotago.Ed25519Signature, len(sigs))
+	for i, sig := range sigs {
+		summary.Signatures[i] = &iotago.Ed25519Signature{PublicKey: pubKeys[i], Signature: sig}
+	}
+
+	if err := summary.Verify(coo.signerProvider.PublicKeysCount(), milestoneIndexSigner.PublicKeysSet()); err != nil {
+		return nil, &SigningError{Index: index, Stage: StageMigrationSummary, Err: err}
+	}
+
+	if err := ioutils.WriteJSONToFile(coo.migrationSummaryFilePath, summary, 0o600); err != nil {
+		return nil, fmt.Errorf("storing migration summary failed: %w", err)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,65 @@
+package coordinator_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func newTestMigrationSummary(t *testing.T, keysCount int) (*coordinator.MigrationSummary, iotago.MilestonePublicKeySet) {
+	summary := &coordinator.MigrationSummary{
+		FirstMigratedAtIndex: 100,
+		LastMigratedAtIndex:  200,
+		MigratedEntriesCount: 1234,
+		MigratedValue:        5_000_000_000,
+		ReceiptsCount:        56,
+		MilestoneIndex:       789,
+		MilestoneID:          iotago.MilestoneID{1},
+	}
+
+	publicKeySet := iotago.MilestonePublicKeySet{}
+	for i := 0; i < keysCount; i++ {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		signature := &iotago.Ed25519Signature{}
+		copy(signature.PublicKey[:], publicKey)
+		copy(signature.Signature[:], ed25519.Sign(privateKey, summary.Essence()))
+
+		publicKeySet[signature.PublicKey] = struct{}{}
+		summary.Signatures = append(summary.Signatures, signature)
+	}
+
+	return summary, publicKeySet
+}
+
+func TestMigrationSummaryVerify(t *testing.T) {
+	summary, publicKeySet := newTestMigrationSummary(t, 2)
+	require.NoError(t, summary.Verify(2, publicKeySet))
+
+	// not enough signatures
+	require.ErrorIs(t, summary.Verify(3, publicKeySet), coordinator.ErrMigrationSummaryInvalid)
+
+	// duplicated signatures don't count twice
+	duplicated := *summary
+	duplicated.Signatures = []*iotago.Ed25519Signature{summary.Signatures[0], summary.Signatures[0]}
+	require.ErrorIs(t, duplicated.Verify(2, publicKeySet), coordinator.ErrMigrationSummaryInvalid)
+
+	// unknown keys are rejected
+	_, otherPublicKeySet := newTestMigrationSummary(t, 2)
+	require.ErrorIs(t, summary.Verify(2, otherPublicKeySet), coordinator.ErrMigrationSummaryInvalid)
+
+	// every field is part of the essence
+	tampered := *summary
+	tampered.MigratedValue++
+	require.ErrorIs(t, tampered.Verify(2, publicKeySet), coordinator.ErrMigrationSummaryInvalid)
+
+	tampered = *summary
+	tampered.ReceiptsCount--
+	require.ErrorIs(t, tampered.Verify(2, publicKeySet), coordinator.ErrMigrationSummaryInvalid)
+}
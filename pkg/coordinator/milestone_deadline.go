@@ -0,0 +1,161 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrInvalidMilestoneDeadline is returned when the milestone deadline is configured with invalid values.
+	ErrInvalidMilestoneDeadline = errors.New("invalid milestone deadline")
+	// ErrMilestoneDeadlineExceeded is returned when a stage of the milestone issuance was aborted because it exceeded its timeout.
+	ErrMilestoneDeadlineExceeded = errors.New("milestone deadline exceeded")
+
+	// the stages that are bounded by their share of the milestone deadline, in the order they are executed.
+	// the sending has no share, it is bounded by the remainder of the deadline.
+	deadlineStages = []string{StageTipSelection, StageWhiteFlag, StageQuorum, StageReceipt, StageSigning}
+)
+
+// MilestoneDeadline is the duration a milestone issuance must be finished or aborted within.
+// The timeouts of the single stages are derived from it, so that configuring the deadline bounds the whole issuance.
+type MilestoneDeadline struct {
+	// the duration the issuance must be finished or aborted within.
+	Total time.Duration
+	// the timeouts of the single stages.
+	Stages map[string]time.Duration
+}
+
+// NewMilestoneDeadline creates a milestone deadline and derives the timeouts of the stages from it.
+// The deadline is split across the stages according to the same shares as the latency budget, see NewLatencyBudget.
+// The deadline must not exceed the milestone interval, so that the issuance is finished before the next milestone is due.
+func NewMilestoneDeadline(milestoneInterval time.Duration, deadline time.Duration, stageShares map[string]float64) (*MilestoneDeadline, error) {
+	if deadline <= 0 || deadline > milestoneInterval {
+		return nil, fmt.Errorf("%w: the deadline must be in (0, %v], got %v", ErrInvalidMilestoneDeadline, milestoneInterval, deadline)
+	}
+
+	if err := validateStageShares(stageShares); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMilestoneDeadline, err)
+	}
+
+	milestoneDeadline := &MilestoneDeadline{
+		Total:  deadline,
+		Stages: make(map[string]time.Duration, len(deadlineStages)),
+	}
+
+	for _, stage := range deadlineStages {
+		milestoneDeadline.Stages[stage] = time.Duration(float64(deadline) * stageShares[stage])
+	}
+
+	return milestoneDeadline, nil
+}
+
+// WithMilestoneDeadline defines the deadline of the milestone issuance (nil = disabled).
+// Stages that exceed their timeout are aborted, the milestone is issued without the receipt or not at all.
+// A milestone that was already signed is never reissued with another content, so that there are never two milestones
+// with the same index. It is not sent again once the deadline is exceeded, the issuance fails with a critical error instead.
+// A send that is already in progress is not interrupted, because the node might already have received the milestone.
+func WithMilestoneDeadline(milestoneDeadline *MilestoneDeadline) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.milestoneDeadline = milestoneDeadline
+	}
+}
+
+// TipSelectionTimeout returns the duration the tip selection of a milestone must be finished within (0 = no timeout).
+// The tip selection happens before the coordinator is asked to issue the milestone, so the caller needs to check it.
+func (coo *Coordinator) TipSelectionTimeout() time.Duration {
+	if coo.milestoneDeadline == nil {
+		return 0
+	}
+
+	return coo.milestoneDeadline.Stages[StageTipSelection]
+}
+
+// issuanceDeadline applies the milestone deadline to the stages of a single milestone issuance.
+type issuanceDeadline struct {
+	// the milestone deadline (nil = disabled).
+	deadline *MilestoneDeadline
+	// the index of the milestone.
+	index iotago.MilestoneIndex
+	// the time the issuance must be finished or aborted.
+	end time.Time
+}
+
+// newIssuanceDeadline creates the deadline of the issuance of the milestone with the given index that started at the given time.
+func newIssuanceDeadline(deadline *MilestoneDeadline, index iotago.MilestoneIndex, start time.Time) *issuanceDeadline {
+	d := &issuanceDeadline{
+		deadline: deadline,
+		index:    index,
+	}
+	if deadline != nil {
+		d.end = start.Add(deadline.Total)
+	}
+
+	return d
+}
+
+// stageContext returns a context that is done once the given stage exceeded its timeout or the issuance exceeded the deadline.
+func (d *issuanceDeadline) stageContext(stage string) (context.Context, context.CancelFunc) {
+	if d.deadline == nil {
+		return context.WithCancel(context.Background())
+	}
+
+	stageEnd := time.Now().Add(d.deadline.Stages[stage])
+	if stageEnd.After(d.end) {
+		stageEnd = d.end
+	}
+
+	return context.WithDeadline(context.Background(), stageEnd)
+}
+
+// remainderContext returns a context derived from the given one that is done once the issuance exceeded the deadline.
+func (d *issuanceDeadline) remainderContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.deadline == nil {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithDeadline(ctx, d.end)
+}
+
+// exceeded returns the error of a stage that was aborted because the given context is done.
+func (d *issuanceDeadline) exceeded(ctx context.Context, stage string) error {
+	return fmt.Errorf("%w: stage %s of milestone %d aborted: %v", ErrMilestoneDeadlineExceeded, stage, d.index, ctx.Err())
+}
+
+// withSigningContext returns a signing function that is abandoned once the given context is done.
+// A signing function can't be canceled, so a late signature is dropped.
+func withSigningContext(ctx context.Context, signingFunc iotago.MilestoneSigningFunc) iotago.MilestoneSigningFunc {
+	if ctx.Done() == nil {
+		return signingFunc
+	}
+
+	type signingResult struct {
+		sigs []iotago.MilestoneSignature
+		err  error
+	}
+
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%w: signing aborted: %v", ErrMilestoneDeadlineExceeded, err)
+		}
+
+		// buffered, so that an abandoned signing function doesn't block forever
+		resultChan := make(chan *signingResult, 1)
+		go func() {
+			sigs, err := signingFunc(pubKeys, msEssence)
+			resultChan <- &signingResult{sigs: sigs, err: err}
+		}()
+
+		select {
+		case result := <-resultChan:
+			return result.sigs, result.err
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: signing aborted: %v", ErrMilestoneDeadlineExceeded, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package coordinator_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/timeutil"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// testStageShares are the default shares of the stages of the latency budget.
+var testStageShares = map[string]float64{
+	coordinator.StageTipSelection: 0.2,
+	coordinator.StageWhiteFlag:    0.3,
+	coordinator.StageQuorum:       0.2,
+	coordinator.StageReceipt:      0.1,
+	coordinator.StageSigning:      0.2,
+}
+
+func TestNewMilestoneDeadline(t *testing.T) {
+	deadline, err := coordinator.NewMilestoneDeadline(10*time.Second, 8*time.Second, testStageShares)
+	require.NoError(t, err)
+	require.Equal(t, 8*time.Second, deadline.Total)
+
+	// the bounded stages are split like the latency budget, the rest is left for the state files and the sending
+	var sum time.Duration
+	for _, stage := range []string{coordinator.StageTipSelection, coordinator.StageWhiteFlag, coordinator.StageQuorum, coordinator.StageReceipt, coordinator.StageSigning} {
+		require.Equal(t, time.Duration(float64(8*time.Second)*testStageShares[stage]), deadline.Stages[stage], stage)
+		sum += deadline.Stages[stage]
+	}
+	require.LessOrEqual(t, sum, deadline.Total)
+	// the sending is bounded by the remainder of the deadline
+	require.NotContains(t, deadline.Stages, coordinator.StageSending)
+
+	_, err = coordinator.NewMilestoneDeadline(10*time.Second, 10*time.Second, testStageShares)
+	require.NoError(t, err)
+
+	_, err = coordinator.NewMilestoneDeadline(10*time.Second, 11*time.Second, testStageShares)
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneDeadline)
+
+	_, err = coordinator.NewMilestoneDeadline(10*time.Second, 0, testStageShares)
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneDeadline)
+
+	// the shares are checked like the ones of the latency budget
+	_, err = coordinator.NewMilestoneDeadline(10*time.Second, 8*time.Second, map[string]float64{coordinator.StageSending: 0.1})
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneDeadline)
+
+	_, err = coordinator.NewMilestoneDeadline(10*time.Second, 8*time.Second, map[string]float64{
+		coordinator.StageQuorum:  0.6,
+		coordinator.StageSigning: 0.6,
+	})
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneDeadline)
+}
+
+// hangingSignerProvider provides signers that never answer while hanging is set.
+type hangingSignerProvider struct {
+	coordinator.MilestoneSignerProvider
+	hanging *atomic.Bool
+}
+
+func (p *hangingSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) coordinator.MilestoneIndexSigner {
+	return &hangingSigner{MilestoneIndexSigner: p.MilestoneSignerProvider.MilestoneIndexSigner(index), hanging: p.hanging}
+}
+
+type hangingSigner struct {
+	coordinator.MilestoneIndexSigner
+	hanging *atomic.Bool
+}
+
+func (s *hangingSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	signingFunc := s.MilestoneIndexSigner.SigningFunc()
+
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		for s.hanging.Load() {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		return signingFunc(pubKeys, msEssence)
+	}
+}
+
+// deadlineNode simulates the parts of the node and the signer that can exceed a milestone deadline.
+type deadlineNode struct {
+	// the signer doesn't answer while set.
+	signerHanging atomic.Bool
+	// the node doesn't answer milestone blocks while set.
+	nodeHanging atomic.Bool
+	// the node doesn't compute the merkle roots while set.
+	whiteFlagHanging atomic.Bool
+	// the node doesn't confirm milestones while set.
+	notConfirmed atomic.Bool
+	// the amount of milestone blocks that were sent.
+	sentMilestones atomic.Uint32
+}
+
+// newDeadlineCoordinator creates a bootstrapped coordinator with the given milestone deadline and options,
+// whose signer and node never answer while the flags of the returned node are set.
+func newDeadlineCoordinator(t *testing.T, deadline time.Duration, opts ...options.Option[coordinator.Coordinator]) (*coordinator.Coordinator, iotago.BlockID, *deadlineNode) {
+	t.Helper()
+
+	node := &deadlineNode{}
+
+	var sentBlocks atomic.Uint32
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if len(msIndex) > 0 {
+			for node.nodeHanging.Load() {
+				time.Sleep(10 * time.Millisecond)
+			}
+			node.sentMilestones.Add(1)
+			if node.notConfirmed.Load() {
+				return iotago.BlockID{byte(sentBlocks.Add(1))}, coordinator.ErrMilestoneNotConfirmed
+			}
+		}
+
+		return iotago.BlockID{byte(sentBlocks.Add(1))}, nil
+	}
+
+	merkleRoots := func(ctx context.Context, index iotago.MilestoneIndex, _ uint32, _ iotago.BlockIDs, _ iotago.MilestoneID) (*coordinator.MilestoneMerkleRoots, error) {
+		for node.whiteFlagHanging.Load() {
+			if !timeutil.Sleep(ctx, 10*time.Millisecond) {
+				return nil, ctx.Err()
+			}
+		}
+
+		return &coordinator.MilestoneMerkleRoots{
+			InclusionMerkleRoot: iotago.MilestoneMerkleProof{byte(index)},
+			AppliedMerkleRoot:   iotago.MilestoneMerkleProof{byte(index), 1},
+		}, nil
+	}
+
+	milestoneDeadline, err := coordinator.NewMilestoneDeadline(10*time.Second, deadline, testStageShares)
+	require.NoError(t, err)
+
+	coo, milestoneBlockID := (&testCoordinatorDeps{
+		signerProvider: &hangingSignerProvider{
+			MilestoneSignerProvider: newTestSignerProvider(t, 1),
+			hanging:                 &node.signerHanging,
+		},
+		merkleRootsFunc: merkleRoots,
+	}).newCoordinator(t, sendBlock, append([]options.Option[coordinator.Coordinator]{coordinator.WithMilestoneDeadline(milestoneDeadline)}, opts...)...)
+
+	return coo, milestoneBlockID, node
+}
+
+func TestMilestoneDeadlineAbortsWhiteFlag(t *testing.T) {
+	coo, milestoneBlockID, node := newDeadlineCoordinator(t, 500*time.Millisecond)
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	node.whiteFlagHanging.Store(true)
+	defer node.whiteFlagHanging.Store(false)
+	node.sentMilestones.Store(0)
+
+	start := time.Now()
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.Less(t, time.Since(start), time.Second)
+
+	// the milestone was not signed, so it is issued with the next interval
+	require.ErrorIs(t, err, coordinator.ErrMilestoneDeadlineExceeded)
+	require.NotNil(t, common.IsSoftError(err))
+	require.Zero(t, node.sentMilestones.Load())
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+
+	node.whiteFlagHanging.Store(false)
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	require.Equal(t, latestMilestoneIndex+1, coo.State().LatestMilestoneIndex)
+}
+
+func TestMilestoneDeadlineAbortsSigning(t *testing.T) {
+	coo, milestoneBlockID, node := newDeadlineCoordinator(t, 500*time.Millisecond)
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	node.signerHanging.Store(true)
+	defer node.signerHanging.Store(false)
+
+	start := time.Now()
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.Less(t, time.Since(start), time.Second)
+
+	// the milestone was not sent, so it is issued with the next interval
+	require.ErrorIs(t, err, coordinator.ErrMilestoneDeadlineExceeded)
+	require.NotNil(t, common.IsSoftError(err))
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+
+	node.signerHanging.Store(false)
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	require.Equal(t, latestMilestoneIndex+1, coo.State().LatestMilestoneIndex)
+}
+
+func TestMilestoneDeadlineDoesNotAbortSending(t *testing.T) {
+	coo, milestoneBlockID, node := newDeadlineCoordinator(t, 500*time.Millisecond)
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	node.nodeHanging.Store(true)
+	defer node.nodeHanging.Store(false)
+
+	issued := make(chan error, 1)
+	go func() {
+		_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+		issued <- err
+	}()
+
+	// the node might already have received the milestone, so a send in progress is not interrupted by the deadline
+	select {
+	case err := <-issued:
+		require.FailNow(t, "the milestone was abandoned", "error: %v", err)
+	case <-time.After(time.Second):
+	}
+
+	node.nodeHanging.Store(false)
+	require.NoError(t, <-issued)
+	require.Equal(t, latestMilestoneIndex+1, coo.State().LatestMilestoneIndex)
+}
+
+func TestMilestoneDeadlineStopsResending(t *testing.T) {
+	coo, milestoneBlockID, node := newDeadlineCoordinator(t, 500*time.Millisecond,
+		coordinator.WithConfirmationCheck(1, func() iotago.MilestoneIndex { return 0 }),
+		coordinator.WithMilestoneResend(coordinator.DefaultMilestoneSendAttempts, 100*time.Millisecond),
+	)
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+
+	node.notConfirmed.Store(true)
+	node.sentMilestones.Store(0)
+
+	start := time.Now()
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.Less(t, time.Since(start), time.Second)
+
+	// the milestone is not sent again once the deadline is exceeded, the coordinator needs to be stopped
+	require.ErrorIs(t, err, coordinator.ErrMilestoneNotConfirmed)
+	require.NotNil(t, common.IsCriticalError(err))
+	require.Less(t, node.sentMilestones.Load(), uint32(coordinator.DefaultMilestoneSendAttempts))
+	require.Greater(t, node.sentMilestones.Load(), uint32(1))
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+}
+
+// hangingTreasurySigner is a treasury signer that never answers while hanging is set.
+type hangingTreasurySigner struct {
+	coordinator.TreasurySigner
+	hanging *atomic.Bool
+}
+
+func (s *hangingTreasurySigner) SigningFunc() iotago.MilestoneSigningFunc {
+	signingFunc := s.TreasurySigner.SigningFunc()
+
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		for s.hanging.Load() {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		return signingFunc(pubKeys, msEssence)
+	}
+}
+
+func TestMilestoneDeadlineReceiptPending(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	entries := []*iotago.MigratedFundsEntry{
+		{
+			TailTransactionHash: iotago.LegacyTailTransactionHash{1},
+			Address:             &iotago.Ed25519Address{2},
+			Deposit:             1_000_000,
+		},
+	}
+
+	migratorService := migrator.NewService(&testMigrationsQueryer{migratedAt: 2, entries: entries}, filepath.Join(t.TempDir(), "migrator.state"), 0)
+	legacyIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, migratorService.InitState(ctx, &legacyIndex))
+
+	_, treasuryPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	treasuryHanging := &atomic.Bool{}
+	treasuryHanging.Store(true)
+	defer treasuryHanging.Store(false)
+
+	milestoneDeadline, err := coordinator.NewMilestoneDeadline(10*time.Second, 500*time.Millisecond, testStageShares)
+	require.NoError(t, err)
+
+	coo, milestoneBlockID := (&testCoordinatorDeps{migratorService: migratorService}).newCoordinator(t, nil,
+		coordinator.WithTreasurySigner(&hangingTreasurySigner{
+			TreasurySigner: coordinator.NewInMemoryEd25519TreasurySigner(treasuryPrivateKey),
+			hanging:        treasuryHanging,
+		}),
+		coordinator.WithMilestoneDeadline(milestoneDeadline),
+	)
+
+	var pending, issued int
+	coo.Events.ReceiptPending.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, _ time.Time, _ *iotago.ReceiptMilestoneOpt) {
+		pending++
+	}))
+	coo.Events.ReceiptIssued.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, _ *iotago.ReceiptMilestoneOpt, _ int) {
+		issued++
+	}))
+
+	migratorCtx, migratorCancel := context.WithCancel(ctx)
+	migratorDone := make(chan struct{})
+	go func() {
+		defer close(migratorDone)
+		migratorService.Start(migratorCtx, func(err error) bool { return false })
+	}()
+	defer func() {
+		migratorCancel()
+		<-migratorDone
+	}()
+
+	// milestones are issued until the receipt was taken from the migrator and its signing exceeded the deadline
+	require.Eventually(t, func() bool {
+		blockID, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+		if errors.Is(err, coordinator.ErrMilestoneDeadlineExceeded) {
+			return true
+		}
+		require.NoError(t, err)
+		milestoneBlockID = blockID
+
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, pending)
+
+	// the pending receipt is reissued with the next milestone, but it is only signaled once
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.ErrorIs(t, err, coordinator.ErrMilestoneDeadlineExceeded)
+
+	treasuryHanging.Store(false)
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	require.Equal(t, 1, pending)
+	require.Equal(t, 1, issued)
+}
@@ -0,0 +1,39 @@
+package coordinator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrInvalidMilestoneMetadata is returned when the configured milestone metadata is invalid.
+	ErrInvalidMilestoneMetadata = errors.New("invalid milestone metadata")
+)
+
+// ParseMilestoneMetadata parses the configured milestone metadata.
+// The metadata is hex decoded if it is prefixed with "0x", otherwise the raw string is used.
+func ParseMilestoneMetadata(metadata string) ([]byte, error) {
+	if metadata == "" {
+		return nil, nil
+	}
+
+	var metadataBytes []byte
+	if strings.HasPrefix(metadata, "0x") {
+		var err error
+		if metadataBytes, err = iotago.DecodeHex(metadata); err != nil {
+			return nil, fmt.Errorf("%w: unable to decode hex: %s", ErrInvalidMilestoneMetadata, err)
+		}
+	} else {
+		metadataBytes = []byte(metadata)
+	}
+
+	if len(metadataBytes) > iotago.MaxMetadataLength {
+		return nil, fmt.Errorf("%w: exceeds the maximum length (%d > %d)", ErrInvalidMilestoneMetadata, len(metadataBytes), iotago.MaxMetadataLength)
+	}
+
+	return metadataBytes, nil
+}
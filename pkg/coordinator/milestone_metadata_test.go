@@ -0,0 +1,44 @@
+package coordinator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestParseMilestoneMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		expected []byte
+		valid    bool
+	}{
+		{name: "empty", metadata: "", expected: nil, valid: true},
+		{name: "plain", metadata: "coo-v1.0.0", expected: []byte("coo-v1.0.0"), valid: true},
+		{name: "hex", metadata: "0x0102ff", expected: []byte{0x01, 0x02, 0xff}, valid: true},
+		{name: "empty hex", metadata: "0x", expected: []byte{}, valid: true},
+		{name: "uppercase hex prefix is plain", metadata: "0X0102", expected: []byte("0X0102"), valid: true},
+		{name: "plain at maximum length", metadata: strings.Repeat("a", iotago.MaxMetadataLength), expected: []byte(strings.Repeat("a", iotago.MaxMetadataLength)), valid: true},
+		{name: "hex at maximum length", metadata: "0x" + strings.Repeat("ab", iotago.MaxMetadataLength), expected: []byte(strings.Repeat("\xab", iotago.MaxMetadataLength)), valid: true},
+		{name: "plain exceeds maximum length", metadata: strings.Repeat("a", iotago.MaxMetadataLength+1), valid: false},
+		{name: "hex exceeds maximum length", metadata: "0x" + strings.Repeat("ab", iotago.MaxMetadataLength+1), valid: false},
+		{name: "invalid hex", metadata: "0xzz", valid: false},
+		{name: "odd length hex", metadata: "0x123", valid: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata, err := coordinator.ParseMilestoneMetadata(test.metadata)
+			if !test.valid {
+				require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneMetadata)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, metadata)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package coordinator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrInvalidMilestoneOptions is returned when the additional options of a milestone violate the milestone option rules.
+	ErrInvalidMilestoneOptions = errors.New("invalid milestone options")
+	// ErrMilestoneTooLarge is returned when a milestone block exceeds the maximum block size.
+	ErrMilestoneTooLarge = errors.New("milestone exceeds the maximum block size")
+	// ErrUnknownProtocolParameters is returned when the protocol parameters that were valid at a milestone index are not known anymore.
+	ErrUnknownProtocolParameters = errors.New("unknown protocol parameters")
+)
+
+// MilestoneOptionsFunc returns the additional options, e.g. protocol parameter updates, the milestone with the given index has to contain.
+// The additional options are bound to a milestone index, so they take priority over receipts.
+// A receipt that doesn't fit into the milestone alongside them is deferred to the next milestone.
+type MilestoneOptionsFunc func(index iotago.MilestoneIndex) (iotago.MilestoneOpts, error)
+
+// WithMilestoneOptions defines the function that returns the additional options of the milestones (nil = no additional options).
+func WithMilestoneOptions(milestoneOptionsFunc MilestoneOptionsFunc) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.milestoneOptionsFunc = milestoneOptionsFunc
+	}
+}
+
+// ProtocolParametersUpdate defines protocol parameters that are announced by a milestone before they become active.
+type ProtocolParametersUpdate struct {
+	// the index of the milestone that announces the protocol parameters.
+	AnnouncementIndex iotago.MilestoneIndex `json:"announcementIndex" koanf:"announcementIndex" usage:"the index of the milestone that announces the protocol parameters"`
+	// the milestone index from which on the protocol parameters are active.
+	TargetIndex iotago.MilestoneIndex `json:"targetIndex" koanf:"targetIndex" usage:"the milestone index from which on the protocol parameters are active"`
+	// the protocol version of the protocol parameters.
+	ProtocolVersion byte `json:"protocolVersion" koanf:"protocolVersion" usage:"the protocol version of the protocol parameters"`
+	// the serialized protocol parameters (hex encoded with 0x prefix).
+	Params string `json:"params" koanf:"params" usage:"the serialized protocol parameters (hex encoded with 0x prefix)"`
+}
+
+// NewProtocolParametersUpdatesFunc returns a MilestoneOptionsFunc that embeds every protocol parameters update
+// into the milestone with its announcement index. It returns nil if there are no updates.
+func NewProtocolParametersUpdatesFunc(updates []*ProtocolParametersUpdate) (MilestoneOptionsFunc, error) {
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	announcements := make(map[iotago.MilestoneIndex]*iotago.ProtocolParamsMilestoneOpt, len(updates))
+	for _, update := range updates {
+		params, err := iotago.DecodeHex(update.Params)
+		if err != nil {
+			return nil, fmt.Errorf("%w: protocol parameters targeting milestone %d are not hex encoded: %s", ErrInvalidMilestoneOptions, update.TargetIndex, err)
+		}
+
+		if _, exists := announcements[update.AnnouncementIndex]; exists {
+			return nil, fmt.Errorf("%w: several protocol parameters are announced by milestone %d", ErrInvalidMilestoneOptions, update.AnnouncementIndex)
+		}
+
+		opt := &iotago.ProtocolParamsMilestoneOpt{
+			TargetMilestoneIndex: update.TargetIndex,
+			ProtocolVersion:      update.ProtocolVersion,
+			Params:               params,
+		}
+		if err := ValidateMilestoneOptions(update.AnnouncementIndex, iotago.MilestoneOpts{opt}); err != nil {
+			return nil, err
+		}
+
+		announcements[update.AnnouncementIndex] = opt
+	}
+
+	return func(index iotago.MilestoneIndex) (iotago.MilestoneOpts, error) {
+		opt, exists := announcements[index]
+		if !exists {
+			return nil, nil
+		}
+
+		return iotago.MilestoneOpts{opt}, nil
+	}, nil
+}
+
+// ProtocolParametersAtFunc returns the protocol parameters that were valid at the milestone with the given index.
+type ProtocolParametersAtFunc func(index iotago.MilestoneIndex) (*iotago.ProtocolParameters, error)
+
+// activeProtocolParameters are protocol parameters that are valid from the target index on.
+type activeProtocolParameters struct {
+	targetIndex iotago.MilestoneIndex
+	params      *iotago.ProtocolParameters
+	// the serialized protocol parameters, to recognize them in the protocol parameters of the node.
+	rawParams []byte
+}
+
+// NewProtocolParametersAtFunc returns a ProtocolParametersAtFunc that returns the protocol parameters of the latest
+// protocol parameters update that is active at the given index. Before the first update, the protocol parameters
+// of the node are valid, unless the node already switched to the protocol parameters of an update.
+func NewProtocolParametersAtFunc(updates []*ProtocolParametersUpdate, protoParamsFunc ProtocolParameteresFunc) (ProtocolParametersAtFunc, error) {
+	active := make([]*activeProtocolParameters, 0, len(updates))
+	for _, update := range updates {
+		rawParams, err := iotago.DecodeHex(update.Params)
+		if err != nil {
+			return nil, fmt.Errorf("%w: protocol parameters targeting milestone %d are not hex encoded: %s", ErrInvalidMilestoneOptions, update.TargetIndex, err)
+		}
+
+		params := &iotago.ProtocolParameters{}
+		if _, err := params.Deserialize(rawParams, serializer.DeSeriModePerformValidation, nil); err != nil {
+			return nil, fmt.Errorf("%w: protocol parameters targeting milestone %d can't be deserialized: %s", ErrInvalidMilestoneOptions, update.TargetIndex, err)
+		}
+
+		active = append(active, &activeProtocolParameters{targetIndex: update.TargetIndex, params: params, rawParams: rawParams})
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].targetIndex < active[j].targetIndex })
+
+	return func(index iotago.MilestoneIndex) (*iotago.ProtocolParameters, error) {
+		for i := len(active) - 1; i >= 0; i-- {
+			if active[i].targetIndex <= index {
+				return active[i].params, nil
+			}
+		}
+
+		protoParams := protoParamsFunc()
+		if len(active) == 0 {
+			return protoParams, nil
+		}
+
+		rawProtoParams, err := protoParams.Serialize(serializer.DeSeriModeNoValidation, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to serialize the protocol parameters of the node: %w", err)
+		}
+
+		// the protocol parameters that were valid before the update are not known anymore
+		for _, update := range active {
+			if bytes.Equal(update.rawParams, rawProtoParams) {
+				return nil, fmt.Errorf("%w: milestone %d is older than the protocol parameters targeting milestone %d, which the node already uses", ErrUnknownProtocolParameters, index, update.targetIndex)
+			}
+		}
+
+		return protoParams, nil
+	}, nil
+}
+
+// milestoneOptions returns the validated additional options of the milestone with the given index.
+func (coo *Coordinator) milestoneOptions(index iotago.MilestoneIndex) (iotago.MilestoneOpts, error) {
+	if coo.milestoneOptionsFunc == nil {
+		return nil, nil
+	}
+
+	opts, err := coo.milestoneOptionsFunc(index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the options of milestone %d: %w", index, err)
+	}
+
+	if err := ValidateMilestoneOptions(index, opts); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// ValidateMilestoneOptions checks the additional options of the milestone with the given index.
+// Receipts are created by the migrator, so they are not allowed as additional options,
+// and every option type may only be contained once.
+func ValidateMilestoneOptions(index iotago.MilestoneIndex, opts iotago.MilestoneOpts) error {
+	seen := make(map[iotago.MilestoneOptType]struct{}, len(opts))
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case *iotago.ReceiptMilestoneOpt:
+			return fmt.Errorf("%w: receipts are only issued by the migrator", ErrInvalidMilestoneOptions)
+
+		case *iotago.ProtocolParamsMilestoneOpt:
+			// the parameters need to be announced before they become active
+			if opt.TargetMilestoneIndex <= index {
+				return fmt.Errorf("%w: protocol parameters of milestone %d target past milestone %d", ErrInvalidMilestoneOptions, index, opt.TargetMilestoneIndex)
+			}
+			if len(opt.Params) > iotago.MaxParamsLength {
+				return fmt.Errorf("%w: protocol parameters exceed the maximum length (%d > %d)", ErrInvalidMilestoneOptions, len(opt.Params), iotago.MaxParamsLength)
+			}
+
+		default:
+			return fmt.Errorf("%w: unsupported option type %d", ErrInvalidMilestoneOptions, opt.Type())
+		}
+
+		if _, exists := seen[opt.Type()]; exists {
+			return fmt.Errorf("%w: duplicate option type %d", ErrInvalidMilestoneOptions, opt.Type())
+		}
+		seen[opt.Type()] = struct{}{}
+	}
+
+	return nil
+}
+
+// combineMilestoneOptions returns the receipt and the additional options in the order the milestone needs to contain them.
+// The options must be ordered lexically by their serialized form, which starts with their type.
+func combineMilestoneOptions(receipt *iotago.ReceiptMilestoneOpt, opts iotago.MilestoneOpts) iotago.MilestoneOpts {
+	if receipt == nil && len(opts) == 0 {
+		return nil
+	}
+
+	combined := make(iotago.MilestoneOpts, 0, len(opts)+1)
+	if receipt != nil {
+		combined = append(combined, receipt)
+	}
+	combined = append(combined, opts...)
+
+	sort.SliceStable(combined, func(i, j int) bool {
+		return combined[i].Type() < combined[j].Type()
+	})
+
+	return combined
+}
+
+// milestoneBlockSize returns the serialized size of the block of the milestone with the given parameters,
+// signed by the given amount of signatures.
+func milestoneBlockSize(protocolAdapter ProtocolAdapter, params *MilestoneParameters, signaturesCount int, protoParams *iotago.ProtocolParameters) (int, error) {
+	msPayload := protocolAdapter.NewMilestone(params)
+	msPayload.Signatures = make(iotago.Signatures, signaturesCount)
+	for i := range msPayload.Signatures {
+		msPayload.Signatures[i] = &iotago.Ed25519Signature{}
+	}
+
+	iotaBlock := &iotago.Block{
+		ProtocolVersion: protocolAdapter.Version(),
+		Parents:         params.Parents,
+		Payload:         msPayload,
+	}
+
+	data, err := iotaBlock.Serialize(serializer.DeSeriModeNoValidation, protoParams)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// checkMilestoneSize checks that the block of the milestone with the given parameters doesn't exceed the maximum block size
+// once it is signed by the given amount of signatures.
+func checkMilestoneSize(protocolAdapter ProtocolAdapter, params *MilestoneParameters, signaturesCount int, protoParams *iotago.ProtocolParameters) error {
+	size, err := milestoneBlockSize(protocolAdapter, params, signaturesCount, protoParams)
+	if errors.Is(err, iotago.ErrBlockExceedsMaxSize) {
+		// the block is not serialized at all if it is too large
+		return fmt.Errorf("%w: milestone %d (%s)", ErrMilestoneTooLarge, params.Index, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if size > iotago.BlockBinSerializedMaxSize {
+		return fmt.Errorf("%w: milestone %d (%d > %d)", ErrMilestoneTooLarge, params.Index, size, iotago.BlockBinSerializedMaxSize)
+	}
+
+	return nil
+}
+
+// deferReceipt returns whether the receipt needs to be deferred to the next milestone,
+// because it doesn't fit into the milestone with the given parameters alongside the additional options.
+func (coo *Coordinator) deferReceipt(params *MilestoneParameters) (bool, error) {
+	if params.Receipt == nil || len(params.Opts) == 0 {
+		return false, nil
+	}
+
+	protoParams := coo.protoParamsFunc()
+	protocolAdapter := coo.protocolAdapters.AdapterAt(params.Index)
+	signaturesCount := len(coo.signerProvider.MilestoneIndexSigner(params.Index).PublicKeys())
+
+	// the additional options need to fit on their own, otherwise the milestone can't be issued at all
+	withoutReceipt := *params
+	withoutReceipt.Receipt = nil
+	if err := checkMilestoneSize(protocolAdapter, &withoutReceipt, signaturesCount, protoParams); err != nil {
+		return false, err
+	}
+
+	if err := checkMilestoneSize(protocolAdapter, params, signaturesCount, protoParams); err != nil {
+		if errors.Is(err, ErrMilestoneTooLarge) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	return false, nil
+}
+
+// keepPendingReceipt keeps the receipt of a milestone that was not sent, so that it is issued with the next milestone.
+// A milestone without a receipt doesn't replace a receipt that is already pending, e.g. because it was deferred.
+func (coo *Coordinator) keepPendingReceipt(receipt *iotago.ReceiptMilestoneOpt) {
+	if receipt != nil {
+		coo.pendingReceipt = receipt
+	}
+}
@@ -0,0 +1,300 @@
+package coordinator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestValidateMilestoneOptions(t *testing.T) {
+	protoParamsOpt := &iotago.ProtocolParamsMilestoneOpt{TargetMilestoneIndex: 20, ProtocolVersion: 3, Params: []byte{1, 2, 3}}
+
+	require.NoError(t, coordinator.ValidateMilestoneOptions(10, nil))
+	require.NoError(t, coordinator.ValidateMilestoneOptions(10, iotago.MilestoneOpts{protoParamsOpt}))
+
+	// receipts are only issued by the migrator
+	err := coordinator.ValidateMilestoneOptions(10, iotago.MilestoneOpts{&iotago.ReceiptMilestoneOpt{}})
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneOptions)
+
+	// the parameters need to be announced before they become active
+	err = coordinator.ValidateMilestoneOptions(20, iotago.MilestoneOpts{protoParamsOpt})
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneOptions)
+
+	err = coordinator.ValidateMilestoneOptions(10, iotago.MilestoneOpts{protoParamsOpt, protoParamsOpt})
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneOptions)
+
+	err = coordinator.ValidateMilestoneOptions(10, iotago.MilestoneOpts{&iotago.ProtocolParamsMilestoneOpt{TargetMilestoneIndex: 20, Params: make([]byte, iotago.MaxParamsLength+1)}})
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneOptions)
+}
+
+func TestStardustMilestoneOptionsOrdering(t *testing.T) {
+	adapter := &coordinator.StardustProtocolAdapter{}
+
+	receipt := &iotago.ReceiptMilestoneOpt{MigratedAt: 7}
+	protoParamsOpt := &iotago.ProtocolParamsMilestoneOpt{TargetMilestoneIndex: 50, ProtocolVersion: 3}
+
+	milestone := adapter.NewMilestone(&coordinator.MilestoneParameters{
+		Index:       42,
+		Parents:     iotago.BlockIDs{{1}},
+		MerkleRoots: &coordinator.MilestoneMerkleRoots{},
+		Receipt:     receipt,
+		Opts:        iotago.MilestoneOpts{protoParamsOpt},
+	})
+
+	// the options are ordered by their type, so the receipt comes first
+	require.Equal(t, iotago.MilestoneOpts{receipt, protoParamsOpt}, milestone.Opts)
+
+	milestone = adapter.NewMilestone(&coordinator.MilestoneParameters{
+		Index:       42,
+		Parents:     iotago.BlockIDs{{1}},
+		MerkleRoots: &coordinator.MilestoneMerkleRoots{},
+	})
+	require.Nil(t, milestone.Opts)
+}
+
+// newMilestoneOptionsCoordinator creates a bootstrapped coordinator that issues its milestones with the given options
+// and returns the sent milestones.
+func newMilestoneOptionsCoordinator(t *testing.T, milestoneOptionsFunc coordinator.MilestoneOptionsFunc) (*coordinator.Coordinator, iotago.BlockID, *[]*iotago.Milestone) {
+	t.Helper()
+
+	sentMilestones := make([]*iotago.Milestone, 0)
+	coo, milestoneBlockID := newTestCoordinator(t, sentMilestonesSendBlock(&sentMilestones), coordinator.WithMilestoneOptions(milestoneOptionsFunc))
+
+	return coo, milestoneBlockID, &sentMilestones
+}
+
+// sentMilestonesSendBlock returns a function that sends blocks and collects the sent milestones.
+func sentMilestonesSendBlock(sentMilestones *[]*iotago.Milestone) coordinator.SendBlockFunc {
+	return func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		if milestone, ok := block.Payload.(*iotago.Milestone); ok {
+			*sentMilestones = append(*sentMilestones, milestone)
+		}
+
+		return iotago.BlockID{byte(len(*sentMilestones))}, nil
+	}
+}
+
+func TestIssueMilestoneWithOptions(t *testing.T) {
+	protoParamsOpt := &iotago.ProtocolParamsMilestoneOpt{TargetMilestoneIndex: 100, ProtocolVersion: 3, Params: []byte{1, 2, 3}}
+
+	var invalid bool
+	coo, milestoneBlockID, sentMilestones := newMilestoneOptionsCoordinator(t, func(index iotago.MilestoneIndex) (iotago.MilestoneOpts, error) {
+		if invalid {
+			return iotago.MilestoneOpts{&iotago.ProtocolParamsMilestoneOpt{TargetMilestoneIndex: index}}, nil
+		}
+
+		return iotago.MilestoneOpts{protoParamsOpt}, nil
+	})
+
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+
+	lastMilestone := (*sentMilestones)[len(*sentMilestones)-1]
+	require.Equal(t, coo.State().LatestMilestoneIndex, lastMilestone.Index)
+	require.Equal(t, iotago.MilestoneOpts{protoParamsOpt}, lastMilestone.Opts)
+
+	// a milestone with invalid options must never be signed
+	invalid = true
+	latestMilestoneIndex := coo.State().LatestMilestoneIndex
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneOptions)
+	require.NotNil(t, common.IsCriticalError(err))
+	require.Equal(t, latestMilestoneIndex, coo.State().LatestMilestoneIndex)
+}
+
+func TestNewProtocolParametersUpdatesFunc(t *testing.T) {
+	milestoneOptionsFunc, err := coordinator.NewProtocolParametersUpdatesFunc(nil)
+	require.NoError(t, err)
+	require.Nil(t, milestoneOptionsFunc)
+
+	milestoneOptionsFunc, err = coordinator.NewProtocolParametersUpdatesFunc([]*coordinator.ProtocolParametersUpdate{
+		{AnnouncementIndex: 10, TargetIndex: 20, ProtocolVersion: 3, Params: "0x010203"},
+	})
+	require.NoError(t, err)
+
+	opts, err := milestoneOptionsFunc(10)
+	require.NoError(t, err)
+	require.Equal(t, iotago.MilestoneOpts{&iotago.ProtocolParamsMilestoneOpt{TargetMilestoneIndex: 20, ProtocolVersion: 3, Params: []byte{1, 2, 3}}}, opts)
+
+	// only the announcing milestone contains the protocol parameters
+	opts, err = milestoneOptionsFunc(11)
+	require.NoError(t, err)
+	require.Nil(t, opts)
+
+	for name, updates := range map[string][]*coordinator.ProtocolParametersUpdate{
+		"not hex encoded":       {{AnnouncementIndex: 10, TargetIndex: 20, Params: "010203"}},
+		"target not in future":  {{AnnouncementIndex: 10, TargetIndex: 10, Params: "0x01"}},
+		"several announcements": {{AnnouncementIndex: 10, TargetIndex: 20, Params: "0x01"}, {AnnouncementIndex: 10, TargetIndex: 30, Params: "0x02"}},
+	} {
+		_, err := coordinator.NewProtocolParametersUpdatesFunc(updates)
+		require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneOptions, name)
+	}
+}
+
+func TestNewProtocolParametersAtFunc(t *testing.T) {
+	updatedProtoParams := *testProtoParams
+	updatedProtoParams.Version = 3
+	updatedProtoParams.MinPoWScore = 2000
+	rawUpdatedProtoParams, err := updatedProtoParams.Serialize(serializer.DeSeriModeNoValidation, nil)
+	require.NoError(t, err)
+
+	nodeProtoParams := testProtoParams
+	protoParamsAtFunc, err := coordinator.NewProtocolParametersAtFunc([]*coordinator.ProtocolParametersUpdate{
+		{AnnouncementIndex: 10, TargetIndex: 20, ProtocolVersion: 3, Params: iotago.EncodeHex(rawUpdatedProtoParams)},
+	}, func() *iotago.ProtocolParameters { return nodeProtoParams })
+	require.NoError(t, err)
+
+	// the protocol parameters of the node are valid until the update is active
+	protoParams, err := protoParamsAtFunc(19)
+	require.NoError(t, err)
+	require.Equal(t, testProtoParams, protoParams)
+
+	protoParams, err = protoParamsAtFunc(20)
+	require.NoError(t, err)
+	require.Equal(t, &updatedProtoParams, protoParams)
+
+	// once the node switched to the update, the protocol parameters before it are not known anymore
+	nodeProtoParams = &updatedProtoParams
+	_, err = protoParamsAtFunc(19)
+	require.ErrorIs(t, err, coordinator.ErrUnknownProtocolParameters)
+
+	protoParams, err = protoParamsAtFunc(25)
+	require.NoError(t, err)
+	require.Equal(t, &updatedProtoParams, protoParams)
+
+	_, err = coordinator.NewProtocolParametersAtFunc([]*coordinator.ProtocolParametersUpdate{
+		{AnnouncementIndex: 10, TargetIndex: 20, ProtocolVersion: 3, Params: "0x010203"},
+	}, func() *iotago.ProtocolParameters { return testProtoParams })
+	require.ErrorIs(t, err, coordinator.ErrInvalidMilestoneOptions)
+}
+
+// testMigrationsQueryer returns the migrations of a single legacy milestone.
+type testMigrationsQueryer struct {
+	migratedAt iotago.MilestoneIndex
+	entries    []*iotago.MigratedFundsEntry
+}
+
+func (q *testMigrationsQueryer) QueryMigratedFunds(_ context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	if msIndex == q.migratedAt {
+		return q.entries, nil
+	}
+
+	return nil, nil
+}
+
+func (q *testMigrationsQueryer) QueryNextMigratedFunds(_ context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	if startIndex <= q.migratedAt {
+		return q.migratedAt, q.entries, nil
+	}
+
+	return q.migratedAt, nil, nil
+}
+
+func TestDeferredReceiptRestart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// a receipt with the maximum amount of entries, which needs to be configured explicitly
+	entries := make([]*iotago.MigratedFundsEntry, iotago.MaxMigratedFundsEntryCount)
+	for i := range entries {
+		entries[i] = &iotago.MigratedFundsEntry{
+			TailTransactionHash: iotago.LegacyTailTransactionHash{byte(i / 2), byte(i % 2)},
+			Address:             &iotago.Ed25519Address{byte(i)},
+			Deposit:             1_000_000,
+		}
+	}
+
+	migratorStateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+	migratorService := migrator.NewService(&testMigrationsQueryer{migratedAt: 2, entries: entries}, migratorStateFilePath, iotago.MaxMigratedFundsEntryCount)
+	legacyIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, migratorService.InitState(ctx, &legacyIndex))
+	require.NoError(t, migratorService.PersistState(ctx, false))
+
+	// the receipt only fits into milestones without protocol parameters if they are signed by many keys
+	signerProvider := newTestSignerProvider(t, 64)
+
+	sentMilestones := make([]*iotago.Milestone, 0)
+	sendBlock := sentMilestonesSendBlock(&sentMilestones)
+
+	announce := true
+	protoParamsOpt := &iotago.ProtocolParamsMilestoneOpt{TargetMilestoneIndex: 1000, ProtocolVersion: 3, Params: make([]byte, iotago.MaxParamsLength)}
+
+	coordinatorStateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+	newCoordinatorDeps := func(migratorService *migrator.Service) *testCoordinatorDeps {
+		return &testCoordinatorDeps{
+			signerProvider:  signerProvider,
+			migratorService: migratorService,
+			stateFilePath:   coordinatorStateFilePath,
+		}
+	}
+	coordinatorOpts := []options.Option[coordinator.Coordinator]{
+		coordinator.WithMilestoneMetadata(make([]byte, iotago.MaxMetadataLength)),
+		coordinator.WithMilestoneOptions(func(index iotago.MilestoneIndex) (iotago.MilestoneOpts, error) {
+			if !announce {
+				return nil, nil
+			}
+
+			return iotago.MilestoneOpts{protoParamsOpt}, nil
+		}),
+	}
+
+	coo, milestoneBlockID := newCoordinatorDeps(migratorService).newCoordinator(t, sendBlock, coordinatorOpts...)
+
+	var deferred *iotago.ReceiptMilestoneOpt
+	coo.Events.ReceiptDeferred.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt) {
+		deferred = receipt
+	}))
+
+	migratorCtx, migratorCancel := context.WithCancel(ctx)
+	migratorDone := make(chan struct{})
+	go func() {
+		defer close(migratorDone)
+		migratorService.Start(migratorCtx, func(err error) bool { return false })
+	}()
+
+	// the milestones are issued without the receipt until the migrator passes it to the coordinator
+	require.Eventually(t, func() bool {
+		blockID, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+		require.NoError(t, err)
+		milestoneBlockID = blockID
+
+		return deferred != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lastMilestone := sentMilestones[len(sentMilestones)-1]
+	require.Equal(t, iotago.MilestoneOpts{protoParamsOpt}, lastMilestone.Opts)
+	require.False(t, migratorService.SendingReceiptWindow().Active)
+
+	migratorCancel()
+	<-migratorDone
+
+	// the deferred receipt is lost on a restart, so its migrations need to be fetched again
+	restartedMigratorService := migrator.NewService(&testMigrationsQueryer{migratedAt: 2, entries: entries}, migratorStateFilePath, iotago.MaxMigratedFundsEntryCount)
+	require.NoError(t, restartedMigratorService.InitState(ctx, nil))
+	require.EqualValues(t, 1, restartedMigratorService.State().LatestMigratedAtIndex)
+
+	restartedCoo := newCoordinatorDeps(restartedMigratorService).createCoordinator(t, sendBlock, coordinatorOpts...)
+	require.NoError(t, restartedCoo.InitState(false, 0, &coordinator.LatestMilestoneInfo{Index: coo.State().LatestMilestoneIndex}))
+
+	// without the protocol parameters, the deferred receipt is issued with the next milestone
+	announce = false
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+
+	lastMilestone = sentMilestones[len(sentMilestones)-1]
+	require.Len(t, lastMilestone.Opts, 1)
+	require.Equal(t, deferred, lastMilestone.Opts[0])
+	require.EqualValues(t, 2, migratorService.State().LatestMigratedAtIndex)
+	require.False(t, migratorService.State().SendingReceipt)
+}
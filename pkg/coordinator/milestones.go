@@ -1,23 +1,39 @@
 package coordinator
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/iotaledger/hive.go/serializer/v2"
 	iotago "github.com/iotaledger/iota.go/v3"
-	builder "github.com/iotaledger/iota.go/v3/builder"
 )
 
-// createCheckpoint creates a checkpoint block.
-func (coo *Coordinator) createCheckpoint(parents iotago.BlockIDs) (*iotago.Block, error) {
+// MilestonePoWStepSize returns the largest milestone block size that needs two trailing zero trits less than a block
+// of the maximum size with the given minimum PoW score, i.e. a ninth of its work. The PoW score is divided by the block size,
+// so every additional trailing zero trit triples the work (e.g. 3^16 / 4000 = 10761 bytes, 3^15 / 1500 = 9565 bytes).
+// The receipts are limited by default so that their milestones stay below that step.
+// Without a minimum PoW score there are no steps, so the maximum block size is returned.
+func MilestonePoWStepSize(minPoWScore uint32) int {
+	if minPoWScore == 0 {
+		return iotago.BlockBinSerializedMaxSize
+	}
+
+	// the work of the trailing zero trits a block of the maximum size needs
+	work := uint64(1)
+	for work < uint64(minPoWScore)*iotago.BlockBinSerializedMaxSize {
+		work *= 3
+	}
+
+	return int(work / 9 / uint64(minPoWScore))
+}
+
+// createCheckpoint creates a checkpoint block in front of the milestone with the given index.
+func (coo *Coordinator) createCheckpoint(index iotago.MilestoneIndex, parents iotago.BlockIDs) (*iotago.Block, error) {
 
 	protoParams := coo.protoParamsFunc()
 
-	iotaBlock, err := builder.
-		NewBlockBuilder().
-		ProtocolVersion(protoParams.Version).
-		Parents(parents).
-		Build()
+	iotaBlock, err := coo.protocolAdapters.AdapterAt(index).NewBlock(parents, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -31,40 +47,36 @@ func (coo *Coordinator) createCheckpoint(parents iotago.BlockIDs) (*iotago.Block
 	return iotaBlock, nil
 }
 
-// createMilestone creates a signed milestone block.
-func (coo *Coordinator) createMilestone(index iotago.MilestoneIndex, timestamp uint32, parents iotago.BlockIDs, receipt *iotago.ReceiptMilestoneOpt, previousMilestoneID iotago.MilestoneID, merkleProof *MilestoneMerkleRoots) (*iotago.Block, error) {
+// createMilestone creates a signed milestone block, the signing is abandoned once the given context is done.
+// The combined size of the options is checked before the milestone is signed.
+func (coo *Coordinator) createMilestone(ctx context.Context, params *MilestoneParameters) (*iotago.Block, error) {
+	index, parents := params.Index, params.Parents
 	milestoneIndexSigner := coo.signerProvider.MilestoneIndexSigner(index)
 	pubKeys := milestoneIndexSigner.PublicKeys()
 
-	confMerkleRoot := [iotago.MilestoneMerkleProofLength]byte{}
-	copy(confMerkleRoot[:], merkleProof.InclusionMerkleRoot[:])
-	appliedMerkleRoot := [iotago.MilestoneMerkleProofLength]byte{}
-	copy(appliedMerkleRoot[:], merkleProof.AppliedMerkleRoot[:])
-
 	protoParams := coo.protoParamsFunc()
+	protocolAdapter := coo.protocolAdapters.AdapterAt(index)
 
-	msPayload := iotago.NewMilestone(index, timestamp, protoParams.Version, previousMilestoneID, parents, confMerkleRoot, appliedMerkleRoot)
-
-	if receipt != nil {
-		msPayload.Opts = iotago.MilestoneOpts{receipt}
+	// the signers must not be asked for signatures of a milestone that can't be sent
+	if err := checkMilestoneSize(protocolAdapter, params, len(pubKeys), protoParams); err != nil {
+		return nil, err
 	}
 
-	iotaBlock, err := builder.
-		NewBlockBuilder().
-		ProtocolVersion(protoParams.Version).
-		Parents(parents).
-		Payload(msPayload).
-		Build()
+	msPayload := protocolAdapter.NewMilestone(params)
+
+	iotaBlock, err := protocolAdapter.NewBlock(parents, msPayload)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := msPayload.Sign(pubKeys, coo.createSigningFuncWithRetries(milestoneIndexSigner.SigningFunc())); err != nil {
-		return nil, err
+	if err := msPayload.Sign(pubKeys, coo.createSigningFuncWithRetries(ctx, milestoneIndexSigner.SigningFunc())); err != nil {
+		return nil, &SigningError{Index: index, Stage: StageSigning, Err: err}
 	}
 
+	// the applicable public keys only contain the keys that are valid for the milestone index according to their key ranges,
+	// so milestones with a signature of a key outside its validity window are rejected.
 	if err = msPayload.VerifySignatures(coo.signerProvider.PublicKeysCount(), milestoneIndexSigner.PublicKeysSet()); err != nil {
-		return nil, err
+		return nil, &SigningError{Index: index, Stage: StageSigning, Err: err}
 	}
 
 	// Perform validation
@@ -75,18 +87,128 @@ func (coo *Coordinator) createMilestone(index iotago.MilestoneIndex, timestamp u
 	return iotaBlock, nil
 }
 
+// maxReceiptSize returns the maximum serialized size of a receipt, so that a milestone containing it does not
+// exceed the given block size. The rest of the milestone block is assumed to have its maximum size,
+// i.e. the configured amount of parents and a signature of every milestone key.
+// The smallest size of all protocol versions is used, because receipts are created before the milestone index is known.
+// Additional milestone options are not taken into account, a receipt that doesn't fit alongside them is deferred.
+func (coo *Coordinator) maxReceiptSize(blockSize int) (int, error) {
+
+	protoParams := coo.protoParamsFunc()
+
+	maxReceiptSize := blockSize
+	for _, protocolAdapter := range coo.protocolAdapters.all() {
+		// the milestone options length prefix is already part of the serialized block
+		milestoneSize, err := milestoneBlockSize(protocolAdapter, &MilestoneParameters{
+			Parents:     make(iotago.BlockIDs, coo.milestoneParentsCount),
+			MerkleRoots: &MilestoneMerkleRoots{},
+			Metadata:    coo.milestoneMetadata,
+		}, coo.signerProvider.PublicKeysCount(), protoParams)
+		if err != nil {
+			return 0, err
+		}
+
+		if size := blockSize - milestoneSize; size < maxReceiptSize {
+			maxReceiptSize = size
+		}
+	}
+
+	return maxReceiptSize, nil
+}
+
+// receiptLimitsKey are the parameters the receipt limits of the migrator are derived from.
+type receiptLimitsKey struct {
+	minPoWScore     uint32
+	publicKeysCount int
+}
+
+// updateReceiptLimits derives the receipt limits of the migrator from the current protocol parameters and the amount of
+// milestone keys. The limits are only recomputed if these changed, e.g. after a protocol parameters update.
+// The amount of milestone keys bounds the signatures of every signer committee, so committee changes never exceed the limits.
+func (coo *Coordinator) updateReceiptLimits() error {
+	if coo.migratorService == nil {
+		return nil
+	}
+
+	key := receiptLimitsKey{
+		minPoWScore:     coo.protoParamsFunc().MinPoWScore,
+		publicKeysCount: coo.signerProvider.PublicKeysCount(),
+	}
+	if coo.receiptLimitsKey != nil && *coo.receiptLimitsKey == key {
+		return nil
+	}
+
+	// the receipts need to fit into the milestones
+	maxReceiptSize, err := coo.maxReceiptSize(iotago.BlockBinSerializedMaxSize)
+	if err != nil {
+		return fmt.Errorf("failed to compute the maximum receipt size: %w", err)
+	}
+
+	// by default, the milestones containing receipts stay below the next PoW requirement step
+	powStepReceiptSize, err := coo.maxReceiptSize(MilestonePoWStepSize(key.minPoWScore))
+	if err != nil {
+		return fmt.Errorf("failed to compute the receipt size below the PoW requirement step: %w", err)
+	}
+
+	coo.migratorService.SetMaxReceiptSize(maxReceiptSize)
+	coo.migratorService.SetPoWStepReceiptSize(powStepReceiptSize)
+	coo.receiptLimitsKey = &key
+
+	return nil
+}
+
+// signReceipt signs the receipt of the milestone with the given index with the treasury key and verifies the resulting signature.
+// The signing is abandoned once the given context is done.
+func (coo *Coordinator) signReceipt(ctx context.Context, index iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt) (*iotago.Ed25519Signature, error) {
+
+	essence, err := coo.protocolAdapters.AdapterAt(index).ReceiptEssence(receipt, coo.protoParamsFunc())
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := coo.treasurySigner.PublicKey()
+
+	sigs, err := coo.createSigningFuncWithRetries(ctx, coo.treasurySigner.SigningFunc())([]iotago.MilestonePublicKey{publicKey}, essence)
+	if err != nil {
+		return nil, fmt.Errorf("unable to produce treasury signature: %w", err)
+	}
+
+	if len(sigs) != 1 {
+		return nil, fmt.Errorf("%w: treasury signer did not provide exactly one signature", iotago.ErrMilestoneProducedSignaturesCountMismatch)
+	}
+
+	signature := &iotago.Ed25519Signature{PublicKey: publicKey, Signature: sigs[0]}
+	if err := VerifyTreasurySignature(publicKey, essence, signature); err != nil {
+		return nil, err
+	}
+
+	return signature, nil
+}
+
 // wraps the given MilestoneSigningFunc into a with retries enhanced version.
-func (coo *Coordinator) createSigningFuncWithRetries(signingFunc iotago.MilestoneSigningFunc) iotago.MilestoneSigningFunc {
+// Once the given context is done, the signing is abandoned and not retried anymore.
+func (coo *Coordinator) createSigningFuncWithRetries(ctx context.Context, signingFunc iotago.MilestoneSigningFunc) iotago.MilestoneSigningFunc {
+	signingFunc = withSigningContext(ctx, signingFunc)
+
 	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) (sigs []iotago.MilestoneSignature, err error) {
 		if coo.signingRetryAmount <= 0 {
-			return signingFunc(pubKeys, msEssence)
+			sigs, err = signingFunc(pubKeys, msEssence)
+			coo.recordSigningResult(err)
+
+			return sigs, err
 		}
 		for i := 0; i < coo.signingRetryAmount; i++ {
 			sigs, err = signingFunc(pubKeys, msEssence)
+			coo.recordSigningResult(err)
 			if err != nil {
 				if i+1 != coo.signingRetryAmount {
 					coo.LogWarnf("signing attempt failed: %s, retrying in %v, retries left %d", err, coo.signingRetryTimeout, coo.signingRetryAmount-(i+1))
-					time.Sleep(coo.signingRetryTimeout)
+
+					select {
+					case <-time.After(coo.signingRetryTimeout):
+					case <-ctx.Done():
+						return nil, fmt.Errorf("%w: signing retries aborted: %v", ErrMilestoneDeadlineExceeded, err)
+					}
 				}
 
 				continue
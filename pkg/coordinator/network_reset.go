@@ -0,0 +1,26 @@
+package coordinator
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+var (
+	// ErrInvalidNetworkReset is returned when the coordinator is bootstrapped for a network reset, but the node doesn't match a reset network.
+	ErrInvalidNetworkReset = errors.New("invalid network reset")
+)
+
+// WithNetworkReset defines whether the coordinator is bootstrapped on a network reset.
+// On a network reset the milestone index continues from the start index, but the node doesn't know any milestone
+// of the previous network, so the first milestone doesn't reference a previous milestone.
+func WithNetworkReset(networkReset bool) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.networkReset = networkReset
+	}
+}
+
+// NetworkResetIndex returns the index of the first milestone after the latest network reset (0 = the network was never reset).
+func (coo *Coordinator) NetworkResetIndex() uint32 {
+	return coo.state.NetworkResetIndex
+}
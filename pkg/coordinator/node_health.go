@@ -0,0 +1,194 @@
+package coordinator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrNodeUnhealthy is returned when the node reports that it is unhealthy or busy pruning.
+	ErrNodeUnhealthy = errors.New("node is unhealthy")
+)
+
+// NodeHealth is the health reported by the node the coordinator is connected to.
+type NodeHealth struct {
+	// whether the node reports itself as healthy.
+	Healthy bool
+	// the index up to which the node pruned the milestones.
+	PruningIndex iotago.MilestoneIndex
+}
+
+// NodeHealthFunc should return the current health of the node.
+type NodeHealthFunc = func() *NodeHealth
+
+// ReceiptPause is a period in which no receipts are issued because the node is unhealthy.
+type ReceiptPause struct {
+	// the index of the milestone the receipts were paused with.
+	Index iotago.MilestoneIndex
+	// the reason the receipts were paused.
+	Reason string
+	// the time the receipts were paused.
+	Since time.Time
+	// the index of the milestone the receipts were resumed with (0 = still paused).
+	ResumedIndex iotago.MilestoneIndex
+	// the duration of the pause, only set once the receipts were resumed.
+	Duration time.Duration
+}
+
+// ReceiptPauseCaller is used to signal a pause of the receipts that started or ended.
+func ReceiptPauseCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(pause *ReceiptPause))(params[0].(*ReceiptPause))
+}
+
+// NodeHealthMonitor decides whether receipts are paused, based on the health the node reports before every milestone.
+// The node is considered to be pruning if its pruning index advances by more than the given step between two milestones,
+// e.g. while it prunes a large part of its database at once.
+type NodeHealthMonitor struct {
+	// used to get the health of the node.
+	healthFunc NodeHealthFunc
+	// the maximum amount of milestones the pruning index may advance per milestone (0 = pruning is ignored).
+	maxPruningStep uint32
+
+	// lock protects the fields below.
+	lock syncutils.RWMutex
+	// the index of the milestone the health was checked for the last time.
+	lastIndex iotago.MilestoneIndex
+	// the pruning index of the node at the last check.
+	lastPruningIndex iotago.MilestoneIndex
+	// the current pause of the receipts, nil if receipts are issued.
+	pause *ReceiptPause
+}
+
+// NewNodeHealthMonitor creates a new NodeHealthMonitor.
+func NewNodeHealthMonitor(healthFunc NodeHealthFunc, maxPruningStep uint32) *NodeHealthMonitor {
+	return &NodeHealthMonitor{
+		healthFunc:     healthFunc,
+		maxPruningStep: maxPruningStep,
+	}
+}
+
+// unhealthyReason returns why the node is unhealthy before the milestone with the given index, or an empty string if it is healthy.
+func (m *NodeHealthMonitor) unhealthyReason(health *NodeHealth, index iotago.MilestoneIndex) string {
+	if !health.Healthy {
+		return "node reports unhealthy"
+	}
+
+	// the pruning index is unknown before the first check
+	if m.maxPruningStep == 0 || m.lastIndex == 0 || health.PruningIndex <= m.lastPruningIndex {
+		return ""
+	}
+
+	milestones := uint32(1)
+	if index > m.lastIndex {
+		milestones = index - m.lastIndex
+	}
+
+	if step := health.PruningIndex - m.lastPruningIndex; step > milestones*m.maxPruningStep {
+		return fmt.Sprintf("node is pruning (pruned %d milestones up to %d)", step, health.PruningIndex)
+	}
+
+	return ""
+}
+
+// Update checks the health of the node before the milestone with the given index is issued.
+// It returns the pause that started or ended with this milestone, both are nil if nothing changed.
+func (m *NodeHealthMonitor) Update(index iotago.MilestoneIndex, now time.Time) (started *ReceiptPause, ended *ReceiptPause) {
+	health := m.healthFunc()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	reason := m.unhealthyReason(health, index)
+	m.lastIndex = index
+	m.lastPruningIndex = health.PruningIndex
+
+	switch {
+	case reason != "" && m.pause == nil:
+		m.pause = &ReceiptPause{
+			Index:  index,
+			Reason: reason,
+			Since:  now,
+		}
+		pause := *m.pause
+
+		return &pause, nil
+
+	case reason == "" && m.pause != nil:
+		pause := *m.pause
+		pause.ResumedIndex = index
+		pause.Duration = now.Sub(pause.Since)
+		m.pause = nil
+
+		return nil, &pause
+
+	default:
+		return nil, nil
+	}
+}
+
+// Pause returns the current pause of the receipts, or nil if receipts are issued.
+func (m *NodeHealthMonitor) Pause() *ReceiptPause {
+	if m == nil {
+		return nil
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.pause == nil {
+		return nil
+	}
+	pause := *m.pause
+
+	return &pause
+}
+
+// WithNodeHealthMonitor defines the monitor that pauses the receipts while the node is unhealthy.
+// Milestones without receipts are issued during the pause, unless skipMilestones is set.
+// Available receipts stay in the migrator and are issued once the node is healthy again.
+func WithNodeHealthMonitor(nodeHealthMonitor *NodeHealthMonitor, skipMilestones bool) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.nodeHealthMonitor = nodeHealthMonitor
+		c.nodeHealthSkipMilestones = skipMilestones
+	}
+}
+
+// ReceiptPause returns the current pause of the receipts because of an unhealthy node, or nil if receipts are issued.
+func (coo *Coordinator) ReceiptPause() *ReceiptPause {
+	return coo.nodeHealthMonitor.Pause()
+}
+
+// checkNodeHealth checks the health of the node before the milestone with the given index is issued,
+// which pauses or resumes the receipts.
+// Returns non-critical errors if the milestone is skipped.
+func (coo *Coordinator) checkNodeHealth(index iotago.MilestoneIndex) error {
+	if coo.nodeHealthMonitor == nil || coo.migratorService == nil {
+		return nil
+	}
+
+	started, ended := coo.nodeHealthMonitor.Update(index, time.Now())
+	if started != nil {
+		coo.Events.ReceiptsPaused.Trigger(started)
+	}
+	if ended != nil {
+		coo.Events.ReceiptsResumed.Trigger(ended)
+	}
+
+	pause := coo.nodeHealthMonitor.Pause()
+	if pause == nil || !coo.nodeHealthSkipMilestones {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: %s", ErrNodeUnhealthy, pause.Reason)
+	coo.Events.MilestoneSkipped.Trigger(index, err)
+
+	return common.SoftError(fmt.Errorf("skipped milestone %d: %w", index, err))
+}
@@ -0,0 +1,74 @@
+package coordinator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func TestNodeHealthMonitor(t *testing.T) {
+	health := &coordinator.NodeHealth{Healthy: true, PruningIndex: 100}
+	monitor := coordinator.NewNodeHealthMonitor(func() *coordinator.NodeHealth {
+		current := *health
+
+		return &current
+	}, 10)
+
+	now := time.Unix(1_000, 0)
+
+	// the first check only learns the pruning index
+	started, ended := monitor.Update(200, now)
+	require.Nil(t, started)
+	require.Nil(t, ended)
+	require.Nil(t, monitor.Pause())
+
+	// regular pruning with the confirmed milestones
+	health.PruningIndex = 101
+	started, ended = monitor.Update(201, now)
+	require.Nil(t, started)
+	require.Nil(t, ended)
+
+	// the node reports unhealthy
+	health.Healthy = false
+	started, ended = monitor.Update(202, now)
+	require.NotNil(t, started)
+	require.Nil(t, ended)
+	require.EqualValues(t, 202, started.Index)
+	require.Equal(t, started, monitor.Pause())
+
+	// the pause is only started once
+	started, ended = monitor.Update(203, now.Add(5*time.Second))
+	require.Nil(t, started)
+	require.Nil(t, ended)
+
+	health.Healthy = true
+	started, ended = monitor.Update(204, now.Add(10*time.Second))
+	require.Nil(t, started)
+	require.NotNil(t, ended)
+	require.EqualValues(t, 202, ended.Index)
+	require.EqualValues(t, 204, ended.ResumedIndex)
+	require.Equal(t, 10*time.Second, ended.Duration)
+	require.Nil(t, monitor.Pause())
+
+	// the node prunes a large part of its database at once
+	health.PruningIndex = 150
+	started, _ = monitor.Update(205, now)
+	require.NotNil(t, started)
+	require.Contains(t, started.Reason, "pruning")
+
+	// the pruning finished
+	_, ended = monitor.Update(206, now)
+	require.NotNil(t, ended)
+
+	// larger steps are expected if milestones were skipped in between
+	health.PruningIndex = 170
+	started, _ = monitor.Update(208, now)
+	require.Nil(t, started)
+
+	// the pause of a disabled monitor is always nil
+	var disabled *coordinator.NodeHealthMonitor
+	require.Nil(t, disabled.Pause())
+}
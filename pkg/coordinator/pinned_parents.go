@@ -0,0 +1,190 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// MaxPinnedParents is the maximum amount of pinned parents,
+	// a milestone always references the latest milestone and the latest checkpoint as well.
+	MaxPinnedParents = iotago.BlockMaxParents - 2
+)
+
+var (
+	// ErrPinnedParentInvalid is returned when a block can't be pinned as a parent of the next milestone.
+	ErrPinnedParentInvalid = errors.New("invalid pinned parent")
+	// ErrPinnedParentNotFound is returned when a block is not pinned.
+	ErrPinnedParentNotFound = errors.New("pinned parent not found")
+	// ErrTooManyPinnedParents is returned when more than the maximum amount of blocks would be pinned.
+	ErrTooManyPinnedParents = errors.New("too many pinned parents")
+)
+
+// BlockState is the state of a block in the node, which is checked before the block is pinned.
+type BlockState struct {
+	// whether the block is solid.
+	Solid bool
+	// the index of the milestone that referenced the block (0 = not referenced yet).
+	ReferencedByMilestoneIndex iotago.MilestoneIndex
+	// whether the block is below max depth and can't be referenced by a milestone anymore.
+	BelowMaxDepth bool
+}
+
+// BlockStateFunc returns the state of the block with the given ID in the node.
+type BlockStateFunc = func(ctx context.Context, blockID iotago.BlockID) (*BlockState, error)
+
+// PinnedParents are blocks that are referenced directly by the next milestone,
+// e.g. to force the confirmation of a stuck critical transaction in an emergency.
+// The blocks are unpinned automatically after a milestone referencing them was issued.
+type PinnedParents struct {
+	// used to check the state of the blocks before they are pinned.
+	blockStateFunc BlockStateFunc
+	// used to protect the pinned blocks.
+	lock syncutils.RWMutex
+	// the pinned blocks, sorted by their IDs.
+	blockIDs iotago.BlockIDs
+	// the maximum amount of pinned blocks.
+	maxPinnedParents int
+}
+
+// WithMaxPinnedParents defines the maximum amount of pinned blocks, e.g. if milestones have less than the maximum amount of parents.
+func WithMaxPinnedParents(maxPinnedParents int) options.Option[PinnedParents] {
+	return func(p *PinnedParents) {
+		p.maxPinnedParents = maxPinnedParents
+	}
+}
+
+// NewPinnedParents creates a new PinnedParents instance.
+func NewPinnedParents(blockStateFunc BlockStateFunc, opts ...options.Option[PinnedParents]) *PinnedParents {
+	return options.Apply(&PinnedParents{
+		blockStateFunc:   blockStateFunc,
+		blockIDs:         iotago.BlockIDs{},
+		maxPinnedParents: MaxPinnedParents,
+	}, opts)
+}
+
+// Pin pins the given blocks as parents of the next milestone and returns all pinned blocks.
+// Only solid blocks that were not referenced by a milestone yet and are not below max depth can be pinned.
+func (p *PinnedParents) Pin(ctx context.Context, blockIDs iotago.BlockIDs) (iotago.BlockIDs, error) {
+	if len(blockIDs) == 0 {
+		return nil, fmt.Errorf("%w: no block IDs given", ErrPinnedParentInvalid)
+	}
+
+	// the node is queried before the lock is acquired, so the issuance of milestones is not blocked
+	for _, blockID := range blockIDs {
+		if err := p.checkBlock(ctx, blockID); err != nil {
+			return nil, err
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	pinned := append(cloneBlockIDs(p.blockIDs), blockIDs...).RemoveDupsAndSort()
+	if len(pinned) > p.maxPinnedParents {
+		return nil, fmt.Errorf("%w: %d blocks would be pinned, only %d are allowed", ErrTooManyPinnedParents, len(pinned), p.maxPinnedParents)
+	}
+	p.blockIDs = pinned
+
+	return cloneBlockIDs(p.blockIDs), nil
+}
+
+func (p *PinnedParents) checkBlock(ctx context.Context, blockID iotago.BlockID) error {
+	state, err := p.blockStateFunc(ctx, blockID)
+	if err != nil {
+		return fmt.Errorf("failed to query the state of block %s: %w", blockID.ToHex(), err)
+	}
+
+	switch {
+	case !state.Solid:
+		return fmt.Errorf("%w: block %s is not solid", ErrPinnedParentInvalid, blockID.ToHex())
+	case state.ReferencedByMilestoneIndex != 0:
+		return fmt.Errorf("%w: block %s was already referenced by milestone %d", ErrPinnedParentInvalid, blockID.ToHex(), state.ReferencedByMilestoneIndex)
+	case state.BelowMaxDepth:
+		return fmt.Errorf("%w: block %s is below max depth", ErrPinnedParentInvalid, blockID.ToHex())
+	default:
+		return nil
+	}
+}
+
+// Unpin removes the given block from the pinned parents.
+func (p *PinnedParents) Unpin(blockID iotago.BlockID) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for i, pinnedBlockID := range p.blockIDs {
+		if pinnedBlockID == blockID {
+			p.blockIDs = append(p.blockIDs[:i], p.blockIDs[i+1:]...)
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: block %s", ErrPinnedParentNotFound, blockID.ToHex())
+}
+
+// BlockIDs returns the pinned blocks.
+func (p *PinnedParents) BlockIDs() iotago.BlockIDs {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return cloneBlockIDs(p.blockIDs)
+}
+
+// used removes the blocks that were referenced by an issued milestone and returns them.
+// Blocks that were pinned after the parents of the milestone were selected stay pinned.
+func (p *PinnedParents) used(parents iotago.BlockIDs) iotago.BlockIDs {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	parentsSet := make(map[iotago.BlockID]struct{}, len(parents))
+	for _, parent := range parents {
+		parentsSet[parent] = struct{}{}
+	}
+
+	remaining := iotago.BlockIDs{}
+	unpinned := iotago.BlockIDs{}
+	for _, blockID := range p.blockIDs {
+		if _, referenced := parentsSet[blockID]; referenced {
+			unpinned = append(unpinned, blockID)
+
+			continue
+		}
+		remaining = append(remaining, blockID)
+	}
+	p.blockIDs = remaining
+
+	return unpinned
+}
+
+func cloneBlockIDs(blockIDs iotago.BlockIDs) iotago.BlockIDs {
+	cloned := make(iotago.BlockIDs, len(blockIDs))
+	copy(cloned, blockIDs)
+
+	return cloned
+}
+
+// WithPinnedParents defines the blocks that are pinned as parents of the next milestone.
+// The pinned blocks are unpinned after a milestone referencing them was issued.
+func WithPinnedParents(pinnedParents *PinnedParents) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.pinnedParents = pinnedParents
+	}
+}
+
+// unpinUsedParents unpins the pinned blocks that were referenced by the issued milestone.
+func (coo *Coordinator) unpinUsedParents(parents iotago.BlockIDs) {
+	if coo.pinnedParents == nil {
+		return
+	}
+
+	for _, blockID := range coo.pinnedParents.used(parents) {
+		coo.LogInfof("pinned parent %s was referenced by milestone %d and is unpinned", blockID.ToHex(), coo.state.LatestMilestoneIndex)
+	}
+}
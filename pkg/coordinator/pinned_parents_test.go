@@ -0,0 +1,75 @@
+package coordinator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestPinnedParents(t *testing.T) {
+	errNodeUnavailable := errors.New("node unavailable")
+
+	blockStates := map[iotago.BlockID]*coordinator.BlockState{
+		{1}: {Solid: true},
+		{2}: {Solid: true},
+		{3}: {Solid: false},
+		{4}: {Solid: true, ReferencedByMilestoneIndex: 10},
+		{5}: {Solid: true, BelowMaxDepth: true},
+	}
+	for i := byte(10); i < 20; i++ {
+		blockStates[iotago.BlockID{i}] = &coordinator.BlockState{Solid: true}
+	}
+
+	pinnedParents := coordinator.NewPinnedParents(func(_ context.Context, blockID iotago.BlockID) (*coordinator.BlockState, error) {
+		state, exists := blockStates[blockID]
+		if !exists {
+			return nil, errNodeUnavailable
+		}
+
+		return state, nil
+	})
+
+	pinned, err := pinnedParents.Pin(context.Background(), iotago.BlockIDs{{2}, {1}, {2}})
+	require.NoError(t, err)
+	require.Equal(t, iotago.BlockIDs{{1}, {2}}, pinned)
+
+	// invalid blocks are rejected without pinning the valid ones of the request
+	for _, blockID := range []iotago.BlockID{{3}, {4}, {5}} {
+		_, err = pinnedParents.Pin(context.Background(), iotago.BlockIDs{{10}, blockID})
+		require.ErrorIs(t, err, coordinator.ErrPinnedParentInvalid)
+	}
+	_, err = pinnedParents.Pin(context.Background(), iotago.BlockIDs{{6}})
+	require.ErrorIs(t, err, errNodeUnavailable)
+	_, err = pinnedParents.Pin(context.Background(), iotago.BlockIDs{})
+	require.ErrorIs(t, err, coordinator.ErrPinnedParentInvalid)
+	require.Equal(t, iotago.BlockIDs{{1}, {2}}, pinnedParents.BlockIDs())
+
+	// the latest milestone and checkpoint need to fit into the milestone as well
+	_, err = pinnedParents.Pin(context.Background(), iotago.BlockIDs{{10}, {11}, {12}, {13}, {14}})
+	require.ErrorIs(t, err, coordinator.ErrTooManyPinnedParents)
+	pinned, err = pinnedParents.Pin(context.Background(), iotago.BlockIDs{{10}, {11}, {12}, {13}})
+	require.NoError(t, err)
+	require.Len(t, pinned, coordinator.MaxPinnedParents)
+
+	require.NoError(t, pinnedParents.Unpin(iotago.BlockID{1}))
+	require.ErrorIs(t, pinnedParents.Unpin(iotago.BlockID{1}), coordinator.ErrPinnedParentNotFound)
+	require.Equal(t, iotago.BlockIDs{{2}, {10}, {11}, {12}, {13}}, pinnedParents.BlockIDs())
+}
+
+func TestPinnedParentsWithLessMilestoneParents(t *testing.T) {
+	pinnedParents := coordinator.NewPinnedParents(func(_ context.Context, _ iotago.BlockID) (*coordinator.BlockState, error) {
+		return &coordinator.BlockState{Solid: true}, nil
+	}, coordinator.WithMaxPinnedParents(2))
+
+	_, err := pinnedParents.Pin(context.Background(), iotago.BlockIDs{{1}, {2}, {3}})
+	require.ErrorIs(t, err, coordinator.ErrTooManyPinnedParents)
+
+	pinned, err := pinnedParents.Pin(context.Background(), iotago.BlockIDs{{1}, {2}})
+	require.NoError(t, err)
+	require.Len(t, pinned, 2)
+}
@@ -0,0 +1,255 @@
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// PoWProviderNode submits the blocks without nonce, so that the PoW is done by the node via INX.
+	PoWProviderNode = "node"
+	// PoWProviderRemote requests the nonces from a pool of remote PoW workers.
+	PoWProviderRemote = "remote"
+
+	// RoutePoW is the route of the remote PoW workers that computes a nonce.
+	RoutePoW = "/pow"
+
+	// the size of the nonce at the end of the serialized block.
+	nonceBytes = 8
+)
+
+var (
+	// ErrUnknownPoWProvider is returned when an unknown PoW provider is configured.
+	ErrUnknownPoWProvider = errors.New("unknown PoW provider")
+	// ErrNoPoWWorkers is returned when the remote PoW provider is configured without workers.
+	ErrNoPoWWorkers = errors.New("no remote PoW workers given")
+	// ErrInvalidNonce is returned when a remote PoW worker returned a nonce that does not reach the target score.
+	ErrInvalidNonce = errors.New("invalid nonce")
+	// ErrPoWFailed is returned when none of the remote PoW workers was able to compute a valid nonce.
+	ErrPoWFailed = errors.New("proof of work failed")
+)
+
+// PoWAttempt is the result of a single attempt to compute the nonce of a block.
+type PoWAttempt struct {
+	// the PoW provider that was used.
+	Provider string
+	// the PoW worker that was asked for the nonce.
+	Worker string
+	// the duration of the attempt.
+	Duration time.Duration
+	// the error of the attempt, if it failed.
+	Err error
+}
+
+// PoWAttemptCaller is used to signal an attempt to compute the nonce of a block.
+func PoWAttemptCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(attempt *PoWAttempt))(params[0].(*PoWAttempt))
+}
+
+// PoWProvider computes the nonces of the blocks issued by the coordinator.
+type PoWProvider interface {
+	// DoPoW sets the nonce of the block, so that the block reaches the minimum PoW score of the protocol.
+	// onAttempt is called for every attempt to compute the nonce.
+	DoPoW(ctx context.Context, block *iotago.Block, protoParams *iotago.ProtocolParameters, onAttempt func(attempt *PoWAttempt)) error
+}
+
+// WithPoWProvider defines the provider that computes the nonces of the blocks issued by the coordinator.
+func WithPoWProvider(powProvider PoWProvider) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.powProvider = powProvider
+	}
+}
+
+// NewPoWProvider creates the PoW provider of the given type.
+func NewPoWProvider(provider string, remoteWorkers []string, timeout time.Duration) (PoWProvider, error) {
+	switch provider {
+	case PoWProviderNode:
+		return &NodePoWProvider{}, nil
+	case PoWProviderRemote:
+		return NewRemotePoWProvider(remoteWorkers, timeout)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPoWProvider, provider)
+	}
+}
+
+// NodePoWProvider leaves the nonce empty, so that the PoW is done by the node when the block is submitted via INX.
+type NodePoWProvider struct{}
+
+// DoPoW resets the nonce of the block.
+func (p *NodePoWProvider) DoPoW(_ context.Context, block *iotago.Block, _ *iotago.ProtocolParameters, _ func(attempt *PoWAttempt)) error {
+	block.Nonce = 0
+
+	return nil
+}
+
+// RemotePoWRequest is the request that is sent to the remote PoW workers.
+type RemotePoWRequest struct {
+	// the serialized block without the nonce (hex encoded).
+	Data string `json:"data"`
+	// the PoW score the block needs to reach.
+	TargetScore uint32 `json:"targetScore"`
+}
+
+// RemotePoWResponse is the response of the remote PoW workers.
+type RemotePoWResponse struct {
+	// the nonce of the block (decimal encoded).
+	Nonce string `json:"nonce"`
+}
+
+// RemotePoWProvider requests the nonces from a pool of remote PoW workers, so the coordinator host does not need to compute them.
+// The workers are asked in a round-robin fashion, if a worker fails the next one is asked.
+// The nonces returned by the workers are verified before they are used.
+type RemotePoWProvider struct {
+	// the base URLs of the remote PoW workers.
+	workers []string
+	// the HTTP client used for the requests.
+	httpClient *http.Client
+	// the index of the worker that is asked first for the next block.
+	next atomic.Uint32
+}
+
+// NewRemotePoWProvider creates a new RemotePoWProvider for the given workers.
+func NewRemotePoWProvider(workers []string, timeout time.Duration) (*RemotePoWProvider, error) {
+	if len(workers) == 0 {
+		return nil, ErrNoPoWWorkers
+	}
+
+	baseURLs := make([]string, 0, len(workers))
+	for _, worker := range workers {
+		baseURLs = append(baseURLs, strings.TrimSuffix(worker, "/"))
+	}
+
+	return &RemotePoWProvider{
+		workers:    baseURLs,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// DoPoW asks the remote PoW workers for the nonce of the block until one of them returned a valid nonce.
+func (p *RemotePoWProvider) DoPoW(ctx context.Context, block *iotago.Block, protoParams *iotago.ProtocolParameters, onAttempt func(attempt *PoWAttempt)) error {
+	block.Nonce = 0
+
+	if protoParams.MinPoWScore == 0 {
+		return nil
+	}
+
+	data, err := block.Serialize(serializer.DeSeriModePerformValidation, protoParams)
+	if err != nil {
+		return fmt.Errorf("unable to serialize block for PoW: %w", err)
+	}
+
+	request := &RemotePoWRequest{
+		Data:        iotago.EncodeHex(data[:len(data)-nonceBytes]),
+		TargetScore: protoParams.MinPoWScore,
+	}
+
+	start := int(p.next.Add(1)-1) % len(p.workers)
+	for i := range p.workers {
+		worker := p.workers[(start+i)%len(p.workers)]
+
+		ts := time.Now()
+		err := p.requestNonce(ctx, worker, request, block, protoParams.MinPoWScore)
+		if onAttempt != nil {
+			onAttempt(&PoWAttempt{
+				Provider: PoWProviderRemote,
+				Worker:   worker,
+				Duration: time.Since(ts),
+				Err:      err,
+			})
+		}
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	block.Nonce = 0
+
+	return fmt.Errorf("%w: all %d remote PoW workers failed", ErrPoWFailed, len(p.workers))
+}
+
+// requestNonce asks a single worker for the nonce and sets it in the block, if it reaches the target score.
+func (p *RemotePoWProvider) requestNonce(ctx context.Context, worker string, request *RemotePoWRequest, block *iotago.Block, targetScore uint32) error {
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, worker+RoutePoW, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote PoW worker returned status code %d: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+
+	response := &RemotePoWResponse{}
+	if err := json.Unmarshal(resBody, response); err != nil {
+		return fmt.Errorf("unable to decode remote PoW response: %w", err)
+	}
+
+	nonce, err := iotago.DecodeUint64(response.Nonce)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidNonce, err)
+	}
+
+	// never trust the workers, a block with an invalid nonce would be rejected by the network
+	block.Nonce = nonce
+	score, err := block.POW()
+	if err != nil {
+		block.Nonce = 0
+
+		return err
+	}
+	if score < float64(targetScore) {
+		block.Nonce = 0
+
+		return fmt.Errorf("%w: score %.2f is below the target score %d", ErrInvalidNonce, score, targetScore)
+	}
+
+	return nil
+}
+
+// doPoW computes the nonce of a block issued by the coordinator.
+// Milestone blocks are not passed to the PoW provider, because the protocol requires their nonce to be zero.
+func (coo *Coordinator) doPoW(block *iotago.Block) error {
+	if _, isMilestone := block.Payload.(*iotago.Milestone); isMilestone {
+		block.Nonce = 0
+
+		return nil
+	}
+
+	return coo.powProvider.DoPoW(context.Background(), block, coo.protoParamsFunc(), func(attempt *PoWAttempt) {
+		coo.Events.PoWAttempt.Trigger(attempt)
+	})
+}
@@ -0,0 +1,118 @@
+package coordinator_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/pow"
+)
+
+var testPoWProtoParams = &iotago.ProtocolParameters{
+	Version:     2,
+	NetworkName: "testnet",
+	Bech32HRP:   iotago.PrefixTestnet,
+	MinPoWScore: 10,
+	RentStructure: iotago.RentStructure{
+		VByteCost:    500,
+		VBFactorData: 1,
+		VBFactorKey:  10,
+	},
+	TokenSupply: 2_779_530_283_277_761,
+}
+
+func newTestCheckpointBlock() *iotago.Block {
+	return &iotago.Block{
+		ProtocolVersion: testPoWProtoParams.Version,
+		Parents:         iotago.BlockIDs{{1}},
+		Payload:         &iotago.TaggedData{Tag: []byte("checkpoint")},
+	}
+}
+
+// newTestPoWWorker creates a remote PoW worker that mines the nonce, or returns the given nonce if it is not empty.
+func newTestPoWWorker(t *testing.T, fixedNonce string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, coordinator.RoutePoW, r.URL.Path)
+
+		request := &coordinator.RemotePoWRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+
+		nonce := fixedNonce
+		if nonce == "" {
+			data, err := iotago.DecodeHex(request.Data)
+			require.NoError(t, err)
+
+			minedNonce, err := pow.New(1).Mine(r.Context(), data, float64(request.TargetScore))
+			require.NoError(t, err)
+			nonce = iotago.EncodeUint64(minedNonce)
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(&coordinator.RemotePoWResponse{Nonce: nonce}))
+	}))
+}
+
+func TestRemotePoWProvider(t *testing.T) {
+	failingWorker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}))
+	defer failingWorker.Close()
+
+	cheatingWorker := newTestPoWWorker(t, "1")
+	defer cheatingWorker.Close()
+
+	worker := newTestPoWWorker(t, "")
+	defer worker.Close()
+
+	provider, err := coordinator.NewRemotePoWProvider([]string{failingWorker.URL, cheatingWorker.URL, worker.URL + "/"}, 5*time.Second)
+	require.NoError(t, err)
+
+	var attempts []*coordinator.PoWAttempt
+	block := newTestCheckpointBlock()
+	require.NoError(t, provider.DoPoW(context.Background(), block, testPoWProtoParams, func(attempt *coordinator.PoWAttempt) {
+		attempts = append(attempts, attempt)
+	}))
+
+	score, err := block.POW()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, score, float64(testPoWProtoParams.MinPoWScore))
+
+	// every attempt is reported, the failing workers are skipped
+	require.Len(t, attempts, 3)
+	require.Error(t, attempts[0].Err)
+	require.ErrorIs(t, attempts[1].Err, coordinator.ErrInvalidNonce)
+	require.NoError(t, attempts[2].Err)
+	require.Equal(t, worker.URL, attempts[2].Worker)
+
+	// the next block starts with the next worker
+	attempts = nil
+	require.NoError(t, provider.DoPoW(context.Background(), newTestCheckpointBlock(), testPoWProtoParams, func(attempt *coordinator.PoWAttempt) {
+		attempts = append(attempts, attempt)
+	}))
+	require.Len(t, attempts, 2)
+	require.Equal(t, cheatingWorker.URL, attempts[0].Worker)
+}
+
+func TestRemotePoWProviderAllWorkersFailed(t *testing.T) {
+	cheatingWorker := newTestPoWWorker(t, "1")
+	defer cheatingWorker.Close()
+
+	provider, err := coordinator.NewRemotePoWProvider([]string{cheatingWorker.URL}, 5*time.Second)
+	require.NoError(t, err)
+
+	block := newTestCheckpointBlock()
+	require.ErrorIs(t, provider.DoPoW(context.Background(), block, testPoWProtoParams, nil), coordinator.ErrPoWFailed)
+	require.Zero(t, block.Nonce)
+
+	_, err = coordinator.NewPoWProvider(coordinator.PoWProviderRemote, nil, time.Second)
+	require.ErrorIs(t, err, coordinator.ErrNoPoWWorkers)
+
+	_, err = coordinator.NewPoWProvider("gpu", nil, time.Second)
+	require.ErrorIs(t, err, coordinator.ErrUnknownPoWProvider)
+}
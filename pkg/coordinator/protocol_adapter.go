@@ -0,0 +1,218 @@
+package coordinator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	iotago "github.com/iotaledger/iota.go/v3"
+	builder "github.com/iotaledger/iota.go/v3/builder"
+)
+
+const (
+	// ProtocolVersionStardust is the protocol version of the stardust protocol.
+	ProtocolVersionStardust byte = 2
+)
+
+var (
+	// ErrInvalidProtocolActivations is returned when the protocol activations are configured with invalid values.
+	ErrInvalidProtocolActivations = errors.New("invalid protocol activations")
+
+	// protocolAdapterFactories contains the constructors of the adapters of all supported protocol versions.
+	protocolAdapterFactories = map[byte]func() ProtocolAdapter{
+		ProtocolVersionStardust: func() ProtocolAdapter { return &StardustProtocolAdapter{} },
+	}
+)
+
+// MilestoneParameters are the values of a milestone that are independent of the protocol version.
+type MilestoneParameters struct {
+	// the index of the milestone.
+	Index iotago.MilestoneIndex
+	// the unix timestamp of the milestone.
+	Timestamp uint32
+	// the ID of the previous milestone.
+	PreviousMilestoneID iotago.MilestoneID
+	// the parents of the milestone.
+	Parents iotago.BlockIDs
+	// the merkle roots calculated by whiteflag confirmation.
+	MerkleRoots *MilestoneMerkleRoots
+	// the optional metadata of the milestone.
+	Metadata []byte
+	// the optional receipt of the milestone.
+	Receipt *iotago.ReceiptMilestoneOpt
+	// the additional options of the milestone besides the receipt, e.g. protocol parameter updates.
+	Opts iotago.MilestoneOpts
+}
+
+// ProtocolAdapter constructs the blocks, milestones and receipts of a protocol version.
+// Protocol versions can encode their milestone options differently, so the coordinator
+// needs an adapter for every protocol version it issues milestones for.
+type ProtocolAdapter interface {
+	// Version returns the protocol version of the constructed blocks.
+	Version() byte
+	// NewMilestone creates the unsigned milestone payload.
+	NewMilestone(params *MilestoneParameters) *iotago.Milestone
+	// NewBlock creates a block with the given parents and the optional payload.
+	NewBlock(parents iotago.BlockIDs, payload iotago.Payload) (*iotago.Block, error)
+	// NewTreasuryTransaction creates the treasury transaction of a receipt that spends the given treasury output.
+	NewTreasuryTransaction(treasuryOutput *LatestTreasuryOutput, receipt *iotago.ReceiptMilestoneOpt) *iotago.TreasuryTransaction
+	// ReceiptEssence returns the essence of the receipt that gets signed by the treasury key.
+	ReceiptEssence(receipt *iotago.ReceiptMilestoneOpt, protoParams *iotago.ProtocolParameters) ([]byte, error)
+}
+
+// StardustProtocolAdapter constructs the blocks, milestones and receipts of the stardust protocol.
+type StardustProtocolAdapter struct{}
+
+// Version returns the protocol version of the constructed blocks.
+func (a *StardustProtocolAdapter) Version() byte {
+	return ProtocolVersionStardust
+}
+
+// NewMilestone creates the unsigned milestone payload.
+func (a *StardustProtocolAdapter) NewMilestone(params *MilestoneParameters) *iotago.Milestone {
+	confMerkleRoot := [iotago.MilestoneMerkleProofLength]byte{}
+	copy(confMerkleRoot[:], params.MerkleRoots.InclusionMerkleRoot[:])
+	appliedMerkleRoot := [iotago.MilestoneMerkleProofLength]byte{}
+	copy(appliedMerkleRoot[:], params.MerkleRoots.AppliedMerkleRoot[:])
+
+	msPayload := iotago.NewMilestone(params.Index, params.Timestamp, a.Version(), params.PreviousMilestoneID, params.Parents, confMerkleRoot, appliedMerkleRoot)
+
+	if len(params.Metadata) > 0 {
+		msPayload.Metadata = params.Metadata
+	}
+
+	msPayload.Opts = combineMilestoneOptions(params.Receipt, params.Opts)
+
+	return msPayload
+}
+
+// NewBlock creates a block with the given parents and the optional payload.
+func (a *StardustProtocolAdapter) NewBlock(parents iotago.BlockIDs, payload iotago.Payload) (*iotago.Block, error) {
+	blockBuilder := builder.
+		NewBlockBuilder().
+		ProtocolVersion(a.Version()).
+		Parents(parents)
+
+	if payload != nil {
+		blockBuilder = blockBuilder.Payload(payload)
+	}
+
+	return blockBuilder.Build()
+}
+
+// NewTreasuryTransaction creates the treasury transaction of a receipt that spends the given treasury output.
+func (a *StardustProtocolAdapter) NewTreasuryTransaction(treasuryOutput *LatestTreasuryOutput, receipt *iotago.ReceiptMilestoneOpt) *iotago.TreasuryTransaction {
+	input := &iotago.TreasuryInput{}
+	copy(input[:], treasuryOutput.MilestoneID[:])
+	output := &iotago.TreasuryOutput{Amount: treasuryOutput.Amount - receipt.Sum()}
+
+	return &iotago.TreasuryTransaction{Input: input, Output: output}
+}
+
+// ReceiptEssence returns the essence of the receipt that gets signed by the treasury key.
+func (a *StardustProtocolAdapter) ReceiptEssence(receipt *iotago.ReceiptMilestoneOpt, protoParams *iotago.ProtocolParameters) ([]byte, error) {
+	return ReceiptEssence(receipt, protoParams)
+}
+
+// ProtocolActivation defines the milestone index from which on the milestones are issued with a protocol version.
+type ProtocolActivation struct {
+	// the protocol version.
+	Version byte `json:"version" koanf:"version" usage:"the protocol version"`
+	// the milestone index from which on the milestones are issued with the protocol version.
+	StartIndex iotago.MilestoneIndex `json:"startIndex" koanf:"startIndex" usage:"the milestone index from which on the milestones are issued with the protocol version"`
+}
+
+// activeProtocolAdapter is a protocol adapter that is used from the start index on.
+type activeProtocolAdapter struct {
+	startIndex iotago.MilestoneIndex
+	adapter    ProtocolAdapter
+}
+
+// ProtocolAdapters selects the protocol adapter for a milestone index.
+type ProtocolAdapters struct {
+	// the adapters sorted by their start index.
+	adapters []*activeProtocolAdapter
+}
+
+// NewProtocolAdapters creates the protocol adapters for the given activations.
+// The first activation needs to start at index 0, without activations all milestones are issued with the stardust protocol.
+func NewProtocolAdapters(activations []*ProtocolActivation) (*ProtocolAdapters, error) {
+	if len(activations) == 0 {
+		activations = []*ProtocolActivation{{Version: ProtocolVersionStardust, StartIndex: 0}}
+	}
+
+	sorted := make([]*ProtocolActivation, len(activations))
+	copy(sorted, activations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartIndex < sorted[j].StartIndex
+	})
+
+	if sorted[0].StartIndex != 0 {
+		return nil, fmt.Errorf("%w: the first activation needs to start at index 0, got %d", ErrInvalidProtocolActivations, sorted[0].StartIndex)
+	}
+
+	adapters := &ProtocolAdapters{adapters: make([]*activeProtocolAdapter, 0, len(sorted))}
+	for i, activation := range sorted {
+		factory, supported := protocolAdapterFactories[activation.Version]
+		if !supported {
+			return nil, fmt.Errorf("%w: unsupported protocol version %d", ErrInvalidProtocolActivations, activation.Version)
+		}
+
+		if i > 0 && sorted[i-1].StartIndex == activation.StartIndex {
+			return nil, fmt.Errorf("%w: several activations start at index %d", ErrInvalidProtocolActivations, activation.StartIndex)
+		}
+
+		adapters.adapters = append(adapters.adapters, &activeProtocolAdapter{
+			startIndex: activation.StartIndex,
+			adapter:    factory(),
+		})
+	}
+
+	return adapters, nil
+}
+
+// AdapterAt returns the protocol adapter of the milestone with the given index.
+func (a *ProtocolAdapters) AdapterAt(index iotago.MilestoneIndex) ProtocolAdapter {
+	// the first adapter always starts at index 0
+	adapter := a.adapters[0].adapter
+	for _, activeAdapter := range a.adapters[1:] {
+		if activeAdapter.startIndex > index {
+			break
+		}
+		adapter = activeAdapter.adapter
+	}
+
+	return adapter
+}
+
+// Activations returns the protocol activations sorted by their start index.
+func (a *ProtocolAdapters) Activations() []*ProtocolActivation {
+	activations := make([]*ProtocolActivation, 0, len(a.adapters))
+	for _, activeAdapter := range a.adapters {
+		activations = append(activations, &ProtocolActivation{
+			Version:    activeAdapter.adapter.Version(),
+			StartIndex: activeAdapter.startIndex,
+		})
+	}
+
+	return activations
+}
+
+// all returns all protocol adapters.
+func (a *ProtocolAdapters) all() []ProtocolAdapter {
+	adapters := make([]ProtocolAdapter, 0, len(a.adapters))
+	for _, activeAdapter := range a.adapters {
+		adapters = append(adapters, activeAdapter.adapter)
+	}
+
+	return adapters
+}
+
+// WithProtocolAdapters defines the protocol adapters used to construct the milestones, depending on their index.
+func WithProtocolAdapters(protocolAdapters *ProtocolAdapters) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.protocolAdapters = protocolAdapters
+	}
+}
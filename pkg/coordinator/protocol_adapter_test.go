@@ -0,0 +1,92 @@
+package coordinator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestNewProtocolAdapters(t *testing.T) {
+	// without activations all milestones are issued with the stardust protocol
+	adapters, err := coordinator.NewProtocolAdapters(nil)
+	require.NoError(t, err)
+	require.Equal(t, coordinator.ProtocolVersionStardust, adapters.AdapterAt(0).Version())
+	require.Equal(t, coordinator.ProtocolVersionStardust, adapters.AdapterAt(1_000_000).Version())
+	require.Equal(t, []*coordinator.ProtocolActivation{{Version: coordinator.ProtocolVersionStardust, StartIndex: 0}}, adapters.Activations())
+
+	// the activations are sorted by their start index
+	adapters, err = coordinator.NewProtocolAdapters([]*coordinator.ProtocolActivation{
+		{Version: coordinator.ProtocolVersionStardust, StartIndex: 500},
+		{Version: coordinator.ProtocolVersionStardust, StartIndex: 0},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*coordinator.ProtocolActivation{
+		{Version: coordinator.ProtocolVersionStardust, StartIndex: 0},
+		{Version: coordinator.ProtocolVersionStardust, StartIndex: 500},
+	}, adapters.Activations())
+
+	_, err = coordinator.NewProtocolAdapters([]*coordinator.ProtocolActivation{{Version: coordinator.ProtocolVersionStardust, StartIndex: 10}})
+	require.ErrorIs(t, err, coordinator.ErrInvalidProtocolActivations)
+
+	_, err = coordinator.NewProtocolAdapters([]*coordinator.ProtocolActivation{{Version: 42, StartIndex: 0}})
+	require.ErrorIs(t, err, coordinator.ErrInvalidProtocolActivations)
+
+	_, err = coordinator.NewProtocolAdapters([]*coordinator.ProtocolActivation{
+		{Version: coordinator.ProtocolVersionStardust, StartIndex: 0},
+		{Version: coordinator.ProtocolVersionStardust, StartIndex: 0},
+	})
+	require.ErrorIs(t, err, coordinator.ErrInvalidProtocolActivations)
+}
+
+func TestStardustProtocolAdapter(t *testing.T) {
+	adapter := &coordinator.StardustProtocolAdapter{}
+
+	receipt := &iotago.ReceiptMilestoneOpt{
+		MigratedAt: 7,
+		Final:      true,
+		Funds: []*iotago.MigratedFundsEntry{
+			{
+				TailTransactionHash: iotago.LegacyTailTransactionHash{5},
+				Address:             &iotago.Ed25519Address{6},
+				Deposit:             1_000_000,
+			},
+		},
+	}
+
+	receipt.Transaction = adapter.NewTreasuryTransaction(&coordinator.LatestTreasuryOutput{MilestoneID: iotago.MilestoneID{8}, Amount: 10_000_000}, receipt)
+	require.Equal(t, &iotago.TreasuryTransaction{
+		Input:  &iotago.TreasuryInput{8},
+		Output: &iotago.TreasuryOutput{Amount: 9_000_000},
+	}, receipt.Transaction)
+
+	parents := iotago.BlockIDs{{1}, {2}}
+	milestone := adapter.NewMilestone(&coordinator.MilestoneParameters{
+		Index:               42,
+		Timestamp:           1000,
+		PreviousMilestoneID: iotago.MilestoneID{3},
+		Parents:             parents,
+		MerkleRoots: &coordinator.MilestoneMerkleRoots{
+			InclusionMerkleRoot: iotago.MilestoneMerkleProof{4},
+			AppliedMerkleRoot:   iotago.MilestoneMerkleProof{5},
+		},
+		Metadata: []byte("coo"),
+		Receipt:  receipt,
+	})
+
+	expected := iotago.NewMilestone(42, 1000, coordinator.ProtocolVersionStardust, iotago.MilestoneID{3}, parents, iotago.MilestoneMerkleProof{4}, iotago.MilestoneMerkleProof{5})
+	expected.Metadata = []byte("coo")
+	expected.Opts = iotago.MilestoneOpts{receipt}
+	require.Equal(t, expected, milestone)
+
+	block, err := adapter.NewBlock(parents, milestone)
+	require.NoError(t, err)
+	require.Equal(t, coordinator.ProtocolVersionStardust, block.ProtocolVersion)
+	require.Equal(t, milestone, block.Payload)
+
+	checkpoint, err := adapter.NewBlock(parents, nil)
+	require.NoError(t, err)
+	require.Nil(t, checkpoint.Payload)
+}
@@ -47,6 +47,8 @@ type QuorumClientStatistic struct {
 	ResponseTimeSeconds float64
 	// error of last whiteflag API call.
 	Error error
+	// the score of the client, if the quorum nodes are scored.
+	Score *QuorumNodeScore
 }
 
 // QuorumFinishedResult holds statistics of a finished quorum.
@@ -67,6 +69,8 @@ type quorum struct {
 	Groups map[string][]*quorumGroupEntry
 	// the maximim timeout of a quorum request.
 	Timeout time.Duration
+	// the optional scoring of the quorum nodes, used to demote consistently disagreeing or slow nodes.
+	scoring *QuorumScoring
 
 	quorumStatsLock syncutils.RWMutex
 }
@@ -111,11 +115,21 @@ func newQuorum(quorumGroups map[string][]*QuorumClientConfig, timeout time.Durat
 	}
 }
 
-// checkMerkleTreeHashQuorumGroup asks all nodes in a quorum group for their merkle tree hash based on the given parents.
+// quorumNodeResponse is the answer of a single node in a quorum group.
+type quorumNodeResponse struct {
+	entry    *quorumGroupEntry
+	response *nodeclient.ComputeWhiteFlagMutationsResponse
+	err      error
+	duration time.Duration
+}
+
+// checkMerkleTreeHashQuorumGroup asks all active nodes in a quorum group for their merkle tree hash based on the given parents.
 // Returns non-critical and critical errors.
 // If no node of the group answers, a non-critical error is returned.
 // If one of the nodes returns a different hash, a critical error is returned.
-func (q *quorum) checkMerkleTreeHashQuorumGroup(cooMerkleProof *MilestoneMerkleRoots,
+// If the quorum nodes are scored, a node that returns a different hash is tolerated and recorded as disagreeing,
+// as long as the strict majority of the answering nodes of the group agrees with the coordinator.
+func (q *quorum) checkMerkleTreeHashQuorumGroup(ctx context.Context, cooMerkleProof *MilestoneMerkleRoots,
 	groupName string,
 	quorumGroupEntries []*quorumGroupEntry,
 	wg *sync.WaitGroup,
@@ -125,21 +139,30 @@ func (q *quorum) checkMerkleTreeHashQuorumGroup(cooMerkleProof *MilestoneMerkleR
 	timestamp uint32,
 	parents iotago.BlockIDs,
 	previousMilestoneID iotago.MilestoneID,
-	onGroupEntryError func(groupName string, entry *quorumGroupEntry, err error)) {
+	onGroupEntryError func(groupName string, entry *quorumGroupEntry, err error),
+	onNodeDemoted func(demotion *QuorumNodeDemotion)) {
 	// mark the group as done at the end
 	defer wg.Done()
 
 	// cancel the quorum after a certain timeout
-	ctx, cancel := context.WithTimeout(context.Background(), q.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, q.Timeout)
 	defer cancel()
 
-	// create buffered channels, so the go routines will not be dangling if no receiver waits for the results anymore
-	// garbage collector will take care if the channels are not used anymore. no need to close manually
-	nodeResultChan := make(chan *nodeclient.ComputeWhiteFlagMutationsResponse, len(quorumGroupEntries))
-	nodeErrorChan := make(chan error, len(quorumGroupEntries))
-
+	// demoted nodes are not asked anymore
+	activeEntries := make([]*quorumGroupEntry, 0, len(quorumGroupEntries))
 	for _, entry := range quorumGroupEntries {
-		go func(entry *quorumGroupEntry, nodeResultChan chan *nodeclient.ComputeWhiteFlagMutationsResponse, nodeErrorChan chan error) {
+		if q.scoring != nil && q.scoring.IsDemoted(groupName, entry.stats.BaseURL) {
+			continue
+		}
+		activeEntries = append(activeEntries, entry)
+	}
+
+	// create a buffered channel, so the go routines will not be dangling if no receiver waits for the results anymore
+	// garbage collector will take care if the channel is not used anymore. no need to close manually
+	nodeResponseChan := make(chan *quorumNodeResponse, len(activeEntries))
+
+	for _, entry := range activeEntries {
+		go func(entry *quorumGroupEntry, nodeResponseChan chan *quorumNodeResponse) {
 			ts := time.Now()
 
 			response, err := entry.api.ComputeWhiteFlagMutations(ctx, index, timestamp, parents, previousMilestoneID)
@@ -148,41 +171,51 @@ func (q *quorum) checkMerkleTreeHashQuorumGroup(cooMerkleProof *MilestoneMerkleR
 			entry.stats.ResponseTimeSeconds = time.Since(ts).Seconds()
 			entry.stats.Error = err
 
-			if err != nil {
-				if onGroupEntryError != nil {
-					onGroupEntryError(groupName, entry, err)
-				}
-				nodeErrorChan <- err
-
-				return
+			if err != nil && onGroupEntryError != nil {
+				onGroupEntryError(groupName, entry, err)
 			}
-			nodeResultChan <- response
-		}(entry, nodeResultChan, nodeErrorChan)
+			nodeResponseChan <- &quorumNodeResponse{entry: entry, response: response, err: err, duration: time.Since(ts)}
+		}(entry, nodeResponseChan)
 	}
 
+	results := make([]QuorumNodeResult, 0, len(activeEntries))
+	answered := make(map[*quorumGroupEntry]struct{}, len(activeEntries))
 	//nolint:ifshort // false positive
 	validResults := 0
+	disagreements := 0
 QuorumLoop:
-	for i := 0; i < len(quorumGroupEntries); i++ {
+	for i := 0; i < len(activeEntries); i++ {
 		// we wait either until the channel got closed or the context is done
 		select {
 		case <-quorumDoneChan:
 			// quorum was aborted
 			return
 
-		case <-nodeErrorChan:
-			// ignore errors of single nodes
-			continue
+		case nodeResponse := <-nodeResponseChan:
+			answered[nodeResponse.entry] = struct{}{}
+			result := QuorumNodeResult{BaseURL: nodeResponse.entry.stats.BaseURL, Response: QuorumResponseAgreed, ResponseTime: nodeResponse.duration}
 
-		case nodeWhiteFlagResponse := <-nodeResultChan:
-			if cooMerkleProof.AppliedMerkleRoot != nodeWhiteFlagResponse.AppliedMerkleRoot ||
-				cooMerkleProof.InclusionMerkleRoot != nodeWhiteFlagResponse.InclusionMerkleRoot {
-				// mismatch of the merkle tree hash of the node => critical error
-				quorumErrChan <- common.CriticalError(ErrQuorumMerkleTreeHashMismatch)
+			switch {
+			case nodeResponse.err != nil:
+				// ignore errors of single nodes
+				result.Response = QuorumResponseFailed
+
+			case cooMerkleProof.AppliedMerkleRoot != nodeResponse.response.AppliedMerkleRoot ||
+				cooMerkleProof.InclusionMerkleRoot != nodeResponse.response.InclusionMerkleRoot:
+				if q.scoring == nil {
+					// mismatch of the merkle tree hash of the node => critical error
+					quorumErrChan <- common.CriticalError(ErrQuorumMerkleTreeHashMismatch)
+
+					return
+				}
+				// the mismatch is evaluated after all nodes of the group answered
+				result.Response = QuorumResponseDisagreed
+				disagreements++
 
-				return
+			default:
+				validResults++
 			}
-			validResults++
+			results = append(results, result)
 
 		case <-ctx.Done():
 			// quorum timeout reached
@@ -190,22 +223,46 @@ QuorumLoop:
 		}
 	}
 
+	if q.scoring != nil {
+		// nodes that did not answer in time failed
+		for _, entry := range activeEntries {
+			if _, ok := answered[entry]; !ok {
+				results = append(results, QuorumNodeResult{BaseURL: entry.stats.BaseURL, Response: QuorumResponseFailed, ResponseTime: q.Timeout})
+			}
+		}
+
+		for _, demotion := range q.scoring.RecordGroup(groupName, results) {
+			if onNodeDemoted != nil {
+				onNodeDemoted(demotion)
+			}
+		}
+
+		if disagreements > 0 && disagreements >= validResults {
+			// the disagreeing nodes are not outvoted by the nodes that agree with the coordinator => critical error
+			quorumErrChan <- common.CriticalError(ErrQuorumMerkleTreeHashMismatch)
+
+			return
+		}
+	}
+
 	if validResults == 0 {
 		// no node of the group answered, return a non-critical error.
 		quorumErrChan <- common.SoftError(ErrQuorumGroupNoAnswer)
 	}
 }
 
-// checkMerkleTreeHash asks all nodes in the quorum for their merkle tree hash based on the given parents.
+// checkMerkleTreeHash asks all active nodes in the quorum for their merkle tree hash based on the given parents.
 // Returns non-critical and critical errors.
 // If no node of a certain group answers, a non-critical error is returned.
 // If one of the nodes returns a different hash, a critical error is returned.
-func (q *quorum) checkMerkleTreeHash(cooMerkleProof *MilestoneMerkleRoots,
+// Nodes that did not answer before the given context is done are treated like nodes that did not answer within the timeout.
+func (q *quorum) checkMerkleTreeHash(ctx context.Context, cooMerkleProof *MilestoneMerkleRoots,
 	index iotago.MilestoneIndex,
 	timestamp uint32,
 	parents iotago.BlockIDs,
 	previousMilestoneID iotago.MilestoneID,
-	onGroupEntryError func(groupName string, entry *quorumGroupEntry, err error)) error {
+	onGroupEntryError func(groupName string, entry *quorumGroupEntry, err error),
+	onNodeDemoted func(demotion *QuorumNodeDemotion)) error {
 	q.quorumStatsLock.Lock()
 	defer q.quorumStatsLock.Unlock()
 
@@ -217,7 +274,7 @@ func (q *quorum) checkMerkleTreeHash(cooMerkleProof *MilestoneMerkleRoots,
 		wg.Add(1)
 
 		// ask all groups in parallel
-		go q.checkMerkleTreeHashQuorumGroup(cooMerkleProof, groupName, quorumGroupEntries, wg, quorumDoneChan, quorumErrChan, index, timestamp, parents, previousMilestoneID, onGroupEntryError)
+		go q.checkMerkleTreeHashQuorumGroup(ctx, cooMerkleProof, groupName, quorumGroupEntries, wg, quorumDoneChan, quorumErrChan, index, timestamp, parents, previousMilestoneID, onGroupEntryError, onNodeDemoted)
 	}
 
 	go func(wg *sync.WaitGroup, doneChan chan struct{}) {
@@ -248,7 +305,13 @@ func (q *quorum) quorumStatsSnapshot() []QuorumClientStatistic {
 
 	for _, quorumGroup := range q.Groups {
 		for _, entry := range quorumGroup {
-			stats = append(stats, *entry.stats)
+			stat := *entry.stats
+			if q.scoring != nil {
+				if score, exists := q.scoring.Score(stat.Group, stat.BaseURL); exists {
+					stat.Score = &score
+				}
+			}
+			stats = append(stats, stat)
 		}
 	}
 
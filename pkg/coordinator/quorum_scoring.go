@@ -0,0 +1,309 @@
+package coordinator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/syncutils"
+)
+
+const (
+	// QuorumResponseAgreed means that the node answered with the same merkle roots as the coordinator.
+	QuorumResponseAgreed QuorumResponse = iota
+	// QuorumResponseDisagreed means that the node answered with different merkle roots than the coordinator.
+	QuorumResponseDisagreed
+	// QuorumResponseFailed means that the node answered with an error or did not answer in time.
+	QuorumResponseFailed
+)
+
+var (
+	// ErrInvalidQuorumScoring is returned when the quorum scoring is configured with invalid values.
+	ErrInvalidQuorumScoring = errors.New("invalid quorum scoring")
+)
+
+// QuorumResponse is the outcome of a single quorum call of a node.
+type QuorumResponse int
+
+// QuorumNodeResult is the result of a quorum node in a single quorum call.
+type QuorumNodeResult struct {
+	// baseURL of the quorum client.
+	BaseURL string
+	// the outcome of the call.
+	Response QuorumResponse
+	// the time it took the node to answer.
+	ResponseTime time.Duration
+}
+
+// QuorumScoringOptions defines when a quorum node is demoted from the active quorum set.
+type QuorumScoringOptions struct {
+	// the amount of the latest quorum calls of a node its score is based on.
+	Window int
+	// the minimum amount of active nodes per quorum group, nodes are not demoted below that amount.
+	MinActiveNodes int
+	// the share of calls in the window a node may answer with different merkle roots before it is demoted.
+	MaxDisagreementRate float64
+	// the share of calls in the window a node may fail or not answer in time before it is demoted.
+	MaxFailureRate float64
+	// the average response time in the window above which a node is demoted (0 = disabled).
+	MaxAverageResponseTime time.Duration
+}
+
+// QuorumNodeScore is the score of a quorum node over the latest quorum calls.
+type QuorumNodeScore struct {
+	// the amount of calls in the window.
+	Calls int
+	// the amount of calls the node answered with the same merkle roots as the coordinator.
+	Agreements int
+	// the amount of calls the node answered with different merkle roots than the coordinator.
+	Disagreements int
+	// the amount of calls the node failed or did not answer in time.
+	Failures int
+	// the average response time of the calls the node answered.
+	AverageResponseTime time.Duration
+	// whether the node was demoted from the active quorum set.
+	Demoted bool
+	// the reason the node was demoted.
+	DemotionReason string
+}
+
+// DisagreementRate returns the share of calls the node answered with different merkle roots.
+func (s QuorumNodeScore) DisagreementRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+
+	return float64(s.Disagreements) / float64(s.Calls)
+}
+
+// FailureRate returns the share of calls the node failed or did not answer in time.
+func (s QuorumNodeScore) FailureRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+
+	return float64(s.Failures) / float64(s.Calls)
+}
+
+// QuorumNodeDemotion holds the information about a quorum node that was demoted from the active quorum set.
+type QuorumNodeDemotion struct {
+	// name of the quorum group the node is member of.
+	Group string
+	// optional alias of the quorum client.
+	Alias string
+	// baseURL of the quorum client.
+	BaseURL string
+	// the score of the node at the time it was demoted.
+	Score QuorumNodeScore
+}
+
+// quorumNodeScore tracks the results of the latest quorum calls of a node.
+type quorumNodeScore struct {
+	alias string
+	// the results of the latest calls, used as a ring buffer.
+	results []QuorumNodeResult
+	// the position the next result is written to.
+	next           int
+	demoted        bool
+	demotionReason string
+}
+
+// record adds the result of a quorum call to the window.
+func (n *quorumNodeScore) record(result QuorumNodeResult, window int) {
+	if len(n.results) < window {
+		n.results = append(n.results, result)
+
+		return
+	}
+
+	n.results[n.next] = result
+	n.next = (n.next + 1) % window
+}
+
+// score computes the score of the node over the window.
+func (n *quorumNodeScore) score() QuorumNodeScore {
+	score := QuorumNodeScore{
+		Calls:          len(n.results),
+		Demoted:        n.demoted,
+		DemotionReason: n.demotionReason,
+	}
+
+	var responseTimeSum time.Duration
+	for _, result := range n.results {
+		switch result.Response {
+		case QuorumResponseAgreed:
+			score.Agreements++
+		case QuorumResponseDisagreed:
+			score.Disagreements++
+		case QuorumResponseFailed:
+			score.Failures++
+
+			continue
+		}
+		responseTimeSum += result.ResponseTime
+	}
+
+	if answered := score.Agreements + score.Disagreements; answered > 0 {
+		score.AverageResponseTime = responseTimeSum / time.Duration(answered)
+	}
+
+	return score
+}
+
+// QuorumScoring tracks the correctness and the response times of the quorum nodes
+// and demotes consistently disagreeing or slow nodes from the active quorum set.
+// Demoted nodes are not asked anymore until the coordinator is restarted.
+type QuorumScoring struct {
+	opts *QuorumScoringOptions
+
+	lock syncutils.RWMutex
+	// the scores of the nodes per group and baseURL, in the order of the configuration.
+	groups map[string][]string
+	nodes  map[string]map[string]*quorumNodeScore
+}
+
+// NewQuorumScoring creates a new QuorumScoring for the nodes of the given quorum groups.
+func NewQuorumScoring(quorumGroups map[string][]*QuorumClientConfig, opts *QuorumScoringOptions) (*QuorumScoring, error) {
+	if opts.Window < 1 {
+		return nil, fmt.Errorf("%w: the window must contain at least one call, got %d", ErrInvalidQuorumScoring, opts.Window)
+	}
+	if opts.MinActiveNodes < 1 {
+		return nil, fmt.Errorf("%w: at least one node per group must stay active, got %d", ErrInvalidQuorumScoring, opts.MinActiveNodes)
+	}
+	if opts.MaxDisagreementRate < 0 || opts.MaxDisagreementRate > 1 {
+		return nil, fmt.Errorf("%w: the maximum disagreement rate must be in [0, 1], got %v", ErrInvalidQuorumScoring, opts.MaxDisagreementRate)
+	}
+	if opts.MaxFailureRate < 0 || opts.MaxFailureRate > 1 {
+		return nil, fmt.Errorf("%w: the maximum failure rate must be in [0, 1], got %v", ErrInvalidQuorumScoring, opts.MaxFailureRate)
+	}
+	if opts.MaxAverageResponseTime < 0 {
+		return nil, fmt.Errorf("%w: the maximum average response time must not be negative", ErrInvalidQuorumScoring)
+	}
+
+	scoring := &QuorumScoring{
+		opts:   opts,
+		groups: make(map[string][]string, len(quorumGroups)),
+		nodes:  make(map[string]map[string]*quorumNodeScore, len(quorumGroups)),
+	}
+
+	for groupName, groupNodes := range quorumGroups {
+		scoring.nodes[groupName] = make(map[string]*quorumNodeScore, len(groupNodes))
+		for _, client := range groupNodes {
+			if _, exists := scoring.nodes[groupName][client.BaseURL]; exists {
+				return nil, fmt.Errorf("%w: duplicate node %s in quorum group %s", ErrInvalidQuorumScoring, client.BaseURL, groupName)
+			}
+
+			scoring.groups[groupName] = append(scoring.groups[groupName], client.BaseURL)
+			scoring.nodes[groupName][client.BaseURL] = &quorumNodeScore{alias: client.Alias}
+		}
+	}
+
+	return scoring, nil
+}
+
+// IsDemoted returns whether the node of the given group was demoted from the active quorum set.
+func (s *QuorumScoring) IsDemoted(groupName string, baseURL string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	node, exists := s.nodes[groupName][baseURL]
+
+	return exists && node.demoted
+}
+
+// Score returns the score of the node of the given group.
+func (s *QuorumScoring) Score(groupName string, baseURL string) (QuorumNodeScore, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	node, exists := s.nodes[groupName][baseURL]
+	if !exists {
+		return QuorumNodeScore{}, false
+	}
+
+	return node.score(), true
+}
+
+// demotionReason returns why the node with the given score needs to be demoted, or an empty string if it doesn't.
+// Nodes are only demoted once the window is full, so a few bad calls after a restart don't demote a node.
+func (s *QuorumScoring) demotionReason(score QuorumNodeScore) string {
+	if score.Calls < s.opts.Window {
+		return ""
+	}
+
+	switch {
+	case score.DisagreementRate() > s.opts.MaxDisagreementRate:
+		return fmt.Sprintf("disagreed in %d of the last %d calls", score.Disagreements, score.Calls)
+	case score.FailureRate() > s.opts.MaxFailureRate:
+		return fmt.Sprintf("failed in %d of the last %d calls", score.Failures, score.Calls)
+	case s.opts.MaxAverageResponseTime > 0 && score.AverageResponseTime > s.opts.MaxAverageResponseTime:
+		return fmt.Sprintf("average response time of %v exceeds %v", score.AverageResponseTime.Truncate(time.Millisecond), s.opts.MaxAverageResponseTime)
+	default:
+		return ""
+	}
+}
+
+// RecordGroup records the results of the nodes of a group in a quorum call and demotes the nodes that need to be demoted.
+// The nodes are demoted in the order of the configuration, as long as the minimum amount of active nodes is kept.
+func (s *QuorumScoring) RecordGroup(groupName string, results []QuorumNodeResult) []*QuorumNodeDemotion {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	groupNodes, exists := s.nodes[groupName]
+	if !exists {
+		return nil
+	}
+
+	for _, result := range results {
+		if node, exists := groupNodes[result.BaseURL]; exists && !node.demoted {
+			node.record(result, s.opts.Window)
+		}
+	}
+
+	activeNodes := 0
+	for _, node := range groupNodes {
+		if !node.demoted {
+			activeNodes++
+		}
+	}
+
+	var demotions []*QuorumNodeDemotion
+	for _, baseURL := range s.groups[groupName] {
+		if activeNodes <= s.opts.MinActiveNodes {
+			break
+		}
+
+		node := groupNodes[baseURL]
+		if node.demoted {
+			continue
+		}
+
+		reason := s.demotionReason(node.score())
+		if reason == "" {
+			continue
+		}
+
+		node.demoted = true
+		node.demotionReason = reason
+		activeNodes--
+
+		demotions = append(demotions, &QuorumNodeDemotion{
+			Group:   groupName,
+			Alias:   node.alias,
+			BaseURL: baseURL,
+			Score:   node.score(),
+		})
+	}
+
+	return demotions
+}
+
+// WithQuorumScoring defines the scoring of the quorum nodes, which demotes consistently disagreeing or slow nodes (nil = disabled).
+// The scoring is only used if the quorum is enabled.
+func WithQuorumScoring(quorumScoring *QuorumScoring) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.quorumScoring = quorumScoring
+	}
+}
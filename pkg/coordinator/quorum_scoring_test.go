@@ -0,0 +1,160 @@
+package coordinator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+var testQuorumGroups = map[string][]*coordinator.QuorumClientConfig{
+	"group1": {
+		{Alias: "node1", BaseURL: "http://node1:14265"},
+		{Alias: "node2", BaseURL: "http://node2:14265"},
+		{Alias: "node3", BaseURL: "http://node3:14265"},
+	},
+	"group2": {
+		{Alias: "node4", BaseURL: "http://node4:14265"},
+	},
+}
+
+func newTestQuorumScoringOptions() *coordinator.QuorumScoringOptions {
+	return &coordinator.QuorumScoringOptions{
+		Window:                 4,
+		MinActiveNodes:         1,
+		MaxDisagreementRate:    0.25,
+		MaxFailureRate:         0.5,
+		MaxAverageResponseTime: time.Second,
+	}
+}
+
+func quorumResult(baseURL string, response coordinator.QuorumResponse, responseTime time.Duration) coordinator.QuorumNodeResult {
+	return coordinator.QuorumNodeResult{BaseURL: baseURL, Response: response, ResponseTime: responseTime}
+}
+
+func TestNewQuorumScoring(t *testing.T) {
+	_, err := coordinator.NewQuorumScoring(testQuorumGroups, newTestQuorumScoringOptions())
+	require.NoError(t, err)
+
+	for _, modify := range []func(opts *coordinator.QuorumScoringOptions){
+		func(opts *coordinator.QuorumScoringOptions) { opts.Window = 0 },
+		func(opts *coordinator.QuorumScoringOptions) { opts.MinActiveNodes = 0 },
+		func(opts *coordinator.QuorumScoringOptions) { opts.MaxDisagreementRate = 1.5 },
+		func(opts *coordinator.QuorumScoringOptions) { opts.MaxFailureRate = -0.1 },
+		func(opts *coordinator.QuorumScoringOptions) { opts.MaxAverageResponseTime = -time.Second },
+	} {
+		opts := newTestQuorumScoringOptions()
+		modify(opts)
+		_, err := coordinator.NewQuorumScoring(testQuorumGroups, opts)
+		require.ErrorIs(t, err, coordinator.ErrInvalidQuorumScoring)
+	}
+
+	_, err = coordinator.NewQuorumScoring(map[string][]*coordinator.QuorumClientConfig{
+		"group1": {{BaseURL: "http://node1:14265"}, {BaseURL: "http://node1:14265"}},
+	}, newTestQuorumScoringOptions())
+	require.ErrorIs(t, err, coordinator.ErrInvalidQuorumScoring)
+}
+
+func TestQuorumScoringDemotion(t *testing.T) {
+	scoring, err := coordinator.NewQuorumScoring(testQuorumGroups, newTestQuorumScoringOptions())
+	require.NoError(t, err)
+
+	record := func(node1 coordinator.QuorumResponse, node2 coordinator.QuorumResponse, node3 coordinator.QuorumResponse) []*coordinator.QuorumNodeDemotion {
+		return scoring.RecordGroup("group1", []coordinator.QuorumNodeResult{
+			quorumResult("http://node1:14265", node1, 100*time.Millisecond),
+			quorumResult("http://node2:14265", node2, 100*time.Millisecond),
+			quorumResult("http://node3:14265", node3, 100*time.Millisecond),
+		})
+	}
+
+	// nodes are not demoted before the window is full
+	for i := 0; i < 3; i++ {
+		require.Empty(t, record(coordinator.QuorumResponseAgreed, coordinator.QuorumResponseDisagreed, coordinator.QuorumResponseAgreed))
+	}
+
+	score, exists := scoring.Score("group1", "http://node2:14265")
+	require.True(t, exists)
+	require.Equal(t, 3, score.Calls)
+	require.Equal(t, 3, score.Disagreements)
+	require.Equal(t, 100*time.Millisecond, score.AverageResponseTime)
+	require.False(t, score.Demoted)
+
+	// the disagreeing node is demoted once the window is full
+	demotions := record(coordinator.QuorumResponseAgreed, coordinator.QuorumResponseAgreed, coordinator.QuorumResponseAgreed)
+	require.Len(t, demotions, 1)
+	require.Equal(t, "group1", demotions[0].Group)
+	require.Equal(t, "node2", demotions[0].Alias)
+	require.Equal(t, "http://node2:14265", demotions[0].BaseURL)
+	require.True(t, demotions[0].Score.Demoted)
+	require.Contains(t, demotions[0].Score.DemotionReason, "disagreed in 3 of the last 4 calls")
+	require.True(t, scoring.IsDemoted("group1", "http://node2:14265"))
+	require.False(t, scoring.IsDemoted("group1", "http://node1:14265"))
+
+	// the results of demoted nodes are ignored
+	require.Empty(t, record(coordinator.QuorumResponseAgreed, coordinator.QuorumResponseAgreed, coordinator.QuorumResponseAgreed))
+	score, _ = scoring.Score("group1", "http://node2:14265")
+	require.Equal(t, 3, score.Disagreements)
+
+	// a slow node is demoted
+	require.Empty(t, scoring.RecordGroup("group1", []coordinator.QuorumNodeResult{
+		quorumResult("http://node1:14265", coordinator.QuorumResponseAgreed, 100*time.Millisecond),
+		quorumResult("http://node3:14265", coordinator.QuorumResponseAgreed, 3*time.Second),
+	}))
+	demotions = scoring.RecordGroup("group1", []coordinator.QuorumNodeResult{
+		quorumResult("http://node1:14265", coordinator.QuorumResponseAgreed, 100*time.Millisecond),
+		quorumResult("http://node3:14265", coordinator.QuorumResponseAgreed, 3*time.Second),
+	})
+	require.Len(t, demotions, 1)
+	require.Equal(t, "http://node3:14265", demotions[0].BaseURL)
+	require.Contains(t, demotions[0].Score.DemotionReason, "average response time")
+
+	// the last active node of the group is never demoted
+	for i := 0; i < 8; i++ {
+		require.Empty(t, scoring.RecordGroup("group1", []coordinator.QuorumNodeResult{
+			quorumResult("http://node1:14265", coordinator.QuorumResponseFailed, time.Second),
+		}))
+	}
+	score, _ = scoring.Score("group1", "http://node1:14265")
+	require.Equal(t, 4, score.Calls)
+	require.Equal(t, 4, score.Failures)
+	require.Equal(t, 1.0, score.FailureRate())
+	require.False(t, score.Demoted)
+
+	// unknown groups are ignored
+	require.Empty(t, scoring.RecordGroup("unknown", []coordinator.QuorumNodeResult{quorumResult("http://node1:14265", coordinator.QuorumResponseFailed, 0)}))
+	_, exists = scoring.Score("unknown", "http://node1:14265")
+	require.False(t, exists)
+}
+
+func TestQuorumScoringWindow(t *testing.T) {
+	scoring, err := coordinator.NewQuorumScoring(testQuorumGroups, newTestQuorumScoringOptions())
+	require.NoError(t, err)
+
+	record := func(response coordinator.QuorumResponse) []*coordinator.QuorumNodeDemotion {
+		return scoring.RecordGroup("group1", []coordinator.QuorumNodeResult{
+			quorumResult("http://node1:14265", coordinator.QuorumResponseAgreed, 100*time.Millisecond),
+			quorumResult("http://node2:14265", response, 100*time.Millisecond),
+		})
+	}
+
+	// a single failure in the window is tolerated
+	require.Empty(t, record(coordinator.QuorumResponseFailed))
+	for i := 0; i < 5; i++ {
+		require.Empty(t, record(coordinator.QuorumResponseAgreed))
+	}
+
+	// old results drop out of the window
+	score, _ := scoring.Score("group1", "http://node2:14265")
+	require.Equal(t, 4, score.Calls)
+	require.Equal(t, 4, score.Agreements)
+	require.Zero(t, score.Failures)
+
+	// too many failures in the window demote the node
+	require.Empty(t, record(coordinator.QuorumResponseFailed))
+	require.Empty(t, record(coordinator.QuorumResponseFailed))
+	demotions := record(coordinator.QuorumResponseFailed)
+	require.Len(t, demotions, 1)
+	require.Contains(t, demotions[0].Score.DemotionReason, "failed in 3 of the last 4 calls")
+}
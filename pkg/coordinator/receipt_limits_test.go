@@ -0,0 +1,110 @@
+package coordinator_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// protoParamsWithMinPoWScore returns the protocol parameters of the tests with the given minimum PoW score.
+func protoParamsWithMinPoWScore(minPoWScore uint32) *iotago.ProtocolParameters {
+	protoParams := *testProtoParams
+	protoParams.MinPoWScore = minPoWScore
+
+	return &protoParams
+}
+
+// receiptLimits returns the receipt limits of a migrator used by a coordinator with the given minimum PoW score
+// and amount of parents and milestone keys.
+func receiptLimits(t *testing.T, minPoWScore uint32, parentsCount int, keysCount int, opts ...options.Option[coordinator.Coordinator]) migrator.ReceiptLimits {
+	t.Helper()
+
+	protoParams := protoParamsWithMinPoWScore(minPoWScore)
+	migratorService := migrator.NewService(nil, filepath.Join(t.TempDir(), "migrator.state"), 0)
+	(&testCoordinatorDeps{
+		signerProvider:  newTestSignerProvider(t, keysCount),
+		migratorService: migratorService,
+		protoParamsFunc: func() *iotago.ProtocolParameters { return protoParams },
+	}).createCoordinator(t, nil, append([]options.Option[coordinator.Coordinator]{coordinator.WithMilestoneParentsCount(parentsCount)}, opts...)...)
+
+	return migratorService.ReceiptLimits()
+}
+
+func TestMilestonePoWStepSize(t *testing.T) {
+	require.Equal(t, 10761, coordinator.MilestonePoWStepSize(4000))
+	require.Equal(t, 9565, coordinator.MilestonePoWStepSize(1500))
+	require.Equal(t, iotago.BlockBinSerializedMaxSize, coordinator.MilestonePoWStepSize(0))
+}
+
+func TestReceiptLimitsDerivedFromMilestone(t *testing.T) {
+	entry := &iotago.MigratedFundsEntry{Address: &iotago.Ed25519Address{}}
+	entrySize := migrator.ReceiptSize(0, []*iotago.MigratedFundsEntry{entry}) - migrator.ReceiptSize(0, nil)
+
+	// the reference milestone of the former fixed limit
+	reference := receiptLimits(t, 4000, 8, 2)
+	require.Equal(t, reference.DerivedMaxEntries, reference.MaxEntries)
+	require.Greater(t, reference.MaxReceiptSize, reference.PoWStepReceiptSize)
+	require.InDelta(t, migrator.SensibleMaxEntriesCount, reference.DerivedMaxEntries, 2)
+
+	// the receipt with the derived amount of entries stays below the PoW requirement step, one more entry doesn't
+	require.LessOrEqual(t, migrator.ReceiptSize(0, make([]*iotago.MigratedFundsEntry, reference.DerivedMaxEntries)), reference.PoWStepReceiptSize)
+	require.Greater(t, migrator.ReceiptSize(0, make([]*iotago.MigratedFundsEntry, reference.DerivedMaxEntries+1)), reference.PoWStepReceiptSize)
+
+	// fewer parents or signatures leave room for more entries, the parents are part of the block and of the milestone essence
+	fewerParents := receiptLimits(t, 4000, 1, 2)
+	require.Equal(t, reference.PoWStepReceiptSize+2*7*iotago.BlockIDLength, fewerParents.PoWStepReceiptSize)
+	require.Greater(t, fewerParents.DerivedMaxEntries, reference.DerivedMaxEntries)
+
+	fewerSignatures := receiptLimits(t, 4000, 8, 1)
+	require.Greater(t, fewerSignatures.PoWStepReceiptSize, reference.PoWStepReceiptSize)
+	require.GreaterOrEqual(t, fewerSignatures.DerivedMaxEntries, reference.DerivedMaxEntries)
+
+	// more signatures need more room
+	moreSignatures := receiptLimits(t, 4000, 8, 10)
+	require.Less(t, moreSignatures.PoWStepReceiptSize, reference.PoWStepReceiptSize)
+	require.Equal(t, (moreSignatures.PoWStepReceiptSize-migrator.ReceiptSize(0, nil))/entrySize, moreSignatures.DerivedMaxEntries)
+	require.Less(t, moreSignatures.DerivedMaxEntries, reference.DerivedMaxEntries)
+
+	// the metadata is part of every milestone
+	withMetadata := receiptLimits(t, 4000, 8, 2, coordinator.WithMilestoneMetadata(make([]byte, 200)))
+	require.Less(t, withMetadata.DerivedMaxEntries, reference.DerivedMaxEntries)
+
+	// a lower minimum PoW score moves the step
+	lowerScore := receiptLimits(t, 1500, 8, 2)
+	require.Equal(t, reference.MaxReceiptSize, lowerScore.MaxReceiptSize)
+	require.Equal(t, reference.PoWStepReceiptSize-(10761-9565), lowerScore.PoWStepReceiptSize)
+
+	// without a minimum PoW score, the receipts are only limited by the maximum block size
+	noPoW := receiptLimits(t, 0, 8, 2)
+	require.Equal(t, noPoW.MaxReceiptSize, noPoW.PoWStepReceiptSize)
+}
+
+func TestReceiptLimitsProtocolParametersUpdate(t *testing.T) {
+	var protoParams atomic.Pointer[iotago.ProtocolParameters]
+	protoParams.Store(protoParamsWithMinPoWScore(4000))
+
+	migratorService := migrator.NewService(nil, filepath.Join(t.TempDir(), "migrator.state"), 0)
+	coo, milestoneBlockID := (&testCoordinatorDeps{
+		migratorService: migratorService,
+		protoParamsFunc: protoParams.Load,
+	}).newCoordinator(t, nil)
+	before := migratorService.ReceiptLimits()
+
+	// the limits are derived again with the next milestone after the update
+	protoParams.Store(protoParamsWithMinPoWScore(1500))
+	_, err := coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+
+	after := migratorService.ReceiptLimits()
+	require.Equal(t, before.MaxReceiptSize, after.MaxReceiptSize)
+	require.Equal(t, before.PoWStepReceiptSize-(10761-9565), after.PoWStepReceiptSize)
+	require.Less(t, after.DerivedMaxEntries, before.DerivedMaxEntries)
+}
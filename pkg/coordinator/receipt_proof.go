@@ -0,0 +1,315 @@
+package coordinator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/ioutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrReceiptProofNotFound is returned when no inclusion proof exists for a milestone.
+	ErrReceiptProofNotFound = errors.New("receipt inclusion proof not found")
+	// ErrReceiptProofInvalid is returned when a receipt inclusion proof can't be verified.
+	ErrReceiptProofInvalid = errors.New("invalid receipt inclusion proof")
+)
+
+// ReceiptProof proves that a receipt was included in a confirmed milestone.
+// The receipt is part of the signed milestone essence, so the proof consists of the signed milestone payload
+// and the position of the receipt within the milestone options. It can be verified with the milestone public keys only,
+// without access to a node.
+type ReceiptProof struct {
+	// the index of the milestone that contains the receipt.
+	MilestoneIndex iotago.MilestoneIndex `json:"milestoneIndex"`
+	// the ID of the milestone that contains the receipt.
+	MilestoneID iotago.MilestoneID `json:"milestoneId"`
+	// the ID of the block that contains the milestone.
+	BlockID iotago.BlockID `json:"blockId"`
+	// the index of the receipt within the milestone options.
+	ReceiptOptionIndex int `json:"receiptOptionIndex"`
+	// the signed milestone payload.
+	Milestone json.RawMessage `json:"milestone"`
+	// the signature of the treasury key over the receipt essence, if the receipt was co-signed by the treasury key.
+	TreasurySignature *iotago.Ed25519Signature `json:"treasurySignature,omitempty"`
+}
+
+// NewReceiptProof creates the inclusion proof of the receipt contained in the given milestone.
+func NewReceiptProof(milestone *iotago.Milestone, blockID iotago.BlockID) (*ReceiptProof, error) {
+
+	receiptOptionIndex := -1
+	for i, opt := range milestone.Opts {
+		if _, ok := opt.(*iotago.ReceiptMilestoneOpt); ok {
+			receiptOptionIndex = i
+
+			break
+		}
+	}
+	if receiptOptionIndex == -1 {
+		return nil, fmt.Errorf("milestone %d does not contain a receipt", milestone.Index)
+	}
+
+	milestoneID, err := milestone.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	milestoneJSON, err := milestone.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReceiptProof{
+		MilestoneIndex:     milestone.Index,
+		MilestoneID:        milestoneID,
+		BlockID:            blockID,
+		ReceiptOptionIndex: receiptOptionIndex,
+		Milestone:          milestoneJSON,
+	}, nil
+}
+
+// Verify checks that the milestone of the proof matches its ID and was signed by at least minSigThreshold
+// of the given milestone public keys, and returns the included receipt.
+func (p *ReceiptProof) Verify(minSigThreshold int, publicKeys iotago.MilestonePublicKeySet) (*iotago.ReceiptMilestoneOpt, error) {
+
+	milestone := &iotago.Milestone{}
+	if err := milestone.UnmarshalJSON(p.Milestone); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReceiptProofInvalid, err)
+	}
+
+	if milestone.Index != p.MilestoneIndex {
+		return nil, fmt.Errorf("%w: milestone index mismatch, expected %d, got %d", ErrReceiptProofInvalid, p.MilestoneIndex, milestone.Index)
+	}
+
+	milestoneID, err := milestone.ID()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReceiptProofInvalid, err)
+	}
+
+	if milestoneID != p.MilestoneID {
+		return nil, fmt.Errorf("%w: milestone ID mismatch, expected %s, got %s", ErrReceiptProofInvalid, p.MilestoneID.ToHex(), milestoneID.ToHex())
+	}
+
+	if err := milestone.VerifySignatures(minSigThreshold, publicKeys); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReceiptProofInvalid, err)
+	}
+
+	return p.receiptOption(milestone)
+}
+
+// Receipt returns the receipt contained in the milestone of the proof without verifying the milestone signatures.
+func (p *ReceiptProof) Receipt() (*iotago.ReceiptMilestoneOpt, error) {
+
+	milestone := &iotago.Milestone{}
+	if err := milestone.UnmarshalJSON(p.Milestone); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReceiptProofInvalid, err)
+	}
+
+	return p.receiptOption(milestone)
+}
+
+// receiptOption returns the receipt at the receipt option index of the milestone.
+func (p *ReceiptProof) receiptOption(milestone *iotago.Milestone) (*iotago.ReceiptMilestoneOpt, error) {
+	if p.ReceiptOptionIndex < 0 || p.ReceiptOptionIndex >= len(milestone.Opts) {
+		return nil, fmt.Errorf("%w: receipt option index %d out of range", ErrReceiptProofInvalid, p.ReceiptOptionIndex)
+	}
+
+	receipt, ok := milestone.Opts[p.ReceiptOptionIndex].(*iotago.ReceiptMilestoneOpt)
+	if !ok {
+		return nil, fmt.Errorf("%w: milestone option %d is not a receipt", ErrReceiptProofInvalid, p.ReceiptOptionIndex)
+	}
+
+	return receipt, nil
+}
+
+// VerifyTreasurySignature checks that the signature of the treasury key stored with the proof was created
+// by the given treasury key over the included receipt. A proof without a treasury signature is invalid,
+// because the receipt was not authorized by the treasury key.
+func (p *ReceiptProof) VerifyTreasurySignature(publicKey iotago.MilestonePublicKey, protoParams *iotago.ProtocolParameters) error {
+	if p.TreasurySignature == nil {
+		return fmt.Errorf("%w: the proof of milestone %d has no treasury signature", ErrInvalidTreasurySignature, p.MilestoneIndex)
+	}
+
+	receipt, err := p.Receipt()
+	if err != nil {
+		return err
+	}
+
+	essence, err := ReceiptEssence(receipt, protoParams)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrReceiptProofInvalid, err)
+	}
+
+	if err := VerifyTreasurySignature(publicKey, essence, p.TreasurySignature); err != nil {
+		return fmt.Errorf("%w: %s", ErrReceiptProofInvalid, err)
+	}
+
+	return nil
+}
+
+// ReceiptProofStore stores the inclusion proofs of the receipts on disk, one file per milestone.
+type ReceiptProofStore struct {
+	// the path to the folder where the proofs are stored.
+	folderPath string
+	// the public key of the treasury key the stored treasury signatures are verified with (nil = not verified).
+	treasuryPublicKey *iotago.MilestonePublicKey
+	// returns the protocol parameters that were valid at the milestones of the receipts, which they were signed with by the treasury key.
+	protoParamsAtFunc ProtocolParametersAtFunc
+}
+
+// WithReceiptProofTreasuryKey defines the treasury key the treasury signatures of the proofs are verified with when they are loaded,
+// with the protocol parameters that were valid at the milestone of the proof.
+func WithReceiptProofTreasuryKey(publicKey iotago.MilestonePublicKey, protoParamsAtFunc ProtocolParametersAtFunc) options.Option[ReceiptProofStore] {
+	return func(s *ReceiptProofStore) {
+		s.treasuryPublicKey = &publicKey
+		s.protoParamsAtFunc = protoParamsAtFunc
+	}
+}
+
+// NewReceiptProofStore creates a new ReceiptProofStore and creates the folder if it does not exist.
+func NewReceiptProofStore(folderPath string, opts ...options.Option[ReceiptProofStore]) (*ReceiptProofStore, error) {
+	if folderPath == "" {
+		return nil, errors.New("no receipt proofs folder path specified")
+	}
+
+	if err := os.MkdirAll(folderPath, 0o700); err != nil {
+		return nil, fmt.Errorf("receipt proofs folder path (%s) can't be created, error: %w", folderPath, err)
+	}
+
+	return options.Apply(&ReceiptProofStore{folderPath: folderPath}, opts), nil
+}
+
+func (s *ReceiptProofStore) filePath(index iotago.MilestoneIndex) string {
+	return path.Join(s.folderPath, fmt.Sprintf("receipt_%d.json", index))
+}
+
+// Store persists the proof.
+func (s *ReceiptProofStore) Store(proof *ReceiptProof) error {
+	if err := ioutils.WriteJSONToFile(s.filePath(proof.MilestoneIndex), proof, 0o600); err != nil {
+		return fmt.Errorf("storing receipt inclusion proof failed: %w", err)
+	}
+
+	return nil
+}
+
+// Proof returns the proof of the receipt contained in the milestone with the given index.
+func (s *ReceiptProofStore) Proof(index iotago.MilestoneIndex) (*ReceiptProof, error) {
+	filePath := s.filePath(index)
+
+	if _, err := os.Stat(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: milestone %d", ErrReceiptProofNotFound, index)
+		}
+
+		return nil, err
+	}
+
+	proof := &ReceiptProof{}
+	if err := ioutils.ReadJSONFromFile(filePath, proof); err != nil {
+		return nil, fmt.Errorf("unable to load receipt inclusion proof: %w", err)
+	}
+
+	// a tampered or stripped treasury signature must not be served as an authorization of the receipt
+	if s.treasuryPublicKey != nil {
+		protoParams, err := s.protoParamsAtFunc(proof.MilestoneIndex)
+		if err != nil {
+			return nil, fmt.Errorf("milestone %d: %w", index, err)
+		}
+
+		if err := proof.VerifyTreasurySignature(*s.treasuryPublicKey, protoParams); err != nil {
+			return nil, fmt.Errorf("milestone %d: %w", index, err)
+		}
+	}
+
+	return proof, nil
+}
+
+// indices returns the milestone indices of all stored proofs in ascending order.
+func (s *ReceiptProofStore) indices() ([]iotago.MilestoneIndex, error) {
+	files, err := os.ReadDir(s.folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list receipt inclusion proofs: %w", err)
+	}
+
+	indices := make([]iotago.MilestoneIndex, 0, len(files))
+	for _, file := range files {
+		var index iotago.MilestoneIndex
+		if file.IsDir() {
+			continue
+		}
+		if _, err := fmt.Sscanf(file.Name(), "receipt_%d.json", &index); err != nil {
+			continue
+		}
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	return indices, nil
+}
+
+// LatestProof returns the stored proof with the highest milestone index, or nil if no proof is stored.
+func (s *ReceiptProofStore) LatestProof() (*ReceiptProof, error) {
+	indices, err := s.indices()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(indices) == 0 {
+		return nil, nil
+	}
+
+	return s.Proof(indices[len(indices)-1])
+}
+
+// Proofs returns all stored proofs ordered by their milestone index.
+func (s *ReceiptProofStore) Proofs() ([]*ReceiptProof, error) {
+	indices, err := s.indices()
+	if err != nil {
+		return nil, err
+	}
+
+	proofs := make([]*ReceiptProof, 0, len(indices))
+	for _, index := range indices {
+		proof, err := s.Proof(index)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+
+	return proofs, nil
+}
+
+// WithReceiptProofStore defines the store the inclusion proofs of confirmed receipts are written to.
+func WithReceiptProofStore(receiptProofStore *ReceiptProofStore) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.receiptProofStore = receiptProofStore
+	}
+}
+
+// storeReceiptProof creates and stores the inclusion proof of the receipt in the confirmed milestone block,
+// together with the signature of the treasury key over the receipt if it was co-signed.
+func (coo *Coordinator) storeReceiptProof(milestoneBlock *iotago.Block, blockID iotago.BlockID, treasurySignature *iotago.Ed25519Signature) error {
+	if coo.receiptProofStore == nil {
+		return nil
+	}
+
+	milestone, ok := milestoneBlock.Payload.(*iotago.Milestone)
+	if !ok {
+		return errors.New("block does not contain a milestone")
+	}
+
+	proof, err := NewReceiptProof(milestone, blockID)
+	if err != nil {
+		return err
+	}
+	proof.TreasurySignature = treasurySignature
+
+	return coo.receiptProofStore.Store(proof)
+}
@@ -0,0 +1,245 @@
+package coordinator_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func newTestReceiptMilestone(t *testing.T, keysCount int) (*iotago.Milestone, iotago.MilestonePublicKeySet) {
+	keyMapping := iotago.MilestonePublicKeyMapping{}
+	publicKeySet := iotago.MilestonePublicKeySet{}
+	publicKeys := make([]iotago.MilestonePublicKey, 0, keysCount)
+	for i := 0; i < keysCount; i++ {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		var milestonePublicKey iotago.MilestonePublicKey
+		copy(milestonePublicKey[:], publicKey)
+		keyMapping[milestonePublicKey] = privateKey
+		publicKeySet[milestonePublicKey] = struct{}{}
+		publicKeys = append(publicKeys, milestonePublicKey)
+	}
+
+	milestone := iotago.NewMilestone(42, 1000, 2, iotago.MilestoneID{1}, iotago.BlockIDs{{2}}, iotago.MilestoneMerkleProof{3}, iotago.MilestoneMerkleProof{4})
+	milestone.Opts = iotago.MilestoneOpts{
+		&iotago.ReceiptMilestoneOpt{
+			MigratedAt: 7,
+			Final:      true,
+			Funds: []*iotago.MigratedFundsEntry{
+				{
+					TailTransactionHash: iotago.LegacyTailTransactionHash{5},
+					Address:             &iotago.Ed25519Address{6},
+					Deposit:             1_000_000,
+				},
+			},
+			Transaction: &iotago.TreasuryTransaction{
+				Input:  &iotago.TreasuryInput{8},
+				Output: &iotago.TreasuryOutput{Amount: 10_000_000},
+			},
+		},
+	}
+	require.NoError(t, milestone.Sign(publicKeys, iotago.InMemoryEd25519MilestoneSigner(keyMapping)))
+
+	return milestone, publicKeySet
+}
+
+func TestReceiptProof(t *testing.T) {
+	milestone, publicKeySet := newTestReceiptMilestone(t, 2)
+
+	proof, err := coordinator.NewReceiptProof(milestone, iotago.BlockID{9})
+	require.NoError(t, err)
+	require.EqualValues(t, 42, proof.MilestoneIndex)
+	require.Equal(t, milestone.MustID(), proof.MilestoneID)
+	require.Equal(t, 0, proof.ReceiptOptionIndex)
+
+	receipt, err := proof.Verify(2, publicKeySet)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, receipt.MigratedAt)
+	require.Len(t, receipt.Funds, 1)
+
+	// the milestone must be signed by the expected keys
+	_, otherKeySet := newTestReceiptMilestone(t, 2)
+	_, err = proof.Verify(2, otherKeySet)
+	require.ErrorIs(t, err, coordinator.ErrReceiptProofInvalid)
+
+	// the milestone must match the milestone ID
+	tampered := *proof
+	tampered.MilestoneID = iotago.MilestoneID{1}
+	_, err = tampered.Verify(2, publicKeySet)
+	require.ErrorIs(t, err, coordinator.ErrReceiptProofInvalid)
+
+	// the receipt option index must point to the receipt
+	tampered = *proof
+	tampered.ReceiptOptionIndex = 1
+	_, err = tampered.Verify(2, publicKeySet)
+	require.ErrorIs(t, err, coordinator.ErrReceiptProofInvalid)
+
+	// milestones without receipts can't be proven
+	milestone.Opts = nil
+	_, err = coordinator.NewReceiptProof(milestone, iotago.BlockID{9})
+	require.Error(t, err)
+}
+
+func TestReceiptProofStore(t *testing.T) {
+	store, err := coordinator.NewReceiptProofStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = store.Proof(42)
+	require.ErrorIs(t, err, coordinator.ErrReceiptProofNotFound)
+
+	milestone, publicKeySet := newTestReceiptMilestone(t, 1)
+	proof, err := coordinator.NewReceiptProof(milestone, iotago.BlockID{9})
+	require.NoError(t, err)
+	require.NoError(t, store.Store(proof))
+
+	loaded, err := store.Proof(42)
+	require.NoError(t, err)
+	require.Equal(t, proof.MilestoneID, loaded.MilestoneID)
+	require.Equal(t, proof.BlockID, loaded.BlockID)
+	require.Nil(t, loaded.TreasurySignature)
+
+	_, err = loaded.Verify(1, publicKeySet)
+	require.NoError(t, err)
+
+	// all proofs can be listed, e.g. to find the receipts of a legacy milestone
+	milestone.Index = 43
+	proof, err = coordinator.NewReceiptProof(milestone, iotago.BlockID{10})
+	require.NoError(t, err)
+	proof.TreasurySignature = &iotago.Ed25519Signature{PublicKey: [32]byte{11}, Signature: [64]byte{12}}
+	require.NoError(t, store.Store(proof))
+
+	proofs, err := store.Proofs()
+	require.NoError(t, err)
+	require.Len(t, proofs, 2)
+	require.EqualValues(t, 42, proofs[0].MilestoneIndex)
+	require.EqualValues(t, 43, proofs[1].MilestoneIndex)
+	require.Equal(t, proof.TreasurySignature, proofs[1].TreasurySignature)
+
+	receipt, err := proofs[1].Receipt()
+	require.NoError(t, err)
+	require.EqualValues(t, 7, receipt.MigratedAt)
+}
+
+func TestReceiptProofTreasurySignature(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	entries := []*iotago.MigratedFundsEntry{
+		{
+			TailTransactionHash: iotago.LegacyTailTransactionHash{1},
+			Address:             &iotago.Ed25519Address{2},
+			Deposit:             1_000_000,
+		},
+	}
+
+	migratorService := migrator.NewService(&testMigrationsQueryer{migratedAt: 2, entries: entries}, filepath.Join(t.TempDir(), "migrator.state"), 0)
+	legacyIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, migratorService.InitState(ctx, &legacyIndex))
+
+	_, treasuryPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	treasurySigner := coordinator.NewInMemoryEd25519TreasurySigner(treasuryPrivateKey)
+
+	storePath := t.TempDir()
+	store, err := coordinator.NewReceiptProofStore(storePath)
+	require.NoError(t, err)
+
+	coo, milestoneBlockID := (&testCoordinatorDeps{migratorService: migratorService}).newCoordinator(t, nil,
+		coordinator.WithTreasurySigner(treasurySigner),
+		coordinator.WithReceiptProofStore(store),
+	)
+
+	var signature *iotago.Ed25519Signature
+	coo.Events.ReceiptSigned.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, receiptSignature *iotago.Ed25519Signature) {
+		signature = receiptSignature
+	}))
+	var receiptIndex iotago.MilestoneIndex
+	coo.Events.ReceiptIssued.Hook(events.NewClosure(func(index iotago.MilestoneIndex, _ *iotago.ReceiptMilestoneOpt, _ int) {
+		receiptIndex = index
+	}))
+
+	migratorCtx, migratorCancel := context.WithCancel(ctx)
+	migratorDone := make(chan struct{})
+	go func() {
+		defer close(migratorDone)
+		migratorService.Start(migratorCtx, func(err error) bool { return false })
+	}()
+	defer func() {
+		migratorCancel()
+		<-migratorDone
+	}()
+
+	require.Eventually(t, func() bool {
+		milestoneBlockID, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+		require.NoError(t, err)
+
+		return receiptIndex != 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the signature of the treasury key is stored with the receipt
+	proof, err := store.Proof(receiptIndex)
+	require.NoError(t, err)
+	require.NotNil(t, proof.TreasurySignature)
+	require.Equal(t, signature, proof.TreasurySignature)
+
+	receipt, err := proof.Receipt()
+	require.NoError(t, err)
+	essence, err := coordinator.ReceiptEssence(receipt, testProtoParams)
+	require.NoError(t, err)
+	require.NoError(t, coordinator.VerifyTreasurySignature(treasurySigner.PublicKey(), essence, proof.TreasurySignature))
+
+	// the treasury signature is verified when the proof is loaded by a store that knows the treasury key
+	verifyingStore, err := coordinator.NewReceiptProofStore(storePath, coordinator.WithReceiptProofTreasuryKey(treasurySigner.PublicKey(), func(_ iotago.MilestoneIndex) (*iotago.ProtocolParameters, error) {
+		return testProtoParams, nil
+	}))
+	require.NoError(t, err)
+	loaded, err := verifyingStore.Proof(receiptIndex)
+	require.NoError(t, err)
+	require.Equal(t, proof.TreasurySignature, loaded.TreasurySignature)
+
+	// a tampered treasury signature is rejected
+	tampered := *proof.TreasurySignature
+	tampered.Signature[0] ^= 0xff
+	proof.TreasurySignature = &tampered
+	require.NoError(t, store.Store(proof))
+	_, err = verifyingStore.Proof(receiptIndex)
+	require.ErrorIs(t, err, coordinator.ErrReceiptProofInvalid)
+	_, err = verifyingStore.Proofs()
+	require.ErrorIs(t, err, coordinator.ErrReceiptProofInvalid)
+
+	// a signature of another key is rejected as well
+	_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherSigner := coordinator.NewInMemoryEd25519TreasurySigner(otherPrivateKey)
+	otherSignatures, err := otherSigner.SigningFunc()([]iotago.MilestonePublicKey{otherSigner.PublicKey()}, essence)
+	require.NoError(t, err)
+	proof.TreasurySignature = &iotago.Ed25519Signature{PublicKey: otherSigner.PublicKey(), Signature: otherSignatures[0]}
+	require.NoError(t, store.Store(proof))
+	_, err = verifyingStore.Proof(receiptIndex)
+	require.ErrorIs(t, err, coordinator.ErrReceiptProofInvalid)
+
+	// a stripped treasury signature is rejected, but the proof can still be loaded by a store that doesn't know the treasury key
+	proof.TreasurySignature = nil
+	require.NoError(t, store.Store(proof))
+	_, err = verifyingStore.Proof(receiptIndex)
+	require.ErrorIs(t, err, coordinator.ErrInvalidTreasurySignature)
+	_, err = store.Proof(receiptIndex)
+	require.NoError(t, err)
+
+	// milestones without a receipt don't have a proof
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID})
+	require.NoError(t, err)
+	_, err = store.Proof(coo.State().LatestMilestoneIndex)
+	require.ErrorIs(t, err, coordinator.ErrReceiptProofNotFound)
+}
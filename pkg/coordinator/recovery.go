@@ -0,0 +1,121 @@
+package coordinator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+const (
+	// CatchUpPolicyImmediate issues a single milestone immediately after a gap was detected,
+	// afterwards milestones are issued in the normal interval again.
+	CatchUpPolicyImmediate = "immediate"
+	// CatchUpPolicyGradual issues several milestones in the catch-up interval after a gap was detected,
+	// so that the blocks that were attached during the downtime are confirmed in smaller cones.
+	CatchUpPolicyGradual = "gradual"
+
+	defaultMaxCatchUpMilestones = 10
+	defaultCatchUpInterval      = time.Second
+)
+
+var (
+	// ErrUnknownCatchUpPolicy is returned when an unknown catch-up policy is configured.
+	ErrUnknownCatchUpPolicy = errors.New("unknown catch-up policy")
+)
+
+// RecoveryPlan describes how the coordinator catches up after it was not issuing milestones for more than one interval,
+// e.g. because it was down for hours.
+type RecoveryPlan struct {
+	// the time of the latest milestone issued before the gap.
+	LatestMilestoneTime time.Time
+	// the duration since the latest milestone was issued.
+	Gap time.Duration
+	// the amount of milestone intervals that were missed.
+	MissedIntervals int
+	// the catch-up policy that is applied.
+	Policy string
+	// the amount of milestones that are issued to catch up.
+	CatchUpMilestones int
+	// the interval the catch-up milestones are issued in.
+	CatchUpInterval time.Duration
+}
+
+func (p *RecoveryPlan) String() string {
+	return fmt.Sprintf("gap: %v, missed intervals: %d, policy: %s, catch-up milestones: %d, catch-up interval: %v",
+		p.Gap.Truncate(time.Second), p.MissedIntervals, p.Policy, p.CatchUpMilestones, p.CatchUpInterval)
+}
+
+// WithRecovery defines how the coordinator catches up after a gap in the issued milestones.
+// With the gradual policy at most maxCatchUpMilestones milestones are issued in the catch-up interval.
+func WithRecovery(catchUpPolicy string, maxCatchUpMilestones int, catchUpInterval time.Duration) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.catchUpPolicy = catchUpPolicy
+		c.maxCatchUpMilestones = maxCatchUpMilestones
+		c.catchUpInterval = catchUpInterval
+	}
+}
+
+// NewRecoveryPlan computes the recovery plan for the gap between the latest milestone and now.
+// It returns nil if less than two milestone intervals passed since the latest milestone.
+func NewRecoveryPlan(latestMilestoneTime time.Time, now time.Time, interval time.Duration, catchUpPolicy string, maxCatchUpMilestones int, catchUpInterval time.Duration) (*RecoveryPlan, error) {
+
+	if latestMilestoneTime.IsZero() || interval <= 0 {
+		// no milestone was issued yet, there is nothing to catch up
+		return nil, nil
+	}
+
+	gap := now.Sub(latestMilestoneTime)
+
+	// a single elapsed interval is the regular case, e.g. after a quick restart
+	missedIntervals := int(gap / interval)
+	if missedIntervals < 2 {
+		return nil, nil
+	}
+
+	plan := &RecoveryPlan{
+		LatestMilestoneTime: latestMilestoneTime,
+		Gap:                 gap,
+		MissedIntervals:     missedIntervals,
+		Policy:              catchUpPolicy,
+	}
+
+	switch catchUpPolicy {
+	case CatchUpPolicyImmediate:
+		plan.CatchUpMilestones = 1
+
+	case CatchUpPolicyGradual:
+		plan.CatchUpMilestones = missedIntervals
+		if maxCatchUpMilestones > 0 && plan.CatchUpMilestones > maxCatchUpMilestones {
+			plan.CatchUpMilestones = maxCatchUpMilestones
+		}
+		plan.CatchUpInterval = catchUpInterval
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCatchUpPolicy, catchUpPolicy)
+	}
+
+	return plan, nil
+}
+
+// PlanRecovery checks whether milestone intervals were missed since the latest issued milestone
+// and returns the plan to catch up. The MilestoneGapDetected event is triggered if a gap was found.
+// It returns nil if there is no gap.
+func (coo *Coordinator) PlanRecovery(now time.Time) (*RecoveryPlan, error) {
+	if coo.state == nil {
+		return nil, errors.New("coordinator state not initialized")
+	}
+
+	plan, err := NewRecoveryPlan(coo.state.LatestMilestoneTime, now, coo.milestoneInterval, coo.catchUpPolicy, coo.maxCatchUpMilestones, coo.catchUpInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan != nil {
+		coo.Events.MilestoneGapDetected.Trigger(plan)
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,85 @@
+package coordinator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func TestNewRecoveryPlan(t *testing.T) {
+	now := time.Now()
+	interval := 5 * time.Second
+
+	tests := []struct {
+		name                 string
+		latestMilestoneTime  time.Time
+		policy               string
+		expectedPlan         bool
+		expectedMissed       int
+		expectedMilestones   int
+		expectedCatchUpDelay time.Duration
+	}{
+		{
+			name:                "not bootstrapped",
+			latestMilestoneTime: time.Time{},
+			policy:              coordinator.CatchUpPolicyImmediate,
+		},
+		{
+			name:                "quick restart",
+			latestMilestoneTime: now.Add(-7 * time.Second),
+			policy:              coordinator.CatchUpPolicyGradual,
+		},
+		{
+			name:                "immediate after a few intervals",
+			latestMilestoneTime: now.Add(-21 * time.Second),
+			policy:              coordinator.CatchUpPolicyImmediate,
+			expectedPlan:        true,
+			expectedMissed:      4,
+			expectedMilestones:  1,
+		},
+		{
+			name:                 "gradual after a few intervals",
+			latestMilestoneTime:  now.Add(-21 * time.Second),
+			policy:               coordinator.CatchUpPolicyGradual,
+			expectedPlan:         true,
+			expectedMissed:       4,
+			expectedMilestones:   4,
+			expectedCatchUpDelay: time.Second,
+		},
+		{
+			name:                 "gradual after hours of downtime",
+			latestMilestoneTime:  now.Add(-3 * time.Hour),
+			policy:               coordinator.CatchUpPolicyGradual,
+			expectedPlan:         true,
+			expectedMissed:       2160,
+			expectedMilestones:   10,
+			expectedCatchUpDelay: time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan, err := coordinator.NewRecoveryPlan(test.latestMilestoneTime, now, interval, test.policy, 10, time.Second)
+			require.NoError(t, err)
+
+			if !test.expectedPlan {
+				require.Nil(t, plan)
+
+				return
+			}
+
+			require.NotNil(t, plan)
+			require.Equal(t, test.policy, plan.Policy)
+			require.Equal(t, now.Sub(test.latestMilestoneTime), plan.Gap)
+			require.Equal(t, test.expectedMissed, plan.MissedIntervals)
+			require.Equal(t, test.expectedMilestones, plan.CatchUpMilestones)
+			require.Equal(t, test.expectedCatchUpDelay, plan.CatchUpInterval)
+		})
+	}
+
+	_, err := coordinator.NewRecoveryPlan(now.Add(-time.Hour), now, interval, "unknown", 10, time.Second)
+	require.ErrorIs(t, err, coordinator.ErrUnknownCatchUpPolicy)
+}
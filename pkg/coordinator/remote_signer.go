@@ -0,0 +1,324 @@
+package coordinator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/remotesigner"
+)
+
+var (
+	// ErrRemoteSignerCertificateInvalid is returned when the certificate presented by the remote signer is not trusted.
+	ErrRemoteSignerCertificateInvalid = errors.New("invalid remote signer certificate")
+	// ErrRemoteSignerSANMismatch is returned when the certificate presented by the remote signer does not contain any of the expected SANs.
+	ErrRemoteSignerSANMismatch = errors.New("remote signer certificate does not contain an expected SAN")
+)
+
+// RemoteSignerTLSConfig defines the files and the expected identity used for mutual TLS with the remote signers.
+type RemoteSignerTLSConfig struct {
+	// the path to the client certificate presented to the remote signer.
+	CertificatePath string
+	// the path to the private key of the client certificate.
+	PrivateKeyPath string
+	// the path to the CA certificates used to verify the remote signer certificate.
+	CACertificatePath string
+	// the subject alternative names of which at least one must be contained in the remote signer certificate.
+	// if empty, the remote signer certificate must be valid for the host name of the remote address.
+	ExpectedSANs []string
+	// the optional verifier for the attestation of the signer environment embedded in the remote signer certificate.
+	AttestationVerifier *AttestationVerifier
+}
+
+// certificateFile is a file that is reloaded if it was modified on disk.
+type certificateFile struct {
+	path    string
+	modTime time.Time
+}
+
+// modified returns whether the file was modified since it was loaded the last time.
+func (f *certificateFile) modified() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, err
+	}
+
+	return !info.ModTime().Equal(f.modTime), nil
+}
+
+// update remembers the current modification time of the file.
+func (f *certificateFile) update() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	f.modTime = info.ModTime()
+
+	return nil
+}
+
+// RemoteSignerCredentials holds the mutual TLS credentials used to connect to the remote signers.
+// The certificates are reloaded from disk on the next connection after they were rotated.
+type RemoteSignerCredentials struct {
+	mutex syncutils.Mutex
+
+	certificateFile   *certificateFile
+	privateKeyFile    *certificateFile
+	caCertificateFile *certificateFile
+	expectedSANs      []string
+	// the optional verifier for the attestations of the remote signers.
+	attestationVerifier *AttestationVerifier
+
+	// the currently loaded client certificate.
+	certificate *tls.Certificate
+	// the currently loaded CA certificates.
+	caPool *x509.CertPool
+	// the amount of times the certificates were loaded, used to detect rotations.
+	generation uint64
+}
+
+// NewRemoteSignerCredentials creates new RemoteSignerCredentials and loads the certificates from disk.
+func NewRemoteSignerCredentials(cfg *RemoteSignerTLSConfig) (*RemoteSignerCredentials, error) {
+
+	if cfg.CertificatePath == "" || cfg.PrivateKeyPath == "" || cfg.CACertificatePath == "" {
+		return nil, errors.New("certificate, private key and CA certificate paths are required for remote signer TLS")
+	}
+
+	c := &RemoteSignerCredentials{
+		certificateFile:   &certificateFile{path: cfg.CertificatePath},
+		privateKeyFile:    &certificateFile{path: cfg.PrivateKeyPath},
+		caCertificateFile: &certificateFile{path: cfg.CACertificatePath},
+		expectedSANs:      cfg.ExpectedSANs,
+
+		attestationVerifier: cfg.AttestationVerifier,
+	}
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// load reads the certificates from disk.
+func (c *RemoteSignerCredentials) load() error {
+
+	certificate, err := tls.LoadX509KeyPair(c.certificateFile.path, c.privateKeyFile.path)
+	if err != nil {
+		return fmt.Errorf("unable to load remote signer client certificate: %w", err)
+	}
+
+	caCertificates, err := os.ReadFile(c.caCertificateFile.path)
+	if err != nil {
+		return fmt.Errorf("unable to load remote signer CA certificate: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caCertificates) {
+		return fmt.Errorf("no valid certificates found in remote signer CA certificate file: %s", c.caCertificateFile.path)
+	}
+
+	for _, file := range []*certificateFile{c.certificateFile, c.privateKeyFile, c.caCertificateFile} {
+		if err := file.update(); err != nil {
+			return err
+		}
+	}
+
+	c.certificate = &certificate
+	c.caPool = caPool
+	c.generation++
+
+	return nil
+}
+
+// current returns the loaded certificates and reloads them first if any of the files was rotated.
+func (c *RemoteSignerCredentials) current() (*tls.Certificate, *x509.CertPool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, file := range []*certificateFile{c.certificateFile, c.privateKeyFile, c.caCertificateFile} {
+		modified, err := file.modified()
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to check remote signer certificate file: %w", err)
+		}
+
+		if modified {
+			if err := c.load(); err != nil {
+				return nil, nil, err
+			}
+
+			break
+		}
+	}
+
+	return c.certificate, c.caPool, nil
+}
+
+// currentGeneration returns the amount of times the certificates were loaded and reloads them first if any of the files was rotated.
+func (c *RemoteSignerCredentials) currentGeneration() (uint64, error) {
+	if _, _, err := c.current(); err != nil {
+		return 0, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.generation, nil
+}
+
+// verifyConnection verifies the certificate chain of the remote signer against the current CA certificates
+// and checks that the certificate contains one of the expected SANs and a valid attestation, if configured.
+func (c *RemoteSignerCredentials) verifyConnection(state tls.ConnectionState) error {
+
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("%w: no certificate presented", ErrRemoteSignerCertificateInvalid)
+	}
+
+	_, caPool, err := c.current()
+	if err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range state.PeerCertificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	leaf := state.PeerCertificates[0]
+
+	opts := x509.VerifyOptions{
+		Roots:         caPool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if len(c.expectedSANs) == 0 {
+		opts.DNSName = state.ServerName
+	}
+
+	if _, err := leaf.Verify(opts); err != nil {
+		return fmt.Errorf("%w: %s", ErrRemoteSignerCertificateInvalid, err)
+	}
+
+	if len(c.expectedSANs) > 0 && !certificateContainsAnySAN(leaf, c.expectedSANs) {
+		return fmt.Errorf("%w: expected one of %v", ErrRemoteSignerSANMismatch, c.expectedSANs)
+	}
+
+	if c.attestationVerifier != nil {
+		// signatures are only trusted if the signer environment satisfies the attestation policy
+		return c.attestationVerifier.Verify(leaf, time.Now())
+	}
+
+	return nil
+}
+
+// certificateContainsAnySAN checks whether the certificate contains at least one of the given subject alternative names.
+func certificateContainsAnySAN(certificate *x509.Certificate, sans []string) bool {
+	for _, san := range sans {
+		if certificateContainsSAN(certificate, san) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// certificateContainsSAN checks whether the certificate contains the given DNS name, IP address or URI as subject alternative name.
+func certificateContainsSAN(certificate *x509.Certificate, san string) bool {
+	for _, dnsName := range certificate.DNSNames {
+		if dnsName == san {
+			return true
+		}
+	}
+
+	if ip := net.ParseIP(san); ip != nil {
+		for _, ipAddress := range certificate.IPAddresses {
+			if ipAddress.Equal(ip) {
+				return true
+			}
+		}
+	}
+
+	for _, uri := range certificate.URIs {
+		if uri.String() == san {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TLSConfig returns the TLS configuration used to connect to the remote signers.
+func (c *RemoteSignerCredentials) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			certificate, _, err := c.current()
+
+			return certificate, err
+		},
+		// the default verification can't handle rotated CA certificates and expected SANs,
+		// the certificate chain is verified in VerifyConnection instead.
+		//nolint:gosec // the certificate is verified in VerifyConnection
+		InsecureSkipVerify: true,
+		VerifyConnection:   c.verifyConnection,
+	}
+}
+
+// RemoteEd25519MilestoneSigner is a function which uses a remote RPC server to produce signatures for the milestone essence data.
+// If no credentials are given, an insecure connection is used, which must only be done if the remote lives on the same host.
+func RemoteEd25519MilestoneSigner(remoteEndpoint string, remoteSignerCredentials *RemoteSignerCredentials) iotago.MilestoneSigningFunc {
+	if remoteSignerCredentials == nil {
+		return iotago.InsecureRemoteEd25519MilestoneSigner(remoteEndpoint)
+	}
+
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		// a new connection is established for every signing request, so rotated certificates are picked up
+		conn, err := grpc.Dial(remoteEndpoint, grpc.WithTransportCredentials(credentials.NewTLS(remoteSignerCredentials.TLSConfig())))
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+
+		return signMilestoneEssence(conn, pubKeys, msEssence)
+	}
+}
+
+// signMilestoneEssence asks the remote signer behind the given connection to sign the milestone essence with the given public keys.
+func signMilestoneEssence(conn grpc.ClientConnInterface, pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+	pubKeysUnbound := make([][]byte, len(pubKeys))
+	for i := range pubKeys {
+		pubKeysUnbound[i] = make([]byte, ed25519.PublicKeySize)
+		copy(pubKeysUnbound[i], pubKeys[i][:])
+	}
+
+	response, err := remotesigner.NewSignatureDispatcherClient(conn).SignMilestone(context.Background(), &remotesigner.SignMilestoneRequest{
+		PubKeys:   pubKeysUnbound,
+		MsEssence: msEssence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sigs := response.GetSignatures()
+	if len(sigs) != len(pubKeys) {
+		return nil, fmt.Errorf("%w: remote did not provide the correct count of signatures", iotago.ErrMilestoneProducedSignaturesCountMismatch)
+	}
+
+	sigs64 := make([]iotago.MilestoneSignature, len(sigs))
+	for i := range sigs {
+		copy(sigs64[i][:], sigs[i])
+	}
+
+	return sigs64, nil
+}
@@ -0,0 +1,271 @@
+package coordinator
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/crypto"
+	"github.com/iotaledger/hive.go/core/ioutils"
+)
+
+const (
+	// DefaultSignerAttestationExtensionOID is the OID of the certificate extension the remote signers embed the attestation in,
+	// if no other OID is configured in the attestation policy.
+	DefaultSignerAttestationExtensionOID = "1.3.6.1.4.1.59891.1.1"
+
+	// the maximum duration an attestation document may be issued in the future, to tolerate clock drifts.
+	maxAttestationClockSkew = time.Minute
+)
+
+var (
+	// ErrRemoteSignerAttestationMissing is returned when the certificate presented by the remote signer does not contain an attestation.
+	ErrRemoteSignerAttestationMissing = errors.New("remote signer certificate does not contain an attestation")
+	// ErrRemoteSignerAttestationInvalid is returned when the attestation of the remote signer does not satisfy the attestation policy.
+	ErrRemoteSignerAttestationInvalid = errors.New("invalid remote signer attestation")
+)
+
+// SignerAttestationDocument describes the environment a remote signer is running in, e.g. a TEE or Nitro enclave.
+type SignerAttestationDocument struct {
+	// the platform of the signer environment.
+	Platform string `json:"platform"`
+	// the unix timestamp at which the document was issued.
+	Timestamp int64 `json:"timestamp"`
+	// the measurements of the signer environment (hex encoded), e.g. the PCR values of an enclave.
+	Measurements map[string]string `json:"measurements"`
+	// the SHA-256 hash of the public key of the signer certificate (hex encoded), which binds the document to the TLS connection.
+	CertificatePublicKeyHash string `json:"certificatePublicKeyHash"`
+}
+
+// SignerAttestation is the signed attestation document embedded in the certificate of a remote signer.
+type SignerAttestation struct {
+	// the serialized SignerAttestationDocument.
+	Document []byte `json:"document"`
+	// the Ed25519 signature of the attestation authority over the serialized document.
+	Signature []byte `json:"signature"`
+}
+
+// AttestationPolicy defines which attestations of remote signers are trusted.
+type AttestationPolicy struct {
+	// the OID of the certificate extension that contains the attestation (optional).
+	ExtensionOID string `json:"extensionOID"`
+	// the public keys of the attestation authorities that are allowed to sign attestation documents (hex encoded).
+	TrustedKeys []string `json:"trustedKeys"`
+	// the allowed platforms of the signer environment (optional).
+	Platforms []string `json:"platforms"`
+	// the required measurements and their accepted values (hex encoded).
+	Measurements map[string][]string `json:"measurements"`
+	// the maximum age of an attestation document, e.g. "24h" (optional).
+	MaxAge string `json:"maxAge"`
+}
+
+// AttestationVerifier verifies the attestations of remote signers against an attestation policy.
+type AttestationVerifier struct {
+	extensionOID asn1.ObjectIdentifier
+	trustedKeys  []ed25519.PublicKey
+	platforms    map[string]struct{}
+	measurements map[string]map[string]struct{}
+	maxAge       time.Duration
+}
+
+// LoadAttestationPolicy loads an attestation policy from a JSON file.
+func LoadAttestationPolicy(filePath string) (*AttestationPolicy, error) {
+	policy := &AttestationPolicy{}
+	if err := ioutils.ReadJSONFromFile(filePath, policy); err != nil {
+		return nil, fmt.Errorf("unable to load attestation policy file: %w", err)
+	}
+
+	return policy, nil
+}
+
+// NewAttestationVerifier creates a new AttestationVerifier for the given policy.
+func NewAttestationVerifier(policy *AttestationPolicy) (*AttestationVerifier, error) {
+
+	extensionOID := policy.ExtensionOID
+	if extensionOID == "" {
+		extensionOID = DefaultSignerAttestationExtensionOID
+	}
+
+	oid, err := parseObjectIdentifier(extensionOID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policy.TrustedKeys) == 0 {
+		return nil, errors.New("no trusted keys given in attestation policy")
+	}
+
+	trustedKeys := make([]ed25519.PublicKey, 0, len(policy.TrustedKeys))
+	for _, trustedKey := range policy.TrustedKeys {
+		publicKey, err := crypto.ParseEd25519PublicKeyFromString(trustedKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted key %s in attestation policy: %w", trustedKey, err)
+		}
+		trustedKeys = append(trustedKeys, publicKey)
+	}
+
+	platforms := make(map[string]struct{}, len(policy.Platforms))
+	for _, platform := range policy.Platforms {
+		platforms[platform] = struct{}{}
+	}
+
+	measurements := make(map[string]map[string]struct{}, len(policy.Measurements))
+	for name, values := range policy.Measurements {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("no accepted values given for measurement %s in attestation policy", name)
+		}
+
+		accepted := make(map[string]struct{}, len(values))
+		for _, value := range values {
+			accepted[strings.ToLower(value)] = struct{}{}
+		}
+		measurements[name] = accepted
+	}
+
+	var maxAge time.Duration
+	if policy.MaxAge != "" {
+		if maxAge, err = time.ParseDuration(policy.MaxAge); err != nil {
+			return nil, fmt.Errorf("invalid max age in attestation policy: %w", err)
+		}
+	}
+
+	return &AttestationVerifier{
+		extensionOID: oid,
+		trustedKeys:  trustedKeys,
+		platforms:    platforms,
+		measurements: measurements,
+		maxAge:       maxAge,
+	}, nil
+}
+
+// Verify checks that the certificate contains an attestation that was signed by a trusted attestation authority,
+// is bound to the public key of the certificate and satisfies the policy.
+func (v *AttestationVerifier) Verify(certificate *x509.Certificate, now time.Time) error {
+
+	var attestationBytes []byte
+	for _, extension := range certificate.Extensions {
+		if extension.Id.Equal(v.extensionOID) {
+			attestationBytes = extension.Value
+
+			break
+		}
+	}
+	if attestationBytes == nil {
+		return ErrRemoteSignerAttestationMissing
+	}
+
+	attestation := &SignerAttestation{}
+	if err := json.Unmarshal(attestationBytes, attestation); err != nil {
+		return fmt.Errorf("%w: %s", ErrRemoteSignerAttestationInvalid, err)
+	}
+
+	if !v.signedByTrustedKey(attestation) {
+		return fmt.Errorf("%w: document not signed by a trusted key", ErrRemoteSignerAttestationInvalid)
+	}
+
+	document := &SignerAttestationDocument{}
+	if err := json.Unmarshal(attestation.Document, document); err != nil {
+		return fmt.Errorf("%w: %s", ErrRemoteSignerAttestationInvalid, err)
+	}
+
+	if !strings.EqualFold(document.CertificatePublicKeyHash, CertificatePublicKeyHash(certificate)) {
+		return fmt.Errorf("%w: document is not bound to the signer certificate", ErrRemoteSignerAttestationInvalid)
+	}
+
+	issued := time.Unix(document.Timestamp, 0)
+	if issued.After(now.Add(maxAttestationClockSkew)) {
+		return fmt.Errorf("%w: document issued in the future (%v)", ErrRemoteSignerAttestationInvalid, issued)
+	}
+	if v.maxAge > 0 && now.Sub(issued) > v.maxAge {
+		return fmt.Errorf("%w: document expired (issued %v)", ErrRemoteSignerAttestationInvalid, issued)
+	}
+
+	if len(v.platforms) > 0 {
+		if _, allowed := v.platforms[document.Platform]; !allowed {
+			return fmt.Errorf("%w: platform %s not allowed", ErrRemoteSignerAttestationInvalid, document.Platform)
+		}
+	}
+
+	for name, accepted := range v.measurements {
+		value, exists := document.Measurements[name]
+		if !exists {
+			return fmt.Errorf("%w: measurement %s missing", ErrRemoteSignerAttestationInvalid, name)
+		}
+
+		if _, ok := accepted[strings.ToLower(value)]; !ok {
+			return fmt.Errorf("%w: measurement %s has unexpected value %s", ErrRemoteSignerAttestationInvalid, name, value)
+		}
+	}
+
+	return nil
+}
+
+func (v *AttestationVerifier) signedByTrustedKey(attestation *SignerAttestation) bool {
+	for _, trustedKey := range v.trustedKeys {
+		if ed25519.Verify(trustedKey, attestation.Document, attestation.Signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewSignerAttestationExtension signs the attestation document with the key of the attestation authority
+// and returns the certificate extension that is embedded in the certificate of the remote signer.
+func NewSignerAttestationExtension(document *SignerAttestationDocument, attestationKey ed25519.PrivateKey) (pkix.Extension, error) {
+	documentBytes, err := json.Marshal(document)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	attestationBytes, err := json.Marshal(&SignerAttestation{
+		Document:  documentBytes,
+		Signature: ed25519.Sign(attestationKey, documentBytes),
+	})
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	oid, err := parseObjectIdentifier(DefaultSignerAttestationExtensionOID)
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+
+	// the extension must not be critical, otherwise the certificate verification fails for unknown extensions
+	return pkix.Extension{Id: oid, Critical: false, Value: attestationBytes}, nil
+}
+
+// CertificatePublicKeyHash returns the hash of the public key of a certificate that binds an attestation document to it.
+func CertificatePublicKeyHash(certificate *x509.Certificate) string {
+	publicKeyHash := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+
+	return hex.EncodeToString(publicKeyHash[:])
+}
+
+func parseObjectIdentifier(oid string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(oid, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid object identifier: %s", oid)
+	}
+
+	identifier := make(asn1.ObjectIdentifier, len(parts))
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid object identifier: %s", oid)
+		}
+		identifier[i] = value
+	}
+
+	return identifier, nil
+}
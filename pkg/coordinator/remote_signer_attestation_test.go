@@ -0,0 +1,178 @@
+package coordinator_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+// newAttestedTestCertificate creates a remote signer certificate that contains an attestation document
+// which is bound to the public key of the certificate, if bind is true.
+func newAttestedTestCertificate(t *testing.T, serial int64, parent *testCertificate, attestationKey ed25519.PrivateKey, document *coordinator.SignerAttestationDocument, bind bool) *testCertificate {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	if bind {
+		publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+
+		publicKeyHash := sha256.Sum256(publicKeyBytes)
+		document.CertificatePublicKeyHash = hex.EncodeToString(publicKeyHash[:])
+	}
+
+	extension, err := coordinator.NewSignerAttestationExtension(document, attestationKey)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(serial),
+		Subject:         pkix.Name{CommonName: "test"},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(time.Hour),
+		DNSNames:        []string{"signer.example"},
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtraExtensions: []pkix.Extension{extension},
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, parent.certificate, &privateKey.PublicKey, parent.privateKey)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(certificateBytes)
+	require.NoError(t, err)
+
+	return &testCertificate{certificate: certificate, privateKey: privateKey}
+}
+
+func testAttestationDocument() *coordinator.SignerAttestationDocument {
+	return &coordinator.SignerAttestationDocument{
+		Platform:  "nitro",
+		Timestamp: time.Now().Unix(),
+		Measurements: map[string]string{
+			"pcr0": "aabbcc",
+			"pcr8": "ddeeff",
+		},
+	}
+}
+
+func TestAttestationVerifier(t *testing.T) {
+	attestationPublicKey, attestationKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, untrustedKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	verifier, err := coordinator.NewAttestationVerifier(&coordinator.AttestationPolicy{
+		TrustedKeys:  []string{hex.EncodeToString(attestationPublicKey)},
+		Platforms:    []string{"nitro"},
+		Measurements: map[string][]string{"pcr0": {"AABBCC", "112233"}},
+		MaxAge:       "1h",
+	})
+	require.NoError(t, err)
+
+	ca := newTestCertificate(t, 1, nil, 0)
+	now := time.Now()
+
+	valid := newAttestedTestCertificate(t, 2, ca, attestationKey, testAttestationDocument(), true)
+	require.NoError(t, verifier.Verify(valid.certificate, now))
+
+	// certificates without attestation are rejected
+	plain := newTestCertificate(t, 3, ca, x509.ExtKeyUsageServerAuth, "signer.example")
+	require.ErrorIs(t, verifier.Verify(plain.certificate, now), coordinator.ErrRemoteSignerAttestationMissing)
+
+	// the document must be signed by a trusted key
+	untrusted := newAttestedTestCertificate(t, 4, ca, untrustedKey, testAttestationDocument(), true)
+	require.ErrorIs(t, verifier.Verify(untrusted.certificate, now), coordinator.ErrRemoteSignerAttestationInvalid)
+
+	// the document must be bound to the certificate, otherwise it could be copied from another signer
+	unbound := newAttestedTestCertificate(t, 5, ca, attestationKey, testAttestationDocument(), false)
+	require.ErrorIs(t, verifier.Verify(unbound.certificate, now), coordinator.ErrRemoteSignerAttestationInvalid)
+
+	// the document expires
+	require.ErrorIs(t, verifier.Verify(valid.certificate, now.Add(2*time.Hour)), coordinator.ErrRemoteSignerAttestationInvalid)
+
+	// the measurements must match the policy
+	document := testAttestationDocument()
+	document.Measurements["pcr0"] = "ffffff"
+	modified := newAttestedTestCertificate(t, 6, ca, attestationKey, document, true)
+	require.ErrorIs(t, verifier.Verify(modified.certificate, now), coordinator.ErrRemoteSignerAttestationInvalid)
+
+	// the platform must be allowed
+	document = testAttestationDocument()
+	document.Platform = "sgx"
+	otherPlatform := newAttestedTestCertificate(t, 7, ca, attestationKey, document, true)
+	require.ErrorIs(t, verifier.Verify(otherPlatform.certificate, now), coordinator.ErrRemoteSignerAttestationInvalid)
+}
+
+func TestRemoteSignerAttestation(t *testing.T) {
+	dir := t.TempDir()
+	certificatePath := filepath.Join(dir, "client.crt")
+	privateKeyPath := filepath.Join(dir, "client.key")
+	caCertificatePath := filepath.Join(dir, "ca.crt")
+
+	attestationPublicKey, attestationKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ca := newTestCertificate(t, 1, nil, 0)
+	client := newTestCertificate(t, 2, ca, x509.ExtKeyUsageClientAuth)
+
+	require.NoError(t, os.WriteFile(caCertificatePath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.certificate.Raw}), 0600))
+	client.writeFiles(t, certificatePath, privateKeyPath, time.Now().Add(-time.Minute))
+
+	verifier, err := coordinator.NewAttestationVerifier(&coordinator.AttestationPolicy{
+		TrustedKeys:  []string{hex.EncodeToString(attestationPublicKey)},
+		Measurements: map[string][]string{"pcr0": {"aabbcc"}},
+	})
+	require.NoError(t, err)
+
+	credentials, err := coordinator.NewRemoteSignerCredentials(&coordinator.RemoteSignerTLSConfig{
+		CertificatePath:     certificatePath,
+		PrivateKeyPath:      privateKeyPath,
+		CACertificatePath:   caCertificatePath,
+		ExpectedSANs:        []string{"signer.example"},
+		AttestationVerifier: verifier,
+	})
+	require.NoError(t, err)
+
+	attested := newAttestedTestCertificate(t, 3, ca, attestationKey, testAttestationDocument(), true)
+	_, err = handshake(t, credentials.TLSConfig(), ca, attested)
+	require.NoError(t, err)
+
+	// a signer without attestation is rejected during the handshake
+	server := newTestCertificate(t, 4, ca, x509.ExtKeyUsageServerAuth, "signer.example")
+	_, err = handshake(t, credentials.TLSConfig(), ca, server)
+	require.ErrorIs(t, err, coordinator.ErrRemoteSignerAttestationMissing)
+}
+
+func TestNewAttestationVerifierInvalidPolicy(t *testing.T) {
+	_, err := coordinator.NewAttestationVerifier(&coordinator.AttestationPolicy{})
+	require.Error(t, err)
+
+	_, err = coordinator.NewAttestationVerifier(&coordinator.AttestationPolicy{
+		TrustedKeys: []string{"invalid"},
+	})
+	require.Error(t, err)
+
+	attestationPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, err = coordinator.NewAttestationVerifier(&coordinator.AttestationPolicy{
+		ExtensionOID: "1.3.abc",
+		TrustedKeys:  []string{hex.EncodeToString(attestationPublicKey)},
+	})
+	require.Error(t, err)
+}
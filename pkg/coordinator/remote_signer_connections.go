@@ -0,0 +1,348 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	grpckeepalive "google.golang.org/grpc/keepalive"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// ErrRemoteSignerConnectionsClosed is returned when a remote signer is used after the connections were closed.
+var ErrRemoteSignerConnectionsClosed = errors.New("remote signer connections closed")
+
+// RemoteSignerKeepalive defines how the connections to the remote signers are kept warm.
+type RemoteSignerKeepalive struct {
+	// the interval in which the connections are probed and keepalive pings are sent.
+	// the remote signers need to permit keepalive pings in that interval, even without active requests.
+	Interval time.Duration
+	// the time after which a probe or a keepalive ping without response is considered failed.
+	Timeout time.Duration
+}
+
+// RemoteSignerConnectionState is the state of the connection to a remote signer.
+type RemoteSignerConnectionState struct {
+	// the address of the remote signer.
+	Endpoint string
+	// the connectivity state of the connection, e.g. READY or TRANSIENT_FAILURE.
+	State string
+	// the time since the connection is ready, zero if it is not ready.
+	ReadySince time.Time
+	// the time of the latest probe.
+	LastProbeTime time.Time
+	// the error of the latest probe, nil if it succeeded.
+	LastProbeError error
+	// the amount of times the connection was re-established after it was lost or the certificates were rotated.
+	Reconnects int
+}
+
+// remoteSignerConnection is a persistent connection to a remote signer.
+type remoteSignerConnection struct {
+	endpoint string
+	conn     *grpc.ClientConn
+	// the generation of the certificates the connection was established with.
+	credentialsGeneration uint64
+	// whether the connection was ready at least once.
+	wasReady       bool
+	readySince     time.Time
+	lastProbeTime  time.Time
+	lastProbeError error
+	reconnects     int
+}
+
+// RemoteSignerConnections establishes the connections to the remote signers.
+// Without keepalive, a new connection is established for every signing request.
+// With keepalive, a persistent connection to every remote signer is established up front and probed periodically,
+// so that the first milestone after an idle period doesn't pay for the connection setup.
+// The persistent connections are re-established after the certificates were rotated.
+type RemoteSignerConnections struct {
+	mutex syncutils.Mutex
+
+	// the optional mutual TLS credentials, insecure connections are used if nil.
+	credentials *RemoteSignerCredentials
+	// the optional keepalive configuration, a connection per signing request is used if nil.
+	keepalive *RemoteSignerKeepalive
+	// the persistent connections by the address of the remote signer.
+	connections map[string]*remoteSignerConnection
+	closed      bool
+}
+
+// NewRemoteSignerConnections creates a new RemoteSignerConnections.
+// If no credentials are given, insecure connections to the remote signers are used.
+// If no keepalive is given, a new connection is established for every signing request.
+func NewRemoteSignerConnections(remoteSignerCredentials *RemoteSignerCredentials, keepalive *RemoteSignerKeepalive) *RemoteSignerConnections {
+	return &RemoteSignerConnections{
+		credentials: remoteSignerCredentials,
+		keepalive:   keepalive,
+		connections: make(map[string]*remoteSignerConnection),
+	}
+}
+
+// KeepaliveEnabled returns whether persistent connections to the remote signers are kept warm.
+func (c *RemoteSignerConnections) KeepaliveEnabled() bool {
+	return c != nil && c.keepalive != nil
+}
+
+// dial establishes a new persistent connection to the remote signer. The lock must be held by the caller.
+func (c *RemoteSignerConnections) dial(endpoint string) (*grpc.ClientConn, uint64, error) {
+	transportCredentials := insecure.NewCredentials()
+
+	var generation uint64
+	if c.credentials != nil {
+		var err error
+		if generation, err = c.credentials.currentGeneration(); err != nil {
+			return nil, 0, err
+		}
+		transportCredentials = credentials.NewTLS(c.credentials.TLSConfig())
+	}
+
+	conn, err := grpc.Dial(endpoint,
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithKeepaliveParams(grpckeepalive.ClientParameters{
+			Time:                c.keepalive.Interval,
+			Timeout:             c.keepalive.Timeout,
+			PermitWithoutStream: true,
+		}),
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to connect to remote signer %s: %w", endpoint, err)
+	}
+
+	// don't wait for the first signing request to establish the connection
+	conn.Connect()
+
+	return conn, generation, nil
+}
+
+// connection returns the persistent connection to the remote signer and establishes it if needed.
+// The connection is re-established if the certificates were rotated since it was established.
+func (c *RemoteSignerConnections) connection(endpoint string) (*remoteSignerConnection, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return nil, ErrRemoteSignerConnectionsClosed
+	}
+
+	connection, exists := c.connections[endpoint]
+	if !exists {
+		conn, generation, err := c.dial(endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		connection = &remoteSignerConnection{
+			endpoint:              endpoint,
+			conn:                  conn,
+			credentialsGeneration: generation,
+		}
+		c.connections[endpoint] = connection
+
+		return connection, nil
+	}
+
+	if c.credentials == nil {
+		return connection, nil
+	}
+
+	generation, err := c.credentials.currentGeneration()
+	if err != nil {
+		return nil, err
+	}
+
+	if generation != connection.credentialsGeneration {
+		// the remote signer must see the rotated client certificate and the rotated CA certificates must be applied
+		conn, generation, err := c.dial(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		_ = connection.conn.Close()
+
+		connection.conn = conn
+		connection.credentialsGeneration = generation
+		connection.readySince = time.Time{}
+	}
+
+	return connection, nil
+}
+
+// Connect establishes the persistent connection to the remote signer up front, if keepalive is enabled.
+func (c *RemoteSignerConnections) Connect(endpoint string) error {
+	if !c.KeepaliveEnabled() {
+		return nil
+	}
+
+	_, err := c.connection(endpoint)
+
+	return err
+}
+
+// SigningFunc returns a function which uses the remote signer to produce signatures for the milestone essence data.
+func (c *RemoteSignerConnections) SigningFunc(endpoint string) iotago.MilestoneSigningFunc {
+	if c == nil {
+		return RemoteEd25519MilestoneSigner(endpoint, nil)
+	}
+
+	if c.keepalive == nil {
+		return RemoteEd25519MilestoneSigner(endpoint, c.credentials)
+	}
+
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		connection, err := c.connection(endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		return signMilestoneEssence(connection.conn, pubKeys, msEssence)
+	}
+}
+
+// waitForReady waits until the connection is ready or the context is done.
+func waitForReady(ctx context.Context, conn *grpc.ClientConn) error {
+	// reconnect right away if the connection is idle
+	conn.Connect()
+
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection is %s: %w", state, ctx.Err())
+		}
+	}
+}
+
+// Probe checks that the persistent connections to all remote signers are ready and re-establishes lost connections.
+// It returns the first error of a failed probe.
+func (c *RemoteSignerConnections) Probe(ctx context.Context) error {
+	if !c.KeepaliveEnabled() {
+		return nil
+	}
+
+	c.mutex.Lock()
+	endpoints := make([]string, 0, len(c.connections))
+	for endpoint := range c.connections {
+		endpoints = append(endpoints, endpoint)
+	}
+	c.mutex.Unlock()
+	sort.Strings(endpoints)
+
+	var probeErr error
+	for _, endpoint := range endpoints {
+		if err := c.probe(ctx, endpoint); err != nil && probeErr == nil {
+			probeErr = err
+		}
+	}
+
+	return probeErr
+}
+
+// probe checks that the persistent connection to the remote signer is ready.
+func (c *RemoteSignerConnections) probe(ctx context.Context, endpoint string) error {
+	connection, err := c.connection(endpoint)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	conn := connection.conn
+	c.mutex.Unlock()
+
+	probeCtx, probeCancel := context.WithTimeout(ctx, c.keepalive.Timeout)
+	defer probeCancel()
+
+	probeErr := waitForReady(probeCtx, conn)
+	if probeErr != nil {
+		probeErr = fmt.Errorf("remote signer %s is not reachable: %w", endpoint, probeErr)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if connection.conn != conn {
+		// the connection was re-established in the meantime
+		return probeErr
+	}
+
+	now := time.Now()
+	connection.lastProbeTime = now
+	connection.lastProbeError = probeErr
+
+	if probeErr != nil {
+		connection.readySince = time.Time{}
+
+		return probeErr
+	}
+
+	if connection.readySince.IsZero() {
+		connection.readySince = now
+		if connection.wasReady {
+			connection.reconnects++
+		}
+		connection.wasReady = true
+	}
+
+	return nil
+}
+
+// States returns the state of the persistent connections ordered by the address of the remote signer.
+// No states are returned if keepalive is disabled.
+func (c *RemoteSignerConnections) States() []*RemoteSignerConnectionState {
+	if !c.KeepaliveEnabled() {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	states := make([]*RemoteSignerConnectionState, 0, len(c.connections))
+	for _, connection := range c.connections {
+		states = append(states, &RemoteSignerConnectionState{
+			Endpoint:       connection.endpoint,
+			State:          connection.conn.GetState().String(),
+			ReadySince:     connection.readySince,
+			LastProbeTime:  connection.lastProbeTime,
+			LastProbeError: connection.lastProbeError,
+			Reconnects:     connection.reconnects,
+		})
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Endpoint < states[j].Endpoint
+	})
+
+	return states
+}
+
+// Close closes all persistent connections, signing requests fail afterwards.
+func (c *RemoteSignerConnections) Close() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.closed = true
+
+	var closeErr error
+	for endpoint, connection := range c.connections {
+		if err := connection.conn.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+		delete(c.connections, endpoint)
+	}
+
+	return closeErr
+}
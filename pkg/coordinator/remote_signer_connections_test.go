@@ -0,0 +1,136 @@
+package coordinator_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/remotesigner"
+)
+
+// testRemoteSigner signs milestone essences with a single key and counts the signing requests.
+type testRemoteSigner struct {
+	remotesigner.UnimplementedSignatureDispatcherServer
+
+	privateKey ed25519.PrivateKey
+	requests   atomic.Int32
+}
+
+func (s *testRemoteSigner) SignMilestone(_ context.Context, req *remotesigner.SignMilestoneRequest) (*remotesigner.SignMilestoneResponse, error) {
+	s.requests.Add(1)
+
+	signatures := make([][]byte, len(req.GetPubKeys()))
+	for i := range signatures {
+		signatures[i] = ed25519.Sign(s.privateKey, req.GetMsEssence())
+	}
+
+	return &remotesigner.SignMilestoneResponse{Signatures: signatures}, nil
+}
+
+// startTestRemoteSigner starts a remote signer on a local port and returns its address.
+func startTestRemoteSigner(t *testing.T, signer *testRemoteSigner) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	remotesigner.RegisterSignatureDispatcherServer(server, signer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestRemoteSignerConnectionsKeepalive(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer := &testRemoteSigner{privateKey: privateKey}
+	endpoint := startTestRemoteSigner(t, signer)
+
+	remoteSigners := coordinator.NewRemoteSignerConnections(nil, &coordinator.RemoteSignerKeepalive{
+		Interval: time.Minute,
+		Timeout:  5 * time.Second,
+	})
+	defer remoteSigners.Close()
+	require.True(t, remoteSigners.KeepaliveEnabled())
+
+	// the connection is established before the first signing request
+	require.NoError(t, remoteSigners.Connect(endpoint))
+	require.NoError(t, remoteSigners.Probe(context.Background()))
+
+	states := remoteSigners.States()
+	require.Len(t, states, 1)
+	require.Equal(t, endpoint, states[0].Endpoint)
+	require.Equal(t, "READY", states[0].State)
+	require.False(t, states[0].ReadySince.IsZero())
+	require.False(t, states[0].LastProbeTime.IsZero())
+	require.NoError(t, states[0].LastProbeError)
+	require.Zero(t, signer.requests.Load())
+
+	var pubKey iotago.MilestonePublicKey
+	copy(pubKey[:], publicKey)
+
+	essence := []byte("essence")
+	signingFunc := remoteSigners.SigningFunc(endpoint)
+	for i := 0; i < 2; i++ {
+		sigs, err := signingFunc([]iotago.MilestonePublicKey{pubKey}, essence)
+		require.NoError(t, err)
+		require.Len(t, sigs, 1)
+		require.True(t, ed25519.Verify(publicKey, essence, sigs[0][:]))
+	}
+	require.EqualValues(t, 2, signer.requests.Load())
+
+	// the same connection is used for all signing requests
+	require.Len(t, remoteSigners.States(), 1)
+	require.Zero(t, remoteSigners.States()[0].Reconnects)
+
+	require.NoError(t, remoteSigners.Close())
+	_, err = signingFunc([]iotago.MilestonePublicKey{pubKey}, essence)
+	require.ErrorIs(t, err, coordinator.ErrRemoteSignerConnectionsClosed)
+}
+
+func TestRemoteSignerConnectionsProbeFailure(t *testing.T) {
+	// reserve a port that nobody listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	endpoint := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	remoteSigners := coordinator.NewRemoteSignerConnections(nil, &coordinator.RemoteSignerKeepalive{
+		Interval: time.Minute,
+		Timeout:  200 * time.Millisecond,
+	})
+	defer remoteSigners.Close()
+
+	require.NoError(t, remoteSigners.Connect(endpoint))
+	require.Error(t, remoteSigners.Probe(context.Background()))
+
+	states := remoteSigners.States()
+	require.Len(t, states, 1)
+	require.NotEqual(t, "READY", states[0].State)
+	require.True(t, states[0].ReadySince.IsZero())
+	require.Error(t, states[0].LastProbeError)
+}
+
+func TestRemoteSignerConnectionsWithoutKeepalive(t *testing.T) {
+	remoteSigners := coordinator.NewRemoteSignerConnections(nil, nil)
+	require.False(t, remoteSigners.KeepaliveEnabled())
+
+	// no persistent connections are established without keepalive
+	require.NoError(t, remoteSigners.Connect("127.0.0.1:1"))
+	require.NoError(t, remoteSigners.Probe(context.Background()))
+	require.Nil(t, remoteSigners.States())
+}
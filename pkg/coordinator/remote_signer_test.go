@@ -0,0 +1,172 @@
+package coordinator_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	privateKey  *ecdsa.PrivateKey
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{c.certificate.Raw},
+		PrivateKey:  c.privateKey,
+	}
+}
+
+func (c *testCertificate) writeFiles(t *testing.T, certificatePath string, privateKeyPath string, modTime time.Time) {
+	privateKeyBytes, err := x509.MarshalECPrivateKey(c.privateKey)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certificatePath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.certificate.Raw}), 0600))
+	require.NoError(t, os.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateKeyBytes}), 0600))
+
+	// the modification time is set explicitly, because the file system resolution could be too low to detect the rotation
+	require.NoError(t, os.Chtimes(certificatePath, modTime, modTime))
+	require.NoError(t, os.Chtimes(privateKeyPath, modTime, modTime))
+}
+
+func newTestCertificate(t *testing.T, serial int64, parent *testCertificate, extKeyUsage x509.ExtKeyUsage, dnsNames ...string) *testCertificate {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     dnsNames,
+	}
+
+	signerCertificate := template
+	signerKey := privateKey
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{extKeyUsage}
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		signerCertificate = parent.certificate
+		signerKey = parent.privateKey
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, signerCertificate, &privateKey.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(certificateBytes)
+	require.NoError(t, err)
+
+	return &testCertificate{certificate: certificate, privateKey: privateKey}
+}
+
+// handshake performs a TLS handshake between the client and a remote signer and returns the client certificate seen by the signer.
+func handshake(t *testing.T, clientConfig *tls.Config, ca *testCertificate, server *testCertificate) (*x509.Certificate, error) {
+	clientPool := x509.NewCertPool()
+	clientPool.AddCert(ca.certificate)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	tlsServer := tls.Server(serverConn, &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{server.tlsCertificate()},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientPool,
+	})
+
+	serverDone := make(chan *x509.Certificate, 1)
+	go func() {
+		defer serverConn.Close()
+
+		if err := tlsServer.Handshake(); err != nil {
+			serverDone <- nil
+
+			return
+		}
+		serverDone <- tlsServer.ConnectionState().PeerCertificates[0]
+	}()
+
+	clientConfig.ServerName = "signer.example"
+	tlsClient := tls.Client(clientConn, clientConfig)
+	err := tlsClient.Handshake()
+	if err != nil {
+		clientConn.Close()
+		<-serverDone
+
+		return nil, err
+	}
+
+	return <-serverDone, nil
+}
+
+func TestRemoteSignerCredentials(t *testing.T) {
+	dir := t.TempDir()
+	certificatePath := filepath.Join(dir, "client.crt")
+	privateKeyPath := filepath.Join(dir, "client.key")
+	caCertificatePath := filepath.Join(dir, "ca.crt")
+
+	ca := newTestCertificate(t, 1, nil, 0)
+	server := newTestCertificate(t, 2, ca, x509.ExtKeyUsageServerAuth, "signer.example")
+	client := newTestCertificate(t, 3, ca, x509.ExtKeyUsageClientAuth)
+
+	require.NoError(t, os.WriteFile(caCertificatePath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.certificate.Raw}), 0600))
+	client.writeFiles(t, certificatePath, privateKeyPath, time.Now().Add(-time.Minute))
+
+	credentials, err := coordinator.NewRemoteSignerCredentials(&coordinator.RemoteSignerTLSConfig{
+		CertificatePath:   certificatePath,
+		PrivateKeyPath:    privateKeyPath,
+		CACertificatePath: caCertificatePath,
+		ExpectedSANs:      []string{"signer.example"},
+	})
+	require.NoError(t, err)
+
+	presented, err := handshake(t, credentials.TLSConfig(), ca, server)
+	require.NoError(t, err)
+	require.Equal(t, client.certificate.SerialNumber, presented.SerialNumber)
+
+	// the rotated client certificate is used for the next connection
+	rotated := newTestCertificate(t, 4, ca, x509.ExtKeyUsageClientAuth)
+	rotated.writeFiles(t, certificatePath, privateKeyPath, time.Now())
+
+	presented, err = handshake(t, credentials.TLSConfig(), ca, server)
+	require.NoError(t, err)
+	require.Equal(t, rotated.certificate.SerialNumber, presented.SerialNumber)
+
+	// a signer certificate without the expected SAN is rejected
+	mismatch, err := coordinator.NewRemoteSignerCredentials(&coordinator.RemoteSignerTLSConfig{
+		CertificatePath:   certificatePath,
+		PrivateKeyPath:    privateKeyPath,
+		CACertificatePath: caCertificatePath,
+		ExpectedSANs:      []string{"other-signer.example"},
+	})
+	require.NoError(t, err)
+
+	_, err = handshake(t, mismatch.TLSConfig(), ca, server)
+	require.ErrorIs(t, err, coordinator.ErrRemoteSignerSANMismatch)
+
+	// a signer certificate issued by an unknown CA is rejected
+	unknownCA := newTestCertificate(t, 5, nil, 0)
+	unknownServer := newTestCertificate(t, 6, unknownCA, x509.ExtKeyUsageServerAuth, "signer.example")
+
+	_, err = handshake(t, credentials.TLSConfig(), ca, unknownServer)
+	require.ErrorIs(t, err, coordinator.ErrRemoteSignerCertificateInvalid)
+}
@@ -0,0 +1,426 @@
+package coordinator
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/crypto"
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+var (
+	// ErrSignerCommitteeChangeNotFound is returned when a prepared signer committee change does not exist.
+	ErrSignerCommitteeChangeNotFound = errors.New("signer committee change not found")
+	// ErrSignerCommitteeChangeInvalid is returned when a signer committee change is invalid.
+	ErrSignerCommitteeChangeInvalid = errors.New("invalid signer committee change")
+	// ErrSignerCommitteeMemberMissing is returned when there are not enough active signer committee members to sign a milestone.
+	ErrSignerCommitteeMemberMissing = errors.New("not enough active signer committee members")
+)
+
+// SignerCommitteeMember is a remote signer that is member of the signer committee.
+type SignerCommitteeMember struct {
+	// the address of the remote signer.
+	RemoteAddress string `json:"remoteAddress" koanf:"remoteAddress" usage:"the address of the remote signer (insecure connection, unless remote signer TLS is enabled!)"`
+	// the public key of the remote signer (hex encoded).
+	PublicKey string `json:"publicKey" koanf:"publicKey" usage:"the public key of the remote signer (hex encoded)"`
+	// the milestone index from which on the member signs milestones.
+	StartIndex iotago.MilestoneIndex `json:"startIndex" koanf:"startIndex" usage:"the milestone index from which on the member signs milestones"`
+	// the milestone index from which on the member no longer signs milestones (0 = no end).
+	EndIndex iotago.MilestoneIndex `json:"endIndex" koanf:"endIndex" usage:"the milestone index from which on the member no longer signs milestones (0 = no end)"`
+}
+
+// activeAt returns whether the member signs the milestone with the given index.
+func (m *SignerCommitteeMember) activeAt(index iotago.MilestoneIndex) bool {
+	return index >= m.StartIndex && (m.EndIndex == 0 || index < m.EndIndex)
+}
+
+// SignerCommitteeChange is a change of the signer committee membership that activates at a certain milestone index.
+type SignerCommitteeChange struct {
+	// the ID of the change.
+	ID string
+	// the milestone index at which the change activates.
+	ActivationIndex iotago.MilestoneIndex
+	// the members that are added to the committee.
+	Add []*SignerCommitteeMember
+	// the public keys (hex encoded) of the members that are removed from the committee.
+	Remove []string
+}
+
+// SignerCommittee provides milestone signers for a committee of remote signers.
+// The membership of the committee can be changed at runtime with a two-phase commit,
+// so that a change activates atomically at a specific milestone index.
+type SignerCommittee struct {
+	mutex syncutils.RWMutex
+
+	// the path to the file the committee is persisted to.
+	filePath string
+	// the connections to the remote signers.
+	remoteSigners *RemoteSignerConnections
+	// the key manager holding the key ranges accepted by the network.
+	keyManager *keymanager.KeyManager
+	// the amount of public keys in a milestone.
+	publicKeysCount int
+	// the members of the committee.
+	members []*SignerCommitteeMember
+	// the prepared, but not yet committed changes.
+	preparedChanges map[string]*SignerCommitteeChange
+}
+
+// NewSignerCommittee creates a new SignerCommittee.
+// If the committee file exists, the members are loaded from the file, otherwise the given members are used.
+// If no remote signer connections are given, a new insecure connection to a remote signer is used for every signing request.
+func NewSignerCommittee(filePath string, members []*SignerCommitteeMember, remoteSigners *RemoteSignerConnections, keyManager *keymanager.KeyManager, publicKeysCount int) (*SignerCommittee, error) {
+
+	if filePath != "" {
+		if _, err := os.Stat(filePath); err == nil {
+			members = []*SignerCommitteeMember{}
+			if err := ioutils.ReadJSONFromFile(filePath, &members); err != nil {
+				return nil, fmt.Errorf("unable to load signer committee file: %w", err)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("unable to check signer committee file: %w", err)
+		}
+	}
+
+	if len(members) == 0 {
+		return nil, errors.New("no signer committee members given")
+	}
+
+	for _, member := range members {
+		if err := validateSignerCommitteeMember(member); err != nil {
+			return nil, err
+		}
+	}
+
+	// the connections to the members are established up front, if they are kept warm
+	for _, member := range members {
+		if err := remoteSigners.Connect(member.RemoteAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SignerCommittee{
+		filePath:        filePath,
+		remoteSigners:   remoteSigners,
+		keyManager:      keyManager,
+		publicKeysCount: publicKeysCount,
+		members:         members,
+		preparedChanges: make(map[string]*SignerCommitteeChange),
+	}, nil
+}
+
+func validateSignerCommitteeMember(member *SignerCommitteeMember) error {
+	if member.RemoteAddress == "" {
+		return fmt.Errorf("%w: no remote address given for public key %s", ErrSignerCommitteeChangeInvalid, member.PublicKey)
+	}
+
+	if _, err := crypto.ParseEd25519PublicKeyFromString(member.PublicKey); err != nil {
+		return fmt.Errorf("%w: invalid public key %s: %s", ErrSignerCommitteeChangeInvalid, member.PublicKey, err)
+	}
+
+	if member.EndIndex != 0 && member.EndIndex <= member.StartIndex {
+		return fmt.Errorf("%w: end index must be greater than start index for public key %s", ErrSignerCommitteeChangeInvalid, member.PublicKey)
+	}
+
+	return nil
+}
+
+func parseMilestonePublicKey(publicKeyHex string) iotago.MilestonePublicKey {
+	var pubKey iotago.MilestonePublicKey
+	//nolint:errcheck // the public keys are validated when they are added to the committee
+	publicKey, _ := crypto.ParseEd25519PublicKeyFromString(publicKeyHex)
+	copy(pubKey[:], publicKey)
+
+	return pubKey
+}
+
+// activeMembers returns the members that sign the milestone with the given index
+// and whose public keys are accepted by the network at that index.
+// The read lock must be held by the caller.
+func (c *SignerCommittee) activeMembers(index iotago.MilestoneIndex) map[iotago.MilestonePublicKey]*SignerCommitteeMember {
+
+	validKeys := c.keyManager.PublicKeysSetForMilestoneIndex(index)
+
+	active := make(map[iotago.MilestonePublicKey]*SignerCommitteeMember)
+	for _, member := range c.members {
+		if !member.activeAt(index) {
+			continue
+		}
+
+		pubKey := parseMilestonePublicKey(member.PublicKey)
+		if _, valid := validKeys[pubKey]; !valid {
+			continue
+		}
+
+		active[pubKey] = member
+	}
+
+	return active
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+// The response of every member is verified against its public key, which needs to be valid for the milestone index.
+func (c *SignerCommittee) MilestoneIndexSigner(index iotago.MilestoneIndex) MilestoneIndexSigner {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	active := c.activeMembers(index)
+
+	// the public keys are sorted, so that the same members are selected for every milestone while the committee doesn't change
+	activeKeys := make([]iotago.MilestonePublicKey, 0, len(active))
+	for pubKey := range active {
+		activeKeys = append(activeKeys, pubKey)
+	}
+	sort.Slice(activeKeys, func(i, j int) bool {
+		return bytes.Compare(activeKeys[i][:], activeKeys[j][:]) < 0
+	})
+	if len(activeKeys) > c.publicKeysCount {
+		activeKeys = activeKeys[:c.publicKeysCount]
+	}
+
+	pubKeys := make([]iotago.MilestonePublicKey, 0, len(activeKeys))
+	signingFuncs := make(map[iotago.MilestonePublicKey]iotago.MilestoneSigningFunc, len(activeKeys))
+	for _, pubKey := range activeKeys {
+		pubKeys = append(pubKeys, pubKey)
+		signingFuncs[pubKey] = KeyRangeVerifyingSigningFunc(index, c.keyManager, c.remoteSigners.SigningFunc(active[pubKey].RemoteAddress))
+	}
+
+	return &RemoteEd25519MilestoneIndexSigner{
+		pubKeys:   pubKeys,
+		pubKeySet: c.keyManager.PublicKeysSetForMilestoneIndex(index),
+		signingFunc: func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+			if len(pubKeys) < c.publicKeysCount {
+				return nil, fmt.Errorf("%w: milestone %d, active: %d, needed: %d", ErrSignerCommitteeMemberMissing, index, len(pubKeys), c.publicKeysCount)
+			}
+
+			// every member is asked for its own signature
+			sigs := make([]iotago.MilestoneSignature, len(pubKeys))
+			for i, pubKey := range pubKeys {
+				signingFunc, exists := signingFuncs[pubKey]
+				if !exists {
+					return nil, fmt.Errorf("%w: public key %s is not active at milestone %d", ErrSignerCommitteeMemberMissing, iotago.EncodeHex(pubKey[:]), index)
+				}
+
+				memberSigs, err := signingFunc([]iotago.MilestonePublicKey{pubKey}, msEssence)
+				if err != nil {
+					return nil, fmt.Errorf("signer committee member %s failed to sign: %w", iotago.EncodeHex(pubKey[:]), err)
+				}
+				sigs[i] = memberSigs[0]
+			}
+
+			return sigs, nil
+		},
+	}
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (c *SignerCommittee) PublicKeysCount() int {
+	return c.publicKeysCount
+}
+
+// Members returns a copy of the members of the committee.
+func (c *SignerCommittee) Members() []*SignerCommitteeMember {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	members := make([]*SignerCommitteeMember, len(c.members))
+	for i, member := range c.members {
+		memberCopy := *member
+		members[i] = &memberCopy
+	}
+
+	return members
+}
+
+// PreparedChanges returns the prepared, but not yet committed changes.
+func (c *SignerCommittee) PreparedChanges() []*SignerCommitteeChange {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	changes := make([]*SignerCommitteeChange, 0, len(c.preparedChanges))
+	for _, change := range c.preparedChanges {
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// applyChange returns the members of the committee after the change was applied.
+// The read lock must be held by the caller.
+func (c *SignerCommittee) applyChange(change *SignerCommitteeChange) ([]*SignerCommitteeMember, error) {
+
+	members := make([]*SignerCommitteeMember, 0, len(c.members)+len(change.Add))
+	for _, member := range c.members {
+		memberCopy := *member
+		members = append(members, &memberCopy)
+	}
+
+	for _, removeKey := range change.Remove {
+		removeKey := parseMilestonePublicKey(removeKey)
+
+		found := false
+		for _, member := range members {
+			if parseMilestonePublicKey(member.PublicKey) != removeKey || !member.activeAt(change.ActivationIndex) {
+				continue
+			}
+			member.EndIndex = change.ActivationIndex
+			found = true
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: public key %s is not an active member at milestone %d", ErrSignerCommitteeChangeInvalid, iotago.EncodeHex(removeKey[:]), change.ActivationIndex)
+		}
+	}
+
+	for _, addMember := range change.Add {
+		addKey := parseMilestonePublicKey(addMember.PublicKey)
+
+		for _, member := range members {
+			if parseMilestonePublicKey(member.PublicKey) == addKey && member.activeAt(change.ActivationIndex) {
+				return nil, fmt.Errorf("%w: public key %s is already an active member at milestone %d", ErrSignerCommitteeChangeInvalid, addMember.PublicKey, change.ActivationIndex)
+			}
+		}
+
+		if _, valid := c.keyManager.PublicKeysSetForMilestoneIndex(change.ActivationIndex)[addKey]; !valid {
+			return nil, fmt.Errorf("%w: public key %s is not accepted by the network at milestone %d", ErrSignerCommitteeChangeInvalid, addMember.PublicKey, change.ActivationIndex)
+		}
+
+		members = append(members, &SignerCommitteeMember{
+			RemoteAddress: addMember.RemoteAddress,
+			PublicKey:     addMember.PublicKey,
+			StartIndex:    change.ActivationIndex,
+			EndIndex:      0,
+		})
+	}
+
+	return members, nil
+}
+
+// validateChange checks whether the change can be applied to the committee.
+// The read lock must be held by the caller.
+func (c *SignerCommittee) validateChange(change *SignerCommitteeChange, latestMilestoneIndex iotago.MilestoneIndex) ([]*SignerCommitteeMember, error) {
+
+	// the next milestone could already be in the making, so the change must activate after that one
+	if change.ActivationIndex <= latestMilestoneIndex+1 {
+		return nil, fmt.Errorf("%w: activation index %d must be greater than %d", ErrSignerCommitteeChangeInvalid, change.ActivationIndex, latestMilestoneIndex+1)
+	}
+
+	if len(change.Add) == 0 && len(change.Remove) == 0 {
+		return nil, fmt.Errorf("%w: no members added or removed", ErrSignerCommitteeChangeInvalid)
+	}
+
+	for _, member := range change.Add {
+		if err := validateSignerCommitteeMember(member); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, removeKey := range change.Remove {
+		if _, err := crypto.ParseEd25519PublicKeyFromString(removeKey); err != nil {
+			return nil, fmt.Errorf("%w: invalid public key %s: %s", ErrSignerCommitteeChangeInvalid, removeKey, err)
+		}
+	}
+
+	members, err := c.applyChange(change)
+	if err != nil {
+		return nil, err
+	}
+
+	// check that the committee is still able to sign milestones after the change
+	activeCount := 0
+	validKeys := c.keyManager.PublicKeysSetForMilestoneIndex(change.ActivationIndex)
+	for _, member := range members {
+		if _, valid := validKeys[parseMilestonePublicKey(member.PublicKey)]; valid && member.activeAt(change.ActivationIndex) {
+			activeCount++
+		}
+	}
+
+	if activeCount < c.publicKeysCount {
+		return nil, fmt.Errorf("%w: only %d active members at milestone %d, needed: %d", ErrSignerCommitteeChangeInvalid, activeCount, change.ActivationIndex, c.publicKeysCount)
+	}
+
+	return members, nil
+}
+
+// PrepareChange validates the change and stores it until it is committed or aborted (first phase).
+func (c *SignerCommittee) PrepareChange(activationIndex iotago.MilestoneIndex, add []*SignerCommitteeMember, remove []string, latestMilestoneIndex iotago.MilestoneIndex) (*SignerCommitteeChange, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	changeIDBytes := make([]byte, 8)
+	if _, err := rand.Read(changeIDBytes); err != nil {
+		return nil, err
+	}
+
+	change := &SignerCommitteeChange{
+		ID:              iotago.EncodeHex(changeIDBytes),
+		ActivationIndex: activationIndex,
+		Add:             add,
+		Remove:          remove,
+	}
+
+	if _, err := c.validateChange(change, latestMilestoneIndex); err != nil {
+		return nil, err
+	}
+
+	c.preparedChanges[change.ID] = change
+
+	return change, nil
+}
+
+// CommitChange applies a prepared change to the committee and persists the committee (second phase).
+// The change activates at its activation index.
+func (c *SignerCommittee) CommitChange(changeID string, latestMilestoneIndex iotago.MilestoneIndex) (*SignerCommitteeChange, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	change, exists := c.preparedChanges[changeID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrSignerCommitteeChangeNotFound, changeID)
+	}
+
+	// the committee or the latest milestone could have changed since the change was prepared
+	members, err := c.validateChange(change, latestMilestoneIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.filePath != "" {
+		if err := ioutils.WriteJSONToFile(c.filePath, members, 0660); err != nil {
+			return nil, fmt.Errorf("unable to persist signer committee file: %w", err)
+		}
+	}
+
+	c.members = members
+	delete(c.preparedChanges, changeID)
+
+	// the added members must not pay for the connection setup when the change activates,
+	// the change is already committed, so a failed connection is established again on the first signing request.
+	for _, member := range change.Add {
+		_ = c.remoteSigners.Connect(member.RemoteAddress)
+	}
+
+	return change, nil
+}
+
+// AbortChange removes a prepared change.
+func (c *SignerCommittee) AbortChange(changeID string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.preparedChanges[changeID]; !exists {
+		return fmt.Errorf("%w: %s", ErrSignerCommitteeChangeNotFound, changeID)
+	}
+
+	delete(c.preparedChanges, changeID)
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package coordinator_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+// newTestCommitteeMembers returns count members whose public keys are accepted by the network from milestone 0 on.
+func newTestCommitteeMembers(t *testing.T, keyManager *keymanager.KeyManager, count int) []*coordinator.SignerCommitteeMember {
+	t.Helper()
+
+	members := make([]*coordinator.SignerCommitteeMember, count)
+	for i := range members {
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keyManager.AddKeyRange(publicKey, 0, 0)
+
+		members[i] = &coordinator.SignerCommitteeMember{
+			RemoteAddress: fmt.Sprintf("signer-%d:12345", i),
+			PublicKey:     hex.EncodeToString(publicKey),
+		}
+	}
+
+	return members
+}
+
+func committeeMemberKey(t *testing.T, member *coordinator.SignerCommitteeMember) iotago.MilestonePublicKey {
+	t.Helper()
+
+	publicKey, err := hex.DecodeString(member.PublicKey)
+	require.NoError(t, err)
+
+	var pubKey iotago.MilestonePublicKey
+	copy(pubKey[:], publicKey)
+
+	return pubKey
+}
+
+func TestSignerCommitteeDeterministicSelection(t *testing.T) {
+	keyManager := keymanager.New()
+	members := newTestCommitteeMembers(t, keyManager, 5)
+
+	committee, err := coordinator.NewSignerCommittee("", members, nil, keyManager, 3)
+	require.NoError(t, err)
+
+	// the members with the lowest public keys are selected
+	expected := make([]iotago.MilestonePublicKey, 0, len(members))
+	for _, member := range members {
+		expected = append(expected, committeeMemberKey(t, member))
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		return bytes.Compare(expected[i][:], expected[j][:]) < 0
+	})
+	expected = expected[:3]
+
+	for i := 0; i < 20; i++ {
+		require.Equal(t, expected, committee.MilestoneIndexSigner(iotago.MilestoneIndex(10+i)).PublicKeys())
+	}
+
+	// the order of the members doesn't matter
+	reversed := make([]*coordinator.SignerCommitteeMember, len(members))
+	for i, member := range members {
+		reversed[len(members)-1-i] = member
+	}
+	committee, err = coordinator.NewSignerCommittee("", reversed, nil, keyManager, 3)
+	require.NoError(t, err)
+	require.Equal(t, expected, committee.MilestoneIndexSigner(10).PublicKeys())
+}
+
+func TestSignerCommitteeChange(t *testing.T) {
+	keyManager := keymanager.New()
+	members := newTestCommitteeMembers(t, keyManager, 3)
+	added := newTestCommitteeMembers(t, keyManager, 1)[0]
+
+	filePath := filepath.Join(t.TempDir(), "committee.json")
+	committee, err := coordinator.NewSignerCommittee(filePath, members, nil, keyManager, 3)
+	require.NoError(t, err)
+
+	// the next milestone could already be in the making
+	_, err = committee.PrepareChange(11, []*coordinator.SignerCommitteeMember{added}, []string{members[0].PublicKey}, 10)
+	require.ErrorIs(t, err, coordinator.ErrSignerCommitteeChangeInvalid)
+
+	// the committee must still be able to sign milestones
+	_, err = committee.PrepareChange(20, nil, []string{members[0].PublicKey}, 10)
+	require.ErrorIs(t, err, coordinator.ErrSignerCommitteeChangeInvalid)
+
+	change, err := committee.PrepareChange(20, []*coordinator.SignerCommitteeMember{added}, []string{members[0].PublicKey}, 10)
+	require.NoError(t, err)
+	require.Len(t, committee.PreparedChanges(), 1)
+
+	// a prepared change is not active
+	require.Contains(t, committee.MilestoneIndexSigner(20).PublicKeys(), committeeMemberKey(t, members[0]))
+
+	committed, err := committee.CommitChange(change.ID, 12)
+	require.NoError(t, err)
+	require.Equal(t, change, committed)
+	require.Empty(t, committee.PreparedChanges())
+
+	// the change activates at its activation index
+	before := committee.MilestoneIndexSigner(19).PublicKeys()
+	require.Contains(t, before, committeeMemberKey(t, members[0]))
+	require.NotContains(t, before, committeeMemberKey(t, added))
+
+	after := committee.MilestoneIndexSigner(20).PublicKeys()
+	require.NotContains(t, after, committeeMemberKey(t, members[0]))
+	require.Contains(t, after, committeeMemberKey(t, added))
+	require.Len(t, after, 3)
+
+	// the committed committee is loaded from the file
+	loaded, err := coordinator.NewSignerCommittee(filePath, nil, nil, keyManager, 3)
+	require.NoError(t, err)
+	require.Equal(t, committee.Members(), loaded.Members())
+	require.Equal(t, after, loaded.MilestoneIndexSigner(20).PublicKeys())
+
+	// a committed change can't be committed again
+	_, err = committee.CommitChange(change.ID, 12)
+	require.ErrorIs(t, err, coordinator.ErrSignerCommitteeChangeNotFound)
+}
+
+func TestSignerCommitteeChangeRevalidatedOnCommit(t *testing.T) {
+	keyManager := keymanager.New()
+	members := newTestCommitteeMembers(t, keyManager, 3)
+	added := newTestCommitteeMembers(t, keyManager, 1)[0]
+
+	committee, err := coordinator.NewSignerCommittee("", members, nil, keyManager, 3)
+	require.NoError(t, err)
+
+	change, err := committee.PrepareChange(20, []*coordinator.SignerCommitteeMember{added}, []string{members[0].PublicKey}, 10)
+	require.NoError(t, err)
+
+	// the milestone at the activation index could already be in the making
+	_, err = committee.CommitChange(change.ID, 19)
+	require.ErrorIs(t, err, coordinator.ErrSignerCommitteeChangeInvalid)
+	require.Len(t, committee.PreparedChanges(), 1)
+	require.Contains(t, committee.MilestoneIndexSigner(20).PublicKeys(), committeeMemberKey(t, members[0]))
+}
+
+func TestSignerCommitteeAbortChange(t *testing.T) {
+	keyManager := keymanager.New()
+	members := newTestCommitteeMembers(t, keyManager, 3)
+	added := newTestCommitteeMembers(t, keyManager, 1)[0]
+
+	committee, err := coordinator.NewSignerCommittee("", members, nil, keyManager, 3)
+	require.NoError(t, err)
+
+	change, err := committee.PrepareChange(20, []*coordinator.SignerCommitteeMember{added}, []string{members[0].PublicKey}, 10)
+	require.NoError(t, err)
+
+	require.NoError(t, committee.AbortChange(change.ID))
+	require.Empty(t, committee.PreparedChanges())
+	require.ErrorIs(t, committee.AbortChange(change.ID), coordinator.ErrSignerCommitteeChangeNotFound)
+
+	// an aborted change can't be committed
+	_, err = committee.CommitChange(change.ID, 12)
+	require.ErrorIs(t, err, coordinator.ErrSignerCommitteeChangeNotFound)
+	require.Equal(t, members, committee.Members())
+	require.Contains(t, committee.MilestoneIndexSigner(20).PublicKeys(), committeeMemberKey(t, members[0]))
+}
@@ -0,0 +1,46 @@
+package coordinator
+
+import (
+	"time"
+)
+
+// SignerHealth contains information about the latest signing attempts of the milestone and treasury signers.
+type SignerHealth struct {
+	// the time of the latest successful signing attempt.
+	LastSuccessTime time.Time
+	// the time of the latest failed signing attempt.
+	LastFailureTime time.Time
+	// the error of the latest failed signing attempt.
+	LastError error
+	// the amount of consecutive failed signing attempts.
+	ConsecutiveFailures int
+	// the time of the latest signing self-test.
+	LastSelfTestTime time.Time
+	// the error of the latest signing self-test, nil if it succeeded.
+	LastSelfTestError error
+}
+
+// recordSigningResult updates the signer health with the result of a signing attempt.
+func (coo *Coordinator) recordSigningResult(err error) {
+	coo.signerHealthLock.Lock()
+	defer coo.signerHealthLock.Unlock()
+
+	if err != nil {
+		coo.signerHealth.LastFailureTime = time.Now()
+		coo.signerHealth.LastError = err
+		coo.signerHealth.ConsecutiveFailures++
+
+		return
+	}
+
+	coo.signerHealth.LastSuccessTime = time.Now()
+	coo.signerHealth.ConsecutiveFailures = 0
+}
+
+// SignerHealth returns information about the latest signing attempts of the milestone and treasury signers.
+func (coo *Coordinator) SignerHealth() SignerHealth {
+	coo.signerHealthLock.RLock()
+	defer coo.signerHealthLock.RUnlock()
+
+	return coo.signerHealth
+}
@@ -0,0 +1,96 @@
+package coordinator
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	iotagoEd25519 "github.com/iotaledger/iota.go/v3/ed25519"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+var (
+	// ErrSignerKeyNotValid is returned when a public key is not valid for the milestone index according to the key ranges.
+	ErrSignerKeyNotValid = errors.New("public key is not valid for the milestone index")
+	// ErrSignerResponseInvalid is returned when a signer returned a signature that is not valid for the requested public key.
+	ErrSignerResponseInvalid = errors.New("invalid signer response")
+)
+
+// describeKeyRanges returns the validity windows of the given public key, used in error messages.
+func describeKeyRanges(keyManager *keymanager.KeyManager, publicKey iotago.MilestonePublicKey) string {
+	var description string
+	for _, keyRange := range keyManager.KeyRanges() {
+		if keyRange.PublicKey != publicKey {
+			continue
+		}
+
+		if description != "" {
+			description += ", "
+		}
+
+		if keyRange.EndIndex == 0 {
+			description += fmt.Sprintf("[%d, ∞)", keyRange.StartIndex)
+
+			continue
+		}
+		description += fmt.Sprintf("[%d, %d]", keyRange.StartIndex, keyRange.EndIndex)
+	}
+
+	if description == "" {
+		return "no key range"
+	}
+
+	return "key ranges " + description
+}
+
+// VerifyMilestoneSignerKeys checks that all given public keys are valid for the milestone index according to their key ranges.
+func VerifyMilestoneSignerKeys(index iotago.MilestoneIndex, keyManager *keymanager.KeyManager, pubKeys []iotago.MilestonePublicKey) error {
+	validKeys := keyManager.PublicKeysSetForMilestoneIndex(index)
+	for _, pubKey := range pubKeys {
+		if _, valid := validKeys[pubKey]; !valid {
+			return fmt.Errorf("%w: key %s at milestone %d, %s", ErrSignerKeyNotValid, iotago.EncodeHex(pubKey[:]), index, describeKeyRanges(keyManager, pubKey))
+		}
+	}
+
+	return nil
+}
+
+// VerifyMilestoneSignerResponse checks that every signature of a signer response was produced by the requested public key
+// at the same position over the given milestone essence.
+func VerifyMilestoneSignerResponse(pubKeys []iotago.MilestonePublicKey, msEssence []byte, sigs []iotago.MilestoneSignature) error {
+	if len(sigs) != len(pubKeys) {
+		return fmt.Errorf("%w: wanted %d signatures, got %d", ErrSignerResponseInvalid, len(pubKeys), len(sigs))
+	}
+
+	for i, pubKey := range pubKeys {
+		if !iotagoEd25519.Verify(pubKey[:], msEssence, sigs[i][:]) {
+			return fmt.Errorf("%w: signature at index %d was not produced by key %s", ErrSignerResponseInvalid, i, iotago.EncodeHex(pubKey[:]))
+		}
+	}
+
+	return nil
+}
+
+// KeyRangeVerifyingSigningFunc wraps the signing function of a signer of the milestone with the given index.
+// Only public keys that are valid for the milestone index are requested from the signer,
+// and every response is verified against the requested public keys, so a signer that signs with another key is detected
+// before the milestone is assembled.
+func KeyRangeVerifyingSigningFunc(index iotago.MilestoneIndex, keyManager *keymanager.KeyManager, signingFunc iotago.MilestoneSigningFunc) iotago.MilestoneSigningFunc {
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		if err := VerifyMilestoneSignerKeys(index, keyManager, pubKeys); err != nil {
+			return nil, err
+		}
+
+		sigs, err := signingFunc(pubKeys, msEssence)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := VerifyMilestoneSignerResponse(pubKeys, msEssence, sigs); err != nil {
+			return nil, err
+		}
+
+		return sigs, nil
+	}
+}
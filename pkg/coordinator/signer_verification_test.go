@@ -0,0 +1,77 @@
+package coordinator_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+func TestKeyRangeVerifyingSigningFunc(t *testing.T) {
+	keyManager := keymanager.New()
+	keyPairs := iotago.MilestonePublicKeyMapping{}
+	pubKeys := make([]iotago.MilestonePublicKey, 0, 2)
+	for _, keyRange := range [][2]iotago.MilestoneIndex{{0, 100}, {50, 0}} {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keyManager.AddKeyRange(publicKey, keyRange[0], keyRange[1])
+
+		var pubKey iotago.MilestonePublicKey
+		copy(pubKey[:], publicKey)
+		keyPairs[pubKey] = privateKey
+		pubKeys = append(pubKeys, pubKey)
+	}
+	essence := []byte("milestone essence")
+
+	// both keys are valid at index 50
+	signingFunc := coordinator.KeyRangeVerifyingSigningFunc(50, keyManager, iotago.InMemoryEd25519MilestoneSigner(keyPairs))
+	sigs, err := signingFunc(pubKeys, essence)
+	require.NoError(t, err)
+	require.Len(t, sigs, 2)
+
+	// the first key is no longer valid at index 101, the signer is not asked
+	signerCalled := false
+	signingFunc = coordinator.KeyRangeVerifyingSigningFunc(101, keyManager, func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		signerCalled = true
+
+		return iotago.InMemoryEd25519MilestoneSigner(keyPairs)(pubKeys, msEssence)
+	})
+	_, err = signingFunc(pubKeys, essence)
+	require.ErrorIs(t, err, coordinator.ErrSignerKeyNotValid)
+	require.Contains(t, err.Error(), "key ranges [0, 100]")
+	require.False(t, signerCalled)
+
+	// the second key is not valid yet at index 49
+	_, err = coordinator.KeyRangeVerifyingSigningFunc(49, keyManager, iotago.InMemoryEd25519MilestoneSigner(keyPairs))(pubKeys, essence)
+	require.ErrorIs(t, err, coordinator.ErrSignerKeyNotValid)
+	require.Contains(t, err.Error(), "key ranges [50, ∞)")
+
+	// unknown keys are never valid
+	_, err = coordinator.KeyRangeVerifyingSigningFunc(50, keyManager, iotago.InMemoryEd25519MilestoneSigner(keyPairs))([]iotago.MilestonePublicKey{{1}}, essence)
+	require.ErrorIs(t, err, coordinator.ErrSignerKeyNotValid)
+	require.Contains(t, err.Error(), "no key range")
+
+	// a signer that signs with the other valid key is detected
+	swappedSigner := func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		sigs, err := iotago.InMemoryEd25519MilestoneSigner(keyPairs)(pubKeys, msEssence)
+		if err != nil {
+			return nil, err
+		}
+		sigs[0], sigs[1] = sigs[1], sigs[0]
+
+		return sigs, nil
+	}
+	_, err = coordinator.KeyRangeVerifyingSigningFunc(50, keyManager, swappedSigner)(pubKeys, essence)
+	require.ErrorIs(t, err, coordinator.ErrSignerResponseInvalid)
+
+	// a signer that returns too few signatures is detected
+	_, err = coordinator.KeyRangeVerifyingSigningFunc(50, keyManager, func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		return iotago.InMemoryEd25519MilestoneSigner(keyPairs)(pubKeys[:1], msEssence)
+	})(pubKeys, essence)
+	require.ErrorIs(t, err, coordinator.ErrSignerResponseInvalid)
+}
@@ -89,56 +89,58 @@ func (s *InMemoryEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSign
 	return s.signingFunc
 }
 
-// InsecureRemoteEd25519MilestoneSignerProvider provides InsecureRemoteEd25519MilestoneIndexSigner.
-type InsecureRemoteEd25519MilestoneSignerProvider struct {
+// RemoteEd25519MilestoneSignerProvider provides RemoteEd25519MilestoneIndexSigner.
+type RemoteEd25519MilestoneSignerProvider struct {
 	signingFunc     iotago.MilestoneSigningFunc
 	keyManger       *keymanager.KeyManager
 	publicKeysCount int
 }
 
-// NewInsecureRemoteEd25519MilestoneSignerProvider creates a new InsecureRemoteEd25519MilestoneSignerProvider.
-func NewInsecureRemoteEd25519MilestoneSignerProvider(remoteEndpoint string, keyManager *keymanager.KeyManager, publicKeysCount int) *InsecureRemoteEd25519MilestoneSignerProvider {
+// NewRemoteEd25519MilestoneSignerProvider creates a new RemoteEd25519MilestoneSignerProvider.
+// If no remote signer connections are given, a new insecure connection to the remote signer is used for every signing request.
+func NewRemoteEd25519MilestoneSignerProvider(remoteEndpoint string, remoteSigners *RemoteSignerConnections, keyManager *keymanager.KeyManager, publicKeysCount int) *RemoteEd25519MilestoneSignerProvider {
 
-	return &InsecureRemoteEd25519MilestoneSignerProvider{
-		signingFunc:     iotago.InsecureRemoteEd25519MilestoneSigner(remoteEndpoint),
+	return &RemoteEd25519MilestoneSignerProvider{
+		signingFunc:     remoteSigners.SigningFunc(remoteEndpoint),
 		keyManger:       keyManager,
 		publicKeysCount: publicKeysCount,
 	}
 }
 
 // MilestoneIndexSigner returns a new signer for the milestone index.
-func (p *InsecureRemoteEd25519MilestoneSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) MilestoneIndexSigner {
+// Every response of the remote signer is verified against the public keys that are valid for the milestone index.
+func (p *RemoteEd25519MilestoneSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) MilestoneIndexSigner {
 
-	return &InsecureRemoteEd25519MilestoneIndexSigner{
+	return &RemoteEd25519MilestoneIndexSigner{
 		pubKeys:     p.keyManger.PublicKeysForMilestoneIndex(index),
 		pubKeySet:   p.keyManger.PublicKeysSetForMilestoneIndex(index),
-		signingFunc: p.signingFunc,
+		signingFunc: KeyRangeVerifyingSigningFunc(index, p.keyManger, p.signingFunc),
 	}
 }
 
 // PublicKeysCount returns the amount of public keys in a milestone.
-func (p *InsecureRemoteEd25519MilestoneSignerProvider) PublicKeysCount() int {
+func (p *RemoteEd25519MilestoneSignerProvider) PublicKeysCount() int {
 	return p.publicKeysCount
 }
 
-// InsecureRemoteEd25519MilestoneIndexSigner is an in memory signer for a particular milestone.
-type InsecureRemoteEd25519MilestoneIndexSigner struct {
+// RemoteEd25519MilestoneIndexSigner is a remote signer for a particular milestone.
+type RemoteEd25519MilestoneIndexSigner struct {
 	pubKeys     []iotago.MilestonePublicKey
 	pubKeySet   iotago.MilestonePublicKeySet
 	signingFunc iotago.MilestoneSigningFunc
 }
 
 // PublicKeys returns a slice of the used public keys.
-func (s *InsecureRemoteEd25519MilestoneIndexSigner) PublicKeys() []iotago.MilestonePublicKey {
+func (s *RemoteEd25519MilestoneIndexSigner) PublicKeys() []iotago.MilestonePublicKey {
 	return s.pubKeys
 }
 
 // PublicKeysSet returns a map of the used public keys.
-func (s *InsecureRemoteEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
+func (s *RemoteEd25519MilestoneIndexSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
 	return s.pubKeySet
 }
 
 // SigningFunc returns a function to sign the particular milestone.
-func (s *InsecureRemoteEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
+func (s *RemoteEd25519MilestoneIndexSigner) SigningFunc() iotago.MilestoneSigningFunc {
 	return s.signingFunc
 }
@@ -0,0 +1,164 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	iotagoEd25519 "github.com/iotaledger/iota.go/v3/ed25519"
+)
+
+// signingSelfTestPrefix separates the test essence of the signing self-test from the essences of real milestones and receipts.
+const signingSelfTestPrefix = "inx-coordinator signing self-test"
+
+var (
+	// ErrSigningSelfTestFailed is returned when a signer did not produce valid signatures for the test essence.
+	ErrSigningSelfTestFailed = errors.New("signing self-test failed")
+)
+
+// SigningSelfTestResult is the result of a signing self-test.
+type SigningSelfTestResult struct {
+	// the index of the next milestone whose signers were tested.
+	Index iotago.MilestoneIndex
+	// the time the self-test was started.
+	Time time.Time
+	// the duration of the self-test.
+	Duration time.Duration
+	// the error of the self-test, nil if all signers produced valid signatures.
+	Err error
+}
+
+// SigningSelfTestCaller is used to signal the result of a signing self-test.
+func SigningSelfTestCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(result *SigningSelfTestResult))(params[0].(*SigningSelfTestResult))
+}
+
+// SigningSelfTestEssence returns the test essence that is signed during the signing self-test.
+// The essence is the hash of a fixed prefix, the milestone index and the time,
+// so the resulting signatures are not valid for any milestone or receipt and can't be submitted.
+func SigningSelfTestEssence(index iotago.MilestoneIndex, testTime time.Time) []byte {
+	data := make([]byte, 0, len(signingSelfTestPrefix)+4+8)
+	data = append(data, signingSelfTestPrefix...)
+	data = binary.LittleEndian.AppendUint32(data, index)
+	data = binary.LittleEndian.AppendUint64(data, uint64(testTime.UnixNano()))
+
+	essence := blake2b.Sum256(data)
+
+	return essence[:]
+}
+
+// SelfTestSigners signs the given essence with the milestone signers and the optional treasury signer
+// and verifies the signatures against the expected public keys.
+func SelfTestSigners(milestoneIndexSigner MilestoneIndexSigner, publicKeysCount int, treasurySigner TreasurySigner, essence []byte) error {
+	pubKeys := milestoneIndexSigner.PublicKeys()
+	if len(pubKeys) < publicKeysCount {
+		return fmt.Errorf("%w: only %d of %d required milestone keys are available", ErrSigningSelfTestFailed, len(pubKeys), publicKeysCount)
+	}
+
+	sigs, err := milestoneIndexSigner.SigningFunc()(pubKeys, essence)
+	if err != nil {
+		return fmt.Errorf("%w: unable to produce milestone signatures: %v", ErrSigningSelfTestFailed, err)
+	}
+
+	if len(sigs) != len(pubKeys) {
+		return fmt.Errorf("%w: %d milestone signatures for %d public keys", ErrSigningSelfTestFailed, len(sigs), len(pubKeys))
+	}
+
+	publicKeysSet := milestoneIndexSigner.PublicKeysSet()
+	for i, pubKey := range pubKeys {
+		if _, known := publicKeysSet[pubKey]; !known {
+			return fmt.Errorf("%w: milestone public key %s is not valid for the next milestone", ErrSigningSelfTestFailed, iotago.EncodeHex(pubKey[:]))
+		}
+		if !iotagoEd25519.Verify(pubKey[:], essence, sigs[i][:]) {
+			return fmt.Errorf("%w: signature of milestone public key %s is invalid", ErrSigningSelfTestFailed, iotago.EncodeHex(pubKey[:]))
+		}
+	}
+
+	if treasurySigner == nil {
+		return nil
+	}
+
+	publicKey := treasurySigner.PublicKey()
+
+	treasurySigs, err := treasurySigner.SigningFunc()([]iotago.MilestonePublicKey{publicKey}, essence)
+	if err != nil {
+		return fmt.Errorf("%w: unable to produce treasury signature: %v", ErrSigningSelfTestFailed, err)
+	}
+
+	if len(treasurySigs) != 1 {
+		return fmt.Errorf("%w: treasury signer did not provide exactly one signature", ErrSigningSelfTestFailed)
+	}
+
+	if err := VerifyTreasurySignature(publicKey, essence, &iotago.Ed25519Signature{PublicKey: publicKey, Signature: treasurySigs[0]}); err != nil {
+		return fmt.Errorf("%w: %v", ErrSigningSelfTestFailed, err)
+	}
+
+	return nil
+}
+
+// SelfTestSigning signs a test essence with all signers of the next milestone and the treasury signer
+// and verifies the signatures, to detect failing signers before a real milestone needs to be signed.
+// The test essence can't be submitted, so the self-test has no effect on the network.
+func (coo *Coordinator) SelfTestSigning() *SigningSelfTestResult {
+	result := &SigningSelfTestResult{
+		Index: coo.State().LatestMilestoneIndex + 1,
+		Time:  time.Now(),
+	}
+
+	// the signers are tested with the same retries that are used for real milestones
+	milestoneIndexSigner := &milestoneIndexSignerWithRetries{
+		MilestoneIndexSigner: coo.signerProvider.MilestoneIndexSigner(result.Index),
+		coo:                  coo,
+	}
+
+	var treasurySigner TreasurySigner
+	if coo.treasurySigner != nil {
+		treasurySigner = &treasurySignerWithRetries{
+			TreasurySigner: coo.treasurySigner,
+			coo:            coo,
+		}
+	}
+
+	essence := SigningSelfTestEssence(result.Index, result.Time)
+	if err := SelfTestSigners(milestoneIndexSigner, coo.signerProvider.PublicKeysCount(), treasurySigner, essence); err != nil {
+		result.Err = &SigningError{Index: result.Index, Stage: StageSelfTest, Err: err}
+	}
+	result.Duration = time.Since(result.Time)
+
+	coo.signerHealthLock.Lock()
+	coo.signerHealth.LastSelfTestTime = result.Time
+	coo.signerHealth.LastSelfTestError = result.Err
+	coo.signerHealthLock.Unlock()
+
+	coo.Events.SigningSelfTestCompleted.Trigger(result)
+
+	return result
+}
+
+// milestoneIndexSignerWithRetries is a MilestoneIndexSigner that retries failed signing attempts.
+type milestoneIndexSignerWithRetries struct {
+	MilestoneIndexSigner
+	coo *Coordinator
+}
+
+// SigningFunc returns a function to sign the particular milestone.
+func (s *milestoneIndexSignerWithRetries) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.coo.createSigningFuncWithRetries(context.Background(), s.MilestoneIndexSigner.SigningFunc())
+}
+
+// treasurySignerWithRetries is a TreasurySigner that retries failed signing attempts.
+type treasurySignerWithRetries struct {
+	TreasurySigner
+	coo *Coordinator
+}
+
+// SigningFunc returns a function to sign the receipt essence.
+func (s *treasurySignerWithRetries) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.coo.createSigningFuncWithRetries(context.Background(), s.TreasurySigner.SigningFunc())
+}
@@ -0,0 +1,65 @@
+package coordinator_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+// wrongKeyTreasurySigner signs with a different key than the public key it claims.
+type wrongKeyTreasurySigner struct {
+	*coordinator.InMemoryEd25519TreasurySigner
+	publicKey iotago.MilestonePublicKey
+}
+
+func (s *wrongKeyTreasurySigner) PublicKey() iotago.MilestonePublicKey {
+	return s.publicKey
+}
+
+func TestSelfTestSigners(t *testing.T) {
+	keyManager := keymanager.New()
+	privateKeys := make([]ed25519.PrivateKey, 0, 2)
+	for i := 0; i < 2; i++ {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		keyManager.AddKeyRange(publicKey, 0, 0)
+		privateKeys = append(privateKeys, privateKey)
+	}
+
+	_, treasuryPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	treasurySigner := coordinator.NewInMemoryEd25519TreasurySigner(treasuryPrivateKey)
+
+	essence := coordinator.SigningSelfTestEssence(42, time.Unix(1000, 0))
+	require.Len(t, essence, 32)
+	require.NotEqual(t, essence, coordinator.SigningSelfTestEssence(43, time.Unix(1000, 0)))
+	require.NotEqual(t, essence, coordinator.SigningSelfTestEssence(42, time.Unix(1001, 0)))
+
+	// all signers produce valid signatures
+	signerProvider := coordinator.NewInMemoryEd25519MilestoneSignerProvider(privateKeys, keyManager, 2)
+	require.NoError(t, coordinator.SelfTestSigners(signerProvider.MilestoneIndexSigner(42), 2, nil, essence))
+	require.NoError(t, coordinator.SelfTestSigners(signerProvider.MilestoneIndexSigner(42), 2, treasurySigner, essence))
+
+	// the private key of a milestone key is missing
+	signerProvider = coordinator.NewInMemoryEd25519MilestoneSignerProvider(privateKeys[:1], keyManager, 2)
+	err = coordinator.SelfTestSigners(signerProvider.MilestoneIndexSigner(42), 2, treasurySigner, essence)
+	require.ErrorIs(t, err, coordinator.ErrSigningSelfTestFailed)
+
+	// the treasury signer doesn't sign with the expected key
+	_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signerProvider = coordinator.NewInMemoryEd25519MilestoneSignerProvider(privateKeys, keyManager, 2)
+	err = coordinator.SelfTestSigners(signerProvider.MilestoneIndexSigner(42), 2, &wrongKeyTreasurySigner{
+		InMemoryEd25519TreasurySigner: coordinator.NewInMemoryEd25519TreasurySigner(otherPrivateKey),
+		publicKey:                     treasurySigner.PublicKey(),
+	}, essence)
+	require.ErrorIs(t, err, coordinator.ErrSigningSelfTestFailed)
+}
@@ -0,0 +1,169 @@
+package coordinator
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+)
+
+const (
+	SoftErrorClassNodeNotSynced         = "node_not_synced"
+	SoftErrorClassNodeLoadTooHigh       = "node_load_too_high"
+	SoftErrorClassNodeLagging           = "node_lagging"
+	SoftErrorClassNodeUnhealthy         = "node_unhealthy"
+	SoftErrorClassSuspended             = "suspended"
+	SoftErrorClassTimestampNotIncreased = "timestamp_not_increased"
+	SoftErrorClassMilestoneNotConfirmed = "milestone_not_confirmed"
+	SoftErrorClassQuorum                = "quorum"
+	SoftErrorClassSubmission            = "submission"
+	SoftErrorClassOther                 = "other"
+)
+
+// softErrorClasses maps the known causes of soft errors to their classes.
+var softErrorClasses = []struct {
+	err   error
+	class string
+}{
+	{common.ErrNodeNotSynced, SoftErrorClassNodeNotSynced},
+	{ErrNodeLoadTooHigh, SoftErrorClassNodeLoadTooHigh},
+	{ErrNodeLagging, SoftErrorClassNodeLagging},
+	{ErrNodeUnhealthy, SoftErrorClassNodeUnhealthy},
+	{ErrSuspended, SoftErrorClassSuspended},
+	{ErrMilestoneTimestampDidNotIncrease, SoftErrorClassTimestampNotIncreased},
+	{ErrMilestoneNotConfirmed, SoftErrorClassMilestoneNotConfirmed},
+	{ErrQuorumMerkleTreeHashMismatch, SoftErrorClassQuorum},
+	{ErrQuorumGroupNoAnswer, SoftErrorClassQuorum},
+	// matches all submission errors, so it must be checked after the more specific causes
+	{&SubmissionError{}, SoftErrorClassSubmission},
+}
+
+// SoftErrorClass returns the class of the given soft error.
+func SoftErrorClass(err error) string {
+	for _, entry := range softErrorClasses {
+		if errors.Is(err, entry.err) {
+			return entry.class
+		}
+	}
+
+	return SoftErrorClassOther
+}
+
+// SoftErrorEntry is a soft error that was encountered by the coordinator.
+type SoftErrorEntry struct {
+	// the time the error was encountered.
+	Timestamp time.Time `json:"timestamp"`
+	// the class of the error.
+	Class string `json:"class"`
+	// the error message.
+	Message string `json:"message"`
+}
+
+// SoftErrorHistory keeps the latest soft errors in a ring buffer,
+// so that operators can inspect them after the fact.
+// If a file path is given, the history is persisted to disk and survives restarts.
+type SoftErrorHistory struct {
+	mutex syncutils.RWMutex
+
+	// the path to the file the history is persisted to (optional).
+	filePath string
+	// the ring buffer holding the entries.
+	entries []*SoftErrorEntry
+	// the position in the ring buffer the next entry is written to.
+	next int
+	// the amount of entries in the ring buffer.
+	count int
+}
+
+// NewSoftErrorHistory creates a new SoftErrorHistory that keeps the latest size soft errors.
+// If the history file exists, the entries are loaded from the file.
+func NewSoftErrorHistory(size int, filePath string) (*SoftErrorHistory, error) {
+
+	if size <= 0 {
+		return nil, errors.New("the size of the soft error history must be greater than 0")
+	}
+
+	h := &SoftErrorHistory{
+		filePath: filePath,
+		entries:  make([]*SoftErrorEntry, size),
+	}
+
+	if filePath == "" {
+		return h, nil
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+
+		return nil, fmt.Errorf("unable to check soft error history file: %w", err)
+	}
+
+	var entries []*SoftErrorEntry
+	if err := ioutils.ReadJSONFromFile(filePath, &entries); err != nil {
+		return nil, fmt.Errorf("unable to load soft error history file: %w", err)
+	}
+
+	for _, entry := range entries {
+		h.add(entry)
+	}
+
+	return h, nil
+}
+
+// add writes the entry to the ring buffer and overwrites the oldest entry if the buffer is full.
+func (h *SoftErrorHistory) add(entry *SoftErrorEntry) {
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	if h.count < len(h.entries) {
+		h.count++
+	}
+}
+
+// entriesWithoutLocking returns the entries ordered from the oldest to the newest.
+func (h *SoftErrorHistory) entriesWithoutLocking() []*SoftErrorEntry {
+	entries := make([]*SoftErrorEntry, 0, h.count)
+
+	start := (h.next - h.count + len(h.entries)) % len(h.entries)
+	for i := 0; i < h.count; i++ {
+		entries = append(entries, h.entries[(start+i)%len(h.entries)])
+	}
+
+	return entries
+}
+
+// Add records a soft error and persists the history if a file path is set.
+func (h *SoftErrorHistory) Add(err error) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.add(&SoftErrorEntry{
+		Timestamp: time.Now(),
+		Class:     SoftErrorClass(err),
+		Message:   err.Error(),
+	})
+
+	if h.filePath == "" {
+		return nil
+	}
+
+	if err := ioutils.WriteJSONToFile(h.filePath, h.entriesWithoutLocking(), 0660); err != nil {
+		return fmt.Errorf("unable to persist soft error history file: %w", err)
+	}
+
+	return nil
+}
+
+// Entries returns the recorded soft errors ordered from the oldest to the newest.
+func (h *SoftErrorHistory) Entries() []*SoftErrorEntry {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.entriesWithoutLocking()
+}
@@ -0,0 +1,42 @@
+package coordinator_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func TestSoftErrorHistory(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "soft_errors.json")
+
+	history, err := coordinator.NewSoftErrorHistory(3, filePath)
+	require.NoError(t, err)
+	require.Empty(t, history.Entries())
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, history.Add(common.SoftError(fmt.Errorf("error %d: %w", i, coordinator.ErrNodeLoadTooHigh))))
+	}
+	require.NoError(t, history.Add(common.SoftError(common.ErrNodeNotSynced)))
+
+	// only the latest entries are kept, ordered from the oldest to the newest
+	entries := history.Entries()
+	require.Len(t, entries, 3)
+	require.Contains(t, entries[0].Message, "error 3")
+	require.Contains(t, entries[1].Message, "error 4")
+	require.Equal(t, coordinator.SoftErrorClassNodeLoadTooHigh, entries[1].Class)
+	require.Equal(t, coordinator.SoftErrorClassNodeNotSynced, entries[2].Class)
+
+	// the history survives a restart
+	loaded, err := coordinator.NewSoftErrorHistory(2, filePath)
+	require.NoError(t, err)
+
+	loadedEntries := loaded.Entries()
+	require.Len(t, loadedEntries, 2)
+	require.Equal(t, entries[1].Message, loadedEntries[0].Message)
+	require.Equal(t, entries[2].Message, loadedEntries[1].Message)
+}
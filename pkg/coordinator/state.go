@@ -4,31 +4,53 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/iotaledger/inx-coordinator/pkg/stateversion"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
+const (
+	// StateVersion is the version of the coordinator state file schema.
+	StateVersion = 2
+)
+
+var (
+	// stateSchema is used to upgrade older coordinator state files to the current version.
+	stateSchema = stateversion.NewSchema("coordinator", StateVersion, map[uint32]stateversion.Migration{
+		// unversioned state files only lack the version field
+		stateversion.UnversionedVersion: func(_ map[string]json.RawMessage) error { return nil },
+		// version 1 lacks the network reset index, networks that were bootstrapped before were never reset
+		1: func(_ map[string]json.RawMessage) error { return nil },
+	})
+)
+
 // State stores the latest state of the coordinator.
+// NetworkResetIndex is the index of the first milestone after the network was reset (0 = the network was never reset).
 type State struct {
 	LatestMilestoneIndex   iotago.MilestoneIndex
 	LatestMilestoneBlockID iotago.BlockID
 	LatestMilestoneID      iotago.MilestoneID
 	LatestMilestoneTime    time.Time
+	NetworkResetIndex      iotago.MilestoneIndex
 }
 
 // jsoncoostate is the JSON representation of a coordinator state.
 type jsoncoostate struct {
+	Version                uint32 `json:"version"`
 	LatestMilestoneIndex   uint32 `json:"latestMilestoneIndex"`
 	LatestMilestoneBlockID string `json:"latestMilestoneBlockId"`
 	LatestMilestoneID      string `json:"latestMilestoneId"`
 	LatestMilestoneTime    int64  `json:"latestMilestoneTime"`
+	NetworkResetIndex      uint32 `json:"networkResetIndex"`
 }
 
 func (cs *State) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&jsoncoostate{
+		Version:                StateVersion,
 		LatestMilestoneIndex:   cs.LatestMilestoneIndex,
 		LatestMilestoneBlockID: cs.LatestMilestoneBlockID.ToHex(),
 		LatestMilestoneID:      cs.LatestMilestoneID.ToHex(),
 		LatestMilestoneTime:    cs.LatestMilestoneTime.UnixNano(),
+		NetworkResetIndex:      cs.NetworkResetIndex,
 	})
 }
 
@@ -53,6 +75,7 @@ func (cs *State) UnmarshalJSON(data []byte) error {
 
 	cs.LatestMilestoneIndex = jsonCooState.LatestMilestoneIndex
 	cs.LatestMilestoneTime = time.Unix(0, jsonCooState.LatestMilestoneTime)
+	cs.NetworkResetIndex = jsonCooState.NetworkResetIndex
 
 	return nil
 }
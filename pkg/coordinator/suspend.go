@@ -0,0 +1,46 @@
+package coordinator
+
+import (
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrSuspended is returned when the issuance of milestones is suspended for a handoff.
+	ErrSuspended = errors.New("coordinator is suspended for a handoff")
+	// ErrReceiptPending is returned when the coordinator can't be suspended because a receipt waits to be issued.
+	ErrReceiptPending = errors.New("a receipt that was not issued yet is pending")
+)
+
+// Suspend stops the issuance of milestones and checkpoints, e.g. to hand off to another coordinator process.
+// It waits until the current milestone was issued and the state file was written, and returns a copy of the state.
+// The coordinator isn't suspended while a receipt that was not sent yet waits to be issued with the next milestone,
+// because the receipt only exists in memory.
+func (coo *Coordinator) Suspend() (*State, error) {
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	if coo.pendingReceipt != nil {
+		return nil, ErrReceiptPending
+	}
+
+	coo.suspended = true
+	state := *coo.state
+
+	return &state, nil
+}
+
+// Resume continues the issuance of milestones and checkpoints after Suspend.
+func (coo *Coordinator) Resume() {
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	coo.suspended = false
+}
+
+// Suspended returns whether the issuance of milestones is suspended.
+func (coo *Coordinator) Suspended() bool {
+	coo.milestoneLock.Lock()
+	defer coo.milestoneLock.Unlock()
+
+	return coo.suspended
+}
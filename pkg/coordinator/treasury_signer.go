@@ -0,0 +1,111 @@
+package coordinator
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+	iotagoEd25519 "github.com/iotaledger/iota.go/v3/ed25519"
+)
+
+var (
+	// ErrInvalidTreasurySignature is returned when the signature of the treasury key over a receipt is invalid.
+	ErrInvalidTreasurySignature = errors.New("invalid treasury signature")
+)
+
+// TreasurySigner signs receipts with a treasury key, which is distinct from the milestone keys.
+// This adds a second authorization domain for fund migrations.
+type TreasurySigner interface {
+	// PublicKey returns the public key of the treasury key.
+	PublicKey() iotago.MilestonePublicKey
+	// SigningFunc returns a function to sign the receipt essence.
+	SigningFunc() iotago.MilestoneSigningFunc
+}
+
+// ReceiptEssence returns the essence of the receipt (including the treasury transaction) that gets signed by the treasury key.
+func ReceiptEssence(receipt *iotago.ReceiptMilestoneOpt, protoParams *iotago.ProtocolParameters) ([]byte, error) {
+	receiptBytes, err := receipt.Serialize(serializer.DeSeriModeNoValidation, protoParams)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize receipt: %w", err)
+	}
+
+	essence := blake2b.Sum256(receiptBytes)
+
+	return essence[:], nil
+}
+
+// VerifyTreasurySignature verifies the signature of the treasury key over the given receipt essence.
+func VerifyTreasurySignature(publicKey iotago.MilestonePublicKey, essence []byte, signature *iotago.Ed25519Signature) error {
+	if signature.PublicKey != publicKey {
+		return fmt.Errorf("%w: unexpected public key %s", ErrInvalidTreasurySignature, iotago.EncodeHex(signature.PublicKey[:]))
+	}
+
+	if !iotagoEd25519.Verify(signature.PublicKey[:], essence, signature.Signature[:]) {
+		return ErrInvalidTreasurySignature
+	}
+
+	return nil
+}
+
+// InMemoryEd25519TreasurySigner is an in memory signer for the treasury key.
+type InMemoryEd25519TreasurySigner struct {
+	publicKey   iotago.MilestonePublicKey
+	signingFunc iotago.MilestoneSigningFunc
+}
+
+// NewInMemoryEd25519TreasurySigner creates a new InMemoryEd25519TreasurySigner.
+func NewInMemoryEd25519TreasurySigner(privateKey ed25519.PrivateKey) *InMemoryEd25519TreasurySigner {
+
+	var publicKey iotago.MilestonePublicKey
+	//nolint:forcetypeassert // ed25519.PrivateKey.Public always returns an ed25519.PublicKey
+	copy(publicKey[:], privateKey.Public().(ed25519.PublicKey))
+
+	return &InMemoryEd25519TreasurySigner{
+		publicKey:   publicKey,
+		signingFunc: iotago.InMemoryEd25519MilestoneSigner(iotago.MilestonePublicKeyMapping{publicKey: privateKey}),
+	}
+}
+
+// PublicKey returns the public key of the treasury key.
+func (s *InMemoryEd25519TreasurySigner) PublicKey() iotago.MilestonePublicKey {
+	return s.publicKey
+}
+
+// SigningFunc returns a function to sign the receipt essence.
+func (s *InMemoryEd25519TreasurySigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.signingFunc
+}
+
+// RemoteEd25519TreasurySigner is a remote signer for the treasury key.
+// The remote signer uses the same protocol as the remote milestone signer.
+type RemoteEd25519TreasurySigner struct {
+	publicKey   iotago.MilestonePublicKey
+	signingFunc iotago.MilestoneSigningFunc
+}
+
+// NewRemoteEd25519TreasurySigner creates a new RemoteEd25519TreasurySigner.
+// If no remote signer connections are given, a new insecure connection to the remote signer is used for every signing request.
+func NewRemoteEd25519TreasurySigner(remoteEndpoint string, remoteSigners *RemoteSignerConnections, publicKey ed25519.PublicKey) *RemoteEd25519TreasurySigner {
+
+	var pubKey iotago.MilestonePublicKey
+	copy(pubKey[:], publicKey)
+
+	return &RemoteEd25519TreasurySigner{
+		publicKey:   pubKey,
+		signingFunc: remoteSigners.SigningFunc(remoteEndpoint),
+	}
+}
+
+// PublicKey returns the public key of the treasury key.
+func (s *RemoteEd25519TreasurySigner) PublicKey() iotago.MilestonePublicKey {
+	return s.publicKey
+}
+
+// SigningFunc returns a function to sign the receipt essence.
+func (s *RemoteEd25519TreasurySigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return s.signingFunc
+}
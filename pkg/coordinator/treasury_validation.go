@@ -0,0 +1,80 @@
+package coordinator
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+const (
+	// TreasuryValidationStrict rejects receipts if the treasury output is inconsistent.
+	TreasuryValidationStrict = "strict"
+	// TreasuryValidationRelaxed only logs inconsistencies of the treasury output that don't invalidate the receipt,
+	// e.g. for private test networks that were bootstrapped with a synthetic treasury.
+	TreasuryValidationRelaxed = "relaxed"
+)
+
+var (
+	// ErrUnknownTreasuryValidationMode is returned when an unknown treasury validation mode is configured.
+	ErrUnknownTreasuryValidationMode = errors.New("unknown treasury validation mode")
+	// ErrTreasuryInsufficient is returned when the treasury output can't fund the migrations of a receipt.
+	ErrTreasuryInsufficient = errors.New("treasury output is insufficient")
+	// ErrTreasuryInconsistent is returned when the treasury output is inconsistent with the protocol.
+	ErrTreasuryInconsistent = errors.New("treasury output is inconsistent")
+)
+
+// TreasuryValidation checks the treasury output that is spent by a receipt.
+type TreasuryValidation struct {
+	// the validation mode.
+	Mode string
+	// the maximum amount of the treasury output (0 = the token supply of the protocol).
+	MaxAmount uint64
+}
+
+// NewTreasuryValidation creates a new TreasuryValidation.
+func NewTreasuryValidation(mode string, maxAmount uint64) (*TreasuryValidation, error) {
+	switch mode {
+	case TreasuryValidationStrict, TreasuryValidationRelaxed:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTreasuryValidationMode, mode)
+	}
+
+	return &TreasuryValidation{
+		Mode:      mode,
+		MaxAmount: maxAmount,
+	}, nil
+}
+
+// Validate checks the treasury output against the sum of the migrations of a receipt.
+// An inconsistency that is tolerated by the mode is returned as tolerated, otherwise as err.
+// An insufficient treasury is never tolerated, because the treasury transaction of the receipt would be invalid.
+func (v *TreasuryValidation) Validate(treasuryOutput *LatestTreasuryOutput, receiptSum uint64, tokenSupply uint64) (tolerated error, err error) {
+	if receiptSum > treasuryOutput.Amount {
+		return nil, fmt.Errorf("%w: the receipt migrates %d, but the treasury only holds %d", ErrTreasuryInsufficient, receiptSum, treasuryOutput.Amount)
+	}
+
+	maxAmount := v.MaxAmount
+	if maxAmount == 0 {
+		maxAmount = tokenSupply
+	}
+
+	if treasuryOutput.Amount <= maxAmount {
+		return nil, nil
+	}
+
+	inconsistency := fmt.Errorf("%w: the treasury holds %d, but at most %d are allowed", ErrTreasuryInconsistent, treasuryOutput.Amount, maxAmount)
+	if v.Mode == TreasuryValidationRelaxed {
+		return inconsistency, nil
+	}
+
+	return nil, inconsistency
+}
+
+// WithTreasuryValidation defines how the treasury output that is spent by a receipt is validated.
+func WithTreasuryValidation(treasuryValidation *TreasuryValidation) options.Option[Coordinator] {
+	return func(c *Coordinator) {
+		c.treasuryValidation = treasuryValidation
+	}
+}
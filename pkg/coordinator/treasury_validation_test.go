@@ -0,0 +1,46 @@
+package coordinator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func TestTreasuryValidation(t *testing.T) {
+	const tokenSupply = 1_000_000
+
+	_, err := coordinator.NewTreasuryValidation("lenient", 0)
+	require.ErrorIs(t, err, coordinator.ErrUnknownTreasuryValidationMode)
+
+	strict, err := coordinator.NewTreasuryValidation(coordinator.TreasuryValidationStrict, 0)
+	require.NoError(t, err)
+	relaxed, err := coordinator.NewTreasuryValidation(coordinator.TreasuryValidationRelaxed, 0)
+	require.NoError(t, err)
+
+	tolerated, err := strict.Validate(&coordinator.LatestTreasuryOutput{Amount: tokenSupply}, tokenSupply, tokenSupply)
+	require.NoError(t, err)
+	require.NoError(t, tolerated)
+
+	// an insufficient treasury is never tolerated
+	for _, validation := range []*coordinator.TreasuryValidation{strict, relaxed} {
+		_, err = validation.Validate(&coordinator.LatestTreasuryOutput{Amount: 100}, 101, tokenSupply)
+		require.ErrorIs(t, err, coordinator.ErrTreasuryInsufficient)
+	}
+
+	// a synthetic treasury that exceeds the token supply is only tolerated in relaxed mode
+	_, err = strict.Validate(&coordinator.LatestTreasuryOutput{Amount: tokenSupply + 1}, 10, tokenSupply)
+	require.ErrorIs(t, err, coordinator.ErrTreasuryInconsistent)
+
+	tolerated, err = relaxed.Validate(&coordinator.LatestTreasuryOutput{Amount: tokenSupply + 1}, 10, tokenSupply)
+	require.NoError(t, err)
+	require.ErrorIs(t, tolerated, coordinator.ErrTreasuryInconsistent)
+
+	// the maximum amount can be customized
+	custom, err := coordinator.NewTreasuryValidation(coordinator.TreasuryValidationStrict, 2*tokenSupply)
+	require.NoError(t, err)
+	tolerated, err = custom.Validate(&coordinator.LatestTreasuryOutput{Amount: tokenSupply + 1}, 10, tokenSupply)
+	require.NoError(t, err)
+	require.NoError(t, tolerated)
+}
@@ -1,10 +1,20 @@
 package daemon
 
 const (
-	PriorityDisconnectINX = iota
+	PriorityCloseStorage = iota
+	PriorityDisconnectINX
 	PriorityStopTangleListener
 	PriorityStopTreasuryListener
 	PriorityStopMigrator
+	PriorityStopMirror
+	PriorityCloseRemoteSigners
 	PriorityStopCoordinator
 	PriorityStopCoordinatorMilestoneTicker
+	PriorityStopScheduler
+	PriorityStopGRPCAPI
+	PriorityStopRestAPI
+	PriorityStopPrometheus
+	PriorityStopTelemetry
+	PriorityStopWebhooks
+	PriorityStopHandoff
 )
@@ -0,0 +1,19 @@
+package dashboard
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// page is a self-contained web page that shows the live status of the coordinator.
+// It uses relative URLs for the status API and the event stream, so it also works behind the API route of the node.
+//
+//go:embed dashboard.html
+var page string
+
+// Handler serves the dashboard page.
+func Handler(c echo.Context) error {
+	return c.HTML(http.StatusOK, page)
+}
@@ -0,0 +1,152 @@
+package dashboard_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/dashboard"
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+)
+
+// newTestServer serves the dashboard and the given event stream like the REST API does.
+func newTestServer(t *testing.T, eventStream *dashboard.EventStream) *httptest.Server {
+	t.Helper()
+
+	e := echo.New()
+	e.GET(api.RouteEvents, eventStream.Handler)
+	e.GET(api.RouteDashboard, dashboard.Handler)
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// connectEventStream connects a WebSocket client to the event stream and waits until it is registered.
+func connectEventStream(t *testing.T, server *httptest.Server, eventStream *dashboard.EventStream) *websocket.Conn {
+	t.Helper()
+
+	clientsCount := eventStream.ClientsCount()
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+api.RouteEvents, nil)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	t.Cleanup(func() { _ = conn.Close() })
+
+	require.Eventually(t, func() bool {
+		return eventStream.ClientsCount() == clientsCount+1
+	}, 5*time.Second, 5*time.Millisecond)
+
+	return conn
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) *api.Event {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	messageType, message, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, websocket.TextMessage, messageType)
+
+	event := &api.Event{}
+	require.NoError(t, json.Unmarshal(message, event))
+
+	return event
+}
+
+func TestHandler(t *testing.T) {
+	server := newTestServer(t, dashboard.NewEventStream(10, eventqueue.PolicyDisconnect, eventqueue.NewMetrics()))
+
+	resp, err := http.Get(server.URL + api.RouteDashboard)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, echo.MIMETextHTMLCharsetUTF8, resp.Header.Get(echo.HeaderContentType))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	page := string(body)
+	require.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+
+	// the page must work behind the API route of the node, so it only uses relative URLs
+	require.Contains(t, page, `fetch("status")`)
+	require.Contains(t, page, `new URL("events", window.location.href)`)
+	require.NotContains(t, page, `fetch("/`)
+	require.NotContains(t, page, `new URL("/`)
+}
+
+func TestEventStreamBroadcast(t *testing.T) {
+	eventStream := dashboard.NewEventStream(10, eventqueue.PolicyDisconnect, eventqueue.NewMetrics())
+	server := newTestServer(t, eventStream)
+
+	first := connectEventStream(t, server, eventStream)
+	second := connectEventStream(t, server, eventStream)
+
+	// all clients receive all events in order
+	for index := uint32(1); index <= 3; index++ {
+		require.NoError(t, eventStream.Broadcast(&api.Event{
+			Type:      api.EventTypeMilestoneIssued,
+			Timestamp: 1_700_000_000,
+			Data:      &api.MilestoneIssuedEvent{Index: index},
+		}))
+	}
+
+	for _, conn := range []*websocket.Conn{first, second} {
+		for index := 1; index <= 3; index++ {
+			event := readEvent(t, conn)
+			require.Equal(t, api.EventTypeMilestoneIssued, event.Type)
+			require.EqualValues(t, 1_700_000_000, event.Timestamp)
+			require.EqualValues(t, index, event.Data.(map[string]any)["index"])
+		}
+	}
+
+	// disconnected clients are unregistered
+	require.NoError(t, first.Close())
+	require.Eventually(t, func() bool {
+		return eventStream.ClientsCount() == 1
+	}, 5*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, eventStream.Broadcast(&api.Event{Type: api.EventTypeMilestoneIssued}))
+	require.Equal(t, api.EventTypeMilestoneIssued, readEvent(t, second).Type)
+
+	// events that can't be serialized are not sent
+	require.Error(t, eventStream.Broadcast(&api.Event{Type: api.EventTypeMilestoneIssued, Data: make(chan int)}))
+}
+
+func TestEventStreamClose(t *testing.T) {
+	eventStream := dashboard.NewEventStream(10, eventqueue.PolicyDisconnect, eventqueue.NewMetrics())
+	server := newTestServer(t, eventStream)
+
+	conn := connectEventStream(t, server, eventStream)
+
+	// the clients are disconnected with a normal closure
+	eventStream.Close()
+	require.Zero(t, eventStream.ClientsCount())
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, _, err := conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "unexpected error: %v", err)
+}
+
+func TestEventStreamRequiresWebSocket(t *testing.T) {
+	eventStream := dashboard.NewEventStream(10, eventqueue.PolicyDisconnect, eventqueue.NewMetrics())
+	server := newTestServer(t, eventStream)
+
+	resp, err := http.Get(server.URL + api.RouteEvents)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Zero(t, eventStream.ClientsCount())
+}
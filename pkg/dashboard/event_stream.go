@@ -0,0 +1,161 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+)
+
+const (
+	// the maximum duration to write an event to a subscriber.
+	eventClientWriteTimeout = 5 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// eventClient is a subscriber of the event stream.
+type eventClient struct {
+	conn  *websocket.Conn
+	queue *eventqueue.Queue[[]byte]
+}
+
+// EventStream sends the events of the coordinator to all connected WebSocket clients.
+// Every client has its own bounded queue, so that a slow client never delays the coordinator or the other clients.
+type EventStream struct {
+	lock    syncutils.RWMutex
+	clients map[*eventClient]struct{}
+
+	queueSize int
+	policy    eventqueue.Policy
+	metrics   *eventqueue.Metrics
+}
+
+// NewEventStream creates a new EventStream that queues up to queueSize events per client.
+func NewEventStream(queueSize int, policy eventqueue.Policy, metrics *eventqueue.Metrics) *EventStream {
+	return &EventStream{
+		clients:   make(map[*eventClient]struct{}),
+		queueSize: queueSize,
+		policy:    policy,
+		metrics:   metrics,
+	}
+}
+
+func (s *EventStream) register(client *eventClient) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.clients[client] = struct{}{}
+}
+
+func (s *EventStream) unregister(client *eventClient) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.clients[client]; !exists {
+		return
+	}
+
+	delete(s.clients, client)
+	client.queue.Close()
+}
+
+// ClientsCount returns the amount of connected clients.
+func (s *EventStream) ClientsCount() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.clients)
+}
+
+// Broadcast queues the event for all clients. Depending on the overflow policy, events are dropped for clients that can't keep up,
+// or these clients are disconnected.
+func (s *EventStream) Broadcast(apiEvent *api.Event) error {
+	event, err := json.Marshal(apiEvent)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %w", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for client := range s.clients {
+		if !client.queue.Push(event) && client.queue.Err() != nil {
+			// the client was disconnected by its queue
+			delete(s.clients, client)
+		}
+	}
+
+	return nil
+}
+
+// Close disconnects all clients.
+func (s *EventStream) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for client := range s.clients {
+		delete(s.clients, client)
+		client.queue.Close()
+	}
+}
+
+// Handler upgrades the request to a WebSocket connection and sends the events until the client disconnects.
+func (s *EventStream) Handler(c echo.Context) error {
+	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// the upgrader already replied with an error
+		return nil
+	}
+
+	client := &eventClient{
+		conn:  conn,
+		queue: eventqueue.New[[]byte](s.queueSize, s.policy, s.metrics, eventqueue.ListenerWebSocket),
+	}
+	s.register(client)
+
+	go func() {
+		defer conn.Close()
+
+		for {
+			// the queue is closed when the client gets unregistered
+			event, err := client.queue.Pop(context.Background())
+			if err != nil {
+				closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+				if errors.Is(err, eventqueue.ErrListenerTooSlow) {
+					closeMessage = websocket.FormatCloseMessage(websocket.CloseTryAgainLater, err.Error())
+				}
+				_ = conn.WriteMessage(websocket.CloseMessage, closeMessage)
+
+				return
+			}
+
+			if err := conn.SetWriteDeadline(time.Now().Add(eventClientWriteTimeout)); err != nil {
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, event); err != nil {
+				return
+			}
+		}
+	}()
+
+	// the messages of the client are discarded, reading is only needed to detect closed connections
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			s.unregister(client)
+
+			return nil
+		}
+	}
+}
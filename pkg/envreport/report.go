@@ -0,0 +1,246 @@
+package envreport
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/stateversion"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// RedactedValue replaces sensitive values in the report.
+	RedactedValue = "<redacted>"
+
+	// KeyPurposeMilestone is the purpose of the keys that sign milestones.
+	KeyPurposeMilestone = "milestone"
+	// KeyPurposeTreasury is the purpose of the key that additionally signs receipts.
+	KeyPurposeTreasury = "treasury"
+	// KeyPurposeIdentity is the key that signs the operational artifacts of the coordinator instance.
+	KeyPurposeIdentity = "identity"
+
+	// the amount of bytes of the hash of a public key that are used as its fingerprint.
+	fingerprintLength = 8
+)
+
+var (
+	// the parts of configuration keys that mark their values as sensitive.
+	sensitiveKeyParts = []string{"password", "secret", "token", "apikey", "privatekey", "prvkey", "credential"}
+)
+
+// Report collects information about the environment the application was started in.
+// The components add their keys and state files while they are initialized.
+type Report struct {
+	lock   syncutils.RWMutex
+	report *api.EnvironmentReport
+}
+
+// New creates a new Report with the given effective configuration, sensitive values of the configuration are redacted.
+func New(name string, version string, config map[string]any) *Report {
+	return &Report{
+		report: &api.EnvironmentReport{
+			Name:           name,
+			Version:        version,
+			GoVersion:      runtime.Version(),
+			Platform:       fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+			StartTimestamp: time.Now().Unix(),
+			Config:         Redact(config),
+			Keys:           make([]*api.EnvironmentKey, 0),
+			StateFiles:     make([]*api.EnvironmentStateFile, 0),
+			Dependencies:   Dependencies(),
+		},
+	}
+}
+
+// Fingerprint returns the fingerprint of a public key, which is the beginning of its SHA-256 hash.
+func Fingerprint(publicKey []byte) string {
+	hash := sha256.Sum256(publicKey)
+
+	return iotago.EncodeHex(hash[:fingerprintLength])
+}
+
+// AddKey adds a public key that is valid between the given milestone indexes (0 = no limit) to the report.
+func (r *Report) AddKey(purpose string, publicKey []byte, startIndex iotago.MilestoneIndex, endIndex iotago.MilestoneIndex) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.report.Keys = append(r.report.Keys, &api.EnvironmentKey{
+		Purpose:     purpose,
+		PublicKey:   iotago.EncodeHex(publicKey),
+		Fingerprint: Fingerprint(publicKey),
+		StartIndex:  startIndex,
+		EndIndex:    endIndex,
+	})
+}
+
+// AddStateFile adds a state file to the report.
+// The version the file is stored with is read immediately, so it must be added before the state is loaded and upgraded.
+func (r *Report) AddStateFile(name string, filePath string, supportedVersion uint32) {
+	stateFile := &api.EnvironmentStateFile{
+		Name:             name,
+		Path:             filePath,
+		SupportedVersion: supportedVersion,
+	}
+
+	storedVersion, err := readStoredVersion(filePath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		stateFile.Exists = true
+		stateFile.Error = err.Error()
+	default:
+		stateFile.Exists = true
+		stateFile.StoredVersion = storedVersion
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.report.StateFiles = append(r.report.StateFiles, stateFile)
+}
+
+// readStoredVersion reads the schema version of a JSON state file.
+func readStoredVersion(filePath string) (uint32, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, fmt.Errorf("unable to parse state file: %w", err)
+	}
+
+	storedVersion := uint32(stateversion.UnversionedVersion)
+	if rawVersion, exists := fields[stateversion.VersionFieldName]; exists {
+		if err := json.Unmarshal(rawVersion, &storedVersion); err != nil {
+			return 0, fmt.Errorf("unable to parse state file version: %w", err)
+		}
+	}
+
+	return storedVersion, nil
+}
+
+// Snapshot returns a copy of the report.
+func (r *Report) Snapshot() *api.EnvironmentReport {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	snapshot := *r.report
+	snapshot.Keys = append(make([]*api.EnvironmentKey, 0, len(r.report.Keys)), r.report.Keys...)
+	snapshot.StateFiles = append(make([]*api.EnvironmentStateFile, 0, len(r.report.StateFiles)), r.report.StateFiles...)
+
+	return &snapshot
+}
+
+// Dependencies returns the modules the application was built with.
+func Dependencies() []*api.EnvironmentDependency {
+	dependencies := make([]*api.EnvironmentDependency, 0)
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return dependencies
+	}
+
+	for _, module := range buildInfo.Deps {
+		dependency := &api.EnvironmentDependency{
+			Path:    module.Path,
+			Version: module.Version,
+		}
+		if module.Replace != nil {
+			dependency.Replace = fmt.Sprintf("%s %s", module.Replace.Path, module.Replace.Version)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies
+}
+
+// isSensitiveKey returns whether the values of the configuration key are sensitive.
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact returns a copy of the configuration with redacted sensitive values.
+// Values of keys that contain a sensitive part like "password" are replaced, and credentials are removed from URLs.
+func Redact(config map[string]any) map[string]any {
+	redacted := make(map[string]any, len(config))
+	for key, value := range config {
+		if isSensitiveKey(key) {
+			if str, ok := value.(string); ok && str == "" {
+				// empty values don't need to be hidden, they show that no value was configured
+				redacted[key] = value
+
+				continue
+			}
+			redacted[key] = RedactedValue
+
+			continue
+		}
+		redacted[key] = redactValue(value)
+	}
+
+	return redacted
+}
+
+// redactValue redacts the sensitive values contained in a configuration value.
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return Redact(v)
+
+	case []any:
+		values := make([]any, len(v))
+		for i := range v {
+			values[i] = redactValue(v[i])
+		}
+
+		return values
+
+	case []map[string]any:
+		values := make([]map[string]any, len(v))
+		for i := range v {
+			values[i] = Redact(v[i])
+		}
+
+		return values
+
+	case string:
+		return redactURL(v)
+
+	default:
+		return value
+	}
+}
+
+// redactURL removes the password from URLs that contain credentials.
+func redactURL(value string) string {
+	if !strings.Contains(value, "@") || !strings.Contains(value, "://") {
+		return value
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.User == nil {
+		return value
+	}
+
+	parsed.User = url.UserPassword(parsed.User.Username(), RedactedValue)
+
+	return parsed.String()
+}
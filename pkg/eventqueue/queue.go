@@ -0,0 +1,265 @@
+package eventqueue
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+)
+
+// Policy defines what happens if an event is pushed to a full queue.
+type Policy string
+
+const (
+	// PolicyDropNewest drops the pushed event, the queued events are kept.
+	PolicyDropNewest Policy = "dropNewest"
+	// PolicyDropOldest drops the oldest queued event to make room for the pushed event.
+	PolicyDropOldest Policy = "dropOldest"
+	// PolicyDisconnect closes the queue, so that the listener is disconnected.
+	PolicyDisconnect Policy = "disconnect"
+)
+
+const (
+	// ListenerWebSocket are the clients of the event stream of the REST API.
+	ListenerWebSocket = "websocket"
+	// ListenerJournal is the writer of the event journal.
+	ListenerJournal = "journal"
+	// ListenerGRPC are the subscribers of the pending receipts of the gRPC API.
+	ListenerGRPC = "grpc"
+	// ListenerWebhook is the worker that delivers the migration notifications to the webhooks.
+	ListenerWebhook = "webhook"
+)
+
+var (
+	// ErrQueueClosed is returned when the queue was closed.
+	ErrQueueClosed = errors.New("event queue closed")
+	// ErrListenerTooSlow is returned when the queue was closed, because the listener didn't keep up with the events.
+	ErrListenerTooSlow = errors.New("listener did not keep up with the events")
+)
+
+// Metrics counts the events that were dropped by the queues of the event listeners and the listeners that were disconnected,
+// by the kind of the listener. A nil Metrics counts nothing.
+type Metrics struct {
+	lock         syncutils.RWMutex
+	dropped      map[string]uint64
+	disconnected map[string]uint64
+}
+
+// NewMetrics creates a new Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		dropped:      make(map[string]uint64),
+		disconnected: make(map[string]uint64),
+	}
+}
+
+// Dropped returns the amount of events that were dropped by the queues of the given kind of listener.
+func (m *Metrics) Dropped(listener string) uint64 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.dropped[listener]
+}
+
+// Disconnected returns the amount of listeners of the given kind that were disconnected, because they didn't keep up.
+func (m *Metrics) Disconnected(listener string) uint64 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.disconnected[listener]
+}
+
+func (m *Metrics) addDropped(listener string) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.dropped[listener]++
+}
+
+func (m *Metrics) addDisconnected(listener string) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.disconnected[listener]++
+}
+
+// Queue is a bounded queue of the events of a single listener.
+// Pushing never blocks, so that a slow listener never delays the issuance of milestones.
+// If the queue is full, the policy decides whether an event is dropped or the listener is disconnected.
+type Queue[T any] struct {
+	lock syncutils.Mutex
+
+	size     int
+	policy   Policy
+	metrics  *Metrics
+	listener string
+
+	events []T
+	// signals the listener that events were pushed or the queue was closed.
+	notify chan struct{}
+	// the reason the queue was closed (nil = open).
+	err error
+	// the amount of events that were dropped.
+	dropped uint64
+}
+
+// New creates a new Queue of the given size for a listener of the given kind.
+// The dropped events and disconnects are counted in the optional metrics.
+func New[T any](size int, policy Policy, metrics *Metrics, listener string) *Queue[T] {
+	if size < 1 {
+		size = 1
+	}
+
+	return &Queue[T]{
+		size:     size,
+		policy:   policy,
+		metrics:  metrics,
+		listener: listener,
+		events:   make([]T, 0, size),
+		notify:   make(chan struct{}, 1),
+	}
+}
+
+// Push adds the event to the queue, it never blocks.
+// It returns false if the event was not queued, because it was dropped or the queue is closed.
+func (q *Queue[T]) Push(event T) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.err != nil {
+		return false
+	}
+
+	if len(q.events) >= q.size {
+		switch q.policy {
+		case PolicyDropOldest:
+			var empty T
+			q.events[0] = empty
+			q.events = q.events[1:]
+			q.dropped++
+			q.metrics.addDropped(q.listener)
+
+		case PolicyDisconnect:
+			q.metrics.addDisconnected(q.listener)
+			q.close(ErrListenerTooSlow)
+
+			return false
+
+		default:
+			q.dropped++
+			q.metrics.addDropped(q.listener)
+
+			return false
+		}
+	}
+
+	q.events = append(q.events, event)
+	q.signal()
+
+	return true
+}
+
+// Pop removes the oldest event from the queue, it blocks until an event was pushed.
+// It returns ErrQueueClosed or ErrListenerTooSlow if the queue was closed, or the error of the context.
+func (q *Queue[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		q.lock.Lock()
+		if q.err != nil {
+			err := q.err
+			q.lock.Unlock()
+
+			var empty T
+
+			return empty, err
+		}
+
+		if len(q.events) > 0 {
+			event := q.events[0]
+
+			var empty T
+			q.events[0] = empty
+			q.events = q.events[1:]
+			q.lock.Unlock()
+
+			return event, nil
+		}
+		q.lock.Unlock()
+
+		select {
+		case <-q.notify:
+		case <-ctx.Done():
+			var empty T
+
+			return empty, ctx.Err()
+		}
+	}
+}
+
+// Flush removes and returns all queued events, e.g. to write them before shutting down.
+func (q *Queue[T]) Flush() []T {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	events := q.events
+	q.events = make([]T, 0, q.size)
+
+	return events
+}
+
+// Len returns the amount of queued events.
+func (q *Queue[T]) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return len(q.events)
+}
+
+// Dropped returns the amount of events that were dropped by the queue.
+func (q *Queue[T]) Dropped() uint64 {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.dropped
+}
+
+// Err returns the reason the queue was closed, or nil if it is still open.
+func (q *Queue[T]) Err() error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.err
+}
+
+// Close closes the queue, the queued events are discarded.
+func (q *Queue[T]) Close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.err == nil {
+		q.close(ErrQueueClosed)
+	}
+}
+
+// close closes the queue for the given reason, the caller must hold the lock.
+func (q *Queue[T]) close(err error) {
+	q.err = err
+	q.events = nil
+	q.signal()
+}
+
+// signal wakes up the listener, the caller must hold the lock.
+func (q *Queue[T]) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,103 @@
+package eventqueue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+)
+
+func popAll(t *testing.T, queue *eventqueue.Queue[int]) []int {
+	var events []int
+	for queue.Len() > 0 {
+		event, err := queue.Pop(context.Background())
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func TestQueueDropNewest(t *testing.T) {
+	metrics := eventqueue.NewMetrics()
+	queue := eventqueue.New[int](2, eventqueue.PolicyDropNewest, metrics, eventqueue.ListenerJournal)
+
+	require.True(t, queue.Push(1))
+	require.True(t, queue.Push(2))
+	require.False(t, queue.Push(3))
+	require.NoError(t, queue.Err())
+
+	require.Equal(t, []int{1, 2}, popAll(t, queue))
+	require.EqualValues(t, 1, queue.Dropped())
+	require.EqualValues(t, 1, metrics.Dropped(eventqueue.ListenerJournal))
+	require.Zero(t, metrics.Dropped(eventqueue.ListenerWebSocket))
+}
+
+func TestQueueDropOldest(t *testing.T) {
+	metrics := eventqueue.NewMetrics()
+	queue := eventqueue.New[int](2, eventqueue.PolicyDropOldest, metrics, eventqueue.ListenerWebSocket)
+
+	require.True(t, queue.Push(1))
+	require.True(t, queue.Push(2))
+	require.True(t, queue.Push(3))
+	require.True(t, queue.Push(4))
+
+	require.Equal(t, []int{3, 4}, popAll(t, queue))
+	require.EqualValues(t, 2, queue.Dropped())
+	require.EqualValues(t, 2, metrics.Dropped(eventqueue.ListenerWebSocket))
+}
+
+func TestQueueDisconnect(t *testing.T) {
+	metrics := eventqueue.NewMetrics()
+	queue := eventqueue.New[int](1, eventqueue.PolicyDisconnect, metrics, eventqueue.ListenerGRPC)
+
+	require.True(t, queue.Push(1))
+	require.False(t, queue.Push(2))
+	require.ErrorIs(t, queue.Err(), eventqueue.ErrListenerTooSlow)
+	require.EqualValues(t, 1, metrics.Disconnected(eventqueue.ListenerGRPC))
+
+	// the queued events are discarded, the listener only learns that it was disconnected
+	_, err := queue.Pop(context.Background())
+	require.ErrorIs(t, err, eventqueue.ErrListenerTooSlow)
+	require.False(t, queue.Push(3))
+}
+
+func TestQueuePop(t *testing.T) {
+	queue := eventqueue.New[int](10, eventqueue.PolicyDropNewest, nil, eventqueue.ListenerJournal)
+
+	// pop blocks until an event was pushed
+	events := make(chan int, 1)
+	go func() {
+		if event, err := queue.Pop(context.Background()); err == nil {
+			events <- event
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, queue.Push(1))
+
+	select {
+	case event := <-events:
+		require.Equal(t, 1, event)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "event was not popped")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := queue.Pop(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// the remaining events can be flushed, e.g. on shutdown
+	require.True(t, queue.Push(2))
+	require.True(t, queue.Push(3))
+	require.Equal(t, []int{2, 3}, queue.Flush())
+	require.Zero(t, queue.Len())
+
+	queue.Close()
+	_, err = queue.Pop(context.Background())
+	require.ErrorIs(t, err, eventqueue.ErrQueueClosed)
+	require.False(t, queue.Push(4))
+}
@@ -0,0 +1,163 @@
+package fileperm
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/logger"
+)
+
+const (
+	// KindState marks files that contain the state of the coordinator.
+	KindState = "state"
+	// KindKey marks files that contain private keys.
+	KindKey = "key"
+
+	// the permission bits of other users.
+	worldPermissions os.FileMode = 0o007
+)
+
+var (
+	// ErrInvalidMode is returned when a configured mode is not a valid octal permission.
+	ErrInvalidMode = errors.New("invalid file mode")
+	// ErrWorldAccessible is returned when a file can be accessed by all users of the host.
+	ErrWorldAccessible = errors.New("file is accessible by other users")
+	// ErrPermissionsTooOpen is returned when a file has more permissions than allowed.
+	ErrPermissionsTooOpen = errors.New("file permissions are too open")
+	// ErrWrongOwner is returned when a file is not owned by the user the process runs as.
+	ErrWrongOwner = errors.New("file is not owned by the user the process runs as")
+)
+
+// ParseMode parses an octal file mode like "0660".
+func ParseMode(mode string) (os.FileMode, error) {
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %v", ErrInvalidMode, mode, err)
+	}
+
+	if os.FileMode(value)&^os.ModePerm != 0 {
+		return 0, fmt.Errorf("%w: %s contains more than permission bits", ErrInvalidMode, mode)
+	}
+
+	return os.FileMode(value), nil
+}
+
+// Policy defines the permissions and the ownership the state and key files must have.
+type Policy struct {
+	// the maximum permissions of state files.
+	StateMode os.FileMode
+	// the maximum permissions of key files.
+	KeyMode os.FileMode
+	// whether additional permissions are removed if it is safe.
+	Fix bool
+	// whether the files must be owned by the user the process runs as.
+	CheckOwner bool
+}
+
+// mode returns the maximum permissions of files of the given kind.
+func (p *Policy) mode(kind string) os.FileMode {
+	if kind == KindKey {
+		return p.KeyMode
+	}
+
+	return p.StateMode
+}
+
+// Enforcer verifies the permissions and the ownership of files according to a policy.
+type Enforcer struct {
+	*logger.WrappedLogger
+
+	policy *Policy
+}
+
+// NewEnforcer creates a new Enforcer. If policy is nil, no files are checked.
+func NewEnforcer(log *logger.Logger, policy *Policy) *Enforcer {
+	return &Enforcer{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		policy:        policy,
+	}
+}
+
+// Enforce verifies the permissions and the ownership of the given files of the given kind.
+// Files that don't exist yet and empty paths are ignored, new files are created with restricted permissions.
+// Additional permissions are removed if fixing is enabled and it is safe, which means the file is a regular file
+// owned by the user the process runs as and, in case of a key file, it was not accessible by other users.
+// A key file that was accessible by other users may already be compromised, so it is never fixed.
+func (e *Enforcer) Enforce(kind string, filePaths ...string) error {
+	if e.policy == nil {
+		return nil
+	}
+
+	for _, filePath := range filePaths {
+		if filePath == "" {
+			continue
+		}
+
+		if err := e.enforce(kind, filePath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enforce verifies the permissions and the ownership of a single file.
+func (e *Enforcer) enforce(kind string, filePath string) error {
+	info, err := os.Lstat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("unable to check permissions of %s file %s: %w", kind, filePath, err)
+	}
+
+	// symbolic links are checked at their target, but never fixed, because the target may be shared with other files
+	symlink := info.Mode()&os.ModeSymlink != 0
+	if symlink {
+		if info, err = os.Stat(filePath); err != nil {
+			return fmt.Errorf("unable to check permissions of %s file %s: %w", kind, filePath, err)
+		}
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	uid, hasOwner := fileOwner(info)
+	if !hasOwner {
+		// the platform has no unix permissions
+		return nil
+	}
+
+	ownedByProcess := uid == os.Getuid()
+	if e.policy.CheckOwner && !ownedByProcess {
+		return fmt.Errorf("%w: %s file %s is owned by uid %d, the process runs as uid %d", ErrWrongOwner, kind, filePath, uid, os.Getuid())
+	}
+
+	perm := info.Mode().Perm()
+	allowed := e.policy.mode(kind)
+	if perm&^allowed == 0 {
+		return nil
+	}
+
+	worldAccessible := perm&worldPermissions != 0
+	safe := e.policy.Fix && ownedByProcess && !symlink && !(kind == KindKey && worldAccessible)
+	if !safe {
+		if worldAccessible {
+			return fmt.Errorf("%w: %s file %s has mode %#o, allowed %#o", ErrWorldAccessible, kind, filePath, perm, allowed)
+		}
+
+		return fmt.Errorf("%w: %s file %s has mode %#o, allowed %#o", ErrPermissionsTooOpen, kind, filePath, perm, allowed)
+	}
+
+	if err := os.Chmod(filePath, perm&allowed); err != nil {
+		return fmt.Errorf("unable to fix permissions of %s file %s: %w", kind, filePath, err)
+	}
+	e.LogWarnf("fixed permissions of %s file %s from %#o to %#o", kind, filePath, perm, perm&allowed)
+
+	return nil
+}
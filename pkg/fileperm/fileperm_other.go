@@ -0,0 +1,18 @@
+//go:build !unix
+
+package fileperm
+
+import (
+	"os"
+)
+
+// SetUmask is not supported on this platform, the permissions of new files are not restricted.
+// It returns the given umask.
+func SetUmask(mask os.FileMode) os.FileMode {
+	return mask
+}
+
+// fileOwner is not supported on this platform, the owner of files is not checked.
+func fileOwner(_ os.FileInfo) (int, bool) {
+	return 0, false
+}
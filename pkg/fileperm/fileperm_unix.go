@@ -0,0 +1,24 @@
+//go:build unix
+
+package fileperm
+
+import (
+	"os"
+	"syscall"
+)
+
+// SetUmask sets the umask of the process, which restricts the permissions of all files created afterwards.
+// It returns the previous umask.
+func SetUmask(mask os.FileMode) os.FileMode {
+	return os.FileMode(syscall.Umask(int(mask)))
+}
+
+// fileOwner returns the uid of the owner of the file.
+func fileOwner(info os.FileInfo) (int, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+
+	return int(stat.Uid), true
+}
@@ -0,0 +1,84 @@
+//go:build unix
+
+package fileperm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-coordinator/pkg/fileperm"
+)
+
+func writeFile(t *testing.T, filePath string, mode os.FileMode) {
+	require.NoError(t, os.WriteFile(filePath, []byte("{}"), mode))
+	// the mode of WriteFile is restricted by the umask
+	require.NoError(t, os.Chmod(filePath, mode))
+}
+
+func requireMode(t *testing.T, filePath string, mode os.FileMode) {
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	require.Equal(t, mode, info.Mode().Perm())
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := fileperm.ParseMode("0660")
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o660), mode)
+
+	_, err = fileperm.ParseMode("0669")
+	require.ErrorIs(t, err, fileperm.ErrInvalidMode)
+	_, err = fileperm.ParseMode("10660")
+	require.ErrorIs(t, err, fileperm.ErrInvalidMode)
+}
+
+func TestEnforce(t *testing.T) {
+	dir := t.TempDir()
+	policy := &fileperm.Policy{
+		StateMode:  0o660,
+		KeyMode:    0o600,
+		Fix:        true,
+		CheckOwner: true,
+	}
+	enforcer := fileperm.NewEnforcer(logger.NewNopLogger(), policy)
+
+	// missing files and empty paths are ignored
+	require.NoError(t, enforcer.Enforce(fileperm.KindState, "", filepath.Join(dir, "missing.state")))
+
+	// world-readable state files are fixed
+	statePath := filepath.Join(dir, "coordinator.state")
+	writeFile(t, statePath, 0o644)
+	require.NoError(t, enforcer.Enforce(fileperm.KindState, statePath))
+	requireMode(t, statePath, 0o640)
+
+	// key files that were accessible by other users are never fixed
+	keyPath := filepath.Join(dir, "client.key")
+	writeFile(t, keyPath, 0o644)
+	require.ErrorIs(t, enforcer.Enforce(fileperm.KindKey, keyPath), fileperm.ErrWorldAccessible)
+	requireMode(t, keyPath, 0o644)
+
+	// but group permissions are removed
+	writeFile(t, keyPath, 0o640)
+	require.NoError(t, enforcer.Enforce(fileperm.KindKey, keyPath))
+	requireMode(t, keyPath, 0o600)
+
+	// symbolic links are verified at their target, but not fixed
+	linkPath := filepath.Join(dir, "linked.state")
+	require.NoError(t, os.Symlink(statePath, linkPath))
+	require.NoError(t, enforcer.Enforce(fileperm.KindState, linkPath))
+	writeFile(t, statePath, 0o664)
+	require.ErrorIs(t, enforcer.Enforce(fileperm.KindState, linkPath), fileperm.ErrWorldAccessible)
+
+	// without fixing, too open permissions are refused
+	policy.Fix = false
+	writeFile(t, statePath, 0o670)
+	require.ErrorIs(t, enforcer.Enforce(fileperm.KindState, statePath), fileperm.ErrPermissionsTooOpen)
+	requireMode(t, statePath, 0o670)
+
+	// a disabled policy doesn't check anything
+	require.NoError(t, fileperm.NewEnforcer(logger.NewNopLogger(), nil).Enforce(fileperm.KindState, statePath))
+}
@@ -0,0 +1,134 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: receipts.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ReceiptStreamClient is the client API for ReceiptStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ReceiptStreamClient interface {
+	// ListenToPendingReceipts streams every new receipt as soon as it was created, before the milestone containing it is issued.
+	ListenToPendingReceipts(ctx context.Context, in *ListenToPendingReceiptsRequest, opts ...grpc.CallOption) (ReceiptStream_ListenToPendingReceiptsClient, error)
+}
+
+type receiptStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReceiptStreamClient(cc grpc.ClientConnInterface) ReceiptStreamClient {
+	return &receiptStreamClient{cc}
+}
+
+func (c *receiptStreamClient) ListenToPendingReceipts(ctx context.Context, in *ListenToPendingReceiptsRequest, opts ...grpc.CallOption) (ReceiptStream_ListenToPendingReceiptsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ReceiptStream_ServiceDesc.Streams[0], "/grpcapi.ReceiptStream/ListenToPendingReceipts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &receiptStreamListenToPendingReceiptsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReceiptStream_ListenToPendingReceiptsClient interface {
+	Recv() (*PendingReceipt, error)
+	grpc.ClientStream
+}
+
+type receiptStreamListenToPendingReceiptsClient struct {
+	grpc.ClientStream
+}
+
+func (x *receiptStreamListenToPendingReceiptsClient) Recv() (*PendingReceipt, error) {
+	m := new(PendingReceipt)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReceiptStreamServer is the server API for ReceiptStream service.
+// All implementations must embed UnimplementedReceiptStreamServer
+// for forward compatibility
+type ReceiptStreamServer interface {
+	// ListenToPendingReceipts streams every new receipt as soon as it was created, before the milestone containing it is issued.
+	ListenToPendingReceipts(*ListenToPendingReceiptsRequest, ReceiptStream_ListenToPendingReceiptsServer) error
+	mustEmbedUnimplementedReceiptStreamServer()
+}
+
+// UnimplementedReceiptStreamServer must be embedded to have forward compatible implementations.
+type UnimplementedReceiptStreamServer struct {
+}
+
+func (UnimplementedReceiptStreamServer) ListenToPendingReceipts(*ListenToPendingReceiptsRequest, ReceiptStream_ListenToPendingReceiptsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListenToPendingReceipts not implemented")
+}
+func (UnimplementedReceiptStreamServer) mustEmbedUnimplementedReceiptStreamServer() {}
+
+// UnsafeReceiptStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReceiptStreamServer will
+// result in compilation errors.
+type UnsafeReceiptStreamServer interface {
+	mustEmbedUnimplementedReceiptStreamServer()
+}
+
+func RegisterReceiptStreamServer(s grpc.ServiceRegistrar, srv ReceiptStreamServer) {
+	s.RegisterService(&ReceiptStream_ServiceDesc, srv)
+}
+
+func _ReceiptStream_ListenToPendingReceipts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListenToPendingReceiptsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReceiptStreamServer).ListenToPendingReceipts(m, &receiptStreamListenToPendingReceiptsServer{stream})
+}
+
+type ReceiptStream_ListenToPendingReceiptsServer interface {
+	Send(*PendingReceipt) error
+	grpc.ServerStream
+}
+
+type receiptStreamListenToPendingReceiptsServer struct {
+	grpc.ServerStream
+}
+
+func (x *receiptStreamListenToPendingReceiptsServer) Send(m *PendingReceipt) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ReceiptStream_ServiceDesc is the grpc.ServiceDesc for ReceiptStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReceiptStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grpcapi.ReceiptStream",
+	HandlerType: (*ReceiptStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListenToPendingReceipts",
+			Handler:       _ReceiptStream_ListenToPendingReceipts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "receipts.proto",
+}
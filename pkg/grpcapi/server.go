@@ -0,0 +1,211 @@
+package grpcapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// NewPendingReceipt converts a receipt that will be included in the milestone with the given index into its gRPC message.
+func NewPendingReceipt(index iotago.MilestoneIndex, timestamp time.Time, receipt *iotago.ReceiptMilestoneOpt, protoParams *iotago.ProtocolParameters) (*PendingReceipt, error) {
+	receiptBytes, err := receipt.Serialize(serializer.DeSeriModeNoValidation, protoParams)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize receipt: %w", err)
+	}
+
+	funds := make([]*MigratedFundsEntry, 0, len(receipt.Funds))
+	for _, entry := range receipt.Funds {
+		address, ok := entry.Address.(*iotago.Ed25519Address)
+		if !ok {
+			return nil, fmt.Errorf("unsupported address type %T of migration %s", entry.Address, iotago.EncodeHex(entry.TailTransactionHash[:]))
+		}
+
+		funds = append(funds, &MigratedFundsEntry{
+			TailTransactionHash: append([]byte{}, entry.TailTransactionHash[:]...),
+			Address:             append([]byte{}, address[:]...),
+			Deposit:             entry.Deposit,
+		})
+	}
+
+	pendingReceipt := &PendingReceipt{
+		MilestoneIndex: index,
+		Timestamp:      timestamp.UnixMilli(),
+		MigratedAt:     receipt.MigratedAt,
+		Final:          receipt.Final,
+		Funds:          funds,
+		Sum:            receipt.Sum(),
+		Receipt:        receiptBytes,
+	}
+
+	if receipt.Transaction != nil {
+		pendingReceipt.TreasuryInputMilestoneId = append([]byte{}, receipt.Transaction.Input[:]...)
+		pendingReceipt.TreasuryOutputAmount = receipt.Transaction.Output.Amount
+	}
+
+	return pendingReceipt, nil
+}
+
+// matches returns whether the receipt contains a migration with at least the given deposit.
+func (r *PendingReceipt) matches(minDeposit uint64) bool {
+	if minDeposit == 0 {
+		return true
+	}
+
+	for _, entry := range r.GetFunds() {
+		if entry.GetDeposit() >= minDeposit {
+			return true
+		}
+	}
+
+	return false
+}
+
+// subscriber is a client that listens to the pending receipts.
+type subscriber struct {
+	// the minimum deposit of a migration in the receipts the subscriber is interested in.
+	minDeposit uint64
+	// the receipts that were not sent to the subscriber yet.
+	receipts *eventqueue.Queue[*PendingReceipt]
+}
+
+// PendingReceiptsServer implements the ReceiptStream service.
+// Every pending receipt is pushed to the bounded queues of all subscribers, by default subscribers that can't keep up are disconnected,
+// so that a slow subscriber never delays the issuance of milestones.
+type PendingReceiptsServer struct {
+	UnimplementedReceiptStreamServer
+
+	// the amount of receipts that are buffered for every subscriber.
+	bufferSize int
+	// what happens if the buffer of a subscriber is full.
+	overflowPolicy eventqueue.Policy
+	// the metrics the dropped receipts and disconnected subscribers are counted in.
+	metrics *eventqueue.Metrics
+
+	lock        syncutils.Mutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+// WithOverflowPolicy sets what happens if the buffer of a subscriber is full.
+func WithOverflowPolicy(policy eventqueue.Policy) options.Option[PendingReceiptsServer] {
+	return func(s *PendingReceiptsServer) {
+		s.overflowPolicy = policy
+	}
+}
+
+// WithQueueMetrics sets the metrics the dropped receipts and disconnected subscribers are counted in.
+func WithQueueMetrics(metrics *eventqueue.Metrics) options.Option[PendingReceiptsServer] {
+	return func(s *PendingReceiptsServer) {
+		s.metrics = metrics
+	}
+}
+
+// NewPendingReceiptsServer creates a new PendingReceiptsServer.
+func NewPendingReceiptsServer(bufferSize int, opts ...options.Option[PendingReceiptsServer]) *PendingReceiptsServer {
+	return options.Apply(&PendingReceiptsServer{
+		bufferSize:     bufferSize,
+		overflowPolicy: eventqueue.PolicyDisconnect,
+		subscribers:    make(map[*subscriber]struct{}),
+	}, opts)
+}
+
+// SubscribersCount returns the amount of connected subscribers.
+func (s *PendingReceiptsServer) SubscribersCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.subscribers)
+}
+
+// Publish pushes the pending receipt to all subscribers, it never blocks.
+func (s *PendingReceiptsServer) Publish(receipt *PendingReceipt) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for sub := range s.subscribers {
+		if !receipt.matches(sub.minDeposit) {
+			continue
+		}
+
+		if !sub.receipts.Push(receipt) && sub.receipts.Err() != nil {
+			// the subscriber was disconnected by its queue
+			delete(s.subscribers, sub)
+		}
+	}
+}
+
+// Close disconnects all subscribers and rejects new ones.
+func (s *PendingReceiptsServer) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		sub.receipts.Close()
+	}
+}
+
+// subscribe adds a new subscriber.
+func (s *PendingReceiptsServer) subscribe(minDeposit uint64) (*subscriber, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+
+	sub := &subscriber{
+		minDeposit: minDeposit,
+		receipts:   eventqueue.New[*PendingReceipt](s.bufferSize, s.overflowPolicy, s.metrics, eventqueue.ListenerGRPC),
+	}
+	s.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// unsubscribe removes the subscriber, if it was not disconnected by the server yet.
+func (s *PendingReceiptsServer) unsubscribe(sub *subscriber) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.subscribers, sub)
+}
+
+// ListenToPendingReceipts streams every new receipt as soon as it was created, before the milestone containing it is issued.
+func (s *PendingReceiptsServer) ListenToPendingReceipts(req *ListenToPendingReceiptsRequest, stream ReceiptStream_ListenToPendingReceiptsServer) error {
+	sub, err := s.subscribe(req.GetMinDeposit())
+	if err != nil {
+		return err
+	}
+	defer s.unsubscribe(sub)
+
+	for {
+		receipt, err := sub.receipts.Pop(stream.Context())
+		if err != nil {
+			switch {
+			case errors.Is(err, eventqueue.ErrListenerTooSlow):
+				return status.Errorf(codes.ResourceExhausted, "subscriber did not keep up with %d pending receipts", s.bufferSize)
+			case errors.Is(err, eventqueue.ErrQueueClosed):
+				// the queue is only closed by the server if it shuts down
+				return status.Error(codes.Unavailable, "server is shutting down")
+			default:
+				// the subscriber closed the stream
+				return nil
+			}
+		}
+
+		if err := stream.Send(receipt); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package grpcapi_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-coordinator/pkg/grpcapi"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func newTestReceipt(deposits ...uint64) *iotago.ReceiptMilestoneOpt {
+	receipt := &iotago.ReceiptMilestoneOpt{
+		MigratedAt: 7,
+		Final:      true,
+		Transaction: &iotago.TreasuryTransaction{
+			Input:  &iotago.TreasuryInput{8},
+			Output: &iotago.TreasuryOutput{Amount: 9_000_000},
+		},
+	}
+	for i, deposit := range deposits {
+		receipt.Funds = append(receipt.Funds, &iotago.MigratedFundsEntry{
+			TailTransactionHash: iotago.LegacyTailTransactionHash{byte(i + 1)},
+			Address:             &iotago.Ed25519Address{byte(i + 1)},
+			Deposit:             deposit,
+		})
+	}
+
+	return receipt
+}
+
+func newTestClient(t *testing.T, server *grpcapi.PendingReceiptsServer) grpcapi.ReceiptStreamClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	grpcapi.RegisterReceiptStreamServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return grpcapi.NewReceiptStreamClient(conn)
+}
+
+func TestNewPendingReceipt(t *testing.T) {
+	protoParams := &iotago.ProtocolParameters{TokenSupply: 10_000_000}
+	receipt := newTestReceipt(1_000_000)
+
+	pendingReceipt, err := grpcapi.NewPendingReceipt(42, time.UnixMilli(1_000_123), receipt, protoParams)
+	require.NoError(t, err)
+	require.EqualValues(t, 42, pendingReceipt.GetMilestoneIndex())
+	require.EqualValues(t, 1_000_123, pendingReceipt.GetTimestamp())
+	require.EqualValues(t, 7, pendingReceipt.GetMigratedAt())
+	require.True(t, pendingReceipt.GetFinal())
+	require.EqualValues(t, 1_000_000, pendingReceipt.GetSum())
+	require.Equal(t, []byte{8}, pendingReceipt.GetTreasuryInputMilestoneId()[:1])
+	require.EqualValues(t, 9_000_000, pendingReceipt.GetTreasuryOutputAmount())
+	require.Len(t, pendingReceipt.GetFunds(), 1)
+	require.Equal(t, receipt.Funds[0].TailTransactionHash[:], pendingReceipt.GetFunds()[0].GetTailTransactionHash())
+	require.Equal(t, receipt.Funds[0].Address.(*iotago.Ed25519Address)[:], pendingReceipt.GetFunds()[0].GetAddress())
+
+	// the serialized receipt is the receipt that will be included in the milestone
+	deserialized := &iotago.ReceiptMilestoneOpt{}
+	_, err = deserialized.Deserialize(pendingReceipt.GetReceipt(), serializer.DeSeriModeNoValidation, protoParams)
+	require.NoError(t, err)
+	require.Equal(t, receipt, deserialized)
+}
+
+func TestPendingReceiptsServer(t *testing.T) {
+	protoParams := &iotago.ProtocolParameters{TokenSupply: 10_000_000}
+	server := grpcapi.NewPendingReceiptsServer(2)
+	client := newTestClient(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	allStream, err := client.ListenToPendingReceipts(ctx, &grpcapi.ListenToPendingReceiptsRequest{})
+	require.NoError(t, err)
+	largeStream, err := client.ListenToPendingReceipts(ctx, &grpcapi.ListenToPendingReceiptsRequest{MinDeposit: 5_000_000})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return server.SubscribersCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	small, err := grpcapi.NewPendingReceipt(42, time.Now(), newTestReceipt(1_000_000), protoParams)
+	require.NoError(t, err)
+	large, err := grpcapi.NewPendingReceipt(43, time.Now(), newTestReceipt(1_000_000, 5_000_000), protoParams)
+	require.NoError(t, err)
+
+	server.Publish(small)
+	server.Publish(large)
+
+	received, err := allStream.Recv()
+	require.NoError(t, err)
+	require.EqualValues(t, 42, received.GetMilestoneIndex())
+	received, err = allStream.Recv()
+	require.NoError(t, err)
+	require.EqualValues(t, 43, received.GetMilestoneIndex())
+
+	// only receipts containing a large enough migration are sent to subscribers with a minimum deposit
+	received, err = largeStream.Recv()
+	require.NoError(t, err)
+	require.EqualValues(t, 43, received.GetMilestoneIndex())
+
+	// closing the server disconnects all subscribers
+	server.Close()
+	_, err = allStream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = largeStream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Zero(t, server.SubscribersCount())
+
+	stream, err := client.ListenToPendingReceipts(ctx, &grpcapi.ListenToPendingReceiptsRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestPendingReceiptsServerSlowSubscriber(t *testing.T) {
+	server := grpcapi.NewPendingReceiptsServer(1)
+
+	// the subscriber never reads from its buffer, because the stream is not served
+	stream := &blockedStream{ctx: context.Background(), release: make(chan struct{})}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.ListenToPendingReceipts(&grpcapi.ListenToPendingReceiptsRequest{}, stream) }()
+	require.Eventually(t, func() bool { return server.SubscribersCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// publishing never blocks, a subscriber that can't keep up is disconnected
+	for i := 0; i < 5; i++ {
+		server.Publish(&grpcapi.PendingReceipt{MilestoneIndex: uint32(i)})
+	}
+	close(stream.release)
+
+	select {
+	case err := <-errCh:
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "subscriber was not disconnected")
+	}
+	require.Zero(t, server.SubscribersCount())
+}
+
+// blockedStream is a stream whose Send blocks until it is unblocked.
+type blockedStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	release chan struct{}
+}
+
+func (s *blockedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *blockedStream) Send(_ *grpcapi.PendingReceipt) error {
+	<-s.release
+
+	return nil
+}
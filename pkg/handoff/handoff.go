@@ -0,0 +1,362 @@
+package handoff
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// ProtocolVersion is the version of the handoff protocol, both processes need to speak the same version.
+const ProtocolVersion = 1
+
+const (
+	messageTypeRequest = "request"
+	messageTypeToken   = "token"
+	messageTypeAck     = "ack"
+	messageTypeError   = "error"
+)
+
+var (
+	// ErrNoPredecessor is returned when no running coordinator listens at the handoff socket.
+	ErrNoPredecessor = errors.New("no running coordinator to take over from")
+	// ErrHandoffFailed is returned when the running coordinator refused or failed to hand off.
+	ErrHandoffFailed = errors.New("handoff failed")
+	// ErrTokenMismatch is returned when the state of the successor doesn't match the handoff token.
+	ErrTokenMismatch = errors.New("state does not match the handoff token")
+	// ErrSocketInUse is returned when another coordinator already listens at the handoff socket.
+	ErrSocketInUse = errors.New("handoff socket is in use by another coordinator")
+)
+
+// Token is passed by the running coordinator to its successor once it stopped issuing milestones and flushed its state.
+type Token struct {
+	// the random ID of the handoff.
+	ID string `json:"id"`
+	// the process ID of the coordinator that handed off.
+	PID int `json:"pid"`
+	// the index of the latest milestone issued by the coordinator that handed off.
+	LatestMilestoneIndex iotago.MilestoneIndex `json:"latestMilestoneIndex"`
+	// the ID of the latest milestone issued by the coordinator that handed off (hex encoded).
+	LatestMilestoneID string `json:"latestMilestoneId"`
+	// the unix timestamp of the handoff.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// NewToken creates the token of a handoff after the milestone with the given index and ID.
+func NewToken(latestMilestoneIndex iotago.MilestoneIndex, latestMilestoneID iotago.MilestoneID) (*Token, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("unable to generate handoff ID: %w", err)
+	}
+
+	return &Token{
+		ID:                   iotago.EncodeHex(id),
+		PID:                  os.Getpid(),
+		LatestMilestoneIndex: latestMilestoneIndex,
+		LatestMilestoneID:    latestMilestoneID.ToHex(),
+		Timestamp:            time.Now().Unix(),
+	}, nil
+}
+
+// Verify returns an error if the state the successor loaded doesn't continue where the coordinator that handed off stopped,
+// which would lead to duplicate or missed milestones.
+func (t *Token) Verify(latestMilestoneIndex iotago.MilestoneIndex, latestMilestoneID iotago.MilestoneID) error {
+	if t.LatestMilestoneIndex != latestMilestoneIndex || t.LatestMilestoneID != latestMilestoneID.ToHex() {
+		return fmt.Errorf("%w: handoff %s after milestone %d (%s), but the state contains milestone %d (%s)",
+			ErrTokenMismatch, t.ID, t.LatestMilestoneIndex, t.LatestMilestoneID, latestMilestoneIndex, latestMilestoneID.ToHex())
+	}
+
+	return nil
+}
+
+// message is a message of the handoff protocol, the messages are exchanged as JSON lines.
+type message struct {
+	Type    string `json:"type"`
+	Version int    `json:"version,omitempty"`
+	Token   *Token `json:"token,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// connection is a connection of the handoff protocol.
+type connection struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+}
+
+func newConnection(conn net.Conn, timeout time.Duration) *connection {
+	return &connection{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: timeout,
+	}
+}
+
+func (c *connection) send(msg *message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(c.timeout)); err != nil {
+		return err
+	}
+	_, err = c.conn.Write(append(data, '\n'))
+
+	return err
+}
+
+func (c *connection) receive(expectedType string) (*message, error) {
+	if err := c.conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &message{}
+	if err := json.Unmarshal(line, msg); err != nil {
+		return nil, fmt.Errorf("invalid handoff message: %w", err)
+	}
+
+	if msg.Type == messageTypeError {
+		return nil, fmt.Errorf("%w: %s", ErrHandoffFailed, msg.Error)
+	}
+
+	if msg.Type != expectedType {
+		return nil, fmt.Errorf("%w: expected %s message, got %s", ErrHandoffFailed, expectedType, msg.Type)
+	}
+
+	return msg, nil
+}
+
+// Yielder is the coordinator that hands off to its successor.
+type Yielder interface {
+	// Yield stops the issuance of milestones after the current one was issued and flushed, and returns the handoff token.
+	Yield() (*Token, error)
+	// Resume continues the issuance of milestones if the successor didn't acknowledge the handoff.
+	Resume()
+}
+
+// Server is the side of the running coordinator, which hands off to a successor that connects to the handoff socket.
+// A nil Server disables the handoff.
+type Server struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	// the path to the unix socket.
+	socketPath string
+	// the timeout of every step of the handoff.
+	timeout time.Duration
+	// the token received from the predecessor at startup, nil if the coordinator didn't take over.
+	predecessor *Token
+
+	lock     syncutils.Mutex
+	listener net.Listener
+	// the connection to the successor, it stays open until the process exits, which signals the successor to continue.
+	successor net.Conn
+}
+
+// NewServer creates a new Server that listens at the given unix socket.
+// The token of the predecessor is the one returned by TakeOver at startup, if any.
+func NewServer(log *logger.Logger, socketPath string, timeout time.Duration, predecessor *Token) *Server {
+	return &Server{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		socketPath:    socketPath,
+		timeout:       timeout,
+		predecessor:   predecessor,
+	}
+}
+
+// Predecessor returns the token received from the coordinator that handed off at startup, or nil.
+func (s *Server) Predecessor() *Token {
+	if s == nil {
+		return nil
+	}
+
+	return s.predecessor
+}
+
+// Listen starts listening at the handoff socket.
+// A socket file left behind by a crashed coordinator is removed, a socket of a running coordinator is never taken over.
+func (s *Server) Listen() error {
+	if conn, err := net.DialTimeout("unix", s.socketPath, s.timeout); err == nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("%w: %s", ErrSocketInUse, s.socketPath)
+	}
+
+	if err := os.Remove(s.socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove stale handoff socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("unable to listen at handoff socket: %w", err)
+	}
+
+	s.lock.Lock()
+	s.listener = listener
+	s.lock.Unlock()
+
+	return nil
+}
+
+// Run accepts successors until ctx is done or the coordinator handed off, in which case onHandedOff is called.
+// The connection to the successor is kept open, it is closed when the process exits.
+func (s *Server) Run(ctx context.Context, yielder Yielder, onHandedOff func(token *Token)) {
+	s.lock.Lock()
+	listener := s.listener
+	s.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				s.LogWarnf("failed to accept handoff connection: %s", err)
+			}
+
+			return
+		}
+
+		token, err := s.handoff(newConnection(conn, s.timeout), yielder)
+		if err != nil {
+			s.LogWarnf("handoff failed: %s", err)
+			_ = conn.Close()
+
+			continue
+		}
+
+		s.lock.Lock()
+		s.successor = conn
+		s.lock.Unlock()
+
+		// no further successors are accepted
+		_ = listener.Close()
+		onHandedOff(token)
+
+		return
+	}
+}
+
+// handoff hands off to the successor on the given connection, the coordinator is resumed if the successor didn't acknowledge the token.
+func (s *Server) handoff(c *connection, yielder Yielder) (*Token, error) {
+	request, err := c.receive(messageTypeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Version != ProtocolVersion {
+		err := fmt.Errorf("%w: protocol version %d is not supported, expected %d", ErrHandoffFailed, request.Version, ProtocolVersion)
+		_ = c.send(&message{Type: messageTypeError, Error: err.Error()})
+
+		return nil, err
+	}
+
+	s.LogInfo("successor requested a handoff, stopping the issuance of milestones")
+	token, err := yielder.Yield()
+	if err != nil {
+		_ = c.send(&message{Type: messageTypeError, Error: err.Error()})
+
+		return nil, err
+	}
+
+	if err := c.send(&message{Type: messageTypeToken, Token: token}); err != nil {
+		yielder.Resume()
+
+		return nil, err
+	}
+
+	if _, err := c.receive(messageTypeAck); err != nil {
+		yielder.Resume()
+
+		return nil, fmt.Errorf("successor didn't acknowledge the handoff, resuming: %w", err)
+	}
+
+	return token, nil
+}
+
+// Close closes the handoff socket and the connection to the successor, which lets the successor continue.
+// It must only be called once the coordinator stopped completely, usually the connection is closed by the exit of the process.
+func (s *Server) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	if s.successor != nil {
+		_ = s.successor.Close()
+	}
+}
+
+// TakeOver asks the coordinator that listens at the given unix socket to hand off, and blocks until its process exited.
+// It returns ErrNoPredecessor if no coordinator is running, so that the coordinator starts normally.
+func TakeOver(ctx context.Context, socketPath string, timeout time.Duration) (*Token, error) {
+	conn, err := net.DialTimeout("unix", socketPath, timeout)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, ErrNoPredecessor
+		}
+
+		return nil, fmt.Errorf("unable to connect to handoff socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	go func() {
+		<-ctx.Done()
+		// unblocks the reads if ctx is done before the predecessor exited
+		_ = conn.SetDeadline(time.Now())
+	}()
+
+	c := newConnection(conn, timeout)
+	if err := c.send(&message{Type: messageTypeRequest, Version: ProtocolVersion}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHandoffFailed, err)
+	}
+
+	// the predecessor finishes its current milestone before it answers
+	response, err := c.receive(messageTypeToken)
+	if err != nil {
+		return nil, err
+	}
+	if response.Token == nil {
+		return nil, fmt.Errorf("%w: no token received", ErrHandoffFailed)
+	}
+
+	if err := c.send(&message{Type: messageTypeAck}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHandoffFailed, err)
+	}
+
+	// the connection is closed when the process of the predecessor exited
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, c.reader); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: predecessor %d didn't exit in time: %v", ErrHandoffFailed, response.Token.PID, ctx.Err())
+		}
+
+		return nil, fmt.Errorf("%w: %v", ErrHandoffFailed, err)
+	}
+
+	return response.Token, nil
+}
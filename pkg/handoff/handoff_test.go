@@ -0,0 +1,145 @@
+package handoff_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-coordinator/pkg/handoff"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// mockYielder hands off after the milestone with the given index and ID.
+type mockYielder struct {
+	index   iotago.MilestoneIndex
+	id      iotago.MilestoneID
+	yielded atomic.Int32
+	resumed atomic.Int32
+}
+
+func (y *mockYielder) Yield() (*handoff.Token, error) {
+	y.yielded.Add(1)
+
+	return handoff.NewToken(y.index, y.id)
+}
+
+func (y *mockYielder) Resume() {
+	y.resumed.Add(1)
+}
+
+func newTestServer(t *testing.T, timeout time.Duration) (*handoff.Server, string) {
+	t.Helper()
+
+	// unix socket paths are limited in length, the test temp dirs might exceed it
+	dir, err := os.MkdirTemp("", "handoff")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	socketPath := filepath.Join(dir, "coordinator.sock")
+	server := handoff.NewServer(logger.NewNopLogger(), socketPath, timeout, nil)
+	require.NoError(t, server.Listen())
+	t.Cleanup(server.Close)
+
+	return server, socketPath
+}
+
+func TestTakeOver(t *testing.T) {
+	server, socketPath := newTestServer(t, time.Second)
+
+	// a second coordinator can't listen at the same socket
+	require.ErrorIs(t, handoff.NewServer(logger.NewNopLogger(), socketPath, time.Second, nil).Listen(), handoff.ErrSocketInUse)
+
+	yielder := &mockYielder{index: 42, id: iotago.MilestoneID{0x01}}
+	handedOff := make(chan *handoff.Token, 1)
+	go server.Run(context.Background(), yielder, func(token *handoff.Token) {
+		handedOff <- token
+	})
+
+	result := make(chan *handoff.Token, 1)
+	go func() {
+		token, err := handoff.TakeOver(context.Background(), socketPath, time.Second)
+		require.NoError(t, err)
+		result <- token
+	}()
+
+	var token *handoff.Token
+	select {
+	case token = <-handedOff:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "coordinator didn't hand off")
+	}
+	require.EqualValues(t, 42, token.LatestMilestoneIndex)
+	require.EqualValues(t, 1, yielder.yielded.Load())
+	require.Zero(t, yielder.resumed.Load())
+
+	// the successor waits until the process of the predecessor exited
+	select {
+	case <-result:
+		require.FailNow(t, "successor continued before the predecessor exited")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	server.Close()
+	select {
+	case received := <-result:
+		require.Equal(t, token, received)
+		require.NoError(t, received.Verify(42, iotago.MilestoneID{0x01}))
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "successor didn't continue after the predecessor exited")
+	}
+
+	// the socket file left behind is removed by the successor
+	successor := handoff.NewServer(logger.NewNopLogger(), socketPath, time.Second, token)
+	require.NoError(t, successor.Listen())
+	successor.Close()
+	require.Equal(t, token, successor.Predecessor())
+}
+
+func TestTakeOverWithoutPredecessor(t *testing.T) {
+	_, err := handoff.TakeOver(context.Background(), filepath.Join(t.TempDir(), "coordinator.sock"), time.Second)
+	require.ErrorIs(t, err, handoff.ErrNoPredecessor)
+
+	var disabled *handoff.Server
+	require.Nil(t, disabled.Predecessor())
+}
+
+func TestHandoffWithoutAck(t *testing.T) {
+	server, socketPath := newTestServer(t, 100*time.Millisecond)
+
+	yielder := &mockYielder{index: 42}
+	go server.Run(context.Background(), yielder, func(_ *handoff.Token) {
+		require.FailNow(t, "coordinator handed off without an ack")
+	})
+
+	// the successor requests the handoff, but disappears before it acknowledges the token
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte(`{"type":"request","version":1}` + "\n"))
+	require.NoError(t, err)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	require.Contains(t, line, `"type":"token"`)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return yielder.resumed.Load() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTokenVerify(t *testing.T) {
+	token, err := handoff.NewToken(42, iotago.MilestoneID{0x01})
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), token.PID)
+
+	require.NoError(t, token.Verify(42, iotago.MilestoneID{0x01}))
+	require.ErrorIs(t, token.Verify(43, iotago.MilestoneID{0x01}), handoff.ErrTokenMismatch)
+	require.ErrorIs(t, token.Verify(42, iotago.MilestoneID{0x02}), handoff.ErrTokenMismatch)
+}
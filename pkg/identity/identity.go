@@ -0,0 +1,131 @@
+package identity
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+	iotagoEd25519 "github.com/iotaledger/iota.go/v3/ed25519"
+)
+
+const (
+	// KindStatusReport is the kind of the signed status reports of the REST API.
+	KindStatusReport = "statusReport"
+	// KindJournalSegment is the kind of the signed archives of the event journal.
+	KindJournalSegment = "journalSegment"
+	// KindMigrationAttestation is the kind of the signed migration attestations of the REST API.
+	KindMigrationAttestation = "migrationAttestation"
+)
+
+var (
+	// ErrInvalidSignature is returned when a signed document wasn't signed by the expected coordinator identity.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Identity is the key of a coordinator instance that signs its operational artifacts, e.g. status reports and audit log segments,
+// so that downstream consumers can verify where they came from.
+// The identity key is distinct from the milestone keys, so it can't be used to sign anything that is valid in the protocol.
+type Identity struct {
+	privateKey ed25519.PrivateKey
+	publicKey  iotago.MilestonePublicKey
+}
+
+// New creates a new Identity from the given private key.
+func New(privateKey ed25519.PrivateKey) *Identity {
+	var publicKey iotago.MilestonePublicKey
+	//nolint:forcetypeassert // ed25519.PrivateKey.Public always returns an ed25519.PublicKey
+	copy(publicKey[:], privateKey.Public().(ed25519.PublicKey))
+
+	return &Identity{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}
+}
+
+// PublicKey returns the public key of the identity.
+func (i *Identity) PublicKey() iotago.MilestonePublicKey {
+	return i.publicKey
+}
+
+// Sign signs the document of the given kind.
+func (i *Identity) Sign(kind string, document []byte) *SignedDocument {
+	signed := &SignedDocument{
+		Kind:      kind,
+		Timestamp: time.Now().Unix(),
+		Document:  document,
+	}
+
+	signature := &iotago.Ed25519Signature{PublicKey: i.publicKey}
+	copy(signature.Signature[:], ed25519.Sign(i.privateKey, signed.Essence()))
+	signed.Signature = signature
+
+	return signed
+}
+
+// SignJSON signs the JSON encoding of the document of the given kind.
+func (i *Identity) SignJSON(kind string, document any) (*SignedDocument, error) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode %s: %w", kind, err)
+	}
+
+	return i.Sign(kind, data), nil
+}
+
+// SignedDocument is a document that was signed by the identity of a coordinator instance.
+type SignedDocument struct {
+	// the kind of the document, it is signed as well, so that a signature can't be passed off for another kind of document.
+	Kind string `json:"kind"`
+	// the unix timestamp of the signature.
+	Timestamp int64 `json:"timestamp"`
+	// the signed document, whitespace outside of its strings is not covered by the signature.
+	Document json.RawMessage `json:"document"`
+	// the signature of the identity over the essence of the document.
+	Signature *iotago.Ed25519Signature `json:"signature"`
+}
+
+// Essence returns the hash of the kind, the timestamp and the document, which gets signed by the identity.
+// The document is compacted first, so that the signature survives reformatting the JSON, e.g. by indenting it.
+func (d *SignedDocument) Essence() []byte {
+	document := bytes.NewBuffer(make([]byte, 0, len(d.Document)))
+	if err := json.Compact(document, d.Document); err != nil {
+		// invalid JSON is signed as it is
+		document.Reset()
+		document.Write(d.Document)
+	}
+
+	data := make([]byte, 0, 1+len(d.Kind)+8+document.Len())
+	data = append(data, byte(len(d.Kind)))
+	data = append(data, d.Kind...)
+	data = binary.LittleEndian.AppendUint64(data, uint64(d.Timestamp))
+	data = append(data, document.Bytes()...)
+
+	essence := blake2b.Sum256(data)
+
+	return essence[:]
+}
+
+// Verify checks that the document of the given kind was signed by the identity with the given public key.
+func (d *SignedDocument) Verify(kind string, publicKey iotago.MilestonePublicKey) error {
+	if d.Kind != kind {
+		return fmt.Errorf("%w: the document is a %s, expected %s", ErrInvalidSignature, d.Kind, kind)
+	}
+	if d.Signature == nil {
+		return fmt.Errorf("%w: the document is not signed", ErrInvalidSignature)
+	}
+	if d.Signature.PublicKey != publicKey {
+		return fmt.Errorf("%w: signed by unknown public key %s", ErrInvalidSignature, iotago.EncodeHex(d.Signature.PublicKey[:]))
+	}
+	if !iotagoEd25519.Verify(d.Signature.PublicKey[:], d.Essence(), d.Signature.Signature[:]) {
+		return fmt.Errorf("%w: signature of public key %s is invalid", ErrInvalidSignature, iotago.EncodeHex(d.Signature.PublicKey[:]))
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package identity_test
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestSignedDocument(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	id := identity.New(privateKey)
+
+	_, otherPrivateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	other := identity.New(otherPrivateKey)
+
+	signed, err := id.SignJSON(identity.KindStatusReport, map[string]uint32{"latestMilestoneIndex": 42})
+	require.NoError(t, err)
+	require.NoError(t, signed.Verify(identity.KindStatusReport, id.PublicKey()))
+
+	// the document survives a round trip through its JSON encoding
+	data, err := json.Marshal(signed)
+	require.NoError(t, err)
+	decoded := &identity.SignedDocument{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.NoError(t, decoded.Verify(identity.KindStatusReport, id.PublicKey()))
+
+	// a signature can't be passed off for another kind of document
+	require.ErrorIs(t, decoded.Verify(identity.KindJournalSegment, id.PublicKey()), identity.ErrInvalidSignature)
+	decoded.Kind = identity.KindJournalSegment
+	require.ErrorIs(t, decoded.Verify(identity.KindJournalSegment, id.PublicKey()), identity.ErrInvalidSignature)
+
+	// only the expected identity is accepted
+	require.ErrorIs(t, signed.Verify(identity.KindStatusReport, other.PublicKey()), identity.ErrInvalidSignature)
+
+	tampered := *signed
+	tampered.Document = json.RawMessage(`{"latestMilestoneIndex":43}`)
+	require.ErrorIs(t, tampered.Verify(identity.KindStatusReport, id.PublicKey()), identity.ErrInvalidSignature)
+
+	tampered = *signed
+	tampered.Timestamp++
+	require.ErrorIs(t, tampered.Verify(identity.KindStatusReport, id.PublicKey()), identity.ErrInvalidSignature)
+
+	tampered = *signed
+	tampered.Signature = &iotago.Ed25519Signature{PublicKey: signed.Signature.PublicKey}
+	require.ErrorIs(t, tampered.Verify(identity.KindStatusReport, id.PublicKey()), identity.ErrInvalidSignature)
+}
+
+func TestSignedDocumentSurvivesIndentation(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	id := identity.New(privateKey)
+
+	signed, err := id.SignJSON(identity.KindStatusReport, map[string]uint32{"latestMilestoneIndex": 42})
+	require.NoError(t, err)
+
+	data, err := json.MarshalIndent(signed, "", "  ")
+	require.NoError(t, err)
+	decoded := &identity.SignedDocument{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.NotEqual(t, signed.Document, decoded.Document)
+	require.NoError(t, decoded.Verify(identity.KindStatusReport, id.PublicKey()))
+}
@@ -0,0 +1,463 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// the extension of the signatures of the archives.
+const signatureExtension = ".sig"
+
+var (
+	// ErrJournalClosed is returned when the journal was already closed.
+	ErrJournalClosed = errors.New("journal closed")
+	// ErrEventsArchived is returned when the requested events were moved to an archive of the journal.
+	ErrEventsArchived = errors.New("events archived")
+	// ErrRotationFailed is returned when the event was appended, but the journal could not be rotated afterwards.
+	ErrRotationFailed = errors.New("rotation of the event journal failed")
+)
+
+// Journal is a persistent, append-only log of events.
+// Every event gets a gapless sequence number, starting at 1, so that listeners can replay the events they missed.
+// The events are stored as JSON lines.
+// If the journal exceeds its maximum size, it is moved to an archive named after the sequence numbers it contains,
+// and a new journal file is started with the latest event, so that the sequence numbers continue after a restart.
+// Alternatively the events can be kept in a store that is shared with other components, see OpenStore.
+type Journal struct {
+	lock syncutils.RWMutex
+
+	filePath string
+	file     *os.File
+	// the sequence number of the first event in the file.
+	firstSequence uint64
+	// the offsets of the events in the file, the event with sequence number n is at offsets[n-firstSequence].
+	offsets []int64
+	// the size of the file.
+	size int64
+	// the store the events are kept in instead of the file (nil = journal file).
+	store storage.Store
+	// the amount of events in the store.
+	storedEvents int
+	// whether the journal was closed.
+	closed bool
+
+	// the size after which the journal is rotated (0 = never).
+	maxSize int64
+	// the policy of the archives of the journal.
+	archivePolicy retention.Policy
+	// the identity that signs the archives of the journal (nil = unsigned).
+	identity *identity.Identity
+}
+
+// Segment describes an archive of the journal, it is signed by the coordinator identity when the journal is rotated.
+type Segment struct {
+	// the file name of the archive, without the compressed extension.
+	Name string `json:"name"`
+	// the sequence number of the first event in the archive.
+	FirstSequence uint64 `json:"firstSequence"`
+	// the sequence number of the last event in the archive.
+	LastSequence uint64 `json:"lastSequence"`
+	// the BLAKE2b-256 hash of the uncompressed archive (hex encoded).
+	Hash string `json:"hash"`
+}
+
+// SignatureFilePath returns the path of the signature of the given archive.
+func SignatureFilePath(archiveFilePath string) string {
+	return strings.TrimSuffix(archiveFilePath, retention.CompressedExtension) + signatureExtension
+}
+
+// HashSegment returns the BLAKE2b-256 hash of the given archive, compressed archives are hashed uncompressed.
+func HashSegment(archiveFilePath string) (string, error) {
+	file, err := retention.Open(archiveFilePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return iotago.EncodeHex(hash.Sum(nil)), nil
+}
+
+// WithRotation rotates the journal once it exceeds maxSize bytes and applies the policy to its archives.
+func WithRotation(maxSize int64, archivePolicy retention.Policy) options.Option[Journal] {
+	return func(j *Journal) {
+		j.maxSize = maxSize
+		j.archivePolicy = archivePolicy
+	}
+}
+
+// WithIdentity signs the archives of the journal with the given coordinator identity.
+// The signature of an archive is stored next to it, see SignatureFilePath.
+func WithIdentity(id *identity.Identity) options.Option[Journal] {
+	return func(j *Journal) {
+		j.identity = id
+	}
+}
+
+// Open opens the journal at the given path and creates it if it does not exist.
+// An incomplete event at the end of the file, e.g. after a crash while writing, is discarded.
+// If rotation is enabled, the policy is applied to the existing archives.
+func Open(filePath string, opts ...options.Option[Journal]) (*Journal, error) {
+	j := options.Apply(&Journal{
+		filePath:      filePath,
+		firstSequence: 1,
+	}, opts)
+
+	// a crash during the rotation can leave the new journal file behind before it was moved into place
+	if _, err := os.Stat(filePath); errors.Is(err, os.ErrNotExist) {
+		if err := os.Rename(j.rotationFilePath(), filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("unable to recover rotated event journal: %w", err)
+		}
+	}
+
+	//nolint:gosec // the path is defined by the operator
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open event journal: %w", err)
+	}
+
+	j.file = file
+	if err := j.load(); err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+
+	if j.maxSize > 0 {
+		if _, err := j.cleanupArchives(); err != nil {
+			_ = file.Close()
+
+			return nil, err
+		}
+	}
+
+	return j, nil
+}
+
+// signArchive writes the signature of the given archive, if the journal has an identity.
+func (j *Journal) signArchive(archiveFilePath string, firstSequence uint64, lastSequence uint64) error {
+	if j.identity == nil {
+		return nil
+	}
+
+	hash, err := HashSegment(archiveFilePath)
+	if err != nil {
+		return fmt.Errorf("unable to hash archive: %w", err)
+	}
+
+	signed, err := j.identity.SignJSON(identity.KindJournalSegment, &Segment{
+		Name:          filepath.Base(archiveFilePath),
+		FirstSequence: firstSequence,
+		LastSequence:  lastSequence,
+		Hash:          hash,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := ioutils.WriteJSONToFile(SignatureFilePath(archiveFilePath), signed, 0o600); err != nil {
+		return fmt.Errorf("unable to write archive signature: %w", err)
+	}
+
+	return nil
+}
+
+// rotationFilePath returns the path of the new journal file while the journal is rotated.
+func (j *Journal) rotationFilePath() string {
+	return j.filePath + ".rotating"
+}
+
+// archiveFilePath returns the path of the archive containing the events with the given sequence numbers.
+func (j *Journal) archiveFilePath(firstSequence uint64, lastSequence uint64) string {
+	return fmt.Sprintf("%s.%d-%d", j.filePath, firstSequence, lastSequence)
+}
+
+// cleanupArchives applies the archive policy to the archives of the journal.
+func (j *Journal) cleanupArchives() (*retention.Result, error) {
+	// the signatures are removed together with their archives
+	policy := j.archivePolicy
+	policy.Companions = append([]string{signatureExtension}, policy.Companions...)
+
+	result, err := retention.Cleanup(filepath.Dir(j.filePath), filepath.Base(j.filePath)+".*-*", policy)
+	if err != nil {
+		return nil, fmt.Errorf("unable to clean up event journal archives: %w", err)
+	}
+
+	return result, nil
+}
+
+// load reads the offsets of all events and truncates the file after the last complete event.
+func (j *Journal) load() error {
+	reader := bufio.NewReader(j.file)
+
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("unable to read event journal: %w", err)
+		}
+
+		// only lines that were written completely are valid events
+		if len(line) == 0 || line[len(line)-1] != '\n' || !json.Valid(bytes.TrimSpace(line)) {
+			break
+		}
+
+		// the journal continues the sequence numbers of its archives
+		if len(j.offsets) == 0 {
+			event := &api.Event{}
+			if err := json.Unmarshal(line, event); err != nil {
+				return fmt.Errorf("unable to parse event journal: %w", err)
+			}
+			if event.Sequence > 0 {
+				j.firstSequence = event.Sequence
+			}
+		}
+
+		j.offsets = append(j.offsets, offset)
+		offset += int64(len(line))
+	}
+
+	if err := j.file.Truncate(offset); err != nil {
+		return fmt.Errorf("unable to truncate event journal: %w", err)
+	}
+	j.size = offset
+
+	return nil
+}
+
+// LatestSequence returns the sequence number of the latest event, or 0 if the journal is empty.
+func (j *Journal) LatestSequence() uint64 {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	return j.latestSequence()
+}
+
+// latestSequence returns the sequence number of the latest event, the caller must hold the lock.
+func (j *Journal) latestSequence() uint64 {
+	if j.eventCount() == 0 {
+		return 0
+	}
+
+	return j.firstSequence + uint64(j.eventCount()) - 1
+}
+
+// eventCount returns the amount of events in the file or the store, the caller must hold the lock.
+func (j *Journal) eventCount() int {
+	if j.store != nil {
+		return j.storedEvents
+	}
+
+	return len(j.offsets)
+}
+
+// Append assigns the next sequence number to the event and appends it to the journal.
+func (j *Journal) Append(event *api.Event) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.closed {
+		return ErrJournalClosed
+	}
+
+	event.Sequence = j.firstSequence + uint64(j.eventCount())
+
+	if j.store != nil {
+		if err := j.appendToStore(event); err != nil {
+			event.Sequence = 0
+
+			return err
+		}
+
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		event.Sequence = 0
+
+		return err
+	}
+	data = append(data, '\n')
+
+	if _, err := j.file.WriteAt(data, j.size); err != nil {
+		event.Sequence = 0
+
+		return fmt.Errorf("unable to write event journal: %w", err)
+	}
+
+	// the sequence number must not be handed out twice, even if the coordinator crashes
+	if err := j.file.Sync(); err != nil {
+		event.Sequence = 0
+
+		return fmt.Errorf("unable to sync event journal: %w", err)
+	}
+
+	j.offsets = append(j.offsets, j.size)
+	j.size += int64(len(data))
+
+	if j.maxSize > 0 && j.size >= j.maxSize {
+		if err := j.rotate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrRotationFailed, err)
+		}
+	}
+
+	return nil
+}
+
+// rotate moves the journal to an archive and starts a new journal file with the latest event, the caller must hold the lock.
+// The new journal file is written before the journal is archived, so that a crash never loses the sequence numbers.
+func (j *Journal) rotate() error {
+	latestSequence := j.latestSequence()
+	latestOffset := j.offsets[len(j.offsets)-1]
+
+	latestEvent := make([]byte, j.size-latestOffset)
+	if _, err := j.file.ReadAt(latestEvent, latestOffset); err != nil {
+		return fmt.Errorf("unable to read latest event: %w", err)
+	}
+
+	rotationFilePath := j.rotationFilePath()
+	//nolint:gosec // the path is defined by the operator
+	file, err := os.OpenFile(rotationFilePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to create new journal file: %w", err)
+	}
+
+	if _, err := file.Write(latestEvent); err != nil {
+		_ = file.Close()
+		_ = os.Remove(rotationFilePath)
+
+		return fmt.Errorf("unable to write new journal file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		_ = os.Remove(rotationFilePath)
+
+		return fmt.Errorf("unable to sync new journal file: %w", err)
+	}
+
+	archiveFilePath := j.archiveFilePath(j.firstSequence, latestSequence)
+	if err := os.Rename(j.filePath, archiveFilePath); err != nil {
+		_ = file.Close()
+		_ = os.Remove(rotationFilePath)
+
+		return fmt.Errorf("unable to archive journal file: %w", err)
+	}
+
+	// the journal file is missing until the new one is moved into place, Open recovers it in case of a crash
+	if err := os.Rename(rotationFilePath, j.filePath); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("unable to move new journal file into place: %w", err)
+	}
+
+	_ = j.file.Close()
+	j.file = file
+	archivedFirstSequence := j.firstSequence
+	j.firstSequence = latestSequence
+	j.offsets = []int64{0}
+	j.size = int64(len(latestEvent))
+
+	// the archive is signed before it is compressed, so that the signature covers its original content
+	if err := j.signArchive(archiveFilePath, archivedFirstSequence, latestSequence); err != nil {
+		return err
+	}
+
+	if _, err := j.cleanupArchives(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Events returns at most limit events starting at the given sequence number.
+func (j *Journal) Events(fromSequence uint64, limit int) ([]*api.Event, error) {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	if j.closed {
+		return nil, ErrJournalClosed
+	}
+
+	if fromSequence == 0 {
+		fromSequence = j.firstSequence
+	}
+	if fromSequence < j.firstSequence {
+		return nil, fmt.Errorf("%w: the journal starts at sequence %d", ErrEventsArchived, j.firstSequence)
+	}
+
+	events := []*api.Event{}
+	if fromSequence > j.latestSequence() || limit <= 0 {
+		return events, nil
+	}
+
+	if j.store != nil {
+		return j.eventsFromStore(fromSequence, limit)
+	}
+
+	offset := j.offsets[fromSequence-j.firstSequence]
+	reader := bufio.NewReader(io.NewSectionReader(j.file, offset, j.size-offset))
+	for len(events) < limit {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read event journal: %w", err)
+		}
+
+		event := &api.Event{}
+		if err := json.Unmarshal(line, event); err != nil {
+			return nil, fmt.Errorf("unable to parse event journal: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// Close closes the journal.
+func (j *Journal) Close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.closed {
+		return nil
+	}
+	j.closed = true
+
+	// the store is shared with other components, it is closed by its owner
+	if j.store != nil {
+		return nil
+	}
+
+	err := j.file.Close()
+	j.file = nil
+
+	return err
+}
@@ -0,0 +1,239 @@
+package journal_test
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
+	"github.com/iotaledger/inx-coordinator/pkg/journal"
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+)
+
+func appendTestEvents(t *testing.T, j *journal.Journal, count int) {
+	for i := 0; i < count; i++ {
+		event := &api.Event{
+			Type:      api.EventTypeMilestoneTimeout,
+			Timestamp: int64(i),
+		}
+		require.NoError(t, j.Append(event))
+		require.Equal(t, j.LatestSequence(), event.Sequence)
+	}
+}
+
+func TestJournalAppendAndReplay(t *testing.T) {
+	j, err := journal.Open(filepath.Join(t.TempDir(), "events.journal"))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, j.Close()) }()
+
+	require.Zero(t, j.LatestSequence())
+
+	appendTestEvents(t, j, 5)
+	require.EqualValues(t, 5, j.LatestSequence())
+
+	events, err := j.Events(2, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.EqualValues(t, 2, events[0].Sequence)
+	require.EqualValues(t, 3, events[1].Sequence)
+	require.EqualValues(t, 2, events[1].Timestamp)
+
+	// sequence 0 starts at the beginning
+	events, err = j.Events(0, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+	require.EqualValues(t, 1, events[0].Sequence)
+
+	// listeners that are up to date get no events
+	events, err = j.Events(6, 100)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestJournalReopen(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "events.journal")
+
+	j, err := journal.Open(filePath)
+	require.NoError(t, err)
+	appendTestEvents(t, j, 3)
+	require.NoError(t, j.Close())
+
+	require.ErrorIs(t, j.Append(&api.Event{}), journal.ErrJournalClosed)
+
+	j, err = journal.Open(filePath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, j.Close()) }()
+
+	require.EqualValues(t, 3, j.LatestSequence())
+
+	// the sequence numbers continue without gaps
+	appendTestEvents(t, j, 1)
+	require.EqualValues(t, 4, j.LatestSequence())
+
+	events, err := j.Events(1, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	for i, event := range events {
+		require.EqualValues(t, i+1, event.Sequence)
+	}
+}
+
+func TestJournalStore(t *testing.T) {
+	store, err := storage.OpenFileStore(filepath.Join(t.TempDir(), "storage.db"))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, store.Close()) }()
+
+	// the entries of other components don't belong to the journal
+	require.NoError(t, store.Put([]byte("other/1"), []byte("{}")))
+
+	j, err := journal.OpenStore(store)
+	require.NoError(t, err)
+	require.Zero(t, j.LatestSequence())
+	appendTestEvents(t, j, 3)
+	require.NoError(t, j.Close())
+
+	require.ErrorIs(t, j.Append(&api.Event{}), journal.ErrJournalClosed)
+
+	// the store is closed by its owner, so the journal can be opened again
+	j, err = journal.OpenStore(store)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, j.LatestSequence())
+
+	appendTestEvents(t, j, 1)
+
+	events, err := j.Events(2, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.EqualValues(t, 2, events[0].Sequence)
+	require.EqualValues(t, 3, events[1].Sequence)
+
+	events, err = j.Events(0, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	require.EqualValues(t, 4, events[3].Sequence)
+}
+
+func TestJournalDiscardsIncompleteEvent(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "events.journal")
+
+	j, err := journal.Open(filePath)
+	require.NoError(t, err)
+	appendTestEvents(t, j, 2)
+	require.NoError(t, j.Close())
+
+	// simulate a crash while the event was written
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"sequence":3,"type":"milest`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	j, err = journal.Open(filePath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, j.Close()) }()
+
+	require.EqualValues(t, 2, j.LatestSequence())
+
+	appendTestEvents(t, j, 1)
+
+	events, err := j.Events(1, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.EqualValues(t, 3, events[2].Sequence)
+}
+
+func TestJournalRotation(t *testing.T) {
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "events.journal")
+
+	// every event exceeds the maximum size, so the journal is rotated after every append
+	j, err := journal.Open(filePath, journal.WithRotation(1, retention.Policy{MaxFiles: 2}))
+	require.NoError(t, err)
+	appendTestEvents(t, j, 4)
+	require.NoError(t, j.Close())
+
+	// the new journal file starts with the latest event, so that the sequence numbers continue after a restart
+	j, err = journal.Open(filePath, journal.WithRotation(1, retention.Policy{MaxFiles: 2}))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, j.Close()) }()
+
+	require.EqualValues(t, 4, j.LatestSequence())
+
+	events, err := j.Events(0, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.EqualValues(t, 4, events[0].Sequence)
+
+	_, err = j.Events(3, 100)
+	require.ErrorIs(t, err, journal.ErrEventsArchived)
+
+	appendTestEvents(t, j, 1)
+	require.EqualValues(t, 5, j.LatestSequence())
+
+	// only the latest archives are kept
+	require.FileExists(t, filePath+".4-5")
+	require.FileExists(t, filePath+".3-4")
+	require.NoFileExists(t, filePath+".2-3")
+	require.NoFileExists(t, filePath+".1-2")
+}
+
+func TestJournalRecoversInterruptedRotation(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "events.journal")
+
+	j, err := journal.Open(filePath)
+	require.NoError(t, err)
+	appendTestEvents(t, j, 3)
+	require.NoError(t, j.Close())
+
+	// simulate a crash after the journal was archived, but before the new journal file was moved into place
+	require.NoError(t, os.Rename(filePath, filePath+".rotating"))
+
+	j, err = journal.Open(filePath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, j.Close()) }()
+
+	require.EqualValues(t, 3, j.LatestSequence())
+}
+
+func TestJournalSignsArchives(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "events.journal")
+
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	id := identity.New(privateKey)
+
+	j, err := journal.Open(filePath,
+		journal.WithRotation(1, retention.Policy{MaxFiles: 1, Compress: true}),
+		journal.WithIdentity(id),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, j.Close()) }()
+
+	appendTestEvents(t, j, 3)
+
+	// the signature of a removed archive is removed as well
+	require.NoFileExists(t, journal.SignatureFilePath(filePath+".1-2"))
+	require.FileExists(t, filePath+".2-3"+retention.CompressedExtension)
+
+	signed := &identity.SignedDocument{}
+	require.NoError(t, ioutils.ReadJSONFromFile(journal.SignatureFilePath(filePath+".2-3"), signed))
+	require.NoError(t, signed.Verify(identity.KindJournalSegment, id.PublicKey()))
+
+	segment := &journal.Segment{}
+	require.NoError(t, json.Unmarshal(signed.Document, segment))
+	require.Equal(t, "events.journal.2-3", segment.Name)
+	require.EqualValues(t, 2, segment.FirstSequence)
+	require.EqualValues(t, 3, segment.LastSequence)
+
+	// the signature covers the uncompressed content of the archive
+	hash, err := journal.HashSegment(filePath + ".2-3" + retention.CompressedExtension)
+	require.NoError(t, err)
+	require.Equal(t, segment.Hash, hash)
+}
@@ -0,0 +1,89 @@
+package journal
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+)
+
+// the prefix of the keys of the events in a shared store.
+var storeKeyPrefix = []byte("journal/")
+
+// storeKey returns the key of the event with the given sequence number,
+// the sequence number is encoded big-endian, so that the events are iterated in order.
+func storeKey(sequence uint64) []byte {
+	return storage.Key(storeKeyPrefix, binary.BigEndian.AppendUint64(nil, sequence))
+}
+
+// OpenStore opens the journal that keeps its events in the given store instead of a journal file.
+// The store is shared with other components, so the events are never rotated and the store is not closed with the journal.
+func OpenStore(store storage.Store) (*Journal, error) {
+	j := &Journal{
+		store:         store,
+		firstSequence: 1,
+	}
+
+	first := true
+	if err := store.Iterate(storeKeyPrefix, func(key []byte, _ []byte) bool {
+		if first {
+			j.firstSequence = binary.BigEndian.Uint64(key[len(storeKeyPrefix):])
+			first = false
+		}
+		j.storedEvents++
+
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("unable to load event journal: %w", err)
+	}
+
+	return j, nil
+}
+
+// appendToStore puts the event with the already assigned sequence number into the store, the caller must hold the lock.
+func (j *Journal) appendToStore(event *api.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err := j.store.Put(storeKey(event.Sequence), data); err != nil {
+		return fmt.Errorf("unable to write event journal: %w", err)
+	}
+
+	// the sequence number must not be handed out twice, even if the coordinator crashes
+	if err := j.store.Flush(); err != nil {
+		return fmt.Errorf("unable to sync event journal: %w", err)
+	}
+
+	j.storedEvents++
+
+	return nil
+}
+
+// eventsFromStore returns at most limit events starting at the given sequence number, the caller must hold the lock.
+func (j *Journal) eventsFromStore(fromSequence uint64, limit int) ([]*api.Event, error) {
+	events := []*api.Event{}
+	for sequence := fromSequence; sequence <= j.latestSequence() && len(events) < limit; sequence++ {
+		data, err := j.store.Get(storeKey(sequence))
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, fmt.Errorf("unable to read event journal: event %d is missing", sequence)
+			}
+
+			return nil, fmt.Errorf("unable to read event journal: %w", err)
+		}
+
+		event := &api.Event{}
+		if err := json.Unmarshal(data, event); err != nil {
+			return nil, fmt.Errorf("unable to parse event journal: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,133 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// CachingQueryer is a Queryer that caches the migrations of legacy milestones on disk.
+// The migrations confirmed by a legacy milestone never change, so the legacy node
+// doesn't need to be queried again for milestones that were already fetched, e.g. after a restart.
+type CachingQueryer struct {
+	// the queryer used for milestones that are not cached yet.
+	queryer Queryer
+	// the directory the cached migrations are stored in, one file per legacy milestone.
+	directory string
+}
+
+// NewCachingQueryer creates a new CachingQueryer.
+func NewCachingQueryer(queryer Queryer, directory string) (*CachingQueryer, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create migrations cache directory: %w", err)
+	}
+
+	return &CachingQueryer{
+		queryer:   queryer,
+		directory: directory,
+	}, nil
+}
+
+func (q *CachingQueryer) filePath(msIndex iotago.MilestoneIndex) string {
+	return filepath.Join(q.directory, fmt.Sprintf("%d.json", msIndex))
+}
+
+// load returns the cached migrations of the legacy milestone and whether they were cached.
+// Unreadable cache files are treated as not cached, so they get overwritten by the next query.
+func (q *CachingQueryer) load(msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, bool) {
+	migrated := []*iotago.MigratedFundsEntry{}
+	if err := ioutils.ReadJSONFromFile(q.filePath(msIndex), &migrated); err != nil {
+		return nil, false
+	}
+
+	return migrated, true
+}
+
+// store caches the migrations of the legacy milestone.
+func (q *CachingQueryer) store(msIndex iotago.MilestoneIndex, migrated []*iotago.MigratedFundsEntry) error {
+	if migrated == nil {
+		migrated = []*iotago.MigratedFundsEntry{}
+	}
+
+	// malformed responses of the legacy node must never be cached
+	for _, entry := range migrated {
+		if err := VerifyMigratedFundsEntry(entry); err != nil {
+			return fmt.Errorf("unable to cache migrations of legacy milestone %d: %w", msIndex, err)
+		}
+	}
+
+	if err := ioutils.WriteJSONToFile(q.filePath(msIndex), migrated, 0600); err != nil {
+		return fmt.Errorf("unable to cache migrations of legacy milestone %d: %w", msIndex, err)
+	}
+
+	return nil
+}
+
+// QueryMigratedFunds returns the migrations confirmed by the legacy milestone with the given index.
+// The legacy node is only queried if the migrations are not cached yet.
+func (q *CachingQueryer) QueryMigratedFunds(ctx context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	if migrated, cached := q.load(msIndex); cached {
+		return migrated, nil
+	}
+
+	migrated, err := q.queryer.QueryMigratedFunds(ctx, msIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.store(msIndex, migrated); err != nil {
+		return nil, err
+	}
+
+	return migrated, nil
+}
+
+// QueryNextMigratedFunds queries the next existing migrations starting from milestone index startIndex.
+// Cached legacy milestones are skipped, the legacy node is only queried starting from the first milestone that is not cached.
+// If there are currently no more migrations, it returns the latest milestone index that was checked.
+func (q *CachingQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+
+	index := startIndex
+	for {
+		migrated, cached := q.load(index)
+		if !cached {
+			break
+		}
+
+		if len(migrated) > 0 {
+			return index, migrated, nil
+		}
+		index++
+	}
+
+	msIndex, migrated, err := q.queryer.QueryNextMigratedFunds(ctx, index)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// all legacy milestones before the returned one were checked and contain no migrations
+	for emptyIndex := index; emptyIndex < msIndex; emptyIndex++ {
+		if err := q.store(emptyIndex, nil); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	// the returned milestone is only cached if it was checked, which is not the case
+	// if the queryer returned the index before the start index because nothing new was found.
+	if msIndex >= index {
+		if err := q.store(msIndex, migrated); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if msIndex < index && index > startIndex {
+		// nothing new was found, but the cached milestones were checked
+		return index - 1, nil, nil
+	}
+
+	return msIndex, migrated, nil
+}
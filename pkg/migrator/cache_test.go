@@ -0,0 +1,65 @@
+package migrator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// countingQueryer counts the queries that reach the legacy node.
+type countingQueryer struct {
+	mockQueryer
+	queries int
+}
+
+func (q *countingQueryer) QueryMigratedFunds(ctx context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	q.queries++
+
+	return q.mockQueryer.QueryMigratedFunds(ctx, msIndex)
+}
+
+func (q *countingQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	q.queries++
+
+	return q.mockQueryer.QueryNextMigratedFunds(ctx, startIndex)
+}
+
+func TestCachingQueryer(t *testing.T) {
+	dir := t.TempDir()
+
+	queryer := &countingQueryer{}
+	cache, err := migrator.NewCachingQueryer(queryer, dir)
+	require.NoError(t, err)
+
+	msIndex, entries, err := cache.QueryNextMigratedFunds(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, serviceTests.migratedAt, msIndex)
+	require.Equal(t, serviceTests.entries, entries)
+	require.Equal(t, 1, queryer.queries)
+
+	// a restarted migrator reads the already fetched milestones from disk
+	restartedQueryer := &countingQueryer{}
+	restarted, err := migrator.NewCachingQueryer(restartedQueryer, dir)
+	require.NoError(t, err)
+
+	msIndex, entries, err = restarted.QueryNextMigratedFunds(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, serviceTests.migratedAt, msIndex)
+	require.Equal(t, serviceTests.entries, entries)
+
+	entries, err = restarted.QueryMigratedFunds(context.Background(), 1)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.Equal(t, 0, restartedQueryer.queries)
+
+	// milestones after the cached ones are queried from the legacy node
+	msIndex, entries, err = restarted.QueryNextMigratedFunds(context.Background(), serviceTests.migratedAt+1)
+	require.NoError(t, err)
+	require.Equal(t, serviceTests.migratedAt, msIndex)
+	require.Empty(t, entries)
+	require.Equal(t, 1, restartedQueryer.queries)
+}
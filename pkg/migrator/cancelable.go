@@ -0,0 +1,75 @@
+package migrator
+
+import (
+	"context"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// UncancelableQueryer defines the interface of queryers that can't be canceled, e.g. the validator of the legacy node,
+// which only gives up on a hung legacy node once the timeout of its HTTP client elapsed.
+type UncancelableQueryer interface {
+	QueryMigratedFunds(iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error)
+	QueryNextMigratedFunds(iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error)
+}
+
+// CancelableQueryer is a Queryer that stops waiting for an UncancelableQueryer once the context of the query is done.
+// The abandoned query keeps running in the background until it returns, its result is discarded,
+// but the caller, e.g. the shutdown of the migrator, is never blocked by it.
+type CancelableQueryer struct {
+	queryer UncancelableQueryer
+}
+
+// NewCancelableQueryer creates a new CancelableQueryer.
+func NewCancelableQueryer(queryer UncancelableQueryer) *CancelableQueryer {
+	return &CancelableQueryer{queryer: queryer}
+}
+
+// queryResult is the result of a query that runs in the background.
+type queryResult struct {
+	msIndex  iotago.MilestoneIndex
+	migrated []*iotago.MigratedFundsEntry
+	err      error
+}
+
+// query runs the query in the background and waits until it returns or the context is done.
+func (q *CancelableQueryer) query(ctx context.Context, query func() *queryResult) *queryResult {
+	if err := ctx.Err(); err != nil {
+		return &queryResult{err: err}
+	}
+
+	// the channel is buffered, so that an abandoned query doesn't leak its goroutine
+	done := make(chan *queryResult, 1)
+	go func() {
+		done <- query()
+	}()
+
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return &queryResult{err: ctx.Err()}
+	}
+}
+
+// QueryMigratedFunds returns the migrations confirmed by the legacy milestone with the given index.
+func (q *CancelableQueryer) QueryMigratedFunds(ctx context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	result := q.query(ctx, func() *queryResult {
+		migrated, err := q.queryer.QueryMigratedFunds(msIndex)
+
+		return &queryResult{migrated: migrated, err: err}
+	})
+
+	return result.migrated, result.err
+}
+
+// QueryNextMigratedFunds queries the next existing migrations starting from milestone index startIndex.
+func (q *CancelableQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	result := q.query(ctx, func() *queryResult {
+		msIndex, migrated, err := q.queryer.QueryNextMigratedFunds(startIndex)
+
+		return &queryResult{msIndex: msIndex, migrated: migrated, err: err}
+	})
+
+	return result.msIndex, result.migrated, result.err
+}
@@ -0,0 +1,89 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// hungQueryer is a legacy node that never answers until it is released, its queries can't be canceled.
+type hungQueryer struct {
+	release chan struct{}
+}
+
+func (q *hungQueryer) QueryMigratedFunds(msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	<-q.release
+
+	return mockQueryer{}.QueryMigratedFunds(context.Background(), msIndex)
+}
+
+func (q *hungQueryer) QueryNextMigratedFunds(startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	<-q.release
+
+	return mockQueryer{}.QueryNextMigratedFunds(context.Background(), startIndex)
+}
+
+func TestCancelableQueryer(t *testing.T) {
+	hung := &hungQueryer{release: make(chan struct{})}
+	queryer := migrator.NewCancelableQueryer(hung)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := queryer.QueryMigratedFunds(ctx, serviceTests.migratedAt)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, _, err = queryer.QueryNextMigratedFunds(ctx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the results of a legacy node that answers are passed through
+	close(hung.release)
+	migrated, err := queryer.QueryMigratedFunds(context.Background(), serviceTests.migratedAt)
+	require.NoError(t, err)
+	require.Equal(t, serviceTests.entries, migrated)
+
+	msIndex, migrated, err := queryer.QueryNextMigratedFunds(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, serviceTests.migratedAt, msIndex)
+	require.Equal(t, serviceTests.entries, migrated)
+}
+
+func TestServiceStopsWithHungLegacyNode(t *testing.T) {
+	hung := &hungQueryer{release: make(chan struct{})}
+	defer close(hung.release)
+
+	s := migrator.NewService(migrator.NewCancelableQueryer(hung), filepath.Join(t.TempDir(), "migrator.state"), len(serviceTests.entries))
+	msIndex := serviceTests.migratedAt
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errorsCount := 0
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.Start(ctx, func(err error) bool {
+			errorsCount++
+
+			return true
+		})
+	}()
+
+	// the service stops although the legacy node never answers, the canceled query is not reported as an error
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "service did not stop")
+	}
+	require.Zero(t, errorsCount)
+	require.Nil(t, s.Receipt(context.Background()))
+}
@@ -0,0 +1,187 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// CircuitStateClosed is the state of the circuit breaker in which all queries are passed to the legacy node.
+	CircuitStateClosed = "closed"
+	// CircuitStateOpen is the state of the circuit breaker in which all queries fail fast.
+	CircuitStateOpen = "open"
+	// CircuitStateHalfOpen is the state of the circuit breaker in which a single query probes whether the legacy node recovered.
+	CircuitStateHalfOpen = "halfOpen"
+)
+
+var (
+	// ErrCircuitOpen is returned when the legacy node is not queried, because the circuit breaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// CircuitStateCaller is used to signal a state change of the circuit breaker.
+func CircuitStateCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(state string))(params[0].(string))
+}
+
+// CircuitBreakerEvents are the events issued by the CircuitBreakerQueryer.
+type CircuitBreakerEvents struct {
+	// StateChanged is triggered when the circuit breaker changes its state.
+	StateChanged *events.Event
+}
+
+// CircuitBreakerStatus is the current status of the circuit breaker.
+type CircuitBreakerStatus struct {
+	// the state of the circuit breaker.
+	State string
+	// the amount of consecutive failed queries.
+	ConsecutiveFailures int
+	// the time the circuit breaker was opened the last time.
+	OpenedTime time.Time
+	// the amount of times the circuit breaker was opened since it was created.
+	OpenedCount uint64
+}
+
+// CircuitBreakerQueryer is a Queryer that stops querying the legacy node after repeated failures.
+// After failureThreshold consecutive failed queries the circuit opens and all queries fail fast with ErrCircuitOpen.
+// Once the open period passed, a single query probes the legacy node, the circuit closes again if it succeeds.
+// Critical errors are caused by the returned migrations and not by the availability of the legacy node, so they are not counted.
+type CircuitBreakerQueryer struct {
+	Events *CircuitBreakerEvents
+
+	// the queryer used while the circuit is closed.
+	queryer Queryer
+	// the amount of consecutive failed queries that open the circuit.
+	failureThreshold int
+	// the period after which an open circuit lets a query probe the legacy node.
+	openPeriod time.Duration
+
+	lock                syncutils.Mutex
+	state               string
+	consecutiveFailures int
+	openedTime          time.Time
+	openedCount         uint64
+}
+
+// NewCircuitBreakerQueryer creates a new CircuitBreakerQueryer.
+func NewCircuitBreakerQueryer(queryer Queryer, failureThreshold int, openPeriod time.Duration) *CircuitBreakerQueryer {
+	return &CircuitBreakerQueryer{
+		Events: &CircuitBreakerEvents{
+			StateChanged: events.NewEvent(CircuitStateCaller),
+		},
+		queryer:          queryer,
+		failureThreshold: failureThreshold,
+		openPeriod:       openPeriod,
+		state:            CircuitStateClosed,
+	}
+}
+
+// Status returns the current status of the circuit breaker.
+func (q *CircuitBreakerQueryer) Status() *CircuitBreakerStatus {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return &CircuitBreakerStatus{
+		State:               q.state,
+		ConsecutiveFailures: q.consecutiveFailures,
+		OpenedTime:          q.openedTime,
+		OpenedCount:         q.openedCount,
+	}
+}
+
+// allow checks whether the legacy node may be queried and moves an open circuit to half-open once the open period passed.
+func (q *CircuitBreakerQueryer) allow() error {
+	q.lock.Lock()
+
+	switch q.state {
+	case CircuitStateOpen:
+		if retryTime := q.openedTime.Add(q.openPeriod); time.Now().Before(retryTime) {
+			q.lock.Unlock()
+
+			return fmt.Errorf("%w: retrying the legacy node in %v", ErrCircuitOpen, time.Until(retryTime).Truncate(time.Second))
+		}
+		q.state = CircuitStateHalfOpen
+		q.lock.Unlock()
+
+		q.Events.StateChanged.Trigger(CircuitStateHalfOpen)
+
+		return nil
+
+	case CircuitStateHalfOpen:
+		q.lock.Unlock()
+
+		// only a single probe is allowed at the same time
+		return fmt.Errorf("%w: the legacy node is being probed", ErrCircuitOpen)
+
+	default:
+		q.lock.Unlock()
+
+		return nil
+	}
+}
+
+// record updates the state of the circuit breaker with the result of a query with the given context.
+func (q *CircuitBreakerQueryer) record(ctx context.Context, err error) {
+	q.lock.Lock()
+
+	previousState := q.state
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// a canceled query says nothing about the legacy node, a canceled probe is retried by the next query
+		if q.state == CircuitStateHalfOpen {
+			q.state = CircuitStateOpen
+		}
+
+	case err == nil || common.IsCriticalError(err) != nil:
+		q.consecutiveFailures = 0
+		q.state = CircuitStateClosed
+
+	default:
+		q.consecutiveFailures++
+		if q.state == CircuitStateHalfOpen || q.consecutiveFailures >= q.failureThreshold {
+			q.openedTime = time.Now()
+			q.openedCount++
+			q.state = CircuitStateOpen
+		}
+	}
+	state := q.state
+	q.lock.Unlock()
+
+	// the event is triggered without holding the lock, so that handlers can query the status
+	if state != previousState {
+		q.Events.StateChanged.Trigger(state)
+	}
+}
+
+// QueryMigratedFunds returns the migrations confirmed by the legacy milestone with the given index.
+func (q *CircuitBreakerQueryer) QueryMigratedFunds(ctx context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	if err := q.allow(); err != nil {
+		return nil, err
+	}
+
+	migrated, err := q.queryer.QueryMigratedFunds(ctx, msIndex)
+	q.record(ctx, err)
+
+	return migrated, err
+}
+
+// QueryNextMigratedFunds queries the next existing migrations starting from milestone index startIndex.
+func (q *CircuitBreakerQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	if err := q.allow(); err != nil {
+		return 0, nil, err
+	}
+
+	msIndex, migrated, err := q.queryer.QueryNextMigratedFunds(ctx, startIndex)
+	q.record(ctx, err)
+
+	return msIndex, migrated, err
+}
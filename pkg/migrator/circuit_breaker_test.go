@@ -0,0 +1,105 @@
+package migrator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// failingQueryer returns the given error for all queries.
+type failingQueryer struct {
+	mockQueryer
+	err     error
+	queries int
+}
+
+func (q *failingQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	q.queries++
+	if q.err != nil {
+		return 0, nil, q.err
+	}
+
+	return q.mockQueryer.QueryNextMigratedFunds(ctx, startIndex)
+}
+
+func TestCircuitBreakerQueryer(t *testing.T) {
+	const openPeriod = 100 * time.Millisecond
+
+	errUnavailable := errors.New("legacy node unavailable")
+	queryer := &failingQueryer{err: errUnavailable}
+	circuitBreaker := migrator.NewCircuitBreakerQueryer(queryer, 2, openPeriod)
+
+	var states []string
+	circuitBreaker.Events.StateChanged.Hook(events.NewClosure(func(state string) {
+		states = append(states, state)
+	}))
+
+	// the circuit opens after the threshold of consecutive failures
+	for i := 0; i < 2; i++ {
+		_, _, err := circuitBreaker.QueryNextMigratedFunds(context.Background(), 1)
+		require.ErrorIs(t, err, errUnavailable)
+	}
+	require.Equal(t, migrator.CircuitStateOpen, circuitBreaker.Status().State)
+
+	// queries fail fast while the circuit is open
+	_, _, err := circuitBreaker.QueryNextMigratedFunds(context.Background(), 1)
+	require.ErrorIs(t, err, migrator.ErrCircuitOpen)
+	require.Equal(t, 2, queryer.queries)
+
+	// a failed probe opens the circuit again
+	time.Sleep(openPeriod)
+	_, _, err = circuitBreaker.QueryNextMigratedFunds(context.Background(), 1)
+	require.ErrorIs(t, err, errUnavailable)
+	require.Equal(t, migrator.CircuitStateOpen, circuitBreaker.Status().State)
+	require.EqualValues(t, 2, circuitBreaker.Status().OpenedCount)
+
+	// a successful probe closes the circuit
+	queryer.err = nil
+	time.Sleep(openPeriod)
+	msIndex, migrated, err := circuitBreaker.QueryNextMigratedFunds(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, serviceTests.migratedAt, msIndex)
+	require.Equal(t, serviceTests.entries, migrated)
+	require.Equal(t, migrator.CircuitStateClosed, circuitBreaker.Status().State)
+	require.Zero(t, circuitBreaker.Status().ConsecutiveFailures)
+
+	// critical errors are not caused by the availability of the legacy node
+	queryer.err = common.CriticalError(errors.New("invalid migrations"))
+	for i := 0; i < 3; i++ {
+		_, _, err = circuitBreaker.QueryNextMigratedFunds(context.Background(), 1)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, migrator.ErrCircuitOpen)
+	}
+	require.Equal(t, migrator.CircuitStateClosed, circuitBreaker.Status().State)
+
+	require.Equal(t, []string{
+		migrator.CircuitStateOpen,
+		migrator.CircuitStateHalfOpen,
+		migrator.CircuitStateOpen,
+		migrator.CircuitStateHalfOpen,
+		migrator.CircuitStateClosed,
+	}, states)
+}
+
+func TestCircuitBreakerQueryerCanceled(t *testing.T) {
+	queryer := &failingQueryer{}
+	circuitBreaker := migrator.NewCircuitBreakerQueryer(queryer, 1, time.Hour)
+
+	// a query that was canceled says nothing about the availability of the legacy node
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	queryer.err = ctx.Err()
+
+	_, _, err := circuitBreaker.QueryNextMigratedFunds(ctx, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, migrator.CircuitStateClosed, circuitBreaker.Status().State)
+	require.Zero(t, circuitBreaker.Status().ConsecutiveFailures)
+}
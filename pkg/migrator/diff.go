@@ -0,0 +1,103 @@
+package migrator
+
+import (
+	"bytes"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// DifferenceOnlyLeft is a migration that only exists in the left source.
+	DifferenceOnlyLeft = "onlyLeft"
+	// DifferenceOnlyRight is a migration that only exists in the right source.
+	DifferenceOnlyRight = "onlyRight"
+	// DifferenceDeposit is a migration whose deposit differs between the sources.
+	DifferenceDeposit = "deposit"
+	// DifferenceAddress is a migration whose address differs between the sources.
+	DifferenceAddress = "address"
+	// DifferenceDuplicateLeft is a migration that exists several times in the left source.
+	DifferenceDuplicateLeft = "duplicateLeft"
+	// DifferenceDuplicateRight is a migration that exists several times in the right source.
+	DifferenceDuplicateRight = "duplicateRight"
+)
+
+// MigrationDifference is a migration that differs between two sources.
+type MigrationDifference struct {
+	// the tail transaction hash of the migration.
+	TailTransactionHash iotago.LegacyTailTransactionHash
+	// the kind of the difference.
+	Kind string
+	// the migration of the left source, nil if it only exists in the right source.
+	Left *iotago.MigratedFundsEntry
+	// the migration of the right source, nil if it only exists in the left source.
+	Right *iotago.MigratedFundsEntry
+}
+
+// MigrationsDiff is the entry by entry comparison of the migrations of a legacy milestone from two sources.
+type MigrationsDiff struct {
+	// the amount of migrations in the left source.
+	LeftCount int
+	// the amount of migrations in the right source.
+	RightCount int
+	// the amount of migrations that are equal in both sources.
+	MatchingCount int
+	// the differences ordered by tail transaction hash.
+	Differences []*MigrationDifference
+}
+
+// Equal returns whether both sources contain the same migrations.
+func (d *MigrationsDiff) Equal() bool {
+	return len(d.Differences) == 0
+}
+
+// DiffMigratedFunds compares the migrations of two sources entry by entry, independent of their order.
+func DiffMigratedFunds(left []*iotago.MigratedFundsEntry, right []*iotago.MigratedFundsEntry) *MigrationsDiff {
+	diff := &MigrationsDiff{
+		LeftCount:   len(left),
+		RightCount:  len(right),
+		Differences: make([]*MigrationDifference, 0),
+	}
+
+	left = sortedMigratedFunds(left)
+	right = sortedMigratedFunds(right)
+
+	var l, r int
+	for l < len(left) || r < len(right) {
+		switch {
+		case l > 0 && l < len(left) && left[l].TailTransactionHash == left[l-1].TailTransactionHash:
+			diff.Differences = append(diff.Differences, &MigrationDifference{TailTransactionHash: left[l].TailTransactionHash, Kind: DifferenceDuplicateLeft, Left: left[l]})
+			l++
+
+		case r > 0 && r < len(right) && right[r].TailTransactionHash == right[r-1].TailTransactionHash:
+			diff.Differences = append(diff.Differences, &MigrationDifference{TailTransactionHash: right[r].TailTransactionHash, Kind: DifferenceDuplicateRight, Right: right[r]})
+			r++
+
+		case r == len(right) || (l < len(left) && compareTailTransactionHashes(left[l], right[r]) < 0):
+			diff.Differences = append(diff.Differences, &MigrationDifference{TailTransactionHash: left[l].TailTransactionHash, Kind: DifferenceOnlyLeft, Left: left[l]})
+			l++
+
+		case l == len(left) || compareTailTransactionHashes(left[l], right[r]) > 0:
+			diff.Differences = append(diff.Differences, &MigrationDifference{TailTransactionHash: right[r].TailTransactionHash, Kind: DifferenceOnlyRight, Right: right[r]})
+			r++
+
+		default:
+			switch {
+			case left[l].Deposit != right[r].Deposit:
+				diff.Differences = append(diff.Differences, &MigrationDifference{TailTransactionHash: left[l].TailTransactionHash, Kind: DifferenceDeposit, Left: left[l], Right: right[r]})
+			case !left[l].Address.Equal(right[r].Address):
+				diff.Differences = append(diff.Differences, &MigrationDifference{TailTransactionHash: left[l].TailTransactionHash, Kind: DifferenceAddress, Left: left[l], Right: right[r]})
+			default:
+				diff.MatchingCount++
+			}
+			l++
+			r++
+		}
+	}
+
+	return diff
+}
+
+// compareTailTransactionHashes compares the tail transaction hashes of two migrations.
+func compareTailTransactionHashes(a *iotago.MigratedFundsEntry, b *iotago.MigratedFundsEntry) int {
+	return bytes.Compare(a.TailTransactionHash[:], b.TailTransactionHash[:])
+}
@@ -0,0 +1,51 @@
+package migrator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestDiffMigratedFunds(t *testing.T) {
+	entry := func(hash byte, address byte, deposit uint64) *iotago.MigratedFundsEntry {
+		return &iotago.MigratedFundsEntry{
+			TailTransactionHash: iotago.LegacyTailTransactionHash{hash},
+			Address:             &iotago.Ed25519Address{address},
+			Deposit:             deposit,
+		}
+	}
+
+	// the order of the migrations doesn't matter
+	diff := migrator.DiffMigratedFunds(serviceTests.entries, []*iotago.MigratedFundsEntry{serviceTests.entries[2], serviceTests.entries[0], serviceTests.entries[1]})
+	require.True(t, diff.Equal())
+	require.Equal(t, len(serviceTests.entries), diff.MatchingCount)
+
+	left := []*iotago.MigratedFundsEntry{entry(5, 5, 100), entry(1, 1, 100), entry(2, 2, 100), entry(3, 3, 100), entry(3, 3, 100)}
+	right := []*iotago.MigratedFundsEntry{entry(2, 2, 200), entry(3, 9, 100), entry(4, 4, 100), entry(5, 5, 100)}
+
+	diff = migrator.DiffMigratedFunds(left, right)
+	require.False(t, diff.Equal())
+	require.Equal(t, 5, diff.LeftCount)
+	require.Equal(t, 4, diff.RightCount)
+	require.Equal(t, 1, diff.MatchingCount)
+
+	kinds := make([]string, 0, len(diff.Differences))
+	for _, difference := range diff.Differences {
+		kinds = append(kinds, difference.Kind)
+	}
+	require.Equal(t, []string{
+		migrator.DifferenceOnlyLeft,
+		migrator.DifferenceDeposit,
+		migrator.DifferenceAddress,
+		migrator.DifferenceDuplicateLeft,
+		migrator.DifferenceOnlyRight,
+	}, kinds)
+
+	require.Equal(t, iotago.LegacyTailTransactionHash{2}, diff.Differences[1].TailTransactionHash)
+	require.EqualValues(t, 100, diff.Differences[1].Left.Deposit)
+	require.EqualValues(t, 200, diff.Differences[1].Right.Deposit)
+	require.Nil(t, diff.Differences[4].Left)
+}
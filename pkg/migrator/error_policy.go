@@ -0,0 +1,114 @@
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+)
+
+const (
+	// ErrorClassNetwork are all errors that are not marked as critical, e.g. QueryErrors of failed API calls to the legacy node.
+	ErrorClassNetwork = "network"
+	// ErrorClassValidation are errors caused by migrations or states that failed the sanity checks.
+	// Invalid stop indexes, invalid states and a legacy network mismatch are returned while querying the legacy node,
+	// so the query is repeated after an alert. Invalid migrations, migrations that were already included and receipts
+	// that don't fit into a milestone can't be skipped, so they always stop the service, see validationStage.
+	ErrorClassValidation = "validation"
+	// ErrorClassCritical are all other critical errors the service can't recover from.
+	ErrorClassCritical = "critical"
+)
+
+const (
+	// ErrorActionRetry logs the error and retries after the query cooldown period.
+	ErrorActionRetry = "retry"
+	// ErrorActionPause logs the error and retries after the pause period.
+	ErrorActionPause = "pause"
+	// ErrorActionAlert raises an alert and retries after the query cooldown period.
+	ErrorActionAlert = "alert"
+	// ErrorActionTerminate terminates the service.
+	ErrorActionTerminate = "terminate"
+)
+
+var (
+	// ErrUnknownErrorAction is returned when an unknown action is configured for an error class.
+	ErrUnknownErrorAction = errors.New("unknown error action")
+	// ErrUnsupportedErrorAction is returned when an action is configured for an error class that doesn't support it.
+	ErrUnsupportedErrorAction = errors.New("unsupported error action")
+
+	// validationErrors are the errors that are classified as validation errors.
+	validationErrors = []error{ErrInvalidMigrations, ErrInvalidState, ErrReceiptTooLarge, ErrInvalidStopIndex, ErrTailTransactionHashIncluded, ErrLegacyNetworkMismatch}
+)
+
+// ErrorClassCaller is used to signal an error together with its class.
+func ErrorClassCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(class string, err error))(params[0].(string), params[1].(error))
+}
+
+// ErrorClass returns the class of an error encountered by the service.
+func ErrorClass(err error) string {
+	for _, validationErr := range validationErrors {
+		if errors.Is(err, validationErr) {
+			return ErrorClassValidation
+		}
+	}
+
+	if common.IsCriticalError(err) != nil {
+		return ErrorClassCritical
+	}
+
+	return ErrorClassNetwork
+}
+
+// ErrorPolicy maps the error classes to the actions that are taken if an error of that class is encountered.
+type ErrorPolicy map[string]string
+
+// DefaultErrorPolicy returns the error policy that retries network errors and terminates on all other errors.
+func DefaultErrorPolicy() ErrorPolicy {
+	return ErrorPolicy{
+		ErrorClassNetwork:    ErrorActionRetry,
+		ErrorClassValidation: ErrorActionTerminate,
+		ErrorClassCritical:   ErrorActionTerminate,
+	}
+}
+
+// NewErrorPolicy creates a new ErrorPolicy and checks that all actions are known.
+// Classes without an action use the action of the default error policy.
+func NewErrorPolicy(actions map[string]string) (ErrorPolicy, error) {
+	policy := DefaultErrorPolicy()
+
+	for class, action := range actions {
+		if _, exists := policy[class]; !exists {
+			return nil, fmt.Errorf("unknown error class: %s", class)
+		}
+
+		switch action {
+		case ErrorActionRetry, ErrorActionPause, ErrorActionAlert, ErrorActionTerminate:
+		default:
+			return nil, fmt.Errorf("%w: %s (error class %s)", ErrUnknownErrorAction, action, class)
+		}
+
+		// invalid migrations can't be skipped and stop the service anyway, so the alert is the only alternative
+		if class == ErrorClassValidation && action != ErrorActionAlert && action != ErrorActionTerminate {
+			return nil, fmt.Errorf("%w: %s (error class %s only supports %s and %s)", ErrUnsupportedErrorAction, action, class, ErrorActionAlert, ErrorActionTerminate)
+		}
+
+		policy[class] = action
+	}
+
+	return policy, nil
+}
+
+// Action returns the class of the error and the action that should be taken.
+func (p ErrorPolicy) Action(err error) (string, string) {
+	class := ErrorClass(err)
+
+	action, exists := p[class]
+	if !exists {
+		return class, ErrorActionTerminate
+	}
+
+	return class, action
+}
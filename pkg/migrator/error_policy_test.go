@@ -0,0 +1,61 @@
+package migrator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+)
+
+func TestErrorClass(t *testing.T) {
+	require.Equal(t, migrator.ErrorClassNetwork, migrator.ErrorClass(errors.New("connection refused")))
+	require.Equal(t, migrator.ErrorClassNetwork, migrator.ErrorClass(common.SoftError(errors.New("timeout"))))
+	require.Equal(t, migrator.ErrorClassCritical, migrator.ErrorClass(common.CriticalError(errors.New("disk full"))))
+	require.Equal(t, migrator.ErrorClassValidation, migrator.ErrorClass(common.CriticalError(migrator.ErrInvalidMigrations)))
+	require.Equal(t, migrator.ErrorClassValidation, migrator.ErrorClass(common.SoftError(migrator.ErrInvalidState)))
+}
+
+func TestNewErrorPolicy(t *testing.T) {
+	policy, err := migrator.NewErrorPolicy(map[string]string{
+		migrator.ErrorClassNetwork: migrator.ErrorActionAlert,
+	})
+	require.NoError(t, err)
+
+	class, action := policy.Action(errors.New("connection refused"))
+	require.Equal(t, migrator.ErrorClassNetwork, class)
+	require.Equal(t, migrator.ErrorActionAlert, action)
+
+	// classes that are not configured use the default action
+	class, action = policy.Action(common.CriticalError(errors.New("disk full")))
+	require.Equal(t, migrator.ErrorClassCritical, class)
+	require.Equal(t, migrator.ErrorActionTerminate, action)
+
+	_, err = migrator.NewErrorPolicy(map[string]string{
+		migrator.ErrorClassValidation: "ignore",
+	})
+	require.ErrorIs(t, err, migrator.ErrUnknownErrorAction)
+
+	_, err = migrator.NewErrorPolicy(map[string]string{
+		"disk": migrator.ErrorActionRetry,
+	})
+	require.Error(t, err)
+
+	// invalid migrations can't be retried, so validation errors are only alerted or terminate the service
+	policy, err = migrator.NewErrorPolicy(map[string]string{
+		migrator.ErrorClassValidation: migrator.ErrorActionAlert,
+	})
+	require.NoError(t, err)
+	class, action = policy.Action(common.CriticalError(migrator.ErrInvalidStopIndex))
+	require.Equal(t, migrator.ErrorClassValidation, class)
+	require.Equal(t, migrator.ErrorActionAlert, action)
+
+	for _, unsupported := range []string{migrator.ErrorActionRetry, migrator.ErrorActionPause} {
+		_, err = migrator.NewErrorPolicy(map[string]string{
+			migrator.ErrorClassValidation: unsupported,
+		})
+		require.ErrorIs(t, err, migrator.ErrUnsupportedErrorAction, unsupported)
+	}
+}
@@ -0,0 +1,90 @@
+package migrator
+
+import (
+	"fmt"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// StageFetch is the stage that queries the migrations from the legacy node.
+	StageFetch = "fetch"
+	// StageValidation is the stage that checks the fetched migrations and creates the receipts.
+	StageValidation = "validation"
+	// StageInitState is the initialization of the state when the service is started.
+	StageInitState = "initState"
+	// StageUpdateState is the application of a migration result to the state when a receipt is created.
+	StageUpdateState = "updateState"
+	// StagePersistState is the persistence of the state to the state file.
+	StagePersistState = "persistState"
+	// StagePersistCheckpoint is the persistence of the fetch progress to the checkpoint file.
+	StagePersistCheckpoint = "persistCheckpoint"
+)
+
+// QueryError is returned when the migrations could not be queried from the legacy node.
+// errors.Is matches a QueryError with an empty stage and index zero against all QueryErrors,
+// otherwise the stage and the index need to be equal.
+type QueryError struct {
+	// the index of the legacy milestone the query started at.
+	Index iotago.MilestoneIndex
+	// the stage of the service the error occurred in.
+	Stage string
+	// the error returned by the legacy node.
+	Err error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("failed to query migrations at legacy milestone %d (%s): %s", e.Index, e.Stage, e.Err)
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+func (e *QueryError) Is(target error) bool {
+	t, ok := target.(*QueryError)
+	if !ok {
+		return false
+	}
+
+	return matchesIndexAndStage(t.Index, t.Stage, e.Index, e.Stage)
+}
+
+// StateError is returned when the state of the service is invalid or could not be loaded or persisted.
+// errors.Is matches a StateError with an empty stage and index zero against all StateErrors,
+// otherwise the stage and the index need to be equal.
+type StateError struct {
+	// the latest legacy milestone index of the state.
+	Index iotago.MilestoneIndex
+	// the stage of the service the error occurred in.
+	Stage string
+	// the cause of the error.
+	Err error
+}
+
+func (e *StateError) Error() string {
+	return fmt.Sprintf("migrator state at legacy milestone %d (%s): %s", e.Index, e.Stage, e.Err)
+}
+
+func (e *StateError) Unwrap() error {
+	return e.Err
+}
+
+func (e *StateError) Is(target error) bool {
+	t, ok := target.(*StateError)
+	if !ok {
+		return false
+	}
+
+	return matchesIndexAndStage(t.Index, t.Stage, e.Index, e.Stage)
+}
+
+// matchesIndexAndStage checks whether the index and the stage of an error match the ones of the target.
+// Empty fields of the target match all values.
+func matchesIndexAndStage(targetIndex iotago.MilestoneIndex, targetStage string, index iotago.MilestoneIndex, stage string) bool {
+	if targetIndex != 0 && targetIndex != index {
+		return false
+	}
+
+	return targetStage == "" || targetStage == stage
+}
@@ -0,0 +1,41 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+)
+
+func TestStateErrors(t *testing.T) {
+	s := migrator.NewService(nil, filepath.Join(t.TempDir(), "migrator.state"), migrator.SensibleMaxEntriesCount)
+
+	// the state file does not exist
+	err := s.InitState(context.Background(), nil)
+	var stateErr *migrator.StateError
+	require.ErrorAs(t, err, &stateErr)
+	require.Equal(t, migrator.StageInitState, stateErr.Stage)
+	require.NotErrorIs(t, err, migrator.ErrInvalidState)
+
+	zeroIndex := uint32(0)
+	err = s.InitState(context.Background(), &zeroIndex)
+	require.ErrorIs(t, err, &migrator.StateError{Stage: migrator.StageInitState})
+	require.ErrorIs(t, err, migrator.ErrInvalidState)
+	require.Equal(t, migrator.ErrorClassValidation, migrator.ErrorClass(err))
+}
+
+func TestQueryErrors(t *testing.T) {
+	cause := errors.New("legacy node unreachable")
+	err := &migrator.QueryError{Index: 10, Stage: migrator.StageFetch, Err: cause}
+
+	require.ErrorIs(t, err, cause)
+	require.ErrorIs(t, err, &migrator.QueryError{})
+	require.ErrorIs(t, err, &migrator.QueryError{Index: 10, Stage: migrator.StageFetch})
+	require.NotErrorIs(t, err, &migrator.QueryError{Index: 11})
+	require.NotErrorIs(t, err, &migrator.StateError{})
+	require.Equal(t, migrator.ErrorClassNetwork, migrator.ErrorClass(err))
+}
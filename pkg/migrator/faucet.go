@@ -0,0 +1,184 @@
+package migrator
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/encoding/t5b1"
+	"github.com/iotaledger/iota.go/trinary"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrFaucetQueueFull is returned when too many requested migrations wait to be confirmed by a simulated legacy milestone.
+	ErrFaucetQueueFull = errors.New("too many pending faucet migrations")
+)
+
+// faucetState is the persisted state of a FaucetQueryer.
+type faucetState struct {
+	// the index of the latest simulated legacy milestone.
+	LatestIndex iotago.MilestoneIndex `json:"latestIndex"`
+	// the migrations of the simulated legacy milestones that confirmed requested migrations.
+	Milestones map[iotago.MilestoneIndex][]*iotago.MigratedFundsEntry `json:"milestones"`
+}
+
+// FaucetQueryer is a Queryer serving migrations that are requested via the API, e.g. to test the receipt handling
+// of wallets on private tangles without running a legacy node.
+// The pending requests are confirmed by a simulated legacy milestone as soon as the migrator queries the next migrations.
+// The simulated legacy milestones are persisted, so that the migrator finds the same migrations after a restart.
+type FaucetQueryer struct {
+	mutex syncutils.Mutex
+
+	// the path to the file the simulated legacy milestones are persisted to.
+	filePath string
+	// the maximum amount of migrations confirmed by a simulated legacy milestone.
+	entriesPerMilestone int
+	// the maximum amount of requested migrations that wait to be confirmed.
+	maxPending int
+
+	state   *faucetState
+	pending []*iotago.MigratedFundsEntry
+}
+
+// NewFaucetQueryer creates a new FaucetQueryer for the network with the given name and loads the simulated legacy milestones
+// from the given file. It refuses to start unless the network is one of the given private tangles.
+func NewFaucetQueryer(networkName string, privateNetworkNames []string, filePath string, entriesPerMilestone int, maxPending int) (*FaucetQueryer, error) {
+
+	if err := CheckPrivateNetwork(networkName, privateNetworkNames); err != nil {
+		return nil, err
+	}
+
+	if entriesPerMilestone <= 0 {
+		return nil, fmt.Errorf("%w: entries per milestone must be greater than 0", ErrInvalidSyntheticConfig)
+	}
+
+	state := &faucetState{Milestones: make(map[iotago.MilestoneIndex][]*iotago.MigratedFundsEntry)}
+	if err := ioutils.ReadJSONFromFile(filePath, state); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to load faucet migrations: %w", err)
+	}
+	if state.Milestones == nil {
+		state.Milestones = make(map[iotago.MilestoneIndex][]*iotago.MigratedFundsEntry)
+	}
+
+	return &FaucetQueryer{
+		filePath:            filePath,
+		entriesPerMilestone: entriesPerMilestone,
+		maxPending:          maxPending,
+		state:               state,
+	}, nil
+}
+
+// randomTailTransactionHash returns a random legacy tail transaction hash.
+func randomTailTransactionHash() (iotago.LegacyTailTransactionHash, error) {
+	var hash iotago.LegacyTailTransactionHash
+
+	randomBytes := make([]byte, consts.HashTrinarySize)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return hash, err
+	}
+
+	trits := make(trinary.Trits, consts.HashTrinarySize)
+	for i, b := range randomBytes {
+		trits[i] = int8(b%3) - 1
+	}
+	t5b1.Encode(hash[:], trits)
+
+	return hash, nil
+}
+
+// Request queues a migration of the given deposit to the given address.
+// It returns the migration, which is identified by a random tail transaction hash.
+func (q *FaucetQueryer) Request(address *iotago.Ed25519Address, deposit uint64) (*iotago.MigratedFundsEntry, error) {
+	tailTransactionHash, err := randomTailTransactionHash()
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate tail transaction hash: %w", err)
+	}
+
+	entry := &iotago.MigratedFundsEntry{
+		TailTransactionHash: tailTransactionHash,
+		Address:             address,
+		Deposit:             deposit,
+	}
+
+	// the requested migrations have to pass the same checks as the migrations of a legacy node
+	if err := VerifyMigratedFundsEntry(entry); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if len(q.pending) >= q.maxPending {
+		return nil, fmt.Errorf("%w: %d migrations wait to be confirmed", ErrFaucetQueueFull, len(q.pending))
+	}
+	q.pending = append(q.pending, entry)
+
+	return entry, nil
+}
+
+// Pending returns the amount of requested migrations that wait to be confirmed by a simulated legacy milestone.
+func (q *FaucetQueryer) Pending() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return len(q.pending)
+}
+
+// QueryMigratedFunds returns the requested migrations confirmed by the simulated legacy milestone with the given index.
+func (q *FaucetQueryer) QueryMigratedFunds(_ context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.state.Milestones[msIndex], nil
+}
+
+// QueryNextMigratedFunds returns the requested migrations of the next simulated legacy milestone starting from startIndex.
+// Pending requests are confirmed by a new simulated legacy milestone, which is persisted before it is returned.
+// If there are no pending requests, it returns the latest simulated milestone index.
+func (q *FaucetQueryer) QueryNextMigratedFunds(_ context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	// the simulation continues where the migrator left off
+	if q.state.LatestIndex+1 < startIndex {
+		q.state.LatestIndex = startIndex - 1
+	}
+
+	for index := startIndex; index <= q.state.LatestIndex; index++ {
+		if migrated := q.state.Milestones[index]; len(migrated) > 0 {
+			return index, migrated, nil
+		}
+	}
+
+	if len(q.pending) == 0 {
+		return q.state.LatestIndex, nil, nil
+	}
+
+	count := len(q.pending)
+	if count > q.entriesPerMilestone {
+		count = q.entriesPerMilestone
+	}
+
+	index := q.state.LatestIndex + 1
+	migrated := q.pending[:count]
+	q.state.Milestones[index] = migrated
+	q.state.LatestIndex = index
+
+	// the migrations of a legacy milestone must never change, so they are persisted before the migrator sees them
+	if err := ioutils.WriteJSONToFile(q.filePath, q.state, 0660); err != nil {
+		delete(q.state.Milestones, index)
+		q.state.LatestIndex = index - 1
+
+		return 0, nil, fmt.Errorf("unable to persist faucet migrations: %w", err)
+	}
+	q.pending = q.pending[count:]
+
+	return index, migrated, nil
+}
@@ -0,0 +1,99 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestFaucetQueryer(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "faucet.json")
+
+	q, err := migrator.NewFaucetQueryer(testPrivateNetworkName, []string{testPrivateNetworkName}, filePath, 2, 3)
+	require.NoError(t, err)
+
+	// without requests, no legacy milestones are simulated
+	msIndex, entries, err := q.QueryNextMigratedFunds(context.Background(), 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 9, msIndex)
+	require.Empty(t, entries)
+
+	address := &iotago.Ed25519Address{0x01}
+	requested := make([]*iotago.MigratedFundsEntry, 0, 3)
+	for i := 0; i < 3; i++ {
+		entry, err := q.Request(address, iotago.MinMigratedFundsEntryDeposit+uint64(i))
+		require.NoError(t, err)
+		require.NoError(t, migrator.VerifyMigratedFundsEntry(entry))
+		requested = append(requested, entry)
+	}
+	require.NotEqual(t, requested[0].TailTransactionHash, requested[1].TailTransactionHash)
+
+	_, err = q.Request(address, iotago.MinMigratedFundsEntryDeposit)
+	require.ErrorIs(t, err, migrator.ErrFaucetQueueFull)
+	_, err = q.Request(address, iotago.MinMigratedFundsEntryDeposit-1)
+	require.ErrorIs(t, err, migrator.ErrInvalidMigrations)
+	require.Equal(t, 3, q.Pending())
+
+	// every simulated legacy milestone confirms at most the configured amount of requests
+	msIndex, entries, err = q.QueryNextMigratedFunds(context.Background(), 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, msIndex)
+	require.Equal(t, requested[:2], entries)
+	require.Equal(t, 1, q.Pending())
+
+	msIndex, entries, err = q.QueryNextMigratedFunds(context.Background(), 11)
+	require.NoError(t, err)
+	require.EqualValues(t, 11, msIndex)
+	require.Equal(t, requested[2:], entries)
+
+	msIndex, entries, err = q.QueryNextMigratedFunds(context.Background(), 12)
+	require.NoError(t, err)
+	require.EqualValues(t, 11, msIndex)
+	require.Empty(t, entries)
+
+	// the simulated legacy milestones survive a restart
+	q, err = migrator.NewFaucetQueryer(testPrivateNetworkName, []string{testPrivateNetworkName}, filePath, 2, 3)
+	require.NoError(t, err)
+
+	entries, err = q.QueryMigratedFunds(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, requested[:2], entries)
+
+	msIndex, entries, err = q.QueryNextMigratedFunds(context.Background(), 11)
+	require.NoError(t, err)
+	require.EqualValues(t, 11, msIndex)
+	require.Equal(t, requested[2:], entries)
+}
+
+func TestFaucetQueryerPrivateNetworkOnly(t *testing.T) {
+	tests := []struct {
+		name                string
+		networkName         string
+		privateNetworkNames []string
+		valid               bool
+	}{
+		{name: "private tangle", networkName: "private_tangle1", privateNetworkNames: []string{"other", "private_tangle1"}, valid: true},
+		{name: "unknown network name", networkName: "", privateNetworkNames: []string{""}, valid: false},
+		{name: "not configured", networkName: "private_tangle2", privateNetworkNames: []string{"private_tangle1"}, valid: false},
+		{name: "mainnet", networkName: "iota-mainnet", privateNetworkNames: []string{"iota-mainnet"}, valid: false},
+		{name: "shimmer", networkName: "shimmer", privateNetworkNames: []string{"shimmer"}, valid: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "faucet.json")
+
+			_, err := migrator.NewFaucetQueryer(test.networkName, test.privateNetworkNames, filePath, 2, 3)
+			if test.valid {
+				require.NoError(t, err)
+
+				return
+			}
+			require.ErrorIs(t, err, migrator.ErrNotPrivateNetwork)
+		})
+	}
+}
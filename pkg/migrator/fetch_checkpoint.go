@@ -0,0 +1,142 @@
+package migrator
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// FetchCheckpoint is the intermediate fetch progress of a long catch-up.
+// All legacy milestones after MigratedAtIndex up to and including VerifiedIndex were fetched and contain no migrations.
+// It is persisted separately from the state, because the state only changes when a receipt is sent,
+// so a restart with the state at MigratedAtIndex resumes fetching after VerifiedIndex instead of re-fetching everything.
+type FetchCheckpoint struct {
+	// the legacy milestone index of the latest fetched migrations the checkpoint is based on.
+	MigratedAtIndex iotago.MilestoneIndex `json:"migratedAtIndex"`
+	// the highest legacy milestone index that was verified to contain no migrations.
+	VerifiedIndex iotago.MilestoneIndex `json:"verifiedIndex"`
+}
+
+// loadFetchCheckpoint loads the fetch checkpoint from the given file, it returns nil if the file doesn't exist.
+func loadFetchCheckpoint(filePath string) (*FetchCheckpoint, error) {
+	checkpoint := &FetchCheckpoint{}
+	if err := ioutils.ReadJSONFromFile(filePath, checkpoint); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to load fetch checkpoint: %w", err)
+	}
+
+	return checkpoint, nil
+}
+
+// SetFetchCheckpoint enables checkpoints of the fetch progress, which are persisted to the given file
+// whenever interval legacy milestones without migrations were verified since the previous checkpoint.
+// The checkpoint is loaded by InitState, so SetFetchCheckpoint must be called before InitState.
+func (s *Service) SetFetchCheckpoint(filePath string, interval uint32) {
+	s.fetchCheckpointFilePath = filePath
+	s.fetchCheckpointInterval = interval
+}
+
+// FetchCheckpoint returns the fetch checkpoint the service resumes fetching from, or nil if there is none.
+func (s *Service) FetchCheckpoint() *FetchCheckpoint {
+	return s.fetchCheckpoint
+}
+
+// initFetchCheckpoint loads the fetch checkpoint for the given state.
+// A checkpoint that is based on another legacy milestone than the state is outdated and ignored.
+// If the state is bootstrapped, an existing checkpoint file belongs to a previous migration and is removed.
+func (s *Service) initFetchCheckpoint(state State, bootstrap bool) error {
+	s.fetchCheckpoint = nil
+	if s.fetchCheckpointFilePath == "" {
+		return nil
+	}
+
+	if bootstrap {
+		if err := os.Remove(s.fetchCheckpointFilePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove fetch checkpoint: %w", err)
+		}
+
+		return nil
+	}
+
+	checkpoint, err := loadFetchCheckpoint(s.fetchCheckpointFilePath)
+	if err != nil {
+		return err
+	}
+
+	if checkpoint != nil && checkpoint.MigratedAtIndex == state.LatestMigratedAtIndex && checkpoint.VerifiedIndex > checkpoint.MigratedAtIndex {
+		s.fetchCheckpoint = checkpoint
+	}
+
+	return nil
+}
+
+// resumeIndex returns the legacy milestone index fetching continues at after the legacy milestone with index msIndex.
+// The legacy milestones verified by the fetch checkpoint are skipped.
+func (s *Service) resumeIndex(msIndex iotago.MilestoneIndex) iotago.MilestoneIndex {
+	if checkpoint := s.fetchCheckpoint; checkpoint != nil && checkpoint.MigratedAtIndex == msIndex {
+		return checkpoint.VerifiedIndex + 1
+	}
+
+	return msIndex + 1
+}
+
+// fetchProgress tracks the fetch progress in the validation stage and persists it as checkpoints.
+type fetchProgress struct {
+	// the current progress.
+	checkpoint FetchCheckpoint
+	// the verified index of the latest persisted checkpoint.
+	persistedIndex iotago.MilestoneIndex
+}
+
+// newFetchProgress creates the fetch progress for the given state and the checkpoint the service resumed from.
+func newFetchProgress(state State, resumed *FetchCheckpoint) *fetchProgress {
+	progress := &fetchProgress{
+		checkpoint: FetchCheckpoint{
+			MigratedAtIndex: state.LatestMigratedAtIndex,
+			VerifiedIndex:   state.LatestMigratedAtIndex,
+		},
+		persistedIndex: state.LatestMigratedAtIndex,
+	}
+
+	if resumed != nil {
+		progress.checkpoint = *resumed
+		progress.persistedIndex = resumed.VerifiedIndex
+	}
+
+	return progress
+}
+
+// update applies the fetched migrations of the legacy milestone with index msIndex to the progress.
+// It returns whether the progress advanced far enough since the latest persisted checkpoint.
+func (p *fetchProgress) update(msIndex iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry, interval uint32) bool {
+	if len(migratedFunds) > 0 {
+		// the checkpoint needs to be based on the latest migrations, so they are fetched again after a restart
+		p.checkpoint = FetchCheckpoint{MigratedAtIndex: msIndex, VerifiedIndex: msIndex}
+		p.persistedIndex = msIndex
+
+		return false
+	}
+
+	if msIndex > p.checkpoint.VerifiedIndex {
+		// without migrations, the returned index is the latest legacy milestone that was checked
+		p.checkpoint.VerifiedIndex = msIndex
+	}
+
+	return interval > 0 && p.checkpoint.VerifiedIndex-p.persistedIndex >= interval
+}
+
+// persistFetchCheckpoint writes the current fetch progress to the checkpoint file.
+func (s *Service) persistFetchCheckpoint(progress *fetchProgress) error {
+	checkpoint := progress.checkpoint
+	if err := ioutils.WriteJSONToFile(s.fetchCheckpointFilePath, &checkpoint, 0600); err != nil {
+		return &StateError{Index: checkpoint.MigratedAtIndex, Stage: StagePersistCheckpoint, Err: fmt.Errorf("unable to persist fetch checkpoint: %w", err)}
+	}
+	progress.persistedIndex = checkpoint.VerifiedIndex
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package migrator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestFetchCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	stateFilePath := filepath.Join(dir, "migrator.state")
+	checkpointFilePath := filepath.Join(dir, "migrator_fetch_checkpoint.json")
+
+	q1 := &scriptedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 2, migratedFunds: serviceTests.entries},
+			{stopIndex: 20},
+			{stopIndex: 35},
+			// the legacy node went back in time, which stops the service
+			{stopIndex: 3},
+		},
+	}
+
+	s1 := migrator.NewService(q1, stateFilePath, len(serviceTests.entries))
+	s1.SetFetchCheckpoint(checkpointFilePath, 10)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s1.InitState(context.Background(), &msIndex))
+	require.Nil(t, s1.FetchCheckpoint())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s1.Start(ctx, func(_ error) bool { return false })
+
+	checkpoint := &migrator.FetchCheckpoint{}
+	require.Eventually(t, func() bool {
+		// only the receipt containing the migrations is persisted, the progress without migrations is not part of the state
+		if receipt := s1.Receipt(context.Background()); receipt != nil {
+			require.NoError(t, s1.PersistState(context.Background(), false))
+		}
+
+		return ioutils.ReadJSONFromFile(checkpointFilePath, checkpoint) == nil && checkpoint.VerifiedIndex == 35
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	require.EqualValues(t, serviceTests.migratedAt, checkpoint.MigratedAtIndex)
+
+	// a restart resumes fetching after the verified legacy milestones
+	q2 := &scriptedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 40},
+			{stopIndex: 3},
+		},
+	}
+
+	s2 := migrator.NewService(q2, stateFilePath, len(serviceTests.entries))
+	s2.SetFetchCheckpoint(checkpointFilePath, 10)
+	require.NoError(t, s2.InitState(context.Background(), nil))
+	require.Equal(t, checkpoint, s2.FetchCheckpoint())
+
+	serviceErr := make(chan error, 1)
+	go s2.Start(context.Background(), func(err error) bool {
+		serviceErr <- err
+
+		return false
+	})
+
+	require.Eventually(t, func() bool {
+		s2.Receipt(context.Background())
+
+		return len(serviceErr) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.ErrorIs(t, <-serviceErr, migrator.ErrInvalidStopIndex)
+	require.Equal(t, []iotago.MilestoneIndex{36, 41}, q2.startIndices)
+
+	// a checkpoint that is based on another legacy milestone than the state is ignored
+	require.NoError(t, ioutils.WriteJSONToFile(checkpointFilePath, &migrator.FetchCheckpoint{MigratedAtIndex: 1, VerifiedIndex: 50}, 0600))
+	s3 := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+	s3.SetFetchCheckpoint(checkpointFilePath, 10)
+	require.NoError(t, s3.InitState(context.Background(), nil))
+	require.Nil(t, s3.FetchCheckpoint())
+
+	// bootstrapping removes the checkpoint of a previous migration
+	s4 := migrator.NewService(&mockQueryer{}, filepath.Join(dir, "bootstrap.state"), len(serviceTests.entries))
+	s4.SetFetchCheckpoint(checkpointFilePath, 10)
+	require.NoError(t, s4.InitState(context.Background(), &msIndex))
+	_, err := os.Stat(checkpointFilePath)
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,275 @@
+package migrator
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/encoding/t5b1"
+	"github.com/iotaledger/iota.go/trinary"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// fuzzEntrySize is the amount of bytes a fuzzed migration is decoded from: deposit, address and tail transaction hash.
+const fuzzEntrySize = 8 + iotago.Ed25519AddressBytesLength + iotago.LegacyTailTransactionHashLength
+
+// fuzzEntry encodes a migration the way it is decoded by decodeFuzzEntries.
+func fuzzEntry(deposit uint64, seed byte) []byte {
+	data := make([]byte, fuzzEntrySize)
+	binary.LittleEndian.PutUint64(data, deposit)
+	for i := 8; i < len(data); i++ {
+		data[i] = seed + byte(i)
+	}
+
+	return data
+}
+
+// decodeFuzzEntries decodes the fuzzed migrations.
+// If normalize is set, the tail transaction hashes are turned into valid legacy hashes,
+// so that the fuzzer doesn't need to find valid T5B1 encodings to reach the receipts.
+func decodeFuzzEntries(data []byte, normalize bool) []*iotago.MigratedFundsEntry {
+	entries := make([]*iotago.MigratedFundsEntry, 0, len(data)/fuzzEntrySize)
+	for ; len(data) >= fuzzEntrySize; data = data[fuzzEntrySize:] {
+		address := &iotago.Ed25519Address{}
+		copy(address[:], data[8:])
+
+		entry := &iotago.MigratedFundsEntry{
+			Deposit: binary.LittleEndian.Uint64(data),
+			Address: address,
+		}
+
+		hash := data[8+iotago.Ed25519AddressBytesLength : fuzzEntrySize]
+		if normalize {
+			trits := make(trinary.Trits, t5b1.DecodedLen(len(hash)))
+			for i, b := range hash {
+				value := int(b) % 243
+				for j := 0; j < 5; j++ {
+					trits[i*5+j] = int8(value%3) - 1
+					value /= 3
+				}
+			}
+			// the padding of the encoding must be zero
+			for i := consts.HashTrinarySize; i < len(trits); i++ {
+				trits[i] = 0
+			}
+			t5b1.Encode(entry.TailTransactionHash[:], trits)
+		} else {
+			copy(entry.TailTransactionHash[:], hash)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// requireValidReceipt checks that the receipt satisfies the invariants of the protocol.
+func requireValidReceipt(t *testing.T, receipt *iotago.ReceiptMilestoneOpt, migratedAt iotago.MilestoneIndex, maxReceiptSize int) {
+	require.NotNil(t, receipt)
+	require.Equal(t, migratedAt, receipt.MigratedAt)
+	require.GreaterOrEqual(t, len(receipt.Funds), iotago.MinMigratedFundsEntryCount)
+	require.LessOrEqual(t, len(receipt.Funds), iotago.MaxMigratedFundsEntryCount)
+
+	if maxReceiptSize > 0 && len(receipt.Funds) > 1 {
+		require.LessOrEqual(t, ReceiptSize(receipt.MigratedAt, receipt.Funds), maxReceiptSize)
+	}
+
+	// the coordinator sorts the funds and adds the treasury transaction that funds the migrations,
+	// the funds of the receipt are shared with the service, so a copy is issued.
+	issued := &iotago.ReceiptMilestoneOpt{
+		MigratedAt: receipt.MigratedAt,
+		Final:      receipt.Final,
+		Funds:      append(iotago.MigratedFundsEntries{}, receipt.Funds...),
+		Transaction: &iotago.TreasuryTransaction{
+			Input:  &iotago.TreasuryInput{},
+			Output: &iotago.TreasuryOutput{Amount: consts.TotalSupply - receipt.Sum()},
+		},
+	}
+	issued.SortFunds()
+
+	require.NoError(t, iotago.ValidateReceipt(issued, &iotago.TreasuryOutput{Amount: consts.TotalSupply}, consts.TotalSupply))
+
+	data, err := issued.Serialize(serializer.DeSeriModePerformValidation, nil)
+	require.NoError(t, err)
+
+	deserialized := &iotago.ReceiptMilestoneOpt{}
+	_, err = deserialized.Deserialize(data, serializer.DeSeriModePerformValidation, nil)
+	require.NoError(t, err)
+	require.Equal(t, issued.Funds, deserialized.Funds)
+}
+
+func FuzzReceipt(f *testing.F) {
+	var valid []byte
+	for i := 0; i < 5; i++ {
+		valid = append(valid, fuzzEntry(iotago.MinMigratedFundsEntryDeposit+uint64(i), byte(i*7))...)
+	}
+	f.Add(valid, uint32(3), uint8(2), uint16(0), true)
+	f.Add(valid, uint32(1), uint8(126), uint16(150), true)
+	f.Add(fuzzEntry(consts.TotalSupply, 1), uint32(1), uint8(0), uint16(1), false)
+	f.Add(append(fuzzEntry(consts.TotalSupply, 1), fuzzEntry(consts.TotalSupply, 2)...), uint32(1), uint8(10), uint16(0), true)
+
+	f.Fuzz(func(t *testing.T, data []byte, migratedAt uint32, maxEntries uint8, maxReceiptSize uint16, normalize bool) {
+		if migratedAt == 0 {
+			migratedAt = 1
+		}
+
+		// invalid migrations are rejected before any receipt is created
+		entries := decodeFuzzEntries(data, normalize)
+		if err := validateMigrations(0, migratedAt, entries); err != nil {
+			require.ErrorIs(t, err, ErrInvalidMigrations)
+
+			return
+		}
+
+		s := &Service{
+			state:             State{LatestMigratedAtIndex: 1},
+			receiptMaxEntries: int(maxEntries)%iotago.MaxMigratedFundsEntryCount + 1,
+		}
+		s.maxReceiptSize.Store(int64(maxReceiptSize))
+
+		var migratedValue uint64
+		for _, entry := range entries {
+			migratedValue += entry.Deposit
+		}
+
+		remaining := entries
+		for {
+			batch, lastBatch, err := s.nextBatch(migratedAt, remaining)
+			if err != nil {
+				require.ErrorIs(t, err, ErrReceiptTooLarge)
+
+				return
+			}
+			require.LessOrEqual(t, len(batch), s.receiptMaxEntries)
+
+			require.NoError(t, s.updateState(&migrationResult{stopIndex: migratedAt, lastBatch: lastBatch, migratedFunds: batch}))
+
+			receipt := createReceipt(migratedAt, lastBatch, batch)
+			if len(batch) == 0 {
+				// never create an empty receipt
+				require.Nil(t, receipt)
+				require.Empty(t, entries)
+
+				break
+			}
+			requireValidReceipt(t, receipt, migratedAt, int(maxReceiptSize))
+			require.Equal(t, lastBatch, receipt.Final)
+
+			remaining = remaining[len(batch):]
+			if lastBatch {
+				require.Empty(t, remaining)
+
+				break
+			}
+		}
+
+		// all migrations were included exactly once
+		state := s.State()
+		require.Equal(t, migratedAt, state.LatestMigratedAtIndex)
+		require.EqualValues(t, len(entries), state.MigratedEntriesCount)
+		require.Equal(t, migratedValue, state.MigratedValue)
+		if migratedAt != 1 {
+			require.EqualValues(t, len(entries), state.LatestIncludedIndex)
+		}
+	})
+}
+
+func FuzzUpdateState(f *testing.F) {
+	f.Add(uint32(5), uint32(2), uint32(5), uint8(3))
+	f.Add(uint32(5), uint32(2), uint32(7), uint8(0))
+	f.Add(uint32(5), uint32(2), uint32(4), uint8(1))
+
+	f.Fuzz(func(t *testing.T, latestMigratedAtIndex uint32, latestIncludedIndex uint32, stopIndex uint32, count uint8) {
+		funds := make([]*iotago.MigratedFundsEntry, count)
+		for i := range funds {
+			funds[i] = &iotago.MigratedFundsEntry{Deposit: iotago.MinMigratedFundsEntryDeposit}
+		}
+
+		before := State{
+			LatestMigratedAtIndex: latestMigratedAtIndex,
+			LatestIncludedIndex:   latestIncludedIndex,
+			ReceiptsCount:         1,
+		}
+		s := &Service{state: before}
+		result := &migrationResult{stopIndex: stopIndex, migratedFunds: funds}
+
+		// the state never moves backwards, a result before the state is rejected and leaves the state untouched
+		if stopIndex < latestMigratedAtIndex {
+			err := s.updateState(result)
+			require.ErrorIs(t, err, ErrInvalidStateUpdate)
+			require.ErrorIs(t, err, &StateError{Index: latestMigratedAtIndex, Stage: StageUpdateState})
+			require.Equal(t, before, s.State())
+
+			return
+		}
+		require.NoError(t, s.updateState(result))
+
+		after := s.State()
+		require.Equal(t, stopIndex, after.LatestMigratedAtIndex)
+		if stopIndex == latestMigratedAtIndex {
+			require.Equal(t, latestIncludedIndex+uint32(count), after.LatestIncludedIndex)
+		} else {
+			require.EqualValues(t, count, after.LatestIncludedIndex)
+		}
+
+		require.EqualValues(t, count, after.MigratedEntriesCount)
+		require.Equal(t, uint64(count)*iotago.MinMigratedFundsEntryDeposit, after.MigratedValue)
+		if count > 0 {
+			require.EqualValues(t, 2, after.ReceiptsCount)
+			require.Equal(t, stopIndex, after.FirstMigratedAtIndex)
+		} else {
+			require.EqualValues(t, 1, after.ReceiptsCount)
+		}
+	})
+}
+
+func FuzzStateFile(f *testing.F) {
+	f.Add([]byte(`{"version":4,"latestMigratedAtIndex":5,"latestIncludedIndex":2,"sendingReceipt":false,"migratedEntriesCount":2,"migratedValue":2000000,"firstMigratedAtIndex":5,"receiptsCount":1,"networkResetIndex":0}`))
+	f.Add([]byte(`{"latestMigratedAtIndex":5,"latestIncludedIndex":2,"sendingReceipt":false}`))
+	f.Add([]byte(`{"version":1,"latestMigratedAtIndex":0}`))
+	f.Add([]byte(`{"version":99}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		upgraded, _, err := stateSchema.Upgrade(data)
+		if err != nil {
+			return
+		}
+
+		var state State
+		if err := json.Unmarshal(upgraded, &state); err != nil {
+			return
+		}
+
+		// a state that was read once survives being written and read again
+		encoded, err := json.Marshal(&state)
+		require.NoError(t, err)
+
+		reupgraded, _, err := stateSchema.Upgrade(encoded)
+		require.NoError(t, err)
+
+		var restored State
+		require.NoError(t, json.Unmarshal(reupgraded, &restored))
+		require.Equal(t, state, restored)
+
+		// an invalid state file is reported as an error, it never crashes the service
+		stateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+		require.NoError(t, os.WriteFile(stateFilePath, data, 0o600))
+
+		s := NewService(nil, stateFilePath, iotago.MaxMigratedFundsEntryCount)
+		if err := s.InitState(context.Background(), nil); err != nil {
+			require.ErrorIs(t, err, &StateError{Stage: StageInitState})
+
+			return
+		}
+		require.NotZero(t, s.State().LatestMigratedAtIndex)
+		require.False(t, s.State().SendingReceipt)
+	})
+}
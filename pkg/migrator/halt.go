@@ -0,0 +1,115 @@
+package migrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrInvalidStateUpdate is returned when a migration result can't be applied to the state, e.g. because it is older than the state.
+	ErrInvalidStateUpdate = errors.New("invalid migrator state update")
+)
+
+// Halt is the halt of the receipt issuance after a migration result could not be applied to the state.
+// The state is left untouched, no further receipts are created until the node or the service is restarted.
+type Halt struct {
+	// the time the receipt issuance was halted.
+	Time time.Time
+	// the reason of the halt.
+	Err error
+	// the path to the file the state and the offending result were dumped to (empty = the dump failed).
+	DumpFilePath string
+	// the error that occurred while writing the dump (nil = success).
+	DumpErr error
+}
+
+// HaltCaller is an event caller which gets a halt passed.
+func HaltCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(*Halt))(params[0].(*Halt))
+}
+
+// haltDump is the diagnostic dump that is written when the receipt issuance is halted.
+type haltDump struct {
+	Timestamp int64          `json:"timestamp"`
+	Error     string         `json:"error"`
+	State     State          `json:"state"`
+	Result    haltDumpResult `json:"result"`
+}
+
+type haltDumpResult struct {
+	StopIndex     iotago.MilestoneIndex `json:"stopIndex"`
+	LastBatch     bool                  `json:"lastBatch"`
+	MigratedFunds []*haltDumpEntry      `json:"migratedFunds"`
+}
+
+type haltDumpEntry struct {
+	TailTransactionHash string `json:"tailTransactionHash"`
+	Address             string `json:"address"`
+	Deposit             uint64 `json:"deposit"`
+}
+
+// Halted returns the halt of the receipt issuance, or nil if receipts are issued.
+func (s *Service) Halted() *Halt {
+	halt := s.halt.Load()
+	if halt == nil {
+		return nil
+	}
+
+	haltCopy := *halt
+
+	return &haltCopy
+}
+
+// haltReceipts halts the receipt issuance because the result could not be applied to the state,
+// the caller must hold the state lock. The state and the result are dumped next to the state file.
+func (s *Service) haltReceipts(err error, result *migrationResult) {
+	now := time.Now()
+
+	halt := &Halt{
+		Time: now,
+		Err:  err,
+	}
+
+	dumpFilePath := fmt.Sprintf("%s.halt-%d.json", s.stateFilePath, now.Unix())
+	if dumpErr := ioutils.WriteJSONToFile(dumpFilePath, newHaltDump(now, err, s.state, result), 0660); dumpErr != nil {
+		halt.DumpErr = dumpErr
+	} else {
+		halt.DumpFilePath = dumpFilePath
+	}
+
+	s.halt.Store(halt)
+
+	haltCopy := *halt
+	s.Events.Halted.Trigger(&haltCopy)
+}
+
+func newHaltDump(now time.Time, err error, state State, result *migrationResult) *haltDump {
+	entries := make([]*haltDumpEntry, 0, len(result.migratedFunds))
+	for _, entry := range result.migratedFunds {
+		dumpEntry := &haltDumpEntry{
+			TailTransactionHash: iotago.EncodeHex(entry.TailTransactionHash[:]),
+			Deposit:             entry.Deposit,
+		}
+		if entry.Address != nil {
+			dumpEntry.Address = entry.Address.String()
+		}
+		entries = append(entries, dumpEntry)
+	}
+
+	return &haltDump{
+		Timestamp: now.Unix(),
+		Error:     err.Error(),
+		State:     state,
+		Result: haltDumpResult{
+			StopIndex:     result.stopIndex,
+			LastBatch:     result.lastBatch,
+			MigratedFunds: entries,
+		},
+	}
+}
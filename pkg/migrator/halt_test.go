@@ -0,0 +1,68 @@
+package migrator
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestReceiptHaltsOnInvalidStateUpdate(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+	s := NewService(nil, stateFilePath, iotago.MaxMigratedFundsEntryCount)
+
+	msIndex := iotago.MilestoneIndex(10)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+	before := s.State()
+
+	var halted *Halt
+	s.Events.Halted.Hook(events.NewClosure(func(halt *Halt) {
+		halted = halt
+	}))
+
+	// a result that is older than the state must never be applied
+	entry := &iotago.MigratedFundsEntry{
+		Address: &iotago.Ed25519Address{1},
+		Deposit: iotago.MinMigratedFundsEntryDeposit,
+	}
+	entry.TailTransactionHash[0] = 0xab
+	// the results are buffered, so that Receipt receives them without a running service
+	s.migrations = make(chan *migrationResult, 1)
+	s.migrations <- &migrationResult{stopIndex: 5, lastBatch: true, migratedFunds: []*iotago.MigratedFundsEntry{entry}}
+
+	require.Nil(t, s.Receipt(context.Background()))
+	require.Equal(t, before, s.State())
+
+	halt := s.Halted()
+	require.NotNil(t, halt)
+	require.Equal(t, halt, halted)
+	require.ErrorIs(t, halt.Err, ErrInvalidStateUpdate)
+	require.ErrorIs(t, halt.Err, &StateError{Index: msIndex, Stage: StageUpdateState})
+	require.NoError(t, halt.DumpErr)
+
+	// the dump contains the state and the offending result
+	data, err := os.ReadFile(halt.DumpFilePath)
+	require.NoError(t, err)
+
+	dump := &haltDump{}
+	require.NoError(t, json.Unmarshal(data, dump))
+	require.Equal(t, before, dump.State)
+	require.Equal(t, halt.Err.Error(), dump.Error)
+	require.EqualValues(t, 5, dump.Result.StopIndex)
+	require.True(t, dump.Result.LastBatch)
+	require.Len(t, dump.Result.MigratedFunds, 1)
+	require.Equal(t, iotago.EncodeHex(entry.TailTransactionHash[:]), dump.Result.MigratedFunds[0].TailTransactionHash)
+	require.Equal(t, entry.Deposit, dump.Result.MigratedFunds[0].Deposit)
+
+	// no further receipts are created, even for valid results
+	s.migrations <- &migrationResult{stopIndex: 11, lastBatch: true, migratedFunds: []*iotago.MigratedFundsEntry{entry}}
+	require.Nil(t, s.Receipt(context.Background()))
+	require.Equal(t, before, s.State())
+	require.Len(t, s.migrations, 1)
+}
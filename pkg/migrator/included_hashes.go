@@ -0,0 +1,310 @@
+package migrator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// includedHashRecordSize is the size of a record in the file of the included tail transaction hashes,
+	// the tail transaction hash followed by the little-endian legacy milestone index it was migrated at.
+	includedHashRecordSize = iotago.LegacyTailTransactionHashLength + serializer.UInt32ByteSize
+)
+
+var (
+	// the prefix of the keys of the included tail transaction hashes in a shared store.
+	includedHashesKeyPrefix = []byte("includedHashes/")
+
+	// ErrTailTransactionHashIncluded is returned when a migration was already included in a previous receipt.
+	ErrTailTransactionHashIncluded = errors.New("tail transaction hash was already included in a receipt")
+	// ErrIncludedHashesMismatch is returned when the digest of the state doesn't match the included tail transaction hashes,
+	// e.g. because the state file and the included hashes file were restored from different backups.
+	ErrIncludedHashesMismatch = errors.New("migrator state does not match the included tail transaction hashes")
+)
+
+// IncludedHashesDigest is the digest of a set of included tail transaction hashes and the indices they were migrated at.
+// It is the XOR of the BLAKE2b-256 hashes of the single records, so that it can be updated with every receipt
+// and doesn't depend on the order the records were written in.
+type IncludedHashesDigest [blake2b.Size256]byte
+
+// add adds the records of the migrations that were migrated at the given legacy milestone to the digest.
+func (d *IncludedHashesDigest) add(migratedAt iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) {
+	for _, entry := range migratedFunds {
+		d.addRecord(entry.TailTransactionHash, migratedAt)
+	}
+}
+
+// addRecord adds the record of a single tail transaction hash to the digest.
+func (d *IncludedHashesDigest) addRecord(hash iotago.LegacyTailTransactionHash, migratedAt iotago.MilestoneIndex) {
+	record := make([]byte, 0, includedHashRecordSize)
+	record = append(record, hash[:]...)
+	record = binary.LittleEndian.AppendUint32(record, migratedAt)
+
+	recordHash := blake2b.Sum256(record)
+	for i := range d {
+		d[i] ^= recordHash[i]
+	}
+}
+
+// AddRecord adds the record of a migration that was included at the given legacy milestone to the digest,
+// so that consumers can compute the digest of their own records and compare it with an attested digest.
+func (d *IncludedHashesDigest) AddRecord(hash iotago.LegacyTailTransactionHash, migratedAt iotago.MilestoneIndex) {
+	d.addRecord(hash, migratedAt)
+}
+
+// String returns the hex encoded digest.
+func (d IncludedHashesDigest) String() string {
+	return iotago.EncodeHex(d[:])
+}
+
+// MarshalText encodes the digest as hex.
+func (d IncludedHashesDigest) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes the hex encoded digest.
+func (d *IncludedHashesDigest) UnmarshalText(text []byte) error {
+	digestBytes, err := iotago.DecodeHex(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid included hashes digest: %w", err)
+	}
+
+	if len(digestBytes) != len(d) {
+		return fmt.Errorf("invalid length of included hashes digest: %d", len(digestBytes))
+	}
+	copy(d[:], digestBytes)
+
+	return nil
+}
+
+// IncludedHashes is the persistent set of the tail transaction hashes of all migrations that were included in receipts.
+// It guards against migrations being included twice, e.g. because of a replaying legacy node or a confused index.
+// The hashes are appended to a file, so that adding the migrations of a receipt doesn't rewrite the whole set.
+// Alternatively the hashes can be kept in a store that is shared with other components, see OpenIncludedHashes.
+type IncludedHashes struct {
+	lock syncutils.RWMutex
+	// the path to the file the hashes are appended to.
+	filePath string
+	// the store the hashes are kept in instead of the file (nil = included hashes file).
+	store storage.Store
+	// the legacy milestone index every included tail transaction hash was migrated at.
+	hashes map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex
+	// the digest of all included tail transaction hashes.
+	digest IncludedHashesDigest
+}
+
+// LoadIncludedHashes loads the included tail transaction hashes from the given file.
+// A missing file is treated as an empty set, an incomplete last record, e.g. caused by a crash while appending,
+// is truncated, because the migrations of that record were never marked as sent in the state.
+func LoadIncludedHashes(filePath string) (*IncludedHashes, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to read included tail transaction hashes: %w", err)
+	}
+
+	if incomplete := len(data) % includedHashRecordSize; incomplete != 0 {
+		data = data[:len(data)-incomplete]
+		if err := os.Truncate(filePath, int64(len(data))); err != nil {
+			return nil, fmt.Errorf("unable to truncate incomplete included tail transaction hash: %w", err)
+		}
+	}
+
+	h := &IncludedHashes{
+		filePath: filePath,
+		hashes:   make(map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex, len(data)/includedHashRecordSize),
+	}
+
+	for offset := 0; offset < len(data); offset += includedHashRecordSize {
+		var hash iotago.LegacyTailTransactionHash
+		copy(hash[:], data[offset:offset+iotago.LegacyTailTransactionHashLength])
+		h.hashes[hash] = binary.LittleEndian.Uint32(data[offset+iotago.LegacyTailTransactionHashLength : offset+includedHashRecordSize])
+	}
+
+	for hash, migratedAt := range h.hashes {
+		h.digest.addRecord(hash, migratedAt)
+	}
+
+	return h, nil
+}
+
+// OpenIncludedHashes loads the included tail transaction hashes from the given store.
+// Every hash is kept as a separate entry with the little-endian legacy milestone index it was migrated at as value.
+func OpenIncludedHashes(store storage.Store) (*IncludedHashes, error) {
+	h := &IncludedHashes{
+		store:  store,
+		hashes: make(map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex),
+	}
+
+	var innerErr error
+	if err := store.Iterate(includedHashesKeyPrefix, func(key []byte, value []byte) bool {
+		if len(key) != len(includedHashesKeyPrefix)+iotago.LegacyTailTransactionHashLength || len(value) != serializer.UInt32ByteSize {
+			innerErr = fmt.Errorf("invalid included tail transaction hash entry %s", iotago.EncodeHex(key))
+
+			return false
+		}
+
+		var hash iotago.LegacyTailTransactionHash
+		copy(hash[:], key[len(includedHashesKeyPrefix):])
+		h.hashes[hash] = binary.LittleEndian.Uint32(value)
+
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("unable to read included tail transaction hashes: %w", err)
+	}
+	if innerErr != nil {
+		return nil, innerErr
+	}
+
+	for hash, migratedAt := range h.hashes {
+		h.digest.addRecord(hash, migratedAt)
+	}
+
+	return h, nil
+}
+
+// Len returns the amount of included tail transaction hashes.
+func (h *IncludedHashes) Len() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return len(h.hashes)
+}
+
+// LatestMigratedAt returns the highest legacy milestone index any included tail transaction hash was migrated at (0 = none).
+func (h *IncludedHashes) LatestMigratedAt() iotago.MilestoneIndex {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	var latest iotago.MilestoneIndex
+	for _, migratedAt := range h.hashes {
+		if migratedAt > latest {
+			latest = migratedAt
+		}
+	}
+
+	return latest
+}
+
+// Digest returns the digest of all included tail transaction hashes.
+func (h *IncludedHashes) Digest() IncludedHashesDigest {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return h.digest
+}
+
+// Check returns an ErrTailTransactionHashIncluded if any of the migrations was already included in a receipt.
+func (h *IncludedHashes) Check(migratedFunds []*iotago.MigratedFundsEntry) error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	for _, entry := range migratedFunds {
+		if migratedAt, exists := h.hashes[entry.TailTransactionHash]; exists {
+			return fmt.Errorf("%w: tail transaction hash %s was migrated at legacy milestone %d", ErrTailTransactionHashIncluded, iotago.EncodeHex(entry.TailTransactionHash[:]), migratedAt)
+		}
+	}
+
+	return nil
+}
+
+// Contains returns whether the tail transaction hash was already included in a receipt.
+func (h *IncludedHashes) Contains(hash iotago.LegacyTailTransactionHash) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	_, exists := h.hashes[hash]
+
+	return exists
+}
+
+// MigratedAt returns the index of the legacy milestone the migration with the given tail transaction hash was confirmed at,
+// and whether it was already included in a receipt.
+func (h *IncludedHashes) MigratedAt(hash iotago.LegacyTailTransactionHash) (iotago.MilestoneIndex, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	migratedAt, exists := h.hashes[hash]
+
+	return migratedAt, exists
+}
+
+// Add appends the tail transaction hashes of the migrations of a receipt to the set and syncs the file.
+func (h *IncludedHashes) Add(migratedAt iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var err error
+	if h.store != nil {
+		err = h.putEntries(migratedAt, migratedFunds)
+	} else {
+		err = h.appendRecords(migratedAt, migratedFunds)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range migratedFunds {
+		// the migrations were checked before, so a hash is never added twice
+		h.hashes[entry.TailTransactionHash] = migratedAt
+	}
+	h.digest.add(migratedAt, migratedFunds)
+
+	return nil
+}
+
+// putEntries puts the tail transaction hashes into the store and flushes it, the caller must hold the lock.
+func (h *IncludedHashes) putEntries(migratedAt iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) error {
+	value := binary.LittleEndian.AppendUint32(nil, migratedAt)
+	for _, entry := range migratedFunds {
+		if err := h.store.Put(storage.Key(includedHashesKeyPrefix, entry.TailTransactionHash[:]), value); err != nil {
+			return fmt.Errorf("unable to put included tail transaction hashes: %w", err)
+		}
+	}
+
+	if err := h.store.Flush(); err != nil {
+		return fmt.Errorf("unable to sync included tail transaction hashes: %w", err)
+	}
+
+	return nil
+}
+
+// appendRecords appends the tail transaction hashes to the file and syncs it, the caller must hold the lock.
+func (h *IncludedHashes) appendRecords(migratedAt iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) error {
+	data := make([]byte, 0, len(migratedFunds)*includedHashRecordSize)
+	for _, entry := range migratedFunds {
+		data = append(data, entry.TailTransactionHash[:]...)
+		data = binary.LittleEndian.AppendUint32(data, migratedAt)
+	}
+
+	file, err := os.OpenFile(h.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0660)
+	if err != nil {
+		return fmt.Errorf("unable to open included tail transaction hashes: %w", err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("unable to append included tail transaction hashes: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("unable to sync included tail transaction hashes: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to close included tail transaction hashes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package migrator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestIncludedHashes(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "included_hashes.bin")
+
+	includedHashes, err := migrator.LoadIncludedHashes(filePath)
+	require.NoError(t, err)
+	require.Zero(t, includedHashes.Len())
+	require.NoError(t, includedHashes.Check(serviceTests.entries))
+
+	require.NoError(t, includedHashes.Add(serviceTests.migratedAt, serviceTests.entries[:2]))
+	require.ErrorIs(t, includedHashes.Check(serviceTests.entries[1:]), migrator.ErrTailTransactionHashIncluded)
+	require.NoError(t, includedHashes.Check(serviceTests.entries[2:]))
+	require.True(t, includedHashes.Contains(serviceTests.entries[1].TailTransactionHash))
+	require.False(t, includedHashes.Contains(serviceTests.entries[2].TailTransactionHash))
+
+	// the hashes survive a restart
+	includedHashes, err = migrator.LoadIncludedHashes(filePath)
+	require.NoError(t, err)
+	require.Equal(t, 2, includedHashes.Len())
+	require.ErrorIs(t, includedHashes.Check(serviceTests.entries[:1]), migrator.ErrTailTransactionHashIncluded)
+	migratedAt, included := includedHashes.MigratedAt(serviceTests.entries[0].TailTransactionHash)
+	require.True(t, included)
+	require.EqualValues(t, serviceTests.migratedAt, migratedAt)
+	_, included = includedHashes.MigratedAt(serviceTests.entries[2].TailTransactionHash)
+	require.False(t, included)
+
+	// an incomplete record of an interrupted append is dropped
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0660)
+	require.NoError(t, err)
+	_, err = file.Write(serviceTests.entries[2].TailTransactionHash[:10])
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	includedHashes, err = migrator.LoadIncludedHashes(filePath)
+	require.NoError(t, err)
+	require.Equal(t, 2, includedHashes.Len())
+	require.NoError(t, includedHashes.Add(serviceTests.migratedAt, serviceTests.entries[2:]))
+
+	includedHashes, err = migrator.LoadIncludedHashes(filePath)
+	require.NoError(t, err)
+	require.Equal(t, len(serviceTests.entries), includedHashes.Len())
+}
+
+func TestIncludedHashesStore(t *testing.T) {
+	store, err := storage.Open(storage.BackendMapDB, "")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, store.Close()) }()
+
+	includedHashes, err := migrator.OpenIncludedHashes(store)
+	require.NoError(t, err)
+	require.Zero(t, includedHashes.Len())
+
+	require.NoError(t, includedHashes.Add(serviceTests.migratedAt, serviceTests.entries[:2]))
+	require.ErrorIs(t, includedHashes.Check(serviceTests.entries[1:]), migrator.ErrTailTransactionHashIncluded)
+	digest := includedHashes.Digest()
+
+	// the hashes and their digest are restored from the store
+	includedHashes, err = migrator.OpenIncludedHashes(store)
+	require.NoError(t, err)
+	require.Equal(t, 2, includedHashes.Len())
+	require.Equal(t, digest, includedHashes.Digest())
+	migratedAt, included := includedHashes.MigratedAt(serviceTests.entries[0].TailTransactionHash)
+	require.True(t, included)
+	require.EqualValues(t, serviceTests.migratedAt, migratedAt)
+
+	// the digest is the same as the one of the included hashes file
+	fileIncludedHashes, err := migrator.LoadIncludedHashes(filepath.Join(t.TempDir(), "included_hashes.bin"))
+	require.NoError(t, err)
+	require.NoError(t, fileIncludedHashes.Add(serviceTests.migratedAt, serviceTests.entries[:2]))
+	require.Equal(t, fileIncludedHashes.Digest(), includedHashes.Digest())
+}
+
+func TestServiceIncludedHashes(t *testing.T) {
+	dir := t.TempDir()
+
+	includedHashes, err := migrator.LoadIncludedHashes(filepath.Join(dir, "included_hashes.bin"))
+	require.NoError(t, err)
+
+	s := migrator.NewService(&mockQueryer{}, filepath.Join(dir, "migrator.state"), len(serviceTests.entries))
+	s.SetIncludedHashes(includedHashes)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.Start(ctx, nil)
+
+	var receipt *iotago.ReceiptMilestoneOpt
+	require.Eventually(t, func() bool {
+		receipt = s.Receipt(context.Background())
+
+		return receipt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	// the hashes are only added once the receipt was sent
+	require.NoError(t, s.PersistState(context.Background(), true))
+	require.Zero(t, includedHashes.Len())
+	require.NoError(t, s.PersistState(context.Background(), false))
+	require.Equal(t, len(receipt.Funds), includedHashes.Len())
+
+	// a service that was bootstrapped again after losing its state must not migrate the funds twice
+	s = migrator.NewService(&mockQueryer{}, filepath.Join(dir, "migrator_rebootstrapped.state"), len(serviceTests.entries))
+	s.SetIncludedHashes(includedHashes)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	serviceErr := make(chan error, 1)
+	go s.Start(ctx, func(err error) bool {
+		serviceErr <- err
+
+		return false
+	})
+
+	select {
+	case err = <-serviceErr:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the service did not reject the included migrations")
+	}
+
+	require.ErrorIs(t, err, migrator.ErrTailTransactionHashIncluded)
+	require.Equal(t, migrator.ErrorClassValidation, migrator.ErrorClass(err))
+	require.Nil(t, s.Receipt(context.Background()))
+}
+
+func TestIncludedHashesDigest(t *testing.T) {
+	dir := t.TempDir()
+
+	// the digest doesn't depend on the order the receipts were included in
+	ordered, err := migrator.LoadIncludedHashes(filepath.Join(dir, "ordered.bin"))
+	require.NoError(t, err)
+	empty := ordered.Digest()
+	require.NoError(t, ordered.Add(serviceTests.migratedAt, serviceTests.entries[:2]))
+	require.NotEqual(t, empty, ordered.Digest())
+	require.NoError(t, ordered.Add(serviceTests.migratedAt, serviceTests.entries[2:]))
+
+	reversed, err := migrator.LoadIncludedHashes(filepath.Join(dir, "reversed.bin"))
+	require.NoError(t, err)
+	require.NoError(t, reversed.Add(serviceTests.migratedAt, serviceTests.entries[2:]))
+	require.NoError(t, reversed.Add(serviceTests.migratedAt, serviceTests.entries[:2]))
+	require.Equal(t, ordered.Digest(), reversed.Digest())
+
+	// the digest survives a restart
+	reloaded, err := migrator.LoadIncludedHashes(filepath.Join(dir, "ordered.bin"))
+	require.NoError(t, err)
+	require.Equal(t, ordered.Digest(), reloaded.Digest())
+
+	// the index the hashes were migrated at is part of the digest
+	other, err := migrator.LoadIncludedHashes(filepath.Join(dir, "other.bin"))
+	require.NoError(t, err)
+	require.NoError(t, other.Add(serviceTests.migratedAt+1, serviceTests.entries))
+	require.NotEqual(t, ordered.Digest(), other.Digest())
+}
+
+func TestInitStateIncludedHashesMismatch(t *testing.T) {
+	dir := t.TempDir()
+	stateFilePath := filepath.Join(dir, "migrator.state")
+	includedHashesFilePath := filepath.Join(dir, "included_hashes.bin")
+
+	includedHashes, err := migrator.LoadIncludedHashes(includedHashesFilePath)
+	require.NoError(t, err)
+
+	s := migrator.NewService(&mockQueryer{}, stateFilePath, 2)
+	s.SetIncludedHashes(includedHashes)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	// keep a backup of the files before the first receipt
+	stateBackup, err := os.ReadFile(stateFilePath)
+	require.NoError(t, err)
+	// no receipt was sent yet, so the included hashes are empty
+	includedHashesBackup := []byte{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.Start(ctx, nil)
+
+	require.Eventually(t, func() bool {
+		return s.Receipt(context.Background()) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, s.PersistState(context.Background(), false))
+	require.Equal(t, includedHashes.Digest(), *s.State().IncludedHashesDigest)
+
+	initState := func() error {
+		includedHashes, err := migrator.LoadIncludedHashes(includedHashesFilePath)
+		require.NoError(t, err)
+
+		s := migrator.NewService(&mockQueryer{}, stateFilePath, 2)
+		s.SetIncludedHashes(includedHashes)
+
+		return s.InitState(context.Background(), nil)
+	}
+
+	// matching files are loaded
+	require.NoError(t, initState())
+
+	// the state was restored from an older backup than the included hashes
+	stateFile, err := os.ReadFile(stateFilePath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(stateFilePath, stateBackup, 0600))
+	err = initState()
+	require.ErrorIs(t, err, migrator.ErrIncludedHashesMismatch)
+	require.ErrorIs(t, err, &migrator.StateError{Stage: migrator.StageInitState})
+
+	// the included hashes were restored from an older backup than the state
+	require.NoError(t, os.WriteFile(stateFilePath, stateFile, 0600))
+	require.NoError(t, os.WriteFile(includedHashesFilePath, includedHashesBackup, 0600))
+	require.ErrorIs(t, initState(), migrator.ErrIncludedHashesMismatch)
+}
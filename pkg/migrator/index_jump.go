@@ -0,0 +1,119 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrIndexJumpNotPending is returned when a jump of the migrated at index is confirmed that is not awaiting confirmation.
+	ErrIndexJumpNotPending = errors.New("no such index jump awaiting confirmation")
+)
+
+// IndexJump is a jump of the migrated at index by more legacy milestones than allowed at once.
+// A legacy node that is connected to the wrong network returns migrations far ahead of the state,
+// so such a jump is only applied after it was confirmed.
+type IndexJump struct {
+	// the legacy milestone index of the latest migrations.
+	FromIndex iotago.MilestoneIndex
+	// the legacy milestone index of the next migrations.
+	ToIndex iotago.MilestoneIndex
+	// the amount of migrations of the next legacy milestone.
+	EntriesCount int
+}
+
+// Len returns the amount of legacy milestones the migrated at index jumps forward.
+func (j *IndexJump) Len() uint32 {
+	return j.ToIndex - j.FromIndex
+}
+
+// IndexJumpCaller is an event caller which gets an index jump passed.
+func IndexJumpCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(*IndexJump))(params[0].(*IndexJump))
+}
+
+// pendingIndexJump is a jump that awaits its confirmation.
+type pendingIndexJump struct {
+	jump      *IndexJump
+	confirmed chan struct{}
+}
+
+// SetMaxIndexJump sets the maximum amount of legacy milestones the migrated at index may jump forward at once (0 = no limit).
+// Larger jumps trigger the IndexJumpDetected event and the migrations are held back until the jump is confirmed.
+// SetMaxIndexJump must be called before Start.
+func (s *Service) SetMaxIndexJump(maxIndexJump uint32) {
+	s.maxIndexJump = maxIndexJump
+}
+
+// PendingIndexJump returns the jump of the migrated at index that awaits confirmation, or nil if there is none.
+func (s *Service) PendingIndexJump() *IndexJump {
+	s.indexJumpLock.Lock()
+	defer s.indexJumpLock.Unlock()
+
+	if s.pendingIndexJump == nil {
+		return nil
+	}
+
+	jump := *s.pendingIndexJump.jump
+
+	return &jump
+}
+
+// ConfirmIndexJump confirms the pending jump of the migrated at index to the given legacy milestone index.
+// The target index has to be given, so that a confirmation never applies to another jump than the one that was checked.
+func (s *Service) ConfirmIndexJump(toIndex iotago.MilestoneIndex) error {
+	s.indexJumpLock.Lock()
+	defer s.indexJumpLock.Unlock()
+
+	if s.pendingIndexJump == nil || s.pendingIndexJump.jump.ToIndex != toIndex {
+		return fmt.Errorf("%w: legacy milestone index %d", ErrIndexJumpNotPending, toIndex)
+	}
+
+	close(s.pendingIndexJump.confirmed)
+	s.pendingIndexJump = nil
+
+	return nil
+}
+
+// awaitIndexJump blocks until a jump of the migrated at index from fromIndex to the migrations of the result is confirmed,
+// if it exceeds the maximum jump. It returns false if ctx was done before.
+func (s *Service) awaitIndexJump(ctx context.Context, fromIndex iotago.MilestoneIndex, result *fetchResult) bool {
+	if s.maxIndexJump == 0 || result.msIndex <= fromIndex || result.msIndex-fromIndex <= s.maxIndexJump {
+		return true
+	}
+
+	pending := &pendingIndexJump{
+		jump: &IndexJump{
+			FromIndex:    fromIndex,
+			ToIndex:      result.msIndex,
+			EntriesCount: len(result.migratedFunds),
+		},
+		confirmed: make(chan struct{}),
+	}
+
+	s.indexJumpLock.Lock()
+	s.pendingIndexJump = pending
+	s.indexJumpLock.Unlock()
+
+	jump := *pending.jump
+	s.Events.IndexJumpDetected.Trigger(&jump)
+
+	select {
+	case <-pending.confirmed:
+		return true
+
+	case <-ctx.Done():
+		s.indexJumpLock.Lock()
+		if s.pendingIndexJump == pending {
+			s.pendingIndexJump = nil
+		}
+		s.indexJumpLock.Unlock()
+
+		return false
+	}
+}
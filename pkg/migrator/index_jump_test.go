@@ -0,0 +1,129 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestIndexJump(t *testing.T) {
+	q := &scriptedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 2, migratedFunds: serviceTests.entries[:1]},
+			// migrations of a legacy node of another network
+			{stopIndex: 200, migratedFunds: serviceTests.entries[1:]},
+		},
+	}
+
+	s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), len(serviceTests.entries))
+	s.SetMaxIndexJump(100)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	detected := make(chan *migrator.IndexJump, 1)
+	s.Events.IndexJumpDetected.Hook(events.NewClosure(func(jump *migrator.IndexJump) {
+		detected <- jump
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, func(_ error) bool { return false })
+
+	var receipt *iotago.ReceiptMilestoneOpt
+	require.Eventually(t, func() bool {
+		receipt = s.Receipt(context.Background())
+
+		return receipt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.EqualValues(t, 2, receipt.MigratedAt)
+
+	var jump *migrator.IndexJump
+	select {
+	case jump = <-detected:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the index jump was not detected")
+	}
+	require.Equal(t, &migrator.IndexJump{FromIndex: 2, ToIndex: 200, EntriesCount: len(serviceTests.entries) - 1}, jump)
+	require.EqualValues(t, 198, jump.Len())
+	require.Equal(t, jump, s.PendingIndexJump())
+
+	// the migrations are held back until the jump is confirmed
+	require.Never(t, func() bool { return s.Receipt(context.Background()) != nil }, 100*time.Millisecond, 10*time.Millisecond)
+	require.ErrorIs(t, s.ConfirmIndexJump(199), migrator.ErrIndexJumpNotPending)
+
+	require.NoError(t, s.ConfirmIndexJump(200))
+	require.Nil(t, s.PendingIndexJump())
+	require.ErrorIs(t, s.ConfirmIndexJump(200), migrator.ErrIndexJumpNotPending)
+
+	require.Eventually(t, func() bool {
+		receipt = s.Receipt(context.Background())
+
+		return receipt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.EqualValues(t, 200, receipt.MigratedAt)
+}
+
+func TestIndexJumpThenBackInTime(t *testing.T) {
+	q := newSteppedQueryer()
+	s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), len(serviceTests.entries))
+	s.SetMaxIndexJump(100)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serviceErr := make(chan error, 1)
+	go s.Start(ctx, func(err error) bool {
+		serviceErr <- err
+
+		return false
+	})
+
+	receipt := func() *iotago.ReceiptMilestoneOpt {
+		var receipt *iotago.ReceiptMilestoneOpt
+		require.Eventually(t, func() bool {
+			receipt = s.Receipt(context.Background())
+
+			return receipt != nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		return receipt
+	}
+
+	q.release(t, 2, serviceTests.entries[:1])
+	require.EqualValues(t, 2, receipt().MigratedAt)
+
+	// migrations of a legacy node of another network
+	q.release(t, 200, serviceTests.entries[1:])
+	require.Eventually(t, func() bool { return s.PendingIndexJump() != nil }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.ConfirmIndexJump(200))
+
+	// the held receipt is read before the legacy node is answered again, so that the service can't stop before
+	require.EqualValues(t, 200, receipt().MigratedAt)
+
+	// the legacy node went back in time, which stops the service
+	select {
+	case startIndex := <-q.queried:
+		require.EqualValues(t, 201, startIndex)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the service did not query the next migrations")
+	}
+	q.steps <- scriptedResult{stopIndex: 3}
+
+	select {
+	case err := <-serviceErr:
+		require.ErrorIs(t, err, migrator.ErrInvalidStopIndex)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the service did not stop")
+	}
+	require.Eventually(t, func() bool { return !s.Running() }, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, s.Receipt(context.Background()))
+}
@@ -0,0 +1,275 @@
+package migrator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/syncutils"
+)
+
+const (
+	// AuthTypeNone doesn't authenticate to the legacy node.
+	AuthTypeNone = "none"
+	// AuthTypeAPIKey sends a static API key to the legacy node.
+	AuthTypeAPIKey = "apiKey"
+	// AuthTypeJWT sends a JWT as bearer token to the legacy node, it is reloaded before it expires.
+	AuthTypeJWT = "jwt"
+
+	// defaultAPIKeyHeader is the header the API key is sent in by default.
+	defaultAPIKeyHeader = "X-API-Key"
+	// defaultJWTHeader is the header the JWT is sent in by default.
+	defaultJWTHeader = "Authorization"
+	// defaultRefreshMargin is the duration before the expiry of a JWT after which it is reloaded.
+	defaultRefreshMargin = time.Minute
+)
+
+var (
+	// ErrUnknownAuthType is returned when an unknown authentication type is configured.
+	ErrUnknownAuthType = errors.New("unknown legacy node authentication type")
+	// ErrInvalidCredential is returned when the API key or the JWT could not be loaded or is invalid.
+	ErrInvalidCredential = errors.New("invalid legacy node credential")
+)
+
+// HTTPClient sends the HTTP requests to the legacy node.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// CredentialSource loads the API key or the JWT used to authenticate to the legacy node.
+type CredentialSource func() (string, error)
+
+// FileCredentialSource loads the credential from the given file, surrounding whitespace is ignored.
+// The file is read again every time the credential is reloaded, so the credential can be rotated without a restart.
+func FileCredentialSource(filePath string) CredentialSource {
+	return func() (string, error) {
+		//nolint:gosec // the path is defined by the operator
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return "", fmt.Errorf("%w: unable to read credential file: %v", ErrInvalidCredential, err)
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	}
+}
+
+// EnvCredentialSource loads the credential from the given environment variable.
+func EnvCredentialSource(name string) CredentialSource {
+	return func() (string, error) {
+		credential, exists := os.LookupEnv(name)
+		if !exists || credential == "" {
+			return "", fmt.Errorf("%w: environment variable '%s' not set", ErrInvalidCredential, name)
+		}
+
+		return strings.TrimSpace(credential), nil
+	}
+}
+
+// AuthHTTPClient is an HTTPClient that authenticates the requests to the legacy node with an API key or a JWT.
+// A JWT is reloaded from its source before it expires. If the legacy node rejects the credential,
+// e.g. because it was rotated, the credential is reloaded and the request is retried once.
+type AuthHTTPClient struct {
+	client HTTPClient
+	source CredentialSource
+
+	authType string
+	// the header the credential is sent in.
+	header string
+	// the duration before the expiry of a JWT after which it is reloaded.
+	refreshMargin time.Duration
+	// used to get the current time.
+	clock func() time.Time
+
+	// lock protects the loaded credential.
+	lock       syncutils.Mutex
+	credential string
+	// the expiry of the loaded JWT (zero = never expires).
+	expiresAt time.Time
+}
+
+// WithAuthHeader defines the header the credential is sent in.
+func WithAuthHeader(header string) options.Option[AuthHTTPClient] {
+	return func(c *AuthHTTPClient) {
+		if header != "" {
+			c.header = header
+		}
+	}
+}
+
+// WithRefreshMargin defines the duration before the expiry of a JWT after which it is reloaded.
+func WithRefreshMargin(refreshMargin time.Duration) options.Option[AuthHTTPClient] {
+	return func(c *AuthHTTPClient) {
+		c.refreshMargin = refreshMargin
+	}
+}
+
+// WithAuthClock defines the clock used to check the expiry of a JWT.
+func WithAuthClock(clock func() time.Time) options.Option[AuthHTTPClient] {
+	return func(c *AuthHTTPClient) {
+		c.clock = clock
+	}
+}
+
+// NewAuthHTTPClient creates a new AuthHTTPClient that authenticates the requests of the given client.
+// The credential is loaded once, so that a missing or invalid credential is detected at startup.
+func NewAuthHTTPClient(client HTTPClient, authType string, source CredentialSource, opts ...options.Option[AuthHTTPClient]) (*AuthHTTPClient, error) {
+	var header string
+	switch authType {
+	case AuthTypeAPIKey:
+		header = defaultAPIKeyHeader
+	case AuthTypeJWT:
+		header = defaultJWTHeader
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAuthType, authType)
+	}
+
+	c := options.Apply(&AuthHTTPClient{
+		client:        client,
+		source:        source,
+		authType:      authType,
+		header:        header,
+		refreshMargin: defaultRefreshMargin,
+		clock:         time.Now,
+	}, opts)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Do sends the request with the credential.
+func (c *AuthHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	credential, err := c.currentCredential()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(c.authenticate(req, credential))
+	if err != nil || (resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden) {
+		return resp, err
+	}
+
+	// the credential was rejected, it may have been rotated or revoked
+	retryCredential, err := c.rejected(credential)
+	if err != nil || retryCredential == credential || req.GetBody == nil {
+		// there is no new credential or the body can't be sent again
+		return resp, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	retry.Body = body
+
+	return c.client.Do(c.authenticate(retry, retryCredential))
+}
+
+// authenticate returns a copy of the request with the credential.
+func (c *AuthHTTPClient) authenticate(req *http.Request, credential string) *http.Request {
+	req = req.Clone(req.Context())
+
+	if c.authType == AuthTypeJWT && c.header == defaultJWTHeader {
+		req.Header.Set(c.header, "Bearer "+credential)
+	} else {
+		req.Header.Set(c.header, credential)
+	}
+
+	return req
+}
+
+// currentCredential returns the loaded credential, a JWT that is about to expire is reloaded first.
+func (c *AuthHTTPClient) currentCredential() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.expiresAt.IsZero() && !c.clock().Before(c.expiresAt.Add(-c.refreshMargin)) {
+		if err := c.reload(); err != nil {
+			return "", err
+		}
+	}
+
+	return c.credential, nil
+}
+
+// rejected reloads the credential after the given credential was rejected by the legacy node.
+// If another request already reloaded it, the reloaded credential is returned.
+func (c *AuthHTTPClient) rejected(credential string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.credential == credential {
+		if err := c.reload(); err != nil {
+			return "", err
+		}
+	}
+
+	return c.credential, nil
+}
+
+// reload loads the credential from its source, the caller must hold the lock.
+func (c *AuthHTTPClient) reload() error {
+	credential, err := c.source()
+	if err != nil {
+		return err
+	}
+	if credential == "" {
+		return fmt.Errorf("%w: the credential is empty", ErrInvalidCredential)
+	}
+
+	var expiresAt time.Time
+	if c.authType == AuthTypeJWT {
+		if expiresAt, err = JWTExpiry(credential); err != nil {
+			return err
+		}
+	}
+
+	c.credential = credential
+	c.expiresAt = expiresAt
+
+	return nil
+}
+
+// JWTExpiry returns the expiry of the given JWT (zero = never expires).
+// The signature is not verified, the token is only checked by the legacy node.
+func JWTExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("%w: the JWT must consist of three parts", ErrInvalidCredential)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: unable to decode JWT payload: %v", ErrInvalidCredential, err)
+	}
+
+	claims := &struct {
+		ExpiresAt *int64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return time.Time{}, fmt.Errorf("%w: unable to parse JWT claims: %v", ErrInvalidCredential, err)
+	}
+
+	if claims.ExpiresAt == nil {
+		return time.Time{}, nil
+	}
+
+	return time.Unix(*claims.ExpiresAt, 0), nil
+}
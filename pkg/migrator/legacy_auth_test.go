@@ -0,0 +1,127 @@
+package migrator_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+)
+
+func testJWT(subject string, expiresAt time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":%q,"exp":%d}`, subject, expiresAt.Unix())))
+
+	return header + "." + payload + ".signature"
+}
+
+func TestAuthHTTPClientAPIKey(t *testing.T) {
+	credentialFilePath := filepath.Join(t.TempDir(), "api_key")
+	require.NoError(t, os.WriteFile(credentialFilePath, []byte("key1\n"), 0o600))
+
+	// the legacy node only accepts the current key
+	var acceptedKey atomic.Value
+	acceptedKey.Store("key1")
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("X-API-Key") != acceptedKey.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := migrator.NewAuthHTTPClient(server.Client(), migrator.AuthTypeAPIKey, migrator.FileCredentialSource(credentialFilePath))
+	require.NoError(t, err)
+
+	post := func() int {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"command":"getNodeInfo"}`))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, post())
+
+	// the key was rotated, the rejected request is retried once with the reloaded key
+	acceptedKey.Store("key2")
+	require.NoError(t, os.WriteFile(credentialFilePath, []byte("key2"), 0o600))
+	requests.Store(0)
+	require.Equal(t, http.StatusOK, post())
+	require.EqualValues(t, 2, requests.Load())
+
+	// a revoked key is not retried with the same key
+	acceptedKey.Store("key3")
+	requests.Store(0)
+	require.Equal(t, http.StatusUnauthorized, post())
+	require.EqualValues(t, 1, requests.Load())
+}
+
+func TestAuthHTTPClientJWTRefresh(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	credentialFilePath := filepath.Join(t.TempDir(), "jwt")
+	require.NoError(t, os.WriteFile(credentialFilePath, []byte(testJWT("first", now.Add(10*time.Minute))), 0o600))
+
+	var authorization atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var clock atomic.Int64
+	clock.Store(now.Unix())
+	client, err := migrator.NewAuthHTTPClient(server.Client(), migrator.AuthTypeJWT, migrator.FileCredentialSource(credentialFilePath),
+		migrator.WithRefreshMargin(time.Minute),
+		migrator.WithAuthClock(func() time.Time { return time.Unix(clock.Load(), 0) }),
+	)
+	require.NoError(t, err)
+
+	get := func() string {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		//nolint:forcetypeassert // only strings are stored
+		return authorization.Load().(string)
+	}
+
+	require.Equal(t, "Bearer "+testJWT("first", now.Add(10*time.Minute)), get())
+
+	// the token is renewed by an external process, it is reloaded once the current token is about to expire
+	require.NoError(t, os.WriteFile(credentialFilePath, []byte(testJWT("second", now.Add(20*time.Minute))), 0o600))
+	require.Equal(t, "Bearer "+testJWT("first", now.Add(10*time.Minute)), get())
+
+	clock.Store(now.Add(9*time.Minute + 30*time.Second).Unix())
+	require.Equal(t, "Bearer "+testJWT("second", now.Add(20*time.Minute)), get())
+}
+
+func TestAuthHTTPClientInvalidCredential(t *testing.T) {
+	t.Setenv("LEGACY_API_TEST_CREDENTIAL", "not-a-jwt")
+
+	_, err := migrator.NewAuthHTTPClient(http.DefaultClient, migrator.AuthTypeJWT, migrator.EnvCredentialSource("LEGACY_API_TEST_CREDENTIAL"))
+	require.ErrorIs(t, err, migrator.ErrInvalidCredential)
+
+	_, err = migrator.NewAuthHTTPClient(http.DefaultClient, migrator.AuthTypeAPIKey, migrator.EnvCredentialSource("LEGACY_API_MISSING_CREDENTIAL"))
+	require.ErrorIs(t, err, migrator.ErrInvalidCredential)
+
+	_, err = migrator.NewAuthHTTPClient(http.DefaultClient, "basic", migrator.EnvCredentialSource("LEGACY_API_TEST_CREDENTIAL"))
+	require.ErrorIs(t, err, migrator.ErrUnknownAuthType)
+}
@@ -0,0 +1,110 @@
+package migrator
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrServiceRunning is returned when the service is restarted while another run was started concurrently.
+	ErrServiceRunning = errors.New("migrator service is already running")
+	// ErrReceiptPending is returned when the service is restarted before the last receipt was persisted as sent.
+	ErrReceiptPending = errors.New("migrator receipt pending")
+)
+
+// Running returns whether s is running.
+func (s *Service) Running() bool {
+	s.lifecycleLock.Lock()
+	defer s.lifecycleLock.Unlock()
+
+	return s.runDone != nil
+}
+
+// Stop stops the running Start of s and waits until it returned.
+// The migrations that were fetched but not passed to Receipt yet are dropped, they are fetched again once s is restarted.
+// Stop returns immediately if s is not running.
+func (s *Service) Stop() {
+	s.lifecycleLock.Lock()
+	cancel, done := s.cancelRun, s.runDone
+	s.lifecycleLock.Unlock()
+
+	if done == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+// Restart stops s, reloads the state from the state file and starts s again in the background,
+// it stops when the given context is done. The halt of the receipt issuance is lifted, so that a halted service
+// continues with the persisted state.
+// The receipt returned by the last call of Receipt must have been persisted as sent, otherwise ErrReceiptPending is returned
+// and s stays stopped, since the migrations of the receipt would be included again. Calls of Receipt that returned nil
+// on an idle legacy network leave no receipt pending.
+// Restart must not be called concurrently with Start.
+func (s *Service) Restart(ctx context.Context, onError OnServiceErrorFunc) error {
+	s.Stop()
+
+	// the state file must contain all snapshots that were taken before it is reloaded
+	s.persistLock.Lock()
+	s.awaitPersisted()
+	s.persistLock.Unlock()
+
+	s.stateLock.RLock()
+	pending := s.receiptPending()
+	s.stateLock.RUnlock()
+	if pending {
+		return ErrReceiptPending
+	}
+
+	if err := s.InitState(ctx, nil); err != nil {
+		return err
+	}
+	s.halt.Store(nil)
+
+	ctx, migrations, ok := s.begin(ctx)
+	if !ok {
+		return ErrServiceRunning
+	}
+	go s.run(ctx, migrations, onError)
+
+	return nil
+}
+
+// begin registers a run of s that stops when the given context is done or Stop is called,
+// and creates the channel the migration results of the run are passed to Receipt on.
+// It returns false if s is already running.
+func (s *Service) begin(ctx context.Context) (context.Context, chan *migrationResult, bool) {
+	s.lifecycleLock.Lock()
+	defer s.lifecycleLock.Unlock()
+
+	if s.runDone != nil {
+		return nil, nil, false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancelRun = cancel
+	s.runDone = make(chan struct{})
+
+	// the channel of a previous run is closed, so a new one is created for every run
+	migrations := make(chan *migrationResult)
+	s.receiptLock.Lock()
+	s.migrations = migrations
+	s.receiptLock.Unlock()
+
+	return ctx, migrations, true
+}
+
+// end unregisters the run of s once it terminated and closes its channel of migration results.
+func (s *Service) end(migrations chan *migrationResult) {
+	s.lifecycleLock.Lock()
+	defer s.lifecycleLock.Unlock()
+
+	s.cancelRun()
+	close(migrations)
+	close(s.runDone)
+	s.cancelRun = nil
+	s.runDone = nil
+}
@@ -0,0 +1,200 @@
+package migrator
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrReceiptMismatch is returned when the receipts seen on the network differ from the computed receipts.
+	ErrReceiptMismatch = errors.New("receipt mismatch")
+)
+
+// MirrorEvents are the events issued by the Mirror.
+type MirrorEvents struct {
+	// ReceiptsVerified is triggered when the receipts of a legacy milestone match the computed receipts.
+	ReceiptsVerified *events.Event
+	// ReceiptsMismatch is triggered when the receipts of a legacy milestone differ from the computed receipts.
+	ReceiptsMismatch *events.Event
+}
+
+// MirrorResult is the result of the comparison of the receipts of a legacy milestone.
+type MirrorResult struct {
+	// the index of the legacy milestone.
+	MigratedAt iotago.MilestoneIndex
+	// the amount of computed migrations.
+	ComputedEntriesCount int
+	// the amount of migrations in the receipts seen on the network.
+	NetworkEntriesCount int
+	// the mismatch, nil if the receipts matched.
+	Err error
+}
+
+// MirrorResultCaller is an event caller which gets a mirror result passed.
+func MirrorResultCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(*MirrorResult))(params[0].(*MirrorResult))
+}
+
+// mirroredReceipts are the migrations of a legacy milestone, collected from all receipts of that milestone.
+type mirroredReceipts struct {
+	funds []*iotago.MigratedFundsEntry
+	final bool
+}
+
+func (r *mirroredReceipts) add(receipt *iotago.ReceiptMilestoneOpt) {
+	r.funds = append(r.funds, receipt.Funds...)
+	r.final = r.final || receipt.Final
+}
+
+// Mirror compares the receipts computed by a migrator Service with the receipts seen on the network,
+// so that independent parties can verify the migration without being able to issue milestones.
+// The migrations of a legacy milestone can be split across several receipts, so all receipts of
+// a legacy milestone are collected until the final one was seen on both sides, independent of the batch sizes.
+type Mirror struct {
+	Events *MirrorEvents
+
+	lock syncutils.Mutex
+	// the first legacy milestone index whose migrations are computed completely.
+	startIndex iotago.MilestoneIndex
+	// the computed receipts that were not compared yet.
+	computed map[iotago.MilestoneIndex]*mirroredReceipts
+	// the receipts seen on the network that were not compared yet.
+	network map[iotago.MilestoneIndex]*mirroredReceipts
+
+	verifiedCount atomic.Uint64
+	mismatchCount atomic.Uint64
+}
+
+// NewMirror creates a new Mirror for a migrator Service in the given state.
+// A partially included legacy milestone can't be verified, because its earlier receipts are not computed again.
+func NewMirror(state State) *Mirror {
+	startIndex := state.LatestMigratedAtIndex
+	if state.LatestIncludedIndex > 0 {
+		startIndex++
+	}
+
+	return &Mirror{
+		Events: &MirrorEvents{
+			ReceiptsVerified: events.NewEvent(MirrorResultCaller),
+			ReceiptsMismatch: events.NewEvent(MirrorResultCaller),
+		},
+		startIndex: startIndex,
+		computed:   make(map[iotago.MilestoneIndex]*mirroredReceipts),
+		network:    make(map[iotago.MilestoneIndex]*mirroredReceipts),
+	}
+}
+
+// StartIndex returns the first legacy milestone index that is verified.
+func (m *Mirror) StartIndex() iotago.MilestoneIndex {
+	return m.startIndex
+}
+
+// AddComputedReceipt adds a receipt that was computed by the migrator Service.
+func (m *Mirror) AddComputedReceipt(receipt *iotago.ReceiptMilestoneOpt) {
+	m.add(m.computed, receipt)
+}
+
+// AddNetworkReceipt adds a receipt that was seen on the network.
+func (m *Mirror) AddNetworkReceipt(receipt *iotago.ReceiptMilestoneOpt) {
+	m.add(m.network, receipt)
+}
+
+func (m *Mirror) add(receipts map[iotago.MilestoneIndex]*mirroredReceipts, receipt *iotago.ReceiptMilestoneOpt) {
+	if receipt == nil || receipt.MigratedAt < m.startIndex {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, exists := receipts[receipt.MigratedAt]; !exists {
+		receipts[receipt.MigratedAt] = &mirroredReceipts{}
+	}
+	receipts[receipt.MigratedAt].add(receipt)
+
+	computed, computedExists := m.computed[receipt.MigratedAt]
+	network, networkExists := m.network[receipt.MigratedAt]
+	if !computedExists || !networkExists || !computed.final || !network.final {
+		return
+	}
+
+	delete(m.computed, receipt.MigratedAt)
+	delete(m.network, receipt.MigratedAt)
+
+	result := &MirrorResult{
+		MigratedAt:           receipt.MigratedAt,
+		ComputedEntriesCount: len(computed.funds),
+		NetworkEntriesCount:  len(network.funds),
+		Err:                  compareMigratedFunds(computed.funds, network.funds),
+	}
+
+	if result.Err != nil {
+		m.mismatchCount.Add(1)
+		m.Events.ReceiptsMismatch.Trigger(result)
+
+		return
+	}
+
+	m.verifiedCount.Add(1)
+	m.Events.ReceiptsVerified.Trigger(result)
+}
+
+// PendingCount returns the amount of legacy milestones whose computed and network receipts were not compared yet.
+func (m *Mirror) PendingCount() (computed int, network int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return len(m.computed), len(m.network)
+}
+
+// VerifiedCount returns the amount of legacy milestones whose receipts matched.
+func (m *Mirror) VerifiedCount() uint64 {
+	return m.verifiedCount.Load()
+}
+
+// MismatchCount returns the amount of legacy milestones whose receipts did not match.
+func (m *Mirror) MismatchCount() uint64 {
+	return m.mismatchCount.Load()
+}
+
+// compareMigratedFunds checks whether both sets of migrations are equal, independent of their order.
+func compareMigratedFunds(computed []*iotago.MigratedFundsEntry, network []*iotago.MigratedFundsEntry) error {
+	if len(computed) != len(network) {
+		return fmt.Errorf("%w: %d computed migrations, %d migrations on the network", ErrReceiptMismatch, len(computed), len(network))
+	}
+
+	computed = sortedMigratedFunds(computed)
+	network = sortedMigratedFunds(network)
+
+	for i := range computed {
+		if computed[i].TailTransactionHash != network[i].TailTransactionHash {
+			return fmt.Errorf("%w: computed migration with tail transaction %s is missing on the network", ErrReceiptMismatch, iotago.EncodeHex(computed[i].TailTransactionHash[:]))
+		}
+		if computed[i].Deposit != network[i].Deposit {
+			return fmt.Errorf("%w: migration with tail transaction %s has a deposit of %d instead of %d", ErrReceiptMismatch, iotago.EncodeHex(computed[i].TailTransactionHash[:]), network[i].Deposit, computed[i].Deposit)
+		}
+		if !computed[i].Address.Equal(network[i].Address) {
+			return fmt.Errorf("%w: migration with tail transaction %s has a different address", ErrReceiptMismatch, iotago.EncodeHex(computed[i].TailTransactionHash[:]))
+		}
+	}
+
+	return nil
+}
+
+func sortedMigratedFunds(funds []*iotago.MigratedFundsEntry) []*iotago.MigratedFundsEntry {
+	sorted := make([]*iotago.MigratedFundsEntry, len(funds))
+	copy(sorted, funds)
+	sort.Slice(sorted, func(i, j int) bool {
+		return compareTailTransactionHashes(sorted[i], sorted[j]) < 0
+	})
+
+	return sorted
+}
@@ -0,0 +1,89 @@
+package migrator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/testkit"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func collectMirrorResults(mirror *migrator.Mirror) (*[]*migrator.MirrorResult, *[]*migrator.MirrorResult) {
+	verified := make([]*migrator.MirrorResult, 0)
+	mismatches := make([]*migrator.MirrorResult, 0)
+	mirror.Events.ReceiptsVerified.Hook(events.NewClosure(func(result *migrator.MirrorResult) {
+		verified = append(verified, result)
+	}))
+	mirror.Events.ReceiptsMismatch.Hook(events.NewClosure(func(result *migrator.MirrorResult) {
+		mismatches = append(mismatches, result)
+	}))
+
+	return &verified, &mismatches
+}
+
+func TestMirrorIndependentOfBatches(t *testing.T) {
+	mirror := migrator.NewMirror(migrator.State{LatestMigratedAtIndex: 100})
+	verified, mismatches := collectMirrorResults(mirror)
+
+	funds := testkit.NewMigrationsBuilder(1).Count(5).Build()
+
+	// the network split the migrations differently and in another order
+	mirror.AddComputedReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 100, Funds: funds[:2]})
+	mirror.AddNetworkReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 100, Funds: []*iotago.MigratedFundsEntry{funds[4], funds[0], funds[3]}})
+	mirror.AddComputedReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 100, Final: true, Funds: funds[2:]})
+	require.Empty(t, *verified)
+
+	computed, network := mirror.PendingCount()
+	require.Equal(t, 1, computed)
+	require.Equal(t, 1, network)
+
+	mirror.AddNetworkReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 100, Final: true, Funds: []*iotago.MigratedFundsEntry{funds[2], funds[1]}})
+	require.Len(t, *verified, 1)
+	require.Empty(t, *mismatches)
+	require.EqualValues(t, 100, (*verified)[0].MigratedAt)
+	require.Equal(t, 5, (*verified)[0].NetworkEntriesCount)
+	require.EqualValues(t, 1, mirror.VerifiedCount())
+
+	computed, network = mirror.PendingCount()
+	require.Zero(t, computed)
+	require.Zero(t, network)
+}
+
+func TestMirrorMismatch(t *testing.T) {
+	mirror := migrator.NewMirror(migrator.State{LatestMigratedAtIndex: 100})
+	_, mismatches := collectMirrorResults(mirror)
+
+	funds := testkit.NewMigrationsBuilder(2).Count(3).Build()
+	tampered := testkit.NewMigrationsBuilder(2).Count(3).Build()
+	tampered[1].Deposit++
+
+	mirror.AddComputedReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 101, Final: true, Funds: funds})
+	mirror.AddNetworkReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 101, Final: true, Funds: tampered})
+
+	mirror.AddComputedReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 102, Final: true, Funds: funds})
+	mirror.AddNetworkReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 102, Final: true, Funds: funds[:2]})
+
+	require.Len(t, *mismatches, 2)
+	require.ErrorIs(t, (*mismatches)[0].Err, migrator.ErrReceiptMismatch)
+	require.Contains(t, (*mismatches)[0].Err.Error(), "deposit")
+	require.ErrorIs(t, (*mismatches)[1].Err, migrator.ErrReceiptMismatch)
+	require.EqualValues(t, 2, mirror.MismatchCount())
+}
+
+func TestMirrorSkipsPartiallyIncludedMilestone(t *testing.T) {
+	mirror := migrator.NewMirror(migrator.State{LatestMigratedAtIndex: 100, LatestIncludedIndex: 3})
+	require.EqualValues(t, 101, mirror.StartIndex())
+
+	// the remaining migrations of the partially included milestone can't be verified
+	funds := testkit.NewMigrationsBuilder(3).Count(2).Build()
+	mirror.AddComputedReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 100, Final: true, Funds: funds})
+	mirror.AddNetworkReceipt(&iotago.ReceiptMilestoneOpt{MigratedAt: 100, Final: true, Funds: funds})
+
+	computed, network := mirror.PendingCount()
+	require.Zero(t, computed)
+	require.Zero(t, network)
+	require.Zero(t, mirror.VerifiedCount())
+}
@@ -0,0 +1,189 @@
+package migrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/iota.go/consts"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrLegacyNetworkMismatch is returned when the legacy node serves a different legacy network than the configured one.
+	ErrLegacyNetworkMismatch = errors.New("legacy node serves a different legacy network")
+)
+
+// LegacyNodeInfo is the information of the legacy node that identifies the legacy network it serves.
+type LegacyNodeInfo struct {
+	// the name of the legacy node software.
+	AppName string `json:"appName"`
+	// the version of the legacy node software.
+	AppVersion string `json:"appVersion"`
+	// the address of the legacy coordinator the legacy node follows.
+	CoordinatorAddress string `json:"coordinatorAddress"`
+	// the index of the latest legacy milestone known to the legacy node.
+	LatestMilestoneIndex iotago.MilestoneIndex `json:"latestMilestoneIndex"`
+}
+
+// LegacyNodeInfoFunc returns the information of the legacy node.
+type LegacyNodeInfoFunc func(ctx context.Context) (*LegacyNodeInfo, error)
+
+// NewLegacyNodeInfoFunc returns a LegacyNodeInfoFunc that queries the getNodeInfo command of the legacy node API at the given address.
+func NewLegacyNodeInfoFunc(httpClient HTTPClient, address string) LegacyNodeInfoFunc {
+	return func(ctx context.Context) (*LegacyNodeInfo, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader([]byte(`{"command":"getNodeInfo"}`)))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-IOTA-API-Version", "1")
+
+		res, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = res.Body.Close() }()
+
+		resBody, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("legacy node returned status code %d: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+		}
+
+		info := &LegacyNodeInfo{}
+		if err := json.Unmarshal(resBody, info); err != nil {
+			return nil, fmt.Errorf("invalid node info of the legacy node: %w", err)
+		}
+
+		return info, nil
+	}
+}
+
+// NetworkGuardStatus is the result of the latest check of the legacy network.
+type NetworkGuardStatus struct {
+	// the time of the latest successful check (zero = none yet).
+	CheckTime time.Time
+	// the information of the legacy node of the latest successful check (nil = none yet).
+	NodeInfo *LegacyNodeInfo
+	// the mismatch detected by the latest successful check (nil = the legacy node serves the configured legacy network).
+	Mismatch error
+}
+
+// NetworkGuardQueryer is a Queryer that refuses to query migrations from a legacy node that serves a different legacy network,
+// e.g. a testnet legacy node that would otherwise feed its migrations into a mainnet coordinator.
+// The legacy network is identified by the address of the legacy coordinator the legacy node follows.
+// It is checked before the first query and again once the check interval passed.
+type NetworkGuardQueryer struct {
+	// the queryer used while the legacy node serves the configured legacy network.
+	queryer Queryer
+	// returns the information of the legacy node.
+	nodeInfo LegacyNodeInfoFunc
+	// the address of the legacy coordinator of the configured legacy network.
+	coordinatorAddress string
+	// the interval in which the legacy network is checked again.
+	checkInterval time.Duration
+
+	lock   syncutils.Mutex
+	status NetworkGuardStatus
+}
+
+// NewNetworkGuardQueryer creates a new NetworkGuardQueryer.
+func NewNetworkGuardQueryer(queryer Queryer, nodeInfo LegacyNodeInfoFunc, coordinatorAddress string, checkInterval time.Duration) *NetworkGuardQueryer {
+	return &NetworkGuardQueryer{
+		queryer:            queryer,
+		nodeInfo:           nodeInfo,
+		coordinatorAddress: coordinatorAddress,
+		checkInterval:      checkInterval,
+	}
+}
+
+// Status returns the result of the latest check of the legacy network.
+func (q *NetworkGuardQueryer) Status() NetworkGuardStatus {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.status
+}
+
+// Check queries the information of the legacy node and checks that it serves the configured legacy network.
+// It returns ErrLegacyNetworkMismatch as critical error if it does not.
+func (q *NetworkGuardQueryer) Check(ctx context.Context) error {
+	info, err := q.nodeInfo(ctx)
+	if err != nil {
+		return &QueryError{Stage: StageFetch, Err: fmt.Errorf("failed to query the node info of the legacy node: %w", err)}
+	}
+
+	var mismatch error
+	switch {
+	case info.CoordinatorAddress == "":
+		mismatch = common.CriticalError(fmt.Errorf("%w: the legacy node (%s %s) doesn't report the address of its coordinator", ErrLegacyNetworkMismatch, info.AppName, info.AppVersion))
+	case !strings.EqualFold(withoutChecksum(info.CoordinatorAddress), withoutChecksum(q.coordinatorAddress)):
+		mismatch = common.CriticalError(fmt.Errorf("%w: the legacy node follows the coordinator %s, expected %s", ErrLegacyNetworkMismatch, info.CoordinatorAddress, q.coordinatorAddress))
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.status = NetworkGuardStatus{
+		CheckTime: time.Now(),
+		NodeInfo:  info,
+		Mismatch:  mismatch,
+	}
+
+	return mismatch
+}
+
+// withoutChecksum returns the address without its checksum, if it has one.
+func withoutChecksum(address string) string {
+	if len(address) > consts.HashTrytesSize {
+		return address[:consts.HashTrytesSize]
+	}
+
+	return address
+}
+
+// check checks the legacy network if it was not checked successfully within the check interval.
+func (q *NetworkGuardQueryer) check(ctx context.Context) error {
+	q.lock.Lock()
+	status := q.status
+	q.lock.Unlock()
+
+	if status.Mismatch == nil && !status.CheckTime.IsZero() && time.Since(status.CheckTime) < q.checkInterval {
+		return nil
+	}
+
+	return q.Check(ctx)
+}
+
+// QueryMigratedFunds queries the legacy node for the migrated funds of the given legacy milestone,
+// if it serves the configured legacy network.
+func (q *NetworkGuardQueryer) QueryMigratedFunds(ctx context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	if err := q.check(ctx); err != nil {
+		return nil, err
+	}
+
+	return q.queryer.QueryMigratedFunds(ctx, msIndex)
+}
+
+// QueryNextMigratedFunds queries the legacy node for the next migrated funds after the given legacy milestone,
+// if it serves the configured legacy network.
+func (q *NetworkGuardQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	if err := q.check(ctx); err != nil {
+		return 0, nil, err
+	}
+
+	return q.queryer.QueryNextMigratedFunds(ctx, startIndex)
+}
@@ -0,0 +1,112 @@
+package migrator_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+)
+
+const (
+	mainnetCoordinator = "UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ"
+	testnetCoordinator = "EQSAUZXULTTYZCLNJNTXQTQHOMOFZERHTCGTXOLTVAHKSA9OGAZDEKECURBRIXIJWNPFCQIOVFVVXJVD9"
+)
+
+// startLegacyNode starts a legacy node API that reports the coordinator address and counts the node info requests.
+func startLegacyNode(t *testing.T, coordinatorAddress *atomic.Value, requests *atomic.Int32) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "1", r.Header.Get("X-IOTA-API-Version"))
+
+		command := make(map[string]any)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&command))
+		require.Equal(t, "getNodeInfo", command["command"])
+		requests.Add(1)
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"appName":              "HORNET",
+			"appVersion":           "0.6.0",
+			"coordinatorAddress":   coordinatorAddress.Load(),
+			"latestMilestoneIndex": 3_000_000,
+		}))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func TestNetworkGuardQueryer(t *testing.T) {
+	var coordinatorAddress atomic.Value
+	coordinatorAddress.Store(mainnetCoordinator)
+	var requests atomic.Int32
+	address := startLegacyNode(t, &coordinatorAddress, &requests)
+
+	guard := migrator.NewNetworkGuardQueryer(&mockQueryer{}, migrator.NewLegacyNodeInfoFunc(http.DefaultClient, address), mainnetCoordinator, time.Hour)
+
+	// the legacy network is checked before the first query
+	_, entries, err := guard.QueryNextMigratedFunds(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, serviceTests.entries, entries)
+	require.EqualValues(t, 1, requests.Load())
+
+	status := guard.Status()
+	require.NoError(t, status.Mismatch)
+	require.Equal(t, mainnetCoordinator, status.NodeInfo.CoordinatorAddress)
+	require.EqualValues(t, 3_000_000, status.NodeInfo.LatestMilestoneIndex)
+
+	// the check is not repeated within the interval
+	_, err = guard.QueryMigratedFunds(context.Background(), serviceTests.migratedAt)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, requests.Load())
+
+	// the legacy node switched to another legacy network
+	coordinatorAddress.Store(testnetCoordinator)
+	err = guard.Check(context.Background())
+	require.ErrorIs(t, err, migrator.ErrLegacyNetworkMismatch)
+	require.Error(t, common.IsCriticalError(err))
+	require.Equal(t, migrator.ErrorClassValidation, migrator.ErrorClass(err))
+
+	// no migrations are fetched and the network is checked again with every query until it matches
+	_, err = guard.QueryMigratedFunds(context.Background(), serviceTests.migratedAt)
+	require.ErrorIs(t, err, migrator.ErrLegacyNetworkMismatch)
+	require.EqualValues(t, 3, requests.Load())
+
+	// addresses with checksum are accepted
+	coordinatorAddress.Store(strings.ToLower(mainnetCoordinator) + "ABCDEFGHI")
+	entries, err = guard.QueryMigratedFunds(context.Background(), serviceTests.migratedAt)
+	require.NoError(t, err)
+	require.Equal(t, serviceTests.entries, entries)
+
+	// a legacy node that doesn't report its coordinator can't be verified
+	coordinatorAddress.Store("")
+	require.ErrorIs(t, guard.Check(context.Background()), migrator.ErrLegacyNetworkMismatch)
+}
+
+func TestNetworkGuardQueryerUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	guard := migrator.NewNetworkGuardQueryer(&mockQueryer{}, migrator.NewLegacyNodeInfoFunc(http.DefaultClient, server.URL), mainnetCoordinator, time.Hour)
+
+	// an unavailable legacy node is a network error, not a mismatch
+	_, err := guard.QueryMigratedFunds(context.Background(), serviceTests.migratedAt)
+	require.ErrorIs(t, err, &migrator.QueryError{})
+	require.NotErrorIs(t, err, migrator.ErrLegacyNetworkMismatch)
+	require.Equal(t, migrator.ErrorClassNetwork, migrator.ErrorClass(err))
+	require.Contains(t, err.Error(), "unavailable")
+	require.True(t, guard.Status().CheckTime.IsZero())
+}
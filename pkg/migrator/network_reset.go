@@ -0,0 +1,39 @@
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrNetworkResetMismatch is returned when the migrator state belongs to another network reset than the coordinator state.
+	ErrNetworkResetMismatch = errors.New("migrator state does not match the network reset of the coordinator")
+)
+
+// SetNetworkResetIndex sets the index of the first milestone after the latest network reset of the coordinator (0 = never reset).
+// A bootstrapped state records the index, a loaded state must have been bootstrapped after the same network reset,
+// so that the migrator state of the previous network is never continued after a reset and vice versa.
+// If it is not set, e.g. when no coordinator is running, the network reset of the state is not checked.
+// SetNetworkResetIndex must be called before InitState.
+func (s *Service) SetNetworkResetIndex(networkResetIndex iotago.MilestoneIndex) {
+	s.networkResetIndex = &networkResetIndex
+}
+
+// checkNetworkReset checks that the state was bootstrapped after the same network reset as the coordinator.
+func (s *Service) checkNetworkReset(state State) error {
+	if s.networkResetIndex == nil || state.NetworkResetIndex == *s.networkResetIndex {
+		return nil
+	}
+
+	switch {
+	case *s.networkResetIndex == 0:
+		return fmt.Errorf("%w: the state was bootstrapped after a network reset at milestone %d, but the network was never reset", ErrNetworkResetMismatch, state.NetworkResetIndex)
+	case state.NetworkResetIndex == 0:
+		return fmt.Errorf("%w: the network was reset at milestone %d, but the state was bootstrapped before, bootstrap the migrator again", ErrNetworkResetMismatch, *s.networkResetIndex)
+	default:
+		return fmt.Errorf("%w: the state was bootstrapped after a network reset at milestone %d, but the network was reset at milestone %d", ErrNetworkResetMismatch, state.NetworkResetIndex, *s.networkResetIndex)
+	}
+}
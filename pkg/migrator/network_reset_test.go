@@ -0,0 +1,61 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestNetworkReset(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+
+	// the state is bootstrapped after the network was reset at milestone 1000
+	s := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+	s.SetNetworkResetIndex(1000)
+	msIndex := serviceTests.migratedAt
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+	require.EqualValues(t, 1000, s.State().NetworkResetIndex)
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	loadState := func(networkResetIndex *iotago.MilestoneIndex) error {
+		loaded := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+		if networkResetIndex != nil {
+			loaded.SetNetworkResetIndex(*networkResetIndex)
+		}
+
+		return loaded.InitState(context.Background(), nil)
+	}
+
+	sameReset := iotago.MilestoneIndex(1000)
+	require.NoError(t, loadState(&sameReset))
+
+	// the state of a reset network is never continued by a coordinator that was not reset, or reset at another index
+	neverReset := iotago.MilestoneIndex(0)
+	require.ErrorIs(t, loadState(&neverReset), migrator.ErrNetworkResetMismatch)
+	otherReset := iotago.MilestoneIndex(2000)
+	require.ErrorIs(t, loadState(&otherReset), migrator.ErrNetworkResetMismatch)
+
+	// without a coordinator the network reset is not checked
+	require.NoError(t, loadState(nil))
+}
+
+func TestNetworkResetStaleState(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+
+	// the state of the previous network was never reset
+	s := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+	msIndex := serviceTests.migratedAt
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	loaded := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+	loaded.SetNetworkResetIndex(1000)
+	err := loaded.InitState(context.Background(), nil)
+	require.ErrorIs(t, err, migrator.ErrNetworkResetMismatch)
+	require.ErrorIs(t, err, &migrator.StateError{Stage: migrator.StageInitState})
+}
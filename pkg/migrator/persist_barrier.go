@@ -0,0 +1,32 @@
+package migrator
+
+// PersistBarrier is released once a snapshot of the state was written to the state file.
+type PersistBarrier struct {
+	done chan struct{}
+	err  error
+}
+
+// newPersistBarrier creates a new PersistBarrier that is not released yet.
+func newPersistBarrier() *PersistBarrier {
+	return &PersistBarrier{
+		done: make(chan struct{}),
+	}
+}
+
+// release releases the barrier with the result of the write.
+func (b *PersistBarrier) release(err error) {
+	b.err = err
+	close(b.done)
+}
+
+// Done returns a channel that is closed once the barrier is released.
+func (b *PersistBarrier) Done() <-chan struct{} {
+	return b.done
+}
+
+// Wait blocks until the barrier is released and returns the error of the write, if any.
+func (b *PersistBarrier) Wait() error {
+	<-b.done
+
+	return b.err
+}
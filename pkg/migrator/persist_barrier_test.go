@@ -0,0 +1,46 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestPersistStateAsync(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+
+	s := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	// the snapshots are written in the order they were taken
+	sending := s.PersistStateAsync(true)
+	sent := s.PersistStateAsync(false)
+	require.NoError(t, sent.Wait())
+
+	select {
+	case <-sending.Done():
+	default:
+		require.FailNow(t, "the earlier snapshot was not written before the later one")
+	}
+	require.NoError(t, sending.Wait())
+
+	state := &migrator.State{}
+	require.NoError(t, ioutils.ReadJSONFromFile(stateFilePath, state))
+	require.False(t, state.SendingReceipt)
+	require.Equal(t, s.State(), *state)
+
+	// a failed write is returned by the barrier
+	s = migrator.NewService(&mockQueryer{}, filepath.Join(t.TempDir(), "missing", "migrator.state"), len(serviceTests.entries))
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	var stateErr *migrator.StateError
+	require.ErrorAs(t, s.PersistStateAsync(true).Wait(), &stateErr)
+	require.Equal(t, migrator.StagePersistState, stateErr.Stage)
+}
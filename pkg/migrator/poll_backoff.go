@@ -0,0 +1,35 @@
+package migrator
+
+import (
+	"time"
+)
+
+// PollBackoff slows down the polls of the legacy node while the legacy network is idle.
+// After every poll that returned no new legacy milestone, the next poll is delayed by Step more than the previous one,
+// up to MaxDelay. The delay is reset as soon as a poll returns a new legacy milestone.
+type PollBackoff struct {
+	// the amount the delay is increased by after every empty poll (0 = disabled).
+	Step time.Duration
+	// the maximum delay between two polls.
+	MaxDelay time.Duration
+}
+
+// Delay returns the delay before the next poll after the given amount of consecutive empty polls.
+func (b PollBackoff) Delay(emptyPolls int) time.Duration {
+	if b.Step <= 0 || emptyPolls <= 0 {
+		return 0
+	}
+
+	// the delay is capped before multiplying, so that it can't overflow
+	if b.MaxDelay > 0 && time.Duration(emptyPolls) > b.MaxDelay/b.Step {
+		return b.MaxDelay
+	}
+
+	return time.Duration(emptyPolls) * b.Step
+}
+
+// SetPollBackoff sets the backoff of the polls of the legacy node while the legacy network is idle.
+// SetPollBackoff must be called before Start.
+func (s *Service) SetPollBackoff(backoff PollBackoff) {
+	s.pollBackoff = backoff
+}
@@ -0,0 +1,111 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestPollBackoffDelay(t *testing.T) {
+	backoff := migrator.PollBackoff{Step: time.Second, MaxDelay: 3 * time.Second}
+	require.Zero(t, backoff.Delay(0))
+	require.Equal(t, time.Second, backoff.Delay(1))
+	require.Equal(t, 2*time.Second, backoff.Delay(2))
+	require.Equal(t, 3*time.Second, backoff.Delay(3))
+	require.Equal(t, 3*time.Second, backoff.Delay(1_000_000_000))
+
+	// without a step, the legacy node is polled without delay
+	require.Zero(t, migrator.PollBackoff{MaxDelay: time.Second}.Delay(10))
+}
+
+var errScriptEnded = errors.New("script ended")
+
+// timedQueryer returns the given results for the queries of the next migrations in order and records when they were queried.
+type timedQueryer struct {
+	mockQueryer
+
+	mutex      sync.Mutex
+	results    []scriptedResult
+	queryTimes []time.Time
+}
+
+func (q *timedQueryer) QueryNextMigratedFunds(_ context.Context, _ iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.queryTimes = append(q.queryTimes, time.Now())
+	if len(q.results) == 0 {
+		return 0, nil, errScriptEnded
+	}
+
+	result := q.results[0]
+	q.results = q.results[1:]
+
+	return result.stopIndex, result.migratedFunds, nil
+}
+
+func TestPollBackoff(t *testing.T) {
+	const step = 100 * time.Millisecond
+
+	q := &timedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 5, migratedFunds: serviceTests.entries},
+			// no new legacy milestones
+			{stopIndex: 5},
+			{stopIndex: 5},
+			// new legacy milestones without migrations
+			{stopIndex: 7},
+			// no new legacy milestones
+			{stopIndex: 7},
+		},
+	}
+
+	s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), len(serviceTests.entries))
+	s.SetPollBackoff(migrator.PollBackoff{Step: step, MaxDelay: 10 * step})
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// receive the receipts, so that the service continues to query the legacy node
+	go func() {
+		for ctx.Err() == nil {
+			s.Receipt(ctx)
+		}
+	}()
+
+	serviceErr := make(chan error, 1)
+	go s.Start(ctx, func(err error) bool {
+		serviceErr <- err
+
+		return false
+	})
+
+	select {
+	case err := <-serviceErr:
+		require.ErrorIs(t, err, errScriptEnded)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the migrator did not finish the script")
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	require.Len(t, q.queryTimes, 6)
+
+	// the delay increases with every empty poll
+	require.GreaterOrEqual(t, q.queryTimes[2].Sub(q.queryTimes[1]), step)
+	require.GreaterOrEqual(t, q.queryTimes[3].Sub(q.queryTimes[2]), 2*step)
+	// the delay is reset once a new legacy milestone appears
+	require.Less(t, q.queryTimes[4].Sub(q.queryTimes[3]), 2*step)
+	require.GreaterOrEqual(t, q.queryTimes[5].Sub(q.queryTimes[4]), step)
+	require.Less(t, q.queryTimes[5].Sub(q.queryTimes[4]), 2*step)
+}
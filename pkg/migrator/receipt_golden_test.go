@@ -0,0 +1,101 @@
+package migrator_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/testkit"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// collectReceipts runs a service on the queryer fixture until it created the given amount of receipts.
+func collectReceipts(t *testing.T, q migrator.Queryer, bootstrapIndex iotago.MilestoneIndex, maxEntries int, maxReceiptSize int, count int) []*iotago.ReceiptMilestoneOpt {
+	s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), maxEntries)
+	s.SetMaxReceiptSize(maxReceiptSize)
+	require.NoError(t, s.InitState(context.Background(), &bootstrapIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, func(err error) bool {
+		t.Errorf("migrator service failed: %s", err)
+
+		return false
+	})
+
+	receipts := make([]*iotago.ReceiptMilestoneOpt, 0, count)
+	require.Eventually(t, func() bool {
+		if receipt := s.Receipt(context.Background()); receipt != nil {
+			receipts = append(receipts, receipt)
+		}
+
+		return len(receipts) == count
+	}, 5*time.Second, time.Millisecond)
+
+	return receipts
+}
+
+// assertGoldenReceipts compares the binary serialization of the receipts with the golden files.
+func assertGoldenReceipts(t *testing.T, name string, receipts []*iotago.ReceiptMilestoneOpt) {
+	for i, receipt := range receipts {
+		data, err := testkit.SerializeReceipt(testkit.CompleteReceipt(receipt, uint64(i)))
+		require.NoError(t, err)
+
+		testkit.AssertGolden(t, fmt.Sprintf("%s_%d", name, i), data)
+	}
+}
+
+func TestGoldenReceiptSingle(t *testing.T) {
+	q := testkit.NewQueryer(10).
+		AddMigrations(5, testkit.NewMigrationsBuilder(1).Deposits(1_000_000, 2_500_000, 7_000_000).Build())
+
+	receipts := collectReceipts(t, q, 4, migrator.SensibleMaxEntriesCount, 0, 1)
+	require.EqualValues(t, 5, receipts[0].MigratedAt)
+	require.True(t, receipts[0].Final)
+
+	assertGoldenReceipts(t, "receipt_single", receipts)
+}
+
+func TestGoldenReceiptBatches(t *testing.T) {
+	q := testkit.NewQueryer(10).
+		AddMigrations(5, testkit.NewMigrationsBuilder(2).Count(5).Build()).
+		AddMigrations(9, testkit.NewMigrationsBuilder(3).Count(2).Deposit(3_000_000).Build())
+
+	receipts := collectReceipts(t, q, 4, 2, 0, 4)
+
+	for i, expected := range []struct {
+		migratedAt   iotago.MilestoneIndex
+		final        bool
+		entriesCount int
+	}{
+		{5, false, 2},
+		{5, false, 2},
+		{5, true, 1},
+		{9, true, 2},
+	} {
+		require.Equal(t, expected.migratedAt, receipts[i].MigratedAt)
+		require.Equal(t, expected.final, receipts[i].Final)
+		require.Len(t, receipts[i].Funds, expected.entriesCount)
+	}
+
+	assertGoldenReceipts(t, "receipt_batches", receipts)
+}
+
+func TestGoldenReceiptMaxSize(t *testing.T) {
+	entries := testkit.NewMigrationsBuilder(4).Count(3).Build()
+	q := testkit.NewQueryer(10).AddMigrations(7, entries)
+
+	// only two entries fit into a receipt
+	receipts := collectReceipts(t, q, 4, migrator.SensibleMaxEntriesCount, migrator.ReceiptSize(7, entries[:2]), 2)
+	require.Len(t, receipts[0].Funds, 2)
+	require.False(t, receipts[0].Final)
+	require.Len(t, receipts[1].Funds, 1)
+	require.True(t, receipts[1].Final)
+
+	assertGoldenReceipts(t, "receipt_max_size", receipts)
+}
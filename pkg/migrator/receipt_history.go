@@ -0,0 +1,149 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// ReceiptHistoryValidationStrict fails the initialization of the state if the node pruned the milestone
+	// of the receipt the state is validated against.
+	ReceiptHistoryValidationStrict = "strict"
+	// ReceiptHistoryValidationFallback validates the state against the included tail transaction hashes instead,
+	// if the node pruned the milestone of the receipt the state is validated against.
+	ReceiptHistoryValidationFallback = "fallback"
+)
+
+var (
+	// ErrUnknownReceiptHistoryValidationMode is returned when an unknown receipt history validation mode is configured.
+	ErrUnknownReceiptHistoryValidationMode = errors.New("unknown receipt history validation mode")
+	// ErrReceiptHistoryPruned is returned when the node pruned the milestone of the receipt the state is validated against.
+	ErrReceiptHistoryPruned = errors.New("receipt history pruned by the node")
+	// ErrReceiptHistoryMismatch is returned when the state is behind the receipts that were confirmed by the network.
+	ErrReceiptHistoryMismatch = errors.New("migrator state does not match the receipt history")
+)
+
+// ConfirmedReceipt is a receipt that was confirmed by the network.
+type ConfirmedReceipt struct {
+	// the index of the milestone that contains the receipt.
+	MilestoneIndex iotago.MilestoneIndex
+	// the receipt.
+	Receipt *iotago.ReceiptMilestoneOpt
+}
+
+// LatestReceiptFunc returns the latest receipt issued by the coordinator that was confirmed by the network,
+// or nil if no receipt is known. It returns ErrReceiptHistoryPruned if the node already pruned the milestone of the receipt.
+type LatestReceiptFunc = func(ctx context.Context) (*ConfirmedReceipt, error)
+
+// ReceiptHistoryValidation validates the restored state against the receipts that were confirmed by the network,
+// so that no receipts are issued on top of an outdated state, e.g. a restored backup.
+type ReceiptHistoryValidation struct {
+	// the validation mode.
+	Mode string
+	// returns the latest receipt the state is validated against.
+	LatestReceiptFunc LatestReceiptFunc
+}
+
+// NewReceiptHistoryValidation creates a new ReceiptHistoryValidation.
+func NewReceiptHistoryValidation(mode string, latestReceiptFunc LatestReceiptFunc) (*ReceiptHistoryValidation, error) {
+	switch mode {
+	case ReceiptHistoryValidationStrict, ReceiptHistoryValidationFallback:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownReceiptHistoryValidationMode, mode)
+	}
+
+	return &ReceiptHistoryValidation{
+		Mode:              mode,
+		LatestReceiptFunc: latestReceiptFunc,
+	}, nil
+}
+
+// ReceiptHistoryFallback describes the validation of the state against the included tail transaction hashes,
+// after the node pruned the milestone of the latest receipt.
+type ReceiptHistoryFallback struct {
+	// the reason why the state could not be validated against the receipt history.
+	Err error
+	// the latest legacy milestone index of the included tail transaction hashes.
+	IncludedMigratedAtIndex iotago.MilestoneIndex
+	// the amount of included tail transaction hashes.
+	IncludedHashesCount int
+	// whether the state was validated, it can't be validated if the included tail transaction hashes are not tracked.
+	Validated bool
+}
+
+// ReceiptHistoryFallbackCaller is an event caller which gets a receipt history fallback passed.
+func ReceiptHistoryFallbackCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(*ReceiptHistoryFallback))(params[0].(*ReceiptHistoryFallback))
+}
+
+// SetReceiptHistoryValidation sets the validation of the restored state against the receipts that were confirmed by the network.
+// SetReceiptHistoryValidation must be called before InitState.
+func (s *Service) SetReceiptHistoryValidation(validation *ReceiptHistoryValidation) {
+	s.receiptHistoryValidation = validation
+}
+
+// validateReceiptHistory validates the restored state against the latest receipt that was confirmed by the network.
+// The state may be ahead of the receipt, e.g. if the latest legacy milestones contained no migrations,
+// but all migrations of the receipt must have been included at the legacy milestone of the receipt.
+func (s *Service) validateReceiptHistory(ctx context.Context, state State) error {
+	if s.receiptHistoryValidation == nil {
+		return nil
+	}
+
+	confirmed, err := s.receiptHistoryValidation.LatestReceiptFunc(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrReceiptHistoryPruned) || s.receiptHistoryValidation.Mode == ReceiptHistoryValidationStrict {
+			return err
+		}
+
+		return s.validateIncludedHashesHistory(state, err)
+	}
+
+	if confirmed == nil {
+		return nil
+	}
+
+	if confirmed.Receipt.MigratedAt > state.LatestMigratedAtIndex {
+		return fmt.Errorf("%w: receipt of milestone %d migrated legacy milestone %d, but the state is at legacy milestone %d",
+			ErrReceiptHistoryMismatch, confirmed.MilestoneIndex, confirmed.Receipt.MigratedAt, state.LatestMigratedAtIndex)
+	}
+
+	if s.includedHashes == nil {
+		return nil
+	}
+
+	for _, entry := range confirmed.Receipt.Funds {
+		if migratedAt, exists := s.includedHashes.MigratedAt(entry.TailTransactionHash); !exists || migratedAt != confirmed.Receipt.MigratedAt {
+			return fmt.Errorf("%w: tail transaction hash %s of the receipt of milestone %d was not included at legacy milestone %d",
+				ErrReceiptHistoryMismatch, iotago.EncodeHex(entry.TailTransactionHash[:]), confirmed.MilestoneIndex, confirmed.Receipt.MigratedAt)
+		}
+	}
+
+	return nil
+}
+
+// validateIncludedHashesHistory validates the state against the included tail transaction hashes,
+// which are the local record of all migrations that were included in receipts.
+// Their digest was already verified against the state, so only the legacy milestone index is checked.
+func (s *Service) validateIncludedHashesHistory(state State, prunedErr error) error {
+	fallback := &ReceiptHistoryFallback{Err: prunedErr}
+
+	if s.includedHashes != nil {
+		fallback.IncludedMigratedAtIndex = s.includedHashes.LatestMigratedAt()
+		fallback.IncludedHashesCount = s.includedHashes.Len()
+		if fallback.IncludedMigratedAtIndex > state.LatestMigratedAtIndex {
+			return fmt.Errorf("%w: migrations of legacy milestone %d were included, but the state is at legacy milestone %d (%s)",
+				ErrReceiptHistoryMismatch, fallback.IncludedMigratedAtIndex, state.LatestMigratedAtIndex, prunedErr)
+		}
+		fallback.Validated = true
+	}
+
+	s.Events.ReceiptHistoryFallback.Trigger(fallback)
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// persistReceiptState bootstraps a service, includes the migrations of serviceTests in a receipt and persists the state.
+// It returns the paths to the state file and the included hashes file.
+func persistReceiptState(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	stateFilePath := filepath.Join(dir, "migrator.state")
+	includedHashesFilePath := filepath.Join(dir, "included_hashes.bin")
+
+	includedHashes, err := migrator.LoadIncludedHashes(includedHashesFilePath)
+	require.NoError(t, err)
+
+	s := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+	s.SetIncludedHashes(includedHashes)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	require.Eventually(t, func() bool {
+		return s.Receipt(context.Background()) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, s.PersistState(context.Background(), false))
+	require.Equal(t, serviceTests.migratedAt, s.State().LatestMigratedAtIndex)
+
+	return stateFilePath, includedHashesFilePath
+}
+
+// restoreWithReceiptHistory restores the persisted state with the given receipt history validation.
+func restoreWithReceiptHistory(t *testing.T, stateFilePath string, includedHashesFilePath string, mode string, latestReceiptFunc migrator.LatestReceiptFunc) (*migrator.Service, error) {
+	t.Helper()
+
+	includedHashes, err := migrator.LoadIncludedHashes(includedHashesFilePath)
+	require.NoError(t, err)
+
+	validation, err := migrator.NewReceiptHistoryValidation(mode, latestReceiptFunc)
+	require.NoError(t, err)
+
+	s := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+	s.SetIncludedHashes(includedHashes)
+	s.SetReceiptHistoryValidation(validation)
+
+	return s, s.InitState(context.Background(), nil)
+}
+
+func TestReceiptHistoryValidation(t *testing.T) {
+	stateFilePath, includedHashesFilePath := persistReceiptState(t)
+
+	confirmedReceipt := func(receipt *iotago.ReceiptMilestoneOpt) migrator.LatestReceiptFunc {
+		return func(_ context.Context) (*migrator.ConfirmedReceipt, error) {
+			return &migrator.ConfirmedReceipt{MilestoneIndex: 10, Receipt: receipt}, nil
+		}
+	}
+
+	// the state matches the receipt that was confirmed by the network
+	_, err := restoreWithReceiptHistory(t, stateFilePath, includedHashesFilePath, migrator.ReceiptHistoryValidationStrict, confirmedReceipt(&iotago.ReceiptMilestoneOpt{
+		MigratedAt: serviceTests.migratedAt,
+		Final:      false,
+		Funds:      serviceTests.entries,
+	}))
+	require.NoError(t, err)
+
+	// no receipt is known yet
+	_, err = restoreWithReceiptHistory(t, stateFilePath, includedHashesFilePath, migrator.ReceiptHistoryValidationStrict, func(_ context.Context) (*migrator.ConfirmedReceipt, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	// the state is behind the network, e.g. because an old backup was restored
+	_, err = restoreWithReceiptHistory(t, stateFilePath, includedHashesFilePath, migrator.ReceiptHistoryValidationStrict, confirmedReceipt(&iotago.ReceiptMilestoneOpt{
+		MigratedAt: serviceTests.migratedAt + 1,
+		Funds:      []*iotago.MigratedFundsEntry{{TailTransactionHash: iotago.LegacyTailTransactionHash{100}, Address: &iotago.Ed25519Address{}, Deposit: 1_000_000}},
+	}))
+	require.ErrorIs(t, err, migrator.ErrReceiptHistoryMismatch)
+
+	// the migrations of the receipt were not included
+	_, err = restoreWithReceiptHistory(t, stateFilePath, includedHashesFilePath, migrator.ReceiptHistoryValidationStrict, confirmedReceipt(&iotago.ReceiptMilestoneOpt{
+		MigratedAt: serviceTests.migratedAt,
+		Funds:      []*iotago.MigratedFundsEntry{{TailTransactionHash: iotago.LegacyTailTransactionHash{100}, Address: &iotago.Ed25519Address{}, Deposit: 1_000_000}},
+	}))
+	require.ErrorIs(t, err, migrator.ErrReceiptHistoryMismatch)
+
+	_, err = migrator.NewReceiptHistoryValidation("lenient", nil)
+	require.ErrorIs(t, err, migrator.ErrUnknownReceiptHistoryValidationMode)
+}
+
+func TestReceiptHistoryPruned(t *testing.T) {
+	stateFilePath, includedHashesFilePath := persistReceiptState(t)
+
+	pruned := func(_ context.Context) (*migrator.ConfirmedReceipt, error) {
+		return nil, migrator.ErrReceiptHistoryPruned
+	}
+
+	// the strict mode refuses to restore the state
+	_, err := restoreWithReceiptHistory(t, stateFilePath, includedHashesFilePath, migrator.ReceiptHistoryValidationStrict, pruned)
+	require.ErrorIs(t, err, migrator.ErrReceiptHistoryPruned)
+
+	// the fallback mode validates the state against the included tail transaction hashes
+	var fallback *migrator.ReceiptHistoryFallback
+	includedHashes, err := migrator.LoadIncludedHashes(includedHashesFilePath)
+	require.NoError(t, err)
+	validation, err := migrator.NewReceiptHistoryValidation(migrator.ReceiptHistoryValidationFallback, pruned)
+	require.NoError(t, err)
+
+	s := migrator.NewService(&mockQueryer{}, stateFilePath, len(serviceTests.entries))
+	s.SetIncludedHashes(includedHashes)
+	s.SetReceiptHistoryValidation(validation)
+	s.Events.ReceiptHistoryFallback.Hook(events.NewClosure(func(f *migrator.ReceiptHistoryFallback) {
+		fallback = f
+	}))
+	require.NoError(t, s.InitState(context.Background(), nil))
+
+	require.NotNil(t, fallback)
+	require.True(t, fallback.Validated)
+	require.ErrorIs(t, fallback.Err, migrator.ErrReceiptHistoryPruned)
+	require.Equal(t, serviceTests.migratedAt, fallback.IncludedMigratedAtIndex)
+	require.Equal(t, len(serviceTests.entries), fallback.IncludedHashesCount)
+
+	// other errors are never tolerated
+	_, err = restoreWithReceiptHistory(t, stateFilePath, includedHashesFilePath, migrator.ReceiptHistoryValidationFallback, func(_ context.Context) (*migrator.ConfirmedReceipt, error) {
+		return nil, context.DeadlineExceeded
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,75 @@
+package migrator
+
+import (
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// ReceiptLimits are the limits of the receipts issued by the migrator.
+type ReceiptLimits struct {
+	// the maximum serialized size of a receipt, so that the milestone containing it doesn't exceed the protocol limits (0 = not known).
+	MaxReceiptSize int
+	// the maximum serialized size of a receipt, so that the milestone containing it stays below the next PoW requirement step (0 = not known).
+	PoWStepReceiptSize int
+	// the configured maximum amount of entries of a receipt (0 = derived from the receipt size below the PoW requirement step).
+	ConfiguredMaxEntries int
+	// the amount of entries that fit into a receipt below the PoW requirement step (0 = not known or none fit).
+	DerivedMaxEntries int
+	// the maximum amount of entries of a receipt that is applied.
+	MaxEntries int
+}
+
+// MaxReceiptEntries returns the amount of migrations that fit into a receipt of the given maximum serialized size,
+// limited to the maximum amount of entries allowed by the protocol.
+// All migrations target Ed25519 addresses, so every entry has the same size.
+func MaxReceiptEntries(maxReceiptSize int) int {
+	entry := &iotago.MigratedFundsEntry{Address: &iotago.Ed25519Address{}}
+
+	emptySize := ReceiptSize(0, nil)
+	entrySize := ReceiptSize(0, []*iotago.MigratedFundsEntry{entry}) - emptySize
+	if maxReceiptSize < emptySize+entrySize {
+		return 0
+	}
+
+	maxEntries := (maxReceiptSize - emptySize) / entrySize
+	if maxEntries > iotago.MaxMigratedFundsEntryCount {
+		return iotago.MaxMigratedFundsEntryCount
+	}
+
+	return maxEntries
+}
+
+// ReceiptLimits returns the limits of the receipts.
+// If no amount is configured, the amount of entries that keeps the milestones below the next PoW requirement step is used,
+// which is derived from the actual amount of parents and signatures of the milestones. The applied amount is always limited
+// to the amount that fits into the maximum receipt size. SensibleMaxEntriesCount is only used if the receipt sizes are not known.
+func (s *Service) ReceiptLimits() ReceiptLimits {
+	limits := ReceiptLimits{
+		MaxReceiptSize:       int(s.maxReceiptSize.Load()),
+		PoWStepReceiptSize:   int(s.powStepReceiptSize.Load()),
+		ConfiguredMaxEntries: s.receiptMaxEntries,
+		MaxEntries:           s.receiptMaxEntries,
+	}
+
+	if limits.PoWStepReceiptSize > 0 {
+		limits.DerivedMaxEntries = MaxReceiptEntries(limits.PoWStepReceiptSize)
+		if limits.MaxEntries <= 0 {
+			limits.MaxEntries = limits.DerivedMaxEntries
+		}
+	}
+
+	if limits.MaxReceiptSize > 0 {
+		if fittingEntries := MaxReceiptEntries(limits.MaxReceiptSize); limits.MaxEntries > fittingEntries {
+			limits.MaxEntries = fittingEntries
+		}
+	}
+
+	if limits.MaxEntries <= 0 {
+		// a receipt with a single entry that doesn't fit is rejected by the size check
+		limits.MaxEntries = SensibleMaxEntriesCount
+		if limits.MaxReceiptSize > 0 || limits.PoWStepReceiptSize > 0 {
+			limits.MaxEntries = 1
+		}
+	}
+
+	return limits
+}
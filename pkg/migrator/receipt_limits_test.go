@@ -0,0 +1,76 @@
+package migrator_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestMaxReceiptEntries(t *testing.T) {
+	entries := make([]*iotago.MigratedFundsEntry, 20)
+	for i := range entries {
+		entries[i] = &iotago.MigratedFundsEntry{Address: &iotago.Ed25519Address{}, Deposit: 1_000_000}
+	}
+
+	// exactly the entries of a receipt of the given size fit into it
+	for _, count := range []int{1, 2, 7, 20} {
+		size := migrator.ReceiptSize(1, entries[:count])
+		require.Equal(t, count, migrator.MaxReceiptEntries(size))
+		require.Equal(t, count-1, migrator.MaxReceiptEntries(size-1))
+	}
+
+	require.Zero(t, migrator.MaxReceiptEntries(0))
+	require.Equal(t, iotago.MaxMigratedFundsEntryCount, migrator.MaxReceiptEntries(iotago.BlockBinSerializedMaxSize*10))
+}
+
+func TestReceiptLimits(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+	entry := &iotago.MigratedFundsEntry{Address: &iotago.Ed25519Address{}}
+	maxReceiptSize := migrator.ReceiptSize(0, []*iotago.MigratedFundsEntry{entry, entry, entry})
+	powStepReceiptSize := migrator.ReceiptSize(0, []*iotago.MigratedFundsEntry{entry, entry})
+
+	// the configured amount is used as long as the receipt sizes are not known
+	s := migrator.NewService(nil, stateFilePath, 5)
+	require.Equal(t, migrator.ReceiptLimits{ConfiguredMaxEntries: 5, MaxEntries: 5}, s.ReceiptLimits())
+
+	// a configured amount may exceed the PoW requirement step, but it is limited to the amount that fits into the milestone
+	s.SetMaxReceiptSize(maxReceiptSize)
+	s.SetPoWStepReceiptSize(powStepReceiptSize)
+	require.Equal(t, migrator.ReceiptLimits{
+		MaxReceiptSize:       maxReceiptSize,
+		PoWStepReceiptSize:   powStepReceiptSize,
+		ConfiguredMaxEntries: 5,
+		DerivedMaxEntries:    2,
+		MaxEntries:           3,
+	}, s.ReceiptLimits())
+
+	s = migrator.NewService(nil, stateFilePath, 1)
+	s.SetMaxReceiptSize(maxReceiptSize)
+	s.SetPoWStepReceiptSize(powStepReceiptSize)
+	require.Equal(t, 1, s.ReceiptLimits().MaxEntries)
+
+	// without a configured amount, SensibleMaxEntriesCount is the fallback until the receipt sizes are known
+	s = migrator.NewService(nil, stateFilePath, 0)
+	require.Equal(t, migrator.SensibleMaxEntriesCount, s.ReceiptLimits().MaxEntries)
+
+	// then the receipts stay below the next PoW requirement step
+	s.SetMaxReceiptSize(maxReceiptSize)
+	s.SetPoWStepReceiptSize(powStepReceiptSize)
+	require.Equal(t, migrator.ReceiptLimits{
+		MaxReceiptSize:     maxReceiptSize,
+		PoWStepReceiptSize: powStepReceiptSize,
+		DerivedMaxEntries:  2,
+		MaxEntries:         2,
+	}, s.ReceiptLimits())
+
+	// if the milestones exceed the step even without a receipt, a receipt still contains a single entry
+	s.SetPoWStepReceiptSize(-100)
+	limits := s.ReceiptLimits()
+	require.Equal(t, 1, limits.PoWStepReceiptSize)
+	require.Zero(t, limits.DerivedMaxEntries)
+	require.Equal(t, 1, limits.MaxEntries)
+}
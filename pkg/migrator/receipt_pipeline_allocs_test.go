@@ -0,0 +1,73 @@
+//go:build !race
+
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// receiptPipelineAllocBudgets are the maximum allocations per operation of the hot path of the receipt pipeline,
+// independent of the amount of migrations. Raise them only if the additional allocations are intended.
+var receiptPipelineAllocBudgets = map[string]float64{
+	"nextMigrations": 0,
+	"nextBatch":      0,
+	"receipt":        3,
+	"persistState":   25,
+}
+
+// the allocation budgets are not checked with the race detector, because it allocates on its own.
+func TestReceiptPipelineAllocations(t *testing.T) {
+	for _, size := range benchmarkBatchSizes {
+		t.Run(fmt.Sprintf("entries=%d", size), func(t *testing.T) {
+			entries := benchmarkEntries(size)
+			s := newBenchmarkService(t, entries)
+			ctx := context.Background()
+
+			startIndex := iotago.MilestoneIndex(2)
+			requireAllocBudget(t, "nextMigrations", func() {
+				if _, _, err := s.nextMigrations(ctx, startIndex); err != nil {
+					t.Fatal(err)
+				}
+			})
+
+			requireAllocBudget(t, "nextBatch", func() {
+				for migratedFunds := entries; len(migratedFunds) > 0; {
+					batch, _, err := s.nextBatch(startIndex, migratedFunds)
+					if err != nil {
+						t.Fatal(err)
+					}
+					migratedFunds = migratedFunds[len(batch):]
+				}
+			})
+
+			// the result is reused, so that only the allocations of the receipt are counted
+			result := &migrationResult{stopIndex: startIndex, lastBatch: true, migratedFunds: entries}
+			requireAllocBudget(t, "receipt", func() {
+				s.migrations <- result
+				if s.Receipt(ctx) == nil {
+					t.Fatal("no receipt created")
+				}
+			})
+
+			requireAllocBudget(t, "persistState", func() {
+				if err := s.PersistState(ctx, false); err != nil {
+					t.Fatal(err)
+				}
+			})
+		})
+	}
+}
+
+// requireAllocBudget checks that the average allocations of f don't exceed the budget of the operation.
+func requireAllocBudget(t *testing.T, operation string, f func()) {
+	t.Helper()
+
+	budget := receiptPipelineAllocBudgets[operation]
+	if allocs := testing.AllocsPerRun(20, f); allocs > budget {
+		t.Errorf("%s allocates %.1f times per operation, the budget is %.0f", operation, allocs, budget)
+	}
+}
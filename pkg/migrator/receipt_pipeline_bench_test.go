@@ -0,0 +1,150 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// benchmarkBatchSizes are the amounts of migrations per legacy milestone the receipt pipeline is benchmarked with,
+// up to the maximum amount of entries of a receipt allowed by the protocol.
+var benchmarkBatchSizes = []int{1, 16, 64, iotago.MaxMigratedFundsEntryCount}
+
+// benchmarkEntries returns the given amount of valid migrations with distinct tail transaction hashes.
+func benchmarkEntries(count int) []*iotago.MigratedFundsEntry {
+	data := make([]byte, 0, count*fuzzEntrySize)
+	for i := 0; i < count; i++ {
+		data = append(data, fuzzEntry(iotago.MinMigratedFundsEntryDeposit, byte(i))...)
+	}
+
+	entries := decodeFuzzEntries(data, true)
+	// the seed only has a single byte, so the hashes are made unique explicitly
+	for i, entry := range entries {
+		entry.TailTransactionHash[0] = byte(i)
+		entry.TailTransactionHash[1] = byte(i >> 8)
+	}
+
+	return entries
+}
+
+// benchmarkQueryer returns the same migrations for every legacy milestone.
+type benchmarkQueryer struct {
+	entries []*iotago.MigratedFundsEntry
+}
+
+func (q *benchmarkQueryer) QueryMigratedFunds(_ context.Context, _ iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	return q.entries, nil
+}
+
+func (q *benchmarkQueryer) QueryNextMigratedFunds(_ context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	return startIndex, q.entries, nil
+}
+
+// newBenchmarkService creates a service with an initialized state whose receipts are limited to the size
+// of a receipt with SensibleMaxEntriesCount entries, so that the largest batches are split into multiple receipts.
+func newBenchmarkService(tb testing.TB, entries []*iotago.MigratedFundsEntry) *Service {
+	tb.Helper()
+
+	s := NewService(&benchmarkQueryer{entries: entries}, filepath.Join(tb.TempDir(), "migrator.state"), 0)
+	s.SetMaxReceiptSize(ReceiptSize(1, benchmarkEntries(SensibleMaxEntriesCount)))
+
+	msIndex := iotago.MilestoneIndex(1)
+	if err := s.InitState(context.Background(), &msIndex); err != nil {
+		tb.Fatal(err)
+	}
+
+	// the results are buffered, so that Receipt receives them without a running service
+	s.migrations = make(chan *migrationResult, 1)
+
+	return s
+}
+
+func BenchmarkReceiptPipelineNextMigrations(b *testing.B) {
+	for _, size := range benchmarkBatchSizes {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			s := newBenchmarkService(b, benchmarkEntries(size))
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, _, err := s.nextMigrations(ctx, iotago.MilestoneIndex(i+2)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReceiptPipelineNextBatch(b *testing.B) {
+	for _, size := range benchmarkBatchSizes {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			entries := benchmarkEntries(size)
+			s := newBenchmarkService(b, entries)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				// slice all migrations of the legacy milestone into receipts
+				for migratedFunds := entries; len(migratedFunds) > 0; {
+					batch, _, err := s.nextBatch(2, migratedFunds)
+					if err != nil {
+						b.Fatal(err)
+					}
+					migratedFunds = migratedFunds[len(batch):]
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReceiptPipelineReceipt(b *testing.B) {
+	for _, size := range benchmarkBatchSizes {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			entries := benchmarkEntries(size)
+			s := newBenchmarkService(b, entries)
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				s.migrations <- &migrationResult{stopIndex: iotago.MilestoneIndex(i + 2), lastBatch: true, migratedFunds: entries}
+				if s.Receipt(ctx) == nil {
+					b.Fatal("no receipt created")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReceiptPipelinePersistState(b *testing.B) {
+	for _, size := range benchmarkBatchSizes {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			entries := benchmarkEntries(size)
+			s := newBenchmarkService(b, entries)
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				s.migrations <- &migrationResult{stopIndex: iotago.MilestoneIndex(i + 2), lastBatch: true, migratedFunds: entries}
+				if s.Receipt(ctx) == nil {
+					b.Fatal("no receipt created")
+				}
+				b.StartTimer()
+
+				// the state is persisted before and after the receipt is sent
+				if err := s.PersistState(ctx, true); err != nil {
+					b.Fatal(err)
+				}
+				if err := s.PersistState(ctx, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package migrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrScreeningFailed is returned when the target address of a migration could not be screened.
+	ErrScreeningFailed = errors.New("address screening failed")
+	// ErrMigrationNotHeld is returned when a migration is released that is not held for review.
+	ErrMigrationNotHeld = errors.New("no such migration held for review")
+)
+
+// ScreeningVerdict is the result of the screening of an address.
+type ScreeningVerdict struct {
+	// whether the address was flagged and migrations to it need a manual review.
+	Flagged bool `json:"flagged"`
+	// the reason why the address was flagged.
+	Reason string `json:"reason,omitempty"`
+}
+
+// AddressScreener screens the target addresses of migrations, e.g. by asking an external compliance service.
+type AddressScreener interface {
+	// ScreenAddress returns the verdict for the given address.
+	ScreenAddress(ctx context.Context, address iotago.Address) (*ScreeningVerdict, error)
+}
+
+// HeldMigration is a migration whose target address was flagged by the screening, it is held until it was reviewed and released.
+type HeldMigration struct {
+	// the legacy milestone index the migration was confirmed at.
+	MigratedAt iotago.MilestoneIndex
+	// the held migration.
+	Entry *iotago.MigratedFundsEntry
+	// the reason why the migration is held.
+	Reason string
+}
+
+// HeldMigrationCaller is an event caller which gets a held migration passed.
+func HeldMigrationCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(*HeldMigration))(params[0].(*HeldMigration))
+}
+
+// heldMigration is a held migration that awaits its release.
+type heldMigration struct {
+	migration *HeldMigration
+	released  chan struct{}
+}
+
+// SetAddressScreener sets the screener of the target addresses of the migrations.
+// Migrations to flagged addresses trigger the MigrationHeld event, and no further migrations are passed on
+// until all held migrations of the legacy milestone were released, because the order of the migrations is part of the state.
+// If failClosed is set, migrations whose address could not be screened are held as well, otherwise they pass.
+// SetAddressScreener must be called before Start.
+func (s *Service) SetAddressScreener(screener AddressScreener, failClosed bool) {
+	s.screener = screener
+	s.screeningFailClosed = failClosed
+}
+
+// HeldMigrations returns the migrations that are held for review.
+func (s *Service) HeldMigrations() []*HeldMigration {
+	s.heldLock.Lock()
+	defer s.heldLock.Unlock()
+
+	migrations := make([]*HeldMigration, 0, len(s.heldMigrations))
+	for _, held := range s.heldMigrations {
+		migration := *held.migration
+		migrations = append(migrations, &migration)
+	}
+
+	return migrations
+}
+
+// ReleaseHeldMigration releases the held migration with the given tail transaction hash after it was reviewed.
+// Releases are not persisted, migrations that are held again after a restart need to be released again.
+func (s *Service) ReleaseHeldMigration(tailTransactionHash iotago.LegacyTailTransactionHash) error {
+	s.heldLock.Lock()
+	defer s.heldLock.Unlock()
+
+	held, exists := s.heldMigrations[tailTransactionHash]
+	if !exists {
+		return fmt.Errorf("%w: tail transaction hash %s", ErrMigrationNotHeld, iotago.EncodeHex(tailTransactionHash[:]))
+	}
+
+	close(held.released)
+	delete(s.heldMigrations, tailTransactionHash)
+
+	return nil
+}
+
+// screenMigrations screens the target addresses of the migrations of the result and blocks until all migrations
+// to flagged addresses were released. It returns false if ctx was done before.
+func (s *Service) screenMigrations(ctx context.Context, result *fetchResult) bool {
+	if s.screener == nil {
+		return true
+	}
+
+	var held []*heldMigration
+	for _, entry := range result.migratedFunds {
+		verdict, err := s.screener.ScreenAddress(ctx, entry.Address)
+		if err != nil {
+			if ctx.Err() != nil {
+				return false
+			}
+
+			err = fmt.Errorf("%w: address of tail transaction hash %s: %v", ErrScreeningFailed, iotago.EncodeHex(entry.TailTransactionHash[:]), err)
+			s.Events.SoftError.Trigger(err)
+
+			if !s.screeningFailClosed {
+				continue
+			}
+			verdict = &ScreeningVerdict{Flagged: true, Reason: err.Error()}
+		}
+
+		if !verdict.Flagged {
+			continue
+		}
+
+		held = append(held, &heldMigration{
+			migration: &HeldMigration{
+				MigratedAt: result.msIndex,
+				Entry:      entry,
+				Reason:     verdict.Reason,
+			},
+			released: make(chan struct{}),
+		})
+	}
+
+	if len(held) == 0 {
+		return true
+	}
+
+	s.heldLock.Lock()
+	for _, h := range held {
+		s.heldMigrations[h.migration.Entry.TailTransactionHash] = h
+	}
+	s.heldLock.Unlock()
+
+	for _, h := range held {
+		migration := *h.migration
+		s.Events.MigrationHeld.Trigger(&migration)
+	}
+
+	for _, h := range held {
+		select {
+		case <-h.released:
+		case <-ctx.Done():
+			s.heldLock.Lock()
+			for _, h := range held {
+				if s.heldMigrations[h.migration.Entry.TailTransactionHash] == h {
+					delete(s.heldMigrations, h.migration.Entry.TailTransactionHash)
+				}
+			}
+			s.heldLock.Unlock()
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// cachedVerdict is a screening verdict and the time it expires.
+type cachedVerdict struct {
+	verdict *ScreeningVerdict
+	expires time.Time
+}
+
+// CachingAddressScreener caches the verdicts of another AddressScreener, so that every address is only screened once per TTL.
+// Failed screenings are not cached.
+type CachingAddressScreener struct {
+	screener AddressScreener
+	ttl      time.Duration
+
+	lock     syncutils.Mutex
+	verdicts map[string]*cachedVerdict
+}
+
+// NewCachingAddressScreener creates a new CachingAddressScreener that caches the verdicts of screener for ttl.
+func NewCachingAddressScreener(screener AddressScreener, ttl time.Duration) *CachingAddressScreener {
+	return &CachingAddressScreener{
+		screener: screener,
+		ttl:      ttl,
+		verdicts: make(map[string]*cachedVerdict),
+	}
+}
+
+// ScreenAddress returns the cached verdict for the given address, or screens the address if there is none.
+func (c *CachingAddressScreener) ScreenAddress(ctx context.Context, address iotago.Address) (*ScreeningVerdict, error) {
+	key := address.Key()
+	now := time.Now()
+
+	c.lock.Lock()
+	cached, exists := c.verdicts[key]
+	if exists && now.After(cached.expires) {
+		delete(c.verdicts, key)
+		exists = false
+	}
+	c.lock.Unlock()
+
+	if exists {
+		return cached.verdict, nil
+	}
+
+	verdict, err := c.screener.ScreenAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.verdicts[key] = &cachedVerdict{verdict: verdict, expires: now.Add(c.ttl)}
+	c.lock.Unlock()
+
+	return verdict, nil
+}
+
+// HTTPScreeningRequest is the request sent to the screening service.
+type HTTPScreeningRequest struct {
+	// the hex encoded serialized address.
+	Address string `json:"address"`
+}
+
+// HTTPAddressScreener asks an external screening service for the verdict of an address.
+// The service receives a HTTPScreeningRequest via POST and answers with a ScreeningVerdict.
+type HTTPAddressScreener struct {
+	// the URL of the screening service.
+	url string
+	// the HTTP client used for the requests.
+	httpClient *http.Client
+}
+
+// NewHTTPAddressScreener creates a new HTTPAddressScreener for the screening service at the given URL.
+func NewHTTPAddressScreener(url string, timeout time.Duration) *HTTPAddressScreener {
+	return &HTTPAddressScreener{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// ScreenAddress asks the screening service for the verdict of the given address.
+func (p *HTTPAddressScreener) ScreenAddress(ctx context.Context, address iotago.Address) (*ScreeningVerdict, error) {
+	addressBytes, err := address.Serialize(serializer.DeSeriModeNoValidation, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize address: %w", err)
+	}
+
+	reqBody, err := json.Marshal(&HTTPScreeningRequest{Address: iotago.EncodeHex(addressBytes)})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("screening service returned status code %d: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+
+	verdict := &ScreeningVerdict{}
+	if err := json.Unmarshal(resBody, verdict); err != nil {
+		return nil, fmt.Errorf("unable to decode screening response: %w", err)
+	}
+
+	return verdict, nil
+}
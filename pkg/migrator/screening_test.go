@@ -0,0 +1,196 @@
+package migrator_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var errScreeningUnavailable = errors.New("screening service unavailable")
+
+// mockScreener flags the addresses in flagged and fails for the addresses in failing.
+type mockScreener struct {
+	flagged map[string]string
+	failing map[string]struct{}
+	calls   atomic.Int32
+}
+
+func (m *mockScreener) ScreenAddress(_ context.Context, address iotago.Address) (*migrator.ScreeningVerdict, error) {
+	m.calls.Add(1)
+
+	if _, failing := m.failing[address.Key()]; failing {
+		return nil, errScreeningUnavailable
+	}
+
+	reason, flagged := m.flagged[address.Key()]
+
+	return &migrator.ScreeningVerdict{Flagged: flagged, Reason: reason}, nil
+}
+
+func TestAddressScreening(t *testing.T) {
+	tests := []struct {
+		name       string
+		failClosed bool
+		held       []*iotago.MigratedFundsEntry
+	}{
+		{name: "fail open", failClosed: false, held: serviceTests.entries[1:2]},
+		{name: "fail closed", failClosed: true, held: serviceTests.entries[1:]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &scriptedQueryer{
+				results: []scriptedResult{
+					{stopIndex: serviceTests.migratedAt, migratedFunds: serviceTests.entries},
+					// the legacy node went back in time, which stops the fetching
+					{stopIndex: 1},
+				},
+			}
+
+			screener := &mockScreener{
+				flagged: map[string]string{serviceTests.entries[1].Address.Key(): "sanctioned"},
+				failing: map[string]struct{}{serviceTests.entries[2].Address.Key(): {}},
+			}
+
+			s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), len(serviceTests.entries))
+			s.SetAddressScreener(screener, tt.failClosed)
+			msIndex := serviceTests.migratedAt - 1
+			require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+			held := make(chan *migrator.HeldMigration, len(serviceTests.entries))
+			s.Events.MigrationHeld.Hook(events.NewClosure(func(migration *migrator.HeldMigration) {
+				held <- migration
+			}))
+			var softErrors atomic.Int32
+			s.Events.SoftError.Hook(events.NewClosure(func(err error) {
+				if errors.Is(err, migrator.ErrScreeningFailed) {
+					softErrors.Add(1)
+				}
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go s.Start(ctx, func(_ error) bool { return false })
+
+			for range tt.held {
+				select {
+				case migration := <-held:
+					require.EqualValues(t, serviceTests.migratedAt, migration.MigratedAt)
+				case <-time.After(5 * time.Second):
+					require.FailNow(t, "the migration was not held")
+				}
+			}
+			require.EqualValues(t, 1, softErrors.Load())
+			require.Len(t, s.HeldMigrations(), len(tt.held))
+			require.Equal(t, "sanctioned", heldMigration(t, s, serviceTests.entries[1].TailTransactionHash).Reason)
+
+			// the migrations are held until all of them were released
+			require.Never(t, func() bool { return s.Receipt(context.Background()) != nil }, 100*time.Millisecond, 10*time.Millisecond)
+			require.ErrorIs(t, s.ReleaseHeldMigration(serviceTests.entries[0].TailTransactionHash), migrator.ErrMigrationNotHeld)
+
+			for _, entry := range tt.held {
+				require.NoError(t, s.ReleaseHeldMigration(entry.TailTransactionHash))
+			}
+			require.Empty(t, s.HeldMigrations())
+
+			var receipt *iotago.ReceiptMilestoneOpt
+			require.Eventually(t, func() bool {
+				receipt = s.Receipt(context.Background())
+
+				return receipt != nil
+			}, 5*time.Second, 10*time.Millisecond)
+			require.ElementsMatch(t, serviceTests.entries, receipt.Funds)
+		})
+	}
+}
+
+func heldMigration(t *testing.T, s *migrator.Service, hash iotago.LegacyTailTransactionHash) *migrator.HeldMigration {
+	t.Helper()
+
+	for _, migration := range s.HeldMigrations() {
+		if migration.Entry.TailTransactionHash == hash {
+			return migration
+		}
+	}
+	require.FailNow(t, "the migration is not held")
+
+	return nil
+}
+
+func TestCachingAddressScreener(t *testing.T) {
+	screener := &mockScreener{
+		flagged: map[string]string{serviceTests.entries[0].Address.Key(): "sanctioned"},
+		failing: map[string]struct{}{serviceTests.entries[1].Address.Key(): {}},
+	}
+	cache := migrator.NewCachingAddressScreener(screener, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		verdict, err := cache.ScreenAddress(context.Background(), serviceTests.entries[0].Address)
+		require.NoError(t, err)
+		require.True(t, verdict.Flagged)
+	}
+	require.EqualValues(t, 1, screener.calls.Load())
+
+	// failed screenings are not cached
+	for i := 0; i < 2; i++ {
+		_, err := cache.ScreenAddress(context.Background(), serviceTests.entries[1].Address)
+		require.ErrorIs(t, err, errScreeningUnavailable)
+	}
+	require.EqualValues(t, 3, screener.calls.Load())
+}
+
+func TestHTTPAddressScreener(t *testing.T) {
+	serializedAddress := func(address iotago.Address) string {
+		addressBytes, err := address.Serialize(serializer.DeSeriModeNoValidation, nil)
+		require.NoError(t, err)
+
+		return iotago.EncodeHex(addressBytes)
+	}
+	flaggedAddress := serializedAddress(serviceTests.entries[1].Address)
+	failingAddress := serializedAddress(serviceTests.entries[2].Address)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &migrator.HTTPScreeningRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		switch request.Address {
+		case flaggedAddress:
+			_ = json.NewEncoder(w).Encode(&migrator.ScreeningVerdict{Flagged: true, Reason: "sanctioned"})
+		case failingAddress:
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_ = json.NewEncoder(w).Encode(&migrator.ScreeningVerdict{})
+		}
+	}))
+	defer server.Close()
+
+	screener := migrator.NewHTTPAddressScreener(server.URL, time.Second)
+
+	verdict, err := screener.ScreenAddress(context.Background(), serviceTests.entries[0].Address)
+	require.NoError(t, err)
+	require.False(t, verdict.Flagged)
+
+	verdict, err = screener.ScreenAddress(context.Background(), serviceTests.entries[1].Address)
+	require.NoError(t, err)
+	require.Equal(t, &migrator.ScreeningVerdict{Flagged: true, Reason: "sanctioned"}, verdict)
+
+	_, err = screener.ScreenAddress(context.Background(), serviceTests.entries[2].Address)
+	require.Error(t, err)
+}
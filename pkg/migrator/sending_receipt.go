@@ -0,0 +1,83 @@
+package migrator
+
+import (
+	"context"
+	"time"
+)
+
+// SendingReceiptWindow describes the window between persisting the state of a receipt that is sent to the network
+// and persisting the state again once the milestone containing the receipt was confirmed.
+// If the coordinator crashes inside the window, the state file has the 'sending receipt' flag set and has to be recovered manually.
+type SendingReceiptWindow struct {
+	// whether the coordinator is inside the window.
+	Active bool
+	// the time the window was entered (zero = not inside the window).
+	Since time.Time
+	// the time the coordinator has been inside the window.
+	Duration time.Duration
+	// the duration after which the coordinator is considered stuck inside the window (0 = disabled).
+	StuckThreshold time.Duration
+	// whether the coordinator has been inside the window for longer than the stuck threshold.
+	Stuck bool
+}
+
+// SetSendingReceiptStuckThreshold sets the duration after which the coordinator is considered stuck inside the 'sending receipt' window.
+// SetSendingReceiptStuckThreshold must be called before Start.
+func (s *Service) SetSendingReceiptStuckThreshold(threshold time.Duration) {
+	s.sendingReceiptStuckThreshold = threshold
+}
+
+// SendingReceiptWindow returns whether and since when the coordinator is inside the 'sending receipt' window.
+func (s *Service) SendingReceiptWindow() SendingReceiptWindow {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+
+	window := SendingReceiptWindow{
+		Active:         s.state.SendingReceipt,
+		StuckThreshold: s.sendingReceiptStuckThreshold,
+	}
+	if !window.Active || s.sendingReceiptSince.IsZero() {
+		return window
+	}
+
+	window.Since = s.sendingReceiptSince
+	window.Duration = time.Since(s.sendingReceiptSince)
+	window.Stuck = window.StuckThreshold > 0 && window.Duration >= window.StuckThreshold
+
+	return window
+}
+
+// AbortSendingReceipt leaves the 'sending receipt' window without sending the receipt returned by the last call of Receipt,
+// e.g. because the milestone containing it could not be signed in time. The receipt stays pending, so it can still be sent with
+// a later milestone, but the state file is restored to the latest state without the receipt, so that its migrations are fetched
+// again if the coordinator is restarted before the receipt was sent.
+// If the given context is done before the state is durable, AbortSendingReceipt returns the error of the context.
+func (s *Service) AbortSendingReceipt(ctx context.Context) error {
+	s.persistLock.Lock()
+	defer s.persistLock.Unlock()
+
+	s.stateLock.Lock()
+	s.sendingReceiptSince = time.Time{}
+	s.state.SendingReceipt = false
+	state := s.committedState
+	s.stateLock.Unlock()
+
+	previous := s.lastPersist
+	barrier := newPersistBarrier()
+	s.lastPersist = barrier
+
+	go func() {
+		if previous != nil {
+			<-previous.Done()
+		}
+		// the hashes of the pending receipt must not be added, it was not sent
+		barrier.release(s.writeState(state, false, nil))
+	}()
+
+	select {
+	case <-barrier.Done():
+		return barrier.Wait()
+	case <-ctx.Done():
+		return &StateError{Stage: StagePersistState, Err: ctx.Err()}
+	}
+}
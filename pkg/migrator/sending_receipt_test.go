@@ -0,0 +1,63 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestSendingReceiptWindow(t *testing.T) {
+	s := migrator.NewService(&mockQueryer{}, filepath.Join(t.TempDir(), "migrator.state"), 0)
+	s.SetSendingReceiptStuckThreshold(50 * time.Millisecond)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	window := s.SendingReceiptWindow()
+	require.False(t, window.Active)
+	require.True(t, window.Since.IsZero())
+	require.Equal(t, 50*time.Millisecond, window.StuckThreshold)
+
+	require.NoError(t, s.PersistState(context.Background(), true))
+	window = s.SendingReceiptWindow()
+	require.True(t, window.Active)
+	require.False(t, window.Since.IsZero())
+	require.False(t, window.Stuck)
+	since := window.Since
+
+	// persisting the state again inside the window doesn't restart it
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, s.PersistState(context.Background(), true))
+	window = s.SendingReceiptWindow()
+	require.Equal(t, since, window.Since)
+	require.GreaterOrEqual(t, window.Duration, 60*time.Millisecond)
+	require.True(t, window.Stuck)
+
+	require.NoError(t, s.PersistState(context.Background(), false))
+	window = s.SendingReceiptWindow()
+	require.False(t, window.Active)
+	require.Zero(t, window.Duration)
+	require.False(t, window.Stuck)
+}
+
+func TestAbortSendingReceipt(t *testing.T) {
+	stateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+	s := migrator.NewService(&mockQueryer{}, stateFilePath, 0)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	require.NoError(t, s.PersistState(context.Background(), true))
+	require.NoError(t, s.AbortSendingReceipt(context.Background()))
+	require.False(t, s.SendingReceiptWindow().Active)
+	require.False(t, s.State().SendingReceipt)
+
+	// the restored state file can be loaded again
+	restarted := migrator.NewService(&mockQueryer{}, stateFilePath, 0)
+	require.NoError(t, restarted.InitState(context.Background(), nil))
+	require.Equal(t, msIndex, restarted.State().LatestMigratedAtIndex)
+}
@@ -2,22 +2,35 @@ package migrator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/iotaledger/hive.go/core/events"
 	"github.com/iotaledger/hive.go/core/ioutils"
 	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hive.go/core/timeutil"
 	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+	"github.com/iotaledger/inx-coordinator/pkg/stateversion"
+	"github.com/iotaledger/iota.go/consts"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
 const (
 	// SensibleMaxEntriesCount defines an amount of entries within receipts which allows a milestone with 8 parents and 2 sigs/pub keys
-	// to fly under the next pow requirement step.
+	// to fly under the next pow requirement step. It is only used as a fallback if no amount is configured and the receipt sizes
+	// were not set by the coordinator, otherwise the amount is derived from the actual parents and signatures of the milestones.
 	SensibleMaxEntriesCount = 110
+	// StateVersion is the version of the migrator state file schema.
+	StateVersion = 5
+	// fetchedBufferSize defines how many legacy milestones can be fetched ahead of the validation stage.
+	fetchedBufferSize = 1
 )
 
 var (
@@ -25,6 +38,26 @@ var (
 	ErrStateFileAlreadyExists = errors.New("migrator state file already exists")
 	// ErrInvalidState is returned when the content of the state file is invalid.
 	ErrInvalidState = errors.New("invalid migrator state")
+	// ErrInvalidMigrations is returned when the fetched migrations are invalid.
+	ErrInvalidMigrations = errors.New("invalid migrations")
+	// ErrReceiptTooLarge is returned when not even a receipt with a single entry fits into a milestone.
+	ErrReceiptTooLarge = errors.New("receipt exceeds the maximum size")
+	// ErrInvalidStopIndex is returned when the legacy node returned a stop index lower than a previously returned one.
+	ErrInvalidStopIndex = errors.New("invalid stop index")
+
+	// stateSchema is used to upgrade older migrator state files to the current version.
+	stateSchema = stateversion.NewSchema("migrator", StateVersion, map[uint32]stateversion.Migration{
+		// unversioned state files only lack the version field
+		stateversion.UnversionedVersion: func(_ map[string]json.RawMessage) error { return nil },
+		// version 1 lacks the cumulative migration statistics, they start at zero after the upgrade
+		1: func(_ map[string]json.RawMessage) error { return nil },
+		// version 2 lacks the first migrated at index and the receipt count, they start at zero after the upgrade
+		2: func(_ map[string]json.RawMessage) error { return nil },
+		// version 3 lacks the network reset index, the states were bootstrapped before any network reset
+		3: func(_ map[string]json.RawMessage) error { return nil },
+		// version 4 lacks the digest of the included hashes, it is taken from the included hashes file once
+		4: func(_ map[string]json.RawMessage) error { return nil },
+	})
 )
 
 // ServiceEvents are events happening around a MigratorService.
@@ -33,6 +66,36 @@ type ServiceEvents struct {
 	SoftError *events.Event
 	// MigratedFundsFetched is triggered when new migration funds were fetched from a legacy node.
 	MigratedFundsFetched *events.Event
+	// ErrorAlert is triggered when an error is encountered whose class is configured to raise an alert.
+	ErrorAlert *events.Event
+	// IndexRangeSkipped is triggered when a range of legacy milestones without migrations was skipped.
+	IndexRangeSkipped *events.Event
+	// IndexJumpDetected is triggered when the migrated at index would jump forward by more than the maximum and awaits confirmation.
+	IndexJumpDetected *events.Event
+	// MigrationHeld is triggered when a migration to a flagged address is held for review.
+	MigrationHeld *events.Event
+	// Halted is triggered when the receipt issuance is halted because a migration result could not be applied to the state.
+	Halted *events.Event
+	// ReceiptHistoryFallback is triggered when the state was validated against the included tail transaction hashes,
+	// because the node pruned the receipt history.
+	ReceiptHistoryFallback *events.Event
+}
+
+// IndexRange is a range of legacy milestone indices, both bounds are inclusive.
+type IndexRange struct {
+	StartIndex iotago.MilestoneIndex
+	EndIndex   iotago.MilestoneIndex
+}
+
+// Len returns the amount of milestone indices in the range.
+func (r *IndexRange) Len() uint32 {
+	return r.EndIndex - r.StartIndex + 1
+}
+
+// IndexRangeCaller is an event caller which gets an index range passed.
+func IndexRangeCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(*IndexRange))(params[0].(*IndexRange))
 }
 
 // MigratedFundsCaller is an event caller which gets migrated funds passed.
@@ -42,9 +105,10 @@ func MigratedFundsCaller(handler interface{}, params ...interface{}) {
 }
 
 // Queryer defines the interface used to query the migrated funds.
+// The queries return the error of the context if it is done before the legacy node answered.
 type Queryer interface {
-	QueryMigratedFunds(iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error)
-	QueryNextMigratedFunds(iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error)
+	QueryMigratedFunds(context.Context, iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error)
+	QueryNextMigratedFunds(context.Context, iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error)
 }
 
 // Service is a service querying and validating batches of migrated funds.
@@ -52,20 +116,98 @@ type Service struct {
 	Events *ServiceEvents
 
 	queryer Queryer
-	state   State
 
-	mutex      syncutils.Mutex
-	migrations chan *migrationResult
+	// stateLock only protects the state, so reading the state never waits for receipts or queries.
+	stateLock syncutils.RWMutex
+	state     State
+	// receiptLock makes receiving a migration result and applying it to the state atomic.
+	receiptLock syncutils.Mutex
+	// persistLock serializes taking the snapshots of the state that are written to the state file.
+	persistLock syncutils.Mutex
+	// the barrier of the latest snapshot of the state that is written to the state file (nil = none yet).
+	lastPersist *PersistBarrier
+	migrations  chan *migrationResult
 
 	stateFilePath     string
 	receiptMaxEntries int
+	// the amount of backups of the state file that are kept.
+	stateBackups int
+	// the tail transaction hashes of all migrations that were included in receipts (nil = not tracked).
+	includedHashes *IncludedHashes
+	// the result of the last receipt, its hashes are added to the included hashes once the receipt was sent.
+	pendingResult *migrationResult
+	// the latest state that was persisted without the 'sending receipt' flag, protected by the stateLock.
+	// It is persisted again if a receipt that was persisted as being sent is not sent after all.
+	committedState State
+	// the path to the file the fetch progress is persisted to (empty = no checkpoints).
+	fetchCheckpointFilePath string
+	// the amount of legacy milestones without migrations after which the fetch progress is persisted (0 = no checkpoints).
+	fetchCheckpointInterval uint32
+	// the fetch checkpoint loaded by InitState that fetching resumes from (nil = none).
+	fetchCheckpoint *FetchCheckpoint
+	// the backoff of the polls of the legacy node while the legacy network is idle.
+	pollBackoff PollBackoff
+	// the maximum serialized size of a receipt (0 = no limit).
+	maxReceiptSize atomic.Int64
+	// the maximum serialized size of a receipt, so that the milestone containing it stays below the next PoW requirement step (0 = not known).
+	powStepReceiptSize atomic.Int64
+	// the amount of legacy milestones without migrations that were skipped since the service was started.
+	skippedIndicesCount atomic.Uint64
+	// the maximum amount of legacy milestones the migrated at index may jump forward at once (0 = no limit).
+	maxIndexJump uint32
+	// indexJumpLock protects the pending index jump.
+	indexJumpLock syncutils.Mutex
+	// the jump of the migrated at index that awaits confirmation (nil = none).
+	pendingIndexJump *pendingIndexJump
+	// the screener of the target addresses of the migrations (nil = no screening).
+	screener AddressScreener
+	// whether migrations whose address could not be screened are held.
+	screeningFailClosed bool
+	// heldLock protects the held migrations.
+	heldLock syncutils.Mutex
+	// the migrations that are held for review.
+	heldMigrations map[iotago.LegacyTailTransactionHash]*heldMigration
+	// the index of the first milestone after the latest network reset of the coordinator (nil = not checked).
+	networkResetIndex *iotago.MilestoneIndex
+	// the validation of the restored state against the receipts confirmed by the network (nil = not validated).
+	receiptHistoryValidation *ReceiptHistoryValidation
+	// the halt of the receipt issuance (nil = receipts are issued).
+	halt atomic.Pointer[Halt]
+	// the time the 'sending receipt' window was entered, protected by the stateLock (zero = not inside the window).
+	sendingReceiptSince time.Time
+	// the duration after which the coordinator is considered stuck inside the 'sending receipt' window (0 = disabled).
+	sendingReceiptStuckThreshold time.Duration
+	// lifecycleLock protects the run of s.
+	lifecycleLock syncutils.Mutex
+	// cancels the running Start (nil = not running).
+	cancelRun context.CancelFunc
+	// closed once the running Start returned (nil = not running).
+	runDone chan struct{}
 }
 
 // State stores the latest state of the MigratorService.
+// MigratedEntriesCount, MigratedValue and ReceiptsCount are the cumulative amount and value of the migrations
+// and the amount of receipts that were issued over the lifetime of the migration.
+// FirstMigratedAtIndex is the first legacy milestone index whose migrations were included in a receipt (0 = none yet).
+// NetworkResetIndex is the index of the first milestone after the network reset the state was bootstrapped after (0 = none).
+// IncludedHashesDigest is the digest of the tail transaction hashes of all migrations that were included in receipts,
+// it is verified against the included hashes file when the state is loaded (nil = not known yet).
 type State struct {
+	Version               uint32                `json:"version"`
 	LatestMigratedAtIndex iotago.MilestoneIndex `json:"latestMigratedAtIndex"`
 	LatestIncludedIndex   uint32                `json:"latestIncludedIndex"`
 	SendingReceipt        bool                  `json:"sendingReceipt"`
+	MigratedEntriesCount  uint64                `json:"migratedEntriesCount"`
+	MigratedValue         uint64                `json:"migratedValue"`
+	FirstMigratedAtIndex  iotago.MilestoneIndex `json:"firstMigratedAtIndex"`
+	ReceiptsCount         uint64                `json:"receiptsCount"`
+	NetworkResetIndex     iotago.MilestoneIndex `json:"networkResetIndex"`
+	IncludedHashesDigest  *IncludedHashesDigest `json:"includedHashesDigest,omitempty"`
+}
+
+type fetchResult struct {
+	msIndex       iotago.MilestoneIndex
+	migratedFunds []*iotago.MigratedFundsEntry
 }
 
 type migrationResult struct {
@@ -75,16 +217,25 @@ type migrationResult struct {
 }
 
 // NewService creates a new MigratorService.
+// If receiptMaxEntries is 0, the maximum amount of entries of a receipt is derived from the receipt size below the PoW requirement step.
 func NewService(queryer Queryer, stateFilePath string, receiptMaxEntries int) *Service {
 	return &Service{
 		Events: &ServiceEvents{
-			SoftError:            events.NewEvent(events.ErrorCaller),
-			MigratedFundsFetched: events.NewEvent(MigratedFundsCaller),
+			SoftError:              events.NewEvent(events.ErrorCaller),
+			MigratedFundsFetched:   events.NewEvent(MigratedFundsCaller),
+			ErrorAlert:             events.NewEvent(ErrorClassCaller),
+			IndexRangeSkipped:      events.NewEvent(IndexRangeCaller),
+			IndexJumpDetected:      events.NewEvent(IndexJumpCaller),
+			MigrationHeld:          events.NewEvent(HeldMigrationCaller),
+			Halted:                 events.NewEvent(HaltCaller),
+			ReceiptHistoryFallback: events.NewEvent(ReceiptHistoryFallbackCaller),
 		},
 		queryer:           queryer,
 		migrations:        make(chan *migrationResult),
 		receiptMaxEntries: receiptMaxEntries,
 		stateFilePath:     stateFilePath,
+		stateBackups:      1,
+		heldMigrations:    make(map[iotago.LegacyTailTransactionHash]*heldMigration),
 	}
 }
 
@@ -92,11 +243,17 @@ func NewService(queryer Queryer, stateFilePath string, receiptMaxEntries int) *S
 // Each receipt can only consists of migrations confirmed by one milestone, it will never be larger than MaxMigratedFundsEntryCount.
 // Receipt returns nil, if there are currently no new migrations available. Although the actual API calls and
 // validations happen in the background, Receipt might block until the next receipt is ready.
-// When s is stopped, Receipt will always return nil.
-func (s *Service) Receipt() *iotago.ReceiptMilestoneOpt {
+// When s is stopped, halted or the given context is done, Receipt will always return nil.
+// If a migration result can't be applied to the state, the receipt issuance is halted instead of creating
+// a receipt that doesn't match the state, see Halted.
+func (s *Service) Receipt(ctx context.Context) *iotago.ReceiptMilestoneOpt {
 	// make the channel receive and the state update atomic, so that the state always matches the result
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.receiptLock.Lock()
+	defer s.receiptLock.Unlock()
+
+	if ctx.Err() != nil || s.halt.Load() != nil {
+		return nil
+	}
 
 	// non-blocking receive; return nil if the channel is closed or value available
 	var result *migrationResult
@@ -107,40 +264,171 @@ func (s *Service) Receipt() *iotago.ReceiptMilestoneOpt {
 	if result == nil {
 		return nil
 	}
-	s.updateState(result)
+
+	s.stateLock.Lock()
+	if err := s.updateState(result); err != nil {
+		s.haltReceipts(err, result)
+		s.stateLock.Unlock()
+
+		return nil
+	}
+	// results without migrations advance the state on idle polls, but never create a receipt that could be pending
+	if len(result.migratedFunds) > 0 {
+		s.pendingResult = result
+	}
+	s.stateLock.Unlock()
 
 	return createReceipt(result.stopIndex, result.lastBatch, result.migratedFunds)
 }
 
+// receiptPending returns whether a receipt with migrations was returned by Receipt, but not persisted as sent yet,
+// or the state is marked as sending a receipt. The caller must hold the stateLock.
+func (s *Service) receiptPending() bool {
+	return (s.pendingResult != nil && len(s.pendingResult.migratedFunds) > 0) || s.state.SendingReceipt
+}
+
+// SetIncludedHashes sets the persistent set of the tail transaction hashes of all migrations that were included in receipts.
+// Migrations that were already included are rejected, the hashes of a receipt are added when its state is persisted as sent.
+// SetIncludedHashes must be called before Start.
+func (s *Service) SetIncludedHashes(includedHashes *IncludedHashes) {
+	s.includedHashes = includedHashes
+}
+
+// SetStateBackups sets the amount of backups of the state file that are kept.
+// SetStateBackups must be called before Start.
+func (s *Service) SetStateBackups(count int) {
+	s.stateBackups = count
+}
+
+// SetMaxReceiptSize sets the maximum serialized size of a receipt, so that the milestone containing it
+// does not exceed the protocol limits. Batches of migrations are split if their receipt would be too large.
+func (s *Service) SetMaxReceiptSize(maxReceiptSize int) {
+	s.maxReceiptSize.Store(int64(maxReceiptSize))
+}
+
+// SetPoWStepReceiptSize sets the maximum serialized size of a receipt, so that the milestone containing it
+// stays below the next PoW requirement step. It defines the maximum amount of entries if no amount is configured.
+func (s *Service) SetPoWStepReceiptSize(powStepReceiptSize int) {
+	if powStepReceiptSize < 1 {
+		// the milestones exceed the step even without a receipt, but the size is known
+		powStepReceiptSize = 1
+	}
+	s.powStepReceiptSize.Store(int64(powStepReceiptSize))
+}
+
+// SkippedIndicesCount returns the amount of legacy milestones without migrations that were skipped since s was started.
+func (s *Service) SkippedIndicesCount() uint64 {
+	return s.skippedIndicesCount.Load()
+}
+
+// State returns a copy of the current state of s.
+func (s *Service) State() State {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+
+	return s.state
+}
+
 // PersistState persists the current state to a file.
 // PersistState must be called when the receipt returned by the last call of Receipt has been send to the network.
-func (s *Service) PersistState(sendingReceipt bool) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+// If the given context is done before the state is durable, PersistState returns the error of the context,
+// the state is still written in the background, so that a file is never left half-written.
+func (s *Service) PersistState(ctx context.Context, sendingReceipt bool) error {
+	barrier := s.PersistStateAsync(sendingReceipt)
+
+	select {
+	case <-barrier.Done():
+		return barrier.Wait()
+	case <-ctx.Done():
+		return &StateError{Stage: StagePersistState, Err: ctx.Err()}
+	}
+}
+
+// PersistStateAsync takes a snapshot of the current state and persists it to a file in the background.
+// The returned barrier is released once the state is durable, so the caller can overlap the disk latency with other work
+// and wait for the barrier before it relies on the persisted state, e.g. before a receipt is sent to the network.
+// The snapshots are written in the order they were taken.
+func (s *Service) PersistStateAsync(sendingReceipt bool) *PersistBarrier {
+	s.persistLock.Lock()
+	defer s.persistLock.Unlock()
+
+	// the state is copied, so that it can be read while the file is written
+	s.stateLock.Lock()
+	switch {
+	case !sendingReceipt:
+		s.sendingReceiptSince = time.Time{}
+	case !s.state.SendingReceipt:
+		s.sendingReceiptSince = time.Now()
+	}
 	s.state.SendingReceipt = sendingReceipt
+	state := s.state
+	pendingResult := s.pendingResult
+	if !sendingReceipt {
+		s.pendingResult = nil
+		s.committedState = state
+	}
+	s.stateLock.Unlock()
+
+	previous := s.lastPersist
+	barrier := newPersistBarrier()
+	s.lastPersist = barrier
+
+	go func() {
+		if previous != nil {
+			<-previous.Done()
+		}
+		barrier.release(s.writeState(state, sendingReceipt, pendingResult))
+	}()
+
+	return barrier
+}
+
+// awaitPersisted waits until all snapshots of the state that were taken so far are written, the caller must hold the persist lock.
+func (s *Service) awaitPersisted() {
+	if s.lastPersist != nil {
+		<-s.lastPersist.Done()
+	}
+}
+
+// writeState writes the snapshot of the state to the state file.
+func (s *Service) writeState(state State, sendingReceipt bool, pendingResult *migrationResult) error {
+	// the hashes are added before the state is persisted, so that a receipt is never marked as sent without them
+	if !sendingReceipt && pendingResult != nil && s.includedHashes != nil {
+		if err := s.includedHashes.Add(pendingResult.stopIndex, pendingResult.migratedFunds); err != nil {
+			return &StateError{Index: state.LatestMigratedAtIndex, Stage: StagePersistState, Err: err}
+		}
+	}
 
 	// create a backup of the existing migrator state file
-	if err := os.Rename(s.stateFilePath, fmt.Sprintf("%s_old", s.stateFilePath)); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("unable to create backup of migrator state file: %w", err)
+	if err := retention.BackupFile(s.stateFilePath, s.stateBackups); err != nil {
+		return &StateError{Index: state.LatestMigratedAtIndex, Stage: StagePersistState, Err: fmt.Errorf("unable to create backup of migrator state file: %w", err)}
 	}
 
-	return ioutils.WriteJSONToFile(s.stateFilePath, &s.state, 0660)
+	if err := ioutils.WriteJSONToFile(s.stateFilePath, &state, 0660); err != nil {
+		return &StateError{Index: state.LatestMigratedAtIndex, Stage: StagePersistState, Err: err}
+	}
+
+	return nil
 }
 
 // InitState initializes the state of s.
 // If msIndex is not nil, s is bootstrapped using that index as its initial state,
 // otherwise the state is loaded from file.
 // The optional utxoManager is used to validate the initialized state against the DB.
-// InitState must be called before Start.
-func (s *Service) InitState(msIndex *iotago.MilestoneIndex) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+// InitState must be called before Start, it returns the error of the given context if it is done before the state was initialized.
+func (s *Service) InitState(ctx context.Context, msIndex *iotago.MilestoneIndex) error {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return &StateError{Stage: StageInitState, Err: err}
+	}
 
 	var state State
 	if msIndex == nil {
-		// restore state from file
-		if err := ioutils.ReadJSONFromFile(s.stateFilePath, &state); err != nil {
-			return fmt.Errorf("failed to load state file: %w", err)
+		// restore state from file and upgrade it to the current version
+		if _, err := stateSchema.ReadJSONFromFile(s.stateFilePath, &state); err != nil {
+			return &StateError{Stage: StageInitState, Err: fmt.Errorf("failed to load state file: %w", err)}
 		}
 	} else {
 		// for bootstrapping the state file must not exist
@@ -148,35 +436,50 @@ func (s *Service) InitState(msIndex *iotago.MilestoneIndex) error {
 			return ErrStateFileAlreadyExists
 		}
 		state = State{
+			Version:               StateVersion,
 			LatestMigratedAtIndex: *msIndex,
 			LatestIncludedIndex:   0,
 		}
+		if s.networkResetIndex != nil {
+			state.NetworkResetIndex = *s.networkResetIndex
+		}
 	}
 
 	if state.SendingReceipt {
-		return fmt.Errorf("%w: 'sending receipt' flag is set which means the node didn't shutdown correctly", ErrInvalidState)
+		return &StateError{Index: state.LatestMigratedAtIndex, Stage: StageInitState, Err: fmt.Errorf("%w: 'sending receipt' flag is set which means the node didn't shutdown correctly", ErrInvalidState)}
 	}
 
 	// validate the state
 	if state.LatestMigratedAtIndex == 0 {
-		return fmt.Errorf("%w: latest migrated at index must not be zero", ErrInvalidState)
-	}
-
-	//TODO: read this from the latest milestone metadata (https://github.com/iotaledger/inx-coordinator/issues/2)
-	//nolint:gocritic // false positive
-	//if utxoManager != nil {
-	//	highestMigratedAtIndex, err := utxoManager.SearchHighestReceiptMigratedAtIndex()
-	//	if err != nil {
-	//		return fmt.Errorf("unable to determine highest migrated at index: %w", err)
-	//	}
-	//	// if highestMigratedAtIndex is zero no receipt in the DB, so we cannot do sanity checks
-	//	if highestMigratedAtIndex > 0 && highestMigratedAtIndex != state.LatestMigratedAtIndex {
-	//		return fmt.Errorf("state receipt does not match highest receipt in database: state: %d, database: %d",
-	//			state.LatestMigratedAtIndex, highestMigratedAtIndex)
-	//	}
-	//}
+		return &StateError{Stage: StageInitState, Err: fmt.Errorf("%w: latest migrated at index must not be zero", ErrInvalidState)}
+	}
+
+	if err := s.checkNetworkReset(state); err != nil {
+		return &StateError{Index: state.LatestMigratedAtIndex, Stage: StageInitState, Err: err}
+	}
+
+	// new receipts must not be issued on top of a state that doesn't belong to the included hashes
+	if err := s.checkIncludedHashesDigest(&state); err != nil {
+		return &StateError{Index: state.LatestMigratedAtIndex, Stage: StageInitState, Err: err}
+	}
+
+	// a restored state must not be behind the receipts that were already confirmed by the network
+	if msIndex == nil {
+		if err := s.validateReceiptHistory(ctx, state); err != nil {
+			return &StateError{Index: state.LatestMigratedAtIndex, Stage: StageInitState, Err: err}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return &StateError{Index: state.LatestMigratedAtIndex, Stage: StageInitState, Err: err}
+	}
+
+	if err := s.initFetchCheckpoint(state, msIndex != nil); err != nil {
+		return &StateError{Index: state.LatestMigratedAtIndex, Stage: StageInitState, Err: err}
+	}
 
 	s.state = state
+	s.committedState = state
 
 	return nil
 }
@@ -186,38 +489,184 @@ func (s *Service) InitState(msIndex *iotago.MilestoneIndex) error {
 // Returning false from the error handler tells the service to terminate.
 type OnServiceErrorFunc func(err error) (terminate bool)
 
-// Start stats the MigratorService s, it stops when the given context is done.
+// Start stats the MigratorService s, it stops when the given context is done or Stop is called.
+// The migrations are fetched and validated in two independent stages, so that the
+// next legacy milestone can already be fetched while the previous one is validated.
+// Start returns immediately if s is already running, a stopped service can be started again with Restart.
 func (s *Service) Start(ctx context.Context, onError OnServiceErrorFunc) {
-	var startIndex iotago.MilestoneIndex
+	ctx, migrations, ok := s.begin(ctx)
+	if !ok {
+		return
+	}
+
+	s.run(ctx, migrations, onError)
+}
+
+// run runs the stages of s that pass the migration results on the given channel until the given context is done.
+func (s *Service) run(ctx context.Context, migrations chan *migrationResult, onError OnServiceErrorFunc) {
+	defer s.end(migrations)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the migrations corresponding to the current state are fetched before the stages are started,
+	// so that the first receipt is available as soon as possible.
+	msIndex, migratedFunds, ok := s.fetchMigrations(ctx, 0, onError)
+	if !ok {
+		return
+	}
+
+	fetched := make(chan *fetchResult, fetchedBufferSize)
+	fetched <- &fetchResult{msIndex: msIndex, migratedFunds: migratedFunds}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// always continue with the next index that was not verified yet
+		s.fetchStage(ctx, s.resumeIndex(msIndex), fetched, onError)
+	}()
+
+	s.validationStage(ctx, fetched, migrations, onError)
+
+	// stop the fetch stage in case the validation stage terminated first
+	cancel()
+	wg.Wait()
+}
+
+// fetchMigrations queries the next existing migrations starting from milestone index startIndex until it succeeds.
+// It returns false if the service should terminate, e.g. because the given context is done.
+func (s *Service) fetchMigrations(ctx context.Context, startIndex iotago.MilestoneIndex, onError OnServiceErrorFunc) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, bool) {
 	for {
-		msIndex, migratedFunds, err := s.nextMigrations(startIndex)
-		if err != nil {
-			if onError != nil && !onError(err) {
-				close(s.migrations)
+		msIndex, migratedFunds, err := s.nextMigrations(ctx, startIndex)
+		if err == nil {
+			return msIndex, migratedFunds, true
+		}
 
-				return
-			}
+		// a canceled query is not an error of the legacy node
+		if ctx.Err() != nil {
+			return 0, nil, false
+		}
+
+		if onError != nil && !onError(err) {
+			return 0, nil, false
+		}
+	}
+}
 
-			continue
+// fetchStage queries the legacy network for the next migrations and passes them to the validation stage.
+// It closes the fetched channel when it terminates.
+func (s *Service) fetchStage(ctx context.Context, startIndex iotago.MilestoneIndex, fetched chan<- *fetchResult, onError OnServiceErrorFunc) {
+	defer close(fetched)
+
+	// the amount of consecutive polls that returned no new legacy milestone
+	var emptyPolls int
+	for {
+		msIndex, migratedFunds, ok := s.fetchMigrations(ctx, startIndex, onError)
+		if !ok {
+			return
 		}
 
-		s.Events.MigratedFundsFetched.Trigger(migratedFunds)
+		// the legacy network is idle if there is no new legacy milestone
+		if len(migratedFunds) == 0 && msIndex < startIndex {
+			emptyPolls++
+		} else {
+			emptyPolls = 0
+		}
 
 		// always continue with the next index
 		startIndex = msIndex + 1
 
+		select {
+		case fetched <- &fetchResult{msIndex: msIndex, migratedFunds: migratedFunds}:
+		case <-ctx.Done():
+			return
+		}
+
+		if delay := s.pollBackoff.Delay(emptyPolls); delay > 0 {
+			if !timeutil.Sleep(ctx, delay) {
+				return
+			}
+		}
+	}
+}
+
+// validationStage validates the fetched migrations and splits them into batches for the receipts that are passed on the migrations channel.
+// It terminates when the fetch stage terminated or the given context is done.
+func (s *Service) validationStage(ctx context.Context, fetched <-chan *fetchResult, migrations chan<- *migrationResult, onError OnServiceErrorFunc) {
+	var lastIndex iotago.MilestoneIndex
+	// the hashes of the migrations that were validated since the start, but are not included yet
+	validatedHashes := make(map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex)
+	// the fetch progress that is persisted during long catch-ups
+	progress := newFetchProgress(s.State(), s.fetchCheckpoint)
+	for {
+		var result *fetchResult
+		select {
+		case result = <-fetched:
+		case <-ctx.Done():
+			return
+		}
+		if result == nil {
+			// the fetch stage terminated
+			return
+		}
+
+		if err := validateMigrations(lastIndex, result.msIndex, result.migratedFunds); err != nil {
+			// skipping migrations is not possible, so the service always terminates on invalid migrations, even if the error policy only alerts
+			if onError != nil {
+				onError(common.CriticalError(err))
+			}
+
+			return
+		}
+		if err := s.checkIncluded(validatedHashes, result.msIndex, result.migratedFunds); err != nil {
+			// migrations that were already included must never be migrated again
+			if onError != nil {
+				onError(common.CriticalError(err))
+			}
+
+			return
+		}
+		if len(result.migratedFunds) > 0 {
+			fromIndex := lastIndex
+			if fromIndex == 0 {
+				fromIndex = s.State().LatestMigratedAtIndex
+			}
+
+			// a large jump may be caused by a legacy node that is connected to the wrong network
+			if !s.awaitIndexJump(ctx, fromIndex, result) {
+				return
+			}
+
+			// migrations to flagged addresses are only passed on after a manual review
+			if !s.screenMigrations(ctx, result) {
+				return
+			}
+			lastIndex = result.msIndex
+		}
+
+		if progress.update(result.msIndex, result.migratedFunds, s.fetchCheckpointInterval) && s.fetchCheckpointFilePath != "" {
+			if err := s.persistFetchCheckpoint(progress); err != nil && onError != nil && !onError(err) {
+				return
+			}
+		}
+
+		s.Events.MigratedFundsFetched.Trigger(result.migratedFunds)
+
+		migratedFunds := result.migratedFunds
 		for {
-			batch := migratedFunds
-			lastBatch := true
-			if len(batch) > s.receiptMaxEntries {
-				batch = batch[:s.receiptMaxEntries]
-				lastBatch = false
+			batch, lastBatch, err := s.nextBatch(result.msIndex, migratedFunds)
+			if err != nil {
+				if onError != nil {
+					onError(common.CriticalError(err))
+				}
+
+				return
 			}
+
 			select {
-			case s.migrations <- &migrationResult{msIndex, lastBatch, batch}:
+			case migrations <- &migrationResult{result.msIndex, lastBatch, batch}:
 			case <-ctx.Done():
-				close(s.migrations)
-
 				return
 			}
 			migratedFunds = migratedFunds[len(batch):]
@@ -228,31 +677,141 @@ func (s *Service) Start(ctx context.Context, onError OnServiceErrorFunc) {
 	}
 }
 
+// nextBatch returns the migrations of the legacy milestone with index msIndex that fit into the next receipt
+// and whether they are the last batch of the legacy milestone.
+func (s *Service) nextBatch(msIndex iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) ([]*iotago.MigratedFundsEntry, bool, error) {
+	batch := migratedFunds
+	if maxEntries := s.ReceiptLimits().MaxEntries; len(batch) > maxEntries {
+		batch = batch[:maxEntries]
+	}
+
+	batch, err := s.fitReceiptSize(msIndex, batch)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return batch, len(batch) == len(migratedFunds), nil
+}
+
+// fitReceiptSize shrinks the batch until the serialized receipt fits into the maximum receipt size.
+func (s *Service) fitReceiptSize(msIndex iotago.MilestoneIndex, batch []*iotago.MigratedFundsEntry) ([]*iotago.MigratedFundsEntry, error) {
+	maxReceiptSize := int(s.maxReceiptSize.Load())
+	if maxReceiptSize <= 0 {
+		return batch, nil
+	}
+
+	for len(batch) > 1 && ReceiptSize(msIndex, batch) > maxReceiptSize {
+		batch = batch[:len(batch)-1]
+	}
+
+	if len(batch) == 0 || ReceiptSize(msIndex, batch) <= maxReceiptSize {
+		return batch, nil
+	}
+
+	return nil, fmt.Errorf("%w: a receipt with a single entry at index %d does not fit into %d bytes", ErrReceiptTooLarge, msIndex, maxReceiptSize)
+}
+
+// ReceiptSize returns the serialized size of a receipt containing the given migrations,
+// including the treasury transaction that is added by the coordinator.
+func ReceiptSize(migratedAt iotago.MilestoneIndex, funds []*iotago.MigratedFundsEntry) int {
+	receipt := &iotago.ReceiptMilestoneOpt{
+		MigratedAt: migratedAt,
+		Funds:      funds,
+		// the treasury transaction has a fixed size
+		Transaction: &iotago.TreasuryTransaction{
+			Input:  &iotago.TreasuryInput{},
+			Output: &iotago.TreasuryOutput{},
+		},
+	}
+
+	return receipt.Size()
+}
+
+// validateMigrations performs sanity checks on the migrations confirmed by the legacy milestone with index msIndex.
+// lastIndex is the index of the previously validated legacy milestone containing migrations, or 0 if there is none.
+func validateMigrations(lastIndex iotago.MilestoneIndex, msIndex iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) error {
+	// if there are no new migrations, the latest checked legacy milestone index is returned, which can be the same as before
+	if len(migratedFunds) > 0 && lastIndex != 0 && msIndex <= lastIndex {
+		return fmt.Errorf("%w: migrations at index %d fetched after migrations at index %d", ErrInvalidMigrations, msIndex, lastIndex)
+	}
+
+	var migratedValue uint64
+	tailTransactionHashes := make(map[iotago.LegacyTailTransactionHash]struct{}, len(migratedFunds))
+	for _, entry := range migratedFunds {
+		if err := VerifyMigratedFundsEntry(entry); err != nil {
+			return fmt.Errorf("migration at index %d: %w", msIndex, err)
+		}
+
+		// the deposits are bounded by the total supply, so the sum can't overflow before it is checked
+		migratedValue += entry.Deposit
+		if migratedValue > consts.TotalSupply {
+			return fmt.Errorf("%w: migrations at index %d exceed the legacy total supply", ErrInvalidMigrations, msIndex)
+		}
+
+		if _, exists := tailTransactionHashes[entry.TailTransactionHash]; exists {
+			return fmt.Errorf("%w: duplicate tail transaction hash at index %d", ErrInvalidMigrations, msIndex)
+		}
+		tailTransactionHashes[entry.TailTransactionHash] = struct{}{}
+	}
+
+	return nil
+}
+
+// checkIncluded checks that none of the migrations was included in a previous receipt or validated before
+// and adds them to the validated hashes.
+// If the included hashes are tracked, validated hashes that were included in the meantime are removed,
+// so that the memory used by the validated hashes stays bounded during long catch-ups.
+func (s *Service) checkIncluded(validatedHashes map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex, msIndex iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) error {
+	if s.includedHashes != nil {
+		if err := s.includedHashes.Check(migratedFunds); err != nil {
+			return fmt.Errorf("migrations at index %d: %w", msIndex, err)
+		}
+
+		for hash := range validatedHashes {
+			if s.includedHashes.Contains(hash) {
+				delete(validatedHashes, hash)
+			}
+		}
+	}
+
+	for _, entry := range migratedFunds {
+		if validatedAt, exists := validatedHashes[entry.TailTransactionHash]; exists {
+			return fmt.Errorf("%w: tail transaction hash %s at index %d was already fetched at index %d", ErrTailTransactionHashIncluded, iotago.EncodeHex(entry.TailTransactionHash[:]), msIndex, validatedAt)
+		}
+	}
+	for _, entry := range migratedFunds {
+		validatedHashes[entry.TailTransactionHash] = msIndex
+	}
+
+	return nil
+}
+
 // stateMigrations queries the next existing migrations after the current state.
 // It returns an empty slice, if the state corresponded to the last migration index of that milestone.
 // It returns an error if the current state contains an included migration index that is too large.
-func (s *Service) stateMigrations() (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+// The legacy node is queried without holding a lock, this is safe because the state only changes
+// through receipts, which are not available before the initial migrations were fetched.
+func (s *Service) stateMigrations(ctx context.Context) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	state := s.State()
 
-	migratedFunds, err := s.queryer.QueryMigratedFunds(s.state.LatestMigratedAtIndex)
+	migratedFunds, err := s.queryer.QueryMigratedFunds(ctx, state.LatestMigratedAtIndex)
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, &QueryError{Index: state.LatestMigratedAtIndex, Stage: StageFetch, Err: err}
 	}
 	l := uint32(len(migratedFunds))
-	if l >= s.state.LatestIncludedIndex {
-		return s.state.LatestMigratedAtIndex, migratedFunds[s.state.LatestIncludedIndex:], nil
+	if l >= state.LatestIncludedIndex {
+		return state.LatestMigratedAtIndex, migratedFunds[state.LatestIncludedIndex:], nil
 	}
 
-	return 0, nil, common.CriticalError(fmt.Errorf("%w: state at index %d but only %d migrations", ErrInvalidState, s.state.LatestIncludedIndex, l))
+	return 0, nil, common.CriticalError(&StateError{Index: state.LatestMigratedAtIndex, Stage: StageFetch, Err: fmt.Errorf("%w: state at index %d but only %d migrations", ErrInvalidState, state.LatestIncludedIndex, l)})
 }
 
 // nextMigrations queries the next existing migrations starting from milestone index startIndex.
 // If startIndex is 0 the indices from state are used.
-func (s *Service) nextMigrations(startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+func (s *Service) nextMigrations(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
 	if startIndex == 0 {
 		// for bootstrapping query the migrations corresponding to the state
-		msIndex, migratedFunds, err := s.stateMigrations()
+		msIndex, migratedFunds, err := s.stateMigrations(ctx)
 		if err != nil {
 			return 0, nil, fmt.Errorf("failed to query migrations corresponding to initial state: %w", err)
 		}
@@ -260,16 +819,64 @@ func (s *Service) nextMigrations(startIndex iotago.MilestoneIndex) (iotago.Miles
 		if len(migratedFunds) > 0 {
 			return msIndex, migratedFunds, nil
 		}
-		// otherwise query the next available migrations
-		startIndex = msIndex + 1
+		// otherwise query the next available migrations that were not verified by the fetch checkpoint yet
+		startIndex = s.resumeIndex(msIndex)
+	}
+
+	stopIndex, migratedFunds, err := s.queryer.QueryNextMigratedFunds(ctx, startIndex)
+	if err != nil {
+		return 0, nil, &QueryError{Index: startIndex, Stage: StageFetch, Err: err}
+	}
+
+	skipped, err := skippedIndexRange(startIndex, stopIndex, migratedFunds)
+	if err != nil {
+		return 0, nil, err
+	}
+	if skipped != nil {
+		s.skippedIndicesCount.Add(uint64(skipped.Len()))
+		s.Events.IndexRangeSkipped.Trigger(skipped)
 	}
 
-	return s.queryer.QueryNextMigratedFunds(startIndex)
+	return stopIndex, migratedFunds, nil
 }
 
-func (s *Service) updateState(result *migrationResult) {
+// skippedIndexRange checks the stop index returned by the legacy node for the query starting at startIndex
+// and returns the range of legacy milestones without migrations that was skipped, or nil if there is none.
+// If there are no migrations, the stop index is the latest legacy milestone index, which is startIndex-1
+// if there is no new legacy milestone. Since every query starts right after the previous stop index,
+// a lower stop index means that the legacy node went back in time.
+func skippedIndexRange(startIndex iotago.MilestoneIndex, stopIndex iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) (*IndexRange, error) {
+	if len(migratedFunds) > 0 {
+		if stopIndex < startIndex {
+			return nil, fmt.Errorf("%w: legacy node returned migrations at index %d for query starting at index %d", ErrInvalidStopIndex, stopIndex, startIndex)
+		}
+		if stopIndex == startIndex {
+			return nil, nil
+		}
+
+		return &IndexRange{StartIndex: startIndex, EndIndex: stopIndex - 1}, nil
+	}
+
+	if stopIndex+1 < startIndex {
+		return nil, fmt.Errorf("%w: legacy node returned index %d for query starting at index %d", ErrInvalidStopIndex, stopIndex, startIndex)
+	}
+	if stopIndex < startIndex {
+		// no new legacy milestones
+		return nil, nil
+	}
+
+	return &IndexRange{StartIndex: startIndex, EndIndex: stopIndex}, nil
+}
+
+// updateState applies the result to the state, the caller must hold the state lock.
+// A result that is older than the state is rejected and the state is left untouched.
+func (s *Service) updateState(result *migrationResult) error {
 	if result.stopIndex < s.state.LatestMigratedAtIndex {
-		panic("invalid stop index")
+		return &StateError{
+			Index: s.state.LatestMigratedAtIndex,
+			Stage: StageUpdateState,
+			Err:   fmt.Errorf("%w: result of legacy milestone %d is older than the state", ErrInvalidStateUpdate, result.stopIndex),
+		}
 	}
 	// the result increases the latest milestone index
 	if result.stopIndex != s.state.LatestMigratedAtIndex {
@@ -277,6 +884,53 @@ func (s *Service) updateState(result *migrationResult) {
 		s.state.LatestIncludedIndex = 0
 	}
 	s.state.LatestIncludedIndex += uint32(len(result.migratedFunds))
+
+	// a receipt is only created for results that contain migrations
+	if len(result.migratedFunds) > 0 {
+		if s.state.FirstMigratedAtIndex == 0 {
+			s.state.FirstMigratedAtIndex = result.stopIndex
+		}
+		s.state.ReceiptsCount++
+	}
+
+	s.state.MigratedEntriesCount += uint64(len(result.migratedFunds))
+	for _, entry := range result.migratedFunds {
+		s.state.MigratedValue += entry.Deposit
+	}
+
+	// the digest is copied, so that copies of the state returned earlier are not modified
+	var digest IncludedHashesDigest
+	if s.state.IncludedHashesDigest != nil {
+		digest = *s.state.IncludedHashesDigest
+	}
+	digest.add(result.stopIndex, result.migratedFunds)
+	s.state.IncludedHashesDigest = &digest
+
+	return nil
+}
+
+// checkIncludedHashesDigest verifies the digest of the given state against the included hashes.
+// A state without a digest, e.g. a bootstrapped state or one written before the digest was introduced, takes the digest of the included hashes.
+func (s *Service) checkIncludedHashesDigest(state *State) error {
+	var digest IncludedHashesDigest
+	if s.includedHashes != nil {
+		digest = s.includedHashes.Digest()
+	} else if state.IncludedHashesDigest != nil {
+		// the included hashes are not tracked, so there is nothing to verify against
+		return nil
+	}
+
+	if state.IncludedHashesDigest == nil {
+		state.IncludedHashesDigest = &digest
+
+		return nil
+	}
+
+	if *state.IncludedHashesDigest != digest {
+		return fmt.Errorf("%w: digest %s of the state, digest %s of the %d included tail transaction hashes", ErrIncludedHashesMismatch, state.IncludedHashesDigest, digest, s.includedHashes.Len())
+	}
+
+	return nil
 }
 
 func createReceipt(migratedAt iotago.MilestoneIndex, final bool, funds []*iotago.MigratedFundsEntry) *iotago.ReceiptMilestoneOpt {
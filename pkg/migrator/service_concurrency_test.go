@@ -0,0 +1,412 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// the deposit of all migrations of the concurrency tests, so that the value of the state follows from the amount of entries.
+const concurrencyTestDeposit = iotago.MinMigratedFundsEntryDeposit
+
+// steppedQueryer answers the queries of the next migrations only when the test releases the next legacy milestone,
+// so that the order in which the legacy milestones are fetched is deterministic.
+// Queries that are blocked return the error of the context once it is done.
+type steppedQueryer struct {
+	// receives the start index of every query of the next migrations.
+	queried chan iotago.MilestoneIndex
+	// the results that are returned to the queries of the next migrations.
+	steps chan scriptedResult
+
+	lock sync.Mutex
+	// the migrations of all legacy milestones that were released.
+	released map[iotago.MilestoneIndex][]*iotago.MigratedFundsEntry
+}
+
+func newSteppedQueryer() *steppedQueryer {
+	return &steppedQueryer{
+		queried:  make(chan iotago.MilestoneIndex),
+		steps:    make(chan scriptedResult),
+		released: make(map[iotago.MilestoneIndex][]*iotago.MigratedFundsEntry),
+	}
+}
+
+func (q *steppedQueryer) QueryMigratedFunds(_ context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.released[msIndex], nil
+}
+
+func (q *steppedQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	select {
+	case q.queried <- startIndex:
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	}
+
+	select {
+	case result := <-q.steps:
+		q.lock.Lock()
+		q.released[result.stopIndex] = result.migratedFunds
+		q.lock.Unlock()
+
+		return result.stopIndex, result.migratedFunds, nil
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	}
+}
+
+// release answers the next query of the legacy node with the migrations of the legacy milestone with the given index.
+func (q *steppedQueryer) release(t *testing.T, msIndex iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) {
+	t.Helper()
+
+	select {
+	case startIndex := <-q.queried:
+		require.LessOrEqual(t, startIndex, msIndex)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the service did not query the next migrations")
+	}
+
+	q.steps <- scriptedResult{stopIndex: msIndex, migratedFunds: migratedFunds}
+}
+
+// concurrencyTestEntries returns count migrations confirmed by the legacy milestone with the given index.
+func concurrencyTestEntries(msIndex iotago.MilestoneIndex, count int) []*iotago.MigratedFundsEntry {
+	entries := make([]*iotago.MigratedFundsEntry, count)
+	for i := range entries {
+		entries[i] = &iotago.MigratedFundsEntry{
+			TailTransactionHash: iotago.LegacyTailTransactionHash{byte(msIndex), byte(i + 1)},
+			Address:             &iotago.Ed25519Address{byte(msIndex), byte(i + 1)},
+			Deposit:             concurrencyTestDeposit,
+		}
+	}
+
+	return entries
+}
+
+// requireStateInvariants checks the invariants that hold for every state that can be observed.
+func requireStateInvariants(t *testing.T, state migrator.State) {
+	t.Helper()
+
+	// the statistics are updated together with the indices
+	require.Equal(t, state.MigratedEntriesCount*concurrencyTestDeposit, state.MigratedValue)
+	require.LessOrEqual(t, state.ReceiptsCount, state.MigratedEntriesCount)
+	if state.ReceiptsCount > 0 {
+		require.NotZero(t, state.FirstMigratedAtIndex)
+		require.LessOrEqual(t, state.FirstMigratedAtIndex, state.LatestMigratedAtIndex)
+		require.NotZero(t, state.LatestIncludedIndex)
+	}
+}
+
+// requireStateMatchesReceipts checks that the state contains exactly the migrations of the given receipts.
+func requireStateMatchesReceipts(t *testing.T, state migrator.State, receipts []*iotago.ReceiptMilestoneOpt) {
+	t.Helper()
+
+	requireStateInvariants(t, state)
+
+	seen := make(map[iotago.LegacyTailTransactionHash]struct{})
+	var entriesCount uint64
+	for i, receipt := range receipts {
+		for _, entry := range receipt.Funds {
+			_, exists := seen[entry.TailTransactionHash]
+			require.False(t, exists, "migration included twice")
+			seen[entry.TailTransactionHash] = struct{}{}
+		}
+		entriesCount += uint64(len(receipt.Funds))
+
+		if i > 0 {
+			require.GreaterOrEqual(t, receipt.MigratedAt, receipts[i-1].MigratedAt)
+		}
+	}
+
+	require.EqualValues(t, len(receipts), state.ReceiptsCount)
+	require.Equal(t, entriesCount, state.MigratedEntriesCount)
+	if len(receipts) > 0 {
+		require.Equal(t, receipts[len(receipts)-1].MigratedAt, state.LatestMigratedAtIndex)
+	}
+}
+
+// stopWithin stops the service and fails if it doesn't stop within the given duration.
+func stopWithin(t *testing.T, s *migrator.Service, duration time.Duration) {
+	t.Helper()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(duration):
+		require.FailNow(t, "the service did not stop")
+	}
+	require.False(t, s.Running())
+}
+
+func TestConcurrentReceiptsAndPersistence(t *testing.T) {
+	const (
+		legacyMilestones = 5
+		entriesPerIndex  = 3
+	)
+
+	q := newSteppedQueryer()
+	dir := t.TempDir()
+	includedHashes, err := migrator.LoadIncludedHashes(filepath.Join(dir, "included_hashes.bin"))
+	require.NoError(t, err)
+
+	// the bootstrapped state has no migrations yet, so it matches the empty included hashes
+	s := newTestService(t, q, filepath.Join(dir, "migrator.state"), 1, 2)
+	s.SetIncludedHashes(includedHashes)
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// the consumer issues the receipts like the coordinator does
+	var receipts []*iotago.ReceiptMilestoneOpt
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			receipt := s.Receipt(context.Background())
+			if receipt == nil {
+				time.Sleep(time.Millisecond)
+
+				continue
+			}
+			receipts = append(receipts, receipt)
+
+			require.NoError(t, s.PersistStateAsync(true).Wait())
+			require.NoError(t, s.PersistState(context.Background(), false))
+		}
+	}()
+
+	// the state is observed while receipts are issued and persisted
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var previous migrator.State
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				state := s.State()
+				requireStateInvariants(t, state)
+
+				// the state never moves backwards
+				require.GreaterOrEqual(t, state.LatestMigratedAtIndex, previous.LatestMigratedAtIndex)
+				require.GreaterOrEqual(t, state.MigratedEntriesCount, previous.MigratedEntriesCount)
+				previous = state
+			}
+		}()
+	}
+
+	// the exported state always belongs to its included hashes
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			archive, err := s.ExportState()
+			if errors.Is(err, migrator.ErrStateNotExportable) {
+				continue
+			}
+			require.NoError(t, err)
+			require.NoError(t, archive.Validate())
+			require.Len(t, archive.IncludedHashes, int(archive.State.MigratedEntriesCount))
+		}
+	}()
+
+	for i := 0; i < legacyMilestones; i++ {
+		msIndex := iotago.MilestoneIndex(2 + i)
+		q.release(t, msIndex, concurrencyTestEntries(msIndex, entriesPerIndex))
+	}
+
+	require.Eventually(t, func() bool {
+		state := s.State()
+
+		return state.MigratedEntriesCount == legacyMilestones*entriesPerIndex && !state.SendingReceipt
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the service is blocked in the query of the next legacy milestone while it is stopped
+	stopWithin(t, s, time.Second)
+	close(done)
+	wg.Wait()
+
+	require.Nil(t, s.Receipt(context.Background()))
+	requireStateMatchesReceipts(t, s.State(), receipts)
+	require.Len(t, receipts, legacyMilestones*2)
+	require.Equal(t, legacyMilestones*entriesPerIndex, includedHashes.Len())
+	require.Equal(t, includedHashes.Digest(), *s.State().IncludedHashesDigest)
+}
+
+func TestConcurrentStopWithPendingResult(t *testing.T) {
+	q := newSteppedQueryer()
+	dir := t.TempDir()
+	includedHashes, err := migrator.LoadIncludedHashes(filepath.Join(dir, "included_hashes.bin"))
+	require.NoError(t, err)
+
+	// the bootstrapped state has no migrations yet, so it matches the empty included hashes
+	s := newTestService(t, q, filepath.Join(dir, "migrator.state"), 1, 2)
+	s.SetIncludedHashes(includedHashes)
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	q.release(t, 2, concurrencyTestEntries(2, 3))
+
+	var receipt *iotago.ReceiptMilestoneOpt
+	require.Eventually(t, func() bool {
+		receipt = s.Receipt(context.Background())
+
+		return receipt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.False(t, receipt.Final)
+	require.NoError(t, s.PersistState(context.Background(), false))
+	receipts := []*iotago.ReceiptMilestoneOpt{receipt}
+
+	// the validation stage waits until the second batch is received, it races with the shutdown
+	var wg sync.WaitGroup
+	var receiptsLock sync.Mutex
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if receipt := s.Receipt(context.Background()); receipt != nil {
+				receiptsLock.Lock()
+				receipts = append(receipts, receipt)
+				receiptsLock.Unlock()
+			}
+		}()
+	}
+	stopWithin(t, s, time.Second)
+	wg.Wait()
+
+	// a result is either received exactly once or dropped entirely
+	require.LessOrEqual(t, len(receipts), 2)
+	requireStateMatchesReceipts(t, s.State(), receipts)
+	require.Nil(t, s.Receipt(context.Background()))
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	// the dropped migrations are fetched again from the state after the restart
+	require.NoError(t, s.Restart(ctx, nil))
+	if len(receipts) == 1 {
+		require.Eventually(t, func() bool {
+			receipt = s.Receipt(context.Background())
+
+			return receipt != nil
+		}, 5*time.Second, 10*time.Millisecond)
+		require.True(t, receipt.Final)
+		receipts = append(receipts, receipt)
+		require.NoError(t, s.PersistState(context.Background(), false))
+	}
+
+	stopWithin(t, s, time.Second)
+	requireStateMatchesReceipts(t, s.State(), receipts)
+	require.EqualValues(t, 3, s.State().MigratedEntriesCount)
+	require.Equal(t, 3, includedHashes.Len())
+}
+
+func TestConcurrentStartAndStop(t *testing.T) {
+	s := migrator.NewService(&mockQueryer{}, filepath.Join(t.TempDir(), "migrator.state"), 1)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var receiptsLock sync.Mutex
+	var receipts []*iotago.ReceiptMilestoneOpt
+
+	// only a single run is active at a time, every run closes its own channel of migration results
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				s.Start(ctx, nil)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				s.Stop()
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 200; j++ {
+				if receipt := s.Receipt(context.Background()); receipt != nil {
+					receiptsLock.Lock()
+					receipts = append(receipts, receipt)
+					receiptsLock.Unlock()
+				}
+			}
+		}()
+	}
+
+	// the starts that are still running are stopped
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		s.Stop()
+
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	stopWithin(t, s, time.Second)
+
+	// no state was persisted, so a restarted run always continues with the state of the received receipts
+	requireStateMatchesReceipts(t, s.State(), receipts)
+	require.LessOrEqual(t, s.State().MigratedEntriesCount, uint64(len(serviceTests.entries)))
+	require.Nil(t, s.Receipt(context.Background()))
+}
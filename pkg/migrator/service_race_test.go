@@ -0,0 +1,124 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// blockingQueryer blocks all queries until it is released.
+type blockingQueryer struct {
+	mockQueryer
+	queried chan struct{}
+	release chan struct{}
+}
+
+func (q *blockingQueryer) QueryMigratedFunds(ctx context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	q.queried <- struct{}{}
+	<-q.release
+
+	return q.mockQueryer.QueryMigratedFunds(ctx, msIndex)
+}
+
+func TestStateNotBlockedByQueryer(t *testing.T) {
+	q := &blockingQueryer{
+		queried: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+
+	s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), 2)
+	msIndex := serviceTests.migratedAt
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	// wait until the service is blocked in the initial query of the legacy node
+	<-q.queried
+
+	stateRead := make(chan migrator.State)
+	go func() {
+		stateRead <- s.State()
+	}()
+
+	select {
+	case state := <-stateRead:
+		require.EqualValues(t, serviceTests.migratedAt, state.LatestMigratedAtIndex)
+	case <-time.After(time.Second):
+		require.FailNow(t, "reading the state was blocked by the legacy node query")
+	}
+
+	require.Nil(t, s.Receipt(context.Background()))
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	close(q.release)
+
+	require.Eventually(t, func() bool {
+		return s.Receipt(context.Background()) != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestServiceConcurrentAccess(t *testing.T) {
+	s := migrator.NewService(&mockQueryer{}, filepath.Join(t.TempDir(), "migrator.state"), 1)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	var wg sync.WaitGroup
+	var receiptsLock sync.Mutex
+	var receipts []*iotago.ReceiptMilestoneOpt
+
+	// concurrently consume the receipts, persist the state and read the state
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+
+			deadline := time.Now().Add(500 * time.Millisecond)
+			for time.Now().Before(deadline) {
+				if receipt := s.Receipt(context.Background()); receipt != nil {
+					receiptsLock.Lock()
+					receipts = append(receipts, receipt)
+					receiptsLock.Unlock()
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				require.NoError(t, s.PersistState(context.Background(), false))
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				state := s.State()
+				require.LessOrEqual(t, state.MigratedEntriesCount, uint64(len(serviceTests.entries)))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// every migration is included exactly once and the state matches the receipts
+	require.Len(t, receipts, len(serviceTests.entries))
+	state := s.State()
+	require.EqualValues(t, len(serviceTests.entries), state.MigratedEntriesCount)
+	require.EqualValues(t, serviceTests.migratedAt, state.LatestMigratedAtIndex)
+	require.EqualValues(t, len(serviceTests.entries), state.LatestIncludedIndex)
+}
@@ -0,0 +1,55 @@
+package migrator
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestValidateMigrationsRepeatedIndex(t *testing.T) {
+	entries := []*iotago.MigratedFundsEntry{
+		{
+			TailTransactionHash: iotago.LegacyTailTransactionHash{1},
+			Address:             &iotago.Ed25519Address{1},
+			Deposit:             iotago.MinMigratedFundsEntryDeposit,
+		},
+	}
+
+	// the first legacy milestone with migrations is accepted at any index
+	require.NoError(t, validateMigrations(0, 5, entries))
+
+	// the latest legacy milestone index is repeated if there are no new legacy milestones
+	require.NoError(t, validateMigrations(5, 5, nil))
+	require.NoError(t, validateMigrations(5, 4, nil))
+	require.NoError(t, validateMigrations(5, 6, entries))
+
+	// new migrations at a repeated or older index would be migrated twice
+	require.ErrorIs(t, validateMigrations(5, 5, entries), ErrInvalidMigrations)
+	require.ErrorIs(t, validateMigrations(5, 4, entries), ErrInvalidMigrations)
+}
+
+func TestReceiptIdlePollsNotPending(t *testing.T) {
+	s := NewService(nil, filepath.Join(t.TempDir(), "migrator.state"), iotago.MaxMigratedFundsEntryCount)
+
+	msIndex := iotago.MilestoneIndex(10)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	// the results are buffered, so that Receipt receives them without a running service
+	s.migrations = make(chan *migrationResult, 3)
+	for index := msIndex; index < msIndex+3; index++ {
+		s.migrations <- &migrationResult{stopIndex: index, lastBatch: true}
+	}
+
+	for i := 0; i < 3; i++ {
+		require.Nil(t, s.Receipt(context.Background()))
+	}
+	require.Empty(t, s.migrations)
+	require.EqualValues(t, 12, s.State().LatestMigratedAtIndex)
+
+	// nothing will be migrated, so there is no receipt pending
+	require.Nil(t, s.pendingResult)
+}
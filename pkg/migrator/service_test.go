@@ -2,58 +2,56 @@ package migrator_test
 
 import (
 	"context"
-	"log"
-	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/iotaledger/hive.go/core/events"
 	"github.com/iotaledger/inx-coordinator/pkg/migrator"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
-var stateFileName string
-
-func init() {
-	dir, err := os.MkdirTemp("", "migrator_test")
-	if err != nil {
-		log.Fatalf("failed to create temp dir: %s", err)
-	}
-	stateFileName = filepath.Join(dir, "migrator.state")
-}
-
 func TestReceiptFull(t *testing.T) {
-	s, teardown := newTestService(t, 1, len(serviceTests.entries))
-	defer teardown()
+	s := newTestService(t, &mockQueryer{}, filepath.Join(t.TempDir(), "migrator.state"), 1, len(serviceTests.entries))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
 
-	receipt1 := s.Receipt()
+	receipt1 := awaitReceipt(t, s)
 	require.EqualValues(t, serviceTests.migratedAt, receipt1.MigratedAt)
 	require.True(t, receipt1.Final)
 	require.ElementsMatch(t, serviceTests.entries, receipt1.Funds)
 
 	time.Sleep(100 * time.Millisecond)
 
-	receipt2 := s.Receipt()
+	receipt2 := s.Receipt(context.Background())
 	require.Nil(t, receipt2)
 }
 
 func TestReceiptAfterClose(t *testing.T) {
-	s, teardown := newTestService(t, 1, len(serviceTests.entries))
+	s := newTestService(t, &mockQueryer{}, filepath.Join(t.TempDir(), "migrator.state"), 1, len(serviceTests.entries))
 
-	receipt := s.Receipt()
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.Start(ctx, nil)
+
+	receipt := awaitReceipt(t, s)
 	require.NotNil(t, receipt)
 
-	teardown()
-	require.Nil(t, s.Receipt())
+	cancel()
+	require.Nil(t, s.Receipt(context.Background()))
 }
 
 func TestReceiptBatch(t *testing.T) {
-	s, teardown := newTestService(t, 1, 2)
-	defer teardown()
+	s := newTestService(t, &mockQueryer{}, filepath.Join(t.TempDir(), "migrator.state"), 1, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
 
-	receipt1 := s.Receipt()
+	receipt1 := awaitReceipt(t, s)
 	require.EqualValues(t, serviceTests.migratedAt, receipt1.MigratedAt)
 	require.False(t, receipt1.Final)
 	require.Len(t, receipt1.Funds, 2)
@@ -61,72 +59,306 @@ func TestReceiptBatch(t *testing.T) {
 
 	time.Sleep(100 * time.Millisecond)
 
-	receipt2 := s.Receipt()
+	receipt2 := s.Receipt(context.Background())
 	require.EqualValues(t, serviceTests.migratedAt, receipt2.MigratedAt)
 	require.True(t, receipt2.Final)
 	require.Len(t, receipt2.Funds, len(serviceTests.entries)-2)
 	require.Subset(t, serviceTests.entries, receipt2.Funds)
 
-	receipt3 := s.Receipt()
+	receipt3 := s.Receipt(context.Background())
 	require.Nil(t, receipt3)
 }
 
 func TestRestoreState(t *testing.T) {
-	s1, teardown1 := newTestService(t, 1, 2)
-	defer teardown1()
+	stateFilePath := filepath.Join(t.TempDir(), "migrator.state")
+
+	s1 := newTestService(t, &mockQueryer{}, stateFilePath, 1, 2)
 
-	receipt1 := s1.Receipt()
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	go s1.Start(ctx1, nil)
+
+	receipt1 := awaitReceipt(t, s1)
 	require.EqualValues(t, serviceTests.migratedAt, receipt1.MigratedAt)
 	require.False(t, receipt1.Final)
 	require.Len(t, receipt1.Funds, 2)
 	require.Subset(t, serviceTests.entries, receipt1.Funds)
 
-	err := s1.PersistState(false)
+	// the cumulative migration statistics are part of the state
+	require.EqualValues(t, 2, s1.State().MigratedEntriesCount)
+	require.EqualValues(t, receipt1.Funds[0].Deposit+receipt1.Funds[1].Deposit, s1.State().MigratedValue)
+	require.EqualValues(t, serviceTests.migratedAt, s1.State().FirstMigratedAtIndex)
+	require.EqualValues(t, 1, s1.State().ReceiptsCount)
+
+	err := s1.PersistState(context.Background(), false)
 	require.NoError(t, err)
 
 	// initialize state from file
-	s2, teardown2 := newTestService(t, 0, 2)
-	defer teardown2()
+	s2 := newTestService(t, &mockQueryer{}, stateFilePath, 0, 2)
 
-	receipt2 := s2.Receipt()
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	go s2.Start(ctx2, nil)
+
+	receipt2 := awaitReceipt(t, s2)
 	require.EqualValues(t, 2, receipt2.MigratedAt)
 	require.True(t, receipt2.Final)
 	require.Len(t, receipt2.Funds, len(serviceTests.entries)-2)
 	require.Subset(t, serviceTests.entries, receipt2.Funds)
+
+	// the cumulative migration statistics are restored and continued
+	var totalValue uint64
+	for _, entry := range serviceTests.entries {
+		totalValue += entry.Deposit
+	}
+	require.EqualValues(t, len(serviceTests.entries), s2.State().MigratedEntriesCount)
+	require.EqualValues(t, totalValue, s2.State().MigratedValue)
+	require.EqualValues(t, serviceTests.migratedAt, s2.State().FirstMigratedAtIndex)
+	require.EqualValues(t, 2, s2.State().ReceiptsCount)
+}
+
+func TestServiceRestart(t *testing.T) {
+	s := newTestService(t, &mockQueryer{}, filepath.Join(t.TempDir(), "migrator.state"), 1, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	receipt1 := awaitReceipt(t, s)
+	require.NotNil(t, receipt1)
+	require.False(t, receipt1.Final)
+
+	// the receipt was not sent yet, so its migrations would be included again
+	require.ErrorIs(t, s.Restart(context.Background(), nil), migrator.ErrReceiptPending)
+	require.False(t, s.Running())
+	require.Nil(t, s.Receipt(context.Background()))
+
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	// the remaining migrations are fetched again from the persisted state
+	require.NoError(t, s.Restart(ctx, nil))
+	require.True(t, s.Running())
+
+	receipt2 := awaitReceipt(t, s)
+	require.EqualValues(t, serviceTests.migratedAt, receipt2.MigratedAt)
+	require.True(t, receipt2.Final)
+	require.Len(t, receipt2.Funds, len(serviceTests.entries)-2)
+	require.Subset(t, serviceTests.entries, receipt2.Funds)
+	require.NotContains(t, receipt2.Funds, receipt1.Funds[0])
+	require.NotContains(t, receipt2.Funds, receipt1.Funds[1])
+
+	s.Stop()
+	require.False(t, s.Running())
+	require.Nil(t, s.Receipt(context.Background()))
+}
+
+func TestServiceRestartIdle(t *testing.T) {
+	q := &scriptedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 1},
+			{stopIndex: 2},
+			{stopIndex: 3},
+		},
+	}
+
+	s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), len(serviceTests.entries))
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	// the legacy network is idle, so Receipt never returns a receipt
+	require.Eventually(t, func() bool {
+		require.Nil(t, s.Receipt(context.Background()))
+
+		return s.State().LatestMigratedAtIndex == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, s.Receipt(context.Background()))
+
+	// no receipt is pending, so the service can be restarted
+	require.NoError(t, s.Restart(ctx, nil))
+	require.True(t, s.Running())
+
+	s.Stop()
+	require.False(t, s.Running())
 }
 
-func newTestService(t *testing.T, msIndex iotago.MilestoneIndex, maxEntries int) (*migrator.Service, func()) {
-	s := migrator.NewService(&mockQueryer{}, stateFileName, maxEntries)
+func TestReceiptMaxSize(t *testing.T) {
+	// only two entries fit into a receipt
+	s := newTestService(t, &mockQueryer{}, filepath.Join(t.TempDir(), "migrator.state"), 1, len(serviceTests.entries))
+	s.SetMaxReceiptSize(migrator.ReceiptSize(serviceTests.migratedAt, serviceTests.entries[:2]))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	receipt1 := awaitReceipt(t, s)
+	require.EqualValues(t, serviceTests.migratedAt, receipt1.MigratedAt)
+	require.False(t, receipt1.Final)
+	require.Len(t, receipt1.Funds, 2)
+
+	time.Sleep(100 * time.Millisecond)
+
+	receipt2 := s.Receipt(context.Background())
+	require.True(t, receipt2.Final)
+	require.Len(t, receipt2.Funds, len(serviceTests.entries)-2)
+}
+
+func TestSkippedIndexRanges(t *testing.T) {
+	q := &scriptedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 5, migratedFunds: serviceTests.entries},
+			{stopIndex: 8},
+			// no new legacy milestones
+			{stopIndex: 8},
+			// the legacy node went back in time
+			{stopIndex: 3},
+		},
+	}
+
+	s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), len(serviceTests.entries))
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	var skipped []*migrator.IndexRange
+	s.Events.IndexRangeSkipped.Hook(events.NewClosure(func(indexRange *migrator.IndexRange) {
+		skipped = append(skipped, indexRange)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serviceErr := make(chan error, 1)
+	go s.Start(ctx, func(err error) bool {
+		serviceErr <- err
+
+		return false
+	})
+
+	var err error
+	require.Eventually(t, func() bool {
+		// receive the receipts, so that the service continues to query the legacy node
+		s.Receipt(context.Background())
+
+		select {
+		case err = <-serviceErr:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.ErrorIs(t, err, migrator.ErrInvalidStopIndex)
+	require.Equal(t, migrator.ErrorClassValidation, migrator.ErrorClass(err))
+
+	require.Equal(t, []iotago.MilestoneIndex{2, 6, 9, 9}, q.startIndices)
+	require.Equal(t, []*migrator.IndexRange{
+		{StartIndex: 2, EndIndex: 4},
+		{StartIndex: 6, EndIndex: 8},
+	}, skipped)
+	require.EqualValues(t, 6, s.SkippedIndicesCount())
+}
+
+func TestRepeatedIndexWithoutMigrations(t *testing.T) {
+	q := &scriptedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 2, migratedFunds: serviceTests.entries[:2]},
+			// no new legacy milestones, the latest legacy milestone index is returned again
+			{stopIndex: 2},
+			{stopIndex: 2},
+			{stopIndex: 2},
+			{stopIndex: 5, migratedFunds: serviceTests.entries[2:]},
+		},
+	}
+
+	s := migrator.NewService(q, filepath.Join(t.TempDir(), "migrator.state"), len(serviceTests.entries))
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	var fetched [][]*iotago.MigratedFundsEntry
+	s.Events.MigratedFundsFetched.Hook(events.NewClosure(func(migratedFunds []*iotago.MigratedFundsEntry) {
+		fetched = append(fetched, migratedFunds)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serviceErr := make(chan error, 1)
+	go s.Start(ctx, func(err error) bool {
+		serviceErr <- err
+
+		return false
+	})
+
+	var receipts []*iotago.ReceiptMilestoneOpt
+	require.Eventually(t, func() bool {
+		if receipt := s.Receipt(context.Background()); receipt != nil {
+			receipts = append(receipts, receipt)
+			require.NoError(t, s.PersistState(context.Background(), false))
+		}
+
+		return len(receipts) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the repeated index is neither an error nor does it produce a receipt
+	s.Stop()
+	select {
+	case err := <-serviceErr:
+		require.FailNow(t, "the repeated index was rejected", "error: %v", err)
+	default:
+	}
+
+	require.EqualValues(t, 2, receipts[0].MigratedAt)
+	require.ElementsMatch(t, serviceTests.entries[:2], receipts[0].Funds)
+	require.EqualValues(t, 5, receipts[1].MigratedAt)
+	require.ElementsMatch(t, serviceTests.entries[2:], receipts[1].Funds)
+
+	require.Equal(t, []iotago.MilestoneIndex{2, 3, 3, 3, 3, 6}, q.startIndices)
+	require.Len(t, fetched, 5)
+	for _, migratedFunds := range fetched[1:4] {
+		require.Empty(t, migratedFunds)
+	}
+	require.EqualValues(t, 5, s.State().LatestMigratedAtIndex)
+}
+
+// newTestService creates a service that queries the given queryer and whose state is bootstrapped at the given legacy milestone index,
+// or loaded from the state file if the index is 0.
+func newTestService(t *testing.T, q migrator.Queryer, stateFilePath string, msIndex iotago.MilestoneIndex, maxEntries int) *migrator.Service {
+	t.Helper()
+
+	s := migrator.NewService(q, stateFilePath, maxEntries)
 
 	if msIndex > 0 {
 		// bootstrap
-		err := s.InitState(&msIndex)
-		require.NoError(t, err)
+		require.NoError(t, s.InitState(context.Background(), &msIndex))
 	} else {
 		// load from state
-		err := s.InitState(nil)
-		require.NoError(t, err)
+		require.NoError(t, s.InitState(context.Background(), nil))
 	}
 
-	ctx, ctxCancel := context.WithCancel(context.Background())
-	started := make(chan struct{})
-	go func() {
-		close(started)
-		s.Start(ctx, nil)
-	}()
+	return s
+}
+
+// awaitReceipt returns the next receipt of the started service once its migrations were fetched.
+func awaitReceipt(t *testing.T, s *migrator.Service) *iotago.ReceiptMilestoneOpt {
+	t.Helper()
 
-	<-started
+	var receipt *iotago.ReceiptMilestoneOpt
+	require.Eventually(t, func() bool {
+		receipt = s.Receipt(context.Background())
 
-	return s, func() {
-		ctxCancel()
-		// we don't need to check the error, maybe the file doesn't exist
-		_ = os.Remove(stateFileName)
-	}
+		return receipt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return receipt
 }
 
 type mockQueryer struct{}
 
-func (mockQueryer) QueryMigratedFunds(msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+func (mockQueryer) QueryMigratedFunds(_ context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
 	if msIndex == serviceTests.migratedAt {
 		return serviceTests.entries, nil
 	}
@@ -134,7 +366,7 @@ func (mockQueryer) QueryMigratedFunds(msIndex iotago.MilestoneIndex) ([]*iotago.
 	return nil, nil
 }
 
-func (mockQueryer) QueryNextMigratedFunds(startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+func (mockQueryer) QueryNextMigratedFunds(_ context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
 	if startIndex <= serviceTests.migratedAt {
 		return serviceTests.migratedAt, serviceTests.entries, nil
 	}
@@ -142,6 +374,34 @@ func (mockQueryer) QueryNextMigratedFunds(startIndex iotago.MilestoneIndex) (iot
 	return serviceTests.migratedAt, nil, nil
 }
 
+type scriptedResult struct {
+	stopIndex     iotago.MilestoneIndex
+	migratedFunds []*iotago.MigratedFundsEntry
+}
+
+// scriptedQueryer returns the given results for the queries of the next migrations in order.
+// Once all results were returned, the queries block until their context is done.
+type scriptedQueryer struct {
+	mockQueryer
+	results      []scriptedResult
+	startIndices []iotago.MilestoneIndex
+}
+
+func (q *scriptedQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	q.startIndices = append(q.startIndices, startIndex)
+
+	if len(q.results) == 0 {
+		<-ctx.Done()
+
+		return 0, nil, ctx.Err()
+	}
+
+	result := q.results[0]
+	q.results = q.results[1:]
+
+	return result.stopIndex, result.migratedFunds, nil
+}
+
 var serviceTests = struct {
 	migratedAt iotago.MilestoneIndex
 	entries    []*iotago.MigratedFundsEntry
@@ -0,0 +1,257 @@
+package migrator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// StateArchiveVersion is the version of the migrator state archive format.
+	StateArchiveVersion = 1
+)
+
+var (
+	// ErrStateNotExportable is returned when the state is exported while a receipt is pending,
+	// because the included tail transaction hashes of the receipt are not part of the persisted state yet.
+	ErrStateNotExportable = errors.New("migrator state can not be exported while a receipt is pending")
+	// ErrInvalidStateArchive is returned when a migrator state archive is invalid.
+	ErrInvalidStateArchive = errors.New("invalid migrator state archive")
+)
+
+// StateArchive is a portable copy of the persistent migrator state, used to move the migration to another host.
+// It contains the state, the tail transaction hashes of all migrations that were included in receipts
+// and the fetch checkpoint, so that the new host neither migrates funds twice nor fetches verified legacy milestones again.
+type StateArchive struct {
+	// the version of the archive format.
+	Version uint32 `json:"version"`
+	// the state of the migrator.
+	State State `json:"state"`
+	// the included tail transaction hashes and the legacy milestone index they were migrated at.
+	IncludedHashes []*IncludedHash `json:"includedHashes"`
+	// the fetch checkpoint that belongs to the state (optional).
+	FetchCheckpoint *FetchCheckpoint `json:"fetchCheckpoint,omitempty"`
+}
+
+// IncludedHash is a tail transaction hash that was included in a receipt.
+type IncludedHash struct {
+	// the hex encoded tail transaction hash.
+	TailTransactionHash string `json:"tailTransactionHash"`
+	// the legacy milestone index the tail transaction hash was migrated at.
+	MigratedAt iotago.MilestoneIndex `json:"migratedAt"`
+}
+
+// StateFilePaths are the paths of the files the persistent migrator state is stored in.
+type StateFilePaths struct {
+	// the path to the migrator state file.
+	State string
+	// the path to the file of the included tail transaction hashes.
+	IncludedHashes string
+	// the path to the fetch checkpoint file (empty = the checkpoint is not imported).
+	FetchCheckpoint string
+}
+
+// entries returns all included tail transaction hashes ordered by the index they were migrated at and the hash.
+func (h *IncludedHashes) entries() []*IncludedHash {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	hashes := make([]iotago.LegacyTailTransactionHash, 0, len(h.hashes))
+	for hash := range h.hashes {
+		hashes = append(hashes, hash)
+	}
+
+	sort.Slice(hashes, func(i, j int) bool {
+		if h.hashes[hashes[i]] != h.hashes[hashes[j]] {
+			return h.hashes[hashes[i]] < h.hashes[hashes[j]]
+		}
+
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+
+	entries := make([]*IncludedHash, len(hashes))
+	for i, hash := range hashes {
+		entries[i] = &IncludedHash{
+			TailTransactionHash: iotago.EncodeHex(hash[:]),
+			MigratedAt:          h.hashes[hash],
+		}
+	}
+
+	return entries
+}
+
+// ExportState returns an archive of the persisted state of s.
+// The state advanced by idle polls of the legacy node is not persisted yet, so the latest state that was persisted
+// without the 'sending receipt' flag is exported. The state can't be exported while a receipt is pending,
+// so that the archive never contains a state whose included tail transaction hashes are incomplete.
+func (s *Service) ExportState() (*StateArchive, error) {
+	// no state is persisted while the archive is created, and the included hashes of the written snapshots are complete
+	s.persistLock.Lock()
+	defer s.persistLock.Unlock()
+	s.awaitPersisted()
+
+	s.stateLock.RLock()
+	state := s.committedState
+	pending := s.receiptPending()
+	s.stateLock.RUnlock()
+
+	if state.LatestMigratedAtIndex == 0 {
+		return nil, fmt.Errorf("%w: state is not initialized", ErrStateNotExportable)
+	}
+
+	if pending {
+		return nil, fmt.Errorf("%w: receipt of legacy milestone %d", ErrStateNotExportable, s.State().LatestMigratedAtIndex)
+	}
+
+	archive := &StateArchive{
+		Version:        StateArchiveVersion,
+		State:          state,
+		IncludedHashes: make([]*IncludedHash, 0),
+	}
+
+	if s.includedHashes != nil {
+		archive.IncludedHashes = s.includedHashes.entries()
+	}
+
+	if s.fetchCheckpointFilePath != "" {
+		checkpoint, err := loadFetchCheckpoint(s.fetchCheckpointFilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		// an outdated checkpoint is ignored after the import anyway
+		if checkpoint != nil && checkpoint.MigratedAtIndex == state.LatestMigratedAtIndex && checkpoint.VerifiedIndex > checkpoint.MigratedAtIndex {
+			archive.FetchCheckpoint = checkpoint
+		}
+	}
+
+	return archive, nil
+}
+
+// Validate checks that the archive is consistent and can be imported by this version.
+func (a *StateArchive) Validate() error {
+	if a.Version != StateArchiveVersion {
+		return fmt.Errorf("%w: unsupported archive version %d, supported version %d", ErrInvalidStateArchive, a.Version, StateArchiveVersion)
+	}
+
+	// older state versions are upgraded when the state is loaded
+	if a.State.Version > StateVersion {
+		return fmt.Errorf("%w: unsupported state version %d, supported version %d", ErrInvalidStateArchive, a.State.Version, StateVersion)
+	}
+
+	if a.State.LatestMigratedAtIndex == 0 {
+		return fmt.Errorf("%w: latest migrated at index must not be zero", ErrInvalidStateArchive)
+	}
+
+	if a.State.SendingReceipt {
+		return fmt.Errorf("%w: 'sending receipt' flag is set", ErrInvalidStateArchive)
+	}
+
+	data, err := a.includedHashesData()
+	if err != nil {
+		return err
+	}
+
+	// archives of states without a digest take the digest of the included hashes when they are loaded
+	if a.State.IncludedHashesDigest != nil {
+		var digest IncludedHashesDigest
+		for offset := 0; offset < len(data); offset += includedHashRecordSize {
+			var hash iotago.LegacyTailTransactionHash
+			copy(hash[:], data[offset:offset+iotago.LegacyTailTransactionHashLength])
+			digest.addRecord(hash, binary.LittleEndian.Uint32(data[offset+iotago.LegacyTailTransactionHashLength:offset+includedHashRecordSize]))
+		}
+
+		if *a.State.IncludedHashesDigest != digest {
+			return fmt.Errorf("%w: digest %s of the state does not match digest %s of the included tail transaction hashes", ErrInvalidStateArchive, a.State.IncludedHashesDigest, digest)
+		}
+	}
+
+	if checkpoint := a.FetchCheckpoint; checkpoint != nil {
+		if checkpoint.MigratedAtIndex != a.State.LatestMigratedAtIndex || checkpoint.VerifiedIndex <= checkpoint.MigratedAtIndex {
+			return fmt.Errorf("%w: fetch checkpoint (%d, %d] does not belong to latest migrated at index %d", ErrInvalidStateArchive, checkpoint.MigratedAtIndex, checkpoint.VerifiedIndex, a.State.LatestMigratedAtIndex)
+		}
+	}
+
+	return nil
+}
+
+// includedHashesData returns the included tail transaction hashes of the archive in the format of the included hashes file.
+func (a *StateArchive) includedHashesData() ([]byte, error) {
+	seen := make(map[iotago.LegacyTailTransactionHash]struct{}, len(a.IncludedHashes))
+	data := make([]byte, 0, len(a.IncludedHashes)*includedHashRecordSize)
+
+	for _, entry := range a.IncludedHashes {
+		hashBytes, err := iotago.DecodeHex(entry.TailTransactionHash)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid tail transaction hash %s: %v", ErrInvalidStateArchive, entry.TailTransactionHash, err)
+		}
+
+		if len(hashBytes) != iotago.LegacyTailTransactionHashLength {
+			return nil, fmt.Errorf("%w: invalid length of tail transaction hash %s", ErrInvalidStateArchive, entry.TailTransactionHash)
+		}
+
+		if entry.MigratedAt == 0 || entry.MigratedAt > a.State.LatestMigratedAtIndex {
+			return nil, fmt.Errorf("%w: tail transaction hash %s was migrated at legacy milestone %d after latest migrated at index %d", ErrInvalidStateArchive, entry.TailTransactionHash, entry.MigratedAt, a.State.LatestMigratedAtIndex)
+		}
+
+		var hash iotago.LegacyTailTransactionHash
+		copy(hash[:], hashBytes)
+		if _, exists := seen[hash]; exists {
+			return nil, fmt.Errorf("%w: duplicate tail transaction hash %s", ErrInvalidStateArchive, entry.TailTransactionHash)
+		}
+		seen[hash] = struct{}{}
+
+		data = append(data, hash[:]...)
+		data = binary.LittleEndian.AppendUint32(data, entry.MigratedAt)
+	}
+
+	return data, nil
+}
+
+// ImportStateArchive writes the state of the archive to the given files.
+// Existing state files are never overwritten, so the state can only be imported on a host without a migrator state.
+// The state file is written last, so an interrupted import can be repeated after the other files were removed.
+func ImportStateArchive(archive *StateArchive, paths StateFilePaths) error {
+	if err := archive.Validate(); err != nil {
+		return err
+	}
+
+	for _, filePath := range []string{paths.State, paths.IncludedHashes} {
+		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrStateFileAlreadyExists, filePath)
+		}
+	}
+
+	data, err := archive.includedHashesData()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(paths.IncludedHashes, data, 0660); err != nil {
+		return fmt.Errorf("unable to write included tail transaction hashes: %w", err)
+	}
+
+	if paths.FetchCheckpoint != "" {
+		if archive.FetchCheckpoint == nil {
+			// a checkpoint left on this host must not skip legacy milestones that were never verified
+			if err := os.Remove(paths.FetchCheckpoint); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("unable to remove fetch checkpoint: %w", err)
+			}
+		} else if err := ioutils.WriteJSONToFile(paths.FetchCheckpoint, archive.FetchCheckpoint, 0600); err != nil {
+			return fmt.Errorf("unable to write fetch checkpoint: %w", err)
+		}
+	}
+
+	if err := ioutils.WriteJSONToFile(paths.State, &archive.State, 0660); err != nil {
+		return fmt.Errorf("unable to write migrator state file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package migrator_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestStateArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	includedHashes, err := migrator.LoadIncludedHashes(filepath.Join(dir, "included_hashes.bin"))
+	require.NoError(t, err)
+
+	q := &scriptedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 2, migratedFunds: serviceTests.entries},
+			{stopIndex: 20},
+			// the legacy node went back in time, which stops the service
+			{stopIndex: 3},
+		},
+	}
+
+	checkpointFilePath := filepath.Join(dir, "migrator_fetch_checkpoint.json")
+	s := migrator.NewService(q, filepath.Join(dir, "migrator.state"), len(serviceTests.entries))
+	s.SetIncludedHashes(includedHashes)
+	s.SetFetchCheckpoint(checkpointFilePath, 10)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, func(_ error) bool { return false })
+
+	var receipt *iotago.ReceiptMilestoneOpt
+	require.Eventually(t, func() bool {
+		receipt = s.Receipt(context.Background())
+
+		return receipt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the hashes of a pending receipt are not included yet
+	_, err = s.ExportState()
+	require.ErrorIs(t, err, migrator.ErrStateNotExportable)
+	require.NoError(t, s.PersistState(context.Background(), true))
+	_, err = s.ExportState()
+	require.ErrorIs(t, err, migrator.ErrStateNotExportable)
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	require.Eventually(t, func() bool {
+		checkpoint := &migrator.FetchCheckpoint{}
+
+		return ioutils.ReadJSONFromFile(checkpointFilePath, checkpoint) == nil && checkpoint.VerifiedIndex == 20
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	archive, err := s.ExportState()
+	require.NoError(t, err)
+	require.Equal(t, s.State(), archive.State)
+	require.Len(t, archive.IncludedHashes, len(receipt.Funds))
+	require.Equal(t, &migrator.FetchCheckpoint{MigratedAtIndex: serviceTests.migratedAt, VerifiedIndex: 20}, archive.FetchCheckpoint)
+
+	// the archive is transferred to another host
+	data, err := json.Marshal(archive)
+	require.NoError(t, err)
+	imported := &migrator.StateArchive{}
+	require.NoError(t, json.Unmarshal(data, imported))
+
+	newDir := t.TempDir()
+	paths := migrator.StateFilePaths{
+		State:           filepath.Join(newDir, "migrator.state"),
+		IncludedHashes:  filepath.Join(newDir, "included_hashes.bin"),
+		FetchCheckpoint: filepath.Join(newDir, "migrator_fetch_checkpoint.json"),
+	}
+	require.NoError(t, migrator.ImportStateArchive(imported, paths))
+
+	// an existing state is never overwritten
+	require.ErrorIs(t, migrator.ImportStateArchive(imported, paths), migrator.ErrStateFileAlreadyExists)
+
+	importedHashes, err := migrator.LoadIncludedHashes(paths.IncludedHashes)
+	require.NoError(t, err)
+	require.Equal(t, len(receipt.Funds), importedHashes.Len())
+	require.ErrorIs(t, importedHashes.Check(receipt.Funds), migrator.ErrTailTransactionHashIncluded)
+
+	restored := migrator.NewService(&mockQueryer{}, paths.State, len(serviceTests.entries))
+	restored.SetFetchCheckpoint(paths.FetchCheckpoint, 10)
+	require.NoError(t, restored.InitState(context.Background(), nil))
+	require.Equal(t, s.State(), restored.State())
+	require.Equal(t, archive.FetchCheckpoint, restored.FetchCheckpoint())
+}
+
+func TestStateArchiveAfterIdlePolls(t *testing.T) {
+	dir := t.TempDir()
+
+	includedHashes, err := migrator.LoadIncludedHashes(filepath.Join(dir, "included_hashes.bin"))
+	require.NoError(t, err)
+
+	q := &scriptedQueryer{
+		results: []scriptedResult{
+			{stopIndex: 2, migratedFunds: serviceTests.entries},
+			{stopIndex: 5},
+			{stopIndex: 6},
+		},
+	}
+
+	stateFilePath := filepath.Join(dir, "migrator.state")
+	s := migrator.NewService(q, stateFilePath, len(serviceTests.entries))
+	s.SetIncludedHashes(includedHashes)
+	msIndex := iotago.MilestoneIndex(1)
+	require.NoError(t, s.InitState(context.Background(), &msIndex))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, nil)
+
+	require.Eventually(t, func() bool {
+		return s.Receipt(context.Background()) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.PersistState(context.Background(), true))
+	require.NoError(t, s.PersistState(context.Background(), false))
+
+	// the coordinator asks for a receipt with every milestone while the legacy network is idle
+	require.Eventually(t, func() bool {
+		require.Nil(t, s.Receipt(context.Background()))
+
+		return s.State().LatestMigratedAtIndex == 6
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the persisted state is exported, not the state advanced by the idle polls
+	archive, err := s.ExportState()
+	require.NoError(t, err)
+	persisted := migrator.State{}
+	require.NoError(t, ioutils.ReadJSONFromFile(stateFilePath, &persisted))
+	require.Equal(t, persisted, archive.State)
+	require.EqualValues(t, serviceTests.migratedAt, archive.State.LatestMigratedAtIndex)
+	require.Len(t, archive.IncludedHashes, len(serviceTests.entries))
+	require.NoError(t, archive.Validate())
+}
+
+func TestStateArchiveValidate(t *testing.T) {
+	hash := iotago.EncodeHex(serviceTests.entries[0].TailTransactionHash[:])
+
+	validArchive := func() *migrator.StateArchive {
+		return &migrator.StateArchive{
+			Version: migrator.StateArchiveVersion,
+			State: migrator.State{
+				Version:               migrator.StateVersion,
+				LatestMigratedAtIndex: 10,
+			},
+			IncludedHashes:  []*migrator.IncludedHash{{TailTransactionHash: hash, MigratedAt: 5}},
+			FetchCheckpoint: &migrator.FetchCheckpoint{MigratedAtIndex: 10, VerifiedIndex: 15},
+		}
+	}
+	require.NoError(t, validArchive().Validate())
+
+	tests := map[string]func(archive *migrator.StateArchive){
+		"unsupported version": func(archive *migrator.StateArchive) { archive.Version++ },
+		"newer state":         func(archive *migrator.StateArchive) { archive.State.Version = migrator.StateVersion + 1 },
+		"zero index":          func(archive *migrator.StateArchive) { archive.State.LatestMigratedAtIndex = 0 },
+		"sending receipt":     func(archive *migrator.StateArchive) { archive.State.SendingReceipt = true },
+		"invalid hash":        func(archive *migrator.StateArchive) { archive.IncludedHashes[0].TailTransactionHash = "0x1234" },
+		"future hash":         func(archive *migrator.StateArchive) { archive.IncludedHashes[0].MigratedAt = 11 },
+		"outdated checkpoint": func(archive *migrator.StateArchive) { archive.FetchCheckpoint.MigratedAtIndex = 9 },
+		"empty checkpoint":    func(archive *migrator.StateArchive) { archive.FetchCheckpoint.VerifiedIndex = 10 },
+		"duplicate hash": func(archive *migrator.StateArchive) {
+			archive.IncludedHashes = append(archive.IncludedHashes, archive.IncludedHashes[0])
+		},
+		"not hex encoded hash": func(archive *migrator.StateArchive) { archive.IncludedHashes[0].TailTransactionHash = "hash" },
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			archive := validArchive()
+			modify(archive)
+			require.ErrorIs(t, archive.Validate(), migrator.ErrInvalidStateArchive)
+		})
+	}
+}
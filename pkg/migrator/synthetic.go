@@ -0,0 +1,204 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/encoding/t5b1"
+	"github.com/iotaledger/iota.go/trinary"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// DistributionUniform distributes the deposits of synthetic migrations uniformly between min and max deposit.
+	DistributionUniform = "uniform"
+	// DistributionExponential distributes the deposits of synthetic migrations exponentially starting at min deposit,
+	// so that most deposits are small and only a few are close to max deposit.
+	DistributionExponential = "exponential"
+)
+
+var (
+	// ErrInvalidSyntheticConfig is returned when the configuration of the SyntheticQueryer is invalid.
+	ErrInvalidSyntheticConfig = errors.New("invalid synthetic migrations configuration")
+	// ErrNotPrivateNetwork is returned when synthetic migrations would be issued on a network that is not a private tangle.
+	ErrNotPrivateNetwork = errors.New("synthetic migrations are only allowed on private tangles")
+)
+
+// publicNetworkNames are the names of the public networks, they are never accepted as private tangles,
+// even if they were configured as such by mistake.
+var publicNetworkNames = map[string]struct{}{
+	"chrysalis-mainnet": {},
+	"iota-mainnet":      {},
+	"iota-testnet":      {},
+	"shimmer":           {},
+	"shimmer-testnet":   {},
+	"testnet":           {},
+}
+
+// CheckPrivateNetwork returns an error unless the network with the given name is one of the given private tangles.
+// Synthetic and faucet migrations mint funds out of thin air, so they must never be issued on a public network.
+func CheckPrivateNetwork(networkName string, privateNetworkNames []string) error {
+	if networkName == "" {
+		return fmt.Errorf("%w: unknown network name", ErrNotPrivateNetwork)
+	}
+
+	if _, public := publicNetworkNames[networkName]; public {
+		return fmt.Errorf("%w: '%s' is a public network", ErrNotPrivateNetwork, networkName)
+	}
+
+	for _, privateNetworkName := range privateNetworkNames {
+		if networkName == privateNetworkName {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: '%s' is not one of the configured private tangles %v", ErrNotPrivateNetwork, networkName, privateNetworkNames)
+}
+
+// SyntheticQueryer is a Queryer generating fake migrations for load testing the receipt pipeline on private tangles.
+// A new legacy milestone is simulated every milestoneInterval, each one confirming entriesPerMilestone migrations.
+// The migrations of a legacy milestone are derived from its index, so querying the same index always returns the same data.
+type SyntheticQueryer struct {
+	mutex syncutils.Mutex
+
+	// the index of the first legacy milestone that was queried, the simulation starts with that milestone.
+	startIndex iotago.MilestoneIndex
+	// the time the first legacy milestone was queried.
+	startTime time.Time
+
+	milestoneInterval   time.Duration
+	entriesPerMilestone int
+	minDeposit          uint64
+	maxDeposit          uint64
+	distribution        string
+}
+
+// NewSyntheticQueryer creates a new SyntheticQueryer for the network with the given name.
+// It refuses to start unless the network is one of the given private tangles.
+func NewSyntheticQueryer(networkName string, privateNetworkNames []string, milestoneInterval time.Duration, entriesPerMilestone int, minDeposit uint64, maxDeposit uint64, distribution string) (*SyntheticQueryer, error) {
+
+	if err := CheckPrivateNetwork(networkName, privateNetworkNames); err != nil {
+		return nil, err
+	}
+
+	if milestoneInterval <= 0 {
+		return nil, fmt.Errorf("%w: milestone interval must be greater than 0", ErrInvalidSyntheticConfig)
+	}
+
+	if entriesPerMilestone < 0 {
+		return nil, fmt.Errorf("%w: entries per milestone must not be negative", ErrInvalidSyntheticConfig)
+	}
+
+	if minDeposit < iotago.MinMigratedFundsEntryDeposit {
+		return nil, fmt.Errorf("%w: min deposit must be at least %d", ErrInvalidSyntheticConfig, iotago.MinMigratedFundsEntryDeposit)
+	}
+
+	if maxDeposit < minDeposit {
+		return nil, fmt.Errorf("%w: max deposit must not be smaller than min deposit", ErrInvalidSyntheticConfig)
+	}
+
+	switch distribution {
+	case DistributionUniform, DistributionExponential:
+	default:
+		return nil, fmt.Errorf("%w: unknown deposit distribution: %s", ErrInvalidSyntheticConfig, distribution)
+	}
+
+	return &SyntheticQueryer{
+		milestoneInterval:   milestoneInterval,
+		entriesPerMilestone: entriesPerMilestone,
+		minDeposit:          minDeposit,
+		maxDeposit:          maxDeposit,
+		distribution:        distribution,
+	}, nil
+}
+
+// latestIndex returns the index of the latest simulated legacy milestone.
+// The simulation starts with the first queried index, so that it continues where a restarted migrator left off.
+func (q *SyntheticQueryer) latestIndex(startIndex iotago.MilestoneIndex) iotago.MilestoneIndex {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.startTime.IsZero() {
+		q.startIndex = startIndex
+		q.startTime = time.Now()
+	}
+
+	return q.startIndex + iotago.MilestoneIndex(time.Since(q.startTime)/q.milestoneInterval)
+}
+
+// deposit returns a random deposit following the configured distribution.
+func (q *SyntheticQueryer) deposit(rng *rand.Rand) uint64 {
+	depositRange := float64(q.maxDeposit - q.minDeposit)
+
+	var deposit uint64
+	switch q.distribution {
+	case DistributionExponential:
+		// the mean of the distribution is at a tenth of the range, larger values are capped at max deposit
+		deposit = q.minDeposit + uint64(math.Min(rng.ExpFloat64()*depositRange/10, depositRange))
+	default:
+		deposit = q.minDeposit + uint64(rng.Float64()*depositRange)
+	}
+
+	return deposit
+}
+
+// randomTrits returns count random trits.
+func randomTrits(rng *rand.Rand, count int) trinary.Trits {
+	trits := make(trinary.Trits, count)
+	for i := range trits {
+		trits[i] = int8(rng.Intn(3) - 1)
+	}
+
+	return trits
+}
+
+// QueryMigratedFunds returns the synthetic migrations confirmed by the legacy milestone with the given index.
+func (q *SyntheticQueryer) QueryMigratedFunds(_ context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	// the data of a milestone must never change, so the generator is seeded with the index
+	//nolint:gosec // no need for a cryptographically secure generator for fake data
+	rng := rand.New(rand.NewSource(int64(msIndex)))
+
+	migrated := make([]*iotago.MigratedFundsEntry, 0, q.entriesPerMilestone)
+	for i := 0; i < q.entriesPerMilestone; i++ {
+		entry := &iotago.MigratedFundsEntry{
+			Address: &iotago.Ed25519Address{},
+			Deposit: q.deposit(rng),
+		}
+		t5b1.Encode(entry.TailTransactionHash[:], randomTrits(rng, consts.HashTrinarySize))
+		rng.Read(entry.Address.(*iotago.Ed25519Address)[:])
+
+		migrated = append(migrated, entry)
+	}
+
+	return migrated, nil
+}
+
+// QueryNextMigratedFunds returns the synthetic migrations of the next legacy milestone starting from startIndex.
+// If there are currently no more migrations, it returns the latest simulated milestone index that was checked.
+func (q *SyntheticQueryer) QueryNextMigratedFunds(ctx context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	latestIndex := q.latestIndex(startIndex)
+	if latestIndex < startIndex {
+		// the next legacy milestone was not simulated yet
+		return startIndex - 1, nil, nil
+	}
+
+	for index := startIndex; index <= latestIndex; index++ {
+		migrated, err := q.QueryMigratedFunds(ctx, index)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if len(migrated) > 0 {
+			return index, migrated, nil
+		}
+	}
+
+	return latestIndex, nil, nil
+}
@@ -0,0 +1,78 @@
+package migrator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const testPrivateNetworkName = "private_tangle1"
+
+func TestSyntheticQueryer(t *testing.T) {
+	q, err := migrator.NewSyntheticQueryer(testPrivateNetworkName, []string{testPrivateNetworkName}, time.Hour, 50, 1_000_000, 5_000_000, migrator.DistributionExponential)
+	require.NoError(t, err)
+
+	// the first queried milestone is available immediately
+	msIndex, entries, err := q.QueryNextMigratedFunds(context.Background(), 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, msIndex)
+	require.Len(t, entries, 50)
+
+	for _, entry := range entries {
+		require.GreaterOrEqual(t, entry.Deposit, uint64(1_000_000))
+		require.LessOrEqual(t, entry.Deposit, uint64(5_000_000))
+		require.NoError(t, migrator.VerifyMigratedFundsEntry(entry))
+	}
+
+	// the migrations of a milestone never change
+	entriesAgain, err := q.QueryMigratedFunds(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, entries, entriesAgain)
+
+	// the next milestone is not simulated yet
+	msIndex, entries, err = q.QueryNextMigratedFunds(context.Background(), 11)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, msIndex)
+	require.Empty(t, entries)
+}
+
+func TestSyntheticQueryerInvalidConfig(t *testing.T) {
+	_, err := migrator.NewSyntheticQueryer(testPrivateNetworkName, []string{testPrivateNetworkName}, time.Second, 10, iotago.MinMigratedFundsEntryDeposit-1, iotago.MinMigratedFundsEntryDeposit, migrator.DistributionUniform)
+	require.ErrorIs(t, err, migrator.ErrInvalidSyntheticConfig)
+
+	_, err = migrator.NewSyntheticQueryer(testPrivateNetworkName, []string{testPrivateNetworkName}, time.Second, 10, iotago.MinMigratedFundsEntryDeposit, iotago.MinMigratedFundsEntryDeposit, "unknown")
+	require.ErrorIs(t, err, migrator.ErrInvalidSyntheticConfig)
+}
+
+func TestSyntheticQueryerPrivateNetworkOnly(t *testing.T) {
+	tests := []struct {
+		name                string
+		networkName         string
+		privateNetworkNames []string
+		valid               bool
+	}{
+		{name: "private tangle", networkName: "private_tangle1", privateNetworkNames: []string{"other", "private_tangle1"}, valid: true},
+		{name: "unknown network name", networkName: "", privateNetworkNames: []string{""}, valid: false},
+		{name: "not configured", networkName: "private_tangle2", privateNetworkNames: []string{"private_tangle1"}, valid: false},
+		{name: "no private tangles", networkName: "private_tangle1", privateNetworkNames: nil, valid: false},
+		{name: "mainnet", networkName: "iota-mainnet", privateNetworkNames: []string{"iota-mainnet"}, valid: false},
+		{name: "shimmer", networkName: "shimmer", privateNetworkNames: []string{"shimmer"}, valid: false},
+		{name: "testnet", networkName: "testnet", privateNetworkNames: []string{"testnet"}, valid: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := migrator.NewSyntheticQueryer(test.networkName, test.privateNetworkNames, time.Second, 10, iotago.MinMigratedFundsEntryDeposit, iotago.MinMigratedFundsEntryDeposit, migrator.DistributionUniform)
+			if test.valid {
+				require.NoError(t, err)
+
+				return
+			}
+			require.ErrorIs(t, err, migrator.ErrNotPrivateNetwork)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/encoding/t5b1"
+	"github.com/iotaledger/iota.go/trinary"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// VerifyMigratedFundsEntry independently re-verifies a migration returned by the legacy node.
+// The tail transaction hash must be a valid T5B1 encoded legacy hash, the target must be an Ed25519 address
+// and the deposit must be within the bounds of the legacy network.
+func VerifyMigratedFundsEntry(entry *iotago.MigratedFundsEntry) error {
+	if entry == nil {
+		return fmt.Errorf("%w: empty migration", ErrInvalidMigrations)
+	}
+
+	if err := verifyLegacyTailTransactionHash(entry.TailTransactionHash); err != nil {
+		return err
+	}
+
+	switch address := entry.Address.(type) {
+	case *iotago.Ed25519Address:
+		if address == nil {
+			return fmt.Errorf("%w: migration without address", ErrInvalidMigrations)
+		}
+	case nil:
+		return fmt.Errorf("%w: migration without address", ErrInvalidMigrations)
+	default:
+		return fmt.Errorf("%w: migration to unsupported address type %s", ErrInvalidMigrations, entry.Address.Type())
+	}
+
+	if entry.Deposit < iotago.MinMigratedFundsEntryDeposit {
+		return fmt.Errorf("%w: deposit %d below minimum %d", ErrInvalidMigrations, entry.Deposit, iotago.MinMigratedFundsEntryDeposit)
+	}
+
+	if entry.Deposit > consts.TotalSupply {
+		return fmt.Errorf("%w: deposit %d exceeds the legacy total supply", ErrInvalidMigrations, entry.Deposit)
+	}
+
+	return nil
+}
+
+// verifyLegacyTailTransactionHash checks that the hash is a valid T5B1 encoding of a 243 trit legacy hash.
+func verifyLegacyTailTransactionHash(hash iotago.LegacyTailTransactionHash) error {
+	trits := make(trinary.Trits, t5b1.DecodedLen(len(hash)))
+	if _, err := t5b1.Decode(trits, hash[:]); err != nil {
+		return fmt.Errorf("%w: malformed tail transaction hash: %s", ErrInvalidMigrations, err)
+	}
+
+	// the encoding contains more trits than the hash, the padding must be zero
+	for _, trit := range trits[consts.HashTrinarySize:] {
+		if trit != 0 {
+			return fmt.Errorf("%w: tail transaction hash exceeds %d trits", ErrInvalidMigrations, consts.HashTrinarySize)
+		}
+	}
+
+	return nil
+}
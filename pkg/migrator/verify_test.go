@@ -0,0 +1,53 @@
+package migrator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/iota.go/encoding/t5b1"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func validMigratedFundsEntry() *iotago.MigratedFundsEntry {
+	entry := &iotago.MigratedFundsEntry{
+		Address: &iotago.Ed25519Address{1, 2, 3},
+		Deposit: 1_000_000,
+	}
+	copy(entry.TailTransactionHash[:], t5b1.EncodeTrytes("TAILTRANSACTIONHASH9999999999999999999999999999999999999999999999999999999999999"))
+
+	return entry
+}
+
+func TestVerifyMigratedFundsEntry(t *testing.T) {
+	require.NoError(t, migrator.VerifyMigratedFundsEntry(validMigratedFundsEntry()))
+
+	// bytes outside of the T5B1 range
+	entry := validMigratedFundsEntry()
+	entry.TailTransactionHash[0] = 122
+	require.ErrorIs(t, migrator.VerifyMigratedFundsEntry(entry), migrator.ErrInvalidMigrations)
+
+	// the padding trits of the last byte must be zero
+	entry = validMigratedFundsEntry()
+	entry.TailTransactionHash[len(entry.TailTransactionHash)-1] = 81
+	require.ErrorIs(t, migrator.VerifyMigratedFundsEntry(entry), migrator.ErrInvalidMigrations)
+
+	entry = validMigratedFundsEntry()
+	entry.Address = nil
+	require.ErrorIs(t, migrator.VerifyMigratedFundsEntry(entry), migrator.ErrInvalidMigrations)
+
+	entry = validMigratedFundsEntry()
+	entry.Address = &iotago.AliasAddress{}
+	require.ErrorIs(t, migrator.VerifyMigratedFundsEntry(entry), migrator.ErrInvalidMigrations)
+
+	entry = validMigratedFundsEntry()
+	entry.Deposit = iotago.MinMigratedFundsEntryDeposit - 1
+	require.ErrorIs(t, migrator.VerifyMigratedFundsEntry(entry), migrator.ErrInvalidMigrations)
+
+	entry = validMigratedFundsEntry()
+	entry.Deposit = 2_779_530_283_277_762
+	require.ErrorIs(t, migrator.VerifyMigratedFundsEntry(entry), migrator.ErrInvalidMigrations)
+
+	require.ErrorIs(t, migrator.VerifyMigratedFundsEntry(nil), migrator.ErrInvalidMigrations)
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/bits-and-blooms/bitset"
 	"github.com/pkg/errors"
 
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -19,6 +21,18 @@ var (
 	ErrNoTipsAvailable = errors.New("no tips available")
 )
 
+// TipSelectionResultCaller is used to signal the result of a tip selection.
+func TipSelectionResultCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(result *TipSelectionResult))(params[0].(*TipSelectionResult))
+}
+
+// Events are the events issued by the HeaviestSelector.
+type Events struct {
+	// TipsSelected is triggered after tips were selected.
+	TipsSelected *events.Event
+}
+
 // HeaviestSelector implements the heaviest branch selection strategy.
 type HeaviestSelector struct {
 	sync.Mutex
@@ -34,18 +48,30 @@ type HeaviestSelector struct {
 	randomTipsPerCheckpoint int
 	// the maximum duration to select the heaviest branch tips
 	heaviestBranchSelectionTimeout time.Duration
+	// the amount of heaviest branch tips the selection aims for (0 = disabled)
+	targetConeWidth int
+	// how much tips with older past cones are preferred (0 = disabled)
+	coneAgeWeight float64
+	// the past cone age at which the maximum age bonus is reached
+	maxConeAge time.Duration
+	// the factor the score of tips is multiplied with, if the node marked them to be promoted or reattached
+	lazyTipPenalty float64
 	// map of all tracked blocks
 	trackedBlocks map[iotago.BlockID]*trackedBlock
 	// list of available tips
 	tips *list.List
 	// whether new blocks should be accepted (used to protect against memory overflows in case the issuing of milestones is halted)
 	acceptNewBlocks bool
+	// events of the HeaviestSelector.
+	Events *Events
 }
 
 type trackedBlock struct {
-	blockID iotago.BlockID // block ID of the corresponding block
-	tip     *list.Element  // pointer to the element in the tip list
-	refs    *bitset.BitSet // BitSet of all the referenced blocks
+	blockID        iotago.BlockID // block ID of the corresponding block
+	tip            *list.Element  // pointer to the element in the tip list
+	refs           *bitset.BitSet // BitSet of all the referenced blocks
+	oldestConeTime time.Time      // the time the oldest tracked block in the past cone became solid
+	lazy           bool           // whether the node marked the block to be promoted or reattached
 }
 
 type TrackedBlocksList struct {
@@ -97,19 +123,35 @@ func (il *TrackedBlocksList) removeTip(tip *trackedBlock) {
 }
 
 // New creates a new HeaviestSelector instance.
-func New(minHeaviestBranchUnreferencedBlocksThreshold int, maxHeaviestBranchTipsPerCheckpoint int, randomTipsPerCheckpoint int, heaviestBranchSelectionTimeout time.Duration) *HeaviestSelector {
-	s := &HeaviestSelector{
+func New(minHeaviestBranchUnreferencedBlocksThreshold int, maxHeaviestBranchTipsPerCheckpoint int, randomTipsPerCheckpoint int, heaviestBranchSelectionTimeout time.Duration, opts ...options.Option[HeaviestSelector]) *HeaviestSelector {
+	s := options.Apply(&HeaviestSelector{
 		minHeaviestBranchUnreferencedBlocksThreshold: minHeaviestBranchUnreferencedBlocksThreshold,
 		maxHeaviestBranchTipsPerCheckpoint:           maxHeaviestBranchTipsPerCheckpoint,
 		randomTipsPerCheckpoint:                      randomTipsPerCheckpoint,
 		heaviestBranchSelectionTimeout:               heaviestBranchSelectionTimeout,
+		maxConeAge:                                   defaultMaxConeAge,
+		lazyTipPenalty:                               1.0,
 		acceptNewBlocks:                              true,
-	}
+		Events: &Events{
+			TipsSelected: events.NewEvent(TipSelectionResultCaller),
+		},
+	}, opts)
 	s.Reset()
 
 	return s
 }
 
+// WithTargetConeWidth defines the amount of heaviest branch tips the selection aims for.
+// Tips are collected until the target is reached, as long as they reference unreferenced blocks,
+// even if the heaviest branch is below "minHeaviestBranchUnreferencedBlocksThreshold".
+// No more heaviest branch tips are collected once the target is reached.
+// The width is capped by "maxHeaviestBranchTipsPerCheckpoint".
+func WithTargetConeWidth(width int) options.Option[HeaviestSelector] {
+	return func(s *HeaviestSelector) {
+		s.targetConeWidth = width
+	}
+}
+
 func (s *HeaviestSelector) Stop() {
 	s.Lock()
 	defer s.Unlock()
@@ -137,45 +179,47 @@ func (s *HeaviestSelector) Reset() {
 }
 
 // selectTip selects a tip to be used for the next checkpoint.
-// it returns a tip, confirming the most blocks in the future cone,
-// and the amount of referenced blocks of this tip, that were not referenced by previously chosen tips.
-func (s *HeaviestSelector) selectTip(tipsList *TrackedBlocksList) (*trackedBlock, uint, error) {
+// it returns the tip with the best cone score, which is mainly determined by the amount of
+// referenced blocks of this tip, that were not referenced by previously chosen tips.
+func (s *HeaviestSelector) selectTip(tipsList *TrackedBlocksList, now time.Time) (*trackedBlock, *ConeScore, error) {
 
 	if tipsList.Len() == 0 {
-		return nil, 0, ErrNoTipsAvailable
+		return nil, nil, ErrNoTipsAvailable
 	}
 
 	var best = struct {
-		tips  []*trackedBlock
-		count uint
+		tips   []*trackedBlock
+		scores []*ConeScore
+		score  float64
 	}{
-		tips:  []*trackedBlock{},
-		count: 0,
+		tips:   []*trackedBlock{},
+		scores: []*ConeScore{},
+		score:  -1,
 	}
 
-	// loop through all tips and find the one with the most referenced blocks
+	// loop through all tips and find the one with the best score
 	for _, tip := range tipsList.blocks {
-		c := tip.refs.Count()
-		if c > best.count {
-			// tip with heavier branch found
-			best.tips = []*trackedBlock{
-				tip,
-			}
-			best.count = c
-		} else if c == best.count {
+		coneScore := s.scoreTip(tip, now)
+		if coneScore.Score > best.score {
+			// tip with better cone found
+			best.tips = []*trackedBlock{tip}
+			best.scores = []*ConeScore{coneScore}
+			best.score = coneScore.Score
+		} else if coneScore.Score == best.score {
 			// add the tip to the slice of currently best tips
 			best.tips = append(best.tips, tip)
+			best.scores = append(best.scores, coneScore)
 		}
 	}
 
 	if len(best.tips) == 0 {
-		return nil, 0, ErrNoTipsAvailable
+		return nil, nil, ErrNoTipsAvailable
 	}
 
 	// select a random tip from the provided slice of tips.
-	selected := best.tips[randomInsecure(0, len(best.tips)-1)]
+	selected := randomInsecure(0, len(best.tips)-1)
 
-	return selected, best.count, nil
+	return best.tips[selected], best.scores[selected], nil
 }
 
 // SelectTips tries to collect tips that confirm the most recent blocks since the last reset of the selector.
@@ -185,6 +229,7 @@ func (s *HeaviestSelector) selectTip(tipsList *TrackedBlocksList) (*trackedBlock
 // to prevent attackers from creating heavier branches while we are searching the best tips.
 // "maxHeaviestBranchTipsPerCheckpoint" is the amount of tips that are collected if
 // the current best tip is not below "UnreferencedBlocksThreshold" before.
+// if a target cone width is set, the selection adapts the amount of collected tips to the target.
 // a minimum amount of selected tips can be enforced, even if none of the heaviest branches matches the
 // "minHeaviestBranchUnreferencedBlocksThreshold" criteria.
 // if at least one heaviest branch tip was found, "randomTipsPerCheckpoint" random tips are added
@@ -205,6 +250,11 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (iotago.BlockIDs, err
 
 	var tips iotago.BlockIDs
 
+	ts := time.Now()
+	result := &TipSelectionResult{
+		Candidates: tipsList.Len(),
+	}
+
 	// run the tip selection for at most 0.1s to keep the view on the tangle recent; this should be plenty
 	ctx, cancel := context.WithTimeout(context.Background(), s.heaviestBranchSelectionTimeout)
 	defer cancel()
@@ -219,19 +269,20 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (iotago.BlockIDs, err
 		default:
 		}
 
-		tip, count, err := s.selectTip(tipsList)
+		tip, coneScore, err := s.selectTip(tipsList, ts)
 		if err != nil {
 			break
 		}
 
-		if (len(tips) > minRequiredTips) && ((count < uint(s.minHeaviestBranchUnreferencedBlocksThreshold)) || deadlineExceeded) {
-			// minimum amount of tips reached and the heaviest tips do not confirm enough blocks or the deadline was exceeded
-			// => no need to collect more
+		if (len(tips) > minRequiredTips) && ((coneScore.UnreferencedBlocks < s.unreferencedBlocksThreshold(len(tips))) || deadlineExceeded || s.targetConeWidthReached(len(tips))) {
+			// minimum amount of tips reached and the heaviest tips do not confirm enough blocks, the deadline was exceeded
+			// or the target cone width was reached => no need to collect more
 			break
 		}
 
 		tipsList.referenceTip(tip)
 		tips = append(tips, tip.blockID)
+		result.Scores = append(result.Scores, coneScore)
 	}
 
 	if len(tips) == 0 {
@@ -252,10 +303,28 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (iotago.BlockIDs, err
 	// reset the whole HeaviestSelector if valid tips were found
 	s.Reset()
 
+	result.Duration = time.Since(ts)
+	s.Events.TipsSelected.Trigger(result)
+
 	//nolint:nilerr // false positive
 	return tips, nil
 }
 
+// unreferencedBlocksThreshold returns the minimum amount of unreferenced blocks the next heaviest branch tip needs to reference.
+// Below the target cone width, every tip that references unreferenced blocks widens the cone.
+func (s *HeaviestSelector) unreferencedBlocksThreshold(selectedTips int) uint {
+	if selectedTips < s.targetConeWidth {
+		return 1
+	}
+
+	return uint(s.minHeaviestBranchUnreferencedBlocksThreshold)
+}
+
+// targetConeWidthReached returns whether enough heaviest branch tips were selected to reach the target cone width.
+func (s *HeaviestSelector) targetConeWidthReached(selectedTips int) bool {
+	return s.targetConeWidth > 0 && selectedTips >= s.targetConeWidth
+}
+
 // OnNewSolidBlock adds a new block to be processed by s.
 // The block must be solid and OnNewSolidBlock must be called in the order of solidification.
 // The block must also not be below max depth.
@@ -290,10 +359,20 @@ func (s *HeaviestSelector) OnNewSolidBlock(blockMeta *inx.BlockMetadata) (tracke
 	// if a new child is added, we expand the bitset by 1 bit and store the Union of the bitsets
 	// of the parents for this child, to know which parts of the cone are referenced by this child.
 	idx := uint(len(s.trackedBlocks))
-	it := &trackedBlock{blockID: blockID, refs: bitset.New(idx + 1).Set(idx)}
+	it := &trackedBlock{
+		blockID:        blockID,
+		refs:           bitset.New(idx + 1).Set(idx),
+		oldestConeTime: time.Now(),
+		lazy:           blockMeta.GetShouldPromote() || blockMeta.GetShouldReattach(),
+	}
 
 	for _, parentItem := range parentItems {
 		it.refs.InPlaceUnion(parentItem.refs)
+
+		// the age of the past cone is determined by its oldest tracked block
+		if parentItem.oldestConeTime.Before(it.oldestConeTime) {
+			it.oldestConeTime = parentItem.oldestConeTime
+		}
 	}
 	s.trackedBlocks[it.blockID] = it
 
@@ -4,9 +4,11 @@ package mselection_test
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/iotaledger/hive.go/core/events"
 	"github.com/iotaledger/inx-coordinator/pkg/mselection"
 	inx "github.com/iotaledger/inx/go"
 	iotago "github.com/iotaledger/iota.go/v3"
@@ -184,3 +186,91 @@ func BenchmarkHeaviestSelector_OnNewSolidBlock(b *testing.B) {
 	}
 	hps.Reset()
 }
+
+func TestHeaviestSelector_LazyTipPenalty(t *testing.T) {
+	hps := mselection.New(
+		CfgCoordinatorTipselectMinHeaviestBranchUnreferencedBlocksThreshold,
+		CfgCoordinatorTipselectMaxHeaviestBranchTipsPerCheckpoint,
+		0,
+		CfgCoordinatorTipselectHeaviestBranchSelectionTimeoutMilliseconds,
+		mselection.WithLazyTipPenalty(0.5),
+	)
+
+	var result *mselection.TipSelectionResult
+	hps.Events.TipsSelected.Hook(events.NewClosure(func(r *mselection.TipSelectionResult) {
+		result = r
+	}))
+
+	// create two chains of the same length, the tip of the first one is lazy
+	lastBlockIDs := make(iotago.BlockIDs, 2)
+	for i := 0; i < 2; i++ {
+		lastBlockIDs[i] = iotago.EmptyBlockID()
+		for j := 1; j <= numTestBlocks; j++ {
+			metadata, blockID := newMetadata(iotago.BlockIDs{lastBlockIDs[i]})
+			metadata.ShouldPromote = i == 0 && j == numTestBlocks
+			hps.OnNewSolidBlock(metadata)
+			lastBlockIDs[i] = blockID
+		}
+	}
+
+	tips, err := hps.SelectTips(1)
+	assert.NoError(t, err)
+	assert.Len(t, tips, 2)
+
+	// the tip of the second chain is preferred, because the first one is lazy
+	assert.Equal(t, lastBlockIDs[1], tips[0])
+	assert.Equal(t, lastBlockIDs[0], tips[1])
+
+	assert.NotNil(t, result)
+	assert.Equal(t, 2, result.Candidates)
+	assert.Len(t, result.Scores, 2)
+	assert.False(t, result.Scores[0].Lazy)
+	assert.True(t, result.Scores[1].Lazy)
+	assert.EqualValues(t, numTestBlocks, result.Scores[1].UnreferencedBlocks)
+	assert.Equal(t, float64(numTestBlocks)*0.5, result.Scores[1].Score)
+}
+
+func TestHeaviestSelector_TargetConeWidth(t *testing.T) {
+	// short chains, whose tips are all below the unreferenced blocks threshold
+	newChains := func(hps *mselection.HeaviestSelector, numChains int) {
+		for i := 0; i < numChains; i++ {
+			lastBlockID := iotago.EmptyBlockID()
+			for j := 0; j < 5; j++ {
+				metadata, blockID := newMetadata(iotago.BlockIDs{lastBlockID})
+				hps.OnNewSolidBlock(metadata)
+				lastBlockID = blockID
+			}
+		}
+	}
+
+	// the deadline of the selection is not exceeded, so only the target cone width stops it
+	for _, test := range []struct {
+		targetConeWidth int
+		expectedTips    int
+	}{
+		{targetConeWidth: 0, expectedTips: 2},
+		{targetConeWidth: 4, expectedTips: 4},
+		{targetConeWidth: 8, expectedTips: 6},
+	} {
+		hps := mselection.New(
+			CfgCoordinatorTipselectMinHeaviestBranchUnreferencedBlocksThreshold,
+			CfgCoordinatorTipselectMaxHeaviestBranchTipsPerCheckpoint,
+			0,
+			time.Second,
+			mselection.WithTargetConeWidth(test.targetConeWidth),
+		)
+		newChains(hps, 6)
+
+		tips, err := hps.SelectTips(1)
+		assert.NoError(t, err)
+		assert.Len(t, tips, test.expectedTips)
+	}
+
+	// the target cone width stops the selection, even if the heaviest branches confirm enough blocks
+	hps := mselection.New(0, CfgCoordinatorTipselectMaxHeaviestBranchTipsPerCheckpoint, 0, time.Second, mselection.WithTargetConeWidth(3))
+	newChains(hps, 6)
+
+	tips, err := hps.SelectTips(1)
+	assert.NoError(t, err)
+	assert.Len(t, tips, 3)
+}
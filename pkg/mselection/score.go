@@ -0,0 +1,79 @@
+package mselection
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+)
+
+const (
+	defaultMaxConeAge = time.Minute
+)
+
+// ConeScore is the quality score of the past cone of a tip.
+type ConeScore struct {
+	// the amount of blocks in the past cone that are not referenced by previously chosen tips.
+	UnreferencedBlocks uint
+	// the age of the oldest tracked block in the past cone.
+	ConeAge time.Duration
+	// whether the node marked the tip to be promoted or reattached.
+	Lazy bool
+	// the resulting score of the tip.
+	Score float64
+}
+
+// TipSelectionResult contains the scores of the tips chosen by a tip selection.
+type TipSelectionResult struct {
+	// the amount of tips that were available for the selection.
+	Candidates int
+	// the scores of the chosen heaviest branch tips, random tips are not scored.
+	Scores []*ConeScore
+	// the duration of the tip selection.
+	Duration time.Duration
+}
+
+// WithConeAgeWeight defines how much tips with older past cones are preferred.
+// A weight of 1 doubles the score of a tip whose past cone is at least maxConeAge old.
+func WithConeAgeWeight(weight float64, maxConeAge time.Duration) options.Option[HeaviestSelector] {
+	return func(s *HeaviestSelector) {
+		s.coneAgeWeight = weight
+		s.maxConeAge = maxConeAge
+	}
+}
+
+// WithLazyTipPenalty defines the factor the score of tips is multiplied with,
+// if the node marked them to be promoted or reattached.
+func WithLazyTipPenalty(penalty float64) options.Option[HeaviestSelector] {
+	return func(s *HeaviestSelector) {
+		s.lazyTipPenalty = penalty
+	}
+}
+
+// scoreTip scores the past cone of the tip.
+// Tips are preferred if they reference many unreferenced blocks and if their past cone contains old blocks,
+// so that blocks that already waited for a long time get confirmed first.
+// Tips the node considers lazy are penalized, because they don't help to confirm recent blocks.
+func (s *HeaviestSelector) scoreTip(tip *trackedBlock, now time.Time) *ConeScore {
+
+	coneScore := &ConeScore{
+		UnreferencedBlocks: tip.refs.Count(),
+		ConeAge:            now.Sub(tip.oldestConeTime),
+		Lazy:               tip.lazy,
+	}
+
+	ageFactor := 1.0
+	if s.coneAgeWeight > 0 && s.maxConeAge > 0 {
+		coneAge := coneScore.ConeAge
+		if coneAge > s.maxConeAge {
+			coneAge = s.maxConeAge
+		}
+		ageFactor += s.coneAgeWeight * float64(coneAge) / float64(s.maxConeAge)
+	}
+
+	coneScore.Score = float64(coneScore.UnreferencedBlocks) * ageFactor
+	if coneScore.Lazy {
+		coneScore.Score *= s.lazyTipPenalty
+	}
+
+	return coneScore
+}
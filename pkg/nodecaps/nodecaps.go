@@ -0,0 +1,241 @@
+package nodecaps
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// The INX APIs the components depend on, which are probed on demand.
+const (
+	// APIComputeWhiteFlag computes the merkle roots of the milestones.
+	APIComputeWhiteFlag = "ComputeWhiteFlag"
+	// APIListenToTreasuryUpdates follows the treasury output that receipts consume.
+	APIListenToTreasuryUpdates = "ListenToTreasuryUpdates"
+	// APIListenToMigrationReceipts follows the receipts confirmed by the network.
+	APIListenToMigrationReceipts = "ListenToMigrationReceipts"
+	// APIRegisterAPIRoute registers the coordinator API at the node.
+	APIRegisterAPIRoute = "RegisterAPIRoute"
+
+	// the route that is unregistered to probe the API route registration, it is never registered.
+	probeRoute = "coordinator/capabilities-probe"
+)
+
+var (
+	// ErrIncompatibleNode is returned when the node lacks a capability the coordinator depends on.
+	ErrIncompatibleNode = errors.New("node is incompatible")
+	// ErrUnknownAPI is returned when an INX API is checked that can't be probed.
+	ErrUnknownAPI = errors.New("unknown INX API")
+)
+
+// probeFunc calls an INX API with a request that doesn't change the node and returns the error of the call.
+type probeFunc func(ctx context.Context, client inx.INXClient) error
+
+// probes call the INX APIs with requests that are rejected or answered without side effects.
+var probes = map[string]probeFunc{
+	APIComputeWhiteFlag: func(ctx context.Context, client inx.INXClient) error {
+		// milestone index 0 is never valid, so the node rejects the request before computing anything
+		_, err := client.ComputeWhiteFlag(ctx, &inx.WhiteFlagRequest{})
+
+		return err
+	},
+	APIListenToTreasuryUpdates: func(ctx context.Context, client inx.INXClient) error {
+		stream, err := client.ListenToTreasuryUpdates(ctx, &inx.MilestoneRangeRequest{})
+		if err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+
+		return err
+	},
+	APIListenToMigrationReceipts: func(ctx context.Context, client inx.INXClient) error {
+		stream, err := client.ListenToMigrationReceipts(ctx, &inx.NoParams{})
+		if err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+
+		return err
+	},
+	APIRegisterAPIRoute: func(ctx context.Context, client inx.INXClient) error {
+		// registering and unregistering are only supported together, unregistering an unknown route has no effect
+		_, err := client.UnregisterAPIRoute(ctx, &inx.APIRouteRequest{Route: probeRoute})
+
+		return err
+	},
+}
+
+// Capabilities are the capabilities of the node the coordinator is connected to.
+// The protocol versions and the pruning state are read once at startup, the INX APIs are probed when they are checked the first time.
+// A nil Capabilities accepts every node, which disables the checks.
+type Capabilities struct {
+	// the logger used to log events.
+	*logger.WrappedLogger
+
+	client       inx.INXClient
+	probeTimeout time.Duration
+
+	// the name of the network the node is connected to.
+	NetworkName string
+	// the protocol version of the current protocol parameters of the node.
+	ProtocolVersion byte
+	// the protocol versions the node supports.
+	SupportedProtocolVersions []byte
+	// the index up to which the node pruned the milestones.
+	MilestonesPruningIndex iotago.MilestoneIndex
+	// the index up to which the node pruned the tangle.
+	TanglePruningIndex iotago.MilestoneIndex
+	// the index up to which the node pruned the ledger.
+	LedgerPruningIndex iotago.MilestoneIndex
+
+	// apisLock protects the probed APIs.
+	apisLock syncutils.Mutex
+	// whether the probed APIs are supported.
+	apis map[string]bool
+}
+
+// New creates the Capabilities of the node from its configuration and status.
+// The INX APIs are probed with the given timeout, streams that didn't return an error until then are supported.
+func New(log *logger.Logger, client inx.INXClient, nodeConfig *inx.NodeConfiguration, nodeStatus *inx.NodeStatus, protoParams *iotago.ProtocolParameters, probeTimeout time.Duration) *Capabilities {
+	supportedVersions := make([]byte, 0, len(nodeConfig.GetSupportedProtocolVersions()))
+	for _, version := range nodeConfig.GetSupportedProtocolVersions() {
+		supportedVersions = append(supportedVersions, byte(version))
+	}
+
+	return &Capabilities{
+		WrappedLogger:             logger.NewWrappedLogger(log),
+		client:                    client,
+		probeTimeout:              probeTimeout,
+		NetworkName:               protoParams.NetworkName,
+		ProtocolVersion:           protoParams.Version,
+		SupportedProtocolVersions: supportedVersions,
+		MilestonesPruningIndex:    nodeStatus.GetMilestonesPruningIndex(),
+		TanglePruningIndex:        nodeStatus.GetTanglePruningIndex(),
+		LedgerPruningIndex:        nodeStatus.GetLedgerPruningIndex(),
+		apis:                      make(map[string]bool),
+	}
+}
+
+// SupportsProtocolVersion returns whether the node supports the given protocol version.
+func (c *Capabilities) SupportsProtocolVersion(version byte) bool {
+	if c == nil {
+		return true
+	}
+
+	// older nodes don't report their supported versions
+	if len(c.SupportedProtocolVersions) == 0 {
+		return version == c.ProtocolVersion
+	}
+
+	for _, supported := range c.SupportedProtocolVersions {
+		if supported == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsAPI returns whether the node implements the given INX API.
+// Only a node that reports the API as unimplemented doesn't support it, other errors of the probe are expected.
+func (c *Capabilities) SupportsAPI(ctx context.Context, api string) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+
+	probe, exists := probes[api]
+	if !exists {
+		return false, fmt.Errorf("%w: %s", ErrUnknownAPI, api)
+	}
+
+	c.apisLock.Lock()
+	defer c.apisLock.Unlock()
+
+	if supported, probed := c.apis[api]; probed {
+		return supported, nil
+	}
+
+	ctxProbe, cancel := context.WithTimeout(ctx, c.probeTimeout)
+	defer cancel()
+
+	err := probe(ctxProbe, c.client)
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	supported := status.Code(err) != codes.Unimplemented
+	c.apis[api] = supported
+
+	return supported, nil
+}
+
+// RequireAPIs returns an error if the node doesn't implement one of the INX APIs the given feature depends on.
+func (c *Capabilities) RequireAPIs(ctx context.Context, feature string, apis ...string) error {
+	for _, api := range apis {
+		supported, err := c.SupportsAPI(ctx, api)
+		if err != nil {
+			return err
+		}
+
+		if !supported {
+			return fmt.Errorf("%w: %s needs the INX API %s, which is not implemented by the node", ErrIncompatibleNode, feature, api)
+		}
+	}
+
+	return nil
+}
+
+// CheckProtocolVersions returns an error if the node doesn't run the protocol version that milestones are issued with from nextIndex on.
+// Protocol versions that are activated later only need to be supported by the node once they are active, so only a warning is logged.
+func (c *Capabilities) CheckProtocolVersions(activations []*coordinator.ProtocolActivation, nextIndex iotago.MilestoneIndex) error {
+	if c == nil || len(activations) == 0 {
+		return nil
+	}
+
+	// the activations are sorted by their start index
+	active := activations[0]
+	for _, activation := range activations {
+		if activation.StartIndex > nextIndex {
+			if !c.SupportsProtocolVersion(activation.Version) {
+				c.LogWarnf("protocol version %d is activated at milestone %d, but not supported by the node, the node needs to be upgraded before", activation.Version, activation.StartIndex)
+			}
+
+			continue
+		}
+		active = activation
+	}
+
+	if !c.SupportsProtocolVersion(active.Version) {
+		return fmt.Errorf("%w: milestone %d is issued with protocol version %d, which is not supported by the node (supported versions %v)", ErrIncompatibleNode, nextIndex, active.Version, c.SupportedProtocolVersions)
+	}
+
+	// the node switches to the new protocol version with the first milestone that is issued with it
+	if active.StartIndex < nextIndex && active.Version != c.ProtocolVersion {
+		return fmt.Errorf("%w: milestone %d is issued with protocol version %d, but the node runs protocol version %d", ErrIncompatibleNode, nextIndex, active.Version, c.ProtocolVersion)
+	}
+
+	return nil
+}
+
+// CheckPruning returns an error if the node already pruned the given milestone the coordinator resumes from (0 = none).
+func (c *Capabilities) CheckPruning(latestMilestoneIndex iotago.MilestoneIndex) error {
+	if c == nil || latestMilestoneIndex == 0 {
+		return nil
+	}
+
+	if c.MilestonesPruningIndex >= latestMilestoneIndex {
+		return fmt.Errorf("%w: the node pruned the milestones up to %d, but the coordinator resumes from milestone %d", ErrIncompatibleNode, c.MilestonesPruningIndex, latestMilestoneIndex)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package nodecaps_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/nodecaps"
+	inx "github.com/iotaledger/inx/go"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// mockINXClient implements the probed INX APIs, the APIs in unimplemented are reported as unimplemented.
+type mockINXClient struct {
+	inx.INXClient
+	unimplemented map[string]struct{}
+	calls         map[string]int
+}
+
+func (m *mockINXClient) result(api string) error {
+	m.calls[api]++
+	if _, unimplemented := m.unimplemented[api]; unimplemented {
+		return status.Errorf(codes.Unimplemented, "method %s not implemented", api)
+	}
+
+	return status.Error(codes.InvalidArgument, "invalid request")
+}
+
+func (m *mockINXClient) ComputeWhiteFlag(_ context.Context, _ *inx.WhiteFlagRequest, _ ...grpc.CallOption) (*inx.WhiteFlagResponse, error) {
+	return nil, m.result(nodecaps.APIComputeWhiteFlag)
+}
+
+func (m *mockINXClient) UnregisterAPIRoute(_ context.Context, _ *inx.APIRouteRequest, _ ...grpc.CallOption) (*inx.NoParams, error) {
+	return nil, m.result(nodecaps.APIRegisterAPIRoute)
+}
+
+// blockingStream is a stream that doesn't send anything until its context is done.
+type blockingStream struct {
+	inx.INX_ListenToMigrationReceiptsClient
+	ctx context.Context
+}
+
+func (s *blockingStream) Recv() (*inx.RawReceipt, error) {
+	<-s.ctx.Done()
+
+	return nil, status.FromContextError(s.ctx.Err()).Err()
+}
+
+func (m *mockINXClient) ListenToMigrationReceipts(ctx context.Context, _ *inx.NoParams, _ ...grpc.CallOption) (inx.INX_ListenToMigrationReceiptsClient, error) {
+	if err := m.result(nodecaps.APIListenToMigrationReceipts); status.Code(err) == codes.Unimplemented {
+		return nil, err
+	}
+
+	return &blockingStream{ctx: ctx}, nil
+}
+
+func newTestCapabilities(t *testing.T, client inx.INXClient, supportedVersions []uint32, milestonesPruningIndex iotago.MilestoneIndex) *nodecaps.Capabilities {
+	t.Helper()
+
+	return nodecaps.New(
+		logger.NewNopLogger(),
+		client,
+		&inx.NodeConfiguration{SupportedProtocolVersions: supportedVersions},
+		&inx.NodeStatus{MilestonesPruningIndex: milestonesPruningIndex},
+		&iotago.ProtocolParameters{Version: coordinator.ProtocolVersionStardust, NetworkName: "testnet"},
+		100*time.Millisecond,
+	)
+}
+
+func TestSupportsAPI(t *testing.T) {
+	client := &mockINXClient{
+		unimplemented: map[string]struct{}{nodecaps.APIRegisterAPIRoute: {}},
+		calls:         make(map[string]int),
+	}
+	capabilities := newTestCapabilities(t, client, []uint32{2}, 0)
+	ctx := context.Background()
+
+	// the probes are only done once
+	for i := 0; i < 2; i++ {
+		supported, err := capabilities.SupportsAPI(ctx, nodecaps.APIComputeWhiteFlag)
+		require.NoError(t, err)
+		require.True(t, supported)
+
+		supported, err = capabilities.SupportsAPI(ctx, nodecaps.APIRegisterAPIRoute)
+		require.NoError(t, err)
+		require.False(t, supported)
+	}
+	require.Equal(t, 1, client.calls[nodecaps.APIComputeWhiteFlag])
+	require.Equal(t, 1, client.calls[nodecaps.APIRegisterAPIRoute])
+
+	// streams that don't fail until the timeout are supported
+	supported, err := capabilities.SupportsAPI(ctx, nodecaps.APIListenToMigrationReceipts)
+	require.NoError(t, err)
+	require.True(t, supported)
+
+	_, err = capabilities.SupportsAPI(ctx, "ReadEverything")
+	require.ErrorIs(t, err, nodecaps.ErrUnknownAPI)
+
+	require.NoError(t, capabilities.RequireAPIs(ctx, "the coordinator", nodecaps.APIComputeWhiteFlag))
+	require.ErrorIs(t, capabilities.RequireAPIs(ctx, "the REST API", nodecaps.APIComputeWhiteFlag, nodecaps.APIRegisterAPIRoute), nodecaps.ErrIncompatibleNode)
+
+	client.unimplemented[nodecaps.APIListenToMigrationReceipts] = struct{}{}
+	capabilities = newTestCapabilities(t, client, []uint32{2}, 0)
+	require.ErrorIs(t, capabilities.RequireAPIs(ctx, "the receipt mirror", nodecaps.APIListenToMigrationReceipts), nodecaps.ErrIncompatibleNode)
+}
+
+func TestCheckProtocolVersions(t *testing.T) {
+	const nextProtocolVersion = coordinator.ProtocolVersionStardust + 1
+
+	tests := []struct {
+		name              string
+		supportedVersions []uint32
+		activations       []*coordinator.ProtocolActivation
+		nextIndex         iotago.MilestoneIndex
+		wantErr           bool
+	}{
+		{
+			name:              "active version supported",
+			supportedVersions: []uint32{2},
+			activations:       []*coordinator.ProtocolActivation{{Version: coordinator.ProtocolVersionStardust}},
+			nextIndex:         10,
+		},
+		{
+			name:        "older node without supported versions",
+			activations: []*coordinator.ProtocolActivation{{Version: coordinator.ProtocolVersionStardust}},
+			nextIndex:   10,
+		},
+		{
+			name:              "future version unsupported",
+			supportedVersions: []uint32{2},
+			activations:       []*coordinator.ProtocolActivation{{Version: coordinator.ProtocolVersionStardust}, {Version: nextProtocolVersion, StartIndex: 100}},
+			nextIndex:         10,
+		},
+		{
+			name:              "active version unsupported",
+			supportedVersions: []uint32{2},
+			activations:       []*coordinator.ProtocolActivation{{Version: coordinator.ProtocolVersionStardust}, {Version: nextProtocolVersion, StartIndex: 100}},
+			nextIndex:         100,
+			wantErr:           true,
+		},
+		{
+			name:              "node switches with the activation milestone",
+			supportedVersions: []uint32{2, 3},
+			activations:       []*coordinator.ProtocolActivation{{Version: coordinator.ProtocolVersionStardust}, {Version: nextProtocolVersion, StartIndex: 100}},
+			nextIndex:         100,
+		},
+		{
+			name:              "node did not switch after the activation",
+			supportedVersions: []uint32{2, 3},
+			activations:       []*coordinator.ProtocolActivation{{Version: coordinator.ProtocolVersionStardust}, {Version: nextProtocolVersion, StartIndex: 100}},
+			nextIndex:         101,
+			wantErr:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities := newTestCapabilities(t, &mockINXClient{}, tt.supportedVersions, 0)
+
+			err := capabilities.CheckProtocolVersions(tt.activations, tt.nextIndex)
+			if tt.wantErr {
+				require.ErrorIs(t, err, nodecaps.ErrIncompatibleNode)
+
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckPruning(t *testing.T) {
+	capabilities := newTestCapabilities(t, &mockINXClient{}, []uint32{2}, 100)
+
+	require.NoError(t, capabilities.CheckPruning(0))
+	require.NoError(t, capabilities.CheckPruning(101))
+	require.ErrorIs(t, capabilities.CheckPruning(100), nodecaps.ErrIncompatibleNode)
+
+	// the checks are disabled without capabilities
+	var disabled *nodecaps.Capabilities
+	require.NoError(t, disabled.CheckPruning(100))
+	require.NoError(t, disabled.CheckProtocolVersions([]*coordinator.ProtocolActivation{{Version: 0}}, 1))
+	require.NoError(t, disabled.RequireAPIs(context.Background(), "the coordinator", nodecaps.APIComputeWhiteFlag))
+}
@@ -0,0 +1,87 @@
+package receiptjson
+
+import (
+	"strconv"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// SchemaVersion is the version of the canonical JSON schema of the receipts.
+// It is increased with every change of the schema that is not backwards compatible.
+const SchemaVersion = 1
+
+// Receipt is a receipt in the canonical JSON schema for external systems, e.g. exchanges.
+// Hashes and IDs are hex encoded, addresses are bech32 encoded and all values are decimal strings,
+// so that they can be consumed in any language without reimplementing the protocol serialization
+// and without losing precision in JSON parsers that represent numbers as floats.
+type Receipt struct {
+	// the version of the schema.
+	SchemaVersion int `json:"schemaVersion"`
+	// the index of the milestone that contains the receipt.
+	MilestoneIndex iotago.MilestoneIndex `json:"milestoneIndex"`
+	// the legacy milestone index the funds were migrated at.
+	MigratedAt iotago.MilestoneIndex `json:"migratedAt"`
+	// whether the receipt is the final one for the legacy milestone.
+	Final bool `json:"final"`
+	// the amount of migrated funds entries in the receipt.
+	EntriesCount int `json:"entriesCount"`
+	// the sum of the deposits of all entries.
+	TotalDeposit string `json:"totalDeposit"`
+	// the treasury transaction that funds the migrations (nil = not known).
+	Treasury *Treasury `json:"treasury,omitempty"`
+	// the migrated funds entries in the order of the receipt.
+	Entries []*Entry `json:"entries"`
+}
+
+// Treasury is the treasury transaction of a receipt.
+type Treasury struct {
+	// the ID of the milestone that created the consumed treasury output (hex encoded).
+	InputMilestoneID string `json:"inputMilestoneId"`
+	// the amount of the new treasury output.
+	OutputAmount string `json:"outputAmount"`
+}
+
+// Entry is a migrated funds entry of a receipt.
+type Entry struct {
+	// the index of the entry within the receipt.
+	Index int `json:"index"`
+	// the tail transaction hash of the migration bundle (hex encoded).
+	TailTransactionHash string `json:"tailTransactionHash"`
+	// the target address of the migrated funds (bech32 encoded).
+	Address string `json:"address"`
+	// the amount of migrated funds.
+	Deposit string `json:"deposit"`
+}
+
+// New transforms the receipt contained in the milestone with the given index into the canonical JSON schema.
+func New(index iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt, bech32HRP iotago.NetworkPrefix) *Receipt {
+	canonical := &Receipt{
+		SchemaVersion:  SchemaVersion,
+		MilestoneIndex: index,
+		MigratedAt:     receipt.MigratedAt,
+		Final:          receipt.Final,
+		EntriesCount:   len(receipt.Funds),
+		Entries:        make([]*Entry, 0, len(receipt.Funds)),
+	}
+
+	var total uint64
+	for i, entry := range receipt.Funds {
+		total += entry.Deposit
+		canonical.Entries = append(canonical.Entries, &Entry{
+			Index:               i,
+			TailTransactionHash: iotago.EncodeHex(entry.TailTransactionHash[:]),
+			Address:             entry.Address.Bech32(bech32HRP),
+			Deposit:             strconv.FormatUint(entry.Deposit, 10),
+		})
+	}
+	canonical.TotalDeposit = strconv.FormatUint(total, 10)
+
+	if receipt.Transaction != nil && receipt.Transaction.Input != nil && receipt.Transaction.Output != nil {
+		canonical.Treasury = &Treasury{
+			InputMilestoneID: iotago.EncodeHex(receipt.Transaction.Input[:]),
+			OutputAmount:     strconv.FormatUint(receipt.Transaction.Output.Amount, 10),
+		}
+	}
+
+	return canonical
+}
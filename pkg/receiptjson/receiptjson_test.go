@@ -0,0 +1,55 @@
+package receiptjson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/receiptjson"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestNew(t *testing.T) {
+	address := &iotago.Ed25519Address{1}
+	receipt := &iotago.ReceiptMilestoneOpt{
+		MigratedAt: 42,
+		Final:      true,
+		Funds: iotago.MigratedFundsEntries{
+			{TailTransactionHash: iotago.LegacyTailTransactionHash{1}, Address: address, Deposit: 1_000_000},
+			// values above 2^53 are not representable by JSON parsers that use floats
+			{TailTransactionHash: iotago.LegacyTailTransactionHash{2}, Address: address, Deposit: 9_007_199_254_740_993},
+		},
+		Transaction: &iotago.TreasuryTransaction{
+			Input:  &iotago.TreasuryInput{3},
+			Output: &iotago.TreasuryOutput{Amount: 100},
+		},
+	}
+
+	canonical := receiptjson.New(100, receipt, iotago.PrefixTestnet)
+
+	jsonReceipt, err := json.Marshal(canonical)
+	require.NoError(t, err)
+
+	bech32Address := address.Bech32(iotago.PrefixTestnet)
+	require.JSONEq(t, `{
+		"schemaVersion": 1,
+		"milestoneIndex": 100,
+		"migratedAt": 42,
+		"final": true,
+		"entriesCount": 2,
+		"totalDeposit": "9007199255740993",
+		"treasury": {
+			"inputMilestoneId": "`+iotago.EncodeHex(receipt.Transaction.Input[:])+`",
+			"outputAmount": "100"
+		},
+		"entries": [
+			{"index": 0, "tailTransactionHash": "`+iotago.EncodeHex(receipt.Funds[0].TailTransactionHash[:])+`", "address": "`+bech32Address+`", "deposit": "1000000"},
+			{"index": 1, "tailTransactionHash": "`+iotago.EncodeHex(receipt.Funds[1].TailTransactionHash[:])+`", "address": "`+bech32Address+`", "deposit": "9007199254740993"}
+		]
+	}`, string(jsonReceipt))
+
+	// the treasury transaction is omitted if it is not known
+	receipt.Transaction = nil
+	require.Nil(t, receiptjson.New(100, receipt, iotago.PrefixTestnet).Treasury)
+}
@@ -0,0 +1,332 @@
+package retention
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// CompressedExtension is the extension of archived files that were compressed with gzip.
+	CompressedExtension = ".gz"
+	// the extension of compressed files that are still being written.
+	tmpExtension = ".tmp"
+)
+
+// Policy defines which archived files are kept and whether they are compressed.
+type Policy struct {
+	// MaxFiles is the maximum amount of archived files that are kept, older files are removed (0 = unlimited).
+	MaxFiles int
+	// MaxAge is the maximum age of archived files, older files are removed (0 = unlimited).
+	MaxAge time.Duration
+	// Compress defines whether archived files are compressed with gzip.
+	Compress bool
+	// Companions are the extensions of files that belong to an archive, e.g. its signature.
+	// They are named after the uncompressed archive, are never compressed and are removed together with their archive.
+	Companions []string
+}
+
+// Result contains the amount of archived files that were removed or compressed by a cleanup.
+type Result struct {
+	Removed    int
+	Compressed int
+}
+
+// BackupFilePath returns the path of the n-th backup of the given file, the latest backup is 0.
+// The latest backup is "<filePath>_old", older backups are numbered "<filePath>_old.1", "<filePath>_old.2", ...
+func BackupFilePath(filePath string, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%s_old", filePath)
+	}
+
+	return fmt.Sprintf("%s_old.%d", filePath, n)
+}
+
+// BackupFile moves the file to its latest backup and shifts the existing backups, so that at most keep backups exist.
+// If the file doesn't exist, the existing backups are left untouched.
+func BackupFile(filePath string, keep int) error {
+	if _, err := os.Stat(filePath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if keep < 1 {
+		keep = 1
+	}
+
+	// the oldest backup is overwritten by its successor
+	for n := keep - 1; n > 0; n-- {
+		if err := os.Rename(BackupFilePath(filePath, n-1), BackupFilePath(filePath, n)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("unable to shift backup %d: %w", n-1, err)
+		}
+	}
+
+	return os.Rename(filePath, BackupFilePath(filePath, 0))
+}
+
+// CleanupBackups removes the numbered backups of the given file that exceed keep, e.g. after keep was lowered.
+// The file itself and its latest backup are never removed.
+func CleanupBackups(filePath string, keep int) (int, error) {
+	if keep < 1 {
+		keep = 1
+	}
+
+	prefix := BackupFilePath(filePath, 0) + "."
+	matches, err := filepath.Glob(escapeGlob(prefix) + "*")
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, match := range matches {
+		// only the numbered backups are touched, other files with the same prefix belong to someone else
+		n, err := strconv.Atoi(strings.TrimPrefix(match, prefix))
+		if err != nil || n < keep || match != BackupFilePath(filePath, n) {
+			continue
+		}
+
+		if err := os.Remove(match); err != nil {
+			return removed, fmt.Errorf("unable to remove backup %s: %w", match, err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// escapeGlob escapes the meta characters of filepath.Match in the given path.
+func escapeGlob(path string) string {
+	var escaped strings.Builder
+	for _, c := range path {
+		if strings.ContainsRune(`*?[\`, c) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(c)
+	}
+
+	return escaped.String()
+}
+
+// archive is an archived file in a folder.
+type archive struct {
+	name    string
+	modTime time.Time
+}
+
+// Cleanup applies the policy to the archived files in the given folder whose names match the pattern (see filepath.Match),
+// with or without the compressed extension. The files in exclude, e.g. the file that is currently written, are never touched.
+// Leftovers of compressions that were interrupted, e.g. by a crash, are removed, the original files are still intact in that case.
+func Cleanup(folderPath string, pattern string, policy Policy, exclude ...string) (*Result, error) {
+	result := &Result{}
+
+	entries, err := os.ReadDir(folderPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return result, nil
+		}
+
+		return nil, fmt.Errorf("unable to read folder %s: %w", folderPath, err)
+	}
+
+	excluded := make(map[string]struct{}, len(exclude))
+	for _, filePath := range exclude {
+		excluded[filepath.Base(filePath)] = struct{}{}
+	}
+
+	var archives []*archive
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if _, skip := excluded[name]; skip {
+			continue
+		}
+
+		if isCompanion(name, policy.Companions) {
+			continue
+		}
+
+		if strings.HasSuffix(name, CompressedExtension+tmpExtension) {
+			if matched, _ := filepath.Match(pattern, strings.TrimSuffix(name, CompressedExtension+tmpExtension)); matched {
+				if err := os.Remove(filepath.Join(folderPath, name)); err != nil {
+					return nil, fmt.Errorf("unable to remove incomplete archive %s: %w", name, err)
+				}
+			}
+
+			continue
+		}
+
+		matched, err := filepath.Match(pattern, strings.TrimSuffix(name, CompressedExtension))
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read archive %s: %w", name, err)
+		}
+		archives = append(archives, &archive{name: name, modTime: info.ModTime()})
+	}
+
+	// the newest archives are kept
+	sort.Slice(archives, func(i, j int) bool {
+		if !archives[i].modTime.Equal(archives[j].modTime) {
+			return archives[i].modTime.After(archives[j].modTime)
+		}
+
+		return archives[i].name > archives[j].name
+	})
+
+	now := time.Now()
+	for i, a := range archives {
+		filePath := filepath.Join(folderPath, a.name)
+
+		if (policy.MaxFiles > 0 && i >= policy.MaxFiles) || (policy.MaxAge > 0 && now.Sub(a.modTime) > policy.MaxAge) {
+			if err := os.Remove(filePath); err != nil {
+				return result, fmt.Errorf("unable to remove archive %s: %w", a.name, err)
+			}
+			result.Removed++
+
+			for _, extension := range policy.Companions {
+				companionFilePath := strings.TrimSuffix(filePath, CompressedExtension) + extension
+				if err := os.Remove(companionFilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return result, fmt.Errorf("unable to remove %s: %w", companionFilePath, err)
+				}
+			}
+
+			continue
+		}
+
+		if policy.Compress && !strings.HasSuffix(a.name, CompressedExtension) {
+			if err := Compress(filePath); err != nil {
+				return result, err
+			}
+			result.Compressed++
+		}
+	}
+
+	return result, nil
+}
+
+// isCompanion checks whether the file belongs to an archive.
+func isCompanion(name string, companions []string) bool {
+	for _, extension := range companions {
+		if strings.HasSuffix(name, extension) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Compress replaces the file with a gzip compressed copy that has the compressed extension and the same modification time.
+// The original file is only removed after the compressed copy was written completely.
+func Compress(filePath string) (err error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+
+	//nolint:gosec // the path is defined by the operator
+	source, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+	defer func() { _ = source.Close() }()
+
+	compressedFilePath := filePath + CompressedExtension
+	tmpFilePath := compressedFilePath + tmpExtension
+
+	//nolint:gosec // the path is defined by the operator
+	target, err := os.OpenFile(tmpFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = target.Close()
+			_ = os.Remove(tmpFilePath)
+		}
+	}()
+
+	writer := gzip.NewWriter(target)
+	if _, err = io.Copy(writer, source); err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+	if err = target.Sync(); err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+	if err = target.Close(); err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+
+	// the age of the archive is taken from the modification time, so it must not change by compressing it
+	if err = os.Chtimes(tmpFilePath, info.ModTime(), info.ModTime()); err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+	if err = os.Rename(tmpFilePath, compressedFilePath); err != nil {
+		return fmt.Errorf("unable to compress %s: %w", filePath, err)
+	}
+
+	if err := os.Remove(filePath); err != nil {
+		return fmt.Errorf("unable to remove compressed file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Open opens the file for reading and decompresses it transparently if it has the compressed extension.
+func Open(filePath string) (io.ReadCloser, error) {
+	//nolint:gosec // the path is defined by the operator
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(filePath, CompressedExtension) {
+		return file, nil
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+
+	return &compressedFile{Reader: reader, file: file}, nil
+}
+
+// compressedFile closes both the decompressing reader and the underlying file.
+type compressedFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (f *compressedFile) Close() error {
+	if err := f.Reader.Close(); err != nil {
+		_ = f.file.Close()
+
+		return err
+	}
+
+	return f.file.Close()
+}
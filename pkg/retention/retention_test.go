@@ -0,0 +1,129 @@
+package retention_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+)
+
+func writeTestFile(t *testing.T, filePath string, content string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0o600))
+	require.NoError(t, os.Chtimes(filePath, modTime, modTime))
+}
+
+func readTestFile(t *testing.T, filePath string) string {
+	file, err := retention.Open(filePath)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, file.Close()) }()
+
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+func TestBackupFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "coordinator.state")
+
+	// a missing file doesn't touch the existing backups
+	require.NoError(t, retention.BackupFile(filePath, 3))
+
+	for _, content := range []string{"1", "2", "3", "4"} {
+		writeTestFile(t, filePath, content, time.Now())
+		require.NoError(t, retention.BackupFile(filePath, 3))
+		require.NoFileExists(t, filePath)
+	}
+
+	require.Equal(t, "4", readTestFile(t, retention.BackupFilePath(filePath, 0)))
+	require.Equal(t, "3", readTestFile(t, retention.BackupFilePath(filePath, 1)))
+	require.Equal(t, "2", readTestFile(t, retention.BackupFilePath(filePath, 2)))
+	require.NoFileExists(t, retention.BackupFilePath(filePath, 3))
+
+	require.NoError(t, retention.BackupFile(filePath, 3))
+	require.Equal(t, "4", readTestFile(t, retention.BackupFilePath(filePath, 0)))
+
+	// a single backup keeps the behavior of the plain "_old" file
+	writeTestFile(t, filePath, "5", time.Now())
+	require.NoError(t, retention.BackupFile(filePath, 1))
+	require.Equal(t, "5", readTestFile(t, filePath+"_old"))
+	require.Equal(t, "3", readTestFile(t, retention.BackupFilePath(filePath, 1)))
+}
+
+func TestCleanupBackups(t *testing.T) {
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "coordinator.state")
+
+	writeTestFile(t, filePath, "current", time.Now())
+	for n := 0; n < 4; n++ {
+		writeTestFile(t, retention.BackupFilePath(filePath, n), "backup", time.Now())
+	}
+	// files that only share the prefix are not touched
+	writeTestFile(t, filePath+"_old.bak", "other", time.Now())
+	writeTestFile(t, filePath+"_old.01", "other", time.Now())
+
+	removed, err := retention.CleanupBackups(filePath, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, removed)
+
+	require.FileExists(t, filePath)
+	require.FileExists(t, retention.BackupFilePath(filePath, 0))
+	require.FileExists(t, retention.BackupFilePath(filePath, 1))
+	require.NoFileExists(t, retention.BackupFilePath(filePath, 2))
+	require.NoFileExists(t, retention.BackupFilePath(filePath, 3))
+	require.FileExists(t, filePath+"_old.bak")
+	require.FileExists(t, filePath+"_old.01")
+}
+
+func TestCleanup(t *testing.T) {
+	folderPath := t.TempDir()
+	now := time.Now()
+
+	writeTestFile(t, filepath.Join(folderPath, "trace_1.jsonl"), "1", now.Add(-4*time.Hour))
+	writeTestFile(t, filepath.Join(folderPath, "trace_2.jsonl"), "2", now.Add(-3*time.Hour))
+	writeTestFile(t, filepath.Join(folderPath, "trace_3.jsonl"), "3", now.Add(-2*time.Hour))
+	writeTestFile(t, filepath.Join(folderPath, "trace_4.jsonl"), "4", now.Add(-time.Hour))
+	writeTestFile(t, filepath.Join(folderPath, "trace_5.jsonl"), "5", now)
+	writeTestFile(t, filepath.Join(folderPath, "other.jsonl"), "other", now.Add(-4*time.Hour))
+	// the leftover of an interrupted compression
+	writeTestFile(t, filepath.Join(folderPath, "trace_4.jsonl.gz.tmp"), "incomplete", now)
+
+	// the current file is neither counted nor compressed
+	result, err := retention.Cleanup(folderPath, "trace_*.jsonl", retention.Policy{
+		MaxFiles: 3,
+		MaxAge:   150 * time.Minute,
+		Compress: true,
+	}, filepath.Join(folderPath, "trace_5.jsonl"))
+	require.NoError(t, err)
+	require.Equal(t, &retention.Result{Removed: 2, Compressed: 2}, result)
+
+	require.NoFileExists(t, filepath.Join(folderPath, "trace_1.jsonl"))
+	require.NoFileExists(t, filepath.Join(folderPath, "trace_2.jsonl"))
+	require.NoFileExists(t, filepath.Join(folderPath, "trace_3.jsonl"))
+	require.NoFileExists(t, filepath.Join(folderPath, "trace_4.jsonl.gz.tmp"))
+	require.FileExists(t, filepath.Join(folderPath, "trace_5.jsonl"))
+	require.FileExists(t, filepath.Join(folderPath, "other.jsonl"))
+	require.Equal(t, "3", readTestFile(t, filepath.Join(folderPath, "trace_3.jsonl.gz")))
+	require.Equal(t, "4", readTestFile(t, filepath.Join(folderPath, "trace_4.jsonl.gz")))
+
+	// the compressed archives keep their age
+	info, err := os.Stat(filepath.Join(folderPath, "trace_3.jsonl.gz"))
+	require.NoError(t, err)
+	require.WithinDuration(t, now.Add(-2*time.Hour), info.ModTime(), time.Second)
+
+	result, err = retention.Cleanup(folderPath, "trace_*.jsonl", retention.Policy{MaxFiles: 1}, filepath.Join(folderPath, "trace_5.jsonl"))
+	require.NoError(t, err)
+	require.Equal(t, &retention.Result{Removed: 1}, result)
+	require.NoFileExists(t, filepath.Join(folderPath, "trace_3.jsonl.gz"))
+	require.FileExists(t, filepath.Join(folderPath, "trace_4.jsonl.gz"))
+
+	// a missing folder has nothing to clean up
+	result, err = retention.Cleanup(filepath.Join(folderPath, "missing"), "*", retention.Policy{MaxFiles: 1})
+	require.NoError(t, err)
+	require.Equal(t, &retention.Result{}, result)
+}
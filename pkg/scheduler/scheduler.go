@@ -0,0 +1,433 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/syncutils"
+)
+
+// Priority defines the order queued tasks are run in.
+type Priority int
+
+const (
+	// PriorityHigh tasks are run before all other queued tasks.
+	PriorityHigh Priority = iota
+	// PriorityNormal tasks are run before low priority tasks.
+	PriorityNormal
+	// PriorityLow tasks are only run if no other tasks are queued, or if they waited longer than the starvation timeout.
+	PriorityLow
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityNormal:
+		return "normal"
+	case PriorityLow:
+		return "low"
+	default:
+		return fmt.Sprintf("priority(%d)", int(p))
+	}
+}
+
+const (
+	defaultWorkers           = 1
+	defaultStarvationTimeout = time.Minute
+)
+
+var (
+	// ErrTaskAlreadyRegistered is returned when a task with the same name was already registered.
+	ErrTaskAlreadyRegistered = errors.New("task already registered")
+	// ErrTaskNotFound is returned when a task is submitted that was not registered.
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrTaskPending is returned when a task is submitted that is already queued.
+	ErrTaskPending = errors.New("task already queued")
+	// ErrSchedulerStopped is returned when a task is submitted after the scheduler was stopped.
+	ErrSchedulerStopped = errors.New("scheduler stopped")
+)
+
+// TaskFunc is the work of a task, the context is canceled when the scheduler is stopped.
+type TaskFunc func(ctx context.Context) error
+
+// TaskRun contains the metrics of a single run of a task.
+type TaskRun struct {
+	// the name of the task.
+	Task string
+	// the priority of the task.
+	Priority Priority
+	// the duration the task was queued before it was run.
+	Wait time.Duration
+	// the duration the task was running.
+	Runtime time.Duration
+	// the error returned by the task (nil = success).
+	Err error
+}
+
+// TaskRunCaller is an event caller which gets a task run passed.
+func TaskRunCaller(handler interface{}, params ...interface{}) {
+	//nolint:forcetypeassert // we will replace that with generic events anyway
+	handler.(func(*TaskRun))(params[0].(*TaskRun))
+}
+
+// Events are the events issued by the scheduler.
+type Events struct {
+	// TaskFinished is triggered after a task was run.
+	TaskFinished *events.Event
+}
+
+// TaskStats contains the runtime metrics of a task.
+type TaskStats struct {
+	// the name of the task.
+	Name string
+	// the priority of the task.
+	Priority Priority
+	// the interval the task is queued in (0 = only when submitted).
+	Interval time.Duration
+	// the amount of runs of the task.
+	Runs uint64
+	// the amount of runs that returned an error.
+	Failures uint64
+	// the amount of times the task was not queued, because it was still queued.
+	Skipped uint64
+	// the cumulative duration the task was running.
+	TotalRuntime time.Duration
+	// the cumulative duration the task was queued before it was run.
+	TotalWait time.Duration
+	// the latest run of the task (nil = none yet).
+	LastRun *TaskRun
+	// the time the latest run of the task finished.
+	LastRunTime time.Time
+}
+
+type task struct {
+	stats TaskStats
+	fn    TaskFunc
+	// the time the task was queued (zero = not queued).
+	queuedAt time.Time
+	// whether the task is running.
+	running bool
+}
+
+// Scheduler runs background tasks, e.g. self-tests and the cleanup of backups, ordered by their priority.
+// Tasks that waited longer than the starvation timeout are run first, so that low priority tasks are never starved.
+// While the scheduler is held, e.g. while a milestone is issued, no tasks are started, so that background work
+// never delays the milestone issuance. Tasks that are already running are not interrupted.
+type Scheduler struct {
+	Events *Events
+
+	lock syncutils.Mutex
+
+	// the amount of tasks that are run at the same time.
+	workers int
+	// the duration after which a queued task is run before tasks with a higher priority.
+	starvationTimeout time.Duration
+	// used to get the current time.
+	clock func() time.Time
+
+	tasks map[string]*task
+	// the names of the queued tasks in the order they were queued.
+	queue []string
+	// the amount of running tasks.
+	running int
+	// the amount of holds that prevent tasks from being started.
+	holds int
+	// whether the scheduler was stopped.
+	stopped bool
+	// signals the dispatcher that tasks were queued, finished or released.
+	signal chan struct{}
+}
+
+// WithWorkers defines the amount of tasks that are run at the same time.
+func WithWorkers(workers int) options.Option[Scheduler] {
+	return func(s *Scheduler) {
+		s.workers = workers
+	}
+}
+
+// WithStarvationTimeout defines the duration after which a queued task is run before tasks with a higher priority.
+func WithStarvationTimeout(starvationTimeout time.Duration) options.Option[Scheduler] {
+	return func(s *Scheduler) {
+		s.starvationTimeout = starvationTimeout
+	}
+}
+
+// WithClock defines the clock used to measure the waiting and running tasks.
+func WithClock(clock func() time.Time) options.Option[Scheduler] {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// New creates a new Scheduler.
+func New(opts ...options.Option[Scheduler]) *Scheduler {
+	s := options.Apply(&Scheduler{
+		Events: &Events{
+			TaskFinished: events.NewEvent(TaskRunCaller),
+		},
+		workers:           defaultWorkers,
+		starvationTimeout: defaultStarvationTimeout,
+		clock:             time.Now,
+		tasks:             make(map[string]*task),
+		signal:            make(chan struct{}, 1),
+	}, opts)
+
+	if s.workers < 1 {
+		s.workers = 1
+	}
+
+	return s
+}
+
+// Register registers a task with the given priority.
+// If the interval is greater than zero, the task is queued in that interval while the scheduler runs,
+// otherwise it is only queued when it is submitted.
+func (s *Scheduler) Register(name string, priority Priority, interval time.Duration, fn TaskFunc) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.tasks[name]; exists {
+		return fmt.Errorf("%w: %s", ErrTaskAlreadyRegistered, name)
+	}
+
+	s.tasks[name] = &task{
+		stats: TaskStats{
+			Name:     name,
+			Priority: priority,
+			Interval: interval,
+		},
+		fn: fn,
+	}
+
+	return nil
+}
+
+// Submit queues the registered task with the given name.
+// It returns ErrTaskPending if the task is still queued, a running task is queued again.
+func (s *Scheduler) Submit(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t, exists := s.tasks[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+
+	if s.stopped {
+		return ErrSchedulerStopped
+	}
+
+	if !t.queuedAt.IsZero() {
+		t.stats.Skipped++
+
+		return fmt.Errorf("%w: %s", ErrTaskPending, name)
+	}
+
+	t.queuedAt = s.clock()
+	s.queue = append(s.queue, name)
+	s.notify()
+
+	return nil
+}
+
+// Hold prevents tasks from being started until the returned release function is called.
+// Calling the release function more than once has no effect.
+func (s *Scheduler) Hold() func() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.holds++
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			s.lock.Lock()
+			defer s.lock.Unlock()
+
+			s.holds--
+			s.notify()
+		})
+	}
+}
+
+// Stats returns the runtime metrics of all registered tasks ordered by their name.
+func (s *Scheduler) Stats() []*TaskStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := make([]*TaskStats, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		taskStats := t.stats
+		stats = append(stats, &taskStats)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
+
+// Run runs the queued tasks and queues the tasks with an interval until the given context is done.
+// Afterwards it waits until the running tasks are finished, the queued tasks are not run anymore.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	s.lock.Lock()
+	for name, t := range s.tasks {
+		if t.stats.Interval <= 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, interval time.Duration) {
+			defer wg.Done()
+			s.queueEvery(ctx, name, interval)
+		}(name, t.stats.Interval)
+	}
+	s.lock.Unlock()
+
+	for {
+		s.dispatch(ctx, &wg)
+
+		select {
+		case <-s.signal:
+		case <-ctx.Done():
+			s.lock.Lock()
+			s.stopped = true
+			s.lock.Unlock()
+
+			wg.Wait()
+
+			return
+		}
+	}
+}
+
+// queueEvery submits the task in the given interval until the context is done.
+func (s *Scheduler) queueEvery(ctx context.Context, name string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// a task that is still queued is skipped, it is counted in the stats
+			_ = s.Submit(name)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatch starts the queued tasks as long as workers are free and the scheduler is not held.
+func (s *Scheduler) dispatch(ctx context.Context, wg *sync.WaitGroup) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for !s.stopped && s.holds == 0 && s.running < s.workers {
+		t := s.next()
+		if t == nil {
+			// no queued task or all queued tasks are still running
+			return
+		}
+
+		now := s.clock()
+		wait := now.Sub(t.queuedAt)
+		t.queuedAt = time.Time{}
+		t.running = true
+		s.running++
+
+		wg.Add(1)
+		go func(t *task) {
+			defer wg.Done()
+			s.runTask(ctx, t, wait)
+		}(t)
+	}
+}
+
+// next removes the task from the queue that is run next, the caller must hold the lock.
+// Tasks that waited longer than the starvation timeout are run first, otherwise the task with the highest priority.
+// Tasks with the same priority are run in the order they were queued, a task is never run twice at the same time.
+func (s *Scheduler) next() *task {
+	now := s.clock()
+
+	selected := -1
+	for i, name := range s.queue {
+		t := s.tasks[name]
+		if t.running {
+			continue
+		}
+
+		if selected == -1 || s.runsBefore(t, s.tasks[s.queue[selected]], now) {
+			selected = i
+		}
+	}
+
+	if selected == -1 {
+		return nil
+	}
+
+	t := s.tasks[s.queue[selected]]
+	s.queue = append(s.queue[:selected], s.queue[selected+1:]...)
+
+	return t
+}
+
+// runsBefore checks whether the queued task a is run before the task b that was queued earlier.
+func (s *Scheduler) runsBefore(a *task, b *task, now time.Time) bool {
+	if s.starvationTimeout > 0 && now.Sub(b.queuedAt) >= s.starvationTimeout {
+		// the earlier task is starving
+		return false
+	}
+
+	return a.stats.Priority < b.stats.Priority
+}
+
+// runTask runs the task and records its metrics.
+func (s *Scheduler) runTask(ctx context.Context, t *task, wait time.Duration) {
+	start := s.clock()
+	err := t.fn(ctx)
+	duration := s.clock().Sub(start)
+
+	run := &TaskRun{
+		Task:     t.stats.Name,
+		Priority: t.stats.Priority,
+		Wait:     wait,
+		Runtime:  duration,
+		Err:      err,
+	}
+
+	s.lock.Lock()
+	t.running = false
+	s.running--
+	t.stats.Runs++
+	if err != nil {
+		t.stats.Failures++
+	}
+	t.stats.TotalRuntime += duration
+	t.stats.TotalWait += wait
+	t.stats.LastRun = run
+	t.stats.LastRunTime = start.Add(duration)
+	s.notify()
+	s.lock.Unlock()
+
+	s.Events.TaskFinished.Trigger(run)
+}
+
+// notify wakes up the dispatcher, the caller must hold the lock.
+func (s *Scheduler) notify() {
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
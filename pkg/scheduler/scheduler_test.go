@@ -0,0 +1,173 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/scheduler"
+)
+
+// recorder records the order the tasks were run in.
+type recorder struct {
+	lock sync.Mutex
+	runs []string
+	done chan string
+}
+
+func newRecorder() *recorder {
+	return &recorder{done: make(chan string, 10)}
+}
+
+func (r *recorder) task(name string, err error) scheduler.TaskFunc {
+	return func(_ context.Context) error {
+		r.lock.Lock()
+		r.runs = append(r.runs, name)
+		r.lock.Unlock()
+
+		select {
+		case r.done <- name:
+		default:
+		}
+
+		return err
+	}
+}
+
+func (r *recorder) wait(t *testing.T, count int) []string {
+	for i := 0; i < count; i++ {
+		select {
+		case <-r.done:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "task was not run")
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]string{}, r.runs...)
+}
+
+func runScheduler(s *scheduler.Scheduler) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.Run(ctx)
+	}()
+
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+func TestSchedulerPriorities(t *testing.T) {
+	s := scheduler.New()
+	r := newRecorder()
+
+	require.NoError(t, s.Register("low", scheduler.PriorityLow, 0, r.task("low", nil)))
+	require.NoError(t, s.Register("normal", scheduler.PriorityNormal, 0, r.task("normal", nil)))
+	require.NoError(t, s.Register("high", scheduler.PriorityHigh, 0, r.task("high", nil)))
+	require.ErrorIs(t, s.Register("high", scheduler.PriorityHigh, 0, r.task("high", nil)), scheduler.ErrTaskAlreadyRegistered)
+	require.ErrorIs(t, s.Submit("unknown"), scheduler.ErrTaskNotFound)
+
+	// the tasks are queued while the scheduler is held, so they are run ordered by their priority
+	release := s.Hold()
+	require.NoError(t, s.Submit("low"))
+	require.NoError(t, s.Submit("normal"))
+	require.NoError(t, s.Submit("high"))
+	require.ErrorIs(t, s.Submit("high"), scheduler.ErrTaskPending)
+
+	stop := runScheduler(s)
+	defer stop()
+
+	// no task is started while the scheduler is held
+	time.Sleep(20 * time.Millisecond)
+	require.Empty(t, r.wait(t, 0))
+
+	release()
+	require.Equal(t, []string{"high", "normal", "low"}, r.wait(t, 3))
+
+	stats := s.Stats()
+	require.Len(t, stats, 3)
+	require.Equal(t, "high", stats[0].Name)
+	require.EqualValues(t, 1, stats[0].Runs)
+	require.EqualValues(t, 1, stats[0].Skipped)
+}
+
+func TestSchedulerStarvation(t *testing.T) {
+	now := time.Now()
+	var clockLock sync.Mutex
+	clock := func() time.Time {
+		clockLock.Lock()
+		defer clockLock.Unlock()
+
+		return now
+	}
+
+	s := scheduler.New(scheduler.WithStarvationTimeout(time.Minute), scheduler.WithClock(clock))
+	r := newRecorder()
+
+	require.NoError(t, s.Register("low", scheduler.PriorityLow, 0, r.task("low", nil)))
+	require.NoError(t, s.Register("high", scheduler.PriorityHigh, 0, r.task("high", nil)))
+
+	release := s.Hold()
+	require.NoError(t, s.Submit("low"))
+
+	// the low priority task waited longer than the starvation timeout, so it runs before the high priority task
+	clockLock.Lock()
+	now = now.Add(2 * time.Minute)
+	clockLock.Unlock()
+	require.NoError(t, s.Submit("high"))
+
+	stop := runScheduler(s)
+	defer stop()
+
+	release()
+	require.Equal(t, []string{"low", "high"}, r.wait(t, 2))
+
+	stats := s.Stats()
+	require.Equal(t, "low", stats[1].Name)
+	require.Equal(t, 2*time.Minute, stats[1].TotalWait)
+}
+
+func TestSchedulerMetrics(t *testing.T) {
+	s := scheduler.New()
+	r := newRecorder()
+	errTask := errors.New("task failed")
+
+	require.NoError(t, s.Register("failing", scheduler.PriorityNormal, 10*time.Millisecond, r.task("failing", errTask)))
+
+	var lock sync.Mutex
+	var finished []*scheduler.TaskRun
+	s.Events.TaskFinished.Hook(events.NewClosure(func(run *scheduler.TaskRun) {
+		lock.Lock()
+		defer lock.Unlock()
+		finished = append(finished, run)
+	}))
+
+	// tasks with an interval are queued while the scheduler runs
+	stop := runScheduler(s)
+	r.wait(t, 2)
+	stop()
+
+	require.ErrorIs(t, s.Submit("failing"), scheduler.ErrSchedulerStopped)
+
+	stats := s.Stats()
+	require.Len(t, stats, 1)
+	require.GreaterOrEqual(t, stats[0].Runs, uint64(2))
+	require.Equal(t, stats[0].Runs, stats[0].Failures)
+	require.ErrorIs(t, stats[0].LastRun.Err, errTask)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(t, finished, int(stats[0].Runs))
+	require.Equal(t, "failing", finished[0].Task)
+}
@@ -0,0 +1,221 @@
+package shutdownreason
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+)
+
+const (
+	// ComponentCoordinator is the component that issues the milestones.
+	ComponentCoordinator = "coordinator"
+	// ComponentMigrator is the component that creates the receipts.
+	ComponentMigrator = "migrator"
+)
+
+// Action tells the operator what has to be done before the coordinator is restarted.
+type Action string
+
+const (
+	// ActionRestart means the cause was transient, the coordinator can be restarted right away.
+	ActionRestart Action = "restart"
+	// ActionInvestigate means the state is consistent, but the cause, e.g. an unreachable signer or node,
+	// has to be fixed first, otherwise the coordinator terminates again.
+	ActionInvestigate Action = "investigate"
+	// ActionManualIntervention means the state can't be continued as it is,
+	// restarting without repairing it risks invalid milestones or receipts.
+	ActionManualIntervention Action = "manualIntervention"
+)
+
+// Code identifies the cause of a shutdown.
+type Code string
+
+const (
+	// CodeCoordinatorCritical is an unclassified critical error of the coordinator.
+	CodeCoordinatorCritical Code = "coordinator.critical"
+	// CodeCoordinatorSigner is a failure of the signers of the milestones or the receipts.
+	CodeCoordinatorSigner Code = "coordinator.signerFailure"
+	// CodeCoordinatorSubmission is a failure to submit a milestone to the node.
+	CodeCoordinatorSubmission Code = "coordinator.submissionFailure"
+	// CodeCoordinatorConfirmationMismatch is a milestone that was confirmed by the node, but differs from the issued one.
+	CodeCoordinatorConfirmationMismatch Code = "coordinator.confirmationMismatch"
+	// CodeCoordinatorInvalidState is a coordinator state or database that can't be continued.
+	CodeCoordinatorInvalidState Code = "coordinator.invalidState"
+	// CodeCoordinatorTreasury is a treasury output that doesn't match the receipts.
+	CodeCoordinatorTreasury Code = "coordinator.treasuryMismatch"
+	// CodeCoordinatorClock is a system clock that jumped backwards.
+	CodeCoordinatorClock Code = "coordinator.clock"
+	// CodeMigratorCritical is an unclassified critical error of the migrator.
+	CodeMigratorCritical Code = "migrator.critical"
+	// CodeMigratorLegacyNode is an error of the legacy node the migrations are queried from.
+	CodeMigratorLegacyNode Code = "migrator.legacyNode"
+	// CodeMigratorInvalidMigrations are migrations that failed the sanity checks.
+	CodeMigratorInvalidMigrations Code = "migrator.invalidMigrations"
+	// CodeMigratorInvalidState is a migrator state that can't be continued.
+	CodeMigratorInvalidState Code = "migrator.invalidState"
+	// CodeMigratorStopped is a migrator service that stopped without an error.
+	CodeMigratorStopped Code = "migrator.stopped"
+)
+
+// Reason is the structured reason of a shutdown the coordinator decided on.
+type Reason struct {
+	// the code of the cause of the shutdown.
+	Code Code `json:"code"`
+	// what has to be done before the coordinator is restarted.
+	Action Action `json:"action"`
+	// the component that decided to shut down.
+	Component string `json:"component"`
+	// the error that caused the shutdown.
+	Message string `json:"message"`
+	// the unix time of the shutdown.
+	Timestamp int64 `json:"timestamp"`
+}
+
+func (r *Reason) String() string {
+	return fmt.Sprintf("%s (action: %s): %s", r.Code, r.Action, r.Message)
+}
+
+// rule maps the errors that match any of the given errors to a code.
+type rule struct {
+	errs   []error
+	code   Code
+	action Action
+}
+
+var (
+	// the rules are checked in order, the first matching rule wins.
+	coordinatorRules = []rule{
+		{[]error{coordinator.ErrConfirmedMilestoneMismatch}, CodeCoordinatorConfirmationMismatch, ActionManualIntervention},
+		{[]error{coordinator.ErrTreasuryInconsistent, coordinator.ErrTreasuryInsufficient}, CodeCoordinatorTreasury, ActionManualIntervention},
+		{[]error{coordinator.ErrInvalidNetworkReset, coordinator.ErrMigrationSummaryInvalid, coordinator.ErrReceiptProofInvalid}, CodeCoordinatorInvalidState, ActionManualIntervention},
+		{[]error{&coordinator.SigningError{}, coordinator.ErrSignerKeyNotValid, coordinator.ErrSignerResponseInvalid, coordinator.ErrSignerCommitteeMemberMissing, coordinator.ErrSigningSelfTestFailed, coordinator.ErrInvalidTreasurySignature}, CodeCoordinatorSigner, ActionInvestigate},
+		{[]error{&coordinator.SubmissionError{}}, CodeCoordinatorSubmission, ActionInvestigate},
+		{[]error{coordinator.ErrClockJumpedBackwards}, CodeCoordinatorClock, ActionInvestigate},
+	}
+
+	migratorRules = []rule{
+		{[]error{migrator.ErrInvalidState, migrator.ErrIncludedHashesMismatch, migrator.ErrInvalidStateUpdate, &migrator.StateError{Stage: migrator.StageInitState}, &migrator.StateError{Stage: migrator.StageUpdateState}}, CodeMigratorInvalidState, ActionManualIntervention},
+		{[]error{migrator.ErrInvalidMigrations, migrator.ErrTailTransactionHashIncluded, migrator.ErrInvalidStopIndex, migrator.ErrReceiptTooLarge}, CodeMigratorInvalidMigrations, ActionManualIntervention},
+	}
+)
+
+// Classify returns the reason of a shutdown of the given component that was caused by the given error.
+// A nil error is classified as a component that stopped unexpectedly.
+func Classify(component string, err error) *Reason {
+	reason := &Reason{
+		Component: component,
+		Timestamp: time.Now().Unix(),
+	}
+	if err != nil {
+		reason.Message = err.Error()
+	}
+
+	switch component {
+	case ComponentMigrator:
+		switch {
+		case err == nil:
+			reason.Code, reason.Action, reason.Message = CodeMigratorStopped, ActionInvestigate, "migrator service stopped unexpectedly"
+		case applyRules(reason, migratorRules, err):
+		case migrator.ErrorClass(err) == migrator.ErrorClassNetwork:
+			// the legacy node may be back after a restart, the state was not touched
+			reason.Code, reason.Action = CodeMigratorLegacyNode, ActionRestart
+		default:
+			reason.Code, reason.Action = CodeMigratorCritical, ActionInvestigate
+		}
+
+	default:
+		if !applyRules(reason, coordinatorRules, err) {
+			reason.Code, reason.Action = CodeCoordinatorCritical, ActionInvestigate
+		}
+	}
+
+	return reason
+}
+
+// applyRules sets the code and the action of the first rule that matches the error, it returns false if no rule matches.
+func applyRules(reason *Reason, rules []rule, err error) bool {
+	for _, r := range rules {
+		for _, ruleErr := range r.errs {
+			if errors.Is(err, ruleErr) {
+				reason.Code, reason.Action = r.code, r.action
+
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Reporter reports the reasons of the shutdowns to the log and to a file, so that the operator of the node
+// and tools that supervise the coordinator know whether it is safe to restart it.
+type Reporter struct {
+	*logger.WrappedLogger
+
+	// the path to the file the reason is written to (empty = the reason is only logged).
+	filePath string
+}
+
+// NewReporter creates a new Reporter that writes the reasons to the given file.
+func NewReporter(log *logger.Logger, filePath string) *Reporter {
+	return &Reporter{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		filePath:      filePath,
+	}
+}
+
+// Report classifies the error that causes the shutdown of the given component, logs the reason and writes it to the file.
+func (r *Reporter) Report(component string, err error) *Reason {
+	reason := Classify(component, err)
+
+	r.Logger().Errorw("Shutdown reason",
+		"code", reason.Code,
+		"action", reason.Action,
+		"component", reason.Component,
+		"message", reason.Message,
+	)
+
+	if r.filePath != "" {
+		if err := ioutils.WriteJSONToFile(r.filePath, reason, 0660); err != nil {
+			r.LogWarnf("unable to write shutdown reason: %s", err)
+		}
+	}
+
+	return reason
+}
+
+// TakePrevious returns the reason the previous run shut down with and removes the file,
+// so that a reason is only reported once. It returns nil if the previous run didn't decide to shut down.
+func (r *Reporter) TakePrevious() (*Reason, error) {
+	if r.filePath == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to read shutdown reason: %w", err)
+	}
+
+	reason := &Reason{}
+	if err := json.Unmarshal(data, reason); err != nil {
+		return nil, fmt.Errorf("unable to decode shutdown reason: %w", err)
+	}
+
+	if err := os.Remove(r.filePath); err != nil {
+		return nil, fmt.Errorf("unable to remove shutdown reason: %w", err)
+	}
+
+	return reason, nil
+}
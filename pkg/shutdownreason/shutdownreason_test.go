@@ -0,0 +1,114 @@
+package shutdownreason_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/hornet/v2/pkg/common"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/shutdownreason"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		component string
+		err       error
+		code      shutdownreason.Code
+		action    shutdownreason.Action
+	}{
+		{
+			name:      "signer failure",
+			component: shutdownreason.ComponentCoordinator,
+			err:       common.CriticalError(&coordinator.SigningError{Index: 5, Stage: "signing", Err: errors.New("connection refused")}),
+			code:      shutdownreason.CodeCoordinatorSigner,
+			action:    shutdownreason.ActionInvestigate,
+		},
+		{
+			name:      "confirmed milestone mismatch",
+			component: shutdownreason.ComponentCoordinator,
+			err:       common.CriticalError(fmt.Errorf("%w: milestone 5", coordinator.ErrConfirmedMilestoneMismatch)),
+			code:      shutdownreason.CodeCoordinatorConfirmationMismatch,
+			action:    shutdownreason.ActionManualIntervention,
+		},
+		{
+			name:      "unclassified coordinator error",
+			component: shutdownreason.ComponentCoordinator,
+			err:       common.CriticalError(errors.New("unknown")),
+			code:      shutdownreason.CodeCoordinatorCritical,
+			action:    shutdownreason.ActionInvestigate,
+		},
+		{
+			name:      "legacy node unreachable",
+			component: shutdownreason.ComponentMigrator,
+			err:       &migrator.QueryError{Index: 5, Stage: migrator.StageFetch, Err: errors.New("connection refused")},
+			code:      shutdownreason.CodeMigratorLegacyNode,
+			action:    shutdownreason.ActionRestart,
+		},
+		{
+			name:      "invalid migrations",
+			component: shutdownreason.ComponentMigrator,
+			err:       common.CriticalError(fmt.Errorf("%w: duplicate tail transaction hash", migrator.ErrInvalidMigrations)),
+			code:      shutdownreason.CodeMigratorInvalidMigrations,
+			action:    shutdownreason.ActionManualIntervention,
+		},
+		{
+			name:      "invalid migrator state",
+			component: shutdownreason.ComponentMigrator,
+			err:       &migrator.StateError{Index: 5, Stage: migrator.StageInitState, Err: migrator.ErrIncludedHashesMismatch},
+			code:      shutdownreason.CodeMigratorInvalidState,
+			action:    shutdownreason.ActionManualIntervention,
+		},
+		{
+			name:      "failed to persist the migrator state",
+			component: shutdownreason.ComponentMigrator,
+			err:       common.CriticalError(&migrator.StateError{Index: 5, Stage: migrator.StagePersistState, Err: errors.New("no space left on device")}),
+			code:      shutdownreason.CodeMigratorCritical,
+			action:    shutdownreason.ActionInvestigate,
+		},
+		{
+			name:      "migrator stopped",
+			component: shutdownreason.ComponentMigrator,
+			code:      shutdownreason.CodeMigratorStopped,
+			action:    shutdownreason.ActionInvestigate,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reason := shutdownreason.Classify(test.component, test.err)
+			require.Equal(t, test.code, reason.Code)
+			require.Equal(t, test.action, reason.Action)
+			require.Equal(t, test.component, reason.Component)
+			require.NotEmpty(t, reason.Message)
+			require.NotZero(t, reason.Timestamp)
+		})
+	}
+}
+
+func TestReporter(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "shutdown_reason.json")
+	reporter := shutdownreason.NewReporter(logger.NewNopLogger(), filePath)
+
+	previous, err := reporter.TakePrevious()
+	require.NoError(t, err)
+	require.Nil(t, previous)
+
+	reason := reporter.Report(shutdownreason.ComponentMigrator, common.CriticalError(fmt.Errorf("%w: duplicate tail transaction hash", migrator.ErrInvalidMigrations)))
+	require.Equal(t, shutdownreason.CodeMigratorInvalidMigrations, reason.Code)
+
+	// the reason is reported once after the restart
+	previous, err = reporter.TakePrevious()
+	require.NoError(t, err)
+	require.Equal(t, reason, previous)
+
+	previous, err = reporter.TakePrevious()
+	require.NoError(t, err)
+	require.Nil(t, previous)
+}
@@ -0,0 +1,121 @@
+package stateversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// VersionFieldName is the name of the JSON field that holds the schema version of a state file.
+	VersionFieldName = "version"
+	// UnversionedVersion is the version of state files that were written before versioning was introduced.
+	UnversionedVersion = 0
+)
+
+var (
+	// ErrDowngradeNotSupported is returned when a state file was written by a newer version of the software.
+	ErrDowngradeNotSupported = errors.New("state file was written by a newer version, downgrades are not supported")
+	// ErrMissingMigration is returned when no migration is registered to upgrade a certain version.
+	ErrMissingMigration = errors.New("missing state schema migration")
+)
+
+// Migration upgrades the JSON fields of a state from a version to the next version.
+type Migration func(fields map[string]json.RawMessage) error
+
+// Schema describes the current version of a state file format and how older versions are upgraded.
+type Schema struct {
+	// the name of the state, used in error messages.
+	name string
+	// the version of the state that is written by this software.
+	currentVersion uint32
+	// the migrations, keyed by the version they upgrade from.
+	migrations map[uint32]Migration
+}
+
+// NewSchema creates a new Schema.
+// migrations must contain a Migration for every version below currentVersion, keyed by the version it upgrades from.
+func NewSchema(name string, currentVersion uint32, migrations map[uint32]Migration) *Schema {
+	return &Schema{
+		name:           name,
+		currentVersion: currentVersion,
+		migrations:     migrations,
+	}
+}
+
+// CurrentVersion returns the version of the state that is written by this software.
+func (s *Schema) CurrentVersion() uint32 {
+	return s.currentVersion
+}
+
+// Upgrade upgrades the given JSON encoded state to the current version.
+// It returns the upgraded JSON data and the version the state was stored with.
+func (s *Schema) Upgrade(data []byte) ([]byte, uint32, error) {
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, 0, fmt.Errorf("unable to parse %s state: %w", s.name, err)
+	}
+
+	storedVersion := uint32(UnversionedVersion)
+	if rawVersion, exists := fields[VersionFieldName]; exists {
+		if err := json.Unmarshal(rawVersion, &storedVersion); err != nil {
+			return nil, 0, fmt.Errorf("unable to parse %s state version: %w", s.name, err)
+		}
+	}
+
+	if storedVersion > s.currentVersion {
+		return nil, 0, fmt.Errorf("%w: %s state version %d, supported version %d", ErrDowngradeNotSupported, s.name, storedVersion, s.currentVersion)
+	}
+
+	if storedVersion == s.currentVersion {
+		return data, storedVersion, nil
+	}
+
+	for version := storedVersion; version < s.currentVersion; version++ {
+		migration, exists := s.migrations[version]
+		if !exists {
+			return nil, 0, fmt.Errorf("%w: %s state version %d to %d", ErrMissingMigration, s.name, version, version+1)
+		}
+
+		if err := migration(fields); err != nil {
+			return nil, 0, fmt.Errorf("unable to migrate %s state from version %d to %d: %w", s.name, version, version+1, err)
+		}
+	}
+
+	rawVersion, err := json.Marshal(s.currentVersion)
+	if err != nil {
+		return nil, 0, err
+	}
+	fields[VersionFieldName] = rawVersion
+
+	upgraded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to encode migrated %s state: %w", s.name, err)
+	}
+
+	return upgraded, storedVersion, nil
+}
+
+// ReadJSONFromFile reads the state from the given file, upgrades it to the current version and unmarshals it into v.
+// It returns the version the state was stored with.
+func (s *Schema) ReadJSONFromFile(filePath string, v interface{}) (uint32, error) {
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read %s state file: %w", s.name, err)
+	}
+
+	upgraded, storedVersion, err := s.Upgrade(data)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := json.Unmarshal(upgraded, v); err != nil {
+		return 0, fmt.Errorf("unable to unmarshal %s state: %w", s.name, err)
+	}
+
+	return storedVersion, nil
+}
@@ -0,0 +1,68 @@
+package stateversion_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/stateversion"
+)
+
+type testState struct {
+	Version uint32 `json:"version"`
+	Index   uint32 `json:"index"`
+	Renamed string `json:"renamed"`
+}
+
+func newTestSchema() *stateversion.Schema {
+	return stateversion.NewSchema("test", 2, map[uint32]stateversion.Migration{
+		0: func(fields map[string]json.RawMessage) error {
+			return nil
+		},
+		1: func(fields map[string]json.RawMessage) error {
+			fields["renamed"] = fields["old"]
+			delete(fields, "old")
+
+			return nil
+		},
+	})
+}
+
+func TestUpgradeUnversioned(t *testing.T) {
+	schema := newTestSchema()
+
+	upgraded, storedVersion, err := schema.Upgrade([]byte(`{"index":5,"old":"value"}`))
+	require.NoError(t, err)
+	require.EqualValues(t, stateversion.UnversionedVersion, storedVersion)
+
+	state := &testState{}
+	require.NoError(t, json.Unmarshal(upgraded, state))
+	require.EqualValues(t, 2, state.Version)
+	require.EqualValues(t, 5, state.Index)
+	require.Equal(t, "value", state.Renamed)
+}
+
+func TestUpgradeCurrentVersion(t *testing.T) {
+	schema := newTestSchema()
+
+	data := []byte(`{"version":2,"index":5,"renamed":"value"}`)
+	upgraded, storedVersion, err := schema.Upgrade(data)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, storedVersion)
+	require.Equal(t, data, upgraded)
+}
+
+func TestUpgradeRefusesDowngrade(t *testing.T) {
+	schema := newTestSchema()
+
+	_, _, err := schema.Upgrade([]byte(`{"version":3,"index":5}`))
+	require.ErrorIs(t, err, stateversion.ErrDowngradeNotSupported)
+}
+
+func TestUpgradeMissingMigration(t *testing.T) {
+	schema := stateversion.NewSchema("test", 2, map[uint32]stateversion.Migration{})
+
+	_, _, err := schema.Upgrade([]byte(`{"version":1,"index":5}`))
+	require.ErrorIs(t, err, stateversion.ErrMissingMigration)
+}
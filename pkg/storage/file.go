@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hive.go/serializer/v2"
+)
+
+// the size of the header of a record, the little-endian lengths of the key and the value.
+const fileRecordHeaderSize = 2 * serializer.UInt32ByteSize
+
+// FileStore keeps the entries in a single append-only file and all of them in memory.
+// Every put appends a record of the key and the value, a later record of the same key replaces the earlier one.
+type FileStore struct {
+	lock syncutils.RWMutex
+
+	filePath string
+	file     *os.File
+	// the size of the file.
+	size int64
+	// the latest value of every key.
+	entries map[string][]byte
+}
+
+// OpenFileStore opens the store at the given path and creates it if it does not exist.
+// An incomplete record at the end of the file, e.g. after a crash while writing, is discarded.
+func OpenFileStore(filePath string) (*FileStore, error) {
+	//nolint:gosec // the path is defined by the operator
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open store: %w", err)
+	}
+
+	s := &FileStore{
+		filePath: filePath,
+		file:     file,
+		entries:  make(map[string][]byte),
+	}
+
+	if err := s.load(); err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// load reads all records and truncates the file after the last complete record.
+func (s *FileStore) load() error {
+	reader := bufio.NewReader(s.file)
+
+	var offset int64
+	header := make([]byte, fileRecordHeaderSize)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+
+			return fmt.Errorf("unable to read store: %w", err)
+		}
+
+		keyLength := binary.LittleEndian.Uint32(header[:serializer.UInt32ByteSize])
+		valueLength := binary.LittleEndian.Uint32(header[serializer.UInt32ByteSize:])
+
+		record := make([]byte, int(keyLength)+int(valueLength))
+		if _, err := io.ReadFull(reader, record); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+
+			return fmt.Errorf("unable to read store: %w", err)
+		}
+
+		s.entries[string(record[:keyLength])] = record[keyLength:]
+		offset += int64(fileRecordHeaderSize + len(record))
+	}
+
+	if err := s.file.Truncate(offset); err != nil {
+		return fmt.Errorf("unable to truncate store: %w", err)
+	}
+	s.size = offset
+
+	return nil
+}
+
+// Put appends a record of the key and the value to the file.
+func (s *FileStore) Put(key []byte, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return ErrStoreClosed
+	}
+
+	record := make([]byte, 0, fileRecordHeaderSize+len(key)+len(value))
+	record = binary.LittleEndian.AppendUint32(record, uint32(len(key)))
+	record = binary.LittleEndian.AppendUint32(record, uint32(len(value)))
+	record = append(record, key...)
+	record = append(record, value...)
+
+	if _, err := s.file.WriteAt(record, s.size); err != nil {
+		return fmt.Errorf("unable to write store: %w", err)
+	}
+
+	s.size += int64(len(record))
+	s.entries[string(key)] = append([]byte{}, value...)
+
+	return nil
+}
+
+// Get returns the value of the given key or ErrNotFound.
+func (s *FileStore) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.file == nil {
+		return nil, ErrStoreClosed
+	}
+
+	value, exists := s.entries[string(key)]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	return append([]byte{}, value...), nil
+}
+
+// Iterate calls the consumer with all entries whose key starts with the given prefix, in ascending order of their keys.
+func (s *FileStore) Iterate(prefix []byte, consumer func(key []byte, value []byte) bool) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.file == nil {
+		return ErrStoreClosed
+	}
+
+	keys := make([]string, 0)
+	for key := range s.entries {
+		if bytes.HasPrefix([]byte(key), prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !consumer([]byte(key), append([]byte{}, s.entries[key]...)) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Flush syncs the file, so that all entries that were put so far survive a crash.
+func (s *FileStore) Flush() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return ErrStoreClosed
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("unable to sync store: %w", err)
+	}
+
+	return nil
+}
+
+// Close syncs and closes the file.
+func (s *FileStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Sync()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	s.file = nil
+
+	return err
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/kvstore"
+)
+
+// KVStore keeps the entries in a key-value store, its durability is defined by the store.
+type KVStore struct {
+	store kvstore.KVStore
+}
+
+// NewKVStore creates a store that keeps the entries in the given key-value store.
+func NewKVStore(store kvstore.KVStore) *KVStore {
+	return &KVStore{store: store}
+}
+
+// Put sets the value of the given key.
+func (s *KVStore) Put(key []byte, value []byte) error {
+	return s.mapError(s.store.Set(key, value))
+}
+
+// Get returns the value of the given key or ErrNotFound.
+func (s *KVStore) Get(key []byte) ([]byte, error) {
+	value, err := s.store.Get(key)
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+
+	return value, nil
+}
+
+// Iterate calls the consumer with all entries whose key starts with the given prefix, in ascending order of their keys.
+// Not all key-value stores iterate in order, so the entries are sorted first.
+func (s *KVStore) Iterate(prefix []byte, consumer func(key []byte, value []byte) bool) error {
+	type entry struct {
+		key   []byte
+		value []byte
+	}
+
+	entries := make([]*entry, 0)
+	if err := s.store.Iterate(prefix, func(key kvstore.Key, value kvstore.Value) bool {
+		entries = append(entries, &entry{key: append([]byte{}, key...), value: append([]byte{}, value...)})
+
+		return true
+	}); err != nil {
+		return s.mapError(err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	for _, e := range entries {
+		if !consumer(e.key, e.value) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Flush persists all outstanding writes of the key-value store.
+func (s *KVStore) Flush() error {
+	return s.mapError(s.store.Flush())
+}
+
+// Close flushes and closes the key-value store.
+func (s *KVStore) Close() error {
+	if err := s.store.Flush(); err != nil && !errors.Is(err, kvstore.ErrStoreClosed) {
+		return err
+	}
+
+	return s.mapError(s.store.Close())
+}
+
+// mapError maps the errors of the key-value store to the errors of the store.
+func (s *KVStore) mapError(err error) error {
+	switch {
+	case errors.Is(err, kvstore.ErrKeyNotFound):
+		return ErrNotFound
+	case errors.Is(err, kvstore.ErrStoreClosed):
+		return ErrStoreClosed
+	default:
+		return err
+	}
+}
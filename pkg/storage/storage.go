@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/kvstore/mapdb"
+)
+
+const (
+	// BackendFile keeps the entries in a single append-only file, they are durable once they were flushed.
+	BackendFile = "file"
+	// BackendMapDB keeps the entries in an in-memory key-value store, they are lost on shutdown.
+	BackendMapDB = "mapdb"
+)
+
+var (
+	// ErrNotFound is returned when the store doesn't contain an entry with the given key.
+	ErrNotFound = errors.New("entry not found")
+	// ErrStoreClosed is returned when the store was already closed.
+	ErrStoreClosed = errors.New("store closed")
+	// ErrUnknownBackend is returned when a store with an unknown backend is opened.
+	ErrUnknownBackend = errors.New("unknown storage backend")
+)
+
+// Store persists the entries of the components that keep records, e.g. the included tail transaction hashes
+// and the event journal, so that they can share a single backend.
+// The components separate their entries by a key prefix.
+type Store interface {
+	// Put sets the value of the given key, an existing value is replaced.
+	Put(key []byte, value []byte) error
+	// Get returns the value of the given key or ErrNotFound.
+	Get(key []byte) ([]byte, error)
+	// Iterate calls the consumer with all entries whose key starts with the given prefix, in ascending order of their keys.
+	// Returning false from the consumer stops the iteration.
+	Iterate(prefix []byte, consumer func(key []byte, value []byte) bool) error
+	// Flush makes all entries that were put so far durable.
+	Flush() error
+	// Close flushes and closes the store.
+	Close() error
+}
+
+// Open opens the store with the given backend, the path is only used by backends that are persisted to disk.
+func Open(backend string, path string) (Store, error) {
+	switch backend {
+	case BackendFile:
+		return OpenFileStore(path)
+	case BackendMapDB:
+		return NewKVStore(mapdb.NewMapDB()), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, backend)
+	}
+}
+
+// Key returns the key of an entry of a component, which is the concatenation of the prefix of the component and the given key.
+func Key(prefix []byte, key []byte) []byte {
+	result := make([]byte, 0, len(prefix)+len(key))
+	result = append(result, prefix...)
+
+	return append(result, key...)
+}
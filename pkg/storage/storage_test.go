@@ -0,0 +1,112 @@
+package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+)
+
+// iterateKeys returns the keys of all entries with the given prefix in the order they were iterated.
+func iterateKeys(t *testing.T, store storage.Store, prefix string) []string {
+	t.Helper()
+
+	keys := make([]string, 0)
+	require.NoError(t, store.Iterate([]byte(prefix), func(key []byte, _ []byte) bool {
+		keys = append(keys, string(key))
+
+		return true
+	}))
+
+	return keys
+}
+
+func testStore(t *testing.T, store storage.Store) {
+	t.Helper()
+
+	_, err := store.Get([]byte("a/1"))
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	for _, key := range []string{"b/2", "a/2", "b/1", "a/1"} {
+		require.NoError(t, store.Put([]byte(key), []byte("value "+key)))
+	}
+	require.NoError(t, store.Put([]byte("a/2"), []byte("replaced")))
+	require.NoError(t, store.Flush())
+
+	value, err := store.Get([]byte("a/2"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("replaced"), value)
+
+	// the entries of a prefix are iterated in ascending order of their keys
+	require.Equal(t, []string{"a/1", "a/2"}, iterateKeys(t, store, "a/"))
+	require.Equal(t, []string{"a/1", "a/2", "b/1", "b/2"}, iterateKeys(t, store, ""))
+
+	// the iteration stops once the consumer returns false
+	var count int
+	require.NoError(t, store.Iterate(nil, func(_ []byte, _ []byte) bool {
+		count++
+
+		return false
+	}))
+	require.Equal(t, 1, count)
+
+	require.NoError(t, store.Close())
+	require.ErrorIs(t, store.Put([]byte("c"), nil), storage.ErrStoreClosed)
+	_, err = store.Get([]byte("a/1"))
+	require.ErrorIs(t, err, storage.ErrStoreClosed)
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := storage.Open(storage.BackendFile, filepath.Join(t.TempDir(), "storage.db"))
+	require.NoError(t, err)
+	testStore(t, store)
+}
+
+func TestMapDBStore(t *testing.T) {
+	store, err := storage.Open(storage.BackendMapDB, "")
+	require.NoError(t, err)
+	testStore(t, store)
+
+	_, err = storage.Open("unknown", "")
+	require.ErrorIs(t, err, storage.ErrUnknownBackend)
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "storage.db")
+
+	store, err := storage.OpenFileStore(filePath)
+	require.NoError(t, err)
+	require.NoError(t, store.Put([]byte("a"), []byte("1")))
+	require.NoError(t, store.Put([]byte("b"), []byte("2")))
+	require.NoError(t, store.Put([]byte("a"), []byte("3")))
+	require.NoError(t, store.Close())
+
+	// simulate a crash while a record was written
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{1, 0, 0, 0, 5, 0, 0, 0, 'c', 'v'})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	store, err = storage.OpenFileStore(filePath)
+	require.NoError(t, err)
+
+	value, err := store.Get([]byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("3"), value)
+	require.Equal(t, []string{"a", "b"}, iterateKeys(t, store, ""))
+
+	// the incomplete record was discarded, so new records are readable again
+	require.NoError(t, store.Put([]byte("c"), []byte("4")))
+	require.NoError(t, store.Close())
+
+	store, err = storage.OpenFileStore(filePath)
+	require.NoError(t, err)
+	value, err = store.Get([]byte("c"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("4"), value)
+	require.NoError(t, store.Close())
+}
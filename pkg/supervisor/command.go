@@ -0,0 +1,128 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/iotaledger/hive.go/core/configuration"
+)
+
+const (
+	// CommandSupervisor is the command that runs several coordinator instances.
+	CommandSupervisor = "supervisor"
+
+	// FlagInstancesFilePath is the flag for the path to the instances file.
+	FlagInstancesFilePath = "instances"
+	// FlagBindAddress is the flag for the bind address of the gateway.
+	FlagBindAddress = "bindAddress"
+
+	// DefaultValueInstancesFilePath is the default path to the instances file.
+	DefaultValueInstancesFilePath = "instances.json"
+	// DefaultValueBindAddress is the default bind address of the gateway.
+	DefaultValueBindAddress = "localhost:9092"
+)
+
+// ShouldRun checks if the supervisor was requested.
+func ShouldRun() bool {
+	args := os.Args[1:]
+
+	return len(args) > 0 && strings.ToLower(args[0]) == CommandSupervisor
+}
+
+// Run runs the supervisor until the process receives a termination signal and exits the process.
+func Run() {
+	if err := run(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			// help text was requested
+			os.Exit(0)
+		}
+
+		fmt.Printf("\nerror: %s\n", err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func run(args []string) error {
+
+	fs := configuration.NewUnsortedFlagSet("", flag.ContinueOnError)
+	instancesFilePathFlag := fs.String(FlagInstancesFilePath, DefaultValueInstancesFilePath, "the path to the file that defines the coordinator instances")
+	bindAddressFlag := fs.String(FlagBindAddress, DefaultValueBindAddress, "the bind address of the gateway to the APIs and metrics of the instances (empty = disabled)")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage of %s:\n", CommandSupervisor)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s",
+			CommandSupervisor,
+			FlagInstancesFilePath,
+			DefaultValueInstancesFilePath,
+		))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("too much arguments")
+	}
+
+	configs, err := LoadInstanceConfigs(*instancesFilePathFlag)
+	if err != nil {
+		return err
+	}
+
+	executable, err := Executable()
+	if err != nil {
+		return err
+	}
+
+	supervisor, err := New(executable, os.Stdout, configs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if *bindAddressFlag != "" {
+		gateway, err := NewGateway(supervisor)
+		if err != nil {
+			return err
+		}
+
+		server := &http.Server{
+			Addr:              *bindAddressFlag,
+			Handler:           gateway,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Printf("gateway stopped: %s\n", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Printf("gateway to the instances listening on %s\n", *bindAddressFlag)
+	}
+
+	fmt.Printf("starting %d coordinator instances\n", len(configs))
+	supervisor.Run(ctx)
+
+	return nil
+}
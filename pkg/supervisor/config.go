@@ -0,0 +1,88 @@
+package supervisor
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/ioutils"
+)
+
+var (
+	// ErrInvalidInstanceConfig is returned when the configuration of the coordinator instances is invalid.
+	ErrInvalidInstanceConfig = errors.New("invalid instance configuration")
+
+	// network IDs are used as path segments of the gateway.
+	networkIDRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+)
+
+// InstanceConfig defines a coordinator instance that is managed by the supervisor.
+// Every instance uses its own configuration file, so it has its own INX target, keys and state files.
+type InstanceConfig struct {
+	// the network ID the instance issues milestones for, used to namespace the APIs of the instance.
+	NetworkID string `json:"networkId"`
+	// the path to the configuration file of the instance.
+	ConfigFilePath string `json:"configFilePath"`
+	// the additional command line arguments of the instance (optional).
+	Args []string `json:"args,omitempty"`
+	// the base URL of the coordinator REST API of the instance (optional).
+	RestAPIURL string `json:"restApiUrl,omitempty"`
+	// the base URL of the prometheus metrics of the instance (optional).
+	PrometheusURL string `json:"prometheusUrl,omitempty"`
+}
+
+// LoadInstanceConfigs loads the configuration of the coordinator instances from a JSON file.
+func LoadInstanceConfigs(filePath string) ([]*InstanceConfig, error) {
+	instances := []*InstanceConfig{}
+	if err := ioutils.ReadJSONFromFile(filePath, &instances); err != nil {
+		return nil, fmt.Errorf("unable to load instances file: %w", err)
+	}
+
+	if err := ValidateInstanceConfigs(instances); err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// ValidateInstanceConfigs checks that the instances are unique and all required fields are set.
+func ValidateInstanceConfigs(instances []*InstanceConfig) error {
+	if len(instances) == 0 {
+		return fmt.Errorf("%w: no instances given", ErrInvalidInstanceConfig)
+	}
+
+	networkIDs := make(map[string]struct{}, len(instances))
+	configFilePaths := make(map[string]struct{}, len(instances))
+
+	for _, instance := range instances {
+		if !networkIDRegex.MatchString(instance.NetworkID) {
+			return fmt.Errorf("%w: invalid network ID \"%s\"", ErrInvalidInstanceConfig, instance.NetworkID)
+		}
+		if _, exists := networkIDs[instance.NetworkID]; exists {
+			return fmt.Errorf("%w: duplicate network ID %s", ErrInvalidInstanceConfig, instance.NetworkID)
+		}
+		networkIDs[instance.NetworkID] = struct{}{}
+
+		if instance.ConfigFilePath == "" {
+			return fmt.Errorf("%w: no config file given for network ID %s", ErrInvalidInstanceConfig, instance.NetworkID)
+		}
+		// instances sharing a config file would also share their state files
+		if _, exists := configFilePaths[instance.ConfigFilePath]; exists {
+			return fmt.Errorf("%w: config file %s is used by several instances", ErrInvalidInstanceConfig, instance.ConfigFilePath)
+		}
+		configFilePaths[instance.ConfigFilePath] = struct{}{}
+
+		for _, baseURL := range []string{instance.RestAPIURL, instance.PrometheusURL} {
+			if baseURL == "" {
+				continue
+			}
+			if _, err := url.ParseRequestURI(baseURL); err != nil {
+				return fmt.Errorf("%w: invalid URL %s for network ID %s: %s", ErrInvalidInstanceConfig, baseURL, instance.NetworkID, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+const (
+	// RouteInstances is the route of the gateway to get the status of all instances.
+	RouteInstances = "/instances"
+	// the path segment of the gateway routes that are forwarded to the coordinator REST API of an instance.
+	apiPathSegment = "api"
+	// the path segment of the gateway routes that are forwarded to the prometheus metrics of an instance.
+	metricsPathSegment = "metrics"
+)
+
+// instanceProxies contains the reverse proxies to the APIs of an instance.
+type instanceProxies struct {
+	restAPI    *httputil.ReverseProxy
+	prometheus *httputil.ReverseProxy
+}
+
+// Gateway exposes the admin APIs and the metrics of all instances namespaced by their network ID,
+// e.g. "/<networkID>/api/status" and "/<networkID>/metrics".
+type Gateway struct {
+	supervisor *Supervisor
+	proxies    map[string]*instanceProxies
+}
+
+// NewGateway creates a new Gateway for the instances of the supervisor.
+func NewGateway(supervisor *Supervisor) (*Gateway, error) {
+	proxies := make(map[string]*instanceProxies, len(supervisor.instances))
+
+	for _, inst := range supervisor.instances {
+		instanceProxy := &instanceProxies{}
+
+		if inst.config.RestAPIURL != "" {
+			target, err := url.Parse(inst.config.RestAPIURL)
+			if err != nil {
+				return nil, err
+			}
+			instanceProxy.restAPI = newReverseProxy(target)
+		}
+
+		if inst.config.PrometheusURL != "" {
+			target, err := url.Parse(inst.config.PrometheusURL)
+			if err != nil {
+				return nil, err
+			}
+			instanceProxy.prometheus = newReverseProxy(target)
+		}
+
+		proxies[inst.config.NetworkID] = instanceProxy
+	}
+
+	return &Gateway{
+		supervisor: supervisor,
+		proxies:    proxies,
+	}, nil
+}
+
+// newReverseProxy creates a reverse proxy that forwards the requests to the base URL of the target.
+// The path of the request must already be stripped of the gateway prefix.
+func newReverseProxy(target *url.URL) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = strings.TrimSuffix(target.Path, "/") + req.URL.Path
+			req.URL.RawPath = ""
+			req.Host = target.Host
+		},
+	}
+}
+
+// ServeHTTP routes the request to the instance with the network ID given in the first path segment.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.URL.Path == RouteInstances {
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(g.supervisor.Statuses())
+
+		return
+	}
+
+	// "/<networkID>/<segment>/<rest>"
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 3)
+	if len(parts) < 2 {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	proxies, exists := g.proxies[parts[0]]
+	if !exists {
+		http.Error(w, "unknown network ID", http.StatusNotFound)
+
+		return
+	}
+
+	var rest string
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+
+	switch {
+	case parts[1] == apiPathSegment && proxies.restAPI != nil:
+		r.URL.Path = "/" + rest
+		proxies.restAPI.ServeHTTP(w, r)
+
+	case parts[1] == metricsPathSegment && rest == "" && proxies.prometheus != nil:
+		r.URL.Path = "/metrics"
+		proxies.prometheus.ServeHTTP(w, r)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
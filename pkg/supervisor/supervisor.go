@@ -0,0 +1,248 @@
+package supervisor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/hive.go/core/timeutil"
+)
+
+const (
+	// the minimum delay before a terminated instance is restarted.
+	minRestartDelay = time.Second
+	// the maximum delay before a terminated instance is restarted.
+	maxRestartDelay = time.Minute
+	// instances that ran at least this long are considered healthy, so the restart delay is reset.
+	healthyRuntime = 5 * time.Minute
+	// the duration the instances have to shut down gracefully before they are killed.
+	stopGracePeriod = 2 * time.Minute
+)
+
+// InstanceStatus is the status of a coordinator instance managed by the supervisor.
+type InstanceStatus struct {
+	// the network ID of the instance.
+	NetworkID string `json:"networkId"`
+	// whether the instance is running.
+	Running bool `json:"running"`
+	// the process ID of the instance, if it is running.
+	PID int `json:"pid,omitempty"`
+	// the amount of restarts of the instance.
+	Restarts int `json:"restarts"`
+	// the error the instance terminated with the last time.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// instance is a coordinator instance that is running as child process.
+type instance struct {
+	config *InstanceConfig
+
+	statusLock syncutils.RWMutex
+	status     *InstanceStatus
+}
+
+func (i *instance) Status() *InstanceStatus {
+	i.statusLock.RLock()
+	defer i.statusLock.RUnlock()
+
+	status := *i.status
+
+	return &status
+}
+
+func (i *instance) updateStatus(update func(status *InstanceStatus)) {
+	i.statusLock.Lock()
+	defer i.statusLock.Unlock()
+
+	update(i.status)
+}
+
+// Supervisor runs several independent coordinator instances as child processes and restarts them if they terminate.
+// The instances are isolated processes, because every coordinator instance needs its own INX connection, keys and state.
+type Supervisor struct {
+	// the path to the executable of the coordinator.
+	executable string
+	// the writer the output of the instances is written to.
+	output io.Writer
+	// used to serialize the output of the instances.
+	outputLock sync.Mutex
+	// the managed instances.
+	instances []*instance
+}
+
+// New creates a new Supervisor for the given instances.
+func New(executable string, output io.Writer, configs []*InstanceConfig) (*Supervisor, error) {
+	if err := ValidateInstanceConfigs(configs); err != nil {
+		return nil, err
+	}
+
+	instances := make([]*instance, 0, len(configs))
+	for _, config := range configs {
+		instances = append(instances, &instance{
+			config: config,
+			status: &InstanceStatus{NetworkID: config.NetworkID},
+		})
+	}
+
+	return &Supervisor{
+		executable: executable,
+		output:     output,
+		instances:  instances,
+	}, nil
+}
+
+// Statuses returns the status of all instances.
+func (s *Supervisor) Statuses() []*InstanceStatus {
+	statuses := make([]*InstanceStatus, 0, len(s.instances))
+	for _, instance := range s.instances {
+		statuses = append(statuses, instance.Status())
+	}
+
+	return statuses
+}
+
+// Run starts all instances and keeps them running until the context is done.
+func (s *Supervisor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, inst := range s.instances {
+		wg.Add(1)
+		go func(inst *instance) {
+			defer wg.Done()
+			s.supervise(ctx, inst)
+		}(inst)
+	}
+	wg.Wait()
+}
+
+// supervise runs the instance and restarts it with an increasing delay until the context is done.
+func (s *Supervisor) supervise(ctx context.Context, inst *instance) {
+	restartDelay := minRestartDelay
+
+	for {
+		started := time.Now()
+		err := s.runInstance(ctx, inst)
+		if ctx.Err() != nil {
+			s.printf(inst, "stopped")
+
+			return
+		}
+
+		if err == nil {
+			err = fmt.Errorf("instance terminated")
+		}
+		inst.updateStatus(func(status *InstanceStatus) {
+			status.LastError = err.Error()
+		})
+
+		if time.Since(started) >= healthyRuntime {
+			restartDelay = minRestartDelay
+		}
+
+		s.printf(inst, "terminated: %s, restarting in %v", err, restartDelay)
+		if !timeutil.Sleep(ctx, restartDelay) {
+			return
+		}
+
+		inst.updateStatus(func(status *InstanceStatus) {
+			status.Restarts++
+		})
+
+		restartDelay *= 2
+		if restartDelay > maxRestartDelay {
+			restartDelay = maxRestartDelay
+		}
+	}
+}
+
+// runInstance starts the process of the instance and waits until it terminated.
+// The process is stopped gracefully if the context is done.
+func (s *Supervisor) runInstance(ctx context.Context, inst *instance) error {
+	args := append([]string{"--config", inst.config.ConfigFilePath}, inst.config.Args...)
+
+	//nolint:gosec // the executable and the arguments are defined by the operator
+	cmd := exec.Command(s.executable, args...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	inst.updateStatus(func(status *InstanceStatus) {
+		status.Running = true
+		status.PID = cmd.Process.Pid
+	})
+	defer inst.updateStatus(func(status *InstanceStatus) {
+		status.Running = false
+		status.PID = 0
+	})
+
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		s.copyOutput(inst, stdout)
+	}()
+
+	processDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			// give the coordinator the chance to persist its state
+			_ = cmd.Process.Signal(syscall.SIGTERM)
+
+			select {
+			case <-processDone:
+			case <-time.After(stopGracePeriod):
+				_ = cmd.Process.Kill()
+			}
+		case <-processDone:
+		}
+	}()
+
+	// all output needs to be read before waiting for the process
+	<-outputDone
+	err = cmd.Wait()
+	close(processDone)
+
+	return err
+}
+
+// copyOutput writes the output of the instance line by line, prefixed with the network ID.
+func (s *Supervisor) copyOutput(inst *instance, reader io.Reader) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		s.println(inst, scanner.Text())
+	}
+}
+
+func (s *Supervisor) printf(inst *instance, format string, args ...any) {
+	s.println(inst, fmt.Sprintf(format, args...))
+}
+
+func (s *Supervisor) println(inst *instance, line string) {
+	s.outputLock.Lock()
+	defer s.outputLock.Unlock()
+
+	_, _ = fmt.Fprintf(s.output, "[%s] %s\n", inst.config.NetworkID, line)
+}
+
+// Executable returns the path to the executable of the running process, which is used to start the instances.
+func Executable() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the executable: %w", err)
+	}
+
+	return executable, nil
+}
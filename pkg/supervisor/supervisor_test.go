@@ -0,0 +1,156 @@
+package supervisor_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/supervisor"
+)
+
+// syncBuffer is a buffer that can be written and read concurrently.
+type syncBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buffer.String()
+}
+
+func TestValidateInstanceConfigs(t *testing.T) {
+	require.NoError(t, supervisor.ValidateInstanceConfigs([]*supervisor.InstanceConfig{
+		{NetworkID: "tenant-a", ConfigFilePath: "a.json", RestAPIURL: "http://localhost:9091"},
+		{NetworkID: "tenant-b", ConfigFilePath: "b.json"},
+	}))
+
+	require.ErrorIs(t, supervisor.ValidateInstanceConfigs(nil), supervisor.ErrInvalidInstanceConfig)
+
+	require.ErrorIs(t, supervisor.ValidateInstanceConfigs([]*supervisor.InstanceConfig{
+		{NetworkID: "tenant/a", ConfigFilePath: "a.json"},
+	}), supervisor.ErrInvalidInstanceConfig)
+
+	require.ErrorIs(t, supervisor.ValidateInstanceConfigs([]*supervisor.InstanceConfig{
+		{NetworkID: "tenant-a", ConfigFilePath: "a.json"},
+		{NetworkID: "tenant-a", ConfigFilePath: "b.json"},
+	}), supervisor.ErrInvalidInstanceConfig)
+
+	// instances must not share their state
+	require.ErrorIs(t, supervisor.ValidateInstanceConfigs([]*supervisor.InstanceConfig{
+		{NetworkID: "tenant-a", ConfigFilePath: "a.json"},
+		{NetworkID: "tenant-b", ConfigFilePath: "a.json"},
+	}), supervisor.ErrInvalidInstanceConfig)
+
+	require.ErrorIs(t, supervisor.ValidateInstanceConfigs([]*supervisor.InstanceConfig{
+		{NetworkID: "tenant-a", ConfigFilePath: "a.json", PrometheusURL: "localhost"},
+	}), supervisor.ErrInvalidInstanceConfig)
+}
+
+func TestSupervisorRestartsInstances(t *testing.T) {
+	// the fake coordinator prints its arguments and terminates immediately
+	executable := filepath.Join(t.TempDir(), "coordinator.sh")
+	require.NoError(t, os.WriteFile(executable, []byte("#!/bin/sh\necho \"args: $@\"\n"), 0700))
+
+	output := &syncBuffer{}
+	s, err := supervisor.New(executable, output, []*supervisor.InstanceConfig{
+		{NetworkID: "tenant-a", ConfigFilePath: "a.json", Args: []string{"--inx.address", "localhost:9029"}},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return s.Statuses()[0].Restarts >= 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+
+	require.Contains(t, output.String(), "[tenant-a] args: --config a.json --inx.address localhost:9029")
+	require.False(t, s.Statuses()[0].Running)
+	require.NotEmpty(t, s.Statuses()[0].LastError)
+}
+
+func TestGateway(t *testing.T) {
+	restAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "api "+r.URL.Path)
+	}))
+	defer restAPI.Close()
+
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "metrics "+r.URL.Path)
+	}))
+	defer prometheus.Close()
+
+	s, err := supervisor.New("coordinator", io.Discard, []*supervisor.InstanceConfig{
+		{NetworkID: "tenant-a", ConfigFilePath: "a.json", RestAPIURL: restAPI.URL + "/api/coordinator/v1", PrometheusURL: prometheus.URL},
+		{NetworkID: "tenant-b", ConfigFilePath: "b.json"},
+	})
+	require.NoError(t, err)
+
+	gateway, err := supervisor.NewGateway(s)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		res, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		return res.StatusCode, strings.TrimSpace(string(body))
+	}
+
+	code, body := get("/tenant-a/api/status")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "api /api/coordinator/v1/status", body)
+
+	code, body = get("/tenant-a/metrics")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "metrics /metrics", body)
+
+	// tenant-b does not expose its APIs
+	code, _ = get("/tenant-b/api/status")
+	require.Equal(t, http.StatusNotFound, code)
+
+	code, _ = get("/tenant-c/api/status")
+	require.Equal(t, http.StatusNotFound, code)
+
+	code, body = get(supervisor.RouteInstances)
+	require.Equal(t, http.StatusOK, code)
+
+	statuses := []*supervisor.InstanceStatus{}
+	require.NoError(t, json.Unmarshal([]byte(body), &statuses))
+	require.Len(t, statuses, 2)
+	require.Equal(t, "tenant-a", statuses[0].NetworkID)
+}
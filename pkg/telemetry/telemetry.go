@@ -0,0 +1,219 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/hive.go/core/syncutils"
+)
+
+// the maximum amount of bytes of the response body that are included in errors.
+const maxErrorBodySize = 512
+
+var (
+	// ErrReportFailed is returned when the report could not be delivered to the endpoint.
+	ErrReportFailed = errors.New("telemetry report failed")
+)
+
+// Report contains the anonymous statistics of a coordinator for a single reporting period.
+// It only contains aggregate values, no identifiers of the instance, the network or the keys are reported.
+type Report struct {
+	// the version of the coordinator.
+	Version string `json:"version"`
+	// the operating system and the architecture the coordinator runs on.
+	Platform string `json:"platform"`
+	// the duration of the reporting period in seconds.
+	PeriodSeconds int64 `json:"periodSeconds"`
+	// the amount of milestones that were issued during the period.
+	MilestonesIssued uint64 `json:"milestonesIssued"`
+	// the average amount of milestones that were issued per minute during the period.
+	MilestonesPerMinute float64 `json:"milestonesPerMinute"`
+	// the amount of milestones that were skipped during the period, e.g. because the node was not synced.
+	MilestonesSkipped uint64 `json:"milestonesSkipped"`
+	// the amount of receipts that were issued during the period.
+	ReceiptsIssued uint64 `json:"receiptsIssued"`
+	// whether the migrator is enabled.
+	MigratorEnabled bool `json:"migratorEnabled"`
+}
+
+// Collector aggregates the statistics of the current reporting period.
+type Collector struct {
+	lock syncutils.Mutex
+
+	version         string
+	migratorEnabled bool
+	clock           func() time.Time
+
+	// the start of the current reporting period.
+	periodStart       time.Time
+	milestonesIssued  uint64
+	milestonesSkipped uint64
+	receiptsIssued    uint64
+}
+
+// WithClock defines the clock used to measure the reporting periods.
+func WithClock(clock func() time.Time) options.Option[Collector] {
+	return func(c *Collector) {
+		c.clock = clock
+	}
+}
+
+// NewCollector creates a new Collector for a coordinator with the given version.
+func NewCollector(version string, migratorEnabled bool, opts ...options.Option[Collector]) *Collector {
+	return options.Apply(&Collector{
+		version:         version,
+		migratorEnabled: migratorEnabled,
+		clock:           time.Now,
+	}, opts, func(c *Collector) {
+		c.periodStart = c.clock()
+	})
+}
+
+// MilestoneIssued counts an issued milestone.
+func (c *Collector) MilestoneIssued() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.milestonesIssued++
+}
+
+// MilestoneSkipped counts a skipped milestone.
+func (c *Collector) MilestoneSkipped() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.milestonesSkipped++
+}
+
+// ReceiptIssued counts an issued receipt.
+func (c *Collector) ReceiptIssued() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.receiptsIssued++
+}
+
+// Report returns the report of the current reporting period without ending it.
+func (c *Collector) Report() *Report {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.report(c.clock())
+}
+
+// report returns the report of the period that ends at the given time, the caller must hold the lock.
+func (c *Collector) report(end time.Time) *Report {
+	period := end.Sub(c.periodStart)
+
+	report := &Report{
+		Version:           c.version,
+		Platform:          fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		PeriodSeconds:     int64(period.Seconds()),
+		MilestonesIssued:  c.milestonesIssued,
+		MilestonesSkipped: c.milestonesSkipped,
+		ReceiptsIssued:    c.receiptsIssued,
+		MigratorEnabled:   c.migratorEnabled,
+	}
+	if period >= time.Second {
+		report.MilestonesPerMinute = float64(c.milestonesIssued) / period.Minutes()
+	}
+
+	return report
+}
+
+// endPeriod returns the report of the current reporting period and starts the next one.
+// The returned function puts the statistics back into the next period, e.g. if the report could not be delivered.
+func (c *Collector) endPeriod() (*Report, func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	start := c.periodStart
+	end := c.clock()
+	report := c.report(end)
+
+	milestonesIssued, milestonesSkipped, receiptsIssued := c.milestonesIssued, c.milestonesSkipped, c.receiptsIssued
+	c.periodStart = end
+	c.milestonesIssued = 0
+	c.milestonesSkipped = 0
+	c.receiptsIssued = 0
+
+	return report, func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		c.periodStart = start
+		c.milestonesIssued += milestonesIssued
+		c.milestonesSkipped += milestonesSkipped
+		c.receiptsIssued += receiptsIssued
+	}
+}
+
+// Reporter delivers the reports of the collector to the telemetry endpoint.
+type Reporter struct {
+	// the URL the reports are posted to.
+	endpoint string
+	// the HTTP client used for the requests.
+	httpClient *http.Client
+	// the collector of the statistics.
+	collector *Collector
+}
+
+// NewReporter creates a new Reporter that posts the reports of the given collector to the endpoint.
+func NewReporter(endpoint string, timeout time.Duration, collector *Collector) *Reporter {
+	return &Reporter{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: timeout},
+		collector:  collector,
+	}
+}
+
+// Send ends the current reporting period and posts its report to the endpoint.
+// If the report could not be delivered, its statistics are included in the next report.
+func (r *Reporter) Send(ctx context.Context) (*Report, error) {
+	report, restore := r.collector.endPeriod()
+
+	if err := r.post(ctx, report); err != nil {
+		restore()
+
+		return nil, fmt.Errorf("%w: %s", ErrReportFailed, err)
+	}
+
+	return report, nil
+}
+
+func (r *Reporter) post(ctx context.Context, report *Report) error {
+	reqBody, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+
+		return fmt.Errorf("endpoint returned status code %d: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+
+	return nil
+}
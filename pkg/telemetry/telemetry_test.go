@@ -0,0 +1,92 @@
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/telemetry"
+)
+
+func TestReporterSend(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	collector := telemetry.NewCollector("1.0.0", true, telemetry.WithClock(func() time.Time { return now }))
+
+	var reports []map[string]any
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		if failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		report := make(map[string]any)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		reports = append(reports, report)
+	}))
+	defer server.Close()
+
+	reporter := telemetry.NewReporter(server.URL, time.Second, collector)
+
+	for i := 0; i < 10; i++ {
+		collector.MilestoneIssued()
+	}
+	collector.MilestoneSkipped()
+	collector.ReceiptIssued()
+	now = now.Add(5 * time.Minute)
+
+	// the statistics of an undelivered report are included in the next one
+	_, err := reporter.Send(context.Background())
+	require.ErrorIs(t, err, telemetry.ErrReportFailed)
+	require.Contains(t, err.Error(), "unavailable")
+
+	collector.MilestoneIssued()
+	collector.MilestoneIssued()
+	now = now.Add(time.Minute)
+
+	failing = false
+	report, err := reporter.Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &telemetry.Report{
+		Version:             "1.0.0",
+		Platform:            report.Platform,
+		PeriodSeconds:       360,
+		MilestonesIssued:    12,
+		MilestonesPerMinute: 2,
+		MilestonesSkipped:   1,
+		ReceiptsIssued:      1,
+		MigratorEnabled:     true,
+	}, report)
+	require.NotEmpty(t, report.Platform)
+
+	// only the aggregate statistics are reported
+	require.Len(t, reports, 1)
+	require.ElementsMatch(t, []string{"version", "platform", "periodSeconds", "milestonesIssued", "milestonesPerMinute", "milestonesSkipped", "receiptsIssued", "migratorEnabled"}, keys(reports[0]))
+
+	// the next period starts after the delivered report
+	now = now.Add(time.Minute)
+	require.Equal(t, &telemetry.Report{
+		Version:         "1.0.0",
+		Platform:        report.Platform,
+		PeriodSeconds:   60,
+		MigratorEnabled: true,
+	}, collector.Report())
+}
+
+func keys(m map[string]any) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package testkit
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// updateGolden is set with "go test ./pkg/migrator/ -args -update-golden" to rewrite the golden files instead of comparing them.
+var updateGolden = flag.Bool("update-golden", false, "rewrite the golden files with the current output")
+
+// AssertGolden compares the data with the hex encoded content of the golden file "testdata/<name>.golden".
+// Changes of the data need to be deliberate, the golden files are only rewritten if the "-update-golden" flag is given.
+func AssertGolden(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	filePath := filepath.Join("testdata", name+".golden")
+
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o700))
+		require.NoError(t, os.WriteFile(filePath, []byte(iotago.EncodeHex(data)+"\n"), 0o600))
+
+		return
+	}
+
+	golden, err := os.ReadFile(filePath)
+	require.NoError(t, err, "golden file missing, run the test with -update-golden to create it")
+
+	expected, err := iotago.DecodeHex(strings.TrimSpace(string(golden)))
+	require.NoError(t, err)
+
+	require.Equal(t, iotago.EncodeHex(expected), iotago.EncodeHex(data), "serialization differs from golden file %s", filePath)
+}
@@ -0,0 +1,125 @@
+// Package testkit contains deterministic fixtures for the tests of the migration and receipt pipeline.
+package testkit
+
+import (
+	"encoding/binary"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/encoding/t5b1"
+	"github.com/iotaledger/iota.go/trinary"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// DefaultDeposit is the deposit of the migrations created by the MigrationsBuilder, if no other deposit is set.
+	DefaultDeposit = 1_000_000
+)
+
+// MigrationsBuilder builds sets of valid migrations.
+// The migrations are derived from the seed with BLAKE2b, so the same builder always creates the same migrations,
+// independent of the Go version or the platform.
+type MigrationsBuilder struct {
+	seed     uint64
+	count    int
+	deposit  uint64
+	deposits []uint64
+}
+
+// NewMigrationsBuilder creates a new MigrationsBuilder with the given seed.
+func NewMigrationsBuilder(seed uint64) *MigrationsBuilder {
+	return &MigrationsBuilder{
+		seed:    seed,
+		count:   1,
+		deposit: DefaultDeposit,
+	}
+}
+
+// Count sets the amount of migrations.
+func (b *MigrationsBuilder) Count(count int) *MigrationsBuilder {
+	b.count = count
+
+	return b
+}
+
+// Deposit sets the deposit of all migrations.
+func (b *MigrationsBuilder) Deposit(deposit uint64) *MigrationsBuilder {
+	b.deposit = deposit
+	b.deposits = nil
+
+	return b
+}
+
+// Deposits sets the deposits of the migrations, the amount of migrations is the amount of deposits.
+func (b *MigrationsBuilder) Deposits(deposits ...uint64) *MigrationsBuilder {
+	b.count = len(deposits)
+	b.deposits = deposits
+
+	return b
+}
+
+// Build creates the migrations.
+func (b *MigrationsBuilder) Build() []*iotago.MigratedFundsEntry {
+	entries := make([]*iotago.MigratedFundsEntry, 0, b.count)
+	for i := 0; i < b.count; i++ {
+		deposit := b.deposit
+		if b.deposits != nil {
+			deposit = b.deposits[i]
+		}
+
+		entries = append(entries, MigratedFundsEntry(b.seed, uint64(i), deposit))
+	}
+
+	return entries
+}
+
+// MigratedFundsEntry creates the migration with the given index of the given seed.
+func MigratedFundsEntry(seed uint64, index uint64, deposit uint64) *iotago.MigratedFundsEntry {
+	entry := &iotago.MigratedFundsEntry{
+		TailTransactionHash: LegacyTailTransactionHash(seed, index),
+		Address:             Ed25519Address(seed, index),
+		Deposit:             deposit,
+	}
+
+	return entry
+}
+
+// LegacyTailTransactionHash derives a valid T5B1 encoded legacy tail transaction hash from the seed and index.
+func LegacyTailTransactionHash(seed uint64, index uint64) iotago.LegacyTailTransactionHash {
+	trits := make(trinary.Trits, 0, consts.HashTrinarySize)
+	for counter := uint64(0); len(trits) < consts.HashTrinarySize; counter++ {
+		for _, b := range derive("tail", seed, index, counter) {
+			if len(trits) == consts.HashTrinarySize {
+				break
+			}
+			trits = append(trits, int8(b%3)-1)
+		}
+	}
+
+	var hash iotago.LegacyTailTransactionHash
+	t5b1.Encode(hash[:], trits)
+
+	return hash
+}
+
+// Ed25519Address derives an Ed25519 address from the seed and index.
+func Ed25519Address(seed uint64, index uint64) *iotago.Ed25519Address {
+	address := &iotago.Ed25519Address{}
+	copy(address[:], derive("address", seed, index, 0))
+
+	return address
+}
+
+// derive returns the BLAKE2b-256 hash of the domain, seed, index and counter.
+func derive(domain string, seed uint64, index uint64, counter uint64) []byte {
+	data := make([]byte, 0, len(domain)+24)
+	data = append(data, domain...)
+	data = binary.LittleEndian.AppendUint64(data, seed)
+	data = binary.LittleEndian.AppendUint64(data, index)
+	data = binary.LittleEndian.AppendUint64(data, counter)
+
+	hash := blake2b.Sum256(data)
+
+	return hash[:]
+}
@@ -0,0 +1,49 @@
+package testkit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/testkit"
+)
+
+func TestMigrationsBuilder(t *testing.T) {
+	entries := testkit.NewMigrationsBuilder(1).Count(20).Build()
+	require.Len(t, entries, 20)
+
+	tailTransactionHashes := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		require.NoError(t, migrator.VerifyMigratedFundsEntry(entry))
+		require.EqualValues(t, testkit.DefaultDeposit, entry.Deposit)
+
+		tailTransactionHashes[string(entry.TailTransactionHash[:])] = struct{}{}
+	}
+	require.Len(t, tailTransactionHashes, len(entries))
+
+	// the same seed always creates the same migrations
+	require.Equal(t, entries, testkit.NewMigrationsBuilder(1).Count(20).Build())
+	require.NotEqual(t, entries[0], testkit.NewMigrationsBuilder(2).Build()[0])
+
+	entries = testkit.NewMigrationsBuilder(1).Deposits(1_000_000, 5_000_000).Build()
+	require.Len(t, entries, 2)
+	require.EqualValues(t, 5_000_000, entries[1].Deposit)
+}
+
+func TestQueryer(t *testing.T) {
+	entries := testkit.NewMigrationsBuilder(1).Count(3).Build()
+	q := testkit.NewQueryer(4).AddMigrations(7, entries)
+
+	msIndex, migratedFunds, err := q.QueryNextMigratedFunds(context.Background(), 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, msIndex)
+	require.Equal(t, entries, migratedFunds)
+
+	// no more migrations, the latest legacy milestone index is returned
+	msIndex, migratedFunds, err = q.QueryNextMigratedFunds(context.Background(), 8)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, msIndex)
+	require.Empty(t, migratedFunds)
+}
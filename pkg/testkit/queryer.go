@@ -0,0 +1,62 @@
+package testkit
+
+import (
+	"context"
+
+	"github.com/iotaledger/hive.go/core/syncutils"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// Queryer is a fixture of the legacy node that serves fixed migrations.
+// It answers the queries the same way the legacy node validator does.
+type Queryer struct {
+	lock syncutils.RWMutex
+
+	migrations  map[iotago.MilestoneIndex][]*iotago.MigratedFundsEntry
+	latestIndex iotago.MilestoneIndex
+}
+
+// NewQueryer creates a new Queryer with the given latest legacy milestone index.
+func NewQueryer(latestIndex iotago.MilestoneIndex) *Queryer {
+	return &Queryer{
+		migrations:  make(map[iotago.MilestoneIndex][]*iotago.MigratedFundsEntry),
+		latestIndex: latestIndex,
+	}
+}
+
+// AddMigrations adds the migrations confirmed by the legacy milestone with the given index.
+// The latest legacy milestone index is increased if needed.
+func (q *Queryer) AddMigrations(msIndex iotago.MilestoneIndex, migratedFunds []*iotago.MigratedFundsEntry) *Queryer {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.migrations[msIndex] = append(q.migrations[msIndex], migratedFunds...)
+	if msIndex > q.latestIndex {
+		q.latestIndex = msIndex
+	}
+
+	return q
+}
+
+// QueryMigratedFunds returns the migrations confirmed by the legacy milestone with the given index.
+func (q *Queryer) QueryMigratedFunds(_ context.Context, msIndex iotago.MilestoneIndex) ([]*iotago.MigratedFundsEntry, error) {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	return q.migrations[msIndex], nil
+}
+
+// QueryNextMigratedFunds returns the migrations of the next legacy milestone with migrations starting from startIndex.
+// If there are no more migrations, it returns the latest legacy milestone index.
+func (q *Queryer) QueryNextMigratedFunds(_ context.Context, startIndex iotago.MilestoneIndex) (iotago.MilestoneIndex, []*iotago.MigratedFundsEntry, error) {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	for index := startIndex; index <= q.latestIndex; index++ {
+		if migratedFunds := q.migrations[index]; len(migratedFunds) > 0 {
+			return index, migratedFunds, nil
+		}
+	}
+
+	return q.latestIndex, nil, nil
+}
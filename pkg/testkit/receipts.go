@@ -0,0 +1,48 @@
+package testkit
+
+import (
+	"github.com/iotaledger/hive.go/serializer/v2"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// TreasuryAmount is the amount of the treasury that is spent by the receipts created by CompleteReceipt.
+	TreasuryAmount = 2_779_530_283_277_761
+)
+
+// ProtocolParameters returns fixed protocol parameters for the serialization of blocks and receipts.
+func ProtocolParameters() *iotago.ProtocolParameters {
+	return &iotago.ProtocolParameters{
+		Version:       2,
+		NetworkName:   "testnet",
+		Bech32HRP:     iotago.PrefixTestnet,
+		MinPoWScore:   0,
+		BelowMaxDepth: 15,
+		RentStructure: iotago.RentStructure{
+			VByteCost:    500,
+			VBFactorData: 1,
+			VBFactorKey:  10,
+		},
+		TokenSupply: TreasuryAmount,
+	}
+}
+
+// CompleteReceipt adds the treasury transaction to the receipt and sorts its funds, the same way the coordinator does
+// before the receipt is embedded into a milestone. The treasury input is derived from the seed.
+func CompleteReceipt(receipt *iotago.ReceiptMilestoneOpt, seed uint64) *iotago.ReceiptMilestoneOpt {
+	input := &iotago.TreasuryInput{}
+	copy(input[:], derive("treasury", seed, 0, 0))
+
+	receipt.Transaction = &iotago.TreasuryTransaction{
+		Input:  input,
+		Output: &iotago.TreasuryOutput{Amount: TreasuryAmount - receipt.Sum()},
+	}
+	receipt.SortFunds()
+
+	return receipt
+}
+
+// SerializeReceipt returns the binary serialization of the receipt with full validation.
+func SerializeReceipt(receipt *iotago.ReceiptMilestoneOpt) ([]byte, error) {
+	return receipt.Serialize(serializer.DeSeriModePerformValidation, ProtocolParameters())
+}
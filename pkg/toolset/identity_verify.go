@@ -0,0 +1,146 @@
+package toolset
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/iotaledger/hive.go/core/configuration"
+	"github.com/iotaledger/hive.go/core/crypto"
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
+	"github.com/iotaledger/inx-coordinator/pkg/journal"
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	FlagToolSignedDocumentFilePath = "document"
+	FlagToolIdentityPublicKey      = "publicKey"
+	FlagToolJournalSegmentFilePath = "segment"
+)
+
+// verifyResult is the result of the verification of a signed document.
+type verifyResult struct {
+	Kind      string `json:"kind"`
+	Timestamp int64  `json:"timestamp"`
+	PublicKey string `json:"publicKey"`
+	// the verified archive of the event journal, if the document is the signature of a journal segment.
+	Segment *journal.Segment `json:"segment,omitempty"`
+}
+
+func verify(args []string) error {
+
+	fs := configuration.NewUnsortedFlagSet("", flag.ContinueOnError)
+	documentFilePathFlag := fs.String(FlagToolSignedDocumentFilePath, "", "the path to the signed document, e.g. a saved signed status report or the signature of an archive of the event journal")
+	publicKeyFlag := fs.String(FlagToolIdentityPublicKey, "", "the public key of the identity of the coordinator instance (hex)")
+	segmentFilePathFlag := fs.String(FlagToolJournalSegmentFilePath, "", "the path to the archive of the event journal, if the document is its signature (default: next to the signature)")
+	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolVerify)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s --%s %s",
+			ToolVerify,
+			FlagToolSignedDocumentFilePath,
+			"events.journal.1-4096.sig",
+			FlagToolIdentityPublicKey,
+			"0x9f1c...",
+		))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if *documentFilePathFlag == "" {
+		return fmt.Errorf("'%s' not specified", FlagToolSignedDocumentFilePath)
+	}
+	if *publicKeyFlag == "" {
+		return fmt.Errorf("'%s' not specified", FlagToolIdentityPublicKey)
+	}
+
+	publicKeyBytes, err := crypto.ParseEd25519PublicKeyFromString(*publicKeyFlag)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	var publicKey iotago.MilestonePublicKey
+	copy(publicKey[:], publicKeyBytes)
+
+	signed := &identity.SignedDocument{}
+	if err := ioutils.ReadJSONFromFile(*documentFilePathFlag, signed); err != nil {
+		return fmt.Errorf("unable to read signed document: %w", err)
+	}
+
+	if err := signed.Verify(signed.Kind, publicKey); err != nil {
+		return err
+	}
+
+	result := &verifyResult{
+		Kind:      signed.Kind,
+		Timestamp: signed.Timestamp,
+		PublicKey: iotago.EncodeHex(publicKey[:]),
+	}
+
+	// the signature of a journal segment only covers the hash, so the archive itself has to match it
+	if signed.Kind == identity.KindJournalSegment {
+		if result.Segment, err = verifyJournalSegment(signed, *documentFilePathFlag, *segmentFilePathFlag); err != nil {
+			return err
+		}
+	}
+
+	if *outputJSONFlag {
+		return printJSON(result)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Kind:\t%s\n", result.Kind)
+	fmt.Fprintf(w, "Signed at:\t%s\n", time.Unix(result.Timestamp, 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Public key:\t%s\n", result.PublicKey)
+	if result.Segment != nil {
+		fmt.Fprintf(w, "Segment:\t%s\n", result.Segment.Name)
+		fmt.Fprintf(w, "Sequences:\t%d-%d\n", result.Segment.FirstSequence, result.Segment.LastSequence)
+	}
+	fmt.Fprintf(w, "Valid:\t%s\n", yesOrNo(true))
+
+	return nil
+}
+
+// verifyJournalSegment checks that the archive of the event journal matches its signed hash.
+// If no path is given, the archive is expected next to its signature, either compressed or not.
+func verifyJournalSegment(signed *identity.SignedDocument, signatureFilePath string, segmentFilePath string) (*journal.Segment, error) {
+	segment := &journal.Segment{}
+	if err := json.Unmarshal(signed.Document, segment); err != nil {
+		return nil, fmt.Errorf("invalid journal segment: %w", err)
+	}
+
+	if segmentFilePath == "" {
+		segmentFilePath = filepath.Join(filepath.Dir(signatureFilePath), segment.Name)
+		if _, err := os.Stat(segmentFilePath); err != nil {
+			segmentFilePath += retention.CompressedExtension
+		}
+	}
+
+	if strings.TrimSuffix(filepath.Base(segmentFilePath), retention.CompressedExtension) != segment.Name {
+		return nil, fmt.Errorf("%w: the signature belongs to %s", identity.ErrInvalidSignature, segment.Name)
+	}
+
+	hash, err := journal.HashSegment(segmentFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash journal segment: %w", err)
+	}
+
+	if hash != segment.Hash {
+		return nil, fmt.Errorf("%w: the journal segment was modified, hash %s, signed %s", identity.ErrInvalidSignature, hash, segment.Hash)
+	}
+
+	return segment, nil
+}
@@ -0,0 +1,452 @@
+package toolset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	flag "github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/core/configuration"
+	"github.com/iotaledger/hive.go/core/ioutils"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	inx "github.com/iotaledger/inx/go"
+	"github.com/iotaledger/iota.go/address"
+	legacyapi "github.com/iotaledger/iota.go/api"
+	"github.com/iotaledger/iota.go/bundle"
+	legacy "github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/encoding/t5b1"
+	"github.com/iotaledger/iota.go/trinary"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/nodeclient"
+)
+
+const (
+	// MigrationCommandTrace is the migration command that traces the migrations of legacy addresses through the migration pipeline.
+	MigrationCommandTrace = "trace"
+
+	FlagToolAddresses             = "addresses"
+	FlagToolAddressesFile         = "addressesFile"
+	FlagToolLegacyNodeURL         = "legacyNodeURL"
+	FlagToolNodeURL               = "nodeURL"
+	FlagToolINXAddress            = "inxAddress"
+	FlagToolIncludedHashesFile    = "includedHashesFile"
+	FlagToolMigrationsCacheFolder = "migrationsCacheFolder"
+
+	DefaultValueLegacyNodeURL = "http://localhost:14266"
+	DefaultValueNodeURL       = "http://localhost:14265"
+	DefaultValueINXAddress    = "localhost:9029"
+)
+
+var (
+	// ErrMigrationsIncomplete is returned when migrations that were confirmed by the legacy network were not credited yet.
+	ErrMigrationsIncomplete = errors.New("migrations were not credited yet")
+)
+
+// migrationTraceEntry is the trace of a migration bundle of a legacy address.
+type migrationTraceEntry struct {
+	TailTransactionHash string `json:"tailTransactionHash"`
+	Address             string `json:"address"`
+	Deposit             uint64 `json:"deposit"`
+	// whether the bundle was confirmed by the legacy network.
+	ConfirmedOnLegacy bool `json:"confirmedOnLegacy"`
+	// whether the migration was fetched by the migrator.
+	Fetched bool `json:"fetched"`
+	// whether the migration was included in a receipt by the coordinator.
+	Receipted bool `json:"receipted"`
+	// whether the receipt that contains the migration was confirmed by the network.
+	Confirmed bool `json:"confirmed"`
+	// whether the output of the migration exists in the ledger of the network.
+	Credited       bool   `json:"credited"`
+	MigratedAt     uint32 `json:"migratedAt,omitempty"`
+	MilestoneIndex uint32 `json:"milestoneIndex,omitempty"`
+	OutputID       string `json:"outputId,omitempty"`
+	Spent          bool   `json:"spent"`
+}
+
+// migrationTraceAddress are the traces of the migrations of a legacy address.
+type migrationTraceAddress struct {
+	LegacyAddress string                 `json:"legacyAddress"`
+	Migrations    []*migrationTraceEntry `json:"migrations"`
+}
+
+// migrationTraceResult is the JSON output of the migration trace command.
+type migrationTraceResult struct {
+	Addresses []*migrationTraceAddress `json:"addresses"`
+}
+
+// receiptedMigration is the position of a migration in a confirmed receipt.
+type receiptedMigration struct {
+	migratedAt     iotago.MilestoneIndex
+	milestoneIndex iotago.MilestoneIndex
+	outputIndex    uint16
+}
+
+func migration(args []string) error {
+	commands := map[string]func([]string) error{
+		MigrationCommandTrace: migrationTrace,
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("no %s command given, available commands: %s", ToolMigration, MigrationCommandTrace)
+	}
+
+	command, exists := commands[strings.ToLower(args[0])]
+	if !exists {
+		return fmt.Errorf("unknown %s command '%s', available commands: %s", ToolMigration, args[0], MigrationCommandTrace)
+	}
+
+	return command(args[1:])
+}
+
+func migrationTrace(args []string) error {
+
+	fs := configuration.NewUnsortedFlagSet("", flag.ContinueOnError)
+	addressesFlag := fs.StringSlice(FlagToolAddresses, nil, "the legacy addresses whose migrations are traced")
+	addressesFileFlag := fs.String(FlagToolAddressesFile, "", "the path to a file with the legacy addresses whose migrations are traced, one per line")
+	legacyNodeURLFlag := fs.String(FlagToolLegacyNodeURL, DefaultValueLegacyNodeURL, "the URL of the API of the legacy node")
+	nodeURLFlag := fs.String(FlagToolNodeURL, DefaultValueNodeURL, "the URL of the REST API of the node, used to query the receipts")
+	inxAddressFlag := fs.String(FlagToolINXAddress, DefaultValueINXAddress, "the INX address of the node, used to query the milestones and outputs")
+	includedHashesFileFlag := fs.String(FlagToolIncludedHashesFile, "", "the path to the included tail transaction hashes file of the migrator (optional)")
+	migrationsCacheFolderFlag := fs.String(FlagToolMigrationsCacheFolder, "", "the path to the migrations cache folder of the migrator (optional)")
+	timeoutFlag := fs.Duration(FlagToolTimeout, DefaultValueTimeout, "the timeout of the API calls")
+	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage of %s %s:\n", ToolMigration, MigrationCommandTrace)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s %s --%s %s --%s %s --%s %s",
+			ToolMigration,
+			MigrationCommandTrace,
+			FlagToolAddressesFile,
+			"addresses.txt",
+			FlagToolIncludedHashesFile,
+			"migrator_included_hashes.bin",
+			FlagToolMigrationsCacheFolder,
+			"migrator_cache",
+		))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	legacyAddresses, err := loadLegacyAddresses(*addressesFlag, *addressesFileFlag)
+	if err != nil {
+		return err
+	}
+
+	// the migrations fetched by the migrator, by their tail transaction hash
+	fetched := make(map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex)
+	if *migrationsCacheFolderFlag != "" {
+		if fetched, err = loadCachedMigrations(*migrationsCacheFolderFlag); err != nil {
+			return err
+		}
+	}
+
+	var includedHashes *migrator.IncludedHashes
+	if *includedHashesFileFlag != "" {
+		// a missing file would be treated as an empty set, which hides a wrong path
+		if _, err := os.Stat(*includedHashesFileFlag); err != nil {
+			return fmt.Errorf("unable to open the included tail transaction hashes file: %w", err)
+		}
+
+		if includedHashes, err = migrator.LoadIncludedHashes(*includedHashesFileFlag); err != nil {
+			return err
+		}
+	}
+
+	legacyAPI, err := legacyapi.ComposeAPI(legacyapi.HTTPClientSettings{
+		URI:    *legacyNodeURLFlag,
+		Client: &http.Client{Timeout: *timeoutFlag},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to initialize the legacy API: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+	defer cancel()
+
+	receipts, err := nodeclient.New(*nodeURLFlag, nodeclient.WithHTTPClient(&http.Client{Timeout: *timeoutFlag})).Receipts(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to query the receipts of the node: %w", err)
+	}
+
+	// the migrations in receipts confirmed by the network, by their tail transaction hash
+	receipted := make(map[iotago.LegacyTailTransactionHash]*receiptedMigration)
+	for _, receiptTuple := range receipts {
+		for i, entry := range receiptTuple.Receipt.Funds {
+			receipted[entry.TailTransactionHash] = &receiptedMigration{
+				migratedAt:     receiptTuple.Receipt.MigratedAt,
+				milestoneIndex: receiptTuple.MilestoneIndex,
+				outputIndex:    uint16(i),
+			}
+		}
+	}
+
+	conn, err := grpc.Dial(*inxAddressFlag, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("unable to connect to INX: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	client := inx.NewINXClient(conn)
+
+	result := &migrationTraceResult{
+		Addresses: make([]*migrationTraceAddress, 0, len(legacyAddresses)),
+	}
+
+	incomplete := 0
+	for _, legacyAddress := range legacyAddresses {
+		bundles, err := legacyAPI.GetBundlesFromAddresses(trinary.Hashes{legacyAddress}, true)
+		if err != nil {
+			return fmt.Errorf("unable to query the bundles of legacy address %s: %w", legacyAddress, err)
+		}
+
+		traced := &migrationTraceAddress{
+			LegacyAddress: legacyAddress,
+			Migrations:    make([]*migrationTraceEntry, 0),
+		}
+
+		for _, bndl := range bundles {
+			entry, isMigration := migrationBundleEntry(bndl, legacyAddress)
+			if !isMigration {
+				continue
+			}
+
+			trace := &migrationTraceEntry{
+				TailTransactionHash: iotago.EncodeHex(entry.TailTransactionHash[:]),
+				Address:             entry.Address.String(),
+				Deposit:             entry.Deposit,
+				ConfirmedOnLegacy:   bndl[0].Persistence != nil && *bndl[0].Persistence,
+			}
+
+			if migratedAt, exists := fetched[entry.TailTransactionHash]; exists {
+				trace.Fetched = true
+				trace.MigratedAt = migratedAt
+			}
+
+			if includedHashes != nil {
+				if migratedAt, exists := includedHashes.MigratedAt(entry.TailTransactionHash); exists {
+					trace.Receipted = true
+					trace.MigratedAt = migratedAt
+				}
+			}
+
+			if position, exists := receipted[entry.TailTransactionHash]; exists {
+				trace.Confirmed = true
+				trace.MigratedAt = position.migratedAt
+				trace.MilestoneIndex = position.milestoneIndex
+
+				if err := traceMigrationOutput(ctx, client, position, trace); err != nil {
+					return fmt.Errorf("unable to trace the output of tail transaction hash %s: %w", trace.TailTransactionHash, err)
+				}
+			}
+
+			// the later stages of the pipeline imply the earlier ones, even if the files of the migrator were not given
+			trace.Receipted = trace.Receipted || trace.Confirmed
+			trace.Fetched = trace.Fetched || trace.Receipted
+
+			if trace.ConfirmedOnLegacy && !trace.Credited {
+				incomplete++
+			}
+
+			traced.Migrations = append(traced.Migrations, trace)
+		}
+
+		result.Addresses = append(result.Addresses, traced)
+	}
+
+	if *outputJSONFlag {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printMigrationTrace(result)
+	}
+
+	if incomplete > 0 {
+		return fmt.Errorf("%w: %d migrations", ErrMigrationsIncomplete, incomplete)
+	}
+
+	return nil
+}
+
+// loadLegacyAddresses returns the legacy addresses given via flag and in the file, without their checksums.
+func loadLegacyAddresses(addresses []string, filePath string) (trinary.Hashes, error) {
+	if filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the addresses file: %w", err)
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				addresses = append(addresses, line)
+			}
+		}
+	}
+
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("neither '%s' nor '%s' specified", FlagToolAddresses, FlagToolAddressesFile)
+	}
+
+	legacyAddresses := make(trinary.Hashes, 0, len(addresses))
+	for _, addr := range addresses {
+		if len(addr) == legacy.AddressWithChecksumTrytesSize {
+			if err := address.ValidChecksum(addr[:legacy.HashTrytesSize], addr[legacy.HashTrytesSize:]); err != nil {
+				return nil, fmt.Errorf("invalid legacy address %s: %w", addr, err)
+			}
+			addr = addr[:legacy.HashTrytesSize]
+		}
+
+		if err := address.ValidAddress(addr); err != nil {
+			return nil, fmt.Errorf("invalid legacy address %s: %w", addr, err)
+		}
+		legacyAddresses = append(legacyAddresses, addr)
+	}
+
+	return legacyAddresses, nil
+}
+
+// loadCachedMigrations returns the index of the legacy milestone of all migrations in the migrations cache folder.
+func loadCachedMigrations(folderPath string) (map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex, error) {
+	files, err := os.ReadDir(folderPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the migrations cache folder: %w", err)
+	}
+
+	cached := make(map[iotago.LegacyTailTransactionHash]iotago.MilestoneIndex)
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		msIndex, err := strconv.ParseUint(strings.TrimSuffix(name, ".json"), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		migratedFunds := []*iotago.MigratedFundsEntry{}
+		if err := ioutils.ReadJSONFromFile(filepath.Join(folderPath, name), &migratedFunds); err != nil {
+			return nil, fmt.Errorf("unable to read the cached migrations of legacy milestone %d: %w", msIndex, err)
+		}
+
+		for _, entry := range migratedFunds {
+			cached[entry.TailTransactionHash] = iotago.MilestoneIndex(msIndex)
+		}
+	}
+
+	return cached, nil
+}
+
+// migrationBundleEntry returns the migration of the bundle if it migrates funds of the legacy address to a migration address.
+func migrationBundleEntry(bndl bundle.Bundle, legacyAddress trinary.Hash) (*iotago.MigratedFundsEntry, bool) {
+	if len(bndl) == 0 || bndl[0].Value <= 0 {
+		return nil, false
+	}
+
+	edAddr, err := address.ParseMigrationAddress(bndl[0].Address)
+	if err != nil {
+		return nil, false
+	}
+
+	spendsLegacyAddress := false
+	for i := range bndl {
+		if bndl[i].Value < 0 && bndl[i].Address == legacyAddress {
+			spendsLegacyAddress = true
+
+			break
+		}
+	}
+	if !spendsLegacyAddress {
+		return nil, false
+	}
+
+	entry := &iotago.MigratedFundsEntry{
+		Address: (*iotago.Ed25519Address)(&edAddr),
+		Deposit: uint64(bndl[0].Value),
+	}
+	copy(entry.TailTransactionHash[:], t5b1.EncodeTrytes(bundle.TailTransactionHash(bndl)))
+
+	return entry, true
+}
+
+// traceMigrationOutput checks whether the output created by the receipt for the migration exists in the ledger of the node.
+// The outputs of a receipt are created in the order of its migrations, with the milestone ID as the transaction ID.
+func traceMigrationOutput(ctx context.Context, client inx.INXClient, position *receiptedMigration, trace *migrationTraceEntry) error {
+	milestone, err := client.ReadMilestone(ctx, &inx.MilestoneRequest{MilestoneIndex: position.milestoneIndex})
+	if err != nil {
+		return fmt.Errorf("unable to read milestone %d: %w", position.milestoneIndex, err)
+	}
+
+	milestoneID := milestone.GetMilestoneInfo().GetMilestoneId().Unwrap()
+	outputID := iotago.OutputIDFromTransactionIDAndIndex(iotago.TransactionID(milestoneID), position.outputIndex)
+	trace.OutputID = outputID.ToHex()
+
+	output, err := client.ReadOutput(ctx, inx.NewOutputId(outputID))
+	if err != nil {
+		if grpcstatus.Code(err) == codes.NotFound {
+			return nil
+		}
+
+		return err
+	}
+
+	trace.Credited = output.GetOutput() != nil || output.GetSpent() != nil
+	trace.Spent = output.GetSpent() != nil
+
+	return nil
+}
+
+// printMigrationTrace prints the traces of the migrations as a human-readable table.
+func printMigrationTrace(result *migrationTraceResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	for _, traced := range result.Addresses {
+		fmt.Fprintf(w, "LEGACY ADDRESS %s (%d migrations)\n", traced.LegacyAddress, len(traced.Migrations))
+		if len(traced.Migrations) == 0 {
+			continue
+		}
+
+		sort.SliceStable(traced.Migrations, func(i, j int) bool {
+			return traced.Migrations[i].MigratedAt < traced.Migrations[j].MigratedAt
+		})
+
+		fmt.Fprintln(w, "  TAIL TRANSACTION HASH\tDEPOSIT\tLEGACY CONFIRMED\tFETCHED\tRECEIPTED\tCONFIRMED\tCREDITED\tMIGRATED AT\tMILESTONE")
+		for _, trace := range traced.Migrations {
+			fmt.Fprintf(w, "  %s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				trace.TailTransactionHash,
+				trace.Deposit,
+				yesOrNo(trace.ConfirmedOnLegacy),
+				yesOrNo(trace.Fetched),
+				yesOrNo(trace.Receipted),
+				yesOrNo(trace.Confirmed),
+				yesOrNo(trace.Credited),
+				formatIndex(trace.MigratedAt),
+				formatIndex(trace.MilestoneIndex),
+			)
+		}
+	}
+}
+
+// formatIndex formats a milestone index, 0 is unknown.
+func formatIndex(index uint32) string {
+	if index == 0 {
+		return "-"
+	}
+
+	return strconv.FormatUint(uint64(index), 10)
+}
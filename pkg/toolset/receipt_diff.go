@@ -0,0 +1,312 @@
+package toolset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	flag "github.com/spf13/pflag"
+
+	"github.com/iotaledger/hive.go/core/configuration"
+	"github.com/iotaledger/hive.go/core/ioutils"
+	validator "github.com/iotaledger/hornet/v2/pkg/model/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	legacyapi "github.com/iotaledger/iota.go/api"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/nodeclient"
+)
+
+const (
+	// ReceiptCommandDiff is the receipt command that compares the migrations of a legacy milestone from two sources.
+	ReceiptCommandDiff = "diff"
+
+	// ReceiptSourceNode are the receipts in the database of a node, queried via its REST API.
+	ReceiptSourceNode = "node"
+	// ReceiptSourceProofs are the receipts in the inclusion proofs stored by the coordinator.
+	ReceiptSourceProofs = "proofs"
+	// ReceiptSourceLegacy are the migrations queried from the legacy node.
+	ReceiptSourceLegacy = "legacy"
+	// ReceiptSourceFile are the migrations in a JSON file, e.g. a file of the migrations cache of the migrator.
+	ReceiptSourceFile = "file"
+
+	FlagToolMigratedAt                  = "migratedAt"
+	FlagToolLeftSource                  = "left"
+	FlagToolRightSource                 = "right"
+	FlagToolLegacyCoordinatorAddress    = "legacyCoordinatorAddress"
+	FlagToolLegacyCoordinatorMerkleTree = "legacyCoordinatorMerkleTreeDepth"
+	FlagToolTimeout                     = "timeout"
+
+	DefaultValueLegacyCoordinatorAddress    = "UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ"
+	DefaultValueLegacyCoordinatorMerkleTree = 24
+	DefaultValueTimeout                     = 30 * time.Second
+)
+
+var (
+	// ErrReceiptsDiffer is returned when the compared sources contain different migrations.
+	ErrReceiptsDiffer = errors.New("the migrations of the sources differ")
+)
+
+// receiptSourceLoader loads the migrations of a legacy milestone from the location of a source.
+type receiptSourceLoader func(ctx context.Context, location string, migratedAt iotago.MilestoneIndex, opts *receiptSourceOptions) ([]*iotago.MigratedFundsEntry, error)
+
+// receiptSourceOptions are the options of the sources that are shared by both sides of the comparison.
+type receiptSourceOptions struct {
+	legacyCoordinatorAddress    string
+	legacyCoordinatorMerkleTree int
+	timeout                     time.Duration
+}
+
+// receiptSourceLoaders contains the loaders of all supported sources.
+var receiptSourceLoaders = map[string]receiptSourceLoader{
+	ReceiptSourceNode:   loadNodeMigrations,
+	ReceiptSourceProofs: loadProofsMigrations,
+	ReceiptSourceLegacy: loadLegacyMigrations,
+	ReceiptSourceFile:   loadFileMigrations,
+}
+
+// receiptDiffEntry is a migration that differs between the sources.
+type receiptDiffEntry struct {
+	TailTransactionHash string                     `json:"tailTransactionHash"`
+	Kind                string                     `json:"kind"`
+	Left                *iotago.MigratedFundsEntry `json:"left,omitempty"`
+	Right               *iotago.MigratedFundsEntry `json:"right,omitempty"`
+}
+
+// receiptDiffResult is the JSON output of the receipt diff command.
+type receiptDiffResult struct {
+	MigratedAt    uint32              `json:"migratedAt"`
+	LeftSource    string              `json:"leftSource"`
+	RightSource   string              `json:"rightSource"`
+	LeftCount     int                 `json:"leftCount"`
+	RightCount    int                 `json:"rightCount"`
+	MatchingCount int                 `json:"matchingCount"`
+	Differences   []*receiptDiffEntry `json:"differences"`
+}
+
+func receipt(args []string) error {
+	commands := map[string]func([]string) error{
+		ReceiptCommandDiff: receiptDiff,
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("no %s command given, available commands: %s", ToolReceipt, ReceiptCommandDiff)
+	}
+
+	command, exists := commands[strings.ToLower(args[0])]
+	if !exists {
+		return fmt.Errorf("unknown %s command '%s', available commands: %s", ToolReceipt, args[0], ReceiptCommandDiff)
+	}
+
+	return command(args[1:])
+}
+
+func receiptDiff(args []string) error {
+
+	fs := configuration.NewUnsortedFlagSet("", flag.ContinueOnError)
+	migratedAtFlag := fs.Uint32(FlagToolMigratedAt, 0, "the index of the legacy milestone whose migrations are compared")
+	leftSourceFlag := fs.String(FlagToolLeftSource, "", "the left source of the migrations (<node|proofs|legacy|file>:<URL or path>)")
+	rightSourceFlag := fs.String(FlagToolRightSource, "", "the right source of the migrations (<node|proofs|legacy|file>:<URL or path>)")
+	legacyCoordinatorAddressFlag := fs.String(FlagToolLegacyCoordinatorAddress, DefaultValueLegacyCoordinatorAddress, "the address of the legacy coordinator, used by legacy sources")
+	legacyCoordinatorMerkleTreeFlag := fs.Int(FlagToolLegacyCoordinatorMerkleTree, DefaultValueLegacyCoordinatorMerkleTree, "the depth of the Merkle tree of the legacy coordinator, used by legacy sources")
+	timeoutFlag := fs.Duration(FlagToolTimeout, DefaultValueTimeout, "the timeout of the API calls of node and legacy sources")
+	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage of %s %s:\n", ToolReceipt, ReceiptCommandDiff)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s %s --%s %d --%s %s --%s %s",
+			ToolReceipt,
+			ReceiptCommandDiff,
+			FlagToolMigratedAt,
+			3864200,
+			FlagToolLeftSource,
+			"proofs:receipts",
+			FlagToolRightSource,
+			"node:http://localhost:14265",
+		))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if *migratedAtFlag == 0 {
+		return fmt.Errorf("'%s' not specified", FlagToolMigratedAt)
+	}
+
+	opts := &receiptSourceOptions{
+		legacyCoordinatorAddress:    *legacyCoordinatorAddressFlag,
+		legacyCoordinatorMerkleTree: *legacyCoordinatorMerkleTreeFlag,
+		timeout:                     *timeoutFlag,
+	}
+
+	left, err := loadReceiptSource(context.Background(), *leftSourceFlag, *migratedAtFlag, opts)
+	if err != nil {
+		return fmt.Errorf("unable to load the left source: %w", err)
+	}
+
+	right, err := loadReceiptSource(context.Background(), *rightSourceFlag, *migratedAtFlag, opts)
+	if err != nil {
+		return fmt.Errorf("unable to load the right source: %w", err)
+	}
+
+	diff := migrator.DiffMigratedFunds(left, right)
+
+	result := &receiptDiffResult{
+		MigratedAt:    *migratedAtFlag,
+		LeftSource:    *leftSourceFlag,
+		RightSource:   *rightSourceFlag,
+		LeftCount:     diff.LeftCount,
+		RightCount:    diff.RightCount,
+		MatchingCount: diff.MatchingCount,
+		Differences:   make([]*receiptDiffEntry, 0, len(diff.Differences)),
+	}
+	for _, difference := range diff.Differences {
+		result.Differences = append(result.Differences, &receiptDiffEntry{
+			TailTransactionHash: iotago.EncodeHex(difference.TailTransactionHash[:]),
+			Kind:                difference.Kind,
+			Left:                difference.Left,
+			Right:               difference.Right,
+		})
+	}
+
+	if *outputJSONFlag {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printReceiptDiff(result)
+	}
+
+	if !diff.Equal() {
+		return fmt.Errorf("%w: %d differences", ErrReceiptsDiffer, len(diff.Differences))
+	}
+
+	return nil
+}
+
+// loadReceiptSource loads the migrations of a legacy milestone from a source in the format <kind>:<location>.
+func loadReceiptSource(ctx context.Context, source string, migratedAt iotago.MilestoneIndex, opts *receiptSourceOptions) ([]*iotago.MigratedFundsEntry, error) {
+	kind, location, found := strings.Cut(source, ":")
+	if !found || location == "" {
+		return nil, fmt.Errorf("invalid source '%s', expected <kind>:<location>", source)
+	}
+
+	loader, exists := receiptSourceLoaders[strings.ToLower(kind)]
+	if !exists {
+		return nil, fmt.Errorf("unknown source kind '%s'", kind)
+	}
+
+	return loader(ctx, location, migratedAt, opts)
+}
+
+func loadNodeMigrations(ctx context.Context, location string, migratedAt iotago.MilestoneIndex, opts *receiptSourceOptions) ([]*iotago.MigratedFundsEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	receipts, err := nodeclient.New(location, nodeclient.WithHTTPClient(&http.Client{Timeout: opts.timeout})).ReceiptsByMigratedAtIndex(ctx, migratedAt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query the receipts of the node: %w", err)
+	}
+
+	migratedFunds := make([]*iotago.MigratedFundsEntry, 0)
+	for _, receiptTuple := range receipts {
+		migratedFunds = append(migratedFunds, receiptTuple.Receipt.Funds...)
+	}
+
+	return migratedFunds, nil
+}
+
+func loadProofsMigrations(_ context.Context, location string, migratedAt iotago.MilestoneIndex, _ *receiptSourceOptions) ([]*iotago.MigratedFundsEntry, error) {
+	// the store would create a missing folder, which hides a wrong path
+	if _, err := os.Stat(location); err != nil {
+		return nil, fmt.Errorf("unable to open the receipt proofs folder: %w", err)
+	}
+
+	store, err := coordinator.NewReceiptProofStore(location)
+	if err != nil {
+		return nil, err
+	}
+
+	proofs, err := store.Proofs()
+	if err != nil {
+		return nil, err
+	}
+
+	migratedFunds := make([]*iotago.MigratedFundsEntry, 0)
+	for _, proof := range proofs {
+		receipt, err := proof.Receipt()
+		if err != nil {
+			return nil, fmt.Errorf("milestone %d: %w", proof.MilestoneIndex, err)
+		}
+
+		if receipt.MigratedAt == migratedAt {
+			migratedFunds = append(migratedFunds, receipt.Funds...)
+		}
+	}
+
+	return migratedFunds, nil
+}
+
+func loadLegacyMigrations(_ context.Context, location string, migratedAt iotago.MilestoneIndex, opts *receiptSourceOptions) ([]*iotago.MigratedFundsEntry, error) {
+	legacyAPI, err := legacyapi.ComposeAPI(legacyapi.HTTPClientSettings{
+		URI:    location,
+		Client: &http.Client{Timeout: opts.timeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize the legacy API: %w", err)
+	}
+
+	migratedFunds, err := validator.NewValidator(legacyAPI, opts.legacyCoordinatorAddress, opts.legacyCoordinatorMerkleTree).QueryMigratedFunds(migratedAt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query the migrations of the legacy node: %w", err)
+	}
+
+	return migratedFunds, nil
+}
+
+func loadFileMigrations(_ context.Context, location string, _ iotago.MilestoneIndex, _ *receiptSourceOptions) ([]*iotago.MigratedFundsEntry, error) {
+	migratedFunds := []*iotago.MigratedFundsEntry{}
+	if err := ioutils.ReadJSONFromFile(location, &migratedFunds); err != nil {
+		return nil, fmt.Errorf("unable to read the migrations file: %w", err)
+	}
+
+	return migratedFunds, nil
+}
+
+// printReceiptDiff prints the comparison of the sources as a human-readable table.
+func printReceiptDiff(result *receiptDiffResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "LEGACY MILESTONE %d\n", result.MigratedAt)
+	fmt.Fprintf(w, "  Left:\t%s (%d migrations)\n", result.LeftSource, result.LeftCount)
+	fmt.Fprintf(w, "  Right:\t%s (%d migrations)\n", result.RightSource, result.RightCount)
+	fmt.Fprintf(w, "  Matching:\t%d\n", result.MatchingCount)
+	fmt.Fprintf(w, "  Equal:\t%s\n", yesOrNo(len(result.Differences) == 0))
+
+	if len(result.Differences) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "DIFFERENCES (%d)\n", len(result.Differences))
+	fmt.Fprintln(w, "  TAIL TRANSACTION HASH\tKIND\tLEFT\tRIGHT")
+	for _, difference := range result.Differences {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", difference.TailTransactionHash, difference.Kind, formatMigration(difference.Left), formatMigration(difference.Right))
+	}
+}
+
+// formatMigration formats the deposit and the address of a migration.
+func formatMigration(entry *iotago.MigratedFundsEntry) string {
+	if entry == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d to %s", entry.Deposit, entry.Address.String())
+}
@@ -0,0 +1,142 @@
+package toolset
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/iotaledger/hive.go/core/configuration"
+	"github.com/iotaledger/hive.go/core/generics/options"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/client"
+)
+
+const (
+	// the maximum amount of soft errors shown in the human-readable output.
+	maxPrintedSoftErrors = 10
+)
+
+func status(args []string) error {
+	return runStatus(args, os.Stdout, time.Now())
+}
+
+// runStatus queries the status of the coordinator and writes it to w.
+// The times in the human-readable output are relative to now.
+func runStatus(args []string, w io.Writer, now time.Time) error {
+
+	fs := configuration.NewUnsortedFlagSet("", flag.ContinueOnError)
+	coordinatorURLFlag := fs.String(FlagToolCoordinatorURL, DefaultValueCoordinatorURL, "URL of the coordinator API (optional)")
+	bearerTokenFlag := fs.String(FlagToolBearerToken, "", "bearer token, if the coordinator API is accessed via the node API (optional)")
+	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolStatus)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s",
+			ToolStatus,
+			FlagToolCoordinatorURL,
+			"http://localhost:14265/api/coordinator/v1",
+		))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	var opts []options.Option[client.Client]
+	if *bearerTokenFlag != "" {
+		opts = append(opts, client.WithBearerToken(*bearerTokenFlag))
+	}
+
+	status, err := client.New(*coordinatorURLFlag, opts...).Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to query the coordinator status: %w", err)
+	}
+
+	if *outputJSONFlag {
+		return writeJSON(w, status)
+	}
+
+	writeStatus(w, status, now)
+
+	return nil
+}
+
+// writeStatus writes the status of the coordinator as a human-readable table to out.
+func writeStatus(out io.Writer, status *api.StatusResponse, now time.Time) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	coo := status.Coordinator
+	sinceLastMilestone := now.Sub(time.Unix(coo.LatestMilestoneTimestamp, 0)).Truncate(time.Second)
+	interval := time.Duration(coo.IntervalMilliseconds) * time.Millisecond
+
+	fmt.Fprintln(w, "COORDINATOR")
+	fmt.Fprintf(w, "  Latest milestone index:\t%d\n", coo.LatestMilestoneIndex)
+	fmt.Fprintf(w, "  Latest milestone ID:\t%s\n", coo.LatestMilestoneID)
+	fmt.Fprintf(w, "  Time since last milestone:\t%v (interval %v)\n", sinceLastMilestone, interval)
+	fmt.Fprintf(w, "  Pending receipts:\t%d\n", coo.PendingReceipts)
+	if coo.ReceiptPause != nil {
+		fmt.Fprintf(w, "  Receipts paused:\tsince milestone %d, %s\n", coo.ReceiptPause.Index, coo.ReceiptPause.Reason)
+	}
+
+	if status.Migrator != nil {
+		fmt.Fprintln(w, "MIGRATOR")
+		fmt.Fprintf(w, "  Latest migrated at index:\t%d\n", status.Migrator.LatestMigratedAtIndex)
+		fmt.Fprintf(w, "  Latest included index:\t%d\n", status.Migrator.LatestIncludedIndex)
+		fmt.Fprintf(w, "  Sending receipt:\t%s\n", yesOrNo(status.Migrator.SendingReceipt))
+		if window := status.Migrator.SendingReceiptWindow; window != nil {
+			stuck := ""
+			if window.Stuck {
+				stuck = fmt.Sprintf(", STUCK (threshold %v)", time.Duration(window.StuckThresholdSeconds*float64(time.Second)))
+			}
+			fmt.Fprintf(w, "  Sending receipt since:\t%v%s\n", time.Duration(window.DurationSeconds*float64(time.Second)).Truncate(time.Second), stuck)
+		}
+		fmt.Fprintf(w, "  Migrated entries:\t%d\n", status.Migrator.MigratedEntriesCount)
+		fmt.Fprintf(w, "  Migrated value:\t%d\n", status.Migrator.MigratedValue)
+	}
+
+	if status.Signer != nil {
+		fmt.Fprintln(w, "SIGNER")
+		fmt.Fprintf(w, "  Public keys per milestone:\t%d\n", status.Signer.PublicKeysCount)
+		fmt.Fprintf(w, "  Healthy:\t%s\n", yesOrNo(status.Signer.ConsecutiveFailures == 0))
+		if status.Signer.LastSuccessTimestamp != 0 {
+			fmt.Fprintf(w, "  Last successful signing:\t%v ago\n", now.Sub(time.Unix(status.Signer.LastSuccessTimestamp, 0)).Truncate(time.Second))
+		}
+		if status.Signer.LastError != "" {
+			fmt.Fprintf(w, "  Consecutive failures:\t%d\n", status.Signer.ConsecutiveFailures)
+			fmt.Fprintf(w, "  Last error:\t%s (%v ago)\n", status.Signer.LastError, now.Sub(time.Unix(status.Signer.LastFailureTimestamp, 0)).Truncate(time.Second))
+		}
+	}
+
+	if len(status.Quorum) > 0 {
+		fmt.Fprintln(w, "QUORUM")
+		fmt.Fprintln(w, "  GROUP\tCLIENT\tRESPONSE TIME\tHEALTHY\tERROR")
+		for _, quorumClient := range status.Quorum {
+			name := quorumClient.BaseURL
+			if quorumClient.Alias != "" {
+				name = quorumClient.Alias
+			}
+
+			fmt.Fprintf(w, "  %s\t%s\t%.3fs\t%s\t%s\n", quorumClient.Group, name, quorumClient.ResponseTimeSeconds, yesOrNo(quorumClient.Error == ""), quorumClient.Error)
+		}
+	}
+
+	if len(status.SoftErrors) > 0 {
+		softErrors := status.SoftErrors
+		if len(softErrors) > maxPrintedSoftErrors {
+			softErrors = softErrors[len(softErrors)-maxPrintedSoftErrors:]
+		}
+
+		fmt.Fprintf(w, "SOFT ERRORS (latest %d of %d)\n", len(softErrors), len(status.SoftErrors))
+		fmt.Fprintln(w, "  TIME\tCLASS\tMESSAGE")
+		for _, softError := range softErrors {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", time.Unix(softError.Timestamp, 0).Format(time.RFC3339), softError.Class, softError.Message)
+		}
+	}
+}
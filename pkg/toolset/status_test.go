@@ -0,0 +1,199 @@
+package toolset
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+)
+
+// newFakeStatusServer returns a server that responds with the given status to status requests.
+func newFakeStatusServer(t *testing.T, status *api.StatusResponse) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != api.RouteStatus {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(status))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func testStatusResponse(now time.Time) *api.StatusResponse {
+	softErrors := make([]*api.SoftErrorStatus, maxPrintedSoftErrors+2)
+	for i := range softErrors {
+		softErrors[i] = &api.SoftErrorStatus{
+			Timestamp: now.Add(-time.Duration(len(softErrors)-i) * time.Minute).Unix(),
+			Class:     "nodeLagging",
+			Message:   "soft error " + strings.Repeat("x", i),
+		}
+	}
+
+	return &api.StatusResponse{
+		Coordinator: &api.CoordinatorStatus{
+			LatestMilestoneIndex:     42,
+			LatestMilestoneID:        "0x4242",
+			LatestMilestoneTimestamp: now.Add(-7 * time.Second).Unix(),
+			IntervalMilliseconds:     5000,
+			PendingReceipts:          1,
+			ReceiptPause:             &api.ReceiptPauseEvent{Index: 40, Reason: "node unhealthy"},
+		},
+		Signer: &api.SignerStatus{
+			PublicKeysCount:      2,
+			LastSuccessTimestamp: now.Add(-3 * time.Second).Unix(),
+			LastFailureTimestamp: now.Add(-time.Minute).Unix(),
+			LastError:            "remote signer unavailable",
+			ConsecutiveFailures:  2,
+		},
+		Migrator: &api.MigratorStatus{
+			LatestMigratedAtIndex: 7,
+			LatestIncludedIndex:   3,
+			SendingReceipt:        true,
+			SendingReceiptWindow:  &api.SendingReceiptWindow{DurationSeconds: 90.5, Stuck: true, StuckThresholdSeconds: 60},
+			MigratedEntriesCount:  110,
+			MigratedValue:         1_000_000,
+		},
+		Quorum: []*api.QuorumClientStatus{
+			{Group: "main", Alias: "node1", BaseURL: "http://node1:14265", ResponseTimeSeconds: 0.25},
+			{Group: "main", BaseURL: "http://node2:14265", ResponseTimeSeconds: 1.5, Error: "timeout"},
+		},
+		SoftErrors: softErrors,
+	}
+}
+
+func TestStatusTable(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	status := testStatusResponse(now)
+	server := newFakeStatusServer(t, status)
+
+	var out bytes.Buffer
+	require.NoError(t, runStatus([]string{"--" + FlagToolCoordinatorURL, server.URL}, &out, now))
+	output := out.String()
+
+	lines := strings.Split(output, "\n")
+	for _, expected := range [][]string{
+		{"COORDINATOR"},
+		{"Latest milestone index:", "42"},
+		{"Latest milestone ID:", "0x4242"},
+		{"Time since last milestone:", "7s (interval 5s)"},
+		{"Pending receipts:", "1"},
+		{"Receipts paused:", "since milestone 40, node unhealthy"},
+		{"MIGRATOR"},
+		{"Latest migrated at index:", "7"},
+		{"Latest included index:", "3"},
+		{"Sending receipt:", "YES"},
+		{"Sending receipt since:", "1m30s, STUCK (threshold 1m0s)"},
+		{"Migrated entries:", "110"},
+		{"Migrated value:", "1000000"},
+		{"SIGNER"},
+		{"Public keys per milestone:", "2"},
+		{"Healthy:", "NO"},
+		{"Last successful signing:", "3s ago"},
+		{"Consecutive failures:", "2"},
+		{"Last error:", "remote signer unavailable (1m0s ago)"},
+		{"QUORUM"},
+		{"main", "node1", "0.250s", "YES"},
+		{"main", "http://node2:14265", "1.500s", "NO", "timeout"},
+		{"SOFT ERRORS (latest 10 of 12)"},
+	} {
+		require.True(t, containsLine(lines, expected), "missing line with %q in output:\n%s", expected, output)
+	}
+
+	// only the latest soft errors are shown
+	require.NotContains(t, output, status.SoftErrors[1].Message+"\n")
+	require.Contains(t, output, time.Unix(status.SoftErrors[2].Timestamp, 0).Format(time.RFC3339))
+	require.Contains(t, output, status.SoftErrors[len(status.SoftErrors)-1].Message)
+
+	// the columns are aligned
+	coordinatorIndex := strings.Index(lineWith(lines, "Latest milestone index:"), "42")
+	require.Equal(t, coordinatorIndex, strings.Index(lineWith(lines, "Pending receipts:"), "1"))
+}
+
+func TestStatusTableOptionalSections(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	server := newFakeStatusServer(t, &api.StatusResponse{
+		Coordinator: &api.CoordinatorStatus{LatestMilestoneIndex: 42, LatestMilestoneTimestamp: now.Unix(), IntervalMilliseconds: 1000},
+	})
+
+	var out bytes.Buffer
+	require.NoError(t, runStatus([]string{"--" + FlagToolCoordinatorURL, server.URL}, &out, now))
+	output := out.String()
+
+	require.Contains(t, output, "COORDINATOR")
+	for _, section := range []string{"Receipts paused:", "MIGRATOR", "SIGNER", "QUORUM", "SOFT ERRORS"} {
+		require.NotContains(t, output, section)
+	}
+}
+
+func TestStatusJSON(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	status := testStatusResponse(now)
+	server := newFakeStatusServer(t, status)
+
+	var out bytes.Buffer
+	require.NoError(t, runStatus([]string{"--" + FlagToolCoordinatorURL, server.URL, "--" + FlagToolOutputJSON}, &out, now))
+
+	// the JSON output is the unmodified status response
+	decoded := &api.StatusResponse{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), decoded))
+	require.Equal(t, status, decoded)
+	require.True(t, strings.HasPrefix(out.String(), "{\n  \"coordinator\": {"))
+}
+
+func TestStatusUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	require.Error(t, runStatus([]string{"--" + FlagToolCoordinatorURL, server.URL}, &out, time.Now()))
+	require.Empty(t, out.String())
+}
+
+// lineWith returns the first line that contains the given text.
+func lineWith(lines []string, text string) string {
+	for _, line := range lines {
+		if strings.Contains(line, text) {
+			return line
+		}
+	}
+
+	return ""
+}
+
+// containsLine returns whether a line contains all the given fields in the given order.
+func containsLine(lines []string, fields []string) bool {
+	for _, line := range lines {
+		remaining := line
+		found := true
+		for _, field := range fields {
+			index := strings.Index(remaining, field)
+			if index == -1 {
+				found = false
+
+				break
+			}
+			remaining = remaining[index+len(field):]
+		}
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
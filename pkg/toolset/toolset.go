@@ -0,0 +1,127 @@
+package toolset
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	FlagToolCoordinatorURL = "coordinatorURL"
+	FlagToolBearerToken    = "bearerToken"
+
+	FlagToolOutputJSON            = "json"
+	FlagToolDescriptionOutputJSON = "format output as JSON"
+)
+
+const (
+	ToolStatus    = "status"
+	ToolReceipt   = "receipt"
+	ToolReplay    = "replay"
+	ToolMigration = "migration"
+	ToolVerify    = "verify"
+)
+
+const (
+	DefaultValueCoordinatorURL = "http://localhost:9091"
+)
+
+// ShouldHandleTools checks if tools were requested.
+func ShouldHandleTools() bool {
+	args := os.Args[1:]
+
+	for _, arg := range args {
+		if strings.ToLower(arg) == "tool" || strings.ToLower(arg) == "tools" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HandleTools handles available tools.
+func HandleTools() {
+
+	args := os.Args[1:]
+	if len(args) == 1 {
+		listTools()
+		os.Exit(1)
+	}
+
+	tools := map[string]func([]string) error{
+		ToolStatus:    status,
+		ToolReceipt:   receipt,
+		ToolReplay:    replay,
+		ToolMigration: migration,
+		ToolVerify:    verify,
+	}
+
+	tool, exists := tools[strings.ToLower(args[1])]
+	if !exists {
+		fmt.Print("tool not found.\n\n")
+		listTools()
+		os.Exit(1)
+	}
+
+	if err := tool(args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			// help text was requested
+			os.Exit(0)
+		}
+
+		fmt.Printf("\nerror: %s\n", err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func listTools() {
+	fmt.Printf("%-20s queries the status of a running coordinator\n", fmt.Sprintf("%s:", ToolStatus))
+	fmt.Printf("%-20s compares the migrations of a legacy milestone from two receipt sources (%s)\n", fmt.Sprintf("%s:", ToolReceipt), ReceiptCommandDiff)
+	fmt.Printf("%-20s replays a trace of the coordinator offline and reports where it diverged\n", fmt.Sprintf("%s:", ToolReplay))
+	fmt.Printf("%-20s traces the migrations of legacy addresses from the legacy network up to the ledger of the network (%s)\n", fmt.Sprintf("%s:", ToolMigration), MigrationCommandTrace)
+	fmt.Printf("%-20s verifies a status report or an archive of the event journal signed by the identity key of the coordinator\n", fmt.Sprintf("%s:", ToolVerify))
+}
+
+func yesOrNo(value bool) string {
+	if value {
+		return "YES"
+	}
+
+	return "NO"
+}
+
+func parseFlagSet(fs *flag.FlagSet, args []string) error {
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Check if all parameters were parsed
+	if fs.NArg() != 0 {
+		return errors.New("too much arguments")
+	}
+
+	return nil
+}
+
+func printJSON(obj interface{}) error {
+	return writeJSON(os.Stdout, obj)
+}
+
+func writeJSON(w io.Writer, obj interface{}) error {
+	output, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(output))
+
+	return err
+}
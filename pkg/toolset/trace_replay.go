@@ -0,0 +1,94 @@
+package toolset
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	flag "github.com/spf13/pflag"
+
+	"github.com/iotaledger/hive.go/core/configuration"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-coordinator/pkg/trace"
+)
+
+const (
+	FlagToolTraceFilePath = "trace"
+)
+
+var (
+	// ErrReplayDiverged is returned when the replayed coordinator behaved differently than the recorded one.
+	ErrReplayDiverged = errors.New("the replay diverged from the trace")
+)
+
+func replay(args []string) error {
+
+	fs := configuration.NewUnsortedFlagSet("", flag.ContinueOnError)
+	traceFilePathFlag := fs.String(FlagToolTraceFilePath, "", "the path to the trace file recorded by the coordinator")
+	outputJSONFlag := fs.Bool(FlagToolOutputJSON, false, FlagToolDescriptionOutputJSON)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage of %s:\n", ToolReplay)
+		fs.PrintDefaults()
+		println(fmt.Sprintf("\nexample: %s --%s %s",
+			ToolReplay,
+			FlagToolTraceFilePath,
+			"traces/trace_1667000000000000000.jsonl",
+		))
+	}
+
+	if err := parseFlagSet(fs, args); err != nil {
+		return err
+	}
+
+	if *traceFilePathFlag == "" {
+		return fmt.Errorf("'%s' not specified", FlagToolTraceFilePath)
+	}
+
+	entries, err := trace.LoadTrace(*traceFilePathFlag)
+	if err != nil {
+		return err
+	}
+
+	result, err := trace.Replay(logger.NewNopLogger(), entries)
+	if err != nil {
+		return fmt.Errorf("unable to replay the trace: %w", err)
+	}
+
+	if *outputJSONFlag {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printReplayResult(result)
+	}
+
+	if result.Diverged() {
+		return fmt.Errorf("%w: %d divergences", ErrReplayDiverged, len(result.Divergences))
+	}
+
+	return nil
+}
+
+// printReplayResult prints the result of a replay as a human-readable table.
+func printReplayResult(result *trace.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Decisions:\t%d\n", result.Decisions)
+	fmt.Fprintf(w, "Replayed:\t%d\n", result.Replayed)
+	fmt.Fprintf(w, "Skipped:\t%d\n", result.Skipped)
+	fmt.Fprintf(w, "Diverged:\t%s\n", yesOrNo(result.Diverged()))
+
+	for _, divergence := range result.Divergences {
+		fmt.Fprintf(w, "\nDECISION %d, ENTRY %d (%s)\n", divergence.DecisionSequence, divergence.Sequence, divergence.Kind)
+		fmt.Fprintf(w, "  %s\n", divergence.Message)
+		if divergence.Recorded != "" {
+			fmt.Fprintf(w, "  Recorded:\t%s\n", divergence.Recorded)
+		}
+		if divergence.Replayed != "" {
+			fmt.Fprintf(w, "  Replayed:\t%s\n", divergence.Replayed)
+		}
+	}
+}
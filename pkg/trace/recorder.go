@@ -0,0 +1,274 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/hive.go/core/syncutils"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// FilePattern matches the names of the trace files in the trace folder (see filepath.Match).
+const FilePattern = "trace_*.jsonl"
+
+// Recorder records the interactions of the coordinator with the node and its decisions to a trace file.
+// The entries are stored as JSON lines, a failed write is logged but doesn't stop the coordinator.
+type Recorder struct {
+	*logger.WrappedLogger
+
+	lock syncutils.Mutex
+
+	file *os.File
+	// the sequence number of the latest entry.
+	sequence uint64
+	// whether writing to the trace file failed already, only the first failure is logged.
+	failed bool
+}
+
+// NewRecorder creates a new trace file in the given folder and returns a recorder that writes to it.
+// Every run of the coordinator gets its own trace file, so that the trace of a crashed run is kept.
+func NewRecorder(log *logger.Logger, folderPath string) (*Recorder, error) {
+	if err := os.MkdirAll(folderPath, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create trace folder: %w", err)
+	}
+
+	filePath := filepath.Join(folderPath, fmt.Sprintf("trace_%d.jsonl", time.Now().UnixNano()))
+
+	//nolint:gosec // the path is defined by the operator
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create trace file: %w", err)
+	}
+
+	return &Recorder{
+		WrappedLogger: logger.NewWrappedLogger(log),
+		file:          file,
+	}, nil
+}
+
+// FilePath returns the path of the trace file.
+func (r *Recorder) FilePath() string {
+	return r.file.Name()
+}
+
+// Close closes the trace file.
+func (r *Recorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.file.Close()
+}
+
+// record writes an entry to the trace file.
+func (r *Recorder) record(kind string, request any, response any, err error) {
+	entry := &Entry{
+		Timestamp: time.Now().UnixNano(),
+		Kind:      kind,
+	}
+
+	var marshalErr error
+	if request != nil {
+		entry.Request, marshalErr = json.Marshal(request)
+	}
+	if response != nil && marshalErr == nil {
+		entry.Response, marshalErr = json.Marshal(response)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.sequence++
+	entry.Sequence = r.sequence
+
+	if marshalErr == nil {
+		var line []byte
+		line, marshalErr = json.Marshal(entry)
+		if marshalErr == nil {
+			_, marshalErr = r.file.Write(append(line, '\n'))
+		}
+	}
+
+	if marshalErr != nil && !r.failed {
+		r.failed = true
+		r.LogWarnf("failed to record %s interaction %d, the trace is incomplete: %s", kind, entry.Sequence, marshalErr)
+	}
+}
+
+// RecordStart records the state and the options of the coordinator, it must be the first entry of the trace.
+func (r *Recorder) RecordStart(start *Start) {
+	r.record(KindStart, start, nil, nil)
+}
+
+// RecordDecision records a decision of the coordinator.
+// The decision is recorded after all interactions with the node that belong to it.
+func (r *Recorder) RecordDecision(decision *coordinator.Decision) {
+	request := &decisionRequest{
+		Kind:    decision.Kind,
+		Parents: encodeBlockIDs(decision.Parents),
+	}
+	if decision.Kind == coordinator.DecisionCheckpoint {
+		request.CheckpointIndex = decision.CheckpointIndex
+		request.LastCheckpointBlockID = decision.LastCheckpointBlockID.ToHex()
+	}
+
+	r.record(KindDecision, request, &decisionResponse{
+		BlockID: decision.BlockID.ToHex(),
+		Skipped: decision.Skipped,
+	}, decision.Err)
+}
+
+// MerkleRootsFunc records the merkle roots computed by the given function.
+func (r *Recorder) MerkleRootsFunc(merkleRootsFunc coordinator.ComputeMilestoneMerkleRoots) coordinator.ComputeMilestoneMerkleRoots {
+	return func(ctx context.Context, index iotago.MilestoneIndex, timestamp uint32, parents iotago.BlockIDs, previousMilestoneID iotago.MilestoneID) (*coordinator.MilestoneMerkleRoots, error) {
+		merkleRoots, err := merkleRootsFunc(ctx, index, timestamp, parents, previousMilestoneID)
+
+		var response any
+		if merkleRoots != nil {
+			response = &merkleRootsResponse{
+				InclusionMerkleRoot: iotago.EncodeHex(merkleRoots.InclusionMerkleRoot[:]),
+				AppliedMerkleRoot:   iotago.EncodeHex(merkleRoots.AppliedMerkleRoot[:]),
+			}
+		}
+
+		r.record(KindMerkleRoots, &merkleRootsRequest{
+			Index:               index,
+			Timestamp:           timestamp,
+			Parents:             encodeBlockIDs(parents),
+			PreviousMilestoneID: iotago.EncodeHex(previousMilestoneID[:]),
+		}, response, err)
+
+		return merkleRoots, err
+	}
+}
+
+// NodeSyncedFunc records the sync status returned by the given function.
+func (r *Recorder) NodeSyncedFunc(nodeSyncedFunc coordinator.IsNodeSyncedFunc) coordinator.IsNodeSyncedFunc {
+	return func() bool {
+		synced := nodeSyncedFunc()
+		r.record(KindNodeSynced, nil, synced, nil)
+
+		return synced
+	}
+}
+
+// ConfirmedMilestoneIndexFunc records the confirmed milestone index returned by the given function.
+func (r *Recorder) ConfirmedMilestoneIndexFunc(confirmedMilestoneIndexFunc coordinator.ConfirmedMilestoneIndexFunc) coordinator.ConfirmedMilestoneIndexFunc {
+	return func() iotago.MilestoneIndex {
+		index := confirmedMilestoneIndexFunc()
+		r.record(KindConfirmedMilestoneIndex, nil, index, nil)
+
+		return index
+	}
+}
+
+// ProtocolParametersFunc records the protocol parameters returned by the given function.
+func (r *Recorder) ProtocolParametersFunc(protoParamsFunc coordinator.ProtocolParameteresFunc) coordinator.ProtocolParameteresFunc {
+	return func() *iotago.ProtocolParameters {
+		protoParams := protoParamsFunc()
+		r.record(KindProtocolParameters, nil, protoParams, nil)
+
+		return protoParams
+	}
+}
+
+// SendBlockFunc records the blocks sent by the given function.
+func (r *Recorder) SendBlockFunc(sendBlockFunc coordinator.SendBlockFunc) coordinator.SendBlockFunc {
+	return func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		blockID, err := sendBlockFunc(block, msIndex...)
+
+		blockJSON, marshalErr := block.MarshalJSON()
+		if marshalErr != nil {
+			r.LogWarnf("failed to record sent block: %s", marshalErr)
+		}
+
+		r.record(KindSendBlock, &sendBlockRequest{
+			Block:          blockJSON,
+			MilestoneIndex: msIndex,
+		}, &sendBlockResponse{
+			BlockID:      blockID.ToHex(),
+			NotConfirmed: errors.Is(err, coordinator.ErrMilestoneNotConfirmed),
+		}, err)
+
+		return blockID, err
+	}
+}
+
+// Clock records the time returned by the given clock.
+func (r *Recorder) Clock(clock func() time.Time) func() time.Time {
+	return func() time.Time {
+		now := clock()
+		r.record(KindClock, nil, now.UnixNano(), nil)
+
+		return now
+	}
+}
+
+// SignerProvider records the public keys and the signatures of the signers of the given provider.
+func (r *Recorder) SignerProvider(provider coordinator.MilestoneSignerProvider) coordinator.MilestoneSignerProvider {
+	return &recordingSignerProvider{
+		MilestoneSignerProvider: provider,
+		recorder:                r,
+	}
+}
+
+// recordingSignerProvider records the public keys and the signatures of its signers.
+type recordingSignerProvider struct {
+	coordinator.MilestoneSignerProvider
+	recorder *Recorder
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+func (p *recordingSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) coordinator.MilestoneIndexSigner {
+	signer := p.MilestoneSignerProvider.MilestoneIndexSigner(index)
+	p.recorder.record(KindSigner, &signerRequest{Index: index}, &signerResponse{PublicKeys: encodePublicKeys(signer.PublicKeys())}, nil)
+
+	return &recordingSigner{
+		MilestoneIndexSigner: signer,
+		recorder:             p.recorder,
+		index:                index,
+	}
+}
+
+// recordingSigner records the signatures of a milestone signer.
+type recordingSigner struct {
+	coordinator.MilestoneIndexSigner
+	recorder *Recorder
+	index    iotago.MilestoneIndex
+}
+
+// SigningFunc returns a function to sign the particular milestone.
+func (s *recordingSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	signingFunc := s.MilestoneIndexSigner.SigningFunc()
+
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		sigs, err := signingFunc(pubKeys, msEssence)
+
+		var response any
+		if err == nil {
+			signatures := make([]string, len(sigs))
+			for i := range sigs {
+				signatures[i] = iotago.EncodeHex(sigs[i][:])
+			}
+			response = &signResponse{Signatures: signatures}
+		}
+
+		s.recorder.record(KindSign, &signRequest{
+			Index:      s.index,
+			PublicKeys: encodePublicKeys(pubKeys),
+			Essence:    iotago.EncodeHex(msEssence),
+		}, response, err)
+
+		return sigs, err
+	}
+}
@@ -0,0 +1,637 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// ErrReplayNotSupported is returned when a trace can't be replayed, e.g. because the migration was enabled.
+	ErrReplayNotSupported = errors.New("replay not supported")
+	// ErrInteractionNotRecorded is returned to the replayed coordinator if an interaction is not part of the trace.
+	ErrInteractionNotRecorded = errors.New("interaction was not recorded")
+)
+
+// Divergence is a difference between the recorded and the replayed coordinator.
+type Divergence struct {
+	// the sequence number of the decision the divergence belongs to.
+	DecisionSequence uint64 `json:"decisionSequence"`
+	// the sequence number of the recorded entry (0 = the replayed interaction was not recorded).
+	Sequence uint64 `json:"sequence"`
+	// the kind of the interaction.
+	Kind string `json:"kind"`
+	// the description of the divergence.
+	Message string `json:"message"`
+	// the recorded request or outcome.
+	Recorded string `json:"recorded,omitempty"`
+	// the replayed request or outcome.
+	Replayed string `json:"replayed,omitempty"`
+}
+
+// Result is the result of a replay.
+type Result struct {
+	// the amount of decisions in the trace.
+	Decisions int `json:"decisions"`
+	// the amount of decisions that were replayed.
+	Replayed int `json:"replayed"`
+	// the amount of decisions that were skipped because of the load or the block lag of the node.
+	Skipped int `json:"skipped"`
+	// the differences between the recorded and the replayed coordinator.
+	Divergences []*Divergence `json:"divergences"`
+}
+
+// Diverged returns whether the replayed coordinator behaved differently than the recorded one.
+func (r *Result) Diverged() bool {
+	return len(r.Divergences) > 0
+}
+
+// replayedError is a recorded error that is returned to the replayed coordinator.
+type replayedError struct {
+	message string
+	cause   error
+}
+
+func (e *replayedError) Error() string {
+	return e.message
+}
+
+func (e *replayedError) Unwrap() error {
+	return e.cause
+}
+
+// replayer answers the interactions of the replayed coordinator with the recorded responses.
+type replayer struct {
+	result *Result
+
+	// the sequence number of the decision that is replayed.
+	decisionSequence uint64
+	// the recorded entries that belong to the decision that is replayed.
+	scope []*Entry
+	// the sequence numbers of the entries in the scope that were used.
+	consumed map[uint64]struct{}
+
+	// the latest protocol parameters and time, used if the replayed coordinator makes more calls than recorded.
+	protoParams *iotago.ProtocolParameters
+	now         time.Time
+}
+
+// Replay re-runs the coordinator logic against a trace, the interactions with the node are answered with the recorded responses.
+// Every decision of the trace is repeated with the recorded inputs, and all differences of the requests and the outcomes are returned.
+// The state of the replayed coordinator is kept in a temporary folder, the signers are not called.
+func Replay(log *logger.Logger, entries []*Entry) (*Result, error) {
+	if len(entries) == 0 || entries[0].Kind != KindStart {
+		return nil, fmt.Errorf("%w: the trace does not begin with a start entry", ErrInvalidTrace)
+	}
+
+	start := &Start{}
+	if err := json.Unmarshal(entries[0].Request, start); err != nil {
+		return nil, fmt.Errorf("%w: start entry: %v", ErrInvalidTrace, err)
+	}
+
+	if start.Migration {
+		return nil, fmt.Errorf("%w: the migration was enabled, but receipts are not part of the trace", ErrReplayNotSupported)
+	}
+
+	stateFolder, err := os.MkdirTemp("", "coordinator-replay")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create state folder: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(stateFolder) }()
+
+	rp := &replayer{
+		result: &Result{
+			Divergences: make([]*Divergence, 0),
+		},
+		protoParams: &iotago.ProtocolParameters{},
+	}
+
+	coo, err := rp.newCoordinator(log, start, filepath.Join(stateFolder, "coordinator.state"))
+	if err != nil {
+		return nil, err
+	}
+
+	scope := make([]*Entry, 0)
+	for _, entry := range entries[1:] {
+		if entry.Kind != KindDecision {
+			scope = append(scope, entry)
+
+			continue
+		}
+
+		if err := rp.replayDecision(coo, entry, scope); err != nil {
+			return nil, err
+		}
+		scope = make([]*Entry, 0)
+	}
+
+	return rp.result, nil
+}
+
+// newCoordinator creates a coordinator with the state and the options of the trace.
+func (rp *replayer) newCoordinator(log *logger.Logger, start *Start, stateFilePath string) (*coordinator.Coordinator, error) {
+	if !start.Bootstrap {
+		if err := os.WriteFile(stateFilePath, start.State, 0o600); err != nil {
+			return nil, fmt.Errorf("unable to write coordinator state: %w", err)
+		}
+	}
+
+	milestoneMetadata, err := iotago.DecodeHex(start.MilestoneMetadata)
+	if err != nil && start.MilestoneMetadata != "" {
+		return nil, fmt.Errorf("%w: milestone metadata: %v", ErrInvalidTrace, err)
+	}
+
+	protocolAdapters, err := coordinator.NewProtocolAdapters(start.ProtocolActivations)
+	if err != nil {
+		return nil, fmt.Errorf("%w: protocol activations: %v", ErrInvalidTrace, err)
+	}
+
+	milestoneOptionsFunc, err := coordinator.NewProtocolParametersUpdatesFunc(start.ProtocolParametersUpdates)
+	if err != nil {
+		return nil, fmt.Errorf("%w: protocol parameters updates: %v", ErrInvalidTrace, err)
+	}
+
+	coo, err := coordinator.New(
+		rp.merkleRoots,
+		rp.nodeSynced,
+		rp.protocolParameters,
+		&replayedSignerProvider{replayer: rp, publicKeysCount: start.PublicKeysCount},
+		nil,
+		nil,
+		rp.sendBlock,
+		coordinator.WithLogger(log),
+		coordinator.WithStateFilePath(stateFilePath),
+		coordinator.WithSigningRetryAmount(start.SigningRetryAmount),
+		coordinator.WithSigningRetryTimeout(0),
+		coordinator.WithBlockBackups(false, ""),
+		coordinator.WithMilestoneMetadata(milestoneMetadata),
+		coordinator.WithProtocolAdapters(protocolAdapters),
+		coordinator.WithMilestoneOptions(milestoneOptionsFunc),
+		coordinator.WithConfirmationCheck(0, rp.confirmedMilestoneIndex),
+		coordinator.WithMaxClockDrift(time.Duration(start.MaxClockDrift)),
+		coordinator.WithClock(rp.clock),
+		coordinator.WithDebugFakeMilestoneTimestamps(start.DebugFakeMilestoneTimestamps),
+		coordinator.WithNetworkReset(start.NetworkReset),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create coordinator: %w", err)
+	}
+
+	latestMilestone := &coordinator.LatestMilestoneInfo{
+		Index:     start.LatestMilestoneIndex,
+		Timestamp: start.LatestMilestoneTimestamp,
+	}
+	if start.LatestMilestoneID != "" {
+		if err := decodeFixed(start.LatestMilestoneID, latestMilestone.MilestoneID[:]); err != nil {
+			return nil, fmt.Errorf("%w: latest milestone ID: %v", ErrInvalidTrace, err)
+		}
+	}
+
+	if err := coo.InitState(start.Bootstrap, start.StartIndex, latestMilestone); err != nil {
+		return nil, fmt.Errorf("unable to initialize coordinator state: %w", err)
+	}
+
+	return coo, nil
+}
+
+// replayDecision repeats a recorded decision with the recorded interactions that belong to it.
+func (rp *replayer) replayDecision(coo *coordinator.Coordinator, entry *Entry, scope []*Entry) error {
+	request := &decisionRequest{}
+	if err := json.Unmarshal(entry.Request, request); err != nil {
+		return fmt.Errorf("%w: decision %d: %v", ErrInvalidTrace, entry.Sequence, err)
+	}
+
+	response := &decisionResponse{}
+	if err := json.Unmarshal(entry.Response, response); err != nil {
+		return fmt.Errorf("%w: decision %d: %v", ErrInvalidTrace, entry.Sequence, err)
+	}
+
+	rp.result.Decisions++
+	if response.Skipped {
+		rp.result.Skipped++
+
+		return nil
+	}
+
+	parents, err := decodeBlockIDs(request.Parents)
+	if err != nil {
+		return fmt.Errorf("%w: decision %d: %v", ErrInvalidTrace, entry.Sequence, err)
+	}
+
+	rp.decisionSequence = entry.Sequence
+	rp.scope = scope
+	rp.consumed = make(map[uint64]struct{})
+
+	var blockID iotago.BlockID
+	switch request.Kind {
+	case coordinator.DecisionBootstrap:
+		blockID, err = coo.Bootstrap()
+
+	case coordinator.DecisionCheckpoint:
+		var lastCheckpointBlockID iotago.BlockID
+		if err := decodeFixed(request.LastCheckpointBlockID, lastCheckpointBlockID[:]); err != nil {
+			return fmt.Errorf("%w: decision %d: %v", ErrInvalidTrace, entry.Sequence, err)
+		}
+		blockID, err = coo.IssueCheckpoint(request.CheckpointIndex, lastCheckpointBlockID, parents)
+
+	case coordinator.DecisionMilestone:
+		blockID, err = coo.IssueMilestone(context.Background(), parents)
+
+	default:
+		return fmt.Errorf("%w: unknown decision kind '%s' in entry %d", ErrInvalidTrace, request.Kind, entry.Sequence)
+	}
+
+	recordedOutcome := describeOutcome(response.BlockID, entry.Error)
+	replayedOutcome := describeOutcome(blockID.ToHex(), errorMessage(err))
+	if recordedOutcome != replayedOutcome {
+		rp.diverge(entry, KindDecision, fmt.Sprintf("the outcome of the %s decision differs", request.Kind), recordedOutcome, replayedOutcome)
+	}
+
+	// the node interactions of a decision must all be repeated, other entries may belong to concurrent signing self-tests
+	for _, scopeEntry := range scope {
+		if _, consumed := rp.consumed[scopeEntry.Sequence]; consumed {
+			continue
+		}
+
+		if scopeEntry.Kind == KindMerkleRoots || scopeEntry.Kind == KindSendBlock {
+			rp.diverge(scopeEntry, scopeEntry.Kind, "the recorded interaction was not repeated", string(scopeEntry.Request), "")
+		}
+	}
+
+	rp.result.Replayed++
+
+	return nil
+}
+
+// diverge adds a divergence to the result.
+func (rp *replayer) diverge(entry *Entry, kind string, message string, recorded string, replayed string) {
+	divergence := &Divergence{
+		DecisionSequence: rp.decisionSequence,
+		Kind:             kind,
+		Message:          message,
+		Recorded:         recorded,
+		Replayed:         replayed,
+	}
+	if entry != nil {
+		divergence.Sequence = entry.Sequence
+	}
+
+	rp.result.Divergences = append(rp.result.Divergences, divergence)
+}
+
+// next returns the first unused recorded entry of the given kind with the same request.
+// If there is no such entry, the first unused entry of the kind is returned and the divergence is added to the result.
+func (rp *replayer) next(kind string, request any) *Entry {
+	var replayedRequest []byte
+	if request != nil {
+		var err error
+		if replayedRequest, err = normalizeRequest(kind, request); err != nil {
+			rp.diverge(nil, kind, fmt.Sprintf("unable to encode the replayed request: %s", err), "", "")
+
+			return nil
+		}
+	}
+
+	var candidate *Entry
+	for _, entry := range rp.scope {
+		if entry.Kind != kind {
+			continue
+		}
+		if _, consumed := rp.consumed[entry.Sequence]; consumed {
+			continue
+		}
+
+		if request == nil {
+			candidate = entry
+
+			break
+		}
+
+		recordedRequest, err := normalizeRequest(kind, entry.Request)
+		if err == nil && string(recordedRequest) == string(replayedRequest) {
+			candidate = entry
+
+			break
+		}
+
+		if candidate == nil {
+			candidate = entry
+		}
+	}
+
+	if candidate == nil {
+		rp.diverge(nil, kind, "the replayed interaction was not recorded", "", string(replayedRequest))
+
+		return nil
+	}
+
+	if request != nil {
+		if recordedRequest, err := normalizeRequest(kind, candidate.Request); err != nil || string(recordedRequest) != string(replayedRequest) {
+			rp.diverge(candidate, kind, "the replayed request differs from the recorded one", string(candidate.Request), string(replayedRequest))
+		}
+	}
+
+	rp.consumed[candidate.Sequence] = struct{}{}
+
+	return candidate
+}
+
+// recordedError returns the error of a recorded entry.
+func recordedError(entry *Entry, cause error) error {
+	if entry.Error == "" {
+		return nil
+	}
+
+	return &replayedError{message: entry.Error, cause: cause}
+}
+
+func (rp *replayer) merkleRoots(_ context.Context, index iotago.MilestoneIndex, timestamp uint32, parents iotago.BlockIDs, previousMilestoneID iotago.MilestoneID) (*coordinator.MilestoneMerkleRoots, error) {
+	entry := rp.next(KindMerkleRoots, &merkleRootsRequest{
+		Index:               index,
+		Timestamp:           timestamp,
+		Parents:             encodeBlockIDs(parents),
+		PreviousMilestoneID: iotago.EncodeHex(previousMilestoneID[:]),
+	})
+	if entry == nil {
+		return nil, ErrInteractionNotRecorded
+	}
+
+	if err := recordedError(entry, nil); err != nil {
+		return nil, err
+	}
+
+	response := &merkleRootsResponse{}
+	if err := json.Unmarshal(entry.Response, response); err != nil {
+		return nil, fmt.Errorf("%w: entry %d: %v", ErrInvalidTrace, entry.Sequence, err)
+	}
+
+	merkleRoots := &coordinator.MilestoneMerkleRoots{}
+	if err := decodeFixed(response.InclusionMerkleRoot, merkleRoots.InclusionMerkleRoot[:]); err != nil {
+		return nil, fmt.Errorf("%w: entry %d: %v", ErrInvalidTrace, entry.Sequence, err)
+	}
+	if err := decodeFixed(response.AppliedMerkleRoot, merkleRoots.AppliedMerkleRoot[:]); err != nil {
+		return nil, fmt.Errorf("%w: entry %d: %v", ErrInvalidTrace, entry.Sequence, err)
+	}
+
+	return merkleRoots, nil
+}
+
+func (rp *replayer) nodeSynced() bool {
+	entry := rp.next(KindNodeSynced, nil)
+	if entry == nil {
+		return false
+	}
+
+	var synced bool
+	if err := json.Unmarshal(entry.Response, &synced); err != nil {
+		rp.diverge(entry, KindNodeSynced, fmt.Sprintf("unable to decode the recorded response: %s", err), string(entry.Response), "")
+
+		return false
+	}
+
+	return synced
+}
+
+func (rp *replayer) confirmedMilestoneIndex() iotago.MilestoneIndex {
+	entry := rp.next(KindConfirmedMilestoneIndex, nil)
+	if entry == nil {
+		return 0
+	}
+
+	var index iotago.MilestoneIndex
+	if err := json.Unmarshal(entry.Response, &index); err != nil {
+		rp.diverge(entry, KindConfirmedMilestoneIndex, fmt.Sprintf("unable to decode the recorded response: %s", err), string(entry.Response), "")
+
+		return 0
+	}
+
+	return index
+}
+
+func (rp *replayer) protocolParameters() *iotago.ProtocolParameters {
+	entry := rp.next(KindProtocolParameters, nil)
+	if entry == nil {
+		return rp.protoParams
+	}
+
+	protoParams := &iotago.ProtocolParameters{}
+	if err := json.Unmarshal(entry.Response, protoParams); err != nil {
+		rp.diverge(entry, KindProtocolParameters, fmt.Sprintf("unable to decode the recorded response: %s", err), string(entry.Response), "")
+
+		return rp.protoParams
+	}
+	rp.protoParams = protoParams
+
+	return protoParams
+}
+
+func (rp *replayer) sendBlock(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+	blockJSON, err := block.MarshalJSON()
+	if err != nil {
+		return iotago.EmptyBlockID(), err
+	}
+
+	entry := rp.next(KindSendBlock, &sendBlockRequest{
+		Block:          blockJSON,
+		MilestoneIndex: msIndex,
+	})
+	if entry == nil {
+		return iotago.EmptyBlockID(), ErrInteractionNotRecorded
+	}
+
+	response := &sendBlockResponse{}
+	if err := json.Unmarshal(entry.Response, response); err != nil {
+		return iotago.EmptyBlockID(), fmt.Errorf("%w: entry %d: %v", ErrInvalidTrace, entry.Sequence, err)
+	}
+
+	var blockID iotago.BlockID
+	if err := decodeFixed(response.BlockID, blockID[:]); err != nil {
+		return iotago.EmptyBlockID(), fmt.Errorf("%w: entry %d: %v", ErrInvalidTrace, entry.Sequence, err)
+	}
+
+	var cause error
+	if response.NotConfirmed {
+		cause = coordinator.ErrMilestoneNotConfirmed
+	}
+
+	return blockID, recordedError(entry, cause)
+}
+
+func (rp *replayer) clock() time.Time {
+	entry := rp.next(KindClock, nil)
+	if entry == nil {
+		return rp.now
+	}
+
+	var nanoseconds int64
+	if err := json.Unmarshal(entry.Response, &nanoseconds); err != nil {
+		rp.diverge(entry, KindClock, fmt.Sprintf("unable to decode the recorded response: %s", err), string(entry.Response), "")
+
+		return rp.now
+	}
+	rp.now = time.Unix(0, nanoseconds)
+
+	return rp.now
+}
+
+// replayedSignerProvider provides signers that answer with the recorded public keys and signatures.
+type replayedSignerProvider struct {
+	replayer        *replayer
+	publicKeysCount int
+}
+
+// MilestoneIndexSigner returns a new signer for the milestone index.
+func (p *replayedSignerProvider) MilestoneIndexSigner(index iotago.MilestoneIndex) coordinator.MilestoneIndexSigner {
+	signer := &replayedSigner{
+		replayer:  p.replayer,
+		index:     index,
+		pubKeys:   make([]iotago.MilestonePublicKey, 0),
+		pubKeySet: make(iotago.MilestonePublicKeySet),
+	}
+
+	entry := p.replayer.next(KindSigner, &signerRequest{Index: index})
+	if entry == nil {
+		return signer
+	}
+
+	response := &signerResponse{}
+	if err := json.Unmarshal(entry.Response, response); err != nil {
+		p.replayer.diverge(entry, KindSigner, fmt.Sprintf("unable to decode the recorded response: %s", err), string(entry.Response), "")
+
+		return signer
+	}
+
+	for _, encoded := range response.PublicKeys {
+		var pubKey iotago.MilestonePublicKey
+		if err := decodeFixed(encoded, pubKey[:]); err != nil {
+			p.replayer.diverge(entry, KindSigner, fmt.Sprintf("unable to decode the recorded public key: %s", err), encoded, "")
+
+			continue
+		}
+		signer.pubKeys = append(signer.pubKeys, pubKey)
+		signer.pubKeySet[pubKey] = struct{}{}
+	}
+
+	return signer
+}
+
+// PublicKeysCount returns the amount of public keys in a milestone.
+func (p *replayedSignerProvider) PublicKeysCount() int {
+	return p.publicKeysCount
+}
+
+// replayedSigner is a milestone signer that answers with the recorded public keys and signatures.
+type replayedSigner struct {
+	replayer  *replayer
+	index     iotago.MilestoneIndex
+	pubKeys   []iotago.MilestonePublicKey
+	pubKeySet iotago.MilestonePublicKeySet
+}
+
+// PublicKeys returns a slice of the used public keys.
+func (s *replayedSigner) PublicKeys() []iotago.MilestonePublicKey {
+	return s.pubKeys
+}
+
+// PublicKeysSet returns a map of the used public keys.
+func (s *replayedSigner) PublicKeysSet() iotago.MilestonePublicKeySet {
+	return s.pubKeySet
+}
+
+// SigningFunc returns a function that answers with the recorded signatures.
+func (s *replayedSigner) SigningFunc() iotago.MilestoneSigningFunc {
+	return func(pubKeys []iotago.MilestonePublicKey, msEssence []byte) ([]iotago.MilestoneSignature, error) {
+		entry := s.replayer.next(KindSign, &signRequest{
+			Index:      s.index,
+			PublicKeys: encodePublicKeys(pubKeys),
+			Essence:    iotago.EncodeHex(msEssence),
+		})
+		if entry == nil {
+			return nil, ErrInteractionNotRecorded
+		}
+
+		if err := recordedError(entry, nil); err != nil {
+			return nil, err
+		}
+
+		response := &signResponse{}
+		if err := json.Unmarshal(entry.Response, response); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %v", ErrInvalidTrace, entry.Sequence, err)
+		}
+
+		sigs := make([]iotago.MilestoneSignature, len(response.Signatures))
+		for i, encoded := range response.Signatures {
+			if err := decodeFixed(encoded, sigs[i][:]); err != nil {
+				return nil, fmt.Errorf("%w: entry %d: %v", ErrInvalidTrace, entry.Sequence, err)
+			}
+		}
+
+		return sigs, nil
+	}
+}
+
+// normalizeRequest returns a canonical encoding of a request, so that recorded and replayed requests can be compared.
+// The nonce of sent blocks is ignored, because the proof of work is not repeated.
+func normalizeRequest(kind string, request any) ([]byte, error) {
+	data, ok := request.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(request); err != nil {
+			return nil, err
+		}
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	if kind == KindSendBlock {
+		if sendBlock, ok := decoded.(map[string]any); ok {
+			if block, ok := sendBlock["block"].(map[string]any); ok {
+				delete(block, "nonce")
+			}
+		}
+	}
+
+	return json.Marshal(decoded)
+}
+
+// decodeBlockIDs decodes hex encoded block IDs.
+func decodeBlockIDs(encoded []string) (iotago.BlockIDs, error) {
+	blockIDs := make(iotago.BlockIDs, len(encoded))
+	for i := range encoded {
+		if err := decodeFixed(encoded[i], blockIDs[i][:]); err != nil {
+			return nil, err
+		}
+	}
+
+	return blockIDs, nil
+}
+
+// errorMessage returns the message of an error or an empty string.
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+// describeOutcome returns a description of the outcome of a decision.
+func describeOutcome(blockID string, errMessage string) string {
+	if errMessage != "" {
+		return fmt.Sprintf("error: %s", errMessage)
+	}
+
+	return fmt.Sprintf("block %s", blockID)
+}
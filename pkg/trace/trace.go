@@ -0,0 +1,226 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// KindStart is the first entry of a trace, it contains the state and the options of the coordinator.
+	KindStart = "start"
+	// KindMerkleRoots is a computation of the merkle roots of a milestone by the node.
+	KindMerkleRoots = "merkleRoots"
+	// KindNodeSynced is a query of the sync status of the node.
+	KindNodeSynced = "nodeSynced"
+	// KindConfirmedMilestoneIndex is a query of the index of the latest milestone the node confirmed.
+	KindConfirmedMilestoneIndex = "confirmedMilestoneIndex"
+	// KindProtocolParameters is a query of the protocol parameters of the node.
+	KindProtocolParameters = "protocolParameters"
+	// KindSendBlock is a block that was sent to the node.
+	KindSendBlock = "sendBlock"
+	// KindClock is a read of the clock for a milestone timestamp.
+	KindClock = "clock"
+	// KindSigner is a query of the public keys of a milestone signer.
+	KindSigner = "signer"
+	// KindSign is a signing request to a milestone signer.
+	KindSign = "sign"
+	// KindDecision is a decision of the coordinator, the entries before it belong to the decision.
+	KindDecision = "decision"
+)
+
+var (
+	// ErrInvalidTrace is returned when a trace file can't be parsed.
+	ErrInvalidTrace = errors.New("invalid trace")
+)
+
+// Entry is a recorded interaction of the coordinator.
+type Entry struct {
+	// the gapless sequence number of the entry, starting at 1.
+	Sequence uint64 `json:"sequence"`
+	// the unix timestamp in nanoseconds the entry was recorded.
+	Timestamp int64 `json:"timestamp"`
+	// the kind of the interaction.
+	Kind string `json:"kind"`
+	// the request of the coordinator.
+	Request json.RawMessage `json:"request,omitempty"`
+	// the response to the coordinator.
+	Response json.RawMessage `json:"response,omitempty"`
+	// the error that was returned to the coordinator.
+	Error string `json:"error,omitempty"`
+}
+
+// Start contains the state and the options of the coordinator at the start of the trace.
+type Start struct {
+	// whether the network was bootstrapped.
+	Bootstrap bool `json:"bootstrap"`
+	// the index of the first milestone at bootstrap.
+	StartIndex uint32 `json:"startIndex"`
+	// whether the network was bootstrapped on a network reset.
+	NetworkReset bool `json:"networkReset,omitempty"`
+	// the latest milestone known by the node.
+	LatestMilestoneIndex     uint32 `json:"latestMilestoneIndex"`
+	LatestMilestoneTimestamp uint32 `json:"latestMilestoneTimestamp"`
+	LatestMilestoneID        string `json:"latestMilestoneId"`
+	// the content of the coordinator state file (empty at bootstrap).
+	State json.RawMessage `json:"state,omitempty"`
+	// the amount of public keys in a milestone.
+	PublicKeysCount int `json:"publicKeysCount"`
+	// the metadata that is embedded into every milestone (hex encoded).
+	MilestoneMetadata string `json:"milestoneMetadata,omitempty"`
+	// the activations of the protocol versions.
+	ProtocolActivations []*coordinator.ProtocolActivation `json:"protocolActivations,omitempty"`
+	// the protocol parameters that are announced by the milestones.
+	ProtocolParametersUpdates []*coordinator.ProtocolParametersUpdate `json:"protocolParametersUpdates,omitempty"`
+	// the amount of times signing is retried.
+	SigningRetryAmount int `json:"signingRetryAmount"`
+	// the maximum duration the issuance of a milestone is delayed until its timestamp increased (nanoseconds).
+	MaxClockDrift int64 `json:"maxClockDrift"`
+	// whether the timestamps of the milestones were faked.
+	DebugFakeMilestoneTimestamps bool `json:"debugFakeMilestoneTimestamps"`
+	// whether the migration was enabled, receipts are not part of the trace.
+	Migration bool `json:"migration"`
+}
+
+// merkleRootsRequest is the request of a merkle roots computation.
+type merkleRootsRequest struct {
+	Index               uint32   `json:"index"`
+	Timestamp           uint32   `json:"timestamp"`
+	Parents             []string `json:"parents"`
+	PreviousMilestoneID string   `json:"previousMilestoneId"`
+}
+
+// merkleRootsResponse are the merkle roots computed by the node.
+type merkleRootsResponse struct {
+	InclusionMerkleRoot string `json:"inclusionMerkleRoot"`
+	AppliedMerkleRoot   string `json:"appliedMerkleRoot"`
+}
+
+// sendBlockRequest is a block that was sent to the node.
+type sendBlockRequest struct {
+	Block          json.RawMessage `json:"block"`
+	MilestoneIndex []uint32        `json:"milestoneIndex,omitempty"`
+}
+
+// sendBlockResponse is the response of the node to a sent block.
+type sendBlockResponse struct {
+	BlockID string `json:"blockId"`
+	// whether the error signaled that the milestone was not confirmed in time.
+	NotConfirmed bool `json:"notConfirmed,omitempty"`
+}
+
+// signerRequest is a query of the public keys of a milestone signer.
+type signerRequest struct {
+	Index uint32 `json:"index"`
+}
+
+// signerResponse are the public keys of a milestone signer.
+type signerResponse struct {
+	PublicKeys []string `json:"publicKeys"`
+}
+
+// signRequest is a signing request to a milestone signer.
+type signRequest struct {
+	Index      uint32   `json:"index"`
+	PublicKeys []string `json:"publicKeys"`
+	Essence    string   `json:"essence"`
+}
+
+// signResponse are the signatures of a milestone signer.
+type signResponse struct {
+	Signatures []string `json:"signatures"`
+}
+
+// decisionRequest are the inputs of a decision.
+type decisionRequest struct {
+	Kind                  string   `json:"kind"`
+	CheckpointIndex       int      `json:"checkpointIndex,omitempty"`
+	LastCheckpointBlockID string   `json:"lastCheckpointBlockId,omitempty"`
+	Parents               []string `json:"parents,omitempty"`
+}
+
+// decisionResponse is the outcome of a decision.
+type decisionResponse struct {
+	BlockID string `json:"blockId"`
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// LoadTrace reads the entries of a trace file.
+// An incomplete entry at the end of the file, e.g. after a crash while writing, is ignored.
+// Trace files that were compressed by the retention policy are decompressed transparently.
+func LoadTrace(filePath string) ([]*Entry, error) {
+	file, err := retention.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open trace: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	entries := make([]*Entry, 0)
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("unable to read trace: %w", err)
+		}
+
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			break
+		}
+
+		entry := &Entry{}
+		if err := json.Unmarshal(bytes.TrimSpace(line), entry); err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %v", ErrInvalidTrace, len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 || entries[0].Kind != KindStart {
+		return nil, fmt.Errorf("%w: the trace does not begin with a start entry", ErrInvalidTrace)
+	}
+
+	return entries, nil
+}
+
+// encodeBlockIDs returns the hex encoded block IDs.
+func encodeBlockIDs(blockIDs iotago.BlockIDs) []string {
+	encoded := make([]string, len(blockIDs))
+	for i, blockID := range blockIDs {
+		encoded[i] = blockID.ToHex()
+	}
+
+	return encoded
+}
+
+// encodePublicKeys returns the hex encoded public keys.
+func encodePublicKeys(pubKeys []iotago.MilestonePublicKey) []string {
+	encoded := make([]string, len(pubKeys))
+	for i := range pubKeys {
+		encoded[i] = iotago.EncodeHex(pubKeys[i][:])
+	}
+
+	return encoded
+}
+
+// decodeFixed decodes a hex encoded value of a fixed length.
+func decodeFixed(value string, target []byte) error {
+	decoded, err := iotago.DecodeHex(value)
+	if err != nil {
+		return err
+	}
+
+	if len(decoded) != len(target) {
+		return fmt.Errorf("invalid length %d, expected %d", len(decoded), len(target))
+	}
+	copy(target, decoded)
+
+	return nil
+}
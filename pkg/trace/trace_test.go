@@ -0,0 +1,188 @@
+package trace_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/core/logger"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/trace"
+	iotago "github.com/iotaledger/iota.go/v3"
+	"github.com/iotaledger/iota.go/v3/keymanager"
+)
+
+var testProtoParams = &iotago.ProtocolParameters{
+	Version:       2,
+	NetworkName:   "testnet",
+	Bech32HRP:     "tst",
+	MinPoWScore:   0,
+	BelowMaxDepth: 15,
+	RentStructure: iotago.RentStructure{
+		VByteCost:    500,
+		VBFactorData: 1,
+		VBFactorKey:  10,
+	},
+	TokenSupply: 2_779_530_283_277_761,
+}
+
+// recordTrace runs a coordinator against a fake node and records the trace.
+func recordTrace(t *testing.T) string {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyManager := keymanager.New()
+	keyManager.AddKeyRange(publicKey, 0, 0)
+
+	recorder, err := trace.NewRecorder(logger.NewNopLogger(), filepath.Join(t.TempDir(), "traces"))
+	require.NoError(t, err)
+
+	var sentBlocks byte
+	sendBlock := func(block *iotago.Block, msIndex ...iotago.MilestoneIndex) (iotago.BlockID, error) {
+		sentBlocks++
+
+		return iotago.BlockID{sentBlocks}, nil
+	}
+
+	merkleRoots := func(_ context.Context, index iotago.MilestoneIndex, _ uint32, _ iotago.BlockIDs, _ iotago.MilestoneID) (*coordinator.MilestoneMerkleRoots, error) {
+		return &coordinator.MilestoneMerkleRoots{
+			InclusionMerkleRoot: iotago.MilestoneMerkleProof{byte(index)},
+			AppliedMerkleRoot:   iotago.MilestoneMerkleProof{byte(index), 1},
+		}, nil
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	clock := func() time.Time {
+		now = now.Add(10 * time.Second)
+
+		return now
+	}
+
+	stateFilePath := filepath.Join(t.TempDir(), "coordinator.state")
+	coo, err := coordinator.New(
+		recorder.MerkleRootsFunc(merkleRoots),
+		recorder.NodeSyncedFunc(func() bool { return true }),
+		recorder.ProtocolParametersFunc(func() *iotago.ProtocolParameters { return testProtoParams }),
+		recorder.SignerProvider(coordinator.NewInMemoryEd25519MilestoneSignerProvider([]ed25519.PrivateKey{privateKey}, keyManager, 1)),
+		nil,
+		nil,
+		recorder.SendBlockFunc(sendBlock),
+		coordinator.WithLogger(logger.NewNopLogger()),
+		coordinator.WithStateFilePath(stateFilePath),
+		coordinator.WithBlockBackups(false, ""),
+		coordinator.WithClock(recorder.Clock(clock)),
+	)
+	require.NoError(t, err)
+	coo.Events.Decision.Hook(events.NewClosure(recorder.RecordDecision))
+
+	recorder.RecordStart(&trace.Start{
+		Bootstrap:          true,
+		StartIndex:         1,
+		PublicKeysCount:    1,
+		SigningRetryAmount: 10,
+		MaxClockDrift:      int64(5 * time.Second),
+	})
+	require.NoError(t, coo.InitState(true, 1, &coordinator.LatestMilestoneInfo{}))
+
+	milestoneBlockID, err := coo.Bootstrap()
+	require.NoError(t, err)
+
+	checkpointBlockID, err := coo.IssueCheckpoint(0, milestoneBlockID, iotago.BlockIDs{{100}, {101}})
+	require.NoError(t, err)
+
+	_, err = coo.IssueMilestone(context.Background(), iotago.BlockIDs{milestoneBlockID, checkpointBlockID})
+	require.NoError(t, err)
+
+	// decisions without tips are not recorded, they don't interact with the node
+	_, err = coo.IssueCheckpoint(1, milestoneBlockID, nil)
+	require.ErrorIs(t, err, coordinator.ErrNoTipsGiven)
+
+	require.NoError(t, recorder.Close())
+
+	return recorder.FilePath()
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	traceFilePath := recordTrace(t)
+
+	entries, err := trace.LoadTrace(traceFilePath)
+	require.NoError(t, err)
+
+	decisions := 0
+	for i, entry := range entries {
+		require.EqualValues(t, i+1, entry.Sequence)
+		if entry.Kind == trace.KindDecision {
+			decisions++
+		}
+	}
+	require.Equal(t, 3, decisions)
+
+	result, err := trace.Replay(logger.NewNopLogger(), entries)
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Decisions)
+	require.Equal(t, 3, result.Replayed)
+	require.Zero(t, result.Skipped)
+	require.False(t, result.Diverged(), "%v", result.Divergences)
+}
+
+func TestReplayDivergence(t *testing.T) {
+	traceFilePath := recordTrace(t)
+
+	entries, err := trace.LoadTrace(traceFilePath)
+	require.NoError(t, err)
+
+	// the node computed other merkle roots for the last milestone than the ones that were signed
+	var tampered bool
+	for i := len(entries) - 1; i >= 0 && !tampered; i-- {
+		if entries[i].Kind != trace.KindMerkleRoots {
+			continue
+		}
+
+		response := map[string]string{}
+		require.NoError(t, json.Unmarshal(entries[i].Response, &response))
+		response["appliedMerkleRoot"] = iotago.EncodeHex(make([]byte, iotago.MilestoneMerkleProofLength))
+		entries[i].Response, err = json.Marshal(response)
+		require.NoError(t, err)
+		tampered = true
+	}
+	require.True(t, tampered)
+
+	result, err := trace.Replay(logger.NewNopLogger(), entries)
+	require.NoError(t, err)
+	require.True(t, result.Diverged())
+	require.Equal(t, trace.KindSign, result.Divergences[0].Kind)
+}
+
+func TestLoadTrace(t *testing.T) {
+	traceFilePath := recordTrace(t)
+
+	entries, err := trace.LoadTrace(traceFilePath)
+	require.NoError(t, err)
+
+	// an incomplete entry at the end of the file is ignored
+	data, err := os.ReadFile(traceFilePath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(traceFilePath, append(data, []byte(`{"sequence":`)...), 0o600))
+
+	truncatedEntries, err := trace.LoadTrace(traceFilePath)
+	require.NoError(t, err)
+	require.Len(t, truncatedEntries, len(entries))
+
+	// a trace needs to begin with a start entry
+	require.NoError(t, os.WriteFile(traceFilePath, []byte("{\"sequence\":1,\"kind\":\"clock\"}\n"), 0o600))
+	_, err = trace.LoadTrace(traceFilePath)
+	require.ErrorIs(t, err, trace.ErrInvalidTrace)
+
+	// traces of a coordinator with migration can't be replayed
+	_, err = trace.Replay(logger.NewNopLogger(), []*trace.Entry{{Sequence: 1, Kind: trace.KindStart, Request: json.RawMessage(`{"migration":true}`)}})
+	require.ErrorIs(t, err, trace.ErrReplayNotSupported)
+}
@@ -0,0 +1,210 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TagValidate is the struct tag that contains the validation rules of a parameter.
+	// Multiple rules are separated by a comma, e.g. `validate:"min=1,max=10"`.
+	TagValidate = "validate"
+
+	// RuleRequired checks that strings and slices are not empty.
+	RuleRequired = "required"
+	// RuleMin checks that numbers and durations are not smaller than the given value.
+	RuleMin = "min"
+	// RuleMax checks that numbers and durations are not bigger than the given value.
+	RuleMax = "max"
+	// RuleOneOf checks that the value is one of the given space separated values.
+	RuleOneOf = "oneof"
+	// RuleURL checks that strings, or all strings in a slice, are absolute HTTP or HTTPS URLs.
+	RuleURL = "url"
+)
+
+var (
+	// ErrInvalidParameter is returned if a parameter does not satisfy its validation rules.
+	ErrInvalidParameter = errors.New("invalid parameter")
+	// ErrUnknownRule is returned if a validation rule is unknown or can't be applied to the type of the parameter.
+	ErrUnknownRule = errors.New("unknown validation rule")
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Validate checks the parameters in the given struct against the rules in their validation tags.
+// Nested structs are validated recursively, the namespace is used as prefix of the parameter names in the errors.
+func Validate(namespace string, params any) error {
+	value := reflect.ValueOf(params)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("parameters of namespace \"%s\" must be a struct", namespace)
+	}
+
+	return validateStruct(namespace, value)
+}
+
+func validateStruct(prefix string, value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := parameterName(prefix, field)
+		fieldValue := value.Field(i)
+
+		if fieldValue.Kind() == reflect.Struct {
+			if err := validateStruct(name, fieldValue); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		rules, exists := field.Tag.Lookup(TagValidate)
+		if !exists || rules == "" {
+			continue
+		}
+
+		for _, rule := range strings.Split(rules, ",") {
+			if err := validateRule(name, fieldValue, rule); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateURL checks that the value is an absolute HTTP or HTTPS URL.
+func validateURL(name string, value string) error {
+	parsed, err := url.ParseRequestURI(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: \"%s\" must be an absolute HTTP or HTTPS URL, got \"%s\"", ErrInvalidParameter, name, value)
+	}
+
+	return nil
+}
+
+// parameterName returns the name of the parameter the same way it is used in the configuration.
+func parameterName(prefix string, field reflect.StructField) string {
+	name := field.Tag.Get("name")
+	if name == "" {
+		runes := []rune(field.Name)
+		runes[0] = unicode.ToLower(runes[0])
+		name = string(runes)
+	}
+
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
+
+func validateRule(name string, value reflect.Value, rule string) error {
+	ruleName, argument, _ := strings.Cut(rule, "=")
+
+	switch ruleName {
+	case RuleRequired:
+		switch value.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map:
+			if value.Len() == 0 {
+				return fmt.Errorf("%w: \"%s\" must not be empty", ErrInvalidParameter, name)
+			}
+
+			return nil
+		}
+
+	case RuleMin, RuleMax:
+		actual, limit, ok, err := compareValues(value, argument)
+		if err != nil {
+			return fmt.Errorf("%w: \"%s\" of parameter \"%s\": %s", ErrUnknownRule, rule, name, err)
+		}
+		if !ok {
+			break
+		}
+
+		if ruleName == RuleMin && actual < limit {
+			return fmt.Errorf("%w: \"%s\" must be at least %s", ErrInvalidParameter, name, argument)
+		}
+		if ruleName == RuleMax && actual > limit {
+			return fmt.Errorf("%w: \"%s\" must be at most %s", ErrInvalidParameter, name, argument)
+		}
+
+		return nil
+
+	case RuleOneOf:
+		if value.Kind() != reflect.String {
+			break
+		}
+
+		allowed := strings.Fields(argument)
+		for _, allowedValue := range allowed {
+			if value.String() == allowedValue {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: \"%s\" must be one of [%s], got \"%s\"", ErrInvalidParameter, name, strings.Join(allowed, ", "), value.String())
+
+	case RuleURL:
+		switch {
+		case value.Kind() == reflect.String:
+			return validateURL(name, value.String())
+
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+			for i := 0; i < value.Len(); i++ {
+				if err := validateURL(fmt.Sprintf("%s[%d]", name, i), value.Index(i).String()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: \"%s\" can't be applied to parameter \"%s\"", ErrUnknownRule, rule, name)
+}
+
+// compareValues converts the value and the limit of a min/max rule to comparable numbers.
+// It returns false if the rule can't be applied to the type of the value.
+func compareValues(value reflect.Value, argument string) (float64, float64, bool, error) {
+	if value.Type() == durationType {
+		limit, err := time.ParseDuration(argument)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		return float64(value.Int()), float64(limit), true, nil
+	}
+
+	var actual float64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	default:
+		return 0, 0, false, nil
+	}
+
+	limit, err := strconv.ParseFloat(argument, 64)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	return actual, limit, true, nil
+}
@@ -0,0 +1,92 @@
+package validation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
+)
+
+type testParams struct {
+	Interval time.Duration `validate:"min=1ms"`
+	Provider string        `validate:"oneof=local remote"`
+	Endpoint string        `validate:"url"`
+	Webhooks []string      `validate:"url"`
+
+	TipSel struct {
+		RandomTips int     `validate:"min=0,max=6"`
+		Penalty    float64 `validate:"min=0,max=1"`
+	} `name:"tipsel"`
+
+	Backups struct {
+		FolderPath string `validate:"required"`
+	}
+}
+
+func validTestParams() *testParams {
+	params := &testParams{
+		Interval: time.Second,
+		Provider: "local",
+		Endpoint: "https://example.com/report",
+		Webhooks: []string{"http://localhost:8080", "https://example.com/hook?token=abc"},
+	}
+	params.TipSel.RandomTips = 3
+	params.TipSel.Penalty = 0.5
+	params.Backups.FolderPath = "backups"
+
+	return params
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, validation.Validate("coordinator", validTestParams()))
+
+	params := validTestParams()
+	params.Interval = 0
+	err := validation.Validate("coordinator", params)
+	require.ErrorIs(t, err, validation.ErrInvalidParameter)
+	require.Contains(t, err.Error(), "coordinator.interval")
+
+	params = validTestParams()
+	params.Provider = "hsm"
+	require.ErrorIs(t, validation.Validate("coordinator", params), validation.ErrInvalidParameter)
+
+	params = validTestParams()
+	params.Endpoint = "example.com/report"
+	require.ErrorIs(t, validation.Validate("coordinator", params), validation.ErrInvalidParameter)
+
+	params = validTestParams()
+	params.Webhooks = append(params.Webhooks, "ftp://example.com")
+	err = validation.Validate("coordinator", params)
+	require.ErrorIs(t, err, validation.ErrInvalidParameter)
+	require.Contains(t, err.Error(), "coordinator.webhooks[2]")
+
+	params = validTestParams()
+	params.TipSel.RandomTips = 7
+	err = validation.Validate("coordinator", params)
+	require.ErrorIs(t, err, validation.ErrInvalidParameter)
+	require.Contains(t, err.Error(), "coordinator.tipsel.randomTips")
+
+	params = validTestParams()
+	params.TipSel.Penalty = 1.5
+	require.ErrorIs(t, validation.Validate("coordinator", params), validation.ErrInvalidParameter)
+
+	params = validTestParams()
+	params.Backups.FolderPath = ""
+	err = validation.Validate("coordinator", params)
+	require.ErrorIs(t, err, validation.ErrInvalidParameter)
+	require.Contains(t, err.Error(), "coordinator.backups.folderPath")
+}
+
+func TestValidateUnknownRule(t *testing.T) {
+	params := &struct {
+		Enabled bool `validate:"min=1"`
+	}{}
+	require.ErrorIs(t, validation.Validate("test", params), validation.ErrUnknownRule)
+
+	invalidLimit := &struct {
+		Interval time.Duration `validate:"min=abc"`
+	}{}
+	require.ErrorIs(t, validation.Validate("test", invalidLimit), validation.ErrUnknownRule)
+}
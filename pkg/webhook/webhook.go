@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/generics/options"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// the maximum amount of bytes of the response body that are included in errors.
+const maxErrorBodySize = 512
+
+var (
+	// ErrDeliveryFailed is returned when a payload could not be delivered to at least one of the endpoints.
+	ErrDeliveryFailed = errors.New("webhook delivery failed")
+)
+
+// MigrationNotification notifies about a single migrated funds entry of a receipt whose milestone was confirmed.
+// The milestone index and the index of the entry within the receipt identify the notification,
+// so that the receivers can detect notifications that were delivered more than once.
+type MigrationNotification struct {
+	// the index of the milestone that contains the receipt.
+	MilestoneIndex iotago.MilestoneIndex `json:"milestoneIndex"`
+	// the legacy milestone index the funds were migrated at.
+	MigratedAt iotago.MilestoneIndex `json:"migratedAt"`
+	// the index of the entry within the receipt.
+	EntryIndex int `json:"entryIndex"`
+	// the tail transaction hash of the migration bundle (hex encoded).
+	TailTransactionHash string `json:"tailTransactionHash"`
+	// the target address of the migrated funds (bech32 encoded).
+	Address string `json:"address"`
+	// the amount of migrated funds.
+	Deposit uint64 `json:"deposit"`
+}
+
+// Notifications returns the notifications of the migrated funds entries of the receipt with a deposit of at least minDeposit.
+func Notifications(index iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt, minDeposit uint64, bech32HRP iotago.NetworkPrefix) []*MigrationNotification {
+	notifications := make([]*MigrationNotification, 0)
+	for i, entry := range receipt.Funds {
+		if entry.Deposit < minDeposit {
+			continue
+		}
+
+		notifications = append(notifications, &MigrationNotification{
+			MilestoneIndex:      index,
+			MigratedAt:          receipt.MigratedAt,
+			EntryIndex:          i,
+			TailTransactionHash: iotago.EncodeHex(entry.TailTransactionHash[:]),
+			Address:             entry.Address.Bech32(bech32HRP),
+			Deposit:             entry.Deposit,
+		})
+	}
+
+	return notifications
+}
+
+// Notifier delivers the notifications to the webhook endpoints.
+type Notifier struct {
+	// the URLs the payloads are posted to.
+	endpoints []string
+	// the HTTP client used for the requests.
+	httpClient *http.Client
+	// the amount of retries of a failed delivery to an endpoint.
+	retryAmount int
+	// the interval between the retries of a failed delivery.
+	retryInterval time.Duration
+}
+
+// WithRetries defines how often and in which interval a failed delivery to an endpoint is retried.
+func WithRetries(amount int, interval time.Duration) options.Option[Notifier] {
+	return func(n *Notifier) {
+		n.retryAmount = amount
+		n.retryInterval = interval
+	}
+}
+
+// NewNotifier creates a new Notifier that posts the payloads to the given endpoints.
+func NewNotifier(endpoints []string, timeout time.Duration, opts ...options.Option[Notifier]) *Notifier {
+	return options.Apply(&Notifier{
+		endpoints:  endpoints,
+		httpClient: &http.Client{Timeout: timeout},
+	}, opts)
+}
+
+// Notify posts the JSON encoded payload, e.g. a MigrationNotification, to all endpoints.
+// A failed delivery to an endpoint is retried, it doesn't prevent the delivery to the other endpoints.
+func (n *Notifier) Notify(ctx context.Context, payload any) error {
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, endpoint := range n.endpoints {
+		if err := n.deliver(ctx, endpoint, reqBody); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", endpoint, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %s", ErrDeliveryFailed, strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// deliver posts the request body to the endpoint and retries failed deliveries until the context is canceled.
+func (n *Notifier) deliver(ctx context.Context, endpoint string, reqBody []byte) error {
+	for attempt := 0; ; attempt++ {
+		err := n.post(ctx, endpoint, reqBody)
+		if err == nil || attempt >= n.retryAmount {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(n.retryInterval):
+		}
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, endpoint string, reqBody []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+
+		return fmt.Errorf("endpoint returned status code %d: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+
+	return nil
+}
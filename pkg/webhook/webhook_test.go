@@ -0,0 +1,86 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/inx-coordinator/pkg/webhook"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func TestNotifications(t *testing.T) {
+	address := &iotago.Ed25519Address{1}
+	receipt := &iotago.ReceiptMilestoneOpt{
+		MigratedAt: 42,
+		Funds: iotago.MigratedFundsEntries{
+			{TailTransactionHash: iotago.LegacyTailTransactionHash{1}, Address: address, Deposit: 1_000_000},
+			{TailTransactionHash: iotago.LegacyTailTransactionHash{2}, Address: address, Deposit: 5_000_000},
+			{TailTransactionHash: iotago.LegacyTailTransactionHash{3}, Address: address, Deposit: 4_999_999},
+		},
+	}
+
+	notifications := webhook.Notifications(100, receipt, 5_000_000, iotago.PrefixTestnet)
+	require.Equal(t, []*webhook.MigrationNotification{{
+		MilestoneIndex:      100,
+		MigratedAt:          42,
+		EntryIndex:          1,
+		TailTransactionHash: iotago.EncodeHex(receipt.Funds[1].TailTransactionHash[:]),
+		Address:             address.Bech32(iotago.PrefixTestnet),
+		Deposit:             5_000_000,
+	}}, notifications)
+
+	require.Len(t, webhook.Notifications(100, receipt, 0, iotago.PrefixTestnet), 3)
+}
+
+func TestNotifierNotify(t *testing.T) {
+	var lock sync.Mutex
+	var received []*webhook.MigrationNotification
+	failures := 2
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		// the first deliveries fail, so that they are retried
+		if failures > 0 {
+			failures--
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		notification := &webhook.MigrationNotification{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(notification))
+		received = append(received, notification)
+	}))
+	defer healthy.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "broken", http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	notification := &webhook.MigrationNotification{MilestoneIndex: 100, MigratedAt: 42, EntryIndex: 1, Deposit: 5_000_000}
+
+	notifier := webhook.NewNotifier([]string{healthy.URL}, time.Second, webhook.WithRetries(2, 10*time.Millisecond))
+	require.NoError(t, notifier.Notify(context.Background(), notification))
+	require.Equal(t, []*webhook.MigrationNotification{notification}, received)
+
+	// a broken endpoint doesn't prevent the delivery to the other endpoints
+	notifier = webhook.NewNotifier([]string{broken.URL, healthy.URL}, time.Second, webhook.WithRetries(1, 10*time.Millisecond))
+	err := notifier.Notify(context.Background(), notification)
+	require.ErrorIs(t, err, webhook.ErrDeliveryFailed)
+	require.Contains(t, err.Error(), broken.URL)
+	require.Contains(t, err.Error(), "broken")
+	require.NotContains(t, err.Error(), healthy.URL)
+	require.Len(t, received, 2)
+}
@@ -0,0 +1,121 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"go.uber.org/dig"
+	"google.golang.org/grpc"
+
+	"github.com/iotaledger/hive.go/core/app"
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+	"github.com/iotaledger/inx-coordinator/pkg/grpcapi"
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func init() {
+	Plugin = &app.Plugin{
+		Component: &app.Component{
+			Name:           "GRPCAPI",
+			DepsFunc:       func(cDeps dependencies) { deps = cDeps },
+			Params:         params,
+			InitConfigPars: initConfigPars,
+			Configure:      configure,
+			Run:            run,
+		},
+		IsEnabled: func() bool {
+			return ParamsGRPCAPI.Enabled
+		},
+	}
+}
+
+var (
+	Plugin *app.Plugin
+	deps   dependencies
+
+	receiptsServer *grpcapi.PendingReceiptsServer
+
+	// closures.
+	onReceiptPending *events.Closure
+)
+
+type dependencies struct {
+	dig.In
+	NodeBridge        *nodebridge.NodeBridge
+	Coordinator       *coordinator.Coordinator
+	EventQueueMetrics *eventqueue.Metrics
+}
+
+func initConfigPars(_ *dig.Container) error {
+	if !ParamsGRPCAPI.Enabled {
+		return nil
+	}
+
+	return validation.Validate("grpcAPI", ParamsGRPCAPI)
+}
+
+func configure() error {
+	receiptsServer = grpcapi.NewPendingReceiptsServer(ParamsGRPCAPI.SubscriberBufferSize,
+		grpcapi.WithOverflowPolicy(eventqueue.Policy(ParamsGRPCAPI.OverflowPolicy)),
+		grpcapi.WithQueueMetrics(deps.EventQueueMetrics),
+	)
+
+	onReceiptPending = events.NewClosure(func(index iotago.MilestoneIndex, timestamp time.Time, receipt *iotago.ReceiptMilestoneOpt) {
+		pendingReceipt, err := grpcapi.NewPendingReceipt(index, timestamp, receipt, deps.NodeBridge.ProtocolParameters())
+		if err != nil {
+			Plugin.LogWarnf("unable to publish pending receipt of legacy milestone %d: %s", receipt.MigratedAt, err)
+
+			return
+		}
+
+		receiptsServer.Publish(pendingReceipt)
+	})
+
+	return nil
+}
+
+func run() error {
+
+	if err := Plugin.App().Daemon().BackgroundWorker("GRPCAPI", func(ctx context.Context) {
+		Plugin.LogInfo("Starting gRPC API server ...")
+
+		listener, err := net.Listen("tcp", ParamsGRPCAPI.BindAddress)
+		if err != nil {
+			Plugin.LogErrorfAndExit("Starting gRPC API server failed: %s", err)
+		}
+
+		grpcServer := grpc.NewServer()
+		grpcapi.RegisterReceiptStreamServer(grpcServer, receiptsServer)
+
+		go func() {
+			Plugin.LogInfof("You can now access the gRPC API using: %s", ParamsGRPCAPI.BindAddress)
+			if err := grpcServer.Serve(listener); err != nil {
+				Plugin.LogErrorfAndExit("Stopped gRPC API server due to an error (%s)", err)
+			}
+		}()
+
+		deps.Coordinator.Events.ReceiptPending.Hook(onReceiptPending)
+
+		Plugin.LogInfo("Starting gRPC API server ... done")
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping gRPC API ...")
+
+		deps.Coordinator.Events.ReceiptPending.Detach(onReceiptPending)
+
+		// disconnect all subscribers, otherwise the graceful stop waits for their streams to end
+		receiptsServer.Close()
+		grpcServer.GracefulStop()
+
+		Plugin.LogInfo("Stopping gRPC API ... done")
+	}, daemon.PriorityStopGRPCAPI); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package grpcapi
+
+import (
+	"github.com/iotaledger/hive.go/core/app"
+)
+
+// ParametersGRPCAPI contains the definition of the parameters used by the coordinator gRPC API.
+type ParametersGRPCAPI struct {
+	// Enabled defines whether the gRPC API plugin is enabled.
+	Enabled bool `default:"false" usage:"whether the gRPC API plugin is enabled"`
+	// BindAddress defines the bind address on which the coordinator gRPC API listens on.
+	BindAddress string `default:"localhost:9092" usage:"the bind address on which the coordinator gRPC API listens on" validate:"required"`
+	// SubscriberBufferSize defines the amount of pending receipts that are buffered per subscriber.
+	SubscriberBufferSize int `default:"100" usage:"the amount of pending receipts that are buffered per subscriber" validate:"min=1"`
+	// OverflowPolicy defines what happens if the buffer of a subscriber is full.
+	OverflowPolicy string `default:"disconnect" usage:"what happens if the buffer of a subscriber is full (dropNewest = the new receipt is dropped, dropOldest = the oldest buffered receipt is dropped, disconnect = the subscriber is disconnected)" validate:"oneof=dropNewest dropOldest disconnect"`
+}
+
+var ParamsGRPCAPI = &ParametersGRPCAPI{}
+
+var params = &app.ComponentParams{
+	Params: map[string]any{
+		"grpcAPI": ParamsGRPCAPI,
+	},
+	Masked: nil,
+}
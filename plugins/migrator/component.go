@@ -3,18 +3,29 @@ package migrator
 import (
 	"context"
 	"fmt"
-	"net/http"
 
+	"github.com/pkg/errors"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/dig"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/iotaledger/hive.go/core/app"
 	"github.com/iotaledger/hive.go/core/app/pkg/shutdown"
+	"github.com/iotaledger/hive.go/core/events"
 	"github.com/iotaledger/hive.go/core/timeutil"
 	"github.com/iotaledger/hornet/v2/pkg/common"
 	validator "github.com/iotaledger/hornet/v2/pkg/model/migrator"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
 	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/envreport"
+	"github.com/iotaledger/inx-coordinator/pkg/fileperm"
 	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+	"github.com/iotaledger/inx-coordinator/pkg/shutdownreason"
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
 	legacyapi "github.com/iotaledger/iota.go/api"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
@@ -29,12 +40,13 @@ const (
 func init() {
 	Plugin = &app.Plugin{
 		Component: &app.Component{
-			Name:      "Migrator",
-			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
-			Params:    params,
-			Provide:   provide,
-			Configure: configure,
-			Run:       run,
+			Name:           "Migrator",
+			DepsFunc:       func(cDeps dependencies) { deps = cDeps },
+			Params:         params,
+			InitConfigPars: initConfigPars,
+			Provide:        provide,
+			Configure:      configure,
+			Run:            run,
 		},
 		IsEnabled: func() bool {
 			return ParamsMigrator.Enabled
@@ -48,38 +60,172 @@ var (
 
 	bootstrap  = flag.Bool(CfgMigratorBootstrap, false, "bootstrap the migration process")
 	startIndex = flag.Uint32(CfgMigratorStartIndex, 1, "index of the first milestone to migrate")
+
+	errorPolicy migrator.ErrorPolicy
 )
 
 type dependencies struct {
 	dig.In
-	MigratorService *migrator.Service
-	ShutdownHandler *shutdown.ShutdownHandler
+	MigratorService   *migrator.Service
+	CircuitBreaker    *migrator.CircuitBreakerQueryer `optional:"true"`
+	NetworkGuard      *migrator.NetworkGuardQueryer   `optional:"true"`
+	Coordinator       *coordinator.Coordinator        `optional:"true"`
+	ReceiptProofStore *coordinator.ReceiptProofStore  `optional:"true"`
+	NodeBridge        *nodebridge.NodeBridge
+	ShutdownHandler   *shutdown.ShutdownHandler
+	ShutdownReasons   *shutdownreason.Reporter
+}
+
+func initConfigPars(_ *dig.Container) error {
+	if !ParamsMigrator.Enabled {
+		return nil
+	}
+
+	if err := validation.Validate("migrator", ParamsMigrator); err != nil {
+		return err
+	}
+
+	if ParamsMigrator.LoadTest.Enabled && ParamsMigrator.Faucet.Enabled {
+		return errors.New("the synthetic migrations of the load test and the faucet can't be enabled at the same time")
+	}
+
+	policy, err := migrator.NewErrorPolicy(map[string]string{
+		migrator.ErrorClassNetwork:    ParamsMigrator.ErrorPolicy.Network,
+		migrator.ErrorClassValidation: ParamsMigrator.ErrorPolicy.Validation,
+		migrator.ErrorClassCritical:   ParamsMigrator.ErrorPolicy.Critical,
+	})
+	if err != nil {
+		return err
+	}
+	errorPolicy = policy
+
+	return nil
 }
 
 // provide provides the MigratorService as a singleton.
 func provide(c *dig.Container) error {
 
-	if err := c.Provide(func() *validator.Validator {
-		legacyAPI, err := legacyapi.ComposeAPI(legacyapi.HTTPClientSettings{
-			URI:    ParamsReceipts.Validator.API.Address,
-			Client: &http.Client{Timeout: ParamsReceipts.Validator.API.Timeout},
-		})
-		if err != nil {
-			Plugin.LogErrorfAndExit("failed to initialize API: %s", err)
+	switch {
+	case ParamsMigrator.LoadTest.Enabled:
+		if err := c.Provide(func(nodeBridge *nodebridge.NodeBridge) migrator.Queryer {
+			queryer, err := migrator.NewSyntheticQueryer(
+				nodeBridge.ProtocolParameters().NetworkName,
+				ParamsMigrator.LoadTest.PrivateNetworkNames,
+				ParamsMigrator.LoadTest.MilestoneInterval,
+				ParamsMigrator.LoadTest.EntriesPerMilestone,
+				ParamsMigrator.LoadTest.MinDeposit,
+				ParamsMigrator.LoadTest.MaxDeposit,
+				ParamsMigrator.LoadTest.Distribution,
+			)
+			if err != nil {
+				Plugin.LogErrorfAndExit("failed to initialize synthetic migrations: %s", err)
+			}
+			Plugin.LogWarn("generating synthetic migrations for load testing, do not use this in production!")
+
+			return queryer
+		}); err != nil {
+			return err
 		}
 
-		return validator.NewValidator(
-			legacyAPI,
-			ParamsReceipts.Validator.Coordinator.Address,
-			ParamsReceipts.Validator.Coordinator.MerkleTreeDepth,
-		)
-	}); err != nil {
-		return err
+	case ParamsMigrator.Faucet.Enabled:
+		type faucetResult struct {
+			dig.Out
+			Queryer migrator.Queryer
+			Faucet  *migrator.FaucetQueryer
+		}
+
+		if err := c.Provide(func(nodeBridge *nodebridge.NodeBridge) faucetResult {
+			faucet, err := migrator.NewFaucetQueryer(
+				nodeBridge.ProtocolParameters().NetworkName,
+				ParamsMigrator.Faucet.PrivateNetworkNames,
+				ParamsMigrator.Faucet.FilePath,
+				ParamsMigrator.Faucet.EntriesPerMilestone,
+				ParamsMigrator.Faucet.MaxPending,
+			)
+			if err != nil {
+				Plugin.LogErrorfAndExit("failed to initialize faucet migrations: %s", err)
+			}
+			Plugin.LogWarn("serving migrations requested via the REST API instead of querying the legacy node, do not use this in production!")
+
+			return faucetResult{Queryer: faucet, Faucet: faucet}
+		}); err != nil {
+			return err
+		}
+
+	default:
+		type queryerResult struct {
+			dig.Out
+			Queryer        migrator.Queryer
+			CircuitBreaker *migrator.CircuitBreakerQueryer
+			NetworkGuard   *migrator.NetworkGuardQueryer
+		}
+
+		if err := c.Provide(func(filePermissions *fileperm.Enforcer) queryerResult {
+			httpClient, err := newLegacyHTTPClient(filePermissions)
+			if err != nil {
+				Plugin.LogErrorfAndExit("failed to initialize the authentication to the legacy node: %s", err)
+			}
+
+			legacyAPI, err := legacyapi.ComposeAPI(legacyapi.HTTPClientSettings{
+				URI:    ParamsReceipts.Validator.API.Address,
+				Client: httpClient,
+			})
+			if err != nil {
+				Plugin.LogErrorfAndExit("failed to initialize API: %s", err)
+			}
+
+			// the validator can't be canceled, so a hung legacy node would block the shutdown until the HTTP client times out
+			var queryer migrator.Queryer = migrator.NewCancelableQueryer(validator.NewValidator(
+				legacyAPI,
+				ParamsReceipts.Validator.Coordinator.Address,
+				ParamsReceipts.Validator.Coordinator.MerkleTreeDepth,
+			))
+
+			var networkGuard *migrator.NetworkGuardQueryer
+			if ParamsMigrator.NetworkGuard.Enabled {
+				// migrations of a legacy node of another legacy network must never be fed into the coordinator
+				networkGuard = migrator.NewNetworkGuardQueryer(
+					queryer,
+					migrator.NewLegacyNodeInfoFunc(httpClient, ParamsReceipts.Validator.API.Address),
+					ParamsReceipts.Validator.Coordinator.Address,
+					ParamsMigrator.NetworkGuard.Interval,
+				)
+				queryer = networkGuard
+			}
+
+			var circuitBreaker *migrator.CircuitBreakerQueryer
+			if ParamsMigrator.CircuitBreaker.Enabled {
+				// the circuit breaker only wraps the legacy node, so cached migrations are available during outages
+				circuitBreaker = migrator.NewCircuitBreakerQueryer(
+					queryer,
+					ParamsMigrator.CircuitBreaker.FailureThreshold,
+					ParamsMigrator.CircuitBreaker.OpenPeriod,
+				)
+				queryer = circuitBreaker
+			}
+
+			if !ParamsMigrator.Cache.Enabled {
+				return queryerResult{Queryer: queryer, CircuitBreaker: circuitBreaker, NetworkGuard: networkGuard}
+			}
+
+			// migrations confirmed by a legacy milestone are immutable, so they only need to be fetched once
+			cachingQueryer, err := migrator.NewCachingQueryer(queryer, ParamsMigrator.Cache.FolderPath)
+			if err != nil {
+				Plugin.LogErrorfAndExit("failed to initialize migrations cache: %s", err)
+			}
+
+			return queryerResult{Queryer: cachingQueryer, CircuitBreaker: circuitBreaker, NetworkGuard: networkGuard}
+		}); err != nil {
+			return err
+		}
 	}
 
 	type serviceDeps struct {
 		dig.In
-		Validator *validator.Validator
+		Queryer           migrator.Queryer
+		EnvironmentReport *envreport.Report
+		FilePermissions   *fileperm.Enforcer
+		Storage           storage.Store `optional:"true"`
 	}
 
 	if err := c.Provide(func(deps serviceDeps) *migrator.Service {
@@ -88,15 +234,77 @@ func provide(c *dig.Container) error {
 		switch {
 		case maxReceiptEntries > iotago.MaxMigratedFundsEntryCount:
 			Plugin.LogErrorfAndExit("%s (set to %d) can be max %d", Plugin.App().Config().GetParameterPath(&(ParamsMigrator.ReceiptMaxEntries)), maxReceiptEntries, iotago.MaxMigratedFundsEntryCount)
-		case maxReceiptEntries <= 0:
-			Plugin.LogErrorfAndExit("%s must be greather than 0", Plugin.App().Config().GetParameterPath(&(ParamsMigrator.ReceiptMaxEntries)))
+		case maxReceiptEntries < 0:
+			Plugin.LogErrorfAndExit("%s must not be negative", Plugin.App().Config().GetParameterPath(&(ParamsMigrator.ReceiptMaxEntries)))
 		}
 
-		return migrator.NewService(
-			deps.Validator,
+		// refuse to run with state files that other users can access
+		if err := deps.FilePermissions.Enforce(fileperm.KindState,
+			ParamsMigrator.StateFilePath,
+			ParamsMigrator.IncludedHashesFilePath,
+			ParamsMigrator.FetchCheckpoint.FilePath,
+			ParamsMigrator.Faucet.FilePath,
+		); err != nil {
+			Plugin.LogErrorfAndExit("failed to verify migrator state files: %s", err)
+		}
+
+		// the stored version has to be reported before the state file is upgraded
+		deps.EnvironmentReport.AddStateFile("migrator", ParamsMigrator.StateFilePath, migrator.StateVersion)
+
+		// the backups that exceed the configured amount are removed before new ones are written
+		removed, err := retention.CleanupBackups(ParamsMigrator.StateFilePath, ParamsMigrator.StateBackups)
+		if err != nil {
+			Plugin.LogErrorfAndExit("failed to clean up migrator state file backups: %s", err)
+		}
+		if removed > 0 {
+			Plugin.LogInfof("removed %d backups of the migrator state file that exceed the configured amount", removed)
+		}
+
+		service := migrator.NewService(
+			deps.Queryer,
 			ParamsMigrator.StateFilePath,
 			ParamsMigrator.ReceiptMaxEntries,
 		)
+		service.SetStateBackups(ParamsMigrator.StateBackups)
+
+		// migrations that were already included in a receipt are rejected, even if the state was lost or the legacy node replays them
+		var includedHashes *migrator.IncludedHashes
+		if deps.Storage != nil {
+			includedHashes, err = migrator.OpenIncludedHashes(deps.Storage)
+		} else {
+			includedHashes, err = migrator.LoadIncludedHashes(ParamsMigrator.IncludedHashesFilePath)
+		}
+		if err != nil {
+			Plugin.LogErrorfAndExit("failed to load included tail transaction hashes: %s", err)
+		}
+		service.SetIncludedHashes(includedHashes)
+
+		if ParamsMigrator.FetchCheckpoint.Interval > 0 {
+			// long catch-ups resume from the latest verified legacy milestone instead of the latest receipt
+			service.SetFetchCheckpoint(ParamsMigrator.FetchCheckpoint.FilePath, ParamsMigrator.FetchCheckpoint.Interval)
+		}
+
+		if ParamsMigrator.MaxIndexJump > 0 {
+			service.SetMaxIndexJump(ParamsMigrator.MaxIndexJump)
+		}
+
+		service.SetSendingReceiptStuckThreshold(ParamsMigrator.SendingReceiptStuckThreshold)
+
+		// don't hammer the legacy node while the legacy network is idle
+		service.SetPollBackoff(migrator.PollBackoff{
+			Step:     ParamsMigrator.PollBackoff.Step,
+			MaxDelay: ParamsMigrator.PollBackoff.MaxDelay,
+		})
+
+		if ParamsMigrator.Screening.Enabled {
+			var screener migrator.AddressScreener = migrator.NewHTTPAddressScreener(ParamsMigrator.Screening.URL, ParamsMigrator.Screening.Timeout)
+			if ParamsMigrator.Screening.CacheTTL > 0 {
+				screener = migrator.NewCachingAddressScreener(screener, ParamsMigrator.Screening.CacheTTL)
+			}
+			service.SetAddressScreener(screener, ParamsMigrator.Screening.FailurePolicy == "closed")
+		}
+
+		return service
 	}); err != nil {
 		return err
 	}
@@ -111,34 +319,155 @@ func configure() error {
 		msIndex = startIndex
 	}
 
-	if err := deps.MigratorService.InitState(msIndex); err != nil {
+	if deps.NetworkGuard != nil {
+		checkLegacyNetwork()
+	}
+
+	// the mirror doesn't run a coordinator, so it can't check the network reset of the state
+	if deps.Coordinator != nil {
+		networkResetIndex := deps.Coordinator.NetworkResetIndex()
+		if networkResetIndex != 0 && *bootstrap {
+			Plugin.LogInfof("bootstrapping migrator after the network reset at milestone %d", networkResetIndex)
+		}
+		deps.MigratorService.SetNetworkResetIndex(networkResetIndex)
+	}
+
+	// the receipts confirmed by the network are only known if the coordinator stores the receipt proofs
+	if deps.ReceiptProofStore != nil {
+		receiptHistoryValidation, err := migrator.NewReceiptHistoryValidation(ParamsMigrator.ReceiptHistory.Validation, latestConfirmedReceipt)
+		if err != nil {
+			Plugin.LogErrorfAndExit("failed to initialize the receipt history validation: %s", err)
+		}
+		deps.MigratorService.SetReceiptHistoryValidation(receiptHistoryValidation)
+	}
+
+	deps.MigratorService.Events.ReceiptHistoryFallback.Hook(events.NewClosure(func(fallback *migrator.ReceiptHistoryFallback) {
+		if !fallback.Validated {
+			Plugin.Logger().Warnw("Receipt history pruned, the migrator state could not be validated",
+				"reason", fallback.Err.Error(),
+			)
+
+			return
+		}
+
+		Plugin.Logger().Warnw("Receipt history pruned, validated the migrator state against the included tail transaction hashes",
+			"reason", fallback.Err.Error(),
+			"includedMigratedAtIndex", fallback.IncludedMigratedAtIndex,
+			"includedHashesCount", fallback.IncludedHashesCount,
+		)
+	}))
+
+	if err := deps.MigratorService.InitState(Plugin.Daemon().ContextStopped(), msIndex); err != nil {
+		deps.ShutdownReasons.Report(shutdownreason.ComponentMigrator, err)
 		Plugin.LogFatalfAndExit("failed to initialize migrator: %s", err)
 	}
 
+	if checkpoint := deps.MigratorService.FetchCheckpoint(); checkpoint != nil {
+		Plugin.LogInfof("resuming to fetch migrations after legacy milestone %d, legacy milestones %d-%d were already verified", checkpoint.VerifiedIndex, checkpoint.MigratedAtIndex+1, checkpoint.VerifiedIndex)
+	}
+
+	deps.MigratorService.Events.IndexRangeSkipped.Hook(events.NewClosure(func(skipped *migrator.IndexRange) {
+		Plugin.LogDebugf("skipped legacy milestones %d-%d without migrations", skipped.StartIndex, skipped.EndIndex)
+	}))
+
+	deps.MigratorService.Events.IndexJumpDetected.Hook(events.NewClosure(func(jump *migrator.IndexJump) {
+		Plugin.LogWarnf("migrations of legacy milestone %d would move the migrated at index %d legacy milestones forward from %d, check that the legacy node is connected to the correct network and confirm the jump via the REST API", jump.ToIndex, jump.Len(), jump.FromIndex)
+	}))
+
+	deps.MigratorService.Events.MigrationHeld.Hook(events.NewClosure(func(held *migrator.HeldMigration) {
+		Plugin.LogWarnf("migration with tail transaction hash %s of legacy milestone %d is held for review: %s", iotago.EncodeHex(held.Entry.TailTransactionHash[:]), held.MigratedAt, held.Reason)
+	}))
+
+	deps.MigratorService.Events.Halted.Hook(events.NewClosure(func(halt *migrator.Halt) {
+		if halt.DumpErr != nil {
+			Plugin.LogErrorf("receipts halted, milestones are issued without receipts until the node is restarted: %s (failed to write diagnostic dump: %s)", halt.Err, halt.DumpErr)
+
+			return
+		}
+		Plugin.LogErrorf("receipts halted, milestones are issued without receipts until the node is restarted: %s (diagnostic dump written to %s)", halt.Err, halt.DumpFilePath)
+	}))
+
+	if deps.CircuitBreaker != nil {
+		deps.CircuitBreaker.Events.StateChanged.Hook(events.NewClosure(func(state string) {
+			switch state {
+			case migrator.CircuitStateOpen:
+				Plugin.LogWarnf("legacy node is unavailable, pausing queries for %v", ParamsMigrator.CircuitBreaker.OpenPeriod)
+			case migrator.CircuitStateHalfOpen:
+				Plugin.LogInfo("probing the legacy node ...")
+			default:
+				Plugin.LogInfo("legacy node is available again")
+			}
+		}))
+	}
+
 	return nil
 }
 
+// checkLegacyNetwork checks that the legacy node serves the configured legacy network before the migrator is started.
+func checkLegacyNetwork() {
+	ctx, cancel := context.WithTimeout(context.Background(), ParamsReceipts.Validator.API.Timeout)
+	defer cancel()
+
+	err := deps.NetworkGuard.Check(ctx)
+	switch {
+	case errors.Is(err, migrator.ErrLegacyNetworkMismatch):
+		Plugin.LogErrorfAndExit("refusing to fetch migrations from %s: %s", ParamsReceipts.Validator.API.Address, err)
+	case err != nil:
+		// the network is checked again before the first query
+		Plugin.LogWarnf("failed to check the legacy network of %s: %s", ParamsReceipts.Validator.API.Address, err)
+	default:
+		info := deps.NetworkGuard.Status().NodeInfo
+		Plugin.LogInfof("legacy node %s (%s %s) follows the coordinator %s at legacy milestone %d", ParamsReceipts.Validator.API.Address, info.AppName, info.AppVersion, info.CoordinatorAddress, info.LatestMilestoneIndex)
+	}
+}
+
 func run() error {
 
 	if err := Plugin.App().Daemon().BackgroundWorker(Plugin.Name, func(ctx context.Context) {
 		Plugin.LogInfof("Starting %s ... done", Plugin.Name)
 		deps.MigratorService.Start(ctx, func(err error) bool {
 
-			if err := common.IsCriticalError(err); err != nil {
-				deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("migrator plugin hit a critical error: %s", err), true)
-
-				return false
+			if errors.Is(err, migrator.ErrCircuitOpen) {
+				// the state changes of the circuit breaker are logged, so failing fast is not logged again
+				return timeutil.Sleep(ctx, ParamsMigrator.QueryCooldownPeriod)
 			}
 
 			if err := common.IsSoftError(err); err != nil {
 				deps.MigratorService.Events.SoftError.Trigger(err)
 			}
 
-			// lets just log the err and halt querying for a configured period
-			Plugin.LogWarn(err)
+			class, action := errorPolicy.Action(err)
+			switch action {
+			case migrator.ErrorActionTerminate:
+				reason := deps.ShutdownReasons.Report(shutdownreason.ComponentMigrator, err)
+				deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("migrator plugin hit a %s error: %s", class, reason), true)
+
+				return false
+
+			case migrator.ErrorActionAlert:
+				Plugin.LogErrorf("migrator plugin hit a %s error: %s", class, err)
+				deps.MigratorService.Events.ErrorAlert.Trigger(class, err)
+
+				return timeutil.Sleep(ctx, ParamsMigrator.QueryCooldownPeriod)
 
-			return timeutil.Sleep(ctx, ParamsMigrator.QueryCooldownPeriod)
+			case migrator.ErrorActionPause:
+				Plugin.LogWarnf("migrator plugin hit a %s error, pausing for %v: %s", class, ParamsMigrator.ErrorPolicy.PausePeriod, err)
+
+				return timeutil.Sleep(ctx, ParamsMigrator.ErrorPolicy.PausePeriod)
+
+			default:
+				// lets just log the err and halt querying for a configured period
+				Plugin.LogWarn(err)
+
+				return timeutil.Sleep(ctx, ParamsMigrator.QueryCooldownPeriod)
+			}
 		})
+
+		if ctx.Err() == nil {
+			// the service stopped on its own, e.g. because of invalid migrations that can't be skipped
+			reason := deps.ShutdownReasons.Report(shutdownreason.ComponentMigrator, nil)
+			deps.ShutdownHandler.SelfShutdown(reason.String(), true)
+		}
 		Plugin.LogInfof("Stopping %s ... done", Plugin.Name)
 	}, daemon.PriorityStopMigrator); err != nil {
 		return err
@@ -146,3 +475,43 @@ func run() error {
 
 	return nil
 }
+
+// latestConfirmedReceipt returns the receipt of the latest stored receipt proof as it is known to the node.
+// It returns migrator.ErrReceiptHistoryPruned if the node already pruned the milestone that contains the receipt.
+func latestConfirmedReceipt(ctx context.Context) (*migrator.ConfirmedReceipt, error) {
+	proof, err := deps.ReceiptProofStore.LatestProof()
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, nil
+	}
+
+	if pruningIndex := deps.NodeBridge.NodeStatus().GetMilestonesPruningIndex(); proof.MilestoneIndex <= pruningIndex {
+		return nil, fmt.Errorf("%w: the latest receipt is contained in milestone %d, the node pruned the milestones up to %d", migrator.ErrReceiptHistoryPruned, proof.MilestoneIndex, pruningIndex)
+	}
+
+	milestone, err := deps.NodeBridge.Milestone(ctx, proof.MilestoneIndex)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: the node doesn't know milestone %d of the latest receipt", migrator.ErrReceiptHistoryPruned, proof.MilestoneIndex)
+		}
+
+		return nil, fmt.Errorf("failed to read milestone %d of the latest receipt: %w", proof.MilestoneIndex, err)
+	}
+	if milestone == nil {
+		return nil, fmt.Errorf("%w: the node doesn't know milestone %d of the latest receipt", migrator.ErrReceiptHistoryPruned, proof.MilestoneIndex)
+	}
+
+	if milestone.MilestoneID != proof.MilestoneID {
+		return nil, fmt.Errorf("%w: milestone %d of the node has ID %s, the receipt proof has ID %s", migrator.ErrReceiptHistoryMismatch, proof.MilestoneIndex, iotago.EncodeHex(milestone.MilestoneID[:]), iotago.EncodeHex(proof.MilestoneID[:]))
+	}
+
+	for _, opt := range milestone.Milestone.Opts {
+		if receipt, ok := opt.(*iotago.ReceiptMilestoneOpt); ok {
+			return &migrator.ConfirmedReceipt{MilestoneIndex: proof.MilestoneIndex, Receipt: receipt}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: milestone %d of the node doesn't contain a receipt", migrator.ErrReceiptHistoryMismatch, proof.MilestoneIndex)
+}
@@ -0,0 +1,41 @@
+package migrator
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/inx-coordinator/pkg/fileperm"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+)
+
+// legacyCredentialEnvVar is the environment variable the credential of the legacy node API is loaded from, if no file is configured.
+const legacyCredentialEnvVar = "LEGACY_API_CREDENTIAL"
+
+// newLegacyHTTPClient returns the HTTP client used to query the legacy node, it authenticates the requests if configured.
+func newLegacyHTTPClient(filePermissions *fileperm.Enforcer) (migrator.HTTPClient, error) {
+	client := &http.Client{Timeout: ParamsReceipts.Validator.API.Timeout}
+
+	auth := ParamsReceipts.Validator.API.Auth
+	if auth.Type == migrator.AuthTypeNone {
+		return client, nil
+	}
+
+	source := migrator.EnvCredentialSource(legacyCredentialEnvVar)
+	if auth.FilePath != "" {
+		// the credential grants access to the legacy node, so it is protected like a key
+		if err := filePermissions.Enforce(fileperm.KindKey, auth.FilePath); err != nil {
+			return nil, err
+		}
+		source = migrator.FileCredentialSource(auth.FilePath)
+	}
+
+	authClient, err := migrator.NewAuthHTTPClient(client, auth.Type, source,
+		migrator.WithAuthHeader(auth.Header),
+		migrator.WithRefreshMargin(auth.RefreshMargin),
+	)
+	if err != nil {
+		return nil, err
+	}
+	Plugin.LogInfof("authenticating to the legacy node with %s", auth.Type)
+
+	return authClient, nil
+}
@@ -4,7 +4,6 @@ import (
 	"time"
 
 	"github.com/iotaledger/hive.go/core/app"
-	"github.com/iotaledger/inx-coordinator/pkg/migrator"
 )
 
 // ParametersMigrator contains the definition of the parameters used by Migrator.
@@ -13,16 +12,128 @@ type ParametersMigrator struct {
 	Enabled bool `default:"false" usage:"whether the migrator plugin is enabled"`
 	// StateFilePath defines the path to the state file of the migrator.
 	StateFilePath string `default:"migrator.state" usage:"path to the state file of the migrator"`
+	// StateBackups defines the amount of backups of the state file that are kept.
+	StateBackups int `default:"1" usage:"the amount of backups of the state file that are kept, the latest one is '<stateFilePath>_old', older ones are numbered ('<stateFilePath>_old.1', ...)" validate:"min=1"`
+	// IncludedHashesFilePath defines the path to the file of the tail transaction hashes of all migrations that were included in receipts.
+	IncludedHashesFilePath string `default:"migrator_included_hashes.bin" usage:"the path to the file of the tail transaction hashes of all migrations that were included in receipts"`
 	// ReceiptMaxEntries defines the max amount of entries to embed within a receipt.
-	ReceiptMaxEntries int `usage:"the max amount of entries to embed within a receipt"`
+	ReceiptMaxEntries int `default:"0" usage:"the max amount of entries to embed within a receipt, it is limited to the amount that fits into a milestone with the configured amount of parents and signatures (0 = the amount that keeps the milestones below the next PoW requirement step with the configured amount of parents and signatures)" validate:"min=0"`
+	// MaxIndexJump defines the maximum amount of legacy milestones the migrated at index may jump forward at once.
+	MaxIndexJump uint32 `default:"0" usage:"the maximum amount of legacy milestones the migrated at index may jump forward at once, larger jumps (e.g. caused by a legacy node of the wrong network) are held back until they are confirmed via the REST API (0 = disabled)"`
+	// SendingReceiptStuckThreshold defines the duration after which the coordinator is considered stuck inside the 'sending receipt' window.
+	SendingReceiptStuckThreshold time.Duration `default:"1m" usage:"the duration after which the coordinator is considered stuck between persisting the state of a receipt and the confirmation of its milestone, a crash in this window requires a manual recovery of the state (0 = disabled)" validate:"min=0s"`
 	// QueryCooldownPeriod defines the cooldown period for the service to ask for new data from the legacy node in case the migrator encounters an error.
 	QueryCooldownPeriod time.Duration `default:"5s" usage:"the cooldown period for the service to ask for new data from the legacy node in case the migrator encounters an error"`
-}
 
-var ParamsMigrator = &ParametersMigrator{
-	ReceiptMaxEntries: migrator.SensibleMaxEntriesCount,
+	// PollBackoff contains the parameters of the linear backoff of the polls of the legacy node while the legacy network is idle.
+	PollBackoff struct {
+		// Step defines the amount the delay between two polls is increased by after every poll that returned no new legacy milestone.
+		Step time.Duration `default:"500ms" usage:"the amount the delay between two polls of the legacy node is increased by after every poll that returned no new legacy milestone, the delay is reset once a new legacy milestone appears (0 = disabled)" validate:"min=0s"`
+		// MaxDelay defines the maximum delay between two polls.
+		MaxDelay time.Duration `default:"10s" usage:"the maximum delay between two polls of the legacy node" validate:"min=0s"`
+	}
+
+	// NetworkGuard contains the parameters of the check that the legacy node serves the configured legacy network.
+	NetworkGuard struct {
+		// Enabled defines whether the legacy network of the legacy node is checked.
+		Enabled bool `default:"true" usage:"whether it is checked on startup and periodically that the legacy node follows the configured legacy coordinator, no migrations are fetched from a legacy node of another legacy network"`
+		// Interval defines the interval in which the legacy network is checked again.
+		Interval time.Duration `default:"10m" usage:"the interval in which the legacy network of the legacy node is checked again" validate:"min=1s"`
+	}
+
+	// ErrorPolicy contains the actions that are taken if the migrator encounters an error of a certain class.
+	ErrorPolicy struct {
+		// Network defines the action for errors while querying the legacy node.
+		Network string `default:"retry" usage:"the action for errors while querying the legacy node (retry/pause/alert/terminate)" validate:"oneof=retry pause alert terminate"`
+		// Validation defines the action for migrations or states that failed the sanity checks.
+		Validation string `default:"terminate" usage:"the action for migrations or states that failed the sanity checks (alert/terminate), invalid stop indexes, invalid states and a legacy network mismatch are queried again after an alert, invalid migrations, already included migrations and receipts that don't fit into a milestone always stop the migrator" validate:"oneof=alert terminate"`
+		// Critical defines the action for critical errors.
+		Critical string `default:"terminate" usage:"the action for critical errors (retry/pause/alert/terminate)" validate:"oneof=retry pause alert terminate"`
+		// PausePeriod defines the period the migrator pauses querying the legacy node if the action is pause.
+		PausePeriod time.Duration `default:"1m" usage:"the period the migrator pauses querying the legacy node if the action is pause" validate:"min=1s"`
+	}
+
+	// Cache contains the parameters of the on-disk cache of the migrations queried from the legacy node.
+	Cache struct {
+		// Enabled defines whether the migrations queried from the legacy node are cached on disk.
+		Enabled bool `default:"true" usage:"whether the migrations queried from the legacy node are cached on disk"`
+		// FolderPath defines the path to the folder where the cached migrations are stored.
+		FolderPath string `default:"migrator_cache" usage:"the path to the folder where the cached migrations are stored"`
+	}
+
+	// FetchCheckpoint contains the parameters of the checkpoints of the fetch progress during long catch-ups.
+	FetchCheckpoint struct {
+		// FilePath defines the path to the file the fetch progress is persisted to.
+		FilePath string `default:"migrator_fetch_checkpoint.json" usage:"the path to the file the fetch progress is persisted to, separately from the state file"`
+		// Interval defines the amount of legacy milestones without migrations after which the fetch progress is persisted.
+		Interval uint32 `default:"1000" usage:"the amount of legacy milestones without migrations after which the fetch progress is persisted, so that a restart resumes fetching from there (0 = disabled)"`
+	}
+
+	// ReceiptHistory contains the parameters of the validation of the restored state against the receipts confirmed by the network.
+	ReceiptHistory struct {
+		// Validation defines how the state is validated if the node pruned the milestone of the latest receipt.
+		Validation string `default:"fallback" usage:"how the restored state is validated if the node pruned the milestone of the latest receipt (strict = refuse to start, fallback = validate against the included tail transaction hashes), requires the receipt proofs of the coordinator" validate:"oneof=strict fallback"`
+	}
+
+	// CircuitBreaker contains the parameters of the circuit breaker that stops querying the legacy node after repeated failures.
+	CircuitBreaker struct {
+		// Enabled defines whether the legacy node is queried through a circuit breaker.
+		Enabled bool `default:"true" usage:"whether the legacy node is queried through a circuit breaker"`
+		// FailureThreshold defines the amount of consecutive failed queries that open the circuit breaker.
+		FailureThreshold int `default:"5" usage:"the amount of consecutive failed queries that open the circuit breaker" validate:"min=1"`
+		// OpenPeriod defines the period after which an open circuit breaker probes the legacy node again.
+		OpenPeriod time.Duration `default:"1m" usage:"the period after which an open circuit breaker probes the legacy node again" validate:"min=1s"`
+	}
+
+	// Screening contains the parameters of the screening of the target addresses of the migrations.
+	Screening struct {
+		// Enabled defines whether the target addresses of the migrations are screened by an external service.
+		Enabled bool `default:"false" usage:"whether the target addresses of the migrations are screened by an external service, migrations to flagged addresses are held until they are released via the REST API"`
+		// URL defines the URL of the screening service.
+		URL string `default:"http://localhost:8090/screen" usage:"the URL of the screening service"`
+		// Timeout defines the timeout of the requests to the screening service.
+		Timeout time.Duration `default:"5s" usage:"the timeout of the requests to the screening service"`
+		// CacheTTL defines the time the verdicts of the screening service are cached.
+		CacheTTL time.Duration `default:"1h" usage:"the time the verdicts of the screening service are cached (0 = disabled)"`
+		// FailurePolicy defines what happens to migrations whose address could not be screened.
+		FailurePolicy string `default:"open" usage:"what happens to migrations whose address could not be screened (open = they pass, closed = they are held)" validate:"oneof=open closed"`
+	}
+
+	// LoadTest contains the parameters of the synthetic migrations used for load testing.
+	LoadTest struct {
+		// Enabled defines whether synthetic migrations are generated instead of querying the legacy node.
+		Enabled bool `default:"false" usage:"whether synthetic migrations are generated instead of querying the legacy node (use for load tests on private tangles only!)"`
+		// PrivateNetworkNames defines the names of the private tangles synthetic migrations are allowed on.
+		PrivateNetworkNames []string `default:"private_tangle1" usage:"the names of the private tangles synthetic migrations are allowed on, the migrator refuses to start on any other network"`
+		// MilestoneInterval defines the interval in which a new legacy milestone is simulated.
+		MilestoneInterval time.Duration `default:"10s" usage:"the interval in which a new legacy milestone is simulated"`
+		// EntriesPerMilestone defines the amount of migrations confirmed by every simulated legacy milestone.
+		EntriesPerMilestone int `default:"110" usage:"the amount of migrations confirmed by every simulated legacy milestone"`
+		// MinDeposit defines the minimum deposit of a synthetic migration.
+		MinDeposit uint64 `default:"1000000" usage:"the minimum deposit of a synthetic migration"`
+		// MaxDeposit defines the maximum deposit of a synthetic migration.
+		MaxDeposit uint64 `default:"1000000000" usage:"the maximum deposit of a synthetic migration"`
+		// Distribution defines the distribution of the deposits of synthetic migrations.
+		Distribution string `default:"uniform" usage:"the distribution of the deposits of synthetic migrations (uniform/exponential)"`
+	}
+
+	// Faucet contains the parameters of the migrations that are requested via the REST API.
+	Faucet struct {
+		// Enabled defines whether migrations requested via the REST API are served instead of querying the legacy node.
+		Enabled bool `default:"false" usage:"whether migrations requested via the REST API are served instead of querying the legacy node, e.g. to test the receipt handling of wallets (use on private tangles only!)"`
+		// PrivateNetworkNames defines the names of the private tangles the faucet is allowed on.
+		PrivateNetworkNames []string `default:"private_tangle1" usage:"the names of the private tangles the faucet is allowed on, the migrator refuses to start on any other network"`
+		// FilePath defines the path to the file the simulated legacy milestones of the requested migrations are persisted to.
+		FilePath string `default:"migrator_faucet.json" usage:"the path to the file the simulated legacy milestones of the requested migrations are persisted to" validate:"required"`
+		// EntriesPerMilestone defines the maximum amount of requested migrations confirmed by a simulated legacy milestone.
+		EntriesPerMilestone int `default:"110" usage:"the maximum amount of requested migrations confirmed by a simulated legacy milestone" validate:"min=1"`
+		// MaxPending defines the maximum amount of requested migrations that wait to be confirmed.
+		MaxPending int `default:"1000" usage:"the maximum amount of requested migrations that wait to be confirmed, further requests are rejected" validate:"min=1"`
+	}
 }
 
+var ParamsMigrator = &ParametersMigrator{}
+
 // ParametersReceipts contains the definition of the parameters used by Receipts.
 type ParametersReceipts struct {
 	Validator struct {
@@ -31,6 +142,16 @@ type ParametersReceipts struct {
 			Address string `default:"http://localhost:14266" usage:"address of the legacy node API to query for white-flag confirmation data"`
 			// Address defines the timeout of API calls.
 			Timeout time.Duration `default:"5s" usage:"timeout of API calls"`
+			Auth    struct {
+				// Type defines how the coordinator authenticates to the legacy node API.
+				Type string `default:"none" usage:"how the coordinator authenticates to the legacy node API (none/apiKey/jwt)" validate:"oneof=none apiKey jwt"`
+				// Header defines the header the credential is sent in.
+				Header string `default:"" usage:"the header the credential is sent in (empty = 'X-API-Key' for API keys, 'Authorization' with the 'Bearer' scheme for JWTs)"`
+				// FilePath defines the path to the file that contains the API key or the JWT.
+				FilePath string `default:"" usage:"the path to the file that contains the API key or the JWT, it is read again when the JWT is about to expire or the credential is rejected (empty = the LEGACY_API_CREDENTIAL environment variable is used)"`
+				// RefreshMargin defines the duration before the expiry of the JWT after which it is reloaded.
+				RefreshMargin time.Duration `default:"1m" usage:"the duration before the expiry of the JWT after which it is reloaded" validate:"min=0s"`
+			}
 		} `name:"api"`
 		Coordinator struct {
 			// Address defines the address of the legacy coordinator.
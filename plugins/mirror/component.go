@@ -0,0 +1,176 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/dig"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/hive.go/core/app"
+	"github.com/iotaledger/hive.go/core/app/pkg/shutdown"
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/hive.go/serializer/v2"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/nodecaps"
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
+	inx "github.com/iotaledger/inx/go"
+)
+
+const (
+	// CommandMirror is the command that runs the receipt mirror instead of the coordinator.
+	CommandMirror = "mirror"
+)
+
+func init() {
+	Plugin = &app.Plugin{
+		Component: &app.Component{
+			Name:           "ReceiptMirror",
+			DepsFunc:       func(cDeps dependencies) { deps = cDeps },
+			Params:         params,
+			InitConfigPars: initConfigPars,
+			Configure:      configure,
+			Run:            run,
+		},
+		IsEnabled: ShouldRun,
+	}
+}
+
+var (
+	Plugin *app.Plugin
+	deps   dependencies
+
+	mirror *migrator.Mirror
+)
+
+type dependencies struct {
+	dig.In
+	MigratorService  *migrator.Service `optional:"true"`
+	NodeBridge       *nodebridge.NodeBridge
+	NodeCapabilities *nodecaps.Capabilities
+	ShutdownHandler  *shutdown.ShutdownHandler
+}
+
+// ShouldRun checks if the receipt mirror was requested.
+// The mirror only runs the migrator and compares its receipts with the receipts on the network,
+// it never issues milestones, so no signing keys are needed.
+func ShouldRun() bool {
+	args := os.Args[1:]
+
+	return len(args) > 0 && strings.ToLower(args[0]) == CommandMirror
+}
+
+func initConfigPars(_ *dig.Container) error {
+	return validation.Validate("mirror", ParamsMirror)
+}
+
+func configure() error {
+	if deps.MigratorService == nil {
+		Plugin.LogFatalfAndExit("the receipt mirror needs the migrator plugin to be enabled")
+	}
+
+	if err := deps.NodeCapabilities.RequireAPIs(Plugin.App().Daemon().ContextStopped(), "the receipt mirror", nodecaps.APIListenToMigrationReceipts); err != nil {
+		Plugin.LogFatalfAndExit("failed to verify the node: %s", err)
+	}
+
+	mirror = migrator.NewMirror(deps.MigratorService.State())
+	Plugin.LogInfof("verifying the receipts starting at legacy milestone %d", mirror.StartIndex())
+
+	mirror.Events.ReceiptsVerified.Hook(events.NewClosure(func(result *migrator.MirrorResult) {
+		Plugin.LogInfof("receipts of legacy milestone %d verified, %d migrations", result.MigratedAt, result.NetworkEntriesCount)
+	}))
+
+	mirror.Events.ReceiptsMismatch.Hook(events.NewClosure(func(result *migrator.MirrorResult) {
+		Plugin.LogErrorf("receipts of legacy milestone %d do not match: %s", result.MigratedAt, result.Err)
+
+		if ParamsMirror.StopOnMismatch {
+			deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("receipts of legacy milestone %d do not match: %s", result.MigratedAt, result.Err), true)
+		}
+	}))
+
+	return nil
+}
+
+func run() error {
+
+	if err := Plugin.App().Daemon().BackgroundWorker("ReceiptMirror[Computed]", func(ctx context.Context) {
+		Plugin.LogInfo("Starting ReceiptMirror[Computed] ... done")
+
+		ticker := time.NewTicker(ParamsMirror.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				Plugin.LogInfo("Stopping ReceiptMirror[Computed] ... done")
+
+				return
+
+			case <-ticker.C:
+				for receipt := deps.MigratorService.Receipt(ctx); receipt != nil; receipt = deps.MigratorService.Receipt(ctx) {
+					mirror.AddComputedReceipt(receipt)
+
+					// the mirror never sends receipts, so the state is always consistent
+					if err := deps.MigratorService.PersistState(ctx, false); err != nil {
+						// the state is still written in the background if the mirror is stopped
+						if ctx.Err() == nil {
+							deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("unable to persist migrator state: %s", err), true)
+						}
+
+						return
+					}
+				}
+			}
+		}
+	}, daemon.PriorityStopMirror); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	if err := Plugin.App().Daemon().BackgroundWorker("ReceiptMirror[Network]", func(ctx context.Context) {
+		Plugin.LogInfo("Starting ReceiptMirror[Network] ... done")
+
+		if err := listenToMigrationReceipts(ctx); err != nil {
+			deps.ShutdownHandler.SelfShutdown(fmt.Sprintf("unable to listen to migration receipts: %s", err), true)
+		}
+
+		Plugin.LogInfo("Stopping ReceiptMirror[Network] ... done")
+	}, daemon.PriorityStopMirror); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	return nil
+}
+
+// listenToMigrationReceipts adds the receipts of the milestones confirmed by the node to the mirror.
+func listenToMigrationReceipts(ctx context.Context) error {
+	stream, err := deps.NodeBridge.Client().ListenToMigrationReceipts(ctx, &inx.NoParams{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		rawReceipt, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled || ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		receipt, err := rawReceipt.UnwrapReceipt(serializer.DeSeriModeNoValidation, deps.NodeBridge.ProtocolParameters())
+		if err != nil {
+			return fmt.Errorf("unable to deserialize receipt: %w", err)
+		}
+
+		mirror.AddNetworkReceipt(receipt)
+	}
+}
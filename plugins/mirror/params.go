@@ -0,0 +1,24 @@
+package mirror
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/core/app"
+)
+
+// ParametersMirror contains the definition of the parameters used by the receipt mirror.
+type ParametersMirror struct {
+	// PollInterval defines the interval the computed receipts are fetched from the migrator.
+	PollInterval time.Duration `default:"1s" usage:"the interval the computed receipts are fetched from the migrator" validate:"min=10ms"`
+	// StopOnMismatch defines whether the mirror is stopped if the receipts on the network differ from the computed receipts.
+	StopOnMismatch bool `default:"false" usage:"whether the mirror is stopped if the receipts on the network differ from the computed receipts"`
+}
+
+var ParamsMirror = &ParametersMirror{}
+
+var params = &app.ComponentParams{
+	Params: map[string]any{
+		"mirror": ParamsMirror,
+	},
+	Masked: nil,
+}
@@ -0,0 +1,142 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/dig"
+
+	"github.com/iotaledger/hive.go/core/app"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/mselection"
+	"github.com/iotaledger/inx-coordinator/pkg/scheduler"
+)
+
+// routeMetrics is the route for getting the prometheus metrics.
+// GET returns metrics.
+const (
+	routeMetrics = "/metrics"
+)
+
+func init() {
+	Plugin = &app.Plugin{
+		Component: &app.Component{
+			Name:      "Prometheus",
+			DepsFunc:  func(cDeps dependencies) { deps = cDeps },
+			Params:    params,
+			Configure: configure,
+			Run:       run,
+		},
+		IsEnabled: func() bool {
+			return ParamsPrometheus.Enabled
+		},
+	}
+}
+
+var (
+	Plugin *app.Plugin
+	deps   dependencies
+
+	registry = prometheus.NewRegistry()
+)
+
+type dependencies struct {
+	dig.In
+	Coordinator       *coordinator.Coordinator
+	Selector          *mselection.HeaviestSelector
+	MigratorService   *migrator.Service               `optional:"true"`
+	CircuitBreaker    *migrator.CircuitBreakerQueryer `optional:"true"`
+	EventQueueMetrics *eventqueue.Metrics
+	Scheduler         *scheduler.Scheduler
+}
+
+func configure() error {
+	if ParamsPrometheus.CoordinatorMetrics {
+		configureCoordinator()
+	}
+	if ParamsPrometheus.TipSelectionMetrics {
+		configureTipSelection()
+	}
+	if ParamsPrometheus.MigratorMetrics && deps.MigratorService != nil {
+		configureMigrator()
+		configureReceipts()
+	}
+	if ParamsPrometheus.EventListenerMetrics {
+		configureEventListeners()
+	}
+	if ParamsPrometheus.SchedulerMetrics {
+		configureScheduler()
+	}
+	if ParamsPrometheus.GoMetrics {
+		registry.MustRegister(collectors.NewGoCollector())
+	}
+	if ParamsPrometheus.ProcessMetrics {
+		registry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	}
+
+	return nil
+}
+
+func run() error {
+	Plugin.LogInfo("Starting Prometheus exporter ...")
+
+	if err := Plugin.Daemon().BackgroundWorker("Prometheus exporter", func(ctx context.Context) {
+		Plugin.LogInfo("Starting Prometheus exporter ... done")
+
+		e := echo.New()
+		e.HideBanner = true
+		e.Use(middleware.Recover())
+
+		e.GET(routeMetrics, func(c echo.Context) error {
+			handler := promhttp.HandlerFor(
+				registry,
+				promhttp.HandlerOpts{
+					EnableOpenMetrics: true,
+				},
+			)
+
+			if ParamsPrometheus.PromhttpMetrics {
+				handler = promhttp.InstrumentMetricHandler(registry, handler)
+			}
+
+			handler.ServeHTTP(c.Response().Writer, c.Request())
+
+			return nil
+		})
+
+		go func() {
+			Plugin.LogInfof("You can now access the Prometheus exporter using: http://%s/metrics", ParamsPrometheus.BindAddress)
+			if err := e.Start(ParamsPrometheus.BindAddress); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				Plugin.LogWarnf("Stopped Prometheus exporter due to an error (%s)", err)
+			}
+		}()
+
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping Prometheus exporter ...")
+
+		shutdownCtx, shutdownCtxCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCtxCancel()
+
+		//nolint:contextcheck // false positive
+		err := e.Shutdown(shutdownCtx)
+		if err != nil {
+			Plugin.LogWarn(err)
+		}
+
+		Plugin.LogInfo("Stopping Prometheus exporter ... done")
+	}, daemon.PriorityStopPrometheus); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 package prometheus
 
-/*
 import (
 	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
 )
 
 var (
@@ -11,7 +13,15 @@ var (
 	coordinatorQuorumErrorCounter       prometheus.Counter
 	coordinatorQuorumNodesResponseTimes *prometheus.HistogramVec
 	coordinatorQuorumNodesErrorCounters *prometheus.CounterVec
+	coordinatorQuorumNodesScores        *prometheus.GaugeVec
+	coordinatorQuorumNodesDemoted       *prometheus.GaugeVec
 	coordinatorSoftErrEncountered       prometheus.Counter
+	coordinatorMilestonesSkipped        prometheus.Counter
+	coordinatorClockDrifts              prometheus.Counter
+	coordinatorPoWAttemptDurations      *prometheus.HistogramVec
+	coordinatorMilestoneStageDurations  *prometheus.HistogramVec
+	coordinatorLatencyBudgetExceeded    *prometheus.CounterVec
+	coordinatorMilestoneDegradations    *prometheus.CounterVec
 )
 
 func configureCoordinator() {
@@ -54,6 +64,26 @@ func configureCoordinator() {
 		[]string{"group", "alias", "baseURL"},
 	)
 
+	coordinatorQuorumNodesScores = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "coordinator",
+			Name:      "quorum_nodes_scores",
+			Help:      "Score of the quorum clients over the scoring window, by type (disagreement_rate, failure_rate, average_response_time [s]).",
+		},
+		[]string{"group", "alias", "baseURL", "type"},
+	)
+
+	coordinatorQuorumNodesDemoted = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "coordinator",
+			Name:      "quorum_nodes_demoted",
+			Help:      "Whether the quorum client was demoted from the active quorum set.",
+		},
+		[]string{"group", "alias", "baseURL"},
+	)
+
 	coordinatorSoftErrEncountered = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "iota",
@@ -63,13 +93,81 @@ func configureCoordinator() {
 		},
 	)
 
+	coordinatorMilestonesSkipped = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "coordinator",
+			Name:      "milestones_skipped_count",
+			Help:      "The count of milestones that were skipped because the node was not synced or lagging behind.",
+		},
+	)
+
+	coordinatorClockDrifts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "coordinator",
+			Name:      "clock_drift_count",
+			Help:      "The count of milestones that were delayed or skipped because the system clock was behind the latest milestone.",
+		},
+	)
+
+	coordinatorPoWAttemptDurations = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "coordinator",
+			Name:      "pow_attempt_duration",
+			Help:      "Durations of the attempts to compute the nonce of checkpoint blocks. [s]",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"provider", "worker", "result"},
+	)
+
+	coordinatorMilestoneStageDurations = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "coordinator",
+			Name:      "milestone_stage_duration",
+			Help:      "Durations of the stages of the milestone issuance. [s]",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"stage"},
+	)
+
+	coordinatorLatencyBudgetExceeded = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "coordinator",
+			Name:      "latency_budget_exceeded_count",
+			Help:      "The count of milestone stages that exceeded their latency budget.",
+		},
+		[]string{"stage"},
+	)
+
+	coordinatorMilestoneDegradations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "coordinator",
+			Name:      "milestone_degradation_count",
+			Help:      "The count of milestones that were degraded to stay within their latency budget.",
+		},
+		[]string{"degradation"},
+	)
+
 	registry.MustRegister(coordinatorQuorumResponseTime)
 	registry.MustRegister(coordinatorQuorumErrorCounter)
 	registry.MustRegister(coordinatorQuorumNodesResponseTimes)
 	registry.MustRegister(coordinatorQuorumNodesErrorCounters)
+	registry.MustRegister(coordinatorQuorumNodesScores)
+	registry.MustRegister(coordinatorQuorumNodesDemoted)
 	registry.MustRegister(coordinatorSoftErrEncountered)
+	registry.MustRegister(coordinatorMilestonesSkipped)
+	registry.MustRegister(coordinatorClockDrifts)
+	registry.MustRegister(coordinatorPoWAttemptDurations)
+	registry.MustRegister(coordinatorMilestoneStageDurations)
+	registry.MustRegister(coordinatorLatencyBudgetExceeded)
+	registry.MustRegister(coordinatorMilestoneDegradations)
 
-	deps.Coordinator.Events.QuorumFinished.Attach(events.NewClosure(func(result *coordinator.QuorumFinishedResult) {
+	deps.Coordinator.Events.QuorumFinished.Hook(events.NewClosure(func(result *coordinator.QuorumFinishedResult) {
 
 		coordinatorQuorumResponseTime.Observe(result.Duration.Seconds())
 		if result.Err != nil {
@@ -94,11 +192,65 @@ func configureCoordinator() {
 			if entry.Error != nil {
 				coordinatorQuorumNodesErrorCounters.With(labelsErrorCounters).Inc()
 			}
+
+			if entry.Score == nil {
+				continue
+			}
+
+			for scoreType, value := range map[string]float64{
+				"disagreement_rate":     entry.Score.DisagreementRate(),
+				"failure_rate":          entry.Score.FailureRate(),
+				"average_response_time": entry.Score.AverageResponseTime.Seconds(),
+			} {
+				coordinatorQuorumNodesScores.With(prometheus.Labels{
+					"group":   entry.Group,
+					"alias":   entry.Alias,
+					"baseURL": entry.BaseURL,
+					"type":    scoreType,
+				}).Set(value)
+			}
+
+			var demoted float64
+			if entry.Score.Demoted {
+				demoted = 1
+			}
+			coordinatorQuorumNodesDemoted.With(prometheus.Labels{
+				"group":   entry.Group,
+				"alias":   entry.Alias,
+				"baseURL": entry.BaseURL,
+			}).Set(demoted)
 		}
 	}))
 
-	deps.Coordinator.Events.SoftError.Attach(events.NewClosure(func(_ error) {
+	deps.Coordinator.Events.SoftError.Hook(events.NewClosure(func(_ error) {
 		coordinatorSoftErrEncountered.Inc()
 	}))
+
+	deps.Coordinator.Events.MilestoneSkipped.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, _ error) {
+		coordinatorMilestonesSkipped.Inc()
+	}))
+
+	deps.Coordinator.Events.PoWAttempt.Hook(events.NewClosure(func(attempt *coordinator.PoWAttempt) {
+		result := "success"
+		if attempt.Err != nil {
+			result = "failure"
+		}
+		coordinatorPoWAttemptDurations.WithLabelValues(attempt.Provider, attempt.Worker, result).Observe(attempt.Duration.Seconds())
+	}))
+
+	deps.Coordinator.Events.ClockDriftDetected.Hook(events.NewClosure(func(_ *coordinator.ClockDrift) {
+		coordinatorClockDrifts.Inc()
+	}))
+
+	deps.Coordinator.Events.MilestoneTimings.Hook(events.NewClosure(func(timings *coordinator.MilestoneTimings) {
+		for _, stage := range timings.Stages {
+			coordinatorMilestoneStageDurations.WithLabelValues(stage.Stage).Observe(stage.Duration.Seconds())
+			if stage.Exceeded {
+				coordinatorLatencyBudgetExceeded.WithLabelValues(stage.Stage).Inc()
+			}
+		}
+		for _, degradation := range timings.Degradations {
+			coordinatorMilestoneDegradations.WithLabelValues(degradation).Inc()
+		}
+	}))
 }
-*/
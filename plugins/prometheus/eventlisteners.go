@@ -0,0 +1,39 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+)
+
+func configureEventListeners() {
+	for _, listener := range []string{eventqueue.ListenerWebSocket, eventqueue.ListenerJournal, eventqueue.ListenerGRPC, eventqueue.ListenerWebhook} {
+		listener := listener
+
+		registry.MustRegister(prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Namespace:   "iota",
+				Subsystem:   "event_listeners",
+				Name:        "dropped_events",
+				Help:        "The amount of events that were dropped, because the listeners didn't keep up.",
+				ConstLabels: prometheus.Labels{"listener": listener},
+			},
+			func() float64 {
+				return float64(deps.EventQueueMetrics.Dropped(listener))
+			},
+		))
+
+		registry.MustRegister(prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Namespace:   "iota",
+				Subsystem:   "event_listeners",
+				Name:        "disconnected",
+				Help:        "The amount of listeners that were disconnected, because they didn't keep up.",
+				ConstLabels: prometheus.Labels{"listener": listener},
+			},
+			func() float64 {
+				return float64(deps.EventQueueMetrics.Disconnected(listener))
+			},
+		))
+	}
+}
@@ -1,16 +1,28 @@
 package prometheus
 
-/*
 import (
 	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
 	iotago "github.com/iotaledger/iota.go/v3"
 )
 
 var (
 	migratorSoftErrEncountered     prometheus.Counter
+	migratorErrorAlerts            *prometheus.CounterVec
+	migratedEntries                prometheus.GaugeFunc
+	migratedValue                  prometheus.GaugeFunc
+	migratorSkippedIndices         prometheus.CounterFunc
+	migratorSkippedIndexRanges     prometheus.Counter
+	migratorHalted                 prometheus.GaugeFunc
+	migratorSendingReceiptSeconds  prometheus.GaugeFunc
+	migratorSendingReceiptStuck    prometheus.GaugeFunc
+	migratorCircuitBreakerState    prometheus.GaugeFunc
+	migratorCircuitBreakerOpened   prometheus.CounterFunc
 	receiptCount                   prometheus.Counter
 	receiptMigrationEntriesApplied prometheus.Counter
+	receiptSize                    prometheus.Histogram
 )
 
 func configureMigrator() {
@@ -23,11 +35,167 @@ func configureMigrator() {
 		},
 	)
 
+	migratorErrorAlerts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "error_alert_count",
+			Help:      "The count of errors the migrator service raised an alert for.",
+		},
+		[]string{"class"},
+	)
+
+	// the cumulative values are persisted in the migrator state, so they survive restarts
+	migratedEntries = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "migrated_entries_count",
+			Help:      "The cumulative count of migration entries included in receipts over the lifetime of the migration.",
+		},
+		func() float64 {
+			return float64(deps.MigratorService.State().MigratedEntriesCount)
+		},
+	)
+
+	migratedValue = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "migrated_value",
+			Help:      "The cumulative value of the migration entries included in receipts over the lifetime of the migration.",
+		},
+		func() float64 {
+			return float64(deps.MigratorService.State().MigratedValue)
+		},
+	)
+
+	migratorSkippedIndices = prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "skipped_indices_count",
+			Help:      "The count of legacy milestones without migrations that were skipped.",
+		},
+		func() float64 {
+			return float64(deps.MigratorService.SkippedIndicesCount())
+		},
+	)
+
+	migratorSkippedIndexRanges = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "skipped_index_ranges_count",
+			Help:      "The count of skipped ranges of legacy milestones without migrations.",
+		},
+	)
+
+	migratorHalted = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "halted",
+			Help:      "Whether the receipt issuance is halted because a migration result could not be applied to the state (1 = halted).",
+		},
+		func() float64 {
+			if deps.MigratorService.Halted() != nil {
+				return 1
+			}
+
+			return 0
+		},
+	)
+
+	migratorSendingReceiptSeconds = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "sending_receipt_seconds",
+			Help:      "The time the coordinator has been between persisting the state of a receipt and the confirmation of its milestone (0 = not sending a receipt).",
+		},
+		func() float64 {
+			return deps.MigratorService.SendingReceiptWindow().Duration.Seconds()
+		},
+	)
+
+	migratorSendingReceiptStuck = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "sending_receipt_stuck",
+			Help:      "Whether the coordinator has been sending a receipt for longer than the stuck threshold (1 = stuck).",
+		},
+		func() float64 {
+			if deps.MigratorService.SendingReceiptWindow().Stuck {
+				return 1
+			}
+
+			return 0
+		},
+	)
+
 	registry.MustRegister(migratorSoftErrEncountered)
+	registry.MustRegister(migratorErrorAlerts)
+	registry.MustRegister(migratedEntries)
+	registry.MustRegister(migratedValue)
+	registry.MustRegister(migratorSkippedIndices)
+	registry.MustRegister(migratorSkippedIndexRanges)
+	registry.MustRegister(migratorHalted)
+	registry.MustRegister(migratorSendingReceiptSeconds)
+	registry.MustRegister(migratorSendingReceiptStuck)
+
+	if deps.CircuitBreaker != nil {
+		configureCircuitBreaker()
+	}
 
-	deps.MigratorService.Events.SoftError.Attach(events.NewClosure(func(_ error) {
+	deps.MigratorService.Events.SoftError.Hook(events.NewClosure(func(_ error) {
 		migratorSoftErrEncountered.Inc()
 	}))
+
+	deps.MigratorService.Events.ErrorAlert.Hook(events.NewClosure(func(class string, _ error) {
+		migratorErrorAlerts.WithLabelValues(class).Inc()
+	}))
+
+	deps.MigratorService.Events.IndexRangeSkipped.Hook(events.NewClosure(func(_ *migrator.IndexRange) {
+		migratorSkippedIndexRanges.Inc()
+	}))
+}
+
+// circuitBreakerStateValues are the values of the circuit breaker state metric.
+var circuitBreakerStateValues = map[string]float64{
+	migrator.CircuitStateClosed:   0,
+	migrator.CircuitStateHalfOpen: 1,
+	migrator.CircuitStateOpen:     2,
+}
+
+func configureCircuitBreaker() {
+	migratorCircuitBreakerState = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "circuit_breaker_state",
+			Help:      "The state of the circuit breaker of the legacy node queries (0 = closed, 1 = half-open, 2 = open).",
+		},
+		func() float64 {
+			return circuitBreakerStateValues[deps.CircuitBreaker.Status().State]
+		},
+	)
+
+	migratorCircuitBreakerOpened = prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "circuit_breaker_opened_count",
+			Help:      "The count of times the circuit breaker of the legacy node queries was opened.",
+		},
+		func() float64 {
+			return float64(deps.CircuitBreaker.Status().OpenedCount)
+		},
+	)
+
+	registry.MustRegister(migratorCircuitBreakerState)
+	registry.MustRegister(migratorCircuitBreakerOpened)
 }
 
 func configureReceipts() {
@@ -36,7 +204,7 @@ func configureReceipts() {
 			Namespace: "iota",
 			Subsystem: "migrator",
 			Name:      "receipt_count",
-			Help:      "The count of encountered receipts.",
+			Help:      "The count of issued receipts.",
 		},
 	)
 
@@ -49,12 +217,23 @@ func configureReceipts() {
 		},
 	)
 
+	receiptSize = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "migrator",
+			Name:      "receipt_size_bytes",
+			Help:      "The serialized size of issued receipts. [bytes]",
+			Buckets:   prometheus.LinearBuckets(0, float64(iotago.BlockBinSerializedMaxSize)/16, 17),
+		},
+	)
+
 	registry.MustRegister(receiptCount)
 	registry.MustRegister(receiptMigrationEntriesApplied)
+	registry.MustRegister(receiptSize)
 
-	deps.Tangle.Events.NewReceipt.Attach(events.NewClosure(func(r *iotago.Receipt) {
+	deps.Coordinator.Events.ReceiptIssued.Hook(events.NewClosure(func(_ iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt, size int) {
 		receiptCount.Inc()
-		receiptMigrationEntriesApplied.Add(float64(len(r.Funds)))
+		receiptMigrationEntriesApplied.Add(float64(len(receipt.Funds)))
+		receiptSize.Observe(float64(size))
 	}))
 }
-*/
@@ -0,0 +1,39 @@
+package prometheus
+
+import (
+	"github.com/iotaledger/hive.go/core/app"
+)
+
+// ParametersPrometheus contains the definition of the parameters used by Prometheus.
+type ParametersPrometheus struct {
+	// Enabled defines whether the prometheus plugin is enabled.
+	Enabled bool `default:"false" usage:"whether the prometheus plugin is enabled"`
+	// BindAddress defines the bind address on which the Prometheus exporter listens on.
+	BindAddress string `default:"localhost:9312" usage:"the bind address on which the Prometheus HTTP server listens on"`
+
+	// CoordinatorMetrics defines whether to include coordinator metrics.
+	CoordinatorMetrics bool `default:"true" usage:"whether to include coordinator metrics"`
+	// TipSelectionMetrics defines whether to include tip selection metrics.
+	TipSelectionMetrics bool `default:"true" usage:"whether to include tip selection metrics"`
+	// MigratorMetrics defines whether to include migrator metrics.
+	MigratorMetrics bool `default:"true" usage:"whether to include migrator metrics"`
+	// EventListenerMetrics defines whether to include metrics of the queues of the event listeners.
+	EventListenerMetrics bool `default:"true" usage:"whether to include metrics of the queues of the event listeners"`
+	// SchedulerMetrics defines whether to include the runtime metrics of the background tasks.
+	SchedulerMetrics bool `default:"true" usage:"whether to include the runtime metrics of the background tasks"`
+	// GoMetrics defines whether to include go metrics.
+	GoMetrics bool `default:"false" usage:"whether to include go metrics"`
+	// ProcessMetrics defines whether to include process metrics.
+	ProcessMetrics bool `default:"false" usage:"whether to include process metrics"`
+	// PromhttpMetrics defines whether to include promhttp metrics.
+	PromhttpMetrics bool `default:"false" usage:"whether to include promhttp metrics"`
+}
+
+var ParamsPrometheus = &ParametersPrometheus{}
+
+var params = &app.ComponentParams{
+	Params: map[string]any{
+		"prometheus": ParamsPrometheus,
+	},
+	Masked: nil,
+}
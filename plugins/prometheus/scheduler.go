@@ -0,0 +1,61 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/scheduler"
+)
+
+var (
+	schedulerTaskRuntimes *prometheus.HistogramVec
+	schedulerTaskWaits    *prometheus.HistogramVec
+	schedulerTaskFailures *prometheus.CounterVec
+)
+
+func configureScheduler() {
+
+	schedulerTaskRuntimes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "scheduler",
+			Name:      "task_runtime",
+			Help:      "The duration the background tasks were running, by task. [s]",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"task", "priority"},
+	)
+
+	schedulerTaskWaits = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "scheduler",
+			Name:      "task_wait",
+			Help:      "The duration the background tasks were queued before they were run, by task. [s]",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"task", "priority"},
+	)
+
+	schedulerTaskFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "scheduler",
+			Name:      "task_failures",
+			Help:      "The amount of runs of the background tasks that failed, by task.",
+		},
+		[]string{"task"},
+	)
+
+	registry.MustRegister(schedulerTaskRuntimes)
+	registry.MustRegister(schedulerTaskWaits)
+	registry.MustRegister(schedulerTaskFailures)
+
+	deps.Scheduler.Events.TaskFinished.Hook(events.NewClosure(func(run *scheduler.TaskRun) {
+		schedulerTaskRuntimes.WithLabelValues(run.Task, run.Priority.String()).Observe(run.Runtime.Seconds())
+		schedulerTaskWaits.WithLabelValues(run.Task, run.Priority.String()).Observe(run.Wait.Seconds())
+		if run.Err != nil {
+			schedulerTaskFailures.WithLabelValues(run.Task).Inc()
+		}
+	}))
+}
@@ -0,0 +1,98 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/mselection"
+)
+
+var (
+	tipSelectionCandidates         prometheus.Gauge
+	tipSelectionDuration           prometheus.Histogram
+	tipSelectionUnreferencedBlocks prometheus.Histogram
+	tipSelectionConeScore          prometheus.Histogram
+	tipSelectionConeAge            prometheus.Histogram
+	tipSelectionLazyTipsCount      prometheus.Counter
+)
+
+func configureTipSelection() {
+	tipSelectionCandidates = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "iota",
+			Subsystem: "tipselection",
+			Name:      "candidates",
+			Help:      "The amount of tips that were available for the latest tip selection.",
+		},
+	)
+
+	tipSelectionDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "tipselection",
+			Name:      "duration",
+			Help:      "Durations of tip selections. [s]",
+			Buckets:   prometheus.DefBuckets,
+		},
+	)
+
+	tipSelectionUnreferencedBlocks = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "tipselection",
+			Name:      "unreferenced_blocks",
+			Help:      "The amount of unreferenced blocks in the past cone of selected tips.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+		},
+	)
+
+	tipSelectionConeScore = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "tipselection",
+			Name:      "cone_score",
+			Help:      "The cone scores of selected tips.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+		},
+	)
+
+	tipSelectionConeAge = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "iota",
+			Subsystem: "tipselection",
+			Name:      "cone_age_seconds",
+			Help:      "The age of the oldest tracked block in the past cone of selected tips. [s]",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+		},
+	)
+
+	tipSelectionLazyTipsCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "iota",
+			Subsystem: "tipselection",
+			Name:      "lazy_tips_count",
+			Help:      "The count of selected tips that the node marked to be promoted or reattached.",
+		},
+	)
+
+	registry.MustRegister(tipSelectionCandidates)
+	registry.MustRegister(tipSelectionDuration)
+	registry.MustRegister(tipSelectionUnreferencedBlocks)
+	registry.MustRegister(tipSelectionConeScore)
+	registry.MustRegister(tipSelectionConeAge)
+	registry.MustRegister(tipSelectionLazyTipsCount)
+
+	deps.Selector.Events.TipsSelected.Hook(events.NewClosure(func(result *mselection.TipSelectionResult) {
+		tipSelectionCandidates.Set(float64(result.Candidates))
+		tipSelectionDuration.Observe(result.Duration.Seconds())
+
+		for _, coneScore := range result.Scores {
+			tipSelectionUnreferencedBlocks.Observe(float64(coneScore.UnreferencedBlocks))
+			tipSelectionConeScore.Observe(coneScore.Score)
+			tipSelectionConeAge.Observe(coneScore.ConeAge.Seconds())
+			if coneScore.Lazy {
+				tipSelectionLazyTipsCount.Inc()
+			}
+		}
+	}))
+}
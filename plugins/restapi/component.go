@@ -0,0 +1,276 @@
+package restapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/dig"
+
+	"github.com/iotaledger/hive.go/core/app"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/apiauth"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/dashboard"
+	"github.com/iotaledger/inx-coordinator/pkg/envreport"
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+	"github.com/iotaledger/inx-coordinator/pkg/fileperm"
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
+	"github.com/iotaledger/inx-coordinator/pkg/journal"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/nodecaps"
+	"github.com/iotaledger/inx-coordinator/pkg/retention"
+	"github.com/iotaledger/inx-coordinator/pkg/storage"
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
+)
+
+func init() {
+	Plugin = &app.Plugin{
+		Component: &app.Component{
+			Name:           "RestAPI",
+			DepsFunc:       func(cDeps dependencies) { deps = cDeps },
+			Params:         params,
+			InitConfigPars: initConfigPars,
+			Provide:        provide,
+			Configure:      configure,
+			Run:            run,
+		},
+		IsEnabled: func() bool {
+			return ParamsRestAPI.Enabled
+		},
+	}
+}
+
+// apiTokenEnvVar is the environment variable the token of the routes that change the state of the coordinator is loaded from, if no file is configured.
+const apiTokenEnvVar = "COO_API_TOKEN"
+
+var (
+	Plugin *app.Plugin
+	deps   dependencies
+
+	tokenAuth         *apiauth.TokenAuth
+	eventStreamer     *dashboard.EventStream
+	eventJournal      *journal.Journal
+	eventJournalQueue *eventqueue.Queue[*api.Event]
+)
+
+type dependencies struct {
+	dig.In
+	Echo              *echo.Echo
+	NodeBridge        *nodebridge.NodeBridge
+	NodeCapabilities  *nodecaps.Capabilities
+	Coordinator       *coordinator.Coordinator
+	SoftErrorHistory  *coordinator.SoftErrorHistory
+	PinnedParents     *coordinator.PinnedParents
+	MigratorService   *migrator.Service                    `optional:"true"`
+	SignerCommittee   *coordinator.SignerCommittee         `optional:"true"`
+	RemoteSigners     *coordinator.RemoteSignerConnections `optional:"true"`
+	ReceiptProofStore *coordinator.ReceiptProofStore       `optional:"true"`
+	CircuitBreaker    *migrator.CircuitBreakerQueryer      `optional:"true"`
+	Faucet            *migrator.FaucetQueryer              `optional:"true"`
+	EnvironmentReport *envreport.Report
+	Identity          *identity.Identity
+	EventQueueMetrics *eventqueue.Metrics
+	FilePermissions   *fileperm.Enforcer
+	Storage           storage.Store `optional:"true"`
+}
+
+func initConfigPars(_ *dig.Container) error {
+	if !ParamsRestAPI.Enabled {
+		return nil
+	}
+
+	return validation.Validate("restAPI", ParamsRestAPI)
+}
+
+func provide(c *dig.Container) error {
+
+	if err := c.Provide(func() *echo.Echo {
+		return httpserver.NewEcho(
+			Plugin.Logger(),
+			nil,
+			ParamsRestAPI.DebugRequestLoggerEnabled,
+		)
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func configure() error {
+	if ParamsRestAPI.Auth.Enabled {
+		if ParamsRestAPI.Auth.FilePath != "" {
+			// the token grants access to the state of the coordinator, so it is protected like a key
+			if err := deps.FilePermissions.Enforce(fileperm.KindKey, ParamsRestAPI.Auth.FilePath); err != nil {
+				return err
+			}
+		}
+
+		token, err := apiauth.LoadToken(ParamsRestAPI.Auth.FilePath, apiTokenEnvVar)
+		if err != nil {
+			return err
+		}
+
+		if tokenAuth, err = apiauth.NewTokenAuth(token, ParamsRestAPI.Auth.Header); err != nil {
+			return err
+		}
+		Plugin.LogInfof("the routes that change the state of the coordinator require a token in the '%s' header", tokenAuth.Header())
+	} else {
+		Plugin.LogWarn("the routes that change the state of the coordinator are not authenticated")
+	}
+
+	if ParamsRestAPI.DashboardEnabled {
+		eventStreamer = dashboard.NewEventStream(
+			ParamsRestAPI.EventStream.QueueSize,
+			eventqueue.Policy(ParamsRestAPI.EventStream.OverflowPolicy),
+			deps.EventQueueMetrics,
+		)
+	}
+
+	if ParamsRestAPI.Journal.Enabled {
+		var err error
+		if deps.Storage != nil {
+			// the shared store is never rotated, the archive parameters only apply to the journal file
+			eventJournal, err = journal.OpenStore(deps.Storage)
+		} else {
+			eventJournal, err = journal.Open(ParamsRestAPI.Journal.FilePath,
+				journal.WithRotation(int64(ParamsRestAPI.Journal.MaxSizeMB)*1024*1024, retention.Policy{
+					MaxFiles: ParamsRestAPI.Journal.MaxArchives,
+					MaxAge:   ParamsRestAPI.Journal.MaxArchiveAge,
+					Compress: ParamsRestAPI.Journal.Compress,
+				}),
+				journal.WithIdentity(deps.Identity),
+			)
+		}
+		if err != nil {
+			return err
+		}
+		Plugin.LogInfof("Event journal contains %d events", eventJournal.LatestSequence())
+
+		// the events are written by a separate worker, so that a slow disk never delays the coordinator
+		eventJournalQueue = eventqueue.New[*api.Event](
+			ParamsRestAPI.Journal.QueueSize,
+			eventqueue.Policy(ParamsRestAPI.Journal.OverflowPolicy),
+			deps.EventQueueMetrics,
+			eventqueue.ListenerJournal,
+		)
+	}
+
+	configureEvents()
+
+	setupRoutes(deps.Echo)
+
+	return nil
+}
+
+func run() error {
+
+	if err := Plugin.App().Daemon().BackgroundWorker("API", func(ctx context.Context) {
+		Plugin.LogInfo("Starting API server ...")
+
+		go func() {
+			Plugin.LogInfof("You can now access the API using: http://%s", ParamsRestAPI.BindAddress)
+			if err := deps.Echo.Start(ParamsRestAPI.BindAddress); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				Plugin.LogErrorfAndExit("Stopped REST-API server due to an error (%s)", err)
+			}
+		}()
+
+		// the API stays reachable at its own address if the node can't route the requests to it
+		registerRoute, err := deps.NodeCapabilities.SupportsAPI(ctx, nodecaps.APIRegisterAPIRoute)
+		if err != nil {
+			Plugin.LogWarnf("Probing INX api route registration failed: %s", err)
+		}
+		if !registerRoute {
+			Plugin.LogWarnf("The node does not support INX api routes, the API is only reachable at http://%s", ParamsRestAPI.BindAddress)
+		}
+
+		advertisedAddress := ParamsRestAPI.BindAddress
+		if ParamsRestAPI.AdvertiseAddress != "" {
+			advertisedAddress = ParamsRestAPI.AdvertiseAddress
+		}
+
+		if registerRoute {
+			ctxRegister, cancelRegister := context.WithTimeout(ctx, 5*time.Second)
+			if err := deps.NodeBridge.RegisterAPIRoute(ctxRegister, api.APIRoute, advertisedAddress); err != nil {
+				Plugin.LogErrorfAndExit("Registering INX api route failed: %s", err)
+			}
+			cancelRegister()
+		}
+
+		Plugin.LogInfo("Starting API server ... done")
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping API ...")
+
+		if registerRoute {
+			ctxUnregister, cancelUnregister := context.WithTimeout(context.Background(), 5*time.Second)
+			//nolint:contextcheck // false positive
+			if err := deps.NodeBridge.UnregisterAPIRoute(ctxUnregister, api.APIRoute); err != nil {
+				Plugin.LogWarnf("Unregistering INX api route failed: %s", err)
+			}
+			cancelUnregister()
+		}
+
+		shutdownCtx, shutdownCtxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCtxCancel()
+
+		//nolint:contextcheck // false positive
+		if err := deps.Echo.Shutdown(shutdownCtx); err != nil {
+			Plugin.LogWarn(err)
+		}
+
+		Plugin.LogInfo("Stopping API ... done")
+	}, daemon.PriorityStopRestAPI); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	if !ParamsRestAPI.DashboardEnabled && !ParamsRestAPI.Journal.Enabled {
+		return nil
+	}
+
+	if err := Plugin.App().Daemon().BackgroundWorker("API[Events]", func(ctx context.Context) {
+		journalWriterDone := make(chan struct{})
+		if eventJournalQueue != nil {
+			go func() {
+				defer close(journalWriterDone)
+				writeEventJournal(ctx)
+			}()
+		} else {
+			close(journalWriterDone)
+		}
+
+		attachEvents()
+		<-ctx.Done()
+		detachEvents()
+
+		if eventJournalQueue != nil {
+			<-journalWriterDone
+
+			// the events that are still queued are recorded before the journal is closed
+			for _, event := range eventJournalQueue.Flush() {
+				recordEvent(event)
+			}
+			eventJournalQueue.Close()
+		}
+
+		if eventStreamer != nil {
+			// disconnect all subscribers of the event stream
+			eventStreamer.Close()
+		}
+
+		if eventJournal != nil {
+			if err := eventJournal.Close(); err != nil {
+				Plugin.LogWarnf("failed to close event journal: %s", err)
+			}
+		}
+	}, daemon.PriorityStopRestAPI); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,396 @@
+package restapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/journal"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/receiptjson"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// the maximum amount of events that are returned by a single event journal request.
+	maxEventJournalPageSize = 1000
+)
+
+var (
+	// closures.
+	onIssuedMilestone             *events.Closure
+	onIssuedCheckpoint            *events.Closure
+	onMilestoneSkipped            *events.Closure
+	onSoftError                   *events.Closure
+	onQuorumFinished              *events.Closure
+	onMilestoneTimeout            *events.Closure
+	onReceiptSigned               *events.Closure
+	onReceiptIssued               *events.Closure
+	onMilestoneConfirmationFailed *events.Closure
+	onMilestoneGapDetected        *events.Closure
+	onClockDriftDetected          *events.Closure
+	onReceiptsPaused              *events.Closure
+	onReceiptsResumed             *events.Closure
+	onSigningSelfTestCompleted    *events.Closure
+	onQuorumNodeDemoted           *events.Closure
+	onMilestoneTimings            *events.Closure
+	onMigratorSoftError           *events.Closure
+	onMigratedFundsFetched        *events.Closure
+	onMigratorErrorAlert          *events.Closure
+	onMigratorIndexRangeSkipped   *events.Closure
+	onMigratorIndexJumpDetected   *events.Closure
+	onMigratorMigrationHeld       *events.Closure
+	onMigratorHalted              *events.Closure
+)
+
+// publishEvent queues the event for the event journal, if enabled, or sends it to the subscribers of the event stream right away.
+// It never blocks, so that slow listeners never delay the coordinator.
+func publishEvent(eventType string, data any) {
+	event := &api.Event{
+		Type:      eventType,
+		Timestamp: time.Now().Unix(),
+		Data:      data,
+	}
+
+	if eventJournalQueue != nil && eventJournalQueue.Push(event) {
+		// the event is sent to the subscribers after it got its sequence number in the journal
+		return
+	}
+
+	if eventStreamer != nil {
+		if err := eventStreamer.Broadcast(event); err != nil {
+			Plugin.LogWarn(err)
+		}
+	}
+}
+
+// writeEventJournal records the queued events in the event journal and sends them to the subscribers of the event stream,
+// until the context is canceled.
+func writeEventJournal(ctx context.Context) {
+	for {
+		event, err := eventJournalQueue.Pop(ctx)
+		if err != nil {
+			return
+		}
+		recordEvent(event)
+	}
+}
+
+// recordEvent records the event in the event journal and sends it to the subscribers of the event stream.
+func recordEvent(event *api.Event) {
+	if err := eventJournal.Append(event); err != nil {
+		if errors.Is(err, journal.ErrRotationFailed) {
+			Plugin.LogWarnf("recorded event in the journal, but %s", err)
+		} else {
+			Plugin.LogWarnf("failed to record event in the journal: %s", err)
+		}
+	}
+
+	if eventStreamer != nil {
+		if err := eventStreamer.Broadcast(event); err != nil {
+			Plugin.LogWarn(err)
+		}
+	}
+}
+
+func configureEvents() {
+	onIssuedMilestone = events.NewClosure(func(index iotago.MilestoneIndex, milestoneID iotago.MilestoneID, blockID iotago.BlockID) {
+		publishEvent(api.EventTypeMilestoneIssued, &api.MilestoneIssuedEvent{
+			Index:       index,
+			MilestoneID: milestoneID.ToHex(),
+			BlockID:     blockID.ToHex(),
+		})
+	})
+
+	onIssuedCheckpoint = events.NewClosure(func(checkpointIndex int, _ int, _ int, blockID iotago.BlockID) {
+		publishEvent(api.EventTypeCheckpointIssued, &api.CheckpointIssuedEvent{
+			Index:   checkpointIndex,
+			BlockID: blockID.ToHex(),
+		})
+	})
+
+	onMilestoneSkipped = events.NewClosure(func(index iotago.MilestoneIndex, err error) {
+		publishEvent(api.EventTypeMilestoneSkipped, &api.MilestoneSkippedEvent{
+			Index:  index,
+			Reason: err.Error(),
+		})
+	})
+
+	onSoftError = events.NewClosure(func(err error) {
+		publishEvent(api.EventTypeSoftError, &api.SoftErrorStatus{
+			Timestamp: time.Now().Unix(),
+			Class:     coordinator.SoftErrorClass(err),
+			Message:   err.Error(),
+		})
+	})
+
+	onQuorumFinished = events.NewClosure(func(result *coordinator.QuorumFinishedResult) {
+		event := &api.QuorumFinishedEvent{
+			DurationMilliseconds: result.Duration.Milliseconds(),
+		}
+		if result.Err != nil {
+			event.Error = result.Err.Error()
+		}
+		publishEvent(api.EventTypeQuorumFinished, event)
+	})
+
+	onMilestoneTimeout = events.NewClosure(func() {
+		publishEvent(api.EventTypeMilestoneTimeout, nil)
+	})
+
+	onReceiptSigned = events.NewClosure(func(index iotago.MilestoneIndex, signature *iotago.Ed25519Signature) {
+		publishEvent(api.EventTypeReceiptSigned, &api.ReceiptSignedEvent{
+			Index:     index,
+			PublicKey: iotago.EncodeHex(signature.PublicKey[:]),
+		})
+	})
+
+	onReceiptIssued = events.NewClosure(func(index iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt, size int) {
+		publishEvent(api.EventTypeReceiptIssued, &api.ReceiptIssuedEvent{
+			Index:        index,
+			MigratedAt:   receipt.MigratedAt,
+			Final:        receipt.Final,
+			EntriesCount: len(receipt.Funds),
+			Size:         size,
+		})
+
+		if ParamsRestAPI.EventStream.CanonicalReceipts {
+			publishEvent(api.EventTypeCanonicalReceipt, receiptjson.New(index, receipt, deps.NodeBridge.ProtocolParameters().Bech32HRP))
+		}
+	})
+
+	onMilestoneConfirmationFailed = events.NewClosure(func(failure *coordinator.MilestoneConfirmationFailure) {
+		event := &api.MilestoneConfirmationFailedEvent{
+			Index:       failure.Index,
+			MilestoneID: failure.MilestoneID.ToHex(),
+			HasReceipt:  failure.HasReceipt,
+		}
+		if failure.ConfirmedMilestoneID != nil {
+			event.ConfirmedMilestoneID = failure.ConfirmedMilestoneID.ToHex()
+		}
+		publishEvent(api.EventTypeMilestoneConfirmationFailed, event)
+	})
+
+	onMilestoneGapDetected = events.NewClosure(func(plan *coordinator.RecoveryPlan) {
+		publishEvent(api.EventTypeMilestoneGapDetected, &api.MilestoneGapDetectedEvent{
+			GapMilliseconds:   plan.Gap.Milliseconds(),
+			MissedIntervals:   plan.MissedIntervals,
+			Policy:            plan.Policy,
+			CatchUpMilestones: plan.CatchUpMilestones,
+		})
+	})
+
+	onClockDriftDetected = events.NewClosure(func(drift *coordinator.ClockDrift) {
+		publishEvent(api.EventTypeClockDriftDetected, &api.ClockDriftDetectedEvent{
+			Index:             drift.Index,
+			DriftMilliseconds: drift.Drift.Milliseconds(),
+			DelayMilliseconds: drift.Delay.Milliseconds(),
+		})
+	})
+
+	onReceiptsPaused = events.NewClosure(func(pause *coordinator.ReceiptPause) {
+		publishEvent(api.EventTypeReceiptsPaused, receiptPauseEvent(pause))
+	})
+
+	onReceiptsResumed = events.NewClosure(func(pause *coordinator.ReceiptPause) {
+		publishEvent(api.EventTypeReceiptsResumed, receiptPauseEvent(pause))
+	})
+
+	onSigningSelfTestCompleted = events.NewClosure(func(result *coordinator.SigningSelfTestResult) {
+		if result.Err == nil {
+			return
+		}
+		publishEvent(api.EventTypeSigningSelfTestFailed, &api.SigningSelfTestFailedEvent{
+			Index:   result.Index,
+			Message: result.Err.Error(),
+		})
+	})
+
+	onQuorumNodeDemoted = events.NewClosure(func(demotion *coordinator.QuorumNodeDemotion) {
+		publishEvent(api.EventTypeQuorumNodeDemoted, &api.QuorumNodeDemotedEvent{
+			Group:   demotion.Group,
+			Alias:   demotion.Alias,
+			BaseURL: demotion.BaseURL,
+			Reason:  demotion.Score.DemotionReason,
+		})
+	})
+
+	onMilestoneTimings = events.NewClosure(func(timings *coordinator.MilestoneTimings) {
+		stages := make([]*api.MilestoneStageTiming, 0, len(timings.Stages))
+		for _, stage := range timings.Stages {
+			stages = append(stages, &api.MilestoneStageTiming{
+				Stage:                stage.Stage,
+				DurationMilliseconds: stage.Duration.Milliseconds(),
+				BudgetMilliseconds:   stage.Budget.Milliseconds(),
+				Exceeded:             stage.Exceeded,
+			})
+		}
+
+		publishEvent(api.EventTypeMilestoneTimings, &api.MilestoneTimingsEvent{
+			Index:              timings.Index,
+			Issued:             timings.Issued,
+			TotalMilliseconds:  timings.Total.Milliseconds(),
+			BudgetMilliseconds: timings.Budget.Milliseconds(),
+			Stages:             stages,
+			Degradations:       timings.Degradations,
+		})
+	})
+
+	onMigratorSoftError = events.NewClosure(func(err error) {
+		publishEvent(api.EventTypeMigratorSoftError, &api.SoftErrorStatus{
+			Timestamp: time.Now().Unix(),
+			Class:     migrator.ErrorClass(err),
+			Message:   err.Error(),
+		})
+	})
+
+	onMigratedFundsFetched = events.NewClosure(func(migratedFunds []*iotago.MigratedFundsEntry) {
+		var value uint64
+		for _, entry := range migratedFunds {
+			value += entry.Deposit
+		}
+		publishEvent(api.EventTypeMigratedFundsFetched, &api.MigratedFundsFetchedEvent{
+			EntriesCount: len(migratedFunds),
+			Value:        value,
+		})
+	})
+
+	onMigratorErrorAlert = events.NewClosure(func(class string, err error) {
+		publishEvent(api.EventTypeMigratorErrorAlert, &api.MigratorErrorAlertEvent{
+			Class:   class,
+			Message: err.Error(),
+		})
+	})
+
+	onMigratorIndexRangeSkipped = events.NewClosure(func(skipped *migrator.IndexRange) {
+		publishEvent(api.EventTypeMigratorIndexRangeSkipped, &api.MigratorIndexRangeSkippedEvent{
+			StartIndex: skipped.StartIndex,
+			EndIndex:   skipped.EndIndex,
+		})
+	})
+
+	onMigratorIndexJumpDetected = events.NewClosure(func(jump *migrator.IndexJump) {
+		publishEvent(api.EventTypeMigratorIndexJumpDetected, migratorIndexJump(jump))
+	})
+
+	onMigratorMigrationHeld = events.NewClosure(func(held *migrator.HeldMigration) {
+		publishEvent(api.EventTypeMigratorMigrationHeld, migratorHeldMigration(held))
+	})
+
+	onMigratorHalted = events.NewClosure(func(halt *migrator.Halt) {
+		publishEvent(api.EventTypeMigratorHalted, migratorHalt(halt))
+	})
+}
+
+// receiptPauseEvent converts a pause of the receipts to its API representation.
+func receiptPauseEvent(pause *coordinator.ReceiptPause) *api.ReceiptPauseEvent {
+	return &api.ReceiptPauseEvent{
+		Index:                pause.Index,
+		Reason:               pause.Reason,
+		SinceTimestamp:       pause.Since.Unix(),
+		ResumedIndex:         pause.ResumedIndex,
+		DurationMilliseconds: pause.Duration.Milliseconds(),
+	}
+}
+
+func attachEvents() {
+	deps.Coordinator.Events.IssuedMilestone.Hook(onIssuedMilestone)
+	deps.Coordinator.Events.IssuedCheckpointBlock.Hook(onIssuedCheckpoint)
+	deps.Coordinator.Events.MilestoneSkipped.Hook(onMilestoneSkipped)
+	deps.Coordinator.Events.SoftError.Hook(onSoftError)
+	deps.Coordinator.Events.QuorumFinished.Hook(onQuorumFinished)
+	deps.Coordinator.Events.MilestoneTimeout.Hook(onMilestoneTimeout)
+	deps.Coordinator.Events.ReceiptSigned.Hook(onReceiptSigned)
+	deps.Coordinator.Events.ReceiptIssued.Hook(onReceiptIssued)
+	deps.Coordinator.Events.MilestoneConfirmationFailed.Hook(onMilestoneConfirmationFailed)
+	deps.Coordinator.Events.MilestoneGapDetected.Hook(onMilestoneGapDetected)
+	deps.Coordinator.Events.ClockDriftDetected.Hook(onClockDriftDetected)
+	deps.Coordinator.Events.ReceiptsPaused.Hook(onReceiptsPaused)
+	deps.Coordinator.Events.ReceiptsResumed.Hook(onReceiptsResumed)
+	deps.Coordinator.Events.SigningSelfTestCompleted.Hook(onSigningSelfTestCompleted)
+	deps.Coordinator.Events.QuorumNodeDemoted.Hook(onQuorumNodeDemoted)
+	deps.Coordinator.Events.MilestoneTimings.Hook(onMilestoneTimings)
+
+	if deps.MigratorService != nil {
+		deps.MigratorService.Events.SoftError.Hook(onMigratorSoftError)
+		deps.MigratorService.Events.MigratedFundsFetched.Hook(onMigratedFundsFetched)
+		deps.MigratorService.Events.ErrorAlert.Hook(onMigratorErrorAlert)
+		deps.MigratorService.Events.IndexRangeSkipped.Hook(onMigratorIndexRangeSkipped)
+		deps.MigratorService.Events.IndexJumpDetected.Hook(onMigratorIndexJumpDetected)
+		deps.MigratorService.Events.MigrationHeld.Hook(onMigratorMigrationHeld)
+		deps.MigratorService.Events.Halted.Hook(onMigratorHalted)
+	}
+}
+
+func detachEvents() {
+	deps.Coordinator.Events.IssuedMilestone.Detach(onIssuedMilestone)
+	deps.Coordinator.Events.IssuedCheckpointBlock.Detach(onIssuedCheckpoint)
+	deps.Coordinator.Events.MilestoneSkipped.Detach(onMilestoneSkipped)
+	deps.Coordinator.Events.SoftError.Detach(onSoftError)
+	deps.Coordinator.Events.QuorumFinished.Detach(onQuorumFinished)
+	deps.Coordinator.Events.MilestoneTimeout.Detach(onMilestoneTimeout)
+	deps.Coordinator.Events.ReceiptSigned.Detach(onReceiptSigned)
+	deps.Coordinator.Events.ReceiptIssued.Detach(onReceiptIssued)
+	deps.Coordinator.Events.MilestoneConfirmationFailed.Detach(onMilestoneConfirmationFailed)
+	deps.Coordinator.Events.MilestoneGapDetected.Detach(onMilestoneGapDetected)
+	deps.Coordinator.Events.ClockDriftDetected.Detach(onClockDriftDetected)
+	deps.Coordinator.Events.ReceiptsPaused.Detach(onReceiptsPaused)
+	deps.Coordinator.Events.ReceiptsResumed.Detach(onReceiptsResumed)
+	deps.Coordinator.Events.SigningSelfTestCompleted.Detach(onSigningSelfTestCompleted)
+	deps.Coordinator.Events.QuorumNodeDemoted.Detach(onQuorumNodeDemoted)
+	deps.Coordinator.Events.MilestoneTimings.Detach(onMilestoneTimings)
+
+	if deps.MigratorService != nil {
+		deps.MigratorService.Events.SoftError.Detach(onMigratorSoftError)
+		deps.MigratorService.Events.MigratedFundsFetched.Detach(onMigratedFundsFetched)
+		deps.MigratorService.Events.ErrorAlert.Detach(onMigratorErrorAlert)
+		deps.MigratorService.Events.IndexRangeSkipped.Detach(onMigratorIndexRangeSkipped)
+		deps.MigratorService.Events.IndexJumpDetected.Detach(onMigratorIndexJumpDetected)
+		deps.MigratorService.Events.MigrationHeld.Detach(onMigratorMigrationHeld)
+		deps.MigratorService.Events.Halted.Detach(onMigratorHalted)
+	}
+}
+
+// eventJournalEvents returns the recorded events starting at the sequence number given by the query parameter.
+func eventJournalEvents(c echo.Context) (*api.EventJournalResponse, error) {
+
+	fromSequence := uint64(1)
+	if c.QueryParam(api.QueryParameterFrom) != "" {
+		from, err := httpserver.ParseUint32QueryParam(c, api.QueryParameterFrom)
+		if err != nil {
+			return nil, err
+		}
+		fromSequence = uint64(from)
+	}
+
+	limit := uint32(maxEventJournalPageSize)
+	if c.QueryParam(api.QueryParameterLimit) != "" {
+		var err error
+		if limit, err = httpserver.ParseUint32QueryParam(c, api.QueryParameterLimit, maxEventJournalPageSize); err != nil {
+			return nil, err
+		}
+	}
+
+	// the latest sequence is read first, so that it is never lower than the sequence of the returned events
+	latestSequence := eventJournal.LatestSequence()
+
+	events, err := eventJournal.Events(fromSequence, int(limit))
+	if err != nil {
+		if errors.Is(err, journal.ErrEventsArchived) {
+			return nil, errors.WithMessagef(echo.ErrNotFound, "%s", err)
+		}
+
+		return nil, err
+	}
+
+	return &api.EventJournalResponse{
+		Events:         events,
+		LatestSequence: latestSequence,
+	}, nil
+}
@@ -0,0 +1,238 @@
+package restapi
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	migratorplugin "github.com/iotaledger/inx-coordinator/plugins/migrator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+var (
+	// errConflict is returned when the migrator state can't be exported or imported in the current state of the coordinator.
+	errConflict = echo.NewHTTPError(http.StatusConflict, "conflict")
+)
+
+func exportMigratorState() (*migrator.StateArchive, error) {
+
+	archive, err := deps.MigratorService.ExportState()
+	if err != nil {
+		if errors.Is(err, migrator.ErrStateNotExportable) {
+			return nil, errors.WithMessagef(errConflict, "%s", err)
+		}
+
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func importMigratorState(c echo.Context) (*api.MigratorStateImportResponse, error) {
+
+	// the running migrator would overwrite the imported state with its own
+	if deps.MigratorService != nil {
+		return nil, errors.WithMessage(errConflict, "the migrator state can only be imported while the migrator plugin is disabled")
+	}
+
+	// the archive is imported into the state files, the shared storage would ignore the imported tail transaction hashes
+	if deps.Storage != nil {
+		return nil, errors.WithMessage(errConflict, "the migrator state can only be imported if the included tail transaction hashes are kept in their own file")
+	}
+
+	archive := &migrator.StateArchive{}
+	if err := c.Bind(archive); err != nil {
+		return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	if err := migrator.ImportStateArchive(archive, migrator.StateFilePaths{
+		State:           migratorplugin.ParamsMigrator.StateFilePath,
+		IncludedHashes:  migratorplugin.ParamsMigrator.IncludedHashesFilePath,
+		FetchCheckpoint: migratorplugin.ParamsMigrator.FetchCheckpoint.FilePath,
+	}); err != nil {
+		switch {
+		case errors.Is(err, migrator.ErrInvalidStateArchive):
+			return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "%s", err)
+		case errors.Is(err, migrator.ErrStateFileAlreadyExists):
+			return nil, errors.WithMessagef(errConflict, "%s", err)
+		default:
+			return nil, err
+		}
+	}
+
+	resp := &api.MigratorStateImportResponse{
+		LatestMigratedAtIndex: archive.State.LatestMigratedAtIndex,
+		IncludedHashesCount:   len(archive.IncludedHashes),
+	}
+	if archive.FetchCheckpoint != nil {
+		resp.FetchCheckpointVerifiedIndex = archive.FetchCheckpoint.VerifiedIndex
+	}
+
+	Plugin.LogInfof("Imported migrator state at legacy milestone %d with %d included tail transaction hashes, enable the migrator plugin to continue the migration", resp.LatestMigratedAtIndex, resp.IncludedHashesCount)
+
+	return resp, nil
+}
+
+// migratorIndexJump converts the pending index jump of the migrator, it returns nil if there is none.
+func migratorHalt(halt *migrator.Halt) *api.MigratorHalt {
+	if halt == nil {
+		return nil
+	}
+
+	resp := &api.MigratorHalt{
+		Timestamp:    halt.Time.Unix(),
+		Message:      halt.Err.Error(),
+		DumpFilePath: halt.DumpFilePath,
+	}
+	if halt.DumpErr != nil {
+		resp.DumpError = halt.DumpErr.Error()
+	}
+
+	return resp
+}
+
+func migratorIndexJump(jump *migrator.IndexJump) *api.MigratorIndexJump {
+	if jump == nil {
+		return nil
+	}
+
+	return &api.MigratorIndexJump{
+		FromIndex:    jump.FromIndex,
+		ToIndex:      jump.ToIndex,
+		EntriesCount: jump.EntriesCount,
+	}
+}
+
+func pendingMigratorIndexJump() (*api.MigratorIndexJump, error) {
+
+	jump := migratorIndexJump(deps.MigratorService.PendingIndexJump())
+	if jump == nil {
+		return nil, errors.WithMessage(echo.ErrNotFound, "no index jump awaits confirmation")
+	}
+
+	return jump, nil
+}
+
+func confirmMigratorIndexJump(c echo.Context) error {
+
+	request := &api.MigratorIndexJumpConfirmRequest{}
+	if err := c.Bind(request); err != nil {
+		return errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	if err := deps.MigratorService.ConfirmIndexJump(request.ToIndex); err != nil {
+		if errors.Is(err, migrator.ErrIndexJumpNotPending) {
+			return errors.WithMessagef(errConflict, "%s", err)
+		}
+
+		return err
+	}
+
+	Plugin.LogInfof("confirmed jump of the migrated at index to legacy milestone %d", request.ToIndex)
+
+	return nil
+}
+
+// migratorHeldMigration converts a migration that is held for review.
+func migratorHeldMigration(held *migrator.HeldMigration) *api.MigratorHeldMigration {
+	return &api.MigratorHeldMigration{
+		MigratedAt:          held.MigratedAt,
+		TailTransactionHash: iotago.EncodeHex(held.Entry.TailTransactionHash[:]),
+		Address:             held.Entry.Address.Bech32(deps.NodeBridge.ProtocolParameters().Bech32HRP),
+		Deposit:             held.Entry.Deposit,
+		Reason:              held.Reason,
+	}
+}
+
+func heldMigrations() *api.MigratorHeldMigrationsResponse {
+
+	held := deps.MigratorService.HeldMigrations()
+	sort.Slice(held, func(i, j int) bool {
+		if held[i].MigratedAt != held[j].MigratedAt {
+			return held[i].MigratedAt < held[j].MigratedAt
+		}
+
+		return bytes.Compare(held[i].Entry.TailTransactionHash[:], held[j].Entry.TailTransactionHash[:]) < 0
+	})
+
+	migrations := make([]*api.MigratorHeldMigration, len(held))
+	for i, migration := range held {
+		migrations[i] = migratorHeldMigration(migration)
+	}
+
+	return &api.MigratorHeldMigrationsResponse{Migrations: migrations}
+}
+
+func releaseHeldMigration(c echo.Context) error {
+
+	hashParam := c.Param(api.ParameterTailTransactionHash)
+	hashBytes, err := iotago.DecodeHex(hashParam)
+	if err != nil || len(hashBytes) != iotago.LegacyTailTransactionHashLength {
+		return errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid tail transaction hash: %s", hashParam)
+	}
+
+	var hash iotago.LegacyTailTransactionHash
+	copy(hash[:], hashBytes)
+
+	if err := deps.MigratorService.ReleaseHeldMigration(hash); err != nil {
+		if errors.Is(err, migrator.ErrMigrationNotHeld) {
+			return errors.WithMessagef(echo.ErrNotFound, "%s", err)
+		}
+
+		return err
+	}
+
+	Plugin.LogInfof("released held migration with tail transaction hash %s", hashParam)
+
+	return nil
+}
+
+func requestFaucetMigration(c echo.Context) (*api.MigratorFaucetResponse, error) {
+
+	request := &api.MigratorFaucetRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	bech32HRP := deps.NodeBridge.ProtocolParameters().Bech32HRP
+	hrp, address, err := iotago.ParseBech32(request.Address)
+	if err != nil {
+		return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid address: %s, error: %s", request.Address, err)
+	}
+	if hrp != bech32HRP {
+		return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid address: %s, expected prefix %s", request.Address, bech32HRP)
+	}
+
+	ed25519Address, ok := address.(*iotago.Ed25519Address)
+	if !ok {
+		return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid address: %s, migrations can only target Ed25519 addresses", request.Address)
+	}
+
+	entry, err := deps.Faucet.Request(ed25519Address, request.Deposit)
+	if err != nil {
+		switch {
+		case errors.Is(err, migrator.ErrInvalidMigrations):
+			return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "%s", err)
+		case errors.Is(err, migrator.ErrFaucetQueueFull):
+			return nil, errors.WithMessagef(echo.ErrServiceUnavailable, "%s", err)
+		default:
+			return nil, err
+		}
+	}
+
+	tailTransactionHash := iotago.EncodeHex(entry.TailTransactionHash[:])
+	Plugin.LogInfof("requested faucet migration of %d to %s with tail transaction hash %s", entry.Deposit, request.Address, tailTransactionHash)
+
+	return &api.MigratorFaucetResponse{
+		TailTransactionHash: tailTransactionHash,
+		Address:             entry.Address.Bech32(bech32HRP),
+		Deposit:             entry.Deposit,
+		PendingCount:        deps.Faucet.Pending(),
+	}, nil
+}
@@ -0,0 +1,74 @@
+package restapi
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/core/app"
+)
+
+// ParametersRestAPI contains the definition of the parameters used by the coordinator REST API.
+type ParametersRestAPI struct {
+	// Enabled defines whether the REST API plugin is enabled.
+	Enabled bool `default:"false" usage:"whether the REST API plugin is enabled"`
+	// BindAddress defines the bind address on which the coordinator REST API listens on.
+	BindAddress string `default:"localhost:9091" usage:"the bind address on which the coordinator REST API listens on"`
+	// AdvertiseAddress defines the address of the coordinator REST API to advertise to the INX Server (optional).
+	AdvertiseAddress string `default:"" usage:"the address of the coordinator REST API to advertise to the INX Server (optional)"`
+	// DashboardEnabled defines whether the embedded dashboard and the event stream are served.
+	DashboardEnabled bool `default:"true" usage:"whether the embedded dashboard and the event stream are served"`
+	// DebugRequestLoggerEnabled defines whether the debug logging for requests should be enabled.
+	DebugRequestLoggerEnabled bool `default:"false" usage:"whether the debug logging for requests should be enabled"`
+
+	Auth        ParametersAuth
+	EventStream ParametersEventStream
+	Journal     ParametersJournal
+}
+
+// ParametersAuth contains the parameters of the authentication of the routes that change the state of the coordinator.
+type ParametersAuth struct {
+	// Enabled defines whether the routes that change the state of the coordinator require a token.
+	Enabled bool `default:"true" usage:"whether the routes that change the state of the coordinator (pinned parents, signer committee changes, migrator state import and export, index jump confirmation, held migration release and faucet migration requests) require a token"`
+	// Header defines the header the token is expected in.
+	Header string `default:"" usage:"the header the token is expected in (empty = 'Authorization' with the 'Bearer' scheme)"`
+	// FilePath defines the path to the file that contains the token.
+	FilePath string `default:"" usage:"the path to the file that contains the token (empty = the COO_API_TOKEN environment variable is used)"`
+}
+
+// ParametersEventStream contains the parameters of the event stream that is sent to the WebSocket clients.
+type ParametersEventStream struct {
+	// QueueSize defines the amount of events that are queued per client.
+	QueueSize int `default:"100" usage:"the amount of events that are queued per WebSocket client of the event stream" validate:"min=1"`
+	// OverflowPolicy defines what happens if the queue of a client is full.
+	OverflowPolicy string `default:"disconnect" usage:"what happens if the queue of a WebSocket client is full (dropNewest = the new event is dropped, dropOldest = the oldest queued event is dropped, disconnect = the client is disconnected)" validate:"oneof=dropNewest dropOldest disconnect"`
+	// CanonicalReceipts defines whether the issued receipts are published in the canonical JSON schema for external systems.
+	CanonicalReceipts bool `default:"false" usage:"whether the issued receipts are additionally published as canonicalReceipt events with hex encoded hashes, bech32 addresses and decimal string values, so that external systems don't need to implement the protocol serialization"`
+}
+
+// ParametersJournal contains the parameters of the persistent event journal.
+type ParametersJournal struct {
+	// Enabled defines whether all events are recorded in a persistent journal that can be replayed via the API.
+	Enabled bool `default:"false" usage:"whether all events are recorded in a persistent journal that can be replayed via the API"`
+	// FilePath defines the path to the event journal file.
+	FilePath string `default:"events.journal" usage:"the path to the event journal file" validate:"required"`
+	// MaxSizeMB defines the size after which the event journal is archived and a new journal file is started.
+	MaxSizeMB int `default:"0" usage:"the size in megabytes after which the event journal is moved to an archive named after the sequence numbers it contains and a new journal file is started (0 = disabled)" validate:"min=0"`
+	// MaxArchives defines the maximum amount of archives of the event journal that are kept.
+	MaxArchives int `default:"0" usage:"the maximum amount of archives of the event journal that are kept, older ones are removed (0 = unlimited)" validate:"min=0"`
+	// MaxArchiveAge defines the maximum age of the archives of the event journal.
+	MaxArchiveAge time.Duration `default:"0s" usage:"the maximum age of the archives of the event journal, older ones are removed (0 = unlimited)"`
+	// Compress defines whether the archives of the event journal are compressed.
+	Compress bool `default:"true" usage:"whether the archives of the event journal are compressed with gzip"`
+	// QueueSize defines the amount of events that are queued until they are written to the event journal.
+	QueueSize int `default:"1000" usage:"the amount of events that are queued until they are written to the event journal, so that a slow disk never delays the coordinator" validate:"min=1"`
+	// OverflowPolicy defines what happens if the queue of the event journal is full.
+	OverflowPolicy string `default:"dropNewest" usage:"what happens if the queue of the event journal is full (dropNewest = the new event is dropped, dropOldest = the oldest queued event is dropped)" validate:"oneof=dropNewest dropOldest"`
+}
+
+var ParamsRestAPI = &ParametersRestAPI{}
+
+var params = &app.ComponentParams{
+	Params: map[string]any{
+		"restAPI": ParamsRestAPI,
+	},
+	Masked: nil,
+}
@@ -0,0 +1,73 @@
+package restapi
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+// pinnedParentsError maps the errors of the pinned parents to REST API errors.
+func pinnedParentsError(err error) error {
+	switch {
+	case errors.Is(err, coordinator.ErrPinnedParentNotFound):
+		return errors.WithMessagef(echo.ErrNotFound, "%s", err)
+	case errors.Is(err, coordinator.ErrPinnedParentInvalid), errors.Is(err, coordinator.ErrTooManyPinnedParents):
+		return errors.WithMessagef(httpserver.ErrInvalidParameter, "%s", err)
+	default:
+		return err
+	}
+}
+
+func pinnedParents() *api.PinnedParentsResponse {
+	return &api.PinnedParentsResponse{
+		BlockIDs: deps.PinnedParents.BlockIDs().ToHex(),
+	}
+}
+
+func pinParents(c echo.Context) (*api.PinnedParentsResponse, error) {
+
+	request := &api.PinnedParentsRequest{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	blockIDs := make(iotago.BlockIDs, 0, len(request.BlockIDs))
+	for _, blockIDHex := range request.BlockIDs {
+		blockID, err := iotago.BlockIDFromHexString(blockIDHex)
+		if err != nil {
+			return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid block ID: %s, error: %s", blockIDHex, err)
+		}
+		blockIDs = append(blockIDs, blockID)
+	}
+
+	pinned, err := deps.PinnedParents.Pin(c.Request().Context(), blockIDs)
+	if err != nil {
+		return nil, pinnedParentsError(err)
+	}
+
+	Plugin.LogInfof("pinned blocks %v as parents of the next milestone", blockIDs.ToHex())
+
+	return &api.PinnedParentsResponse{
+		BlockIDs: pinned.ToHex(),
+	}, nil
+}
+
+func unpinParent(c echo.Context) error {
+
+	blockID, err := httpserver.ParseBlockIDParam(c, api.ParameterBlockID)
+	if err != nil {
+		return err
+	}
+
+	if err := deps.PinnedParents.Unpin(blockID); err != nil {
+		return pinnedParentsError(err)
+	}
+
+	Plugin.LogInfof("unpinned block %s", blockID.ToHex())
+
+	return nil
+}
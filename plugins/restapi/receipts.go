@@ -0,0 +1,234 @@
+package restapi
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func receiptProof(c echo.Context) (*api.ReceiptProofResponse, error) {
+
+	msIndex, err := httpserver.ParseMilestoneIndexParam(c, api.ParameterMilestoneIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := deps.ReceiptProofStore.Proof(msIndex)
+	if err != nil {
+		if errors.Is(err, coordinator.ErrReceiptProofNotFound) {
+			return nil, errors.WithMessagef(echo.ErrNotFound, "%s", err)
+		}
+
+		return nil, err
+	}
+
+	var treasurySignature *api.TreasurySignature
+	if proof.TreasurySignature != nil {
+		treasurySignature = &api.TreasurySignature{
+			PublicKey: iotago.EncodeHex(proof.TreasurySignature.PublicKey[:]),
+			Signature: iotago.EncodeHex(proof.TreasurySignature.Signature[:]),
+		}
+	}
+
+	return &api.ReceiptProofResponse{
+		MilestoneIndex:     proof.MilestoneIndex,
+		MilestoneID:        proof.MilestoneID.ToHex(),
+		BlockID:            proof.BlockID.ToHex(),
+		ReceiptOptionIndex: proof.ReceiptOptionIndex,
+		Milestone:          proof.Milestone,
+		TreasurySignature:  treasurySignature,
+	}, nil
+}
+
+const (
+	// the maximum amount of entries that are returned by a single receipt entries request.
+	maxReceiptEntriesPageSize = 100
+)
+
+// receiptEntryFields parses the selected fields of the receipt entries, all fields are selected by default.
+func receiptEntryFields(c echo.Context) (map[string]bool, error) {
+	fields := map[string]bool{
+		api.ReceiptEntryFieldTailTransactionHash: true,
+		api.ReceiptEntryFieldAddress:             true,
+		api.ReceiptEntryFieldDeposit:             true,
+	}
+
+	query := c.QueryParam(api.QueryParameterFields)
+	if query == "" {
+		return fields, nil
+	}
+
+	selected := make(map[string]bool, len(fields))
+	for _, field := range strings.Split(query, ",") {
+		field = strings.TrimSpace(field)
+		if !fields[field] {
+			return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid field: %s", field)
+		}
+		selected[field] = true
+	}
+
+	return selected, nil
+}
+
+func receiptEntries(c echo.Context) (*api.ReceiptEntriesResponse, error) {
+
+	msIndex, err := httpserver.ParseMilestoneIndexParam(c, api.ParameterMilestoneIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if c.QueryParam(api.QueryParameterOffset) != "" {
+		value, err := httpserver.ParseUint32QueryParam(c, api.QueryParameterOffset)
+		if err != nil {
+			return nil, err
+		}
+		offset = int(value)
+	}
+
+	limit := uint32(maxReceiptEntriesPageSize)
+	if c.QueryParam(api.QueryParameterLimit) != "" {
+		if limit, err = httpserver.ParseUint32QueryParam(c, api.QueryParameterLimit, maxReceiptEntriesPageSize); err != nil {
+			return nil, err
+		}
+	}
+
+	fields, err := receiptEntryFields(c)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := deps.ReceiptProofStore.Proof(msIndex)
+	if err != nil {
+		if errors.Is(err, coordinator.ErrReceiptProofNotFound) {
+			return nil, errors.WithMessagef(echo.ErrNotFound, "%s", err)
+		}
+
+		return nil, err
+	}
+
+	receipt, err := proof.Receipt()
+	if err != nil {
+		return nil, err
+	}
+
+	// the totals always cover the whole receipt
+	response := &api.ReceiptEntriesResponse{
+		MilestoneIndex: proof.MilestoneIndex,
+		MigratedAt:     receipt.MigratedAt,
+		Final:          receipt.Final,
+		EntriesCount:   len(receipt.Funds),
+		Value:          receipt.Sum(),
+		Offset:         offset,
+		Entries:        make([]*api.ReceiptEntry, 0),
+	}
+
+	bech32HRP := deps.NodeBridge.ProtocolParameters().Bech32HRP
+	for i := offset; i < len(receipt.Funds) && len(response.Entries) < int(limit); i++ {
+		funds := receipt.Funds[i]
+
+		entry := &api.ReceiptEntry{Index: i}
+		if fields[api.ReceiptEntryFieldTailTransactionHash] {
+			entry.TailTransactionHash = iotago.EncodeHex(funds.TailTransactionHash[:])
+		}
+		if fields[api.ReceiptEntryFieldAddress] {
+			entry.Address = funds.Address.Bech32(bech32HRP)
+		}
+		if fields[api.ReceiptEntryFieldDeposit] {
+			entry.Deposit = funds.Deposit
+		}
+		response.Entries = append(response.Entries, entry)
+	}
+
+	return response, nil
+}
+
+func migrationSummary() (*api.MigrationSummaryResponse, error) {
+
+	summary, err := deps.Coordinator.MigrationSummary()
+	if err != nil {
+		if errors.Is(err, coordinator.ErrMigrationSummaryNotFound) {
+			return nil, errors.WithMessagef(echo.ErrNotFound, "%s", err)
+		}
+
+		return nil, err
+	}
+
+	return migrationSummaryResponse(summary), nil
+}
+
+// migrationSummaryResponse converts the signed summary of the completed migration.
+func migrationSummaryResponse(summary *coordinator.MigrationSummary) *api.MigrationSummaryResponse {
+	signatures := make([]*api.MigrationSummarySignature, len(summary.Signatures))
+	for i, signature := range summary.Signatures {
+		signatures[i] = &api.MigrationSummarySignature{
+			PublicKey: iotago.EncodeHex(signature.PublicKey[:]),
+			Signature: iotago.EncodeHex(signature.Signature[:]),
+		}
+	}
+
+	return &api.MigrationSummaryResponse{
+		FirstMigratedAtIndex: summary.FirstMigratedAtIndex,
+		LastMigratedAtIndex:  summary.LastMigratedAtIndex,
+		MigratedEntriesCount: summary.MigratedEntriesCount,
+		MigratedValue:        summary.MigratedValue,
+		ReceiptsCount:        summary.ReceiptsCount,
+		MilestoneIndex:       summary.MilestoneIndex,
+		MilestoneID:          summary.MilestoneID.ToHex(),
+		Signatures:           signatures,
+	}
+}
+
+func migrationAttestation() (*api.MigrationAttestationResponse, error) {
+
+	attestation, err := deps.Coordinator.MigrationAttestation()
+	if err != nil {
+		switch {
+		case errors.Is(err, coordinator.ErrMigrationSummaryNotFound):
+			return nil, errors.WithMessagef(echo.ErrNotFound, "%s", err)
+		case errors.Is(err, coordinator.ErrMigrationAttestationInconsistent):
+			return nil, errors.WithMessagef(errConflict, "%s", err)
+		default:
+			return nil, err
+		}
+	}
+
+	bech32HRP := deps.NodeBridge.ProtocolParameters().Bech32HRP
+
+	resp := &api.MigrationAttestationResponse{
+		Summary:    migrationSummaryResponse(attestation.Summary),
+		Complete:   attestation.Complete(),
+		Exclusions: make([]*api.MigrationExclusion, len(attestation.Exclusions)),
+	}
+	if attestation.IncludedHashesDigest != nil {
+		resp.IncludedHashesDigest = attestation.IncludedHashesDigest.String()
+	}
+	for i, exclusion := range attestation.Exclusions {
+		resp.Exclusions[i] = &api.MigrationExclusion{
+			MigratedAt:          exclusion.MigratedAt,
+			TailTransactionHash: iotago.EncodeHex(exclusion.Entry.TailTransactionHash[:]),
+			Address:             exclusion.Entry.Address.Bech32(bech32HRP),
+			Deposit:             exclusion.Entry.Deposit,
+			Reason:              exclusion.Reason,
+		}
+	}
+
+	return resp, nil
+}
+
+func signedMigrationAttestation() (*identity.SignedDocument, error) {
+
+	resp, err := migrationAttestation()
+	if err != nil {
+		return nil, err
+	}
+
+	return deps.Identity.SignJSON(identity.KindMigrationAttestation, resp)
+}
@@ -0,0 +1,233 @@
+package restapi
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/dashboard"
+)
+
+func setupRoutes(e *echo.Echo) {
+
+	// the routes that change the state of the coordinator or export it require the token, if the authentication is enabled
+	var protected []echo.MiddlewareFunc
+	if tokenAuth != nil {
+		protected = append(protected, tokenAuth.Middleware())
+	}
+
+	e.GET(api.RouteStatus, func(c echo.Context) error {
+		resp, err := status()
+		if err != nil {
+			return err
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	// the signed status route is only available if the coordinator has an identity key
+	if deps.Identity != nil {
+		e.GET(api.RouteStatusSigned, func(c echo.Context) error {
+			resp, err := signedStatus()
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusOK, resp)
+		})
+	}
+
+	// the receipt routes are only available if the migration is enabled and the proofs are stored
+	if deps.ReceiptProofStore != nil {
+		e.GET(api.RouteReceiptProof, func(c echo.Context) error {
+			resp, err := receiptProof(c)
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusOK, resp)
+		})
+
+		e.GET(api.RouteReceiptEntries, func(c echo.Context) error {
+			resp, err := receiptEntries(c)
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusOK, resp)
+		})
+	}
+
+	e.GET(api.RoutePinnedParents, func(c echo.Context) error {
+		return httpserver.JSONResponse(c, http.StatusOK, pinnedParents())
+	})
+
+	e.POST(api.RoutePinnedParents, func(c echo.Context) error {
+		resp, err := pinParents(c)
+		if err != nil {
+			return err
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, resp)
+	}, protected...)
+
+	e.DELETE(api.RoutePinnedParent, func(c echo.Context) error {
+		if err := unpinParent(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}, protected...)
+
+	// the migration summary route is only available if the migration is enabled
+	if deps.MigratorService != nil {
+		e.GET(api.RouteMigrationSummary, func(c echo.Context) error {
+			resp, err := migrationSummary()
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusOK, resp)
+		})
+
+		e.GET(api.RouteMigrationAttestation, func(c echo.Context) error {
+			resp, err := migrationAttestation()
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusOK, resp)
+		})
+
+		// the signed migration attestation route is only available if the coordinator has an identity key
+		if deps.Identity != nil {
+			e.GET(api.RouteMigrationAttestationSigned, func(c echo.Context) error {
+				resp, err := signedMigrationAttestation()
+				if err != nil {
+					return err
+				}
+
+				return httpserver.JSONResponse(c, http.StatusOK, resp)
+			})
+		}
+
+		// the export contains the whole migrator state, so it requires the token like the import
+		e.GET(api.RouteMigratorStateExport, func(c echo.Context) error {
+			resp, err := exportMigratorState()
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusOK, resp)
+		}, protected...)
+
+		e.GET(api.RouteMigratorIndexJump, func(c echo.Context) error {
+			resp, err := pendingMigratorIndexJump()
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusOK, resp)
+		})
+
+		e.POST(api.RouteMigratorIndexJumpConfirm, func(c echo.Context) error {
+			if err := confirmMigratorIndexJump(c); err != nil {
+				return err
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		}, protected...)
+
+		e.GET(api.RouteMigratorHeldMigrations, func(c echo.Context) error {
+			return httpserver.JSONResponse(c, http.StatusOK, heldMigrations())
+		})
+
+		e.POST(api.RouteMigratorHeldMigrationRelease, func(c echo.Context) error {
+			if err := releaseHeldMigration(c); err != nil {
+				return err
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		}, protected...)
+	}
+
+	// the faucet route is only available if the migrator serves requested migrations instead of querying the legacy node
+	if deps.Faucet != nil {
+		e.POST(api.RouteMigratorFaucet, func(c echo.Context) error {
+			resp, err := requestFaucetMigration(c)
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusAccepted, resp)
+		}, protected...)
+	}
+
+	// the import is refused while the migrator is running, so that the state can be imported on a new host before the migrator is enabled
+	e.POST(api.RouteMigratorStateImport, func(c echo.Context) error {
+		resp, err := importMigratorState(c)
+		if err != nil {
+			return err
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, resp)
+	}, protected...)
+
+	if eventJournal != nil {
+		e.GET(api.RouteEventJournal, func(c echo.Context) error {
+			resp, err := eventJournalEvents(c)
+			if err != nil {
+				return err
+			}
+
+			return httpserver.JSONResponse(c, http.StatusOK, resp)
+		})
+	}
+
+	if ParamsRestAPI.DashboardEnabled {
+		e.GET(api.RouteEvents, eventStreamer.Handler)
+		e.GET(api.RouteDashboard, dashboard.Handler)
+	}
+
+	// the signer committee routes are only available if the committee signing provider is used
+	if deps.SignerCommittee == nil {
+		return
+	}
+
+	e.GET(api.RouteSignerCommittee, func(c echo.Context) error {
+		resp, err := signerCommittee()
+		if err != nil {
+			return err
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, resp)
+	})
+
+	e.POST(api.RouteSignerCommitteeChanges, func(c echo.Context) error {
+		resp, err := prepareSignerCommitteeChange(c)
+		if err != nil {
+			return err
+		}
+
+		return httpserver.JSONResponse(c, http.StatusCreated, resp)
+	}, protected...)
+
+	e.POST(api.RouteSignerCommitteeChangeCommit, func(c echo.Context) error {
+		resp, err := commitSignerCommitteeChange(c)
+		if err != nil {
+			return err
+		}
+
+		return httpserver.JSONResponse(c, http.StatusOK, resp)
+	}, protected...)
+
+	e.DELETE(api.RouteSignerCommitteeChange, func(c echo.Context) error {
+		if err := abortSignerCommitteeChange(c); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}, protected...)
+}
@@ -0,0 +1,118 @@
+package restapi
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/inx-app/pkg/httpserver"
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+)
+
+func signerCommitteeMemberToAPI(member *coordinator.SignerCommitteeMember) *api.SignerCommitteeMember {
+	return &api.SignerCommitteeMember{
+		RemoteAddress: member.RemoteAddress,
+		PublicKey:     member.PublicKey,
+		StartIndex:    member.StartIndex,
+		EndIndex:      member.EndIndex,
+	}
+}
+
+func signerCommitteeChangeToAPI(change *coordinator.SignerCommitteeChange) *api.SignerCommitteeChange {
+	apiChange := &api.SignerCommitteeChange{
+		ID:              change.ID,
+		ActivationIndex: change.ActivationIndex,
+		Add:             make([]*api.SignerCommitteeMember, 0, len(change.Add)),
+		Remove:          change.Remove,
+	}
+	for _, member := range change.Add {
+		apiChange.Add = append(apiChange.Add, signerCommitteeMemberToAPI(member))
+	}
+
+	return apiChange
+}
+
+// signerCommitteeError maps the errors of the signer committee to REST API errors.
+func signerCommitteeError(err error) error {
+	switch {
+	case errors.Is(err, coordinator.ErrSignerCommitteeChangeNotFound):
+		return errors.WithMessagef(echo.ErrNotFound, "%s", err)
+	case errors.Is(err, coordinator.ErrSignerCommitteeChangeInvalid):
+		return errors.WithMessagef(httpserver.ErrInvalidParameter, "%s", err)
+	default:
+		return err
+	}
+}
+
+func signerCommittee() (*api.SignerCommitteeResponse, error) {
+
+	resp := &api.SignerCommitteeResponse{
+		Members:         []*api.SignerCommitteeMember{},
+		PreparedChanges: []*api.SignerCommitteeChange{},
+	}
+
+	for _, member := range deps.SignerCommittee.Members() {
+		resp.Members = append(resp.Members, signerCommitteeMemberToAPI(member))
+	}
+
+	for _, change := range deps.SignerCommittee.PreparedChanges() {
+		resp.PreparedChanges = append(resp.PreparedChanges, signerCommitteeChangeToAPI(change))
+	}
+
+	return resp, nil
+}
+
+func prepareSignerCommitteeChange(c echo.Context) (*api.SignerCommitteeChange, error) {
+
+	request := &api.SignerCommitteeChange{}
+	if err := c.Bind(request); err != nil {
+		return nil, errors.WithMessagef(httpserver.ErrInvalidParameter, "invalid request, error: %s", err)
+	}
+
+	add := make([]*coordinator.SignerCommitteeMember, 0, len(request.Add))
+	for _, member := range request.Add {
+		if member == nil {
+			return nil, errors.WithMessage(httpserver.ErrInvalidParameter, "invalid request, error: empty member")
+		}
+		add = append(add, &coordinator.SignerCommitteeMember{
+			RemoteAddress: member.RemoteAddress,
+			PublicKey:     member.PublicKey,
+		})
+	}
+
+	change, err := deps.SignerCommittee.PrepareChange(request.ActivationIndex, add, request.Remove, deps.Coordinator.State().LatestMilestoneIndex)
+	if err != nil {
+		return nil, signerCommitteeError(err)
+	}
+
+	Plugin.LogInfof("prepared signer committee change %s, activation index: %d", change.ID, change.ActivationIndex)
+
+	return signerCommitteeChangeToAPI(change), nil
+}
+
+func commitSignerCommitteeChange(c echo.Context) (*api.SignerCommitteeChange, error) {
+
+	changeID := c.Param(api.ParameterChangeID)
+
+	change, err := deps.SignerCommittee.CommitChange(changeID, deps.Coordinator.State().LatestMilestoneIndex)
+	if err != nil {
+		return nil, signerCommitteeError(err)
+	}
+
+	Plugin.LogInfof("committed signer committee change %s, activation index: %d", change.ID, change.ActivationIndex)
+
+	return signerCommitteeChangeToAPI(change), nil
+}
+
+func abortSignerCommitteeChange(c echo.Context) error {
+
+	changeID := c.Param(api.ParameterChangeID)
+
+	if err := deps.SignerCommittee.AbortChange(changeID); err != nil {
+		return signerCommitteeError(err)
+	}
+
+	Plugin.LogInfof("aborted signer committee change %s", changeID)
+
+	return nil
+}
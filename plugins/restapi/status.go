@@ -0,0 +1,167 @@
+package restapi
+
+import (
+	"github.com/iotaledger/inx-coordinator/pkg/api"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/identity"
+)
+
+func status() (*api.StatusResponse, error) {
+
+	cooState := deps.Coordinator.State()
+
+	resp := &api.StatusResponse{
+		Coordinator: &api.CoordinatorStatus{
+			LatestMilestoneIndex:     cooState.LatestMilestoneIndex,
+			LatestMilestoneID:        cooState.LatestMilestoneID.ToHex(),
+			LatestMilestoneBlockID:   cooState.LatestMilestoneBlockID.ToHex(),
+			LatestMilestoneTimestamp: cooState.LatestMilestoneTime.Unix(),
+			IntervalMilliseconds:     deps.Coordinator.Interval().Milliseconds(),
+			PendingReceipts:          deps.Coordinator.PendingReceipts(),
+			NetworkResetIndex:        cooState.NetworkResetIndex,
+		},
+		Environment: deps.EnvironmentReport.Snapshot(),
+	}
+	if pause := deps.Coordinator.ReceiptPause(); pause != nil {
+		resp.Coordinator.ReceiptPause = receiptPauseEvent(pause)
+	}
+
+	signerHealth := deps.Coordinator.SignerHealth()
+	resp.Signer = &api.SignerStatus{
+		PublicKeysCount:     deps.Coordinator.PublicKeysCount(),
+		ConsecutiveFailures: signerHealth.ConsecutiveFailures,
+	}
+	if !signerHealth.LastSuccessTime.IsZero() {
+		resp.Signer.LastSuccessTimestamp = signerHealth.LastSuccessTime.Unix()
+	}
+	if !signerHealth.LastFailureTime.IsZero() {
+		resp.Signer.LastFailureTimestamp = signerHealth.LastFailureTime.Unix()
+	}
+	if signerHealth.LastError != nil {
+		resp.Signer.LastError = signerHealth.LastError.Error()
+	}
+	if !signerHealth.LastSelfTestTime.IsZero() {
+		resp.Signer.LastSelfTestTimestamp = signerHealth.LastSelfTestTime.Unix()
+	}
+	if signerHealth.LastSelfTestError != nil {
+		resp.Signer.LastSelfTestError = signerHealth.LastSelfTestError.Error()
+	}
+	resp.Signer.Connections = remoteSignerConnections(deps.RemoteSigners.States())
+
+	if deps.MigratorService != nil {
+		migratorState := deps.MigratorService.State()
+		resp.Migrator = &api.MigratorStatus{
+			LatestMigratedAtIndex: migratorState.LatestMigratedAtIndex,
+			LatestIncludedIndex:   migratorState.LatestIncludedIndex,
+			SendingReceipt:        migratorState.SendingReceipt,
+			MigratedEntriesCount:  migratorState.MigratedEntriesCount,
+			MigratedValue:         migratorState.MigratedValue,
+			PendingIndexJump:      migratorIndexJump(deps.MigratorService.PendingIndexJump()),
+			HeldMigrationsCount:   len(deps.MigratorService.HeldMigrations()),
+			Halt:                  migratorHalt(deps.MigratorService.Halted()),
+		}
+
+		if window := deps.MigratorService.SendingReceiptWindow(); window.Active {
+			resp.Migrator.SendingReceiptWindow = &api.SendingReceiptWindow{
+				SinceTimestamp:        window.Since.Unix(),
+				DurationSeconds:       window.Duration.Seconds(),
+				StuckThresholdSeconds: window.StuckThreshold.Seconds(),
+				Stuck:                 window.Stuck,
+			}
+		}
+
+		receiptLimits := deps.MigratorService.ReceiptLimits()
+		resp.Migrator.ReceiptLimits = &api.ReceiptLimits{
+			MaxReceiptSize:       receiptLimits.MaxReceiptSize,
+			PoWStepReceiptSize:   receiptLimits.PoWStepReceiptSize,
+			ConfiguredMaxEntries: receiptLimits.ConfiguredMaxEntries,
+			DerivedMaxEntries:    receiptLimits.DerivedMaxEntries,
+			MaxEntries:           receiptLimits.MaxEntries,
+		}
+
+		if deps.CircuitBreaker != nil {
+			circuitBreakerStatus := deps.CircuitBreaker.Status()
+			resp.Migrator.CircuitBreaker = &api.CircuitBreakerStatus{
+				State:               circuitBreakerStatus.State,
+				ConsecutiveFailures: circuitBreakerStatus.ConsecutiveFailures,
+				OpenedCount:         circuitBreakerStatus.OpenedCount,
+			}
+			if !circuitBreakerStatus.OpenedTime.IsZero() {
+				resp.Migrator.CircuitBreaker.LastOpenedTimestamp = circuitBreakerStatus.OpenedTime.Unix()
+			}
+		}
+	}
+
+	for _, stat := range deps.Coordinator.QuorumStats() {
+		clientStatus := &api.QuorumClientStatus{
+			Group:               stat.Group,
+			Alias:               stat.Alias,
+			BaseURL:             stat.BaseURL,
+			ResponseTimeSeconds: stat.ResponseTimeSeconds,
+		}
+		if stat.Error != nil {
+			clientStatus.Error = stat.Error.Error()
+		}
+		if stat.Score != nil {
+			clientStatus.Score = &api.QuorumClientScore{
+				Calls:                      stat.Score.Calls,
+				Agreements:                 stat.Score.Agreements,
+				Disagreements:              stat.Score.Disagreements,
+				Failures:                   stat.Score.Failures,
+				AverageResponseTimeSeconds: stat.Score.AverageResponseTime.Seconds(),
+				Demoted:                    stat.Score.Demoted,
+				DemotionReason:             stat.Score.DemotionReason,
+			}
+		}
+		resp.Quorum = append(resp.Quorum, clientStatus)
+	}
+
+	resp.SoftErrors = make([]*api.SoftErrorStatus, 0)
+	for _, entry := range deps.SoftErrorHistory.Entries() {
+		resp.SoftErrors = append(resp.SoftErrors, &api.SoftErrorStatus{
+			Timestamp: entry.Timestamp.Unix(),
+			Class:     entry.Class,
+			Message:   entry.Message,
+		})
+	}
+
+	return resp, nil
+}
+
+// remoteSignerConnections converts the states of the persistent connections to the remote signers, it returns nil if there are none.
+func remoteSignerConnections(states []*coordinator.RemoteSignerConnectionState) []*api.RemoteSignerConnectionStatus {
+	if len(states) == 0 {
+		return nil
+	}
+
+	connections := make([]*api.RemoteSignerConnectionStatus, 0, len(states))
+	for _, state := range states {
+		connection := &api.RemoteSignerConnectionStatus{
+			Endpoint:   state.Endpoint,
+			State:      state.State,
+			Reconnects: state.Reconnects,
+		}
+		if !state.ReadySince.IsZero() {
+			connection.ReadySinceTimestamp = state.ReadySince.Unix()
+		}
+		if !state.LastProbeTime.IsZero() {
+			connection.LastProbeTimestamp = state.LastProbeTime.Unix()
+		}
+		if state.LastProbeError != nil {
+			connection.LastProbeError = state.LastProbeError.Error()
+		}
+		connections = append(connections, connection)
+	}
+
+	return connections
+}
+
+func signedStatus() (*identity.SignedDocument, error) {
+
+	resp, err := status()
+	if err != nil {
+		return nil, err
+	}
+
+	return deps.Identity.SignJSON(identity.KindStatusReport, resp)
+}
@@ -0,0 +1,120 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/dig"
+
+	"github.com/iotaledger/hive.go/core/app"
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/scheduler"
+	"github.com/iotaledger/inx-coordinator/pkg/telemetry"
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+const (
+	// taskReport reports the statistics of the current period.
+	taskReport = "telemetry"
+)
+
+func init() {
+	Plugin = &app.Plugin{
+		Component: &app.Component{
+			Name:           "Telemetry",
+			DepsFunc:       func(cDeps dependencies) { deps = cDeps },
+			Params:         params,
+			InitConfigPars: initConfigPars,
+			Configure:      configure,
+			Run:            run,
+		},
+		IsEnabled: func() bool {
+			return ParamsTelemetry.Enabled
+		},
+	}
+}
+
+var (
+	Plugin *app.Plugin
+	deps   dependencies
+
+	collector *telemetry.Collector
+	reporter  *telemetry.Reporter
+
+	// closures.
+	onIssuedMilestone  *events.Closure
+	onMilestoneSkipped *events.Closure
+	onReceiptIssued    *events.Closure
+)
+
+type dependencies struct {
+	dig.In
+	Coordinator     *coordinator.Coordinator
+	MigratorService *migrator.Service `optional:"true"`
+	Scheduler       *scheduler.Scheduler
+}
+
+func initConfigPars(_ *dig.Container) error {
+	if !ParamsTelemetry.Enabled {
+		return nil
+	}
+
+	return validation.Validate("telemetry", ParamsTelemetry)
+}
+
+func configure() error {
+	collector = telemetry.NewCollector(Plugin.App().Info().Version, deps.MigratorService != nil)
+	reporter = telemetry.NewReporter(ParamsTelemetry.Endpoint, ParamsTelemetry.Timeout, collector)
+
+	onIssuedMilestone = events.NewClosure(func(_ iotago.MilestoneIndex, _ iotago.MilestoneID, _ iotago.BlockID) {
+		collector.MilestoneIssued()
+	})
+	onMilestoneSkipped = events.NewClosure(func(_ iotago.MilestoneIndex, _ error) {
+		collector.MilestoneSkipped()
+	})
+	onReceiptIssued = events.NewClosure(func(_ iotago.MilestoneIndex, _ *iotago.ReceiptMilestoneOpt, _ int) {
+		collector.ReceiptIssued()
+	})
+
+	// the reports are sent in the background, so that they never delay the issuance of a milestone
+	return deps.Scheduler.Register(taskReport, scheduler.PriorityLow, ParamsTelemetry.Interval, func(ctx context.Context) error {
+		report, err := reporter.Send(ctx)
+		if err != nil {
+			Plugin.LogWarnf("failed to report anonymous statistics: %s", err)
+
+			return err
+		}
+		Plugin.LogDebugf("reported anonymous statistics of the last %v: %d milestones, %d receipts", time.Duration(report.PeriodSeconds)*time.Second, report.MilestonesIssued, report.ReceiptsIssued)
+
+		return nil
+	})
+}
+
+func run() error {
+	if err := Plugin.App().Daemon().BackgroundWorker("Telemetry", func(ctx context.Context) {
+		Plugin.LogInfo("Starting Telemetry ...")
+		Plugin.LogInfof("Reporting anonymous statistics to %s every %v", ParamsTelemetry.Endpoint, ParamsTelemetry.Interval)
+
+		deps.Coordinator.Events.IssuedMilestone.Hook(onIssuedMilestone)
+		deps.Coordinator.Events.MilestoneSkipped.Hook(onMilestoneSkipped)
+		deps.Coordinator.Events.ReceiptIssued.Hook(onReceiptIssued)
+
+		Plugin.LogInfo("Starting Telemetry ... done")
+		<-ctx.Done()
+		Plugin.LogInfo("Stopping Telemetry ...")
+
+		deps.Coordinator.Events.IssuedMilestone.Detach(onIssuedMilestone)
+		deps.Coordinator.Events.MilestoneSkipped.Detach(onMilestoneSkipped)
+		deps.Coordinator.Events.ReceiptIssued.Detach(onReceiptIssued)
+
+		Plugin.LogInfo("Stopping Telemetry ... done")
+	}, daemon.PriorityStopTelemetry); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	return nil
+}
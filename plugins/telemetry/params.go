@@ -0,0 +1,28 @@
+package telemetry
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/core/app"
+)
+
+// ParametersTelemetry contains the definition of the parameters used by the telemetry plugin.
+type ParametersTelemetry struct {
+	// Enabled defines whether anonymous statistics are reported.
+	Enabled bool `default:"false" usage:"whether anonymous aggregate statistics (milestone rate, receipt count, version) are reported"`
+	// Endpoint defines the URL the statistics are reported to.
+	Endpoint string `default:"" usage:"the URL the statistics are reported to" validate:"required,url"`
+	// Interval defines the interval in which the statistics are reported.
+	Interval time.Duration `default:"1h" usage:"the interval in which the statistics are reported" validate:"min=1m"`
+	// Timeout defines the timeout of a report.
+	Timeout time.Duration `default:"10s" usage:"the timeout of a report" validate:"min=1s"`
+}
+
+var ParamsTelemetry = &ParametersTelemetry{}
+
+var params = &app.ComponentParams{
+	Params: map[string]any{
+		"telemetry": ParamsTelemetry,
+	},
+	Masked: nil,
+}
@@ -0,0 +1,190 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/dig"
+
+	"github.com/iotaledger/hive.go/core/app"
+	"github.com/iotaledger/hive.go/core/events"
+	"github.com/iotaledger/inx-app/pkg/nodebridge"
+	"github.com/iotaledger/inx-coordinator/pkg/coordinator"
+	"github.com/iotaledger/inx-coordinator/pkg/daemon"
+	"github.com/iotaledger/inx-coordinator/pkg/eventqueue"
+	"github.com/iotaledger/inx-coordinator/pkg/migrator"
+	"github.com/iotaledger/inx-coordinator/pkg/receiptjson"
+	"github.com/iotaledger/inx-coordinator/pkg/validation"
+	"github.com/iotaledger/inx-coordinator/pkg/webhook"
+	iotago "github.com/iotaledger/iota.go/v3"
+)
+
+func init() {
+	Plugin = &app.Plugin{
+		Component: &app.Component{
+			Name:           "Webhooks",
+			DepsFunc:       func(cDeps dependencies) { deps = cDeps },
+			Params:         params,
+			InitConfigPars: initConfigPars,
+			Configure:      configure,
+			Run:            run,
+		},
+		IsEnabled: func() bool {
+			return ParamsWebhooks.Enabled
+		},
+	}
+}
+
+var (
+	Plugin *app.Plugin
+	deps   dependencies
+
+	notifier        *webhook.Notifier
+	receiptNotifier *webhook.Notifier
+	deliveryQueue   *eventqueue.Queue[*delivery]
+
+	// closures.
+	onReceiptIssued *events.Closure
+)
+
+// delivery is a payload that is queued until it is delivered to the webhooks.
+type delivery struct {
+	notifier *webhook.Notifier
+	payload  any
+	// describes the payload in the logs.
+	description string
+	// the key-value pairs that identify the payload in the logs.
+	logFields []any
+}
+
+type dependencies struct {
+	dig.In
+	Coordinator       *coordinator.Coordinator
+	MigratorService   *migrator.Service `optional:"true"`
+	NodeBridge        *nodebridge.NodeBridge
+	EventQueueMetrics *eventqueue.Metrics
+}
+
+func initConfigPars(_ *dig.Container) error {
+	if !ParamsWebhooks.Enabled {
+		return nil
+	}
+
+	if err := validation.Validate("webhooks", ParamsWebhooks); err != nil {
+		return err
+	}
+
+	if len(ParamsWebhooks.Endpoints) == 0 && len(ParamsWebhooks.ReceiptEndpoints) == 0 {
+		return fmt.Errorf("%w: \"webhooks.endpoints\" and \"webhooks.receiptEndpoints\" must not both be empty", validation.ErrInvalidParameter)
+	}
+
+	return nil
+}
+
+func configure() error {
+	if deps.MigratorService == nil {
+		Plugin.LogWarn("The migrator is disabled, no receipts are issued that the webhooks could be notified about")
+	}
+
+	retries := webhook.WithRetries(ParamsWebhooks.RetryAmount, ParamsWebhooks.RetryInterval)
+	notifier = webhook.NewNotifier(ParamsWebhooks.Endpoints, ParamsWebhooks.Timeout, retries)
+	receiptNotifier = webhook.NewNotifier(ParamsWebhooks.ReceiptEndpoints, ParamsWebhooks.Timeout, retries)
+
+	// the payloads are delivered by a separate worker, so that slow endpoints never delay the coordinator
+	deliveryQueue = eventqueue.New[*delivery](
+		ParamsWebhooks.QueueSize,
+		eventqueue.PolicyDropNewest,
+		deps.EventQueueMetrics,
+		eventqueue.ListenerWebhook,
+	)
+
+	// the receipt is issued once the milestone that contains it was confirmed
+	onReceiptIssued = events.NewClosure(func(index iotago.MilestoneIndex, receipt *iotago.ReceiptMilestoneOpt, _ int) {
+		bech32HRP := deps.NodeBridge.ProtocolParameters().Bech32HRP
+
+		if len(ParamsWebhooks.ReceiptEndpoints) > 0 {
+			enqueue(&delivery{
+				notifier:    receiptNotifier,
+				payload:     receiptjson.New(index, receipt, bech32HRP),
+				description: "a receipt",
+				logFields:   []any{"milestoneIndex", index, "migratedAt", receipt.MigratedAt, "entriesCount", len(receipt.Funds)},
+			})
+		}
+
+		if len(ParamsWebhooks.Endpoints) == 0 {
+			return
+		}
+
+		for _, notification := range webhook.Notifications(index, receipt, ParamsWebhooks.MinDeposit, bech32HRP) {
+			enqueue(&delivery{
+				notifier:    notifier,
+				payload:     notification,
+				description: "a migration",
+				logFields: []any{
+					"milestoneIndex", notification.MilestoneIndex,
+					"migratedAt", notification.MigratedAt,
+					"entryIndex", notification.EntryIndex,
+					"tailTransactionHash", notification.TailTransactionHash,
+					"address", notification.Address,
+					"deposit", notification.Deposit,
+				},
+			})
+		}
+	})
+
+	return nil
+}
+
+func run() error {
+
+	if err := Plugin.App().Daemon().BackgroundWorker("Webhooks", func(ctx context.Context) {
+		if len(ParamsWebhooks.Endpoints) > 0 {
+			Plugin.LogInfof("Notifying %d webhooks about migrations with a deposit of at least %d", len(ParamsWebhooks.Endpoints), ParamsWebhooks.MinDeposit)
+		}
+		if len(ParamsWebhooks.ReceiptEndpoints) > 0 {
+			Plugin.LogInfof("Posting the receipts to %d webhooks", len(ParamsWebhooks.ReceiptEndpoints))
+		}
+
+		deps.Coordinator.Events.ReceiptIssued.Hook(onReceiptIssued)
+		deliverPayloads(ctx)
+		deps.Coordinator.Events.ReceiptIssued.Detach(onReceiptIssued)
+
+		// the payloads that are still queued can't be delivered anymore, they are logged instead
+		for _, queued := range deliveryQueue.Flush() {
+			logUndelivered(queued, "the coordinator was shut down")
+		}
+		deliveryQueue.Close()
+
+		Plugin.LogInfo("Stopping Webhooks ... done")
+	}, daemon.PriorityStopWebhooks); err != nil {
+		Plugin.LogPanicf("failed to start worker: %s", err)
+	}
+
+	return nil
+}
+
+// enqueue queues the payload for the delivery to the webhooks, it never blocks.
+func enqueue(queued *delivery) {
+	if !deliveryQueue.Push(queued) {
+		logUndelivered(queued, "the delivery queue is full")
+	}
+}
+
+// deliverPayloads delivers the queued payloads to the webhooks until the context is canceled.
+func deliverPayloads(ctx context.Context) {
+	for {
+		queued, err := deliveryQueue.Pop(ctx)
+		if err != nil {
+			return
+		}
+
+		if err := queued.notifier.Notify(ctx, queued.payload); err != nil {
+			logUndelivered(queued, err.Error())
+		}
+	}
+}
+
+// logUndelivered logs a payload that was not delivered to all webhooks, so that it can be followed up manually.
+func logUndelivered(queued *delivery, reason string) {
+	Plugin.Logger().Warnw(fmt.Sprintf("failed to notify the webhooks about %s", queued.description), append([]any{"reason", reason}, queued.logFields...)...)
+}
@@ -0,0 +1,36 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/core/app"
+)
+
+// ParametersWebhooks contains the definition of the parameters used by the webhooks plugin.
+type ParametersWebhooks struct {
+	// Enabled defines whether the webhooks are notified about migrations and receipts.
+	Enabled bool `default:"false" usage:"whether the webhooks are notified about the migrated funds entries with at least the minimum deposit once the milestone containing their receipt is confirmed"`
+	// Endpoints defines the URLs the notifications are posted to.
+	Endpoints []string `default:"" usage:"the URLs the notifications about the migrated funds entries are posted to" validate:"url"`
+	// ReceiptEndpoints defines the URLs the receipts are posted to in the canonical JSON schema.
+	ReceiptEndpoints []string `default:"" usage:"the URLs the whole receipts are posted to once the milestone containing them is confirmed, in the canonical JSON schema with hex encoded hashes, bech32 addresses and decimal string values" validate:"url"`
+	// MinDeposit defines the minimum deposit of a migrated funds entry to notify the webhooks about.
+	MinDeposit uint64 `default:"1000000000000" usage:"the minimum deposit of a migrated funds entry to notify the webhooks about"`
+	// Timeout defines the timeout of a notification.
+	Timeout time.Duration `default:"10s" usage:"the timeout of a notification" validate:"min=1s"`
+	// RetryAmount defines how often a failed notification is retried.
+	RetryAmount int `default:"3" usage:"how often a failed notification is retried per endpoint" validate:"min=0"`
+	// RetryInterval defines the interval between the retries of a failed notification.
+	RetryInterval time.Duration `default:"5s" usage:"the interval between the retries of a failed notification" validate:"min=100ms"`
+	// QueueSize defines the amount of notifications that are queued until they are delivered.
+	QueueSize int `default:"1000" usage:"the amount of notifications that are queued until they are delivered, so that slow endpoints never delay the coordinator" validate:"min=1"`
+}
+
+var ParamsWebhooks = &ParametersWebhooks{}
+
+var params = &app.ComponentParams{
+	Params: map[string]any{
+		"webhooks": ParamsWebhooks,
+	},
+	Masked: []string{"webhooks.endpoints", "webhooks.receiptEndpoints"},
+}